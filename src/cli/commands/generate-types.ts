@@ -0,0 +1,54 @@
+import path from 'path';
+import chalk from 'chalk';
+import {
+  defaultModelsSourcePath,
+  generatePaginationTypes,
+} from '../../lib/ts-types-generator.js';
+import { pathExists, readFile, writeFile } from '../../utils/file-operations.js';
+
+interface GenerateTypesOptions {
+  out?: string;
+  source?: string;
+  model?: string;
+  modelsFrom?: string;
+}
+
+/**
+ * Emit TypeScript definitions for the pagination envelope
+ * Reads the Go response types (the bundled api-pagination models.go unless --source points at
+ * an installed copy) and writes a .d.ts, so frontend types never drift from the API.
+ */
+export async function generateTypesCommand(options: GenerateTypesOptions) {
+  const sourcePath = path.resolve(options.source ?? defaultModelsSourcePath());
+  const outPath = path.resolve(options.out ?? 'pagination.d.ts');
+  const models = (options.model ?? '')
+    .split(',')
+    .map((model) => model.trim())
+    .filter(Boolean);
+
+  try {
+    if (!(await pathExists(sourcePath))) {
+      throw new Error(`Go source not found: ${sourcePath}`);
+    }
+
+    const invalid = models.find((model) => !/^[A-Za-z_$][\w$]*$/.test(model));
+    if (invalid) {
+      throw new Error(`Invalid model type name: ${invalid}`);
+    }
+
+    const content = generatePaginationTypes(await readFile(sourcePath), {
+      models,
+      modelsFrom: options.modelsFrom,
+      sourceName: path.basename(sourcePath),
+    });
+    await writeFile(outPath, content);
+
+    console.log(chalk.green(`✅ Wrote ${path.relative(process.cwd(), outPath)}`));
+    if (models.length > 0) {
+      console.log(chalk.gray(`   Page aliases for: ${models.join(', ')}`));
+    }
+  } catch (error) {
+    console.error(chalk.red('\n❌ Failed to generate types:'), (error as Error).message);
+    process.exit(1);
+  }
+}
@@ -3,13 +3,25 @@ import inquirer from 'inquirer';
 import chalk from 'chalk';
 import ora from 'ora';
 import fs from 'fs-extra';
-import { AgentInstaller } from '../../lib/agent-installer.js';
-import { SkillsInstaller } from '../../lib/skills-installer.js';
+import { AgentInstaller, InstallResult as AgentInstallResult } from '../../lib/agent-installer.js';
+import {
+  GeneratedFileAction,
+  SkillInstallResult,
+  SkillsInstaller,
+} from '../../lib/skills-installer.js';
 import { EnhancedTechDetector } from '../../lib/enhanced-tech-detector.js';
 import { ConfigGenerator } from '../../lib/config-generator.js';
 import { StackInstaller } from '../../lib/stack-installer.js';
 import { getTemplatesDirectory, pathExists, readFile } from '../../utils/file-operations.js';
 import type { TemplateFeatures } from '../../lib/stack-template.js';
+import { parseConcurrency } from '../../utils/concurrency.js';
+import {
+  OutputFormat,
+  parseOutputFormat,
+  routeConsoleToStderr,
+  writeJsonError,
+  writeJsonSuccess,
+} from '../../utils/json-output.js';
 
 /**
  * Get project name from package.json or directory name
@@ -31,8 +43,9 @@ async function getProjectName(projectRoot: string): Promise<string> {
 
 /**
  * Update .gitignore to exclude .claude/ directory
+ * Returns whether .gitignore was written
  */
-async function updateGitignore(projectRoot: string): Promise<void> {
+async function updateGitignore(projectRoot: string): Promise<boolean> {
   const gitignorePath = path.join(projectRoot, '.gitignore');
   const claudeEntry = '.claude/';
 
@@ -53,7 +66,7 @@ async function updateGitignore(projectRoot: string): Promise<void> {
 
       if (hasClaudeEntry) {
         // Already present, no need to update
-        return;
+        return false;
       }
     }
 
@@ -65,11 +78,13 @@ async function updateGitignore(projectRoot: string): Promise<void> {
       : `${header}${claudeEntry}\n`;
 
     await fs.writeFile(gitignorePath, newContent, 'utf-8');
+    return true;
   } catch (error) {
     // Silently fail - not critical if .gitignore update fails
     console.warn(
       chalk.yellow(`\nWarning: Could not update .gitignore: ${(error as Error).message}`)
     );
+    return false;
   }
 }
 
@@ -80,17 +95,106 @@ interface InitOptions {
   mcp?: boolean;
   mode?: 'strict' | 'flexible' | 'adaptive';
   template?: string;
+  concurrency?: string;
+  output?: string;
+}
+
+/**
+ * JSON shape of `init --output json`
+ * Paths are relative to the project root and sorted, so the report does not depend on
+ * generation order.
+ */
+export interface InitOutput {
+  template: string | null;
+  files: {
+    written: string[];
+    skipped: string[];
+    merged: string[];
+  };
+  agents: {
+    installed: string[];
+    errors: Array<{ agent: string; error: string }>;
+  };
+  skills: {
+    installed: string[];
+    errors: Array<{ skill: string; error: string }>;
+    templatePacks: Array<{ skill: string; templatePack: string; score: number }>;
+  };
+}
+
+/**
+ * Build the machine-readable init report
+ */
+export function buildInitOutput(run: {
+  template: string | null;
+  templateFiles: string[];
+  configFiles: string[];
+  agents: AgentInstallResult;
+  skills: SkillInstallResult;
+}): InitOutput {
+  const sorted = (paths: string[]) => [...paths].sort((a, b) => a.localeCompare(b));
+  const generated = (action: GeneratedFileAction) =>
+    run.skills.files.filter((file) => file.action === action).map((file) => file.path);
+
+  return {
+    template: run.template,
+    files: {
+      written: sorted([...run.templateFiles, ...generated('written'), ...run.configFiles]),
+      skipped: sorted(generated('skipped')),
+      merged: sorted(generated('merged')),
+    },
+    agents: {
+      installed: run.agents.installed.map((agent) => agent.name),
+      errors: run.agents.errors,
+    },
+    skills: {
+      installed: run.skills.installed.map((skill) => skill.name),
+      errors: run.skills.errors,
+      templatePacks: run.skills.templatePacksUsed ?? [],
+    },
+  };
 }
 
 export async function initCommand(options: InitOptions) {
+  let format: OutputFormat;
+  try {
+    format = parseOutputFormat(options.output);
+  } catch (error) {
+    console.error(chalk.red('Error:'), (error as Error).message);
+    process.exit(1);
+  }
+
+  // JSON mode cannot prompt, and keeps stdout for the envelope alone
+  const json = format === 'json';
+  if (json && !options.yes) {
+    writeJsonError('init', 'INIT_REQUIRES_YES', '--output json runs without prompts; pass --yes');
+    process.exit(1);
+  }
+  const restoreConsole = json ? routeConsoleToStderr() : null;
+
+  try {
+    await runInit(options, json);
+  } finally {
+    restoreConsole?.();
+  }
+}
+
+async function runInit(options: InitOptions, json: boolean) {
   console.log(chalk.cyan.bold('\n🚀 AgentWeaver CLI - Setup Wizard\n'));
 
   const projectRoot = process.cwd();
+  const concurrency = parseConcurrency(options.concurrency);
   const claudeDir = path.join(projectRoot, '.claude');
   const agentsDir = path.join(claudeDir, 'agents');
   const skillsDir = path.join(claudeDir, 'skills');
 
   // Check if .claude directory already exists
+  if (json && (await pathExists(claudeDir))) {
+    writeJsonError('init', 'INIT_TARGET_EXISTS', '.claude directory already exists', {
+      path: claudeDir,
+    });
+    process.exit(1);
+  }
   if (await pathExists(claudeDir)) {
     const { overwrite } = await inquirer.prompt([
       {
@@ -266,6 +370,11 @@ export async function initCommand(options: InitOptions) {
         templateInstallResult.errors.forEach((error: string) => {
           console.log(chalk.red(`  ✗ ${error}`));
         });
+        if (json) {
+          writeJsonError('init', 'INIT_FAILED', 'Template installation failed', {
+            errors: templateInstallResult.errors,
+          });
+        }
         process.exit(1);
       }
     }
@@ -563,7 +672,13 @@ export async function initCommand(options: InitOptions) {
     }
 
     // Step 7: Install skills with template pack resolution
-    let skillResult: any = { installed: [], templatePacksUsed: [] };
+    let skillResult: SkillInstallResult = {
+      installed: [],
+      skipped: [],
+      errors: [],
+      templatePacksUsed: [],
+      files: [],
+    };
     if (
       selectedSkills.length > 0 ||
       (!options.skills && !options.yes) ||
@@ -597,6 +712,10 @@ export async function initCommand(options: InitOptions) {
         overwrite: true,
         techStackContext,
         projectRoot,
+        concurrency,
+        onProgress: ({ skill, phase, done, total }) => {
+          skillSpinner.text = `Installing skills: ${skill} (${phase} ${done}/${total})`;
+        },
       });
 
       if (skillResult.errors.length > 0) {
@@ -621,7 +740,8 @@ export async function initCommand(options: InitOptions) {
       }
     }
 
-    // Step 8: Generate configurations
+    // Step 8: Generate configurations (paths relative to the project root, for the JSON report)
+    const configFiles: string[] = [];
     if (mcpServers.length > 0) {
       const mcpSpinner = ora('Generating MCP configuration...').start();
       const mcpConfig = ConfigGenerator.generateMcpConfig({
@@ -642,6 +762,7 @@ export async function initCommand(options: InitOptions) {
       const envExample = ConfigGenerator.generateEnvExample(mcpConfig);
       const { writeFile: writeFileUtil } = await import('../../utils/file-operations.js');
       await writeFileUtil(path.join(projectRoot, '.env.example'), envExample);
+      configFiles.push('.mcp.json', '.env.example');
     }
 
     const configSpinner = ora('Generating AgentWeaver configuration...').start();
@@ -679,12 +800,34 @@ export async function initCommand(options: InitOptions) {
 
     // Copy WORKFLOWS.md to .claude directory
     await ConfigGenerator.copyWorkflowsFile(claudeDir);
+    configFiles.push(
+      '.claude/agentweaver.config.yml',
+      '.claude/tech-stack.md',
+      '.claude/CLAUDE.md',
+      '.claude/WORKFLOWS.md'
+    );
 
     // Add .claude/ to .gitignore
     const gitignoreSpinner = ora('Updating .gitignore').start();
-    await updateGitignore(projectRoot);
+    if (await updateGitignore(projectRoot)) {
+      configFiles.push('.gitignore');
+    }
     gitignoreSpinner.succeed('Updated .gitignore to exclude .claude/');
 
+    if (json) {
+      writeJsonSuccess(
+        'init',
+        buildInitOutput({
+          template: templateInstallResult?.template.id ?? null,
+          templateFiles: templateInstallResult?.filesCreated ?? [],
+          configFiles,
+          agents: agentResult,
+          skills: skillResult,
+        })
+      );
+      return;
+    }
+
     // Success message
     console.log(chalk.green.bold('\n✅ Installation complete!\n'));
 
@@ -755,7 +898,11 @@ export async function initCommand(options: InitOptions) {
     console.log(chalk.cyan('📚 Documentation: https://github.com/CodeLift-LLC/AgentWeaver-CLI'));
     console.log('');
   } catch (error) {
-    console.error(chalk.red('\n❌ Installation failed:'), (error as Error).message);
+    if (json) {
+      writeJsonError('init', 'INIT_FAILED', (error as Error).message);
+    } else {
+      console.error(chalk.red('\n❌ Installation failed:'), (error as Error).message);
+    }
     process.exit(1);
   }
 }
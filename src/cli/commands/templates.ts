@@ -1,7 +1,98 @@
 import chalk from 'chalk';
 import { StackInstaller } from '../../lib/stack-installer.js';
+import type { StackTemplate } from '../../lib/stack-template.js';
+import {
+  OutputFormat,
+  parseOutputFormat,
+  writeJsonError,
+  writeJsonSuccess,
+} from '../../utils/json-output.js';
+
+interface TemplatesOptions {
+  output?: string;
+}
+
+/**
+ * JSON shape of a single template in `templates --output json`
+ */
+export interface TemplateListEntry {
+  id: string;
+  name: string;
+  description: string;
+  complexity: string;
+  architecture: string;
+  tags: string[];
+  techStack: {
+    framework: string | null;
+    language: string | null;
+    database: string | null;
+    orm: string | null;
+    packageManager: string | null;
+  };
+  features: {
+    default: string[];
+    optional: string[];
+  };
+  fileCount: number;
+  dockerServiceCount: number;
+}
+
+/**
+ * Build the machine-readable template list
+ */
+export function buildTemplatesOutput(templates: StackTemplate[]): {
+  templates: TemplateListEntry[];
+} {
+  return {
+    templates: templates.map((template) => {
+      const { backend, frontend, database, packageManager } = template.techStack;
+      const features = Object.entries(template.features);
+
+      return {
+        id: template.id,
+        name: template.name,
+        description: template.description,
+        complexity: template.complexity,
+        architecture: template.architecture,
+        tags: template.tags ?? [],
+        techStack: {
+          framework: backend?.framework ?? frontend?.framework ?? null,
+          language: backend?.language ?? frontend?.language ?? null,
+          database: database?.primary ?? null,
+          orm: database?.orm ?? null,
+          packageManager: packageManager?.node ?? packageManager?.python ?? null,
+        },
+        features: {
+          default: features.filter(([_, enabled]) => enabled).map(([name]) => name),
+          optional: features.filter(([_, enabled]) => !enabled).map(([name]) => name),
+        },
+        fileCount: template.files.length,
+        dockerServiceCount: template.dockerServices.length,
+      };
+    }),
+  };
+}
+
+export async function templatesCommand(options: TemplatesOptions = {}) {
+  let format: OutputFormat;
+  try {
+    format = parseOutputFormat(options.output);
+  } catch (error) {
+    console.error(chalk.red('Error:'), (error as Error).message);
+    process.exit(1);
+  }
+
+  if (format === 'json') {
+    try {
+      const templates = await new StackInstaller().listTemplates();
+      writeJsonSuccess('templates', buildTemplatesOutput(templates));
+    } catch (error) {
+      writeJsonError('templates', 'TEMPLATES_LIST_FAILED', (error as Error).message);
+      process.exit(1);
+    }
+    return;
+  }
 
-export async function templatesCommand() {
   console.log(chalk.cyan.bold('\n📦 Available Tech Stack Templates\n'));
 
   try {
@@ -1,19 +1,84 @@
 import path from 'path';
 import chalk from 'chalk';
 import ora from 'ora';
-import { TemplatePackValidator } from '../../lib/template-pack-validator.js';
-import { getTemplatesDirectory } from '../../utils/file-operations.js';
+import { TemplatePackValidator, ValidationResult } from '../../lib/template-pack-validator.js';
+import { getTemplatesDirectory, listDirectories } from '../../utils/file-operations.js';
+import {
+  OutputFormat,
+  parseOutputFormat,
+  writeJsonError,
+  writeJsonSuccess,
+} from '../../utils/json-output.js';
 
 interface ValidateOptions {
   skill?: string;
   pack?: string;
   verbose?: boolean;
+  output?: string;
+}
+
+/**
+ * JSON shape of `validate --output json`
+ */
+export interface ValidationOutput {
+  packs: Array<{
+    skill: string | null;
+    name: string;
+    path: string;
+    valid: boolean;
+    errors: Array<{ field: string; message: string; severity: string }>;
+    warnings: Array<{ field: string; message: string }>;
+  }>;
+  summary: {
+    total: number;
+    valid: number;
+    invalid: number;
+  };
+}
+
+/**
+ * Build the machine-readable validation report
+ */
+export function buildValidationOutput(
+  entries: Array<{ skill: string | null; result: ValidationResult }>
+): ValidationOutput {
+  const packs = entries.map(({ skill, result }) => ({
+    skill,
+    name: result.packName,
+    path: result.packPath,
+    valid: result.valid,
+    errors: result.errors.map(({ field, message, severity }) => ({ field, message, severity })),
+    warnings: result.warnings.map(({ field, message }) => ({ field, message })),
+  }));
+  const validCount = packs.filter((pack) => pack.valid).length;
+
+  return {
+    packs,
+    summary: {
+      total: packs.length,
+      valid: validCount,
+      invalid: packs.length - validCount,
+    },
+  };
 }
 
 /**
  * Validate template packs for correctness
  */
 export async function validateCommand(options: ValidateOptions) {
+  let format: OutputFormat;
+  try {
+    format = parseOutputFormat(options.output);
+  } catch (error) {
+    console.error(chalk.red('Error:'), (error as Error).message);
+    process.exit(1);
+  }
+
+  if (format === 'json') {
+    await validateAsJson(options);
+    return;
+  }
+
   console.log(chalk.cyan.bold('\n🔍 AgentWeaver Template Pack Validator\n'));
 
   const templatesDir = getTemplatesDirectory();
@@ -36,6 +101,46 @@ export async function validateCommand(options: ValidateOptions) {
   }
 }
 
+/**
+ * Validate with machine-readable output
+ * The report goes to stdout; failures additionally emit an error envelope on stderr
+ */
+async function validateAsJson(options: ValidateOptions) {
+  const templatesDir = getTemplatesDirectory();
+  const validator = new TemplatePackValidator();
+  const entries: Array<{ skill: string | null; result: ValidationResult }> = [];
+
+  try {
+    if (options.pack) {
+      entries.push({ skill: null, result: await validator.validateTemplatePack(options.pack) });
+    } else {
+      const skillsDir = path.join(templatesDir, 'skills');
+      const skills = options.skill ? [options.skill] : await listDirectories(skillsDir);
+
+      for (const skill of skills) {
+        const results = await validator.validateAllTemplatePacks(path.join(skillsDir, skill));
+        results.forEach((result) => entries.push({ skill, result }));
+      }
+    }
+  } catch (error) {
+    writeJsonError('validate', 'VALIDATION_ERROR', (error as Error).message);
+    process.exit(1);
+  }
+
+  const output = buildValidationOutput(entries);
+  writeJsonSuccess('validate', output);
+
+  if (output.summary.invalid > 0) {
+    writeJsonError(
+      'validate',
+      'VALIDATION_FAILED',
+      `${output.summary.invalid} template pack(s) failed validation`,
+      output.summary
+    );
+    process.exit(1);
+  }
+}
+
 /**
  * Validate a specific template pack
  */
@@ -114,7 +219,6 @@ async function validateAllPacks(
   const spinner = ora('Scanning for template packs...').start();
 
   const skillsDir = path.join(templatesDir, 'skills');
-  const { listDirectories } = await import('../../utils/file-operations.js');
   const skills = await listDirectories(skillsDir);
 
   spinner.stop();
@@ -6,6 +6,7 @@ import { initCommand } from './commands/init.js';
 import { validateCommand } from './commands/validate.js';
 import { regenerateDocsCommand } from './commands/regenerate-docs.js';
 import { templatesCommand } from './commands/templates.js';
+import { generateTypesCommand } from './commands/generate-types.js';
 
 const program = new Command();
 
@@ -32,12 +33,15 @@ program
   .option('--skills <skills>', 'Comma-separated list of skills to install')
   .option('--no-mcp', 'Skip MCP server configuration')
   .option('--mode <mode>', 'Tech stack mode: strict, flexible, or adaptive', 'flexible')
+  .option('--concurrency <n>', 'Max template files rendered in parallel (default: CPU count)')
+  .option('--output <format>', 'Output format: text or json (json requires --yes)', 'text')
   .action(initCommand);
 
 // Templates command
 program
   .command('templates')
   .description('List available tech stack templates')
+  .option('--output <format>', 'Output format: text or json', 'text')
   .action(templatesCommand);
 
 // Validate command
@@ -47,6 +51,7 @@ program
   .option('--skill <skill>', 'Validate template packs for a specific skill')
   .option('--pack <pack>', 'Validate a specific template pack directory')
   .option('-v, --verbose', 'Show detailed validation information')
+  .option('--output <format>', 'Output format: text or json', 'text')
   .action(validateCommand);
 
 // Regenerate docs command
@@ -55,6 +60,19 @@ program
   .description('Regenerate documentation files (tech-stack.md) from agentweaver.config.yml')
   .action(regenerateDocsCommand);
 
+// Generate types command
+program
+  .command('generate-types')
+  .description('Generate TypeScript definitions for the api-pagination response envelope')
+  .option('--out <file>', 'Output .d.ts file', 'pagination.d.ts')
+  .option('--source <file>', 'Go models.go to read (default: the bundled api-pagination pack)')
+  .option(
+    '--model <models>',
+    'Comma-separated model types to emit page aliases for (e.g., Product,Order)'
+  )
+  .option('--models-from <module>', 'Module the --model types are imported from', './models')
+  .action(generateTypesCommand);
+
 // Global error handling
 program.exitOverride();
 
@@ -0,0 +1,322 @@
+import os from 'os';
+import path from 'path';
+import fs from 'fs-extra';
+import { execFile } from 'child_process';
+import { promisify } from 'util';
+import { mapWithConcurrency } from '../utils/concurrency.js';
+
+const execFileAsync = promisify(execFile);
+
+/**
+ * Transactional file generation for template packs
+ *
+ * Files are rendered into a staging directory, verified there, and only then moved into the
+ * project. Originals that get overwritten are backed up first, so any failure (template error,
+ * verification failure, disk full) restores the project to exactly its previous state, including
+ * removing any directories the commit created.
+ *
+ * Staging and verification are safe to run concurrently; commit order (and therefore any report
+ * built from stagedFiles) is sorted by path so it does not depend on which render finished first.
+ */
+
+export class GenerationError extends Error {
+  constructor(
+    message: string,
+    public readonly file: string | null,
+    public readonly originalError?: Error
+  ) {
+    super(message);
+    this.name = 'GenerationError';
+  }
+}
+
+interface StagedFile {
+  /** Path relative to the project root (used in reports) */
+  relativePath: string;
+
+  /** Rendered content location inside the staging directory */
+  stagedPath: string;
+
+  /** Final location inside the project */
+  targetPath: string;
+}
+
+export interface VerifyOptions {
+  /** Maximum files verified at once (default 1) */
+  concurrency?: number;
+
+  /** Called after each file is verified, in completion order */
+  onProgress?: (done: number, total: number, file: string) => void;
+}
+
+interface CommittedFile {
+  file: StagedFile;
+
+  /** Backup of the original file, or null when the target did not exist */
+  backupPath: string | null;
+}
+
+export class GenerationTransaction {
+  private readonly projectRoot: string;
+  private stagingDirPromise: Promise<string> | null = null;
+  private staged = new Map<string, StagedFile>();
+  private committed: CommittedFile[] = [];
+  private createdDirectories: string[] = [];
+
+  constructor(projectRoot: string) {
+    this.projectRoot = path.resolve(projectRoot);
+  }
+
+  /**
+   * Relative paths of all staged files, sorted by path
+   */
+  get stagedFiles(): string[] {
+    return this.files.map((file) => file.relativePath);
+  }
+
+  private get files(): StagedFile[] {
+    return [...this.staged.values()].sort((a, b) => a.relativePath.localeCompare(b.relativePath));
+  }
+
+  /**
+   * Render a file into the staging directory
+   */
+  async stage(targetPath: string, content: string): Promise<void> {
+    const absoluteTarget = path.resolve(this.projectRoot, targetPath);
+    const relativePath = path.relative(this.projectRoot, absoluteTarget);
+
+    if (relativePath.startsWith('..') || path.isAbsolute(relativePath)) {
+      throw new GenerationError(`Refusing to write outside the project: ${targetPath}`, targetPath);
+    }
+
+    const stagingDir = await this.ensureStagingDir();
+    const stagedPath = path.join(stagingDir, 'files', relativePath);
+
+    try {
+      await fs.outputFile(stagedPath, content, 'utf-8');
+    } catch (error) {
+      throw new GenerationError(
+        `Failed to stage ${relativePath}: ${(error as Error).message}`,
+        relativePath,
+        error as Error
+      );
+    }
+
+    this.staged.set(relativePath, { relativePath, stagedPath, targetPath: absoluteTarget });
+  }
+
+  /**
+   * Format and verify staged files before anything touches the project
+   * - .json files must parse
+   * - .go files are formatted with gofmt when it is installed (syntax errors fail verification)
+   * - .go files then have their imports checked with goimports when it is installed (a missing
+   *   or unused import fails verification, since the generated package would not compile)
+   * Every file is checked even when some fail; all failures are reported together
+   */
+  async verify(options: VerifyOptions = {}): Promise<void> {
+    const files = this.files;
+    let done = 0;
+
+    const results = await mapWithConcurrency(files, options.concurrency ?? 1, async (file) => {
+      try {
+        await this.verifyFile(file);
+      } finally {
+        options.onProgress?.(++done, files.length, file.relativePath);
+      }
+    });
+
+    const failures = results.flatMap((result, index) =>
+      result.status === 'rejected'
+        ? [{ file: files[index].relativePath, error: result.reason as Error }]
+        : []
+    );
+
+    // Imports are checked once every file is formatted, because goimports reads sibling files
+    // to find package-level declarations
+    if (failures.length === 0) {
+      failures.push(...(await this.checkGoImports(files)));
+    }
+
+    if (failures.length === 1) {
+      const [failure] = failures;
+      throw new GenerationError(
+        `Verification failed for ${failure.file}: ${failure.error.message}`,
+        failure.file,
+        failure.error
+      );
+    }
+
+    if (failures.length > 1) {
+      const details = failures.map((failure) => `  ${failure.file}: ${failure.error.message}`);
+      throw new GenerationError(
+        `Verification failed for ${failures.length} files:\n${details.join('\n')}`,
+        failures[0].file,
+        failures[0].error
+      );
+    }
+  }
+
+  /**
+   * Move staged files into the project, backing up originals
+   * On failure every already-moved file is restored and the error is rethrown
+   */
+  async commit(): Promise<void> {
+    const stagingDir = await this.ensureStagingDir();
+
+    for (const file of this.files) {
+      try {
+        let backupPath: string | null = null;
+
+        if (await fs.pathExists(file.targetPath)) {
+          backupPath = path.join(stagingDir, 'backup', file.relativePath);
+          await fs.copy(file.targetPath, backupPath);
+        }
+
+        this.committed.push({ file, backupPath });
+        this.createdDirectories.push(...(await this.missingDirectories(file.targetPath)));
+        await fs.move(file.stagedPath, file.targetPath, { overwrite: true });
+      } catch (error) {
+        await this.rollback();
+        throw new GenerationError(
+          `Failed to write ${file.relativePath}: ${(error as Error).message}`,
+          file.relativePath,
+          error as Error
+        );
+      }
+    }
+  }
+
+  /**
+   * Restore the project to its state before commit()
+   */
+  async rollback(): Promise<void> {
+    for (const { file, backupPath } of [...this.committed].reverse()) {
+      try {
+        if (backupPath) {
+          await fs.copy(backupPath, file.targetPath, { overwrite: true });
+        } else {
+          await fs.remove(file.targetPath);
+        }
+      } catch (error) {
+        console.warn(
+          `Warning: Could not restore ${file.relativePath}: ${(error as Error).message}`
+        );
+      }
+    }
+
+    // Deepest first, so each directory is empty by the time it is removed; rmdir leaves any
+    // directory something else has since written into
+    for (const directory of [...this.createdDirectories].reverse()) {
+      try {
+        await fs.rmdir(directory);
+      } catch (error) {
+        const { code } = error as { code?: string };
+
+        // The move that would have created it failed first
+        if (code === 'ENOENT') continue;
+
+        console.warn(
+          `Warning: Could not remove ${path.relative(this.projectRoot, directory)}: ${(error as Error).message}`
+        );
+      }
+    }
+
+    this.committed = [];
+    this.createdDirectories = [];
+  }
+
+  /**
+   * Remove the staging directory (call once the transaction is committed or abandoned)
+   */
+  async dispose(): Promise<void> {
+    if (this.stagingDirPromise) {
+      await fs.remove(await this.stagingDirPromise);
+      this.stagingDirPromise = null;
+    }
+  }
+
+  /**
+   * Directories a write to targetPath would create, outermost first
+   */
+  private async missingDirectories(targetPath: string): Promise<string[]> {
+    const missing: string[] = [];
+    let dir = path.dirname(targetPath);
+    while (!(await fs.pathExists(dir))) {
+      missing.unshift(dir);
+      dir = path.dirname(dir);
+    }
+    return missing;
+  }
+
+  private async ensureStagingDir(): Promise<string> {
+    // Share one mkdtemp across concurrent stage() calls
+    if (!this.stagingDirPromise) {
+      this.stagingDirPromise = fs.mkdtemp(path.join(os.tmpdir(), 'agentweaver-stage-'));
+    }
+    return this.stagingDirPromise;
+  }
+
+  private async verifyFile(file: StagedFile): Promise<void> {
+    if (file.relativePath.endsWith('.json')) {
+      JSON.parse(await fs.readFile(file.stagedPath, 'utf-8'));
+    } else if (file.relativePath.endsWith('.go')) {
+      await this.formatGoFile(file);
+    }
+  }
+
+  private async formatGoFile(file: StagedFile): Promise<void> {
+    try {
+      await execFileAsync('gofmt', ['-w', file.stagedPath]);
+    } catch (error) {
+      const { code, stderr } = error as { code?: string | number; stderr?: string };
+
+      // gofmt is optional; skip formatting when Go is not installed
+      if (code === 'ENOENT') return;
+
+      const details = (stderr || (error as Error).message)
+        .split(file.stagedPath)
+        .join(file.relativePath);
+      throw new Error(`gofmt reported errors\n${details.trim()}`);
+    }
+  }
+
+  private async checkGoImports(
+    files: StagedFile[]
+  ): Promise<Array<{ file: string; error: Error }>> {
+    const goFiles = files.filter((file) => file.relativePath.endsWith('.go'));
+    if (goFiles.length === 0) return [];
+
+    let stdout: string;
+    try {
+      ({ stdout } = await execFileAsync('goimports', [
+        '-l',
+        ...goFiles.map((file) => file.stagedPath),
+      ]));
+    } catch (error) {
+      const { code, stderr } = error as { code?: string | number; stderr?: string };
+
+      // goimports is optional; gofmt has already caught syntax errors
+      if (code === 'ENOENT') return [];
+
+      let details = stderr || (error as Error).message;
+      for (const file of goFiles) {
+        details = details.split(file.stagedPath).join(file.relativePath);
+      }
+      return [
+        {
+          file: goFiles[0].relativePath,
+          error: new Error(`goimports reported errors\n${details.trim()}`),
+        },
+      ];
+    }
+
+    // goimports -l lists the files whose imports it would add or remove
+    const listed = new Set(stdout.split('\n').map((line) => line.trim()));
+    return goFiles
+      .filter((file) => listed.has(file.stagedPath))
+      .map((file) => ({
+        file: file.relativePath,
+        error: new Error('imports are missing or unused (run goimports on the template output)'),
+      }));
+  }
+}
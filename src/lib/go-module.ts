@@ -0,0 +1,94 @@
+import path from 'path';
+import { pathExists, readFile } from '../utils/file-operations.js';
+
+/**
+ * Go module resolution for template packs that emit Go code
+ *
+ * Generated Go files need real import paths (e.g. `github.com/acme/shop/internal/api/pagination`)
+ * instead of placeholders, so we locate the go.mod governing the output directory and derive
+ * import paths from its module directive.
+ */
+
+export interface GoModuleInfo {
+  /** Module path declared by the `module` directive */
+  modulePath: string;
+
+  /** Directory containing the go.mod file */
+  moduleRoot: string;
+}
+
+export class GoModuleNotFoundError extends Error {
+  constructor(public readonly directory: string) {
+    super(
+      `No go.mod found for ${directory}. Go templates derive import paths from the module path, ` +
+        'so run `go mod init <module-path>` in your project root (or the nested module that should ' +
+        'own the generated code) and re-run the installation.'
+    );
+    this.name = 'GoModuleNotFoundError';
+  }
+}
+
+/**
+ * Extract the module path from go.mod content
+ */
+export function parseModulePath(goModContent: string): string | null {
+  const match = goModContent.match(/^\s*module\s+("?)([^\s"]+)\1\s*(?:\/\/.*)?$/m);
+  return match ? match[2] : null;
+}
+
+/**
+ * Find the nearest go.mod at or above `startDir`
+ * The directory does not need to exist yet, which lets us resolve output directories before writing
+ */
+export async function findGoModule(startDir: string): Promise<GoModuleInfo | null> {
+  let current = path.resolve(startDir);
+  let reachedRoot = false;
+
+  while (!reachedRoot) {
+    const goModPath = path.join(current, 'go.mod');
+
+    if (await pathExists(goModPath)) {
+      const modulePath = parseModulePath(await readFile(goModPath));
+      if (modulePath) {
+        return { modulePath, moduleRoot: current };
+      }
+    }
+
+    const parent = path.dirname(current);
+    reachedRoot = parent === current;
+    current = parent;
+  }
+
+  return null;
+}
+
+/**
+ * Compute the import path of `dir` within the given module
+ */
+export function goImportPath(module: GoModuleInfo, dir: string): string {
+  const relative = path.relative(module.moduleRoot, path.resolve(dir));
+
+  if (relative.startsWith('..') || path.isAbsolute(relative)) {
+    throw new Error(`${dir} is outside the Go module rooted at ${module.moduleRoot}`);
+  }
+
+  // Import paths always use forward slashes, regardless of platform
+  const segments = relative.split(path.sep).filter(Boolean);
+  return [module.modulePath, ...segments].join('/');
+}
+
+/**
+ * Resolve the import path for a directory using its nearest go.mod
+ * Throws GoModuleNotFoundError when the directory is not inside any module
+ */
+export async function resolveGoImportPath(dir: string): Promise<{
+  module: GoModuleInfo;
+  importPath: string;
+}> {
+  const module = await findGoModule(dir);
+  if (!module) {
+    throw new GoModuleNotFoundError(dir);
+  }
+
+  return { module, importPath: goImportPath(module, dir) };
+}
@@ -10,7 +10,10 @@ import {
 } from '../utils/file-operations.js';
 import { parseSkillFile, SkillFrontmatter } from '../utils/yaml-parser.js';
 import { TemplateResolver } from './template-resolver.js';
-import { ResolutionContext, TemplatePackMatch } from './template-pack.js';
+import { ResolutionContext, TemplateFile, TemplatePackMatch } from './template-pack.js';
+import { resolveGoImportPath } from './go-module.js';
+import { GenerationError, GenerationTransaction } from './generation-transaction.js';
+import { defaultConcurrency, mapWithConcurrency } from '../utils/concurrency.js';
 
 /**
  * Skills installer for copying and validating skill directories
@@ -30,6 +33,26 @@ export interface SkillInstallOptions {
   overwrite?: boolean;
   techStackContext?: ResolutionContext; // Tech stack for template resolution
   projectRoot?: string; // Project root for relative path resolution
+  concurrency?: number; // Max files rendered/verified at once (default: CPU count)
+  onProgress?: (progress: GenerationProgress) => void; // Streamed while template packs generate
+}
+
+export type GenerationPhase = 'render' | 'verify' | 'commit';
+
+export interface GenerationProgress {
+  skill: string;
+  phase: GenerationPhase;
+  done: number;
+  total: number;
+  file?: string;
+}
+
+export type GeneratedFileAction = 'written' | 'skipped' | 'merged';
+
+export interface GeneratedFile {
+  skill: string;
+  path: string; // Relative to the project root
+  action: GeneratedFileAction;
 }
 
 export interface SkillInstallResult {
@@ -37,6 +60,17 @@ export interface SkillInstallResult {
   skipped: string[];
   errors: Array<{ skill: string; error: string }>;
   templatePacksUsed?: Array<{ skill: string; templatePack: string; score: number }>;
+  files: GeneratedFile[]; // Template pack files written, skipped, or merged
+}
+
+/** A template pack rendered into the shared transaction, recorded once it commits */
+interface StagedSkill {
+  skill: SkillInfo;
+  sourcePath: string;
+  targetPath: string;
+  templateMatch: TemplatePackMatch;
+  pending: Array<{ file: TemplateFile; targetFilePath: string; exists: boolean }>;
+  skippedFiles: GeneratedFile[];
 }
 
 export class SkillsInstaller {
@@ -127,6 +161,7 @@ export class SkillsInstaller {
       skipped: [],
       errors: [],
       templatePacksUsed: [],
+      files: [],
     };
 
     // Get list of skills to install
@@ -149,8 +184,18 @@ export class SkillsInstaller {
       ? new TemplateResolver(this.sourceDirectory)
       : null;
 
-    // Install each skill
-    for (const skill of skillsToInstall) {
+    // Every template pack renders into one transaction, committed only once all of them
+    // verify, so a failing pack never leaves the packs it works with half installed
+    const transaction = new GenerationTransaction(options.projectRoot || process.cwd());
+    const staged: StagedSkill[] = [];
+    const failedPacks: string[] = [];
+
+    // Install each skill (queue grows when a template pack requires another skill)
+    const queue = [...skillsToInstall];
+    const queued = new Set(queue.map((skill) => skill.dirName));
+
+    for (let index = 0; index < queue.length; index++) {
+      const skill = queue[index];
       const targetPath = path.join(options.targetDirectory, skill.dirName);
 
       // Check if skill already exists
@@ -164,23 +209,53 @@ export class SkillsInstaller {
 
         // Check if skill has templates and we have tech stack context
         if (skill.hasTemplates && templateResolver && options.techStackContext) {
-          // Use template pack resolution
-          const installResult = await this.installSkillWithTemplates(
-            skill,
-            sourcePath,
-            targetPath,
-            templateResolver,
-            options.techStackContext,
-            options.projectRoot
+          // Resolve best template pack
+          const templateMatch = await templateResolver.resolveTemplatePack(
+            skill.dirName,
+            options.techStackContext
           );
 
-          if (installResult.templatePack) {
-            result.templatePacksUsed!.push({
-              skill: skill.name,
-              templatePack: installResult.templatePack.pack.manifest.name,
-              score: installResult.templatePack.score,
-            });
+          if (templateMatch) {
+            try {
+              staged.push(
+                await this.stageSkillTemplates(
+                  skill,
+                  sourcePath,
+                  targetPath,
+                  templateMatch,
+                  transaction,
+                  options
+                )
+              );
+            } catch (error) {
+              failedPacks.push(templateMatch.pack.manifest.name);
+              throw error;
+            }
+
+            for (const required of templateMatch.pack.manifest.requiredSkills || []) {
+              const requiredSkill = availableSkills.find(
+                (candidate) => candidate.dirName === required || candidate.name === required
+              );
+
+              if (!requiredSkill) {
+                console.warn(`  Warning: ${skill.name} requires unknown skill ${required}`);
+              } else if (!queued.has(requiredSkill.dirName)) {
+                console.log(`  Adding ${requiredSkill.name} (required by ${skill.name})`);
+                queued.add(requiredSkill.dirName);
+                queue.push(requiredSkill);
+              }
+            }
+
+            // Recorded as installed once the transaction commits
+            continue;
           }
+
+          await this.writeSkillFile(sourcePath, targetPath);
+
+          // No suitable template pack found, log warning
+          console.warn(
+            `  Warning: No suitable template pack found for ${skill.name} with current tech stack`
+          );
         } else {
           // Legacy: Copy entire skill directory
           await copyDirectory(sourcePath, targetPath);
@@ -195,95 +270,228 @@ export class SkillsInstaller {
       }
     }
 
+    await this.commitStagedSkills(staged, failedPacks, transaction, result, options);
+
     return result;
   }
 
   /**
-   * Install a skill using template pack resolution
+   * Render a skill's template pack into the shared transaction
+   * Nothing touches the project here; commitStagedSkills writes every staged pack at once
    */
-  private async installSkillWithTemplates(
+  private async stageSkillTemplates(
     skill: SkillInfo,
     sourcePath: string,
     targetPath: string,
-    resolver: TemplateResolver,
-    context: ResolutionContext,
-    projectRoot?: string
-  ): Promise<{ templatePack: TemplatePackMatch | null }> {
-    // Ensure target directory exists
-    await ensureDirectory(targetPath);
-
-    // Copy SKILL.md file
-    const skillMdSource = path.join(sourcePath, 'SKILL.md');
-    const skillMdTarget = path.join(targetPath, 'SKILL.md');
-    const skillMdContent = await readFile(skillMdSource);
-    await writeFile(skillMdTarget, skillMdContent);
-
-    // Resolve best template pack
-    const templateMatch = await resolver.resolveTemplatePack(skill.dirName, context);
-
-    if (templateMatch) {
-      // Install files from the selected template pack
-      const projectRootPath = projectRoot || process.cwd();
+    templateMatch: TemplatePackMatch,
+    transaction: GenerationTransaction,
+    options: SkillInstallOptions
+  ): Promise<StagedSkill> {
+    const projectRootPath = options.projectRoot || process.cwd();
+    const staged: StagedSkill = {
+      skill,
+      sourcePath,
+      targetPath,
+      templateMatch,
+      pending: [],
+      skippedFiles: [],
+    };
 
+    try {
       // Prepare template variables context
       const templateContext = this.buildTemplateContext(templateMatch.pack.manifest.variables);
 
-      for (const file of templateMatch.pack.manifest.files) {
-        const sourceFilePath = path.join(templateMatch.pack.packPath, file.source);
+      // Go packs need real import paths derived from the project's go.mod
+      if (templateMatch.pack.manifest.applicability.language === 'go') {
+        await this.applyGoModuleContext(templateContext, projectRootPath);
+      }
 
+      const concurrency = options.concurrency ?? defaultConcurrency();
+
+      // Resolve targets up front so skip decisions and the final report follow manifest order
+      for (const file of templateMatch.pack.manifest.files) {
         // Resolve target path using Handlebars
         const targetFilePathTemplate = Handlebars.compile(file.target);
         const targetFilePath = targetFilePathTemplate(templateContext);
-
-        const absoluteTargetPath = path.join(projectRootPath, targetFilePath);
+        const exists = await pathExists(path.join(projectRootPath, targetFilePath));
 
         // Check strategy
-        if (file.strategy === 'skip-if-exists' && (await pathExists(absoluteTargetPath))) {
+        if (file.strategy === 'skip-if-exists' && exists) {
           console.log(`  Skipping ${targetFilePath} (already exists)`);
+          staged.skippedFiles.push({ skill: skill.name, path: targetFilePath, action: 'skipped' });
           continue;
         }
 
-        // Ensure target directory exists
-        await ensureDirectory(path.dirname(absoluteTargetPath));
+        staged.pending.push({ file, targetFilePath, exists });
+      }
 
-        // Read file content
-        let fileContent = await readFile(sourceFilePath);
+      // Render into the staging directory so a failure never leaves a half-written project
+      const { pending } = staged;
+      let rendered = 0;
+      const results = await mapWithConcurrency(
+        pending,
+        concurrency,
+        async ({ file, targetFilePath }) => {
+          try {
+            // Read file content
+            const sourceFilePath = path.join(templateMatch.pack.packPath, file.source);
+            let fileContent = await readFile(sourceFilePath);
+
+            // Apply Handlebars template interpolation if specified
+            if (file.templateEngine === 'handlebars') {
+              const template = Handlebars.compile(fileContent);
+              fileContent = template(templateContext);
+            }
+
+            await transaction.stage(path.join(projectRootPath, targetFilePath), fileContent);
+          } finally {
+            options.onProgress?.({
+              skill: skill.name,
+              phase: 'render',
+              done: ++rendered,
+              total: pending.length,
+              file: targetFilePath,
+            });
+          }
+        }
+      );
 
-        // Apply Handlebars template interpolation if specified
-        if (file.templateEngine === 'handlebars') {
-          const template = Handlebars.compile(fileContent);
-          fileContent = template(templateContext);
+      // One broken template does not stop the others rendering, but nothing is committed
+      const failures = results.flatMap((result, index) =>
+        result.status === 'rejected'
+          ? [`  ${pending[index].file.source}: ${(result.reason as Error).message}`]
+          : []
+      );
+      if (failures.length > 0) {
+        throw new GenerationError(
+          `Failed to render ${failures.length} file(s):\n${failures.join('\n')}`,
+          null
+        );
+      }
+    } catch (error) {
+      throw new Error(
+        `Generation of ${templateMatch.pack.manifest.name} failed, no project files were changed: ${(error as Error).message}`
+      );
+    }
+
+    return staged;
+  }
+
+  /**
+   * Verify every staged pack, move them all into the project, then record their skills
+   * When any pack failed to render or verify, or the commit fails, no pack is committed and
+   * each staged skill reports the failure
+   */
+  private async commitStagedSkills(
+    staged: StagedSkill[],
+    failedPacks: string[],
+    transaction: GenerationTransaction,
+    result: SkillInstallResult,
+    options: SkillInstallOptions
+  ): Promise<void> {
+    try {
+      if (staged.length === 0) return;
+
+      if (failedPacks.length > 0) {
+        throw new GenerationError(`${failedPacks.join(', ')} failed to generate`, null);
+      }
+
+      // Progress is attributed to the skill whose pack staged each file
+      const skillOf = new Map<string, string>();
+      for (const { skill, pending } of staged) {
+        for (const { targetFilePath } of pending) {
+          skillOf.set(path.normalize(targetFilePath), skill.name);
         }
+      }
 
-        // Write processed file
-        await writeFile(absoluteTargetPath, fileContent);
-        console.log(`  ✓ Installed ${targetFilePath}`);
+      // Format and verify in staging, then move everything into place (rolls back on failure)
+      await transaction.verify({
+        concurrency: options.concurrency ?? defaultConcurrency(),
+        onProgress: (done, total, file) =>
+          options.onProgress?.({
+            skill: skillOf.get(file) ?? '',
+            phase: 'verify',
+            done,
+            total,
+            file,
+          }),
+      });
+
+      const skills = staged.map(({ skill }) => skill.name).join(', ');
+      const total = transaction.stagedFiles.length;
+      options.onProgress?.({ skill: skills, phase: 'commit', done: 0, total });
+      await transaction.commit();
+      options.onProgress?.({ skill: skills, phase: 'commit', done: total, total });
+    } catch (error) {
+      for (const { skill, templateMatch } of staged) {
+        result.errors.push({
+          skill: skill.name,
+          error: `Generation of ${templateMatch.pack.manifest.name} failed, no project files were changed: ${(error as Error).message}`,
+        });
       }
+      return;
+    } finally {
+      await transaction.dispose();
+    }
 
-      // Create a templates-used.json file for reference
-      const templatesUsedPath = path.join(targetPath, 'templates-used.json');
-      await writeFile(
-        templatesUsedPath,
-        JSON.stringify(
-          {
-            templatePack: templateMatch.pack.manifest.name,
-            version: templateMatch.pack.manifest.version,
-            score: templateMatch.score,
-            reasons: templateMatch.reasons,
-            installedAt: new Date().toISOString(),
-          },
-          null,
-          2
-        )
-      );
-    } else {
-      // No suitable template pack found, log warning
-      console.warn(
-        `  Warning: No suitable template pack found for ${skill.name} with current tech stack`
-      );
+    for (const staging of staged) {
+      await this.recordStagedSkill(staging, result);
     }
+  }
 
-    return { templatePack: templateMatch };
+  /**
+   * Report a committed pack's files and install its skill
+   */
+  private async recordStagedSkill(
+    { skill, sourcePath, targetPath, templateMatch, pending, skippedFiles }: StagedSkill,
+    result: SkillInstallResult
+  ): Promise<void> {
+    result.files.push(...skippedFiles);
+    for (const { file, targetFilePath, exists } of pending) {
+      console.log(`  ✓ Installed ${targetFilePath}`);
+      result.files.push({
+        skill: skill.name,
+        path: targetFilePath,
+        action: file.strategy === 'merge' && exists ? 'merged' : 'written',
+      });
+    }
+
+    // Only record the skill once its files are committed
+    await this.writeSkillFile(sourcePath, targetPath);
+
+    // Create a templates-used.json file for reference
+    const templatesUsedPath = path.join(targetPath, 'templates-used.json');
+    await writeFile(
+      templatesUsedPath,
+      JSON.stringify(
+        {
+          templatePack: templateMatch.pack.manifest.name,
+          version: templateMatch.pack.manifest.version,
+          score: templateMatch.score,
+          reasons: templateMatch.reasons,
+          installedAt: new Date().toISOString(),
+        },
+        null,
+        2
+      )
+    );
+
+    result.installed.push(skill);
+    result.templatePacksUsed!.push({
+      skill: skill.name,
+      templatePack: templateMatch.pack.manifest.name,
+      score: templateMatch.score,
+    });
+  }
+
+  /**
+   * Copy the skill's SKILL.md into the target skill directory
+   */
+  private async writeSkillFile(sourcePath: string, targetPath: string): Promise<void> {
+    await ensureDirectory(targetPath);
+
+    const skillMdContent = await readFile(path.join(sourcePath, 'SKILL.md'));
+    await writeFile(path.join(targetPath, 'SKILL.md'), skillMdContent);
   }
 
   /**
@@ -305,6 +513,22 @@ export class SkillsInstaller {
     return context;
   }
 
+  /**
+   * Populate Go module variables from the go.mod governing the output directory
+   * - moduleName: module path of the nearest go.mod (nested modules win over the root module)
+   * - packageImportPath: import path of {{packagePath}}, for cross-package imports in templates
+   */
+  private async applyGoModuleContext(
+    context: Record<string, any>,
+    projectRoot: string
+  ): Promise<void> {
+    const packageDir = path.join(projectRoot, context.packagePath || '');
+    const { module, importPath } = await resolveGoImportPath(packageDir);
+
+    context.moduleName = module.modulePath;
+    context.packageImportPath = importPath;
+  }
+
   /**
    * Gets information about a specific skill
    */
@@ -90,6 +90,9 @@ export class TemplatePackValidator {
     // Validate variables
     this.validateVariables(manifest, errors, warnings);
 
+    // Validate required skills
+    await this.validateRequiredSkills(packPath, manifest, errors, warnings);
+
     return {
       valid: errors.length === 0,
       errors,
@@ -345,6 +348,51 @@ export class TemplatePackValidator {
     }
   }
 
+  /**
+   * Validate that required skills are well-formed and exist next to this pack's skill
+   * Packs live at skills/<skill>/templates/<pack>, so sibling skills are three levels up
+   */
+  private async validateRequiredSkills(
+    packPath: string,
+    manifest: TemplatePackManifest,
+    errors: ValidationError[],
+    warnings: ValidationWarning[]
+  ): Promise<void> {
+    if (manifest.requiredSkills === undefined) return;
+
+    if (!Array.isArray(manifest.requiredSkills)) {
+      errors.push({
+        type: 'error',
+        field: 'requiredSkills',
+        message: 'requiredSkills must be an array of skill directory names',
+        severity: 'high',
+      });
+      return;
+    }
+
+    const skillsRoot = path.resolve(packPath, '..', '..', '..');
+
+    for (const required of manifest.requiredSkills) {
+      if (typeof required !== 'string' || required.trim() === '') {
+        errors.push({
+          type: 'error',
+          field: 'requiredSkills',
+          message: 'requiredSkills entries must be non-empty strings',
+          severity: 'high',
+        });
+        continue;
+      }
+
+      if (!(await pathExists(path.join(skillsRoot, required, 'SKILL.md')))) {
+        warnings.push({
+          type: 'warning',
+          field: `requiredSkills[${required}]`,
+          message: `Required skill '${required}' was not found alongside this pack's skill`,
+        });
+      }
+    }
+  }
+
   /**
    * Extract variable names from a Handlebars template string
    */
@@ -24,6 +24,9 @@ export interface TemplatePackManifest {
   /** Files to be copied/generated */
   files: TemplateFile[];
 
+  /** Other skills whose generated code this pack imports (installed alongside it) */
+  requiredSkills?: string[];
+
   /** Variables that can be used in templates */
   variables?: Record<string, VariableDefinition>;
 
@@ -0,0 +1,375 @@
+import path from 'path';
+import { getTemplatesDirectory } from '../utils/file-operations.js';
+
+/**
+ * TypeScript definitions for the api-pagination response envelope
+ *
+ * Frontends consume PaginatedResponse/PaginationMeta/PaginationLinks as JSON, so their types
+ * are derived from the Go structs themselves: json tags name the properties, pointers and
+ * omitempty become optional (`?:`), the item type parameter stays generic, and the fields each
+ * paginator's ToResponse fills in split PaginationMeta into offset and cursor variants.
+ */
+
+/** Go source the definitions are derived from, relative to the templates directory */
+export const PAGINATION_MODELS_SOURCE = path.join(
+  'skills',
+  'api-pagination',
+  'templates',
+  'gin',
+  'models.go'
+);
+
+/** Response structs emitted as interfaces, in output order */
+const ENVELOPE_STRUCTS = ['PaginationLinks', 'PaginationMeta', 'PaginatedResponse'];
+
+export interface GoField {
+  /** Go field name */
+  name: string;
+
+  /** Go type expression, e.g. `*int64` or `[]T` */
+  type: string;
+
+  /** JSON property name (the Go name when untagged) */
+  jsonName: string;
+
+  omitEmpty: boolean;
+
+  /** Comment lines directly above the field */
+  doc: string[];
+}
+
+export interface GoStruct {
+  name: string;
+
+  /** Type parameter names, e.g. ['T'] for `PaginatedResponse[T any]` */
+  typeParams: string[];
+
+  fields: GoField[];
+
+  /** Comment lines directly above the type declaration */
+  doc: string[];
+}
+
+/**
+ * A paginator's ToResponse: the PaginationMeta fields it sets, and which of them it always
+ * sets (`&p.Field` and never reassigned, so present even though the Go type is a pointer)
+ */
+export interface ResponseVariant {
+  /** Paginator prefix, e.g. `Offset` for OffsetPagination */
+  prefix: string;
+
+  fields: string[];
+  alwaysSet: string[];
+}
+
+export interface TypesGeneratorOptions {
+  /** Model type names to emit concrete page aliases for (`--model`) */
+  models?: string[];
+
+  /** Module the model types are imported from (default `./models`) */
+  modelsFrom?: string;
+
+  /** Source file name recorded in the header */
+  sourceName?: string;
+}
+
+export class TypesGenerationError extends Error {
+  constructor(message: string) {
+    super(message);
+    this.name = 'TypesGenerationError';
+  }
+}
+
+/**
+ * Default location of the Go response types in the bundled templates
+ */
+export function defaultModelsSourcePath(): string {
+  return path.join(getTemplatesDirectory(), PAGINATION_MODELS_SOURCE);
+}
+
+/**
+ * Parse the struct declarations of a Go source file
+ */
+export function parseGoStructs(source: string): Map<string, GoStruct> {
+  const structs = new Map<string, GoStruct>();
+  const lines = source.split('\n');
+
+  for (let i = 0; i < lines.length; i++) {
+    const header = lines[i].match(/^type\s+(\w+)(?:\[([^\]]*)\])?\s+struct\s*\{\s*$/);
+    if (!header) {
+      continue;
+    }
+
+    const typeParams = header[2]
+      ? header[2]
+          .split(',')
+          .map((param) => param.trim().split(/\s+/)[0])
+          .filter(Boolean)
+      : [];
+    const struct: GoStruct = {
+      name: header[1],
+      typeParams,
+      fields: [],
+      doc: commentBlockAbove(lines, i),
+    };
+
+    let doc: string[] = [];
+    for (i++; i < lines.length && !/^\}/.test(lines[i]); i++) {
+      const line = lines[i].trim();
+      if (line === '') {
+        doc = [];
+        continue;
+      }
+      if (line.startsWith('//')) {
+        doc.push(line.replace(/^\/\/\s?/, ''));
+        continue;
+      }
+
+      const field = line.match(/^(\w+)\s+([^`]+?)\s*(?:`([^`]*)`)?\s*(?:\/\/.*)?$/);
+      if (!field) {
+        throw new TypesGenerationError(`Unsupported field in ${struct.name}: ${line}`);
+      }
+
+      const [, name, type, tags] = field;
+      const jsonTag = tags?.match(/json:"([^"]*)"/)?.[1];
+      const [tagName, ...tagOptions] = (jsonTag ?? '').split(',');
+      const exported = /^[A-Z]/.test(name);
+
+      if (exported && tagName !== '-') {
+        struct.fields.push({
+          name,
+          type,
+          jsonName: tagName || name,
+          omitEmpty: tagOptions.includes('omitempty'),
+          doc,
+        });
+      }
+      doc = [];
+    }
+
+    structs.set(struct.name, struct);
+  }
+
+  return structs;
+}
+
+/**
+ * Find the PaginationMeta fields each paginator's ToResponse sets
+ */
+export function parseResponseVariants(source: string): ResponseVariant[] {
+  const variants: ResponseVariant[] = [];
+  const method = /^func \(\w+ \*(\w+)Pagination\[\w+\]\) ToResponse\(/gm;
+
+  let match: RegExpExecArray | null;
+  while ((match = method.exec(source)) !== null) {
+    const end = source.indexOf('\n}\n', match.index);
+    const body = source.slice(match.index, end === -1 ? undefined : end);
+    const fields: string[] = [];
+    const alwaysSet: string[] = [];
+
+    const literal = body.match(/Pagination:\s*PaginationMeta\{([\s\S]*?)\n\t\t\}/);
+    for (const assignment of literal?.[1].matchAll(/^\s*(\w+):\s*(.+?),\s*$/gm) ?? []) {
+      fields.push(assignment[1]);
+      if (assignment[2].startsWith('&')) {
+        alwaysSet.push(assignment[1]);
+      }
+    }
+    // Fields assigned after the literal (`a, b = x, y` included) may be cleared, so they are
+    // never always-set
+    const assigned = /((?:response\.Pagination\.\w+,\s*)*response\.Pagination\.\w+)\s*=[^=]/g;
+    for (const assignment of body.matchAll(assigned)) {
+      for (const [, name] of assignment[1].matchAll(/response\.Pagination\.(\w+)/g)) {
+        if (!fields.includes(name)) {
+          fields.push(name);
+        }
+        const index = alwaysSet.indexOf(name);
+        if (index !== -1) {
+          alwaysSet.splice(index, 1);
+        }
+      }
+    }
+
+    variants.push({ prefix: match[1], fields, alwaysSet });
+  }
+
+  return variants;
+}
+
+/**
+ * Generate the .d.ts content for the pagination envelope from the Go source of models.go
+ */
+export function generatePaginationTypes(
+  source: string,
+  options: TypesGeneratorOptions = {}
+): string {
+  const structs = parseGoStructs(source);
+  const known = new Set(ENVELOPE_STRUCTS);
+  const out: string[] = [
+    '// Code generated by `agentweaver generate-types` from the api-pagination Go response types. DO NOT EDIT.',
+    `// Source: ${options.sourceName ?? 'models.go'}`,
+    '',
+  ];
+
+  const models = options.models ?? [];
+  if (models.length > 0) {
+    const modelsFrom = options.modelsFrom ?? './models';
+    out.push(`import type { ${models.join(', ')} } from '${modelsFrom}';`, '');
+  }
+
+  for (const name of ENVELOPE_STRUCTS) {
+    const struct = structs.get(name);
+    if (!struct) {
+      throw new TypesGenerationError(`${name} not found in ${options.sourceName ?? 'models.go'}`);
+    }
+    out.push(...renderInterface(struct, known), '');
+  }
+
+  // Offset and cursor variants of the metadata, and of the envelope carrying it
+  const meta = structs.get('PaginationMeta')!;
+  const variants = parseResponseVariants(source);
+  for (const variant of variants) {
+    const metaName = `${variant.prefix}PaginationMeta`;
+    const unknown = variant.fields.find(
+      (fieldName) => !meta.fields.some((field) => field.name === fieldName)
+    );
+    if (unknown) {
+      throw new TypesGenerationError(
+        `${variant.prefix}Pagination.ToResponse sets PaginationMeta.${unknown}, which has no JSON property`
+      );
+    }
+
+    // Keep PaginationMeta's field order; fields ToResponse always sets are required
+    const fields = meta.fields
+      .filter((field) => variant.fields.includes(field.name))
+      .map((field) =>
+        variant.alwaysSet.includes(field.name)
+          ? { ...field, type: field.type.replace(/^\*/, ''), omitEmpty: false }
+          : field
+      );
+
+    out.push(
+      ...renderInterface(
+        {
+          name: metaName,
+          typeParams: [],
+          fields: fields.map((field) => ({ ...field, doc: [] })),
+          doc: [`${metaName} is the PaginationMeta set by ${variant.prefix}Pagination.ToResponse`],
+        },
+        known
+      ),
+      '',
+      `/** ${variant.prefix}PaginatedResponse is the envelope of ${variant.prefix}Pagination.ToResponse */`,
+      `export interface ${variant.prefix}PaginatedResponse<T> extends PaginatedResponse<T> {`,
+      `  pagination: ${metaName};`,
+      '}',
+      ''
+    );
+  }
+
+  // Concrete per-endpoint aliases
+  for (const model of models) {
+    for (const variant of variants) {
+      out.push(
+        `export type ${model}${variant.prefix}Page = ${variant.prefix}PaginatedResponse<${model}>;`
+      );
+    }
+  }
+  if (models.length > 0) {
+    out.push('');
+  }
+
+  return out.join('\n');
+}
+
+/**
+ * Render a Go struct as an exported TypeScript interface
+ */
+function renderInterface(struct: GoStruct, known: Set<string>): string[] {
+  const typeParams = new Set(struct.typeParams);
+  const generics = struct.typeParams.length > 0 ? `<${struct.typeParams.join(', ')}>` : '';
+  const lines = [...renderDoc(struct.doc, ''), `export interface ${struct.name}${generics} {`];
+
+  for (const field of struct.fields) {
+    const pointer = field.type.startsWith('*');
+    const tsType = goTypeToTs(field.type.replace(/^\*/, ''), typeParams, known);
+    const optional = pointer || field.omitEmpty;
+    const nullable = pointer && !field.omitEmpty ? ' | null' : '';
+
+    lines.push(...renderDoc(field.doc, '  '));
+    lines.push(`  ${field.jsonName}${optional ? '?' : ''}: ${tsType}${nullable};`);
+  }
+
+  lines.push('}');
+  return lines;
+}
+
+/**
+ * Render comment lines as a JSDoc block
+ */
+function renderDoc(doc: string[], indent: string): string[] {
+  if (doc.length === 0) {
+    return [];
+  }
+  if (doc.length === 1) {
+    return [`${indent}/** ${doc[0]} */`];
+  }
+  return [`${indent}/**`, ...doc.map((line) => `${indent} * ${line}`.trimEnd()), `${indent} */`];
+}
+
+/**
+ * Map a Go type expression to TypeScript
+ */
+export function goTypeToTs(goType: string, typeParams: Set<string>, known: Set<string>): string {
+  const type = goType.trim();
+
+  if (type.startsWith('*')) {
+    return goTypeToTs(type.slice(1), typeParams, known);
+  }
+  if (type.startsWith('[]')) {
+    const element = goTypeToTs(type.slice(2), typeParams, known);
+    return /^[\w.]+$/.test(element) ? `${element}[]` : `Array<${element}>`;
+  }
+
+  const map = type.match(/^map\[(\w+)\](.+)$/);
+  if (map) {
+    return `Record<string, ${goTypeToTs(map[2], typeParams, known)}>`;
+  }
+
+  const generic = type.match(/^(\w+)\[(.+)\]$/);
+  if (generic && known.has(generic[1])) {
+    const args = generic[2].split(',').map((arg) => goTypeToTs(arg, typeParams, known));
+    return `${generic[1]}<${args.join(', ')}>`;
+  }
+
+  if (typeParams.has(type) || known.has(type)) {
+    return type;
+  }
+  if (/^(u?int(8|16|32|64)?|float(32|64)|byte|rune)$/.test(type)) {
+    return 'number';
+  }
+
+  switch (type) {
+    case 'string':
+    case 'time.Time':
+      return 'string';
+    case 'bool':
+      return 'boolean';
+    case 'any':
+    case 'interface{}':
+    case 'json.RawMessage':
+      return 'unknown';
+  }
+
+  throw new TypesGenerationError(`Unsupported Go type for TypeScript generation: ${type}`);
+}
+
+/**
+ * Comment lines directly above line `index`
+ */
+function commentBlockAbove(lines: string[], index: number): string[] {
+  const doc: string[] = [];
+  for (let i = index - 1; i >= 0 && lines[i].startsWith('//'); i--) {
+    doc.unshift(lines[i].replace(/^\/\/\s?/, ''));
+  }
+  return doc;
+}
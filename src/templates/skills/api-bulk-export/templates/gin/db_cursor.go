@@ -0,0 +1,81 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// dbCursorName names the server-side cursor of an export; cursors are scoped to their
+// transaction, so concurrent exports do not collide
+const dbCursorName = "export_rows"
+
+// supportsDBCursor reports whether db's dialect has DECLARE/FETCH server-side cursors
+func supportsDBCursor(db *gorm.DB) bool {
+	return db.Dialector != nil && db.Dialector.Name() == "postgres"
+}
+
+// walkDBCursor emits every row of db from a server-side cursor
+// The query runs once: the cursor is declared over it, ordered by the key column, and each
+// batch is a FETCH of the next rows, all in one transaction. There is no per-batch planning or
+// index seek, and the export reads one consistent snapshot; the trade-off is a transaction
+// held open for the whole export, so keep the time budget bounded.
+func walkDBCursor[T any](ctx context.Context, db *gorm.DB, ascending bool, cfg Config, summary *Summary, emit func(batch []T) error) error {
+	direction := " ASC"
+	if !ascending {
+		direction = " DESC"
+	}
+	query := db.Model(new(T)).Order(cfg.KeyColumn + direction)
+
+	err := db.Session(&gorm.Session{NewDB: true}).WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DECLARE "+dbCursorName+" NO SCROLL CURSOR FOR ?", query).Error; err != nil {
+			return fmt.Errorf("failed to declare export cursor: %w", err)
+		}
+
+		for {
+			batchSize := cfg.BatchSize
+			if remaining := cfg.MaxRows - summary.Rows; remaining < int64(batchSize) {
+				batchSize = int(remaining)
+			}
+			if batchSize == 0 {
+				// The row cap is reached; one more row means the export is truncated
+				var next []T
+				if err := fetch(tx, 1, &next); err != nil {
+					return err
+				}
+				if len(next) > 0 {
+					summary.Truncated, summary.Reason = true, ReasonRowCap
+				}
+				break
+			}
+
+			var batch []T
+			if err := fetch(tx, batchSize, &batch); err != nil {
+				return err
+			}
+			if err := emit(batch); err != nil {
+				return err
+			}
+			if len(batch) < batchSize {
+				break
+			}
+		}
+
+		return tx.Exec("CLOSE " + dbCursorName).Error
+	})
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		summary.Truncated, summary.Reason = true, ReasonTimeBudget
+		return nil
+	}
+	return err
+}
+
+// fetch reads the cursor's next n rows into dest
+func fetch[T any](tx *gorm.DB, n int, dest *[]T) error {
+	if err := tx.Raw(fmt.Sprintf("FETCH FORWARD %d FROM %s", n, dbCursorName)).Find(dest).Error; err != nil {
+		return fmt.Errorf("failed to fetch export batch: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+type shipment struct {
+	ID int64 `json:"id"`
+}
+
+// postgresDialector reports the postgres dialect and nothing else
+type postgresDialector struct {
+	gorm.Dialector
+}
+
+func (postgresDialector) Name() string { return "postgres" }
+
+func (postgresDialector) Explain(sql string, vars ...interface{}) string { return sql }
+
+// cursorPool is a connection pool that only begins transactions; the callbacks serve the rows
+type cursorPool struct {
+	commits int
+}
+
+func (p *cursorPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return nil, nil
+}
+
+func (p *cursorPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return nil, nil
+}
+
+func (p *cursorPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, nil
+}
+
+func (p *cursorPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return nil
+}
+
+func (p *cursorPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	return &cursorTx{cursorPool: p}, nil
+}
+
+// cursorTx is a transaction of a cursorPool
+type cursorTx struct {
+	*cursorPool
+}
+
+func (tx *cursorTx) Commit() error {
+	tx.commits++
+	return nil
+}
+
+func (tx *cursorTx) Rollback() error { return nil }
+
+// cursorDB serves rows 1..rows through a fake server-side cursor; statements records the
+// cursor statements and fetches, and keyset pages fail the test
+func cursorDB(t *testing.T, rows int64, statements *[]string) (*gorm.DB, *cursorPool) {
+	t.Helper()
+	db, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Dialector = postgresDialector{}
+	pool := &cursorPool{}
+	db.Statement.ConnPool = pool
+
+	var position int64
+	err = db.Callback().Raw().Register("test:declare", func(tx *gorm.DB) {
+		*statements = append(*statements, strings.Fields(tx.Statement.SQL.String())[0])
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Callback().Query().Register("test:fetch", func(tx *gorm.DB) {
+		dest, ok := tx.Statement.Dest.(*[]shipment)
+		if !ok {
+			return
+		}
+		var n int64
+		if _, err := fmt.Sscanf(tx.Statement.SQL.String(), "FETCH FORWARD %d FROM "+dbCursorName, &n); err != nil {
+			t.Errorf("page query %q, want a FETCH from the export cursor", tx.Statement.SQL.String())
+			return
+		}
+		*statements = append(*statements, fmt.Sprintf("FETCH %d", n))
+		for ; n > 0 && position < rows; n-- {
+			position++
+			*dest = append(*dest, shipment{ID: position})
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db.Model(&shipment{}), pool
+}
+
+func TestWriteThroughDBCursor(t *testing.T) {
+	var statements []string
+	db, pool := cursorDB(t, 5, &statements)
+
+	var out bytes.Buffer
+	summary, err := Write[shipment](context.Background(), db, &out, FormatNDJSON, true, Config{BatchSize: 2, DBCursor: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Rows != 5 || summary.Truncated {
+		t.Errorf("summary %+v, want all 5 rows", summary)
+	}
+	if lines := strings.Count(out.String(), "\n"); lines != 5 {
+		t.Errorf("wrote %d lines, want 5:\n%s", lines, out.String())
+	}
+
+	want := "DECLARE,FETCH 2,FETCH 2,FETCH 2,CLOSE"
+	if got := strings.Join(statements, ","); got != want {
+		t.Errorf("statements = %s, want %s", got, want)
+	}
+	if pool.commits != 1 {
+		t.Errorf("commits = %d, want one transaction", pool.commits)
+	}
+}
+
+func TestDBCursorRowCap(t *testing.T) {
+	var statements []string
+	db, _ := cursorDB(t, 5, &statements)
+
+	var out bytes.Buffer
+	summary, err := Write[shipment](context.Background(), db, &out, FormatNDJSON, true, Config{BatchSize: 2, MaxRows: 3, DBCursor: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Rows != 3 || !summary.Truncated || summary.Reason != ReasonRowCap {
+		t.Errorf("summary %+v, want 3 rows truncated by the row cap", summary)
+	}
+	if want := "DECLARE,FETCH 2,FETCH 1,FETCH 1,CLOSE"; strings.Join(statements, ",") != want {
+		t.Errorf("statements = %v, want %s", statements, want)
+	}
+}
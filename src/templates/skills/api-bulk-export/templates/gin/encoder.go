@@ -0,0 +1,182 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// encoder writes rows in one output format
+type encoder interface {
+	encode(item any) error
+	flush() error
+}
+
+// newEncoder returns the encoder for format, writing rows of T to w
+func newEncoder[T any](w io.Writer, format Format) (encoder, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVEncoder(w, reflect.TypeOf((*T)(nil)).Elem())
+	case FormatNDJSON:
+		return &ndjsonEncoder{w: w, json: json.NewEncoder(w)}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q (use csv or ndjson)", ErrUnsupportedFormat, format)
+	}
+}
+
+// flushWriter flushes w if it buffers
+func flushWriter(w io.Writer) error {
+	switch f := w.(type) {
+	case Flusher:
+		return f.Flush()
+	case http.Flusher:
+		f.Flush()
+	}
+	return nil
+}
+
+// ndjsonEncoder writes one JSON object per line, using the model's json tags
+type ndjsonEncoder struct {
+	w    io.Writer
+	json *json.Encoder
+}
+
+func (e *ndjsonEncoder) encode(item any) error {
+	return e.json.Encode(item)
+}
+
+func (e *ndjsonEncoder) flush() error {
+	return flushWriter(e.w)
+}
+
+// csvColumn is one exported struct field
+type csvColumn struct {
+	header string
+	index  []int
+}
+
+// csvEncoder writes a header row and one record per item
+// Columns are the exported fields of T, named by their csv tag, then json tag, then field name;
+// `csv:"-"` or `json:"-"` leaves a field out
+type csvEncoder struct {
+	w       io.Writer
+	csv     *csv.Writer
+	columns []csvColumn
+	record  []string
+}
+
+func newCSVEncoder(w io.Writer, t reflect.Type) (*csvEncoder, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: csv needs a struct type, got %s", ErrUnsupportedFormat, t)
+	}
+
+	e := &csvEncoder{w: w, csv: csv.NewWriter(w), columns: csvColumns(t, nil)}
+	e.record = make([]string, len(e.columns))
+
+	for i, column := range e.columns {
+		e.record[i] = column.header
+	}
+	if err := e.csv.Write(e.record); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// csvColumns lists the exported fields of t, flattening embedded structs
+func csvColumns(t reflect.Type, index []int) []csvColumn {
+	var columns []csvColumn
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		fieldIndex := append(append([]int{}, index...), i)
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			columns = append(columns, csvColumns(sf.Type, fieldIndex)...)
+			continue
+		}
+
+		header := sf.Name
+		for _, tag := range []string{sf.Tag.Get("csv"), sf.Tag.Get("json")} {
+			name := strings.Split(tag, ",")[0]
+			if name == "-" {
+				header = ""
+				break
+			}
+			if name != "" {
+				header = name
+				break
+			}
+		}
+		if header == "" {
+			continue
+		}
+
+		columns = append(columns, csvColumn{header: header, index: fieldIndex})
+	}
+	return columns
+}
+
+func (e *csvEncoder) encode(item any) error {
+	v := reflect.Indirect(reflect.ValueOf(item))
+	for i, column := range e.columns {
+		e.record[i] = csvValue(v.FieldByIndex(column.index))
+	}
+	return e.csv.Write(e.record)
+}
+
+func (e *csvEncoder) flush() error {
+	e.csv.Flush()
+	if err := e.csv.Error(); err != nil {
+		return err
+	}
+	return flushWriter(e.w)
+}
+
+// csvValue renders a field as a CSV cell
+// Times use RFC 3339, nil pointers are empty, and nested values are JSON
+func csvValue(v reflect.Value) string {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	switch value := v.Interface().(type) {
+	case time.Time:
+		return value.Format(time.RFC3339Nano)
+	case fmt.Stringer:
+		return escapeFormula(value.String())
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return escapeFormula(v.String())
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		raw, err := json.Marshal(v.Interface())
+		if err != nil {
+			return ""
+		}
+		return string(raw)
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// escapeFormula stops spreadsheet apps from evaluating cells that start with a formula character
+// See https://owasp.org/www-community/attacks/CSV_Injection
+func escapeFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,214 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"gorm.io/gorm"
+
+	"{{packageImportPath}}/pagination"
+)
+
+// Format is the output format of an export
+type Format string
+
+const (
+	FormatCSV    Format = "csv"
+	FormatNDJSON Format = "ndjson"
+)
+
+// ErrUnsupportedFormat is returned for formats other than csv and ndjson
+var ErrUnsupportedFormat = errors.New("unsupported export format")
+
+// ErrUnsupportedSort is returned when an export is sorted by anything but its key column
+// Exports walk the table in key order so every batch is an indexed range scan
+var ErrUnsupportedSort = errors.New("exports can only be sorted by their key")
+
+// Truncation reasons reported in Summary.Reason
+const (
+	ReasonRowCap     = "row cap"
+	ReasonTimeBudget = "time budget"
+)
+
+// Config controls how an export walks and bounds the data
+type Config struct {
+	// KeyColumn is the unique integer column batches are keyset-paginated on (default "id")
+	KeyColumn string
+
+	// KeyField is the API name of the key in ?sort= (default "id")
+	KeyField string
+
+	// BatchSize is the number of rows fetched per query (default {{exportBatchSize}})
+	// The pagination skill's maximum page size also applies
+	BatchSize int
+
+	// MaxRows caps the rows in one export (default {{maxExportRows}})
+	MaxRows int64
+
+	// TimeBudget bounds the whole export, including slow clients (default {{exportTimeBudget}}s)
+	TimeBudget time.Duration
+
+	// Filename is the download name without extension (default "export")
+	Filename string
+
+	// DBCursor streams the export from a server-side cursor (DECLARE/FETCH) in one
+	// transaction on Postgres, instead of a keyset query per batch; other dialects ignore it
+	DBCursor bool
+}
+
+// withDefaults fills unset fields
+func (cfg Config) withDefaults() Config {
+	if cfg.KeyColumn == "" {
+		cfg.KeyColumn = "id"
+	}
+	if cfg.KeyField == "" {
+		cfg.KeyField = "id"
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = {{exportBatchSize}}
+	}
+	if cfg.MaxRows <= 0 {
+		cfg.MaxRows = {{maxExportRows}}
+	}
+	if cfg.TimeBudget <= 0 {
+		cfg.TimeBudget = {{exportTimeBudget}} * time.Second
+	}
+	if cfg.Filename == "" {
+		cfg.Filename = "export"
+	}
+	return cfg
+}
+
+// Summary describes a finished export
+type Summary struct {
+	Rows      int64  `json:"rows"`
+	Truncated bool   `json:"truncated"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// Flusher is implemented by buffering writers (gzip streams, multipart uploads)
+// Write flushes once per batch so memory stays bounded and slow readers apply backpressure
+type Flusher interface {
+	Flush() error
+}
+
+// Write streams every row of query to w, walking the table in keyset batches
+// It reuses pagination.CursorPaginateInt for each batch, so filters applied to query are
+// honored and every batch is an indexed range scan. With Config.DBCursor on Postgres the rows
+// come from one server-side cursor instead (see walkDBCursor). Hitting the row cap or the time
+// budget ends the export early with Summary.Truncated set rather than an error.
+//
+// w can be any io.Writer: an HTTP response (see Handler), a file, or the write end of an
+// io.Pipe feeding an S3 upload.
+//
+// Example usage:
+//
+//	// Nightly export to S3 with the aws-sdk-go-v2 upload manager
+//	reader, writer := io.Pipe()
+//	go func() {
+//	    query := db.Model(&Order{}).Where("created_at >= ?", since)
+//	    _, err := export.Write[Order](ctx, query, writer, export.FormatNDJSON, true, export.Config{})
+//	    writer.CloseWithError(err)
+//	}()
+//
+//	_, err := uploader.Upload(ctx, &s3.PutObjectInput{
+//	    Bucket: aws.String("exports"),
+//	    Key:    aws.String("orders.ndjson"),
+//	    Body:   reader,
+//	})
+func Write[T any](
+	ctx context.Context,
+	db *gorm.DB,
+	w io.Writer,
+	format Format,
+	ascending bool,
+	cfg Config,
+) (Summary, error) {
+	cfg = cfg.withDefaults()
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.TimeBudget)
+	defer cancel()
+
+	enc, err := newEncoder[T](w, format)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	var summary Summary
+
+	// Flushing per batch hands rows to the client as they are read and blocks here while a
+	// slow client catches up, so the next batch is not fetched early
+	emit := func(batch []T) error {
+		for i := range batch {
+			if err := enc.encode(&batch[i]); err != nil {
+				return fmt.Errorf("failed to write export row: %w", err)
+			}
+			summary.Rows++
+		}
+		if err := enc.flush(); err != nil {
+			return fmt.Errorf("failed to flush export batch: %w", err)
+		}
+		return nil
+	}
+
+	if cfg.DBCursor && supportsDBCursor(db) {
+		err = walkDBCursor(ctx, db, ascending, cfg, &summary, emit)
+	} else {
+		err = walkKeyset(ctx, db, ascending, cfg, &summary, emit)
+	}
+	if err != nil {
+		return summary, err
+	}
+
+	return summary, enc.flush()
+}
+
+// walkKeyset emits every row of db in keyset batches of CursorPaginateInt pages
+func walkKeyset[T any](ctx context.Context, db *gorm.DB, ascending bool, cfg Config, summary *Summary, emit func(batch []T) error) error {
+	cursor := ""
+	for {
+		batchSize := cfg.BatchSize
+		if remaining := cfg.MaxRows - summary.Rows; remaining < int64(batchSize) {
+			batchSize = int(remaining)
+		}
+
+		var batch []T
+		page, err := pagination.CursorPaginateInt(
+			db.WithContext(ctx), &batch, cursor, batchSize, cfg.KeyColumn, ascending,
+		)
+		if err != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				summary.Truncated, summary.Reason = true, ReasonTimeBudget
+				return nil
+			}
+			return fmt.Errorf("failed to fetch export batch: %w", err)
+		}
+
+		if err := emit(batch); err != nil {
+			return err
+		}
+
+		if !page.HasNext {
+			return nil
+		}
+		if summary.Rows >= cfg.MaxRows {
+			summary.Truncated, summary.Reason = true, ReasonRowCap
+			return nil
+		}
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			summary.Truncated, summary.Reason = true, ReasonTimeBudget
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		cursor, err = pagination.DefaultCursorCodec.Encode(page.LastKey)
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package export
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"{{packageImportPath}}/filtering"
+	"{{packageImportPath}}/sorting"
+)
+
+// Handler returns a download endpoint that streams every row of T matching the request's
+// filter[...] params as CSV or NDJSON
+// Run filtering.ParseFilterParams[T]() and sorting.ParseSortParams[T]() first so the export
+// accepts the same params as the list endpoint. Only sorting by the key (?sort=id or
+// ?sort=-id) is supported; exports walk the table in key order.
+//
+// The format comes from ?format= or the Accept header (text/csv, application/x-ndjson) and
+// defaults to CSV. Responses are gzipped when the client accepts it. Row count and truncation
+// are reported in the X-Export-Rows and X-Export-Truncated trailers, since the status line is
+// sent before the export finishes.
+//
+// Example usage:
+//
+//	r.GET("/orders/export",
+//	    filtering.ParseFilterParams[Order](),
+//	    sorting.ParseSortParams[Order](),
+//	    export.Handler[Order](db, export.Config{MaxRows: 500000}),
+//	)
+//
+//	// curl -H 'Accept-Encoding: gzip' '/orders/export?format=ndjson&filter[status]=paid&sort=-id'
+func Handler[T any](db *gorm.DB, cfg Config) gin.HandlerFunc {
+	cfg = cfg.withDefaults()
+
+	return func(c *gin.Context) {
+		format, err := negotiateFormat(c)
+		if err != nil {
+			AbortWithExportError(c, err)
+			return
+		}
+
+		ascending, err := sortDirection(sorting.GetSortFields(c), cfg)
+		if err != nil {
+			AbortWithExportError(c, err)
+			return
+		}
+
+		query := db.Model(new(T))
+		if filters := filtering.GetFilters(c); len(filters) > 0 {
+			query, err = filtering.ApplyFilters(query, filters)
+			if err != nil {
+				filtering.AbortWithFilterError(c, err)
+				return
+			}
+		}
+
+		header := c.Writer.Header()
+		header.Set("Content-Type", contentTypes[format])
+		header.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", cfg.Filename+"."+string(format)))
+		header.Set("Cache-Control", "no-store")
+		header.Set("Trailer", "X-Export-Rows, X-Export-Truncated")
+
+		out := &responseWriter{c: c}
+		if strings.Contains(c.GetHeader("Accept-Encoding"), "gzip") {
+			header.Set("Content-Encoding", "gzip")
+			header.Add("Vary", "Accept-Encoding")
+			out.gzip = gzip.NewWriter(c.Writer)
+		}
+
+		summary, err := Write[T](c.Request.Context(), query, out, format, ascending, cfg)
+		if err != nil && !c.Writer.Written() {
+			// Nothing has been sent yet, so a normal error response is still possible
+			for _, name := range []string{"Content-Type", "Content-Disposition", "Content-Encoding", "Trailer"} {
+				header.Del(name)
+			}
+			AbortWithExportError(c, err)
+			return
+		}
+
+		if out.gzip != nil {
+			if closeErr := out.gzip.Close(); err == nil {
+				err = closeErr
+			}
+		}
+
+		header.Set("X-Export-Rows", strconv.FormatInt(summary.Rows, 10))
+		header.Set("X-Export-Truncated", summary.Reason)
+
+		if err != nil {
+			// Headers are gone; record the error and let the aborted body signal failure
+			_ = c.Error(err)
+			c.Abort()
+		}
+	}
+}
+
+// contentTypes maps formats to response content types
+var contentTypes = map[Format]string{
+	FormatCSV:    "text/csv; charset=utf-8",
+	FormatNDJSON: "application/x-ndjson",
+}
+
+// negotiateFormat picks the export format from ?format= or the Accept header
+func negotiateFormat(c *gin.Context) (Format, error) {
+	if raw := c.Query("format"); raw != "" {
+		format := Format(strings.ToLower(raw))
+		if _, ok := contentTypes[format]; !ok {
+			return "", fmt.Errorf("%w: %q (use csv or ndjson)", ErrUnsupportedFormat, raw)
+		}
+		return format, nil
+	}
+
+	accept := c.GetHeader("Accept")
+	if strings.Contains(accept, "application/x-ndjson") || strings.Contains(accept, "application/jsonl") {
+		return FormatNDJSON, nil
+	}
+	return FormatCSV, nil
+}
+
+// sortDirection maps ?sort= onto the key direction, rejecting any other sort
+func sortDirection(fields []sorting.SortField, cfg Config) (bool, error) {
+	switch {
+	case len(fields) == 0:
+		return true, nil
+	case len(fields) == 1 && fields[0].Name == cfg.KeyField:
+		return fields[0].Direction != sorting.Desc, nil
+	default:
+		return false, fmt.Errorf("%w (use sort=%s or sort=-%s)", ErrUnsupportedSort, cfg.KeyField, cfg.KeyField)
+	}
+}
+
+// responseWriter writes export output to the response, through gzip when negotiated
+// Flush pushes buffered bytes all the way to the client once per batch
+type responseWriter struct {
+	c    *gin.Context
+	gzip *gzip.Writer
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if w.gzip != nil {
+		return w.gzip.Write(p)
+	}
+	return w.c.Writer.Write(p)
+}
+
+func (w *responseWriter) Flush() error {
+	if w.gzip != nil {
+		if err := w.gzip.Flush(); err != nil {
+			return err
+		}
+	}
+	w.c.Writer.Flush()
+	return nil
+}
+
+// AbortWithExportError responds 400 for unsupported formats and sorts, 500 otherwise
+func AbortWithExportError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrUnsupportedFormat):
+		c.AbortWithStatusJSON(400, gin.H{"error": err.Error(), "param": "format"})
+	case errors.Is(err, ErrUnsupportedSort):
+		c.AbortWithStatusJSON(400, gin.H{"error": err.Error(), "param": "sort"})
+	default:
+		c.AbortWithStatusJSON(500, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,242 @@
+// Package resource wires a model's paginated list, count, and export routes in one call
+// It composes the pagination, filtering, sorting, and export packages the way a hand-written
+// endpoint would; handlers that need more control keep using those packages directly.
+package resource
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"{{packageImportPath}}/export"
+	"{{packageImportPath}}/filtering"
+	"{{packageImportPath}}/pagination"
+	"{{packageImportPath}}/sorting"
+)
+
+// TotalCountHeader carries the matching row count of a HEAD request
+const TotalCountHeader = "X-Total-Count"
+
+// Option customizes a resource registered with RegisterPaginatedResource
+type Option[T any] func(*settings[T])
+
+// settings is a resource's resolved configuration
+type settings[T any] struct {
+	scope      func(c *gin.Context, db *gorm.DB) *gorm.DB
+	serializer func(c *gin.Context, page pagination.PaginatedResponse[T]) any
+	sorts      *sorting.SortSchema
+	filters    *filtering.FilterSchema
+	status     pagination.StatusPolicy
+	options    []pagination.Option
+	export     *export.Config
+}
+
+// WithScope narrows every query of the resource for the request, e.g. to the caller's tenant
+func WithScope[T any](scope func(c *gin.Context, db *gorm.DB) *gorm.DB) Option[T] {
+	return func(s *settings[T]) {
+		s.scope = scope
+	}
+}
+
+// WithSerializer replaces the list response body, e.g. to map models to DTOs or wrap the
+// envelope differently (default: the page's PaginatedResponse)
+func WithSerializer[T any](serialize func(c *gin.Context, page pagination.PaginatedResponse[T]) any) Option[T] {
+	return func(s *settings[T]) {
+		s.serializer = serialize
+	}
+}
+
+// WithSortSchema replaces the sort allowlist derived from T's `sort` tags
+func WithSortSchema[T any](schema *sorting.SortSchema) Option[T] {
+	return func(s *settings[T]) {
+		s.sorts = schema
+	}
+}
+
+// WithFilterSchema replaces the filter allowlist derived from T's `filter` tags
+func WithFilterSchema[T any](schema *filtering.FilterSchema) Option[T] {
+	return func(s *settings[T]) {
+		s.filters = schema
+	}
+}
+
+// WithStatusPolicy sets the status of empty and out-of-range list pages
+func WithStatusPolicy[T any](policy pagination.StatusPolicy) Option[T] {
+	return func(s *settings[T]) {
+		s.status = policy
+	}
+}
+
+// WithPaginationOptions passes opts to every page and count of the resource
+func WithPaginationOptions[T any](opts ...pagination.Option) Option[T] {
+	return func(s *settings[T]) {
+		s.options = append(s.options, opts...)
+	}
+}
+
+// WithExport also registers GET path+"/export", streaming every matching row with cfg
+func WithExport[T any](cfg export.Config) Option[T] {
+	return func(s *settings[T]) {
+		s.export = &cfg
+	}
+}
+
+// RegisterPaginatedResource registers the routes of a paginated collection of T at path
+//   - GET path lists a page: offset pagination with ?page=/?page_size=, ?sort= and
+//     filter[...] validated against T's allowlists, links to the request's path
+//   - HEAD path answers the matching row count in X-Total-Count, without a body
+//   - GET path+"/export" streams every matching row, with WithExport
+//
+// The sort and filter allowlists come from T's `sort` and `filter` struct tags (see
+// sorting.FromStruct and filtering.FromStruct) unless schemas were registered for T already
+// or are given with WithSortSchema/WithFilterSchema; they are registered for T either way.
+//
+// Example usage:
+//
+//	resource.RegisterPaginatedResource[Order](r, "/orders", db,
+//	    resource.WithScope[Order](func(c *gin.Context, db *gorm.DB) *gorm.DB {
+//	        return db.Where("tenant_id = ?", c.GetString("tenant_id"))
+//	    }),
+//	    resource.WithExport[Order](export.Config{MaxRows: 500000}),
+//	)
+//	resource.RegisterPaginatedResource[Product](r, "/products", db)
+func RegisterPaginatedResource[T any](r gin.IRoutes, path string, db *gorm.DB, opts ...Option[T]) {
+	s := &settings[T]{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(s)
+		}
+	}
+
+	if s.sorts == nil {
+		if registered, ok := sorting.SchemaFor[T](); ok {
+			s.sorts = registered
+		} else {
+			s.sorts = sorting.FromStruct[T]()
+		}
+	}
+	if s.filters == nil {
+		if registered, ok := filtering.SchemaFor[T](); ok {
+			s.filters = registered
+		} else {
+			s.filters = filtering.FromStruct[T]()
+		}
+	}
+	sorting.Register[T](s.sorts)
+	filtering.Register[T](s.filters)
+
+	parse := []gin.HandlerFunc{
+		pagination.ParsePaginationParams,
+		filtering.ParseFilterParams[T](),
+		sorting.ParseSortParams[T](),
+	}
+
+	r.GET(path, append(parse, s.listHandler(db))...)
+	r.HEAD(path, append(parse, s.countHandler(db))...)
+	if s.export != nil {
+		cfg := *s.export
+		r.GET(path+"/export", parse[1], parse[2], func(c *gin.Context) {
+			export.Handler[T](s.query(c, db), cfg)(c)
+		})
+	}
+}
+
+// query is db for T narrowed by the resource's scope
+func (s *settings[T]) query(c *gin.Context, db *gorm.DB) *gorm.DB {
+	query := db.Model(new(T))
+	if s.scope != nil {
+		query = s.scope(c, query)
+	}
+	return query
+}
+
+// filtered is the request's query with its filters applied; the error has been answered
+func (s *settings[T]) filtered(c *gin.Context, db *gorm.DB) (*gorm.DB, error) {
+	query := s.query(c, db)
+	filters := filtering.GetFilters(c)
+	if len(filters) == 0 {
+		return query, nil
+	}
+	query, err := filtering.ApplyFiltersWithSchema(query, s.filters, filters)
+	if err != nil {
+		filtering.AbortWithFilterError(c, err)
+		return nil, err
+	}
+	return query, nil
+}
+
+// paginationOptions are the options of the request's page or count
+func (s *settings[T]) paginationOptions(c *gin.Context, params pagination.PaginationParams) []pagination.Option {
+	return append([]pagination.Option{
+		pagination.WithPageIndexing(params.Indexing),
+		pagination.WithContext(c.Request.Context()),
+	}, s.options...)
+}
+
+// listHandler returns the GET handler of the resource's pages
+func (s *settings[T]) listHandler(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query, err := s.filtered(c, db)
+		if err != nil {
+			return
+		}
+		query, err = sorting.ApplySortWithSchema(query, s.sorts, sorting.GetSortFields(c))
+		if err != nil {
+			sorting.AbortWithSortError(c, err)
+			return
+		}
+
+		params := pagination.GetPaginationParams(c)
+		var items []T
+		result, err := pagination.OffsetPaginate(query, &items, params.Page, params.RequestedPageSize(), s.paginationOptions(c, params)...)
+		if err != nil {
+			pagination.AbortWithError(c, pagination.ErrorStatus(err), err)
+			return
+		}
+
+		page := result.ToResponse(c.Request.URL.Path)
+		var body any = page
+		if s.serializer != nil {
+			body = s.serializer(c, page)
+		}
+
+		outcome := pagination.OffsetOutcome(result)
+		switch status := s.status.Status(outcome); {
+		case status == http.StatusNoContent:
+			c.Status(status)
+		case status >= http.StatusBadRequest && outcome == pagination.OutcomeOutOfRange:
+			pagination.AbortWithError(c, status, fmt.Errorf("%w: page %d of %d", pagination.ErrPageOutOfRange, result.CurrentPage, result.TotalPages))
+		case status >= http.StatusBadRequest:
+			pagination.AbortWithError(c, status, pagination.ErrEmptyPage)
+		default:
+			c.JSON(status, body)
+		}
+	}
+}
+
+// countHandler returns the HEAD handler reporting the matching row count
+func (s *settings[T]) countHandler(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query, err := s.filtered(c, db)
+		if err != nil {
+			return
+		}
+
+		params := pagination.GetPaginationParams(c)
+		opts := append(s.paginationOptions(c, params), pagination.AllowZeroPageSize(params.PageSize))
+		var items []T
+		result, err := pagination.OffsetPaginate(query, &items, params.Page, 0, opts...)
+		if err != nil {
+			c.AbortWithStatus(pagination.ErrorStatus(err))
+			return
+		}
+
+		if result.CountMode != pagination.CountSkipped {
+			c.Header(TotalCountHeader, strconv.FormatInt(result.TotalItems, 10))
+		}
+		c.Status(http.StatusOK)
+	}
+}
@@ -0,0 +1,149 @@
+package resource
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"{{packageImportPath}}/pagination"
+)
+
+type order struct {
+	ID     int64  `json:"id" sort:""`
+	Status string `json:"status" sort:"" filter:"eq"`
+}
+
+// orderDB returns a database whose pages are *rows and whose count is *total
+func orderDB(t *testing.T, rows *[]order, total *int64) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Callback().Query().Register("test:orders", func(tx *gorm.DB) {
+		switch dest := tx.Statement.Dest.(type) {
+		case *[]order:
+			*dest = append((*dest)[:0], *rows...)
+		case *int64:
+			*dest = *total
+			tx.RowsAffected = 1
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// serve registers the resource at /orders and returns the response to method on target
+func serve(db *gorm.DB, method, target string, opts ...Option[order]) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	RegisterPaginatedResource[order](r, "/orders", db, opts...)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(method, target, nil))
+	return w
+}
+
+func TestRegisterPaginatedResourceLists(t *testing.T) {
+	rows := []order{
+		{ID: 3, Status: "paid"},
+		{ID: 4, Status: "paid"},
+	}
+	total := int64(6)
+	db := orderDB(t, &rows, &total)
+
+	w := serve(db, http.MethodGet, "/orders?page=2&page_size=2")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body)
+	}
+	var response pagination.PaginatedResponse[order]
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Data) != 2 || *response.Pagination.CurrentPage != 2 || *response.Pagination.TotalPages != 3 {
+		t.Errorf("response %+v", response.Pagination)
+	}
+
+	w = serve(db, http.MethodHead, "/orders")
+	if w.Code != http.StatusOK || w.Header().Get(TotalCountHeader) != "6" || w.Body.Len() != 0 {
+		t.Errorf("HEAD: status %d, count %q, body %q; want 200 and 6 without a body", w.Code, w.Header().Get(TotalCountHeader), w.Body)
+	}
+}
+
+func TestRegisterPaginatedResourceScope(t *testing.T) {
+	rows := []order{
+		{ID: 1, Status: "paid"},
+	}
+	total := int64(1)
+	db := orderDB(t, &rows, &total)
+
+	var tenants []string
+	scope := WithScope[order](func(c *gin.Context, db *gorm.DB) *gorm.DB {
+		tenants = append(tenants, c.GetHeader("X-Tenant"))
+		return db.Where("tenant_id = ?", c.GetHeader("X-Tenant"))
+	})
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	RegisterPaginatedResource[order](r, "/orders", db, scope)
+	for _, method := range []string{http.MethodGet, http.MethodHead} {
+		req := httptest.NewRequest(method, "/orders", nil)
+		req.Header.Set("X-Tenant", "acme")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status %d", method, w.Code)
+		}
+	}
+	if len(tenants) != 2 || tenants[0] != "acme" || tenants[1] != "acme" {
+		t.Errorf("scope saw tenants %v, want acme for the list and the count", tenants)
+	}
+}
+
+func TestRegisterPaginatedResourceSerializer(t *testing.T) {
+	rows := []order{
+		{ID: 7, Status: "paid"},
+	}
+	total := int64(1)
+	db := orderDB(t, &rows, &total)
+
+	serializer := WithSerializer[order](func(c *gin.Context, page pagination.PaginatedResponse[order]) any {
+		ids := make([]int64, len(page.Data))
+		for i, o := range page.Data {
+			ids[i] = o.ID
+		}
+		return gin.H{"ids": ids, "total": *page.Pagination.TotalItems}
+	})
+
+	w := serve(db, http.MethodGet, "/orders", serializer)
+	var body struct {
+		IDs   []int64 `json:"ids"`
+		Total int64   `json:"total"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusOK || len(body.IDs) != 1 || body.IDs[0] != 7 || body.Total != 1 {
+		t.Errorf("serialized body %s (status %d), want the serializer's shape", w.Body, w.Code)
+	}
+}
+
+func TestRegisterPaginatedResourceStatusPolicy(t *testing.T) {
+	var rows []order
+	total := int64(0)
+	db := orderDB(t, &rows, &total)
+
+	policy := WithStatusPolicy[order](pagination.StatusPolicy{Empty: http.StatusNoContent})
+	if w := serve(db, http.MethodGet, "/orders", policy); w.Code != http.StatusNoContent {
+		t.Errorf("empty collection: status %d, want the policy's 204", w.Code)
+	}
+	if w := serve(db, http.MethodGet, "/orders/export"); w.Code != http.StatusNotFound {
+		t.Errorf("export without WithExport: status %d, want 404", w.Code)
+	}
+}
@@ -0,0 +1,90 @@
+package apidocs
+
+import (
+	"reflect"
+
+	"{{packageImportPath}}/pagination"
+	"{{packageImportPath}}/sorting"
+)
+
+// Component names of the pagination parameters registered by this package
+const (
+	PageParam     = "Page"
+	PageSizeParam = "PageSize"
+	CursorParam   = "Cursor"
+)
+
+// The pagination parameters are contributed once, with the parser's real default and
+// maximum page size, and referenced by every list operation
+func init() {
+	var offset, cursor []Parameter
+	if err := Convert(pagination.OpenAPIParameters(false), &offset); err != nil {
+		panic("apidocs: " + err.Error())
+	}
+	if err := Convert(pagination.OpenAPIParameters(true), &cursor); err != nil {
+		panic("apidocs: " + err.Error())
+	}
+
+	Default.AddParameter(PageParam, offset[0])
+	Default.AddParameter(PageSizeParam, offset[1])
+	Default.AddParameter(CursorParam, cursor[0])
+}
+
+// SortParameter documents the sort parameter of M's registered sorting schema
+// ok is false when M has no schema (its list endpoint does not accept ?{{sortParam}}=)
+func SortParameter[M any]() (param Parameter, ok bool) {
+	schema, ok := sorting.SchemaFor[M]()
+	if !ok {
+		return Parameter{}, false
+	}
+	if err := Convert(schema.OpenAPIParameter(), &param); err != nil {
+		return Parameter{}, false
+	}
+	return param, true
+}
+
+// ListOperation documents a paginated list of M
+// The operation references the pagination parameters (cursor or offset style), M's sort
+// parameter when a sorting schema is registered, and a page response whose items reference
+// the component schema named after M (contribute it with AddSchema). extra parameters, such
+// as filters, are appended.
+//
+// Example usage:
+//
+//	func init() {
+//	    apidocs.Default.AddSchema("User", apidocs.Schema{"type": "object", "properties": ...})
+//	    apidocs.Default.MustAddOperation("GET", "/users", apidocs.ListOperation[User]("listUsers", true))
+//	}
+func ListOperation[M any](operationID string, cursor bool, extra ...Parameter) Operation {
+	name := reflect.TypeOf((*M)(nil)).Elem().Name()
+
+	params := []Parameter{ParameterRef(PageSizeParam)}
+	if cursor {
+		params = append([]Parameter{ParameterRef(CursorParam)}, params...)
+	} else {
+		params = append([]Parameter{ParameterRef(PageParam)}, params...)
+	}
+	if sort, ok := SortParameter[M](); ok {
+		params = append(params, sort)
+	}
+	params = append(params, extra...)
+
+	var page Schema
+	items := pagination.OpenAPISchema{Ref: "#/components/schemas/" + name}
+	if err := Convert(pagination.OpenAPIPageSchema(cursor, items), &page); err != nil {
+		panic("apidocs: " + err.Error())
+	}
+
+	return Operation{
+		OperationID: operationID,
+		Summary:     "List " + name + " records",
+		Parameters:  params,
+		Responses: map[string]Response{
+			"200": {
+				Description: "A page of " + name + " records",
+				Content:     map[string]MediaType{"application/json": {Schema: page}},
+			},
+			"400": {Description: "Invalid pagination, sort, or filter parameters"},
+		},
+	}
+}
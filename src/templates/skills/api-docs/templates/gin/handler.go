@@ -0,0 +1,66 @@
+package apidocs
+
+import (
+	"html/template"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SpecHandler serves the registry's assembled OpenAPI document as JSON
+func SpecHandler(r *Registry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		data, err := r.JSON()
+		if err != nil {
+			c.AbortWithStatusJSON(500, gin.H{"error": err.Error()})
+			return
+		}
+		c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+	}
+}
+
+// UIEnabled reports whether the documentation UIs are served
+// They are compiled in with the apidocs_ui build tag (go build -tags apidocs_ui) or enabled at
+// runtime with {{uiEnvVar}}=true, so production builds do not expose them by default.
+func UIEnabled() bool {
+	if uiBuildTag {
+		return true
+	}
+	switch strings.ToLower(os.Getenv("{{uiEnvVar}}")) {
+	case "1", "true", "yes":
+		return true
+	}
+	return false
+}
+
+// Mount serves the document at {{docsPath}}/openapi.json and, when UIEnabled, Swagger UI at
+// {{docsPath}} and Redoc at {{docsPath}}/redoc
+//
+// Example usage:
+//
+//	r := gin.Default()
+//	apidocs.Mount(r, apidocs.Default)
+//	// go run -tags apidocs_ui . (or {{uiEnvVar}}=true) to browse http://localhost:8080{{docsPath}}
+func Mount(router gin.IRoutes, r *Registry) {
+	specURL := "{{docsPath}}/openapi.json"
+	router.GET(specURL, SpecHandler(r))
+
+	if !UIEnabled() {
+		return
+	}
+	router.GET("{{docsPath}}", uiHandler(swaggerUIPage, specURL))
+	router.GET("{{docsPath}}/redoc", uiHandler(redocPage, specURL))
+}
+
+// uiHandler renders a documentation page pointing at specURL
+func uiHandler(page *template.Template, specURL string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Content-Type", "text/html; charset=utf-8")
+		c.Status(http.StatusOK)
+		if err := page.Execute(c.Writer, map[string]string{"SpecURL": specURL}); err != nil {
+			_ = c.Error(err)
+		}
+	}
+}
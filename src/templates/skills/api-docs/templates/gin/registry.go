@@ -0,0 +1,220 @@
+package apidocs
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Schema is an OpenAPI 3 schema object
+// It is kept as a plain map so schemas from any package (and hand-written ones) fit
+type Schema map[string]any
+
+// Parameter is an OpenAPI 3 parameter object, or a reference to one ({"$ref": ...})
+type Parameter struct {
+	Ref         string `json:"$ref,omitempty"`
+	Name        string `json:"name,omitempty"`
+	In          string `json:"in,omitempty"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	Schema      Schema `json:"schema,omitempty"`
+}
+
+// Response is an OpenAPI 3 response object with a JSON body
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType is an OpenAPI 3 media type object
+type MediaType struct {
+	Schema Schema `json:"schema"`
+}
+
+// RequestBody is an OpenAPI 3 request body object
+type RequestBody struct {
+	Description string               `json:"description,omitempty"`
+	Required    bool                 `json:"required,omitempty"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+// Operation is an OpenAPI 3 operation object
+type Operation struct {
+	OperationID string              `json:"operationId,omitempty"`
+	Summary     string              `json:"summary,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Tags        []string            `json:"tags,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+// Info is the OpenAPI info object
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Components holds the reusable schemas and parameters of a Document
+type Components struct {
+	Schemas    map[string]Schema    `json:"schemas,omitempty"`
+	Parameters map[string]Parameter `json:"parameters,omitempty"`
+}
+
+// Document is an OpenAPI 3 document
+type Document struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       Info                             `json:"info"`
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components Components                       `json:"components"`
+}
+
+// Registry assembles an OpenAPI document from contributions
+// Skills contribute components (pagination parameters, error schemas, ...) and generated
+// handlers contribute their operations, typically from init functions, so the served
+// document always matches the code that is compiled in.
+type Registry struct {
+	mu  sync.RWMutex
+	doc Document
+}
+
+// NewRegistry creates an empty Registry
+func NewRegistry(info Info) *Registry {
+	return &Registry{doc: Document{
+		OpenAPI: "3.0.3",
+		Info:    info,
+		Paths:   make(map[string]map[string]*Operation),
+		Components: Components{
+			Schemas:    make(map[string]Schema),
+			Parameters: make(map[string]Parameter),
+		},
+	}}
+}
+
+// Default is the registry skills and generated handlers contribute to
+var Default = NewRegistry(Info{Title: "{{apiTitle}}", Version: "{{apiVersion}}"})
+
+// AddSchema contributes a component schema, referenced as SchemaRef(name)
+func (r *Registry) AddSchema(name string, schema Schema) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.doc.Components.Schemas[name] = schema
+}
+
+// AddParameter contributes a component parameter, referenced as ParameterRef(name)
+func (r *Registry) AddParameter(name string, param Parameter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.doc.Components.Parameters[name] = param
+}
+
+// ginParam matches gin path parameters (":id" and "*path")
+var ginParam = regexp.MustCompile(`[:*]([A-Za-z0-9_]+)`)
+
+// AddOperation contributes the operation of a route
+// Gin paths are converted to OpenAPI templates ("/users/:id" becomes "/users/{id}") and
+// undocumented path parameters are added as required strings; registering the same method
+// and path twice is an error.
+//
+// Example usage:
+//
+//	func init() {
+//	    apidocs.Default.MustAddOperation("GET", "/users", apidocs.ListOperation[User]("listUsers", true))
+//	}
+func (r *Registry) AddOperation(method, path string, op Operation) error {
+	op.Parameters = withPathParameters(path, op.Parameters)
+	path = ginParam.ReplaceAllString(path, "{$1}")
+	method = strings.ToLower(method)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item := r.doc.Paths[path]
+	if item == nil {
+		item = make(map[string]*Operation)
+		r.doc.Paths[path] = item
+	}
+	if _, exists := item[method]; exists {
+		return fmt.Errorf("operation %s %s is already documented", strings.ToUpper(method), path)
+	}
+	if op.Responses == nil {
+		op.Responses = map[string]Response{"200": {Description: "OK"}}
+	}
+	item[method] = &op
+	return nil
+}
+
+// withPathParameters prepends a parameter for every path parameter params does not document
+func withPathParameters(path string, params []Parameter) []Parameter {
+	documented := make(map[string]bool, len(params))
+	for _, param := range params {
+		if param.In == "path" {
+			documented[param.Name] = true
+		}
+	}
+
+	var missing []Parameter
+	for _, match := range ginParam.FindAllStringSubmatch(path, -1) {
+		if !documented[match[1]] {
+			missing = append(missing, Parameter{
+				Name:     match[1],
+				In:       "path",
+				Required: true,
+				Schema:   Schema{"type": "string"},
+			})
+		}
+	}
+	return append(missing, params...)
+}
+
+// MustAddOperation is AddOperation for init functions; it panics on duplicates
+func (r *Registry) MustAddOperation(method, path string, op Operation) {
+	if err := r.AddOperation(method, path, op); err != nil {
+		panic("apidocs: " + err.Error())
+	}
+}
+
+// JSON renders the assembled document
+func (r *Registry) JSON() ([]byte, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	data, err := json.MarshalIndent(r.doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to render OpenAPI document: %w", err)
+	}
+	return data, nil
+}
+
+// SchemaRef references a component schema
+func SchemaRef(name string) Schema {
+	return Schema{"$ref": "#/components/schemas/" + name}
+}
+
+// ParameterRef references a component parameter
+func ParameterRef(name string) Parameter {
+	return Parameter{Ref: "#/components/parameters/" + name}
+}
+
+// Convert re-decodes an OpenAPI value from another package (such as a filtering or
+// pagination OpenAPIParameter, or a slice of them) into this package's types
+//
+// Example usage:
+//
+//	var params []apidocs.Parameter
+//	if err := apidocs.Convert(filterSchema.OpenAPIParameters(), &params); err != nil {
+//	    return err
+//	}
+func Convert(from any, to any) error {
+	data, err := json.Marshal(from)
+	if err != nil {
+		return fmt.Errorf("failed to convert OpenAPI value: %w", err)
+	}
+	if err := json.Unmarshal(data, to); err != nil {
+		return fmt.Errorf("failed to convert OpenAPI value: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package apidocs
+
+import "html/template"
+
+// The pages load Swagger UI and Redoc from jsDelivr; pin the versions here (or serve
+// vendored copies with embed) for reproducible, offline docs.
+// Custom delimiters keep Go's template syntax clear of the skill's own templating.
+
+var swaggerUIPage = template.Must(template.New("swagger-ui").Delims("[[", "]]").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>{{apiTitle}} - Swagger UI</title>
+  <link rel="stylesheet" href="https://cdn.jsdelivr.net/npm/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://cdn.jsdelivr.net/npm/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "[[.SpecURL]]", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`))
+
+var redocPage = template.Must(template.New("redoc").Delims("[[", "]]").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>{{apiTitle}} - Redoc</title>
+</head>
+<body>
+  <redoc spec-url="[[.SpecURL]]"></redoc>
+  <script src="https://cdn.jsdelivr.net/npm/redoc@2/bundles/redoc.standalone.js"></script>
+</body>
+</html>
+`))
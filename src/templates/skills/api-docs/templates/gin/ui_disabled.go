@@ -0,0 +1,6 @@
+//go:build !apidocs_ui
+
+package apidocs
+
+// uiBuildTag is true in builds with -tags apidocs_ui
+const uiBuildTag = false
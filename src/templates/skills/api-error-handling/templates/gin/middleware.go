@@ -0,0 +1,72 @@
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Middleware renders the last error a handler attached with c.Error as a problem
+// Handlers report failures with c.Error(err) and return; responses already written are
+// left alone.
+//
+// Example usage:
+//
+//	r := gin.Default()
+//	r.Use(problem.Middleware())
+//
+//	func GetUsers(c *gin.Context) {
+//	    result, err := pagination.CursorPaginateInt(db, &users, cursor, 20, "id", true)
+//	    if err != nil {
+//	        _ = c.Error(err) // ErrInvalidCursor renders as a 400 invalid-cursor problem
+//	        return
+//	    }
+//	    c.JSON(200, result)
+//	}
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		Render(c, From(c.Errors.Last().Err))
+	}
+}
+
+// Abort stops the handler chain and renders err as a problem
+func Abort(c *gin.Context, err error) {
+	c.Abort()
+	Render(c, From(err))
+}
+
+// AbortStatus is Abort with the status used when err is not a registered problem
+func AbortStatus(c *gin.Context, status int, err error) {
+	c.Abort()
+	Render(c, FromStatus(err, status))
+}
+
+// Render writes p as application/problem+json, or as application/json for clients that
+// accept JSON but not problem+json
+// The request path is used as the instance when p has none.
+func Render(c *gin.Context, p *Problem) {
+	if p.Instance == "" && c.Request != nil && c.Request.URL != nil {
+		p.Instance = c.Request.URL.Path
+	}
+	if p.Status == 0 {
+		p.Status = http.StatusInternalServerError
+	}
+
+	body, err := json.Marshal(p)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	contentType := MediaType
+	if c.NegotiateFormat(MediaType, gin.MIMEJSON) == gin.MIMEJSON {
+		contentType = gin.MIMEJSON
+	}
+	c.Data(p.Status, contentType+"; charset=utf-8", body)
+}
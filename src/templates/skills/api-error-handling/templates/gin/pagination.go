@@ -0,0 +1,92 @@
+package problem
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"{{packageImportPath}}/pagination"
+)
+
+// Problem types of the pagination errors
+var (
+	TypeInvalidCursor      = TypeURI("invalid-cursor")
+	TypePageOutOfRange     = TypeURI("page-out-of-range")
+	TypeCursorFieldChanged = TypeURI("cursor-field-changed")
+	TypeInvalidPageToken   = TypeURI("invalid-page-token")
+	TypePageTokenChanged   = TypeURI("page-token-changed")
+	TypeSnapshotExpired    = TypeURI("snapshot-expired")
+	TypeTooBusy            = TypeURI("too-busy")
+	TypeResultTooLarge     = TypeURI("result-too-large")
+	TypeMaxPagesReached    = TypeURI("max-pages-reached")
+	TypeSortNotAllowed     = TypeURI("sort-not-allowed")
+)
+
+// The pagination sentinels are registered once, and pagination's own middleware is routed
+// through Render so its 400s use the same format as every other error
+func init() {
+	Register(pagination.ErrInvalidCursor, Problem{
+		Type:       TypeInvalidCursor,
+		Title:      "Invalid cursor",
+		Status:     400,
+		Extensions: map[string]any{"param": "cursor"},
+	})
+	Register(pagination.ErrCursorFieldMismatch, Problem{
+		Type:       TypeCursorFieldChanged,
+		Title:      "Cursor no longer valid",
+		Status:     400,
+		Extensions: map[string]any{"param": "cursor"},
+	})
+	Register(pagination.ErrCursorFieldNotAllowed, Problem{
+		Type:       TypeSortNotAllowed,
+		Title:      "Sort field not allowed",
+		Status:     400,
+		Extensions: map[string]any{"param": "sort"},
+	})
+	Register(pagination.ErrInvalidPageToken, Problem{
+		Type:       TypeInvalidPageToken,
+		Title:      "Invalid page token",
+		Status:     400,
+		Extensions: map[string]any{"param": "page_token"},
+	})
+	Register(pagination.ErrPageTokenMismatch, Problem{
+		Type:       TypePageTokenChanged,
+		Title:      "Filters or sort changed; restart from the first page",
+		Status:     400,
+		Extensions: map[string]any{"param": "page_token"},
+	})
+	Register(pagination.ErrPageOutOfRange, Problem{
+		Type:       TypePageOutOfRange,
+		Title:      "Page out of range",
+		Status:     404,
+		Extensions: map[string]any{"param": "page"},
+	})
+	Register(pagination.ErrSnapshotExpired, Problem{
+		Type:       TypeSnapshotExpired,
+		Title:      "Pagination snapshot expired",
+		Status:     410,
+		Extensions: map[string]any{"param": "cursor"},
+	})
+	Register(pagination.ErrResultTooLarge, Problem{
+		Type:       TypeResultTooLarge,
+		Title:      "Too many rows to return at once",
+		Status:     422,
+		Extensions: map[string]any{"param": "page_size"},
+	})
+	Register(pagination.ErrMaxPagesReached, Problem{
+		Type:       TypeMaxPagesReached,
+		Title:      "Too many pages requested in one session",
+		Status:     429,
+		Extensions: map[string]any{"param": "cursor"},
+	})
+	Register(pagination.ErrTooBusy, Problem{
+		Type:   TypeTooBusy,
+		Title:  "Too many concurrent list requests",
+		Status: 503,
+	})
+
+	pagination.AbortWithError = func(c *gin.Context, status int, err error) {
+		AbortStatus(c, status, err)
+	}
+	pagination.ErrorBody = func(status int, err error) any {
+		return FromStatus(err, status)
+	}
+}
@@ -0,0 +1,143 @@
+package problem
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// MediaType is the RFC 7807 content type
+const MediaType = "application/problem+json"
+
+// TypeURI builds a problem type URI under {{typeBaseURI}}
+// Type URIs identify the kind of problem; clients switch on them, so never rename one.
+func TypeURI(slug string) string {
+	return "{{typeBaseURI}}" + slug
+}
+
+// Problem is an RFC 7807 problem details object
+// Extensions are additional members serialized next to the standard ones, e.g. "param" or
+// "fields"; they cannot override the standard members.
+//
+//	{
+//	  "type":     "{{typeBaseURI}}invalid-cursor",
+//	  "title":    "Invalid cursor",
+//	  "status":   400,
+//	  "detail":   "invalid cursor: illegal base64 data at input byte 4",
+//	  "instance": "/users",
+//	  "param":    "cursor"
+//	}
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Extensions map[string]any
+
+	// cause is the wrapped error, so errors.Is and errors.As see through the problem
+	cause error
+}
+
+// New creates a problem with a type URI (see TypeURI), title, and status
+func New(status int, typeURI, title string) *Problem {
+	return &Problem{Type: typeURI, Title: title, Status: status}
+}
+
+// Error implements the error interface
+func (p *Problem) Error() string {
+	if p.Detail != "" {
+		return fmt.Sprintf("%s: %s", p.Title, p.Detail)
+	}
+	return p.Title
+}
+
+// Unwrap returns the error the problem was built from, if any
+func (p *Problem) Unwrap() error {
+	return p.cause
+}
+
+// With sets an extension member and returns p for chaining
+func (p *Problem) With(key string, value any) *Problem {
+	if p.Extensions == nil {
+		p.Extensions = make(map[string]any)
+	}
+	p.Extensions[key] = value
+	return p
+}
+
+// clone copies p, including its extensions, so templates are never mutated
+func (p Problem) clone() *Problem {
+	if p.Extensions != nil {
+		extensions := make(map[string]any, len(p.Extensions))
+		for key, value := range p.Extensions {
+			extensions[key] = value
+		}
+		p.Extensions = extensions
+	}
+	return &p
+}
+
+// standardMembers are the RFC 7807 members an extension may not replace
+var standardMembers = map[string]bool{"type": true, "title": true, "status": true, "detail": true, "instance": true}
+
+// MarshalJSON renders the standard members followed by the extensions
+// An empty type is rendered as "about:blank", as RFC 7807 specifies.
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	members := make(map[string]any, len(p.Extensions)+5)
+	for key, value := range p.Extensions {
+		if !standardMembers[key] {
+			members[key] = value
+		}
+	}
+
+	members["type"] = p.Type
+	if p.Type == "" {
+		members["type"] = "about:blank"
+	}
+	members["title"] = p.Title
+	if p.Title == "" {
+		members["title"] = http.StatusText(p.Status)
+	}
+	members["status"] = p.Status
+	if p.Detail != "" {
+		members["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		members["instance"] = p.Instance
+	}
+
+	return json.Marshal(members)
+}
+
+// UnmarshalJSON reads a problem, collecting unknown members into Extensions
+// Useful in tests and in clients of other services that return problem+json.
+func (p *Problem) UnmarshalJSON(data []byte) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+
+	targets := map[string]any{
+		"type":     &p.Type,
+		"title":    &p.Title,
+		"status":   &p.Status,
+		"detail":   &p.Detail,
+		"instance": &p.Instance,
+	}
+	for key, raw := range members {
+		if target, ok := targets[key]; ok {
+			if err := json.Unmarshal(raw, target); err != nil {
+				return fmt.Errorf("invalid problem member %q: %w", key, err)
+			}
+			continue
+		}
+
+		var value any
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return err
+		}
+		p.With(key, value)
+	}
+	return nil
+}
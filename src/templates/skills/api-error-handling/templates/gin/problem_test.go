@@ -0,0 +1,110 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"{{packageImportPath}}/pagination"
+)
+
+func TestFromPaginationSentinels(t *testing.T) {
+	cases := []struct {
+		err    error
+		status int
+		typ    string
+	}{
+		{fmt.Errorf("%w: illegal base64 data", pagination.ErrInvalidCursor), 400, TypeInvalidCursor},
+		{fmt.Errorf("%w: page 9 of 3", pagination.ErrPageOutOfRange), 404, TypePageOutOfRange},
+		{pagination.ErrSnapshotExpired, 410, TypeSnapshotExpired},
+	}
+
+	for _, tc := range cases {
+		p := From(tc.err)
+		if p.Status != tc.status || p.Type != tc.typ {
+			t.Errorf("%v: got %d %s", tc.err, p.Status, p.Type)
+		}
+		if p.Detail != tc.err.Error() {
+			t.Errorf("%v: Detail = %q", tc.err, p.Detail)
+		}
+		if !errors.Is(p, tc.err) {
+			t.Errorf("%v: problem should unwrap to the original error", tc.err)
+		}
+	}
+
+	// Registered templates are copied, never mutated
+	From(pagination.ErrInvalidCursor).With("param", "changed")
+	if From(pagination.ErrInvalidCursor).Extensions["param"] != "cursor" {
+		t.Error("template extensions were mutated")
+	}
+}
+
+func TestFromHidesInternalDetail(t *testing.T) {
+	p := From(errors.New("pq: connection refused to 10.0.0.5"))
+	if p.Status != 500 || p.Detail != "" {
+		t.Errorf("got %d with detail %q", p.Status, p.Detail)
+	}
+}
+
+type codedError struct{}
+
+func (codedError) Error() string   { return "2 fields are invalid" }
+func (codedError) StatusCode() int { return 400 }
+func (codedError) ProblemExtensions() map[string]any {
+	return map[string]any{"fields": []string{"a", "b"}}
+}
+
+func TestFromStatusCoder(t *testing.T) {
+	p := From(fmt.Errorf("binding: %w", codedError{}))
+	if p.Status != 400 || p.Title != "Bad Request" || p.Extensions["fields"] == nil {
+		t.Errorf("unexpected problem: %+v", p)
+	}
+}
+
+func TestWrapKeepsChain(t *testing.T) {
+	notFound := errors.New("record not found")
+	err := fmt.Errorf("loading user: %w", Wrap(notFound, 404, TypeURI("not-found"), "User not found"))
+
+	if !errors.Is(err, notFound) {
+		t.Error("wrapped error should still match the original")
+	}
+	p := From(err)
+	if p.Status != 404 || p.Title != "User not found" || p.Detail != "record not found" {
+		t.Errorf("unexpected problem: %+v", p)
+	}
+}
+
+func TestProblemJSONRoundTrip(t *testing.T) {
+	p := New(400, TypeInvalidCursor, "Invalid cursor").With("param", "cursor").With("status", 999)
+	p.Instance = "/users"
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var members map[string]any
+	if err := json.Unmarshal(data, &members); err != nil {
+		t.Fatal(err)
+	}
+	if members["status"] != float64(400) || members["param"] != "cursor" || members["instance"] != "/users" {
+		t.Errorf("unexpected members: %s", data)
+	}
+	if _, ok := members["detail"]; ok {
+		t.Errorf("empty detail should be omitted: %s", data)
+	}
+
+	var decoded Problem
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Type != TypeInvalidCursor || decoded.Status != 400 || decoded.Extensions["param"] != "cursor" {
+		t.Errorf("decoded = %+v", decoded)
+	}
+
+	blank, _ := json.Marshal(&Problem{Status: 503})
+	if string(blank) != `{"status":503,"title":"Service Unavailable","type":"about:blank"}` {
+		t.Errorf("blank problem = %s", blank)
+	}
+}
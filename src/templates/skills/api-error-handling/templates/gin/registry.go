@@ -0,0 +1,144 @@
+package problem
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+)
+
+// entry maps errors to problems
+type entry struct {
+	match func(err error) (*Problem, bool)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   []entry
+)
+
+// Register maps a sentinel error (matched with errors.Is) to a problem template
+// The problem's detail is the error's message for 4xx statuses; 5xx details are omitted so
+// internal messages never reach clients.
+//
+// Example usage:
+//
+//	var ErrOrderLocked = errors.New("order is locked for fulfilment")
+//
+//	func init() {
+//	    problem.Register(ErrOrderLocked, problem.Problem{
+//	        Type:   problem.TypeURI("order-locked"),
+//	        Title:  "Order locked",
+//	        Status: 409,
+//	    })
+//	}
+func Register(target error, template Problem) {
+	add(entry{match: func(err error) (*Problem, bool) {
+		if !errors.Is(err, target) {
+			return nil, false
+		}
+		return withDetail(template.clone(), err), true
+	}})
+}
+
+// RegisterType maps every error of type E (matched with errors.As) to the problem built by fn
+// Use it for error types that carry fields worth exposing as extension members.
+//
+// Example usage:
+//
+//	problem.RegisterType(func(err *sorting.SortError) *problem.Problem {
+//	    return problem.New(400, problem.TypeURI("invalid-sort"), "Invalid sort").
+//	        With("field", err.Field).
+//	        With("reason", err.Reason)
+//	})
+func RegisterType[E error](fn func(E) *Problem) {
+	add(entry{match: func(err error) (*Problem, bool) {
+		var target E
+		if !errors.As(err, &target) {
+			return nil, false
+		}
+		p := fn(target)
+		if p == nil {
+			return nil, false
+		}
+		return withDetail(p, err), true
+	}})
+}
+
+func add(e entry) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry = append(registry, e)
+}
+
+// statusCoder is implemented by errors that know their HTTP status (e.g. validation.Error)
+type statusCoder interface {
+	StatusCode() int
+}
+
+// extender is implemented by errors that contribute problem extension members
+type extender interface {
+	ProblemExtensions() map[string]any
+}
+
+// From converts err to a problem
+// In order: a *Problem anywhere in the chain (see Wrap), then registered errors in
+// registration order, then errors implementing StatusCode() int (with ProblemExtensions()
+// map[string]any for extension members), and finally a 500 without detail.
+func From(err error) *Problem {
+	return FromStatus(err, http.StatusInternalServerError)
+}
+
+// FromStatus is From with the status used for unrecognized errors
+func FromStatus(err error, status int) *Problem {
+	var p *Problem
+	if errors.As(err, &p) {
+		return p.clone()
+	}
+
+	registryMu.RLock()
+	entries := registry
+	registryMu.RUnlock()
+	for _, e := range entries {
+		if p, ok := e.match(err); ok {
+			return p
+		}
+	}
+
+	var coder statusCoder
+	if errors.As(err, &coder) {
+		status = coder.StatusCode()
+	}
+	p = withDetail(&Problem{Status: status, Title: http.StatusText(status)}, err)
+	var ext extender
+	if errors.As(err, &ext) {
+		for key, value := range ext.ProblemExtensions() {
+			p.With(key, value)
+		}
+	}
+	return p
+}
+
+// withDetail fills in the client-safe detail and the cause
+func withDetail(p *Problem, err error) *Problem {
+	if p.Detail == "" && p.Status < 500 {
+		p.Detail = err.Error()
+	}
+	p.cause = err
+	return p
+}
+
+// Wrap attaches a problem to an arbitrary error
+// The result still matches the original with errors.Is and errors.As, and renders as the
+// given problem (with err's message as the detail for 4xx statuses).
+//
+// Example usage:
+//
+//	if errors.Is(err, gorm.ErrRecordNotFound) {
+//	    return problem.Wrap(err, 404, problem.TypeURI("not-found"), "User not found")
+//	}
+func Wrap(err error, status int, typeURI, title string) error {
+	if err == nil {
+		return nil
+	}
+	return withDetail(New(status, typeURI, title), err)
+}
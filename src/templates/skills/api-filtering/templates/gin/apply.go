@@ -0,0 +1,91 @@
+package filtering
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// likeEscaper escapes LIKE wildcards so user input only ever matches literally
+// "!" is used as the escape character because backslash escaping differs between MySQL and Postgres
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// ApplyFilters adds parameterized WHERE clauses for filters using the schema registered for
+// the query's model (db.Model(&Product{}) must be set). Every value is bound as a parameter;
+// columns come only from the allowlist, never from the request.
+//
+// Example usage:
+//
+//	func ListProducts(c *gin.Context) {
+//	    query, err := filtering.ApplyFilters(db.Model(&Product{}), filtering.GetFilters(c))
+//	    if err != nil {
+//	        filtering.AbortWithFilterError(c, err)
+//	        return
+//	    }
+//
+//	    var products []Product
+//	    result, err := pagination.OffsetPaginate(query, &products, pagination.GetPage(c), pagination.GetPageSize(c))
+//	    // ...
+//	}
+func ApplyFilters(db *gorm.DB, filters []Filter) (*gorm.DB, error) {
+	var model interface{}
+	if db.Statement != nil {
+		model = db.Statement.Model
+	}
+
+	schema, ok := lookupSchema(model)
+	if !ok {
+		return nil, fmt.Errorf("no filter schema registered for model %T (call filtering.Register first)", model)
+	}
+
+	return ApplyFiltersWithSchema(db, schema, filters)
+}
+
+// ApplyFiltersWithSchema is ApplyFilters with an explicit schema (for Raw/Table queries without a model)
+func ApplyFiltersWithSchema(db *gorm.DB, schema *FilterSchema, filters []Filter) (*gorm.DB, error) {
+	query := db
+
+	for _, filter := range filters {
+		resolved, err := schema.resolve(filter)
+		if err != nil {
+			return nil, err
+		}
+
+		query = applyCondition(query, resolved)
+	}
+
+	return query, nil
+}
+
+// applyCondition adds a single validated condition
+func applyCondition(query *gorm.DB, f resolvedFilter) *gorm.DB {
+	column := f.field.Column
+
+	switch f.op {
+	case OpNe:
+		return query.Where(fmt.Sprintf("%s <> ?", column), f.values[0])
+	case OpGt:
+		return query.Where(fmt.Sprintf("%s > ?", column), f.values[0])
+	case OpGte:
+		return query.Where(fmt.Sprintf("%s >= ?", column), f.values[0])
+	case OpLt:
+		return query.Where(fmt.Sprintf("%s < ?", column), f.values[0])
+	case OpLte:
+		return query.Where(fmt.Sprintf("%s <= ?", column), f.values[0])
+	case OpIn:
+		return query.Where(fmt.Sprintf("%s IN ?", column), f.values)
+	case OpBetween:
+		return query.Where(fmt.Sprintf("%s BETWEEN ? AND ?", column), f.values[0], f.values[1])
+	case OpLike:
+		pattern := "%" + likeEscaper.Replace(f.values[0].(string)) + "%"
+		return query.Where(fmt.Sprintf("%s LIKE ? ESCAPE '!'", column), pattern)
+	case OpIsNull:
+		if f.values[0].(bool) {
+			return query.Where(fmt.Sprintf("%s IS NULL", column))
+		}
+		return query.Where(fmt.Sprintf("%s IS NOT NULL", column))
+	default:
+		return query.Where(fmt.Sprintf("%s = ?", column), f.values[0])
+	}
+}
@@ -0,0 +1,102 @@
+package filtering
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"{{packageImportPath}}/pagination"
+)
+
+// ErrFiltersChanged is returned when a cursor is replayed with different filters
+// Cursors point into one filtered result set; reusing them with other filters would skip or repeat rows
+var ErrFiltersChanged = errors.New("cursor was issued for different filters")
+
+// FilterHash returns a short, stable fingerprint of a filter set
+// ParseFilters returns filters in canonical order, so equal queries hash identically
+func FilterHash(filters []Filter) string {
+	h := sha256.New()
+	for _, filter := range filters {
+		h.Write([]byte(filter.String()))
+		h.Write([]byte{0})
+	}
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil)[:9])
+}
+
+// FilterChangePolicy decides what a filter-bound cursor does when the filters changed
+type FilterChangePolicy int
+
+const (
+	// RejectChangedFilters fails the request with ErrFiltersChanged (the default)
+	RejectChangedFilters FilterChangePolicy = iota
+
+	// RestartOnChangedFilters serves the first page of the new filters instead; the page
+	// reports restarted so clients can reset their list
+	RestartOnChangedFilters
+)
+
+// filterBoundCodec prefixes cursors with the filter hash they were issued for
+type filterBoundCodec struct {
+	base   pagination.CursorCodec
+	hash   string
+	policy FilterChangePolicy
+}
+
+// CursorCodec binds pagination cursors to the given filters
+// base is the codec being wrapped (nil = pagination.DefaultCursorCodec). Decoding a cursor that
+// was issued for different filters fails with ErrFiltersChanged.
+//
+// Example usage:
+//
+//	filters := filtering.GetFilters(c)
+//	query, err := filtering.ApplyFilters(db.Model(&Product{}), filters)
+//	// ...
+//	result, err := pagination.CursorPaginateInt(query, &products, pagination.GetCursor(c), 20, "id", true,
+//	    pagination.WithCursorCodec(filtering.CursorCodec(filters, nil)),
+//	)
+//	if errors.Is(err, filtering.ErrFiltersChanged) {
+//	    c.JSON(400, gin.H{"error": "filters changed; restart pagination without a cursor"})
+//	    return
+//	}
+func CursorCodec(filters []Filter, base pagination.CursorCodec) pagination.CursorCodec {
+	return CursorCodecWithPolicy(filters, base, RejectChangedFilters)
+}
+
+// CursorCodecWithPolicy is CursorCodec with a choice of what changed filters do
+// With RestartOnChangedFilters, the cursor paginators serve the first page and set Restarted
+// instead of returning ErrFiltersChanged.
+//
+// Example usage:
+//
+//	codec := filtering.CursorCodecWithPolicy(filters, nil, filtering.RestartOnChangedFilters)
+//	result, err := pagination.CursorPaginateInt(query, &products, pagination.GetCursor(c), 20, "id", true,
+//	    pagination.WithCursorCodec(codec),
+//	)
+//	// result.Restarted => the client's filters changed; replace the list instead of appending
+func CursorCodecWithPolicy(filters []Filter, base pagination.CursorCodec, policy FilterChangePolicy) pagination.CursorCodec {
+	if base == nil {
+		base = pagination.DefaultCursorCodec
+	}
+	return filterBoundCodec{base: base, hash: FilterHash(filters), policy: policy}
+}
+
+func (c filterBoundCodec) Encode(value any) (string, error) {
+	token, err := c.base.Encode(value)
+	if err != nil {
+		return "", err
+	}
+	return c.hash + "." + token, nil
+}
+
+func (c filterBoundCodec) Decode(cursor string) (any, error) {
+	hash, token, ok := strings.Cut(cursor, ".")
+	if !ok || hash != c.hash {
+		if c.policy == RestartOnChangedFilters {
+			return nil, fmt.Errorf("%w: %v", pagination.ErrRestartPagination, ErrFiltersChanged)
+		}
+		return nil, ErrFiltersChanged
+	}
+	return c.base.Decode(token)
+}
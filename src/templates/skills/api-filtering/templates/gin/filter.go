@@ -0,0 +1,84 @@
+package filtering
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Operator is a comparison a filter applies to a field
+type Operator string
+
+const (
+	OpEq      Operator = "eq"
+	OpNe      Operator = "ne"
+	OpGt      Operator = "gt"
+	OpGte     Operator = "gte"
+	OpLt      Operator = "lt"
+	OpLte     Operator = "lte"
+	OpIn      Operator = "in"
+	OpBetween Operator = "between"
+	OpLike    Operator = "like"
+	OpIsNull  Operator = "null"
+)
+
+// knownOperators lists every operator the parser accepts
+var knownOperators = map[Operator]bool{
+	OpEq: true, OpNe: true, OpGt: true, OpGte: true, OpLt: true, OpLte: true,
+	OpIn: true, OpBetween: true, OpLike: true, OpIsNull: true,
+}
+
+// FieldType controls how raw query values are coerced before they reach SQL
+type FieldType string
+
+const (
+	TypeString FieldType = "string"
+	TypeInt    FieldType = "integer"
+	TypeFloat  FieldType = "number"
+	TypeBool   FieldType = "boolean"
+	TypeTime   FieldType = "date-time"
+)
+
+// Filter is one parsed condition, e.g. filter[price][gte]=100
+// Raw holds the unparsed values (several for in/between); they are coerced against the schema
+type Filter struct {
+	Field    string   `json:"field"`
+	Operator Operator `json:"operator"`
+	Raw      []string `json:"values"`
+}
+
+// String renders the filter back in query-string form (used for cursor hashing and logs)
+func (f Filter) String() string {
+	return fmt.Sprintf("filter[%s][%s]=%v", f.Field, f.Operator, f.Raw)
+}
+
+// ErrInvalidFilter is the sentinel wrapped by every filter validation error
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// FilterError describes why a filter was rejected
+// It matches errors.Is(err, ErrInvalidFilter) and is safe to show to API clients
+type FilterError struct {
+	Field    string   `json:"field,omitempty"`
+	Operator Operator `json:"operator,omitempty"`
+	Reason   string   `json:"reason"`
+}
+
+func (e *FilterError) Error() string {
+	switch {
+	case e.Field == "":
+		return fmt.Sprintf("%s: %s", ErrInvalidFilter, e.Reason)
+	case e.Operator == "":
+		return fmt.Sprintf("%s: %s: %s", ErrInvalidFilter, e.Field, e.Reason)
+	default:
+		return fmt.Sprintf("%s: %s[%s]: %s", ErrInvalidFilter, e.Field, e.Operator, e.Reason)
+	}
+}
+
+func (e *FilterError) Unwrap() error {
+	return ErrInvalidFilter
+}
+
+// MaxFilters caps how many conditions a single request may send
+const MaxFilters = {{maxFilters}}
+
+// MaxInValues caps the number of values in an "in" filter
+const MaxInValues = {{maxInValues}}
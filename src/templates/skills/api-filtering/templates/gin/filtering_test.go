@@ -0,0 +1,177 @@
+package filtering
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"{{packageImportPath}}/pagination"
+)
+
+type testProduct struct {
+	ID         uint    `json:"id" filter:"eq,in"`
+	Name       string  `json:"name" filter:"eq,like"`
+	Price      float64 `json:"price" filter:"gte,lte,between"`
+	CategoryID *uint   `json:"category_id" filter:"eq"`
+	Secret     string  `json:"secret"`
+}
+
+func parseAndValidate(t *testing.T, query string) error {
+	t.Helper()
+
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		t.Fatalf("bad test query %q: %v", query, err)
+	}
+
+	filters, err := ParseFilters(values)
+	if err != nil {
+		return err
+	}
+	return FromStruct[testProduct]().Validate(filters)
+}
+
+func TestValidFilters(t *testing.T) {
+	queries := []string{
+		"filter[id]=7",
+		"filter[id][in]=1,2,3",
+		"filter[name][like]=50%25_off",
+		"filter[price][between]=10,99.5",
+		"filter[category_id][null]=true",
+	}
+
+	for _, query := range queries {
+		if err := parseAndValidate(t, query); err != nil {
+			t.Errorf("%s: unexpected error: %v", query, err)
+		}
+	}
+}
+
+func TestOperatorMisuse(t *testing.T) {
+	queries := map[string]string{
+		"unknown operator":      "filter[price][approx]=10",
+		"operator not allowed":  "filter[price][eq]=10",
+		"field not filterable":  "filter[secret]=x",
+		"null on non-nullable":  "filter[name][null]=true",
+		"between with one":      "filter[price][between]=10",
+		"like on numeric field": "filter[id][like]=1",
+		"malformed key":         "filter[price]gte=10",
+	}
+
+	for name, query := range queries {
+		err := parseAndValidate(t, query)
+		if !errors.Is(err, ErrInvalidFilter) {
+			t.Errorf("%s (%s): expected ErrInvalidFilter, got %v", name, query, err)
+		}
+	}
+}
+
+func TestTypeCoercionErrors(t *testing.T) {
+	queries := []string{
+		"filter[id]=abc",
+		"filter[id][in]=1,two",
+		"filter[price][gte]=cheap",
+		"filter[category_id][null]=maybe",
+	}
+
+	for _, query := range queries {
+		var filterErr *FilterError
+		if err := parseAndValidate(t, query); !errors.As(err, &filterErr) {
+			t.Errorf("%s: expected *FilterError, got %v", query, err)
+		}
+	}
+}
+
+func TestFilterHashIsCanonical(t *testing.T) {
+	a, _ := ParseFilters(url.Values{"filter[price][gte]": {"10"}, "filter[id]": {"1"}})
+	b, _ := ParseFilters(url.Values{"filter[id]": {"1"}, "filter[price][gte]": {"10"}})
+	c, _ := ParseFilters(url.Values{"filter[id]": {"2"}})
+
+	if FilterHash(a) != FilterHash(b) {
+		t.Error("equal filter sets should hash identically")
+	}
+	if FilterHash(a) == FilterHash(c) {
+		t.Error("different filter sets should hash differently")
+	}
+}
+
+func TestCursorCodecRejectsChangedFilters(t *testing.T) {
+	issued, _ := ParseFilters(url.Values{"filter[id]": {"1"}})
+	changed, _ := ParseFilters(url.Values{"filter[id]": {"2"}})
+
+	cursor, err := CursorCodec(issued, nil).Encode(42)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := CursorCodec(issued, nil).Decode(cursor); err != nil {
+		t.Errorf("same filters: unexpected error: %v", err)
+	}
+	if _, err := CursorCodec(changed, nil).Decode(cursor); !errors.Is(err, ErrFiltersChanged) {
+		t.Errorf("changed filters: expected ErrFiltersChanged, got %v", err)
+	}
+}
+
+func TestCursorCodecRestartsOnChangedFilters(t *testing.T) {
+	issued, _ := ParseFilters(url.Values{"filter[id]": {"1"}})
+	changed, _ := ParseFilters(url.Values{"filter[id]": {"2"}})
+
+	cursor, err := CursorCodecWithPolicy(issued, nil, RestartOnChangedFilters).Encode(42)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := CursorCodecWithPolicy(issued, nil, RestartOnChangedFilters).Decode(cursor); err != nil {
+		t.Errorf("same filters: unexpected error: %v", err)
+	}
+
+	// The paginators treat ErrRestartPagination as "serve the first page"
+	_, err = CursorCodecWithPolicy(changed, nil, RestartOnChangedFilters).Decode(cursor)
+	if !errors.Is(err, pagination.ErrRestartPagination) {
+		t.Errorf("changed filters: expected ErrRestartPagination, got %v", err)
+	}
+}
+
+func TestPrefixConditionEscapesWildcards(t *testing.T) {
+	condition, pattern := prefixCondition("name", "50%_off!")
+
+	if condition != "name LIKE ? ESCAPE '!'" {
+		t.Errorf("condition = %q", condition)
+	}
+	if pattern != "50!%!_off!!%" {
+		t.Errorf("pattern = %q, want the prefix escaped with a trailing wildcard", pattern)
+	}
+}
+
+func TestPrefixCursorContinuation(t *testing.T) {
+	cursor, err := PrefixCursorCodec("name", "san", nil).Encode("San Diego")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The next page of the same search continues after the boundary
+	value, err := PrefixCursorCodec("name", "san", nil).Decode(cursor)
+	if err != nil || value != "San Diego" {
+		t.Errorf("same prefix: got %v, %v", value, err)
+	}
+
+	// A refined search would skip matches sorting before "San Diego"
+	if _, err := PrefixCursorCodec("name", "sa", nil).Decode(cursor); !errors.Is(err, ErrFiltersChanged) {
+		t.Errorf("changed prefix: expected ErrFiltersChanged, got %v", err)
+	}
+	if _, err := CursorCodec(nil, nil).Decode(cursor); !errors.Is(err, ErrFiltersChanged) {
+		t.Errorf("unfiltered cursor: expected ErrFiltersChanged, got %v", err)
+	}
+}
+
+func TestAllowedOperators(t *testing.T) {
+	allowed := FromStruct[testProduct]().AllowedOperators()
+
+	if !reflect.DeepEqual(allowed["category_id"], []string{"eq", "null"}) {
+		t.Errorf("category_id operators = %v, want eq plus null for the nullable column", allowed["category_id"])
+	}
+	if _, ok := allowed["secret"]; ok {
+		t.Error("untagged field listed as filterable")
+	}
+}
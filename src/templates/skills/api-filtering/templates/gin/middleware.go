@@ -0,0 +1,69 @@
+package filtering
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ParseFilterParams returns middleware that parses filter[...] query params and validates
+// them against M's schema. Invalid filters are rejected with 400 before the handler runs;
+// valid ones are stored on the context.
+//
+// Example usage:
+//
+//	import "{{packageImportPath}}/filtering"
+//
+//	r.GET("/products",
+//	    pagination.ParsePaginationParams,
+//	    filtering.ParseFilterParams[Product](),
+//	    ListProducts,
+//	)
+func ParseFilterParams[M any]() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filters, err := ParseFilters(c.Request.URL.Query())
+		if err == nil {
+			if schema, ok := SchemaFor[M](); ok {
+				err = schema.Validate(filters)
+			}
+		}
+		if err != nil {
+			AbortWithFilterError(c, err)
+			return
+		}
+
+		// Store in context for handler use
+		c.Set("filters", filters)
+
+		c.Next()
+	}
+}
+
+// GetFilters retrieves the parsed filters from Gin context
+// Returns nil (no filtering) if not set
+func GetFilters(c *gin.Context) []Filter {
+	if filters, exists := c.Get("filters"); exists {
+		if f, ok := filters.([]Filter); ok {
+			return f
+		}
+	}
+	return nil
+}
+
+// AbortWithFilterError responds 400 for filter validation errors and stale cursors, 500 otherwise
+func AbortWithFilterError(c *gin.Context, err error) {
+	var filterErr *FilterError
+	switch {
+	case errors.As(err, &filterErr):
+		c.AbortWithStatusJSON(400, gin.H{
+			"error":    filterErr.Error(),
+			"field":    filterErr.Field,
+			"operator": filterErr.Operator,
+			"reason":   filterErr.Reason,
+		})
+	case errors.Is(err, ErrFiltersChanged):
+		c.AbortWithStatusJSON(400, gin.H{"error": err.Error(), "param": "cursor"})
+	default:
+		c.AbortWithStatusJSON(500, gin.H{"error": err.Error()})
+	}
+}
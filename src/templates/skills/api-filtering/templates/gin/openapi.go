@@ -0,0 +1,85 @@
+package filtering
+
+import "fmt"
+
+// OpenAPIParameter is an OpenAPI 3 query parameter object
+type OpenAPIParameter struct {
+	Name        string        `json:"name"`
+	In          string        `json:"in"`
+	Description string        `json:"description,omitempty"`
+	Required    bool          `json:"required"`
+	Schema      OpenAPISchema `json:"schema"`
+}
+
+// OpenAPISchema is the subset of an OpenAPI 3 schema object used by filter parameters
+type OpenAPISchema struct {
+	Type   string `json:"type"`
+	Format string `json:"format,omitempty"`
+}
+
+// OpenAPIParameters documents every field/operator pair of the schema as query parameters
+// Merge the result into the "parameters" of the matching list operation in your OpenAPI spec
+//
+// Example usage:
+//
+//	schema, _ := filtering.SchemaFor[Product]()
+//	spec.Paths["/products"].Get.Parameters = append(
+//	    spec.Paths["/products"].Get.Parameters,
+//	    schema.OpenAPIParameters()...,
+//	)
+func (s *FilterSchema) OpenAPIParameters() []OpenAPIParameter {
+	var params []OpenAPIParameter
+
+	for _, field := range s.Fields() {
+		ops := field.Operators
+		if field.Nullable {
+			ops = append(append([]Operator{}, ops...), OpIsNull)
+		}
+
+		for _, op := range ops {
+			param := OpenAPIParameter{
+				Name:   fmt.Sprintf("filter[%s][%s]", field.Name, op),
+				In:     "query",
+				Schema: openAPISchemaFor(field.Type),
+			}
+
+			switch op {
+			case OpIn:
+				param.Description = fmt.Sprintf("%s is one of the comma-separated values (max %d)", field.Name, MaxInValues)
+				param.Schema = OpenAPISchema{Type: "string"}
+			case OpBetween:
+				param.Description = fmt.Sprintf("%s is between two comma-separated values (inclusive)", field.Name)
+				param.Schema = OpenAPISchema{Type: "string"}
+			case OpLike:
+				param.Description = fmt.Sprintf("%s contains the value (wildcards are matched literally)", field.Name)
+			case OpIsNull:
+				param.Description = fmt.Sprintf("%s is null (true) or not null (false)", field.Name)
+				param.Schema = OpenAPISchema{Type: "boolean"}
+			case OpEq:
+				param.Description = fmt.Sprintf("%s equals the value (shorthand: filter[%s])", field.Name, field.Name)
+			default:
+				param.Description = fmt.Sprintf("%s %s the value", field.Name, op)
+			}
+
+			params = append(params, param)
+		}
+	}
+
+	return params
+}
+
+// openAPISchemaFor maps a filter field type to its OpenAPI schema
+func openAPISchemaFor(fieldType FieldType) OpenAPISchema {
+	switch fieldType {
+	case TypeInt:
+		return OpenAPISchema{Type: "integer", Format: "int64"}
+	case TypeFloat:
+		return OpenAPISchema{Type: "number", Format: "double"}
+	case TypeBool:
+		return OpenAPISchema{Type: "boolean"}
+	case TypeTime:
+		return OpenAPISchema{Type: "string", Format: "date-time"}
+	default:
+		return OpenAPISchema{Type: "string"}
+	}
+}
@@ -0,0 +1,162 @@
+package filtering
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// filterKeyPattern matches filter[field] and filter[field][op]
+var filterKeyPattern = regexp.MustCompile(`^filter\[([A-Za-z_][A-Za-z0-9_.]*)\](?:\[([a-z]+)\])?$`)
+
+// ParseFilters extracts filters from query parameters such as
+// filter[price][gte]=100&filter[status]=active&filter[tag][in]=a,b
+// filter[field]=v is shorthand for filter[field][eq]=v. Values are only syntax-checked here;
+// Validate them against a registered FilterSchema before building SQL.
+// Filters are returned sorted by field and operator so equal queries parse identically.
+func ParseFilters(values url.Values) ([]Filter, error) {
+	var filters []Filter
+
+	for key, raws := range values {
+		if !strings.HasPrefix(key, "filter[") {
+			continue
+		}
+
+		match := filterKeyPattern.FindStringSubmatch(key)
+		if match == nil {
+			return nil, &FilterError{Reason: fmt.Sprintf("malformed filter parameter %q", key)}
+		}
+
+		field, op := match[1], Operator(match[2])
+		if op == "" {
+			op = OpEq
+		}
+		if !knownOperators[op] {
+			return nil, &FilterError{Field: field, Operator: op, Reason: "unknown operator"}
+		}
+
+		for _, raw := range raws {
+			filter := Filter{Field: field, Operator: op, Raw: []string{raw}}
+			if op == OpIn || op == OpBetween {
+				filter.Raw = strings.Split(raw, ",")
+			}
+			filters = append(filters, filter)
+		}
+	}
+
+	if len(filters) > MaxFilters {
+		return nil, &FilterError{Reason: fmt.Sprintf("at most %d filters are allowed", MaxFilters)}
+	}
+
+	sort.SliceStable(filters, func(i, j int) bool {
+		if filters[i].Field != filters[j].Field {
+			return filters[i].Field < filters[j].Field
+		}
+		return filters[i].Operator < filters[j].Operator
+	})
+
+	return filters, nil
+}
+
+// Validate checks every filter against the allowlist and coerces its values
+func (s *FilterSchema) Validate(filters []Filter) error {
+	for _, filter := range filters {
+		if _, err := s.resolve(filter); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolvedFilter is a validated filter with values coerced to the field type
+type resolvedFilter struct {
+	field  FilterableField
+	op     Operator
+	values []interface{}
+}
+
+// resolve validates one filter and coerces its values
+func (s *FilterSchema) resolve(filter Filter) (resolvedFilter, error) {
+	field, ok := s.fields[filter.Field]
+	if !ok {
+		return resolvedFilter{}, &FilterError{Field: filter.Field, Reason: "not filterable"}
+	}
+	if !field.allows(filter.Operator) {
+		return resolvedFilter{}, &FilterError{
+			Field:    filter.Field,
+			Operator: filter.Operator,
+			Reason:   fmt.Sprintf("operator not allowed (allowed: %s)", operatorList(field)),
+		}
+	}
+
+	fail := func(reason string) (resolvedFilter, error) {
+		return resolvedFilter{}, &FilterError{Field: filter.Field, Operator: filter.Operator, Reason: reason}
+	}
+
+	switch filter.Operator {
+	case OpBetween:
+		if len(filter.Raw) != 2 {
+			return fail("between takes exactly two comma-separated values")
+		}
+	case OpIn:
+		if len(filter.Raw) == 0 || len(filter.Raw) > MaxInValues {
+			return fail(fmt.Sprintf("in takes between 1 and %d values", MaxInValues))
+		}
+	case OpLike:
+		if field.Type != TypeString {
+			return fail("like is only supported on string fields")
+		}
+	case OpIsNull:
+		isNull, err := strconv.ParseBool(filter.Raw[0])
+		if err != nil {
+			return fail("null takes true or false")
+		}
+		return resolvedFilter{field: field, op: filter.Operator, values: []interface{}{isNull}}, nil
+	}
+
+	values := make([]interface{}, 0, len(filter.Raw))
+	for _, raw := range filter.Raw {
+		value, err := coerce(field.Type, strings.TrimSpace(raw))
+		if err != nil {
+			return fail(fmt.Sprintf("%q is not a valid %s", raw, field.Type))
+		}
+		values = append(values, value)
+	}
+
+	return resolvedFilter{field: field, op: filter.Operator, values: values}, nil
+}
+
+// coerce converts a raw query value to the Go type matching the field
+func coerce(fieldType FieldType, raw string) (interface{}, error) {
+	switch fieldType {
+	case TypeInt:
+		return strconv.ParseInt(raw, 10, 64)
+	case TypeFloat:
+		return strconv.ParseFloat(raw, 64)
+	case TypeBool:
+		return strconv.ParseBool(raw)
+	case TypeTime:
+		if t, err := time.Parse(time.RFC3339, raw); err == nil {
+			return t, nil
+		}
+		return time.Parse("2006-01-02", raw)
+	default:
+		return raw, nil
+	}
+}
+
+// operatorList renders a field's allowed operators for error messages
+func operatorList(field FilterableField) string {
+	names := make([]string, 0, len(field.Operators)+1)
+	for _, op := range field.Operators {
+		names = append(names, string(op))
+	}
+	if field.Nullable {
+		names = append(names, string(OpIsNull))
+	}
+	return strings.Join(names, ", ")
+}
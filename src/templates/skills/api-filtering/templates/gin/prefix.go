@@ -0,0 +1,70 @@
+package filtering
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"{{packageImportPath}}/pagination"
+)
+
+// ApplyPrefix restricts query to rows whose column starts with prefix
+// Wildcards in prefix are escaped, so "50%" matches "50% off" but not "500". column comes
+// from code, never from the request.
+//
+// Example usage:
+//
+//	query := filtering.ApplyPrefix(db.Model(&City{}), "name", c.Query("q"))
+func ApplyPrefix(db *gorm.DB, column, prefix string) *gorm.DB {
+	condition, pattern := prefixCondition(column, prefix)
+	return db.Where(condition, pattern)
+}
+
+// PrefixCursorPaginate paginates the rows whose column starts with prefix, in ascending
+// cursorField order, for typeahead and autocomplete endpoints
+// The prefix filter and the cursor boundary are applied together on every page, and cursors
+// are bound to the prefix with PrefixCursorCodec: a cursor replayed with another prefix fails
+// with ErrFiltersChanged instead of skipping matches that sort before the old boundary. A
+// WithCursorCodec in opts replaces that binding, so wrap custom codecs with PrefixCursorCodec.
+// Index column for prefix scans (in Postgres, text_pattern_ops unless the collation is C).
+//
+// Example usage:
+//
+//	func SuggestCities(c *gin.Context) {
+//	    var cities []City
+//	    result, err := filtering.PrefixCursorPaginate(db.Model(&City{}), &cities,
+//	        "name", c.Query("q"), pagination.GetCursor(c), 10, "name")
+//	    if errors.Is(err, filtering.ErrFiltersChanged) {
+//	        c.JSON(400, gin.H{"error": "search changed; restart without a cursor"})
+//	        return
+//	    }
+//	    // ...
+//	    c.JSON(200, result)
+//	}
+func PrefixCursorPaginate[T any](
+	db *gorm.DB,
+	dest *[]T,
+	column string,
+	prefix string,
+	cursor string,
+	pageSize int,
+	cursorField string,
+	opts ...pagination.Option,
+) (*pagination.CursorPagination[T], error) {
+	bound := []pagination.Option{pagination.WithCursorCodec(PrefixCursorCodec(column, prefix, nil))}
+	query := ApplyPrefix(db, column, prefix)
+
+	return pagination.CursorPaginateString(query, dest, cursor, pageSize, cursorField, true, append(bound, opts...)...)
+}
+
+// PrefixCursorCodec binds pagination cursors to a prefix match on column
+// base is the codec being wrapped (nil = pagination.DefaultCursorCodec).
+func PrefixCursorCodec(column, prefix string, base pagination.CursorCodec) pagination.CursorCodec {
+	match := Filter{Field: column, Operator: "prefix", Raw: []string{prefix}}
+	return CursorCodec([]Filter{match}, base)
+}
+
+// prefixCondition builds the escaped LIKE condition and pattern for a prefix match
+func prefixCondition(column, prefix string) (string, string) {
+	return fmt.Sprintf("%s LIKE ? ESCAPE '!'", column), likeEscaper.Replace(prefix) + "%"
+}
@@ -0,0 +1,238 @@
+package filtering
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// FilterableField describes one field clients may filter on
+type FilterableField struct {
+	Name      string
+	Column    string
+	Type      FieldType
+	Operators []Operator
+	Nullable  bool
+}
+
+// allows reports whether op is permitted on the field
+func (f FilterableField) allows(op Operator) bool {
+	if op == OpIsNull {
+		return f.Nullable
+	}
+	for _, allowed := range f.Operators {
+		if allowed == op {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterSchema is the filter allowlist for one model
+type FilterSchema struct {
+	fields map[string]FilterableField
+}
+
+// NewFilterSchema creates an empty schema; add fields with Allow
+func NewFilterSchema() *FilterSchema {
+	return &FilterSchema{fields: make(map[string]FilterableField)}
+}
+
+// Allow registers a filterable API name, its column and type, and the operators it accepts
+func (s *FilterSchema) Allow(name string, column string, fieldType FieldType, ops ...Operator) *FilterSchema {
+	s.fields[name] = FilterableField{Name: name, Column: column, Type: fieldType, Operators: ops}
+	return s
+}
+
+// AllowNullable is Allow for columns that may be NULL (enables filter[name][null]=true|false)
+func (s *FilterSchema) AllowNullable(name string, column string, fieldType FieldType, ops ...Operator) *FilterSchema {
+	s.fields[name] = FilterableField{Name: name, Column: column, Type: fieldType, Operators: ops, Nullable: true}
+	return s
+}
+
+// Field returns the definition of a filterable field
+func (s *FilterSchema) Field(name string) (FilterableField, bool) {
+	field, ok := s.fields[name]
+	return field, ok
+}
+
+// Fields returns every filterable field sorted by name
+func (s *FilterSchema) Fields() []FilterableField {
+	fields := make([]FilterableField, 0, len(s.fields))
+	for _, field := range s.fields {
+		fields = append(fields, field)
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Name < fields[j].Name })
+	return fields
+}
+
+// AllowedOperators maps every filterable field to its operators, "null" included for nullable
+// fields, e.g. for pagination.Capabilities.WithFilters
+func (s *FilterSchema) AllowedOperators() map[string][]string {
+	allowed := make(map[string][]string, len(s.fields))
+	for name, field := range s.fields {
+		ops := make([]string, 0, len(field.Operators)+1)
+		for _, op := range field.Operators {
+			ops = append(ops, string(op))
+		}
+		if field.Nullable {
+			ops = append(ops, string(OpIsNull))
+		}
+		allowed[name] = ops
+	}
+	return allowed
+}
+
+// FromStruct builds a schema from `filter` struct tags on M
+// The tag lists the allowed operators; the API name comes from the json tag and the column
+// from the gorm column tag (or the snake_cased field name). Pointer fields are nullable.
+//
+// Example usage:
+//
+//	type Product struct {
+//	    ID         uint       `json:"id" filter:"eq,in"`
+//	    Name       string     `json:"name" filter:"eq,like"`
+//	    Price      float64    `json:"price" filter:"eq,gte,lte,between"`
+//	    CategoryID *uint      `json:"category_id" filter:"eq,in"`
+//	    CreatedAt  time.Time  `json:"created_at" filter:"gte,lte,between"`
+//	}
+//
+//	func init() {
+//	    filtering.Register[Product](filtering.FromStruct[Product]())
+//	}
+func FromStruct[M any]() *FilterSchema {
+	schema := NewFilterSchema()
+	collectTaggedFields(schema, reflect.TypeOf(new(M)).Elem())
+	return schema
+}
+
+// collectTaggedFields walks struct fields (including embedded structs) for filter tags
+func collectTaggedFields(schema *FilterSchema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			collectTaggedFields(schema, sf.Type)
+			continue
+		}
+
+		tag, ok := sf.Tag.Lookup("filter")
+		if !ok || tag == "-" {
+			continue
+		}
+
+		var ops []Operator
+		for _, op := range strings.Split(tag, ",") {
+			if op = strings.TrimSpace(op); op != "" {
+				ops = append(ops, Operator(op))
+			}
+		}
+
+		fieldType := sf.Type
+		nullable := fieldType.Kind() == reflect.Ptr
+		if nullable {
+			fieldType = fieldType.Elem()
+		}
+
+		schema.fields[apiName(sf)] = FilterableField{
+			Name:      apiName(sf),
+			Column:    columnName(sf),
+			Type:      fieldTypeOf(fieldType),
+			Operators: ops,
+			Nullable:  nullable,
+		}
+	}
+}
+
+// apiName returns the json name of a struct field, falling back to snake_case
+func apiName(sf reflect.StructField) string {
+	if name := strings.Split(sf.Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+		return name
+	}
+	return snakeCase(sf.Name)
+}
+
+// columnName returns the gorm column of a struct field, falling back to snake_case
+func columnName(sf reflect.StructField) string {
+	for _, setting := range strings.Split(sf.Tag.Get("gorm"), ";") {
+		if strings.HasPrefix(strings.TrimSpace(setting), "column:") {
+			return strings.TrimPrefix(strings.TrimSpace(setting), "column:")
+		}
+	}
+	return snakeCase(sf.Name)
+}
+
+// fieldTypeOf maps a Go type to the coercion type used for its filter values
+func fieldTypeOf(t reflect.Type) FieldType {
+	if t == reflect.TypeOf(time.Time{}) {
+		return TypeTime
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return TypeInt
+	case reflect.Float32, reflect.Float64:
+		return TypeFloat
+	case reflect.Bool:
+		return TypeBool
+	default:
+		return TypeString
+	}
+}
+
+// snakeCase converts a Go field name to snake_case ("CategoryID" => "category_id")
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			prevLower := i > 0 && unicode.IsLower(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if i > 0 && (prevLower || (nextLower && unicode.IsUpper(runes[i-1]))) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// registry holds one FilterSchema per model type
+var registry sync.Map
+
+// Register sets the filter allowlist for model M
+func Register[M any](schema *FilterSchema) {
+	registry.Store(modelType(new(M)), schema)
+}
+
+// SchemaFor returns the filter schema registered for model M
+func SchemaFor[M any]() (*FilterSchema, bool) {
+	return lookupSchema(new(M))
+}
+
+// lookupSchema finds the schema for a model value (struct, pointer, or slice of either)
+func lookupSchema(model interface{}) (*FilterSchema, bool) {
+	if model == nil {
+		return nil, false
+	}
+
+	schema, ok := registry.Load(modelType(model))
+	if !ok {
+		return nil, false
+	}
+	return schema.(*FilterSchema), true
+}
+
+// modelType unwraps pointers and slices down to the model's struct type
+func modelType(model interface{}) reflect.Type {
+	t := reflect.TypeOf(model)
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,312 @@
+package pagination
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrNoArchiveMapping is returned when ArchiveTables has no FromArchive
+var ErrNoArchiveMapping = errors.New("archive rows need a FromArchive mapping")
+
+// Sources of an ArchivedCursorPaginate cursor
+const (
+	liveSource    = "live"
+	archiveSource = "archive"
+)
+
+// DefaultArchiveCountMaxAge is how long an archive count is cached by default
+const DefaultArchiveCountMaxAge = time.Hour
+
+// ArchiveTables is a live table and the archive table its oldest rows are moved to, read as one
+// timeline by ArchivedCursorPaginate
+// A is the archive's row type; FromArchive maps it to T, so the archive's schema may differ.
+type ArchiveTables[T, A any] struct {
+	// Live and Archive query the two tables, each with the request's filters applied
+	Live    *gorm.DB
+	Archive *gorm.DB
+
+	// FromArchive maps an archive row to T
+	FromArchive func(A) T
+
+	// ArchiveCursorField is the archive's column for the cursor field ("" = the same name);
+	// the tie-breaker column must have the same name in both tables
+	ArchiveCursorField string
+
+	// Count sets TotalItems to the live count plus the archive count; a row in both tables
+	// mid-migration is counted twice until it leaves the live table
+	Count bool
+
+	// CountCache keeps archive counts for CountMaxAge (default DefaultArchiveCountMaxAge),
+	// since the archive changes only when rows are moved (nil = count the archive every time);
+	// counts are indexed under the archive's table, so the migration can InvalidateTable it
+	CountCache  PageCache
+	CountMaxAge time.Duration
+}
+
+// archiveFetch returns up to limit rows of source in page order, past p when it is set
+type archiveFetch[T any] func(source string, p *partitionCursor, limit int) ([]T, error)
+
+// ArchivedCursorPaginate pages one continuous timeline across a live table and its archive
+// Rows are ordered by cursorField (then the tie-breaker) with the archive's rows ordered before
+// the live table's, as a migration that moves the oldest rows produces. Newest first (ascending
+// false) serves the live table until it runs out past the cursor, then continues in the
+// archive on the same page; ascending walks the archive first. The cursor records the table it
+// points into.
+//
+// Each table continues strictly past the last row served, whichever table served it, so a row
+// copied to the archive but not yet deleted from the live table is served once (deduplicated by
+// its cursor field and tie-breaker, i.e. its id). Pages move forward only: PreviousCursor is
+// never set, and WithRowIDTieBreaker is rejected.
+//
+// Example usage:
+//
+//	type ArchivedEvent struct {
+//	    EventID  int64
+//	    Payload  []byte
+//	    Occurred time.Time
+//	}
+//
+//	result, err := pagination.ArchivedCursorPaginate(pagination.ArchiveTables[Event, ArchivedEvent]{
+//	    Live:    db.Model(&Event{}).Where("tenant_id = ?", tenant),
+//	    Archive: db.Table("events_archive").Where("tenant_id = ?", tenant),
+//	    FromArchive: func(a ArchivedEvent) Event {
+//	        return Event{ID: a.EventID, Payload: a.Payload, CreatedAt: a.Occurred}
+//	    },
+//	    ArchiveCursorField: "event_id",
+//	    Count:              true,
+//	    CountCache:         archiveCounts, // pagination.NewMemoryPageCache(1000)
+//	}, &events, c.Query("cursor"), 50, "id", false)
+func ArchivedCursorPaginate[T, A any](
+	tables ArchiveTables[T, A],
+	dest *[]T,
+	cursor string,
+	pageSize int,
+	cursorField string,
+	ascending bool,
+	opts ...Option,
+) (*CursorPagination[T], error) {
+	if tables.FromArchive == nil {
+		return nil, ErrNoArchiveMapping
+	}
+	o := applyOptions(opts)
+	live := o.bindContext(tables.Live).Session(&gorm.Session{})
+	archive := o.bindContext(tables.Archive).Session(&gorm.Session{})
+
+	if err := checkDestType[T](live); err != nil {
+		return nil, err
+	}
+
+	// Constrain page size
+	if limit := queryMaxPageSize(live); pageSize > limit {
+		pageSize = limit
+	}
+	if pageSize < 1 {
+		pageSize = o.config.DefaultPageSize
+	}
+
+	tie, err := resolveTieBreaker[T](live, cursorField, o)
+	if err != nil {
+		return nil, err
+	}
+	if tie != nil && tie.field == nil {
+		return nil, fmt.Errorf("%w: rowid cannot be recorded in archive cursors", ErrTieBreakerRequired)
+	}
+
+	archiveField := tables.ArchiveCursorField
+	if archiveField == "" {
+		archiveField = cursorField
+	}
+
+	fetch := func(source string, p *partitionCursor, limit int) ([]T, error) {
+		query, field := live, cursorField
+		if source == archiveSource {
+			query, field = archive, archiveField
+		}
+		if p != nil {
+			if tie != nil && p.Tie != nil {
+				query = query.Where(tieBreakerCondition(field, tie.column, ascending, false), p.Value, p.Value, p.Tie)
+			} else {
+				query = query.Where(cursorCondition(field, ascending, false), p.Value)
+			}
+		}
+		query = query.Order(tieBreakerOrder(field, ascending))
+		if tie != nil {
+			query = query.Order(tieBreakerOrder(tie.column, ascending))
+		}
+
+		if source == liveSource {
+			var rows []T
+			err := o.fetchLimiter.do(queryContext(query), func() error {
+				return o.onFetchDB(query).Limit(sqlLimit(query, limit)).Find(&rows).Error
+			})
+			return rows, err
+		}
+
+		var archived []A
+		err := o.fetchLimiter.do(queryContext(query), func() error {
+			return o.onFetchDB(query).Limit(sqlLimit(query, limit)).Find(&archived).Error
+		})
+		rows := make([]T, len(archived))
+		for i := range archived {
+			rows[i] = tables.FromArchive(archived[i])
+		}
+		return rows, err
+	}
+
+	result, err := paginateArchive(live, fetch, dest, cursor, pageSize, cursorField, tie, ascending, o)
+	if err != nil {
+		return nil, err
+	}
+
+	if tables.Count {
+		total, err := archivedTotal(live, archive, tables, o)
+		if err != nil {
+			return nil, err
+		}
+		result.TotalItems = &total
+	}
+	return result, nil
+}
+
+// paginateArchive builds an ArchivedCursorPaginate page over the tables read through fetch
+func paginateArchive[T any](
+	db *gorm.DB,
+	fetch archiveFetch[T],
+	dest *[]T,
+	cursor string,
+	pageSize int,
+	cursorField string,
+	tie *tieBreaker,
+	ascending bool,
+	o options,
+) (*CursorPagination[T], error) {
+	key, err := newFieldExtractor[T](db, cursorField)
+	if err != nil {
+		return nil, err
+	}
+
+	position, restarted, err := decodePartitionCursor(cursor, o)
+	if err != nil {
+		return nil, err
+	}
+
+	// Newest first reads the live table, then the archive
+	sources := []string{liveSource, archiveSource}
+	if ascending {
+		sources = []string{archiveSource, liveSource}
+	}
+	start := 0
+	if position != nil {
+		switch position.Partition {
+		case sources[0]:
+		case sources[1]:
+			start = 1
+		default:
+			return nil, fmt.Errorf("%w: unknown table %q", ErrInvalidCursor, position.Partition)
+		}
+	}
+
+	// Fetch one row past the page, continuing into the next table past the last row served
+	ctx := queryContext(db)
+	var rows []T
+	var owners []string
+	after := position
+	for step := start; step < len(sources) && len(rows) <= pageSize; step++ {
+		fetched, err := fetch(sources[step], after, pageSize+1-len(rows))
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch items from the %s table: %w", sources[step], err)
+		}
+		rows = append(rows, fetched...)
+		for range fetched {
+			owners = append(owners, sources[step])
+		}
+		if len(fetched) > 0 {
+			after = archivePosition(ctx, fetched[len(fetched)-1], sources[step], key, tie)
+		}
+	}
+
+	hasNext := len(rows) > pageSize
+	if hasNext {
+		rows = rows[:pageSize]
+	}
+	items := nonNilItems(rows)
+	*dest = items
+
+	result := &CursorPagination[T]{
+		Items:       items,
+		HasNext:     hasNext,
+		HasPrevious: cursor != "" && !restarted,
+		PageSize:    pageSize,
+		Restarted:   restarted,
+	}
+	if len(items) > 0 {
+		last := len(items) - 1
+		result.FirstKey = key.value(ctx, items[0])
+		result.LastKey = key.value(ctx, items[last])
+
+		endCursor, err := encodePartitionCursor(*archivePosition(ctx, items[last], owners[last], key, tie), o)
+		if err != nil {
+			return nil, err
+		}
+		result.EndCursor = &endCursor
+		if hasNext {
+			nextCursor := endCursor
+			result.NextCursor = &nextCursor
+		}
+	}
+	return result, nil
+}
+
+// archivePosition is the cursor position of item, served from source
+func archivePosition[T any](ctx context.Context, item T, source string, key *fieldExtractor, tie *tieBreaker) *partitionCursor {
+	p := &partitionCursor{Partition: source, Value: key.value(ctx, item)}
+	if tie != nil {
+		p.Tie = tie.field.value(ctx, item)
+	}
+	return p
+}
+
+// archivedTotal counts the live table and adds the archive's count, cached when the tables
+// have a CountCache
+func archivedTotal[T, A any](live, archive *gorm.DB, tables ArchiveTables[T, A], o options) (int64, error) {
+	var liveTotal int64
+	err := o.countLimiter.do(queryContext(live), func() error {
+		return o.onCountDB(live).Count(&liveTotal).Error
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count live items: %w", err)
+	}
+
+	ctx := queryContext(archive)
+	cacheKey := ""
+	if tables.CountCache != nil {
+		cacheKey = "archive-count:" + countQueryHash(archive, o)
+		if raw, ok, err := tables.CountCache.Get(ctx, cacheKey); err == nil && ok {
+			if archiveTotal, err := strconv.ParseInt(string(raw), 10, 64); err == nil {
+				return liveTotal + archiveTotal, nil
+			}
+		}
+	}
+
+	var archiveTotal int64
+	err = o.countLimiter.do(ctx, func() error {
+		return o.onCountDB(archive).Count(&archiveTotal).Error
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count archived items: %w", err)
+	}
+
+	if tables.CountCache != nil {
+		maxAge := tables.CountMaxAge
+		if maxAge <= 0 {
+			maxAge = DefaultArchiveCountMaxAge
+		}
+		_ = tables.CountCache.Set(ctx, cacheKey, []byte(strconv.FormatInt(archiveTotal, 10)), maxAge, archive.Statement.Table)
+	}
+	return liveTotal + archiveTotal, nil
+}
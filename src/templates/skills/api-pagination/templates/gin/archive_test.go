@@ -0,0 +1,221 @@
+package pagination
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// archivedEvent is an event in the archive table, whose schema names the ID differently
+type archivedEvent struct {
+	EventID int64
+	Payload string
+}
+
+// archiveDB serves live events and archived events past the page query's cursor condition,
+// checking that archive conditions use the archive's column; archiveCounts counts the
+// archive's COUNT queries
+func archiveDB(t *testing.T, live []event, archive []archivedEvent, archiveCounts *int) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Dialector = quotingDialector{namedDialector{name: "postgres"}}
+
+	// page returns the ids past the cursor condition, in the query's order and limit
+	page := func(tx *gorm.DB, ids []int64, column string) []int64 {
+		after := int64(-1)
+		if c, ok := tx.Statement.Clauses["WHERE"]; ok {
+			for _, e := range c.Expression.(clause.Where).Exprs {
+				if expr, ok := e.(clause.Expr); ok && len(expr.Vars) > 0 {
+					if !strings.HasPrefix(expr.SQL, column+" ") {
+						t.Errorf("condition %q, want one on %s", expr.SQL, column)
+					}
+					after = expr.Vars[0].(int64)
+				}
+			}
+		}
+		descending := strings.HasSuffix(tx.Statement.Clauses["ORDER BY"].Expression.(clause.OrderBy).Columns[0].Column.Name, "DESC")
+		limit := *tx.Statement.Clauses["LIMIT"].Expression.(clause.Limit).Limit
+
+		var out []int64
+		for i := range ids {
+			id := ids[i]
+			if descending {
+				id = ids[len(ids)-1-i]
+			}
+			if after >= 0 && ((!descending && id <= after) || (descending && id >= after)) {
+				continue
+			}
+			if len(out) < limit {
+				out = append(out, id)
+			}
+		}
+		return out
+	}
+
+	err = db.Callback().Query().Register("test:archive", func(tx *gorm.DB) {
+		if tx.DryRun {
+			return
+		}
+		switch dest := tx.Statement.Dest.(type) {
+		case *[]event:
+			var ids []int64
+			for _, e := range live {
+				ids = append(ids, e.ID)
+			}
+			for _, id := range page(tx, ids, "id") {
+				*dest = append(*dest, event{ID: id})
+			}
+		case *[]archivedEvent:
+			var ids []int64
+			for _, e := range archive {
+				ids = append(ids, e.EventID)
+			}
+			for _, id := range page(tx, ids, "event_id") {
+				*dest = append(*dest, archivedEvent{EventID: id})
+			}
+		case *int64:
+			*dest = int64(len(live))
+			if tx.Statement.Table == "events_archive" {
+				*archiveCounts++
+				*dest = int64(len(archive))
+			}
+			tx.RowsAffected = 1
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// archivedWithIDs returns an archived event per id
+func archivedWithIDs(ids ...int64) []archivedEvent {
+	archived := make([]archivedEvent, len(ids))
+	for i, id := range ids {
+		archived[i].EventID = id
+	}
+	return archived
+}
+
+// eventTables are the live events and their archive, with row 5 in both mid-migration
+func eventTables(db *gorm.DB) ArchiveTables[event, archivedEvent] {
+	return ArchiveTables[event, archivedEvent]{
+		Live:               db.Model(&event{}),
+		Archive:            db.Table("events_archive"),
+		FromArchive:        func(a archivedEvent) event { return event{ID: a.EventID} },
+		ArchiveCursorField: "event_id",
+	}
+}
+
+// walkArchive pages through both tables and returns the ids of each page
+func walkArchive(t *testing.T, tables ArchiveTables[event, archivedEvent], pageSize int, ascending bool) [][]int64 {
+	t.Helper()
+	var seen [][]int64
+	cursor := ""
+	for pages := 0; pages < 10; pages++ {
+		var events []event
+		result, err := ArchivedCursorPaginate(tables, &events, cursor, pageSize, "id", ascending)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []int64
+		for _, e := range result.Items {
+			ids = append(ids, e.ID)
+		}
+		seen = append(seen, ids)
+
+		if !result.HasNext {
+			break
+		}
+		cursor = *result.NextCursor
+	}
+	return seen
+}
+
+func TestArchivedCursorPaginateNewestFirst(t *testing.T) {
+	var counts int
+	db := archiveDB(t, eventsWithIDs(5, 6, 7, 8, 9), archivedWithIDs(1, 2, 3, 4, 5), &counts)
+
+	// The second page crosses from the live table into the archive; row 5 is served once
+	got := walkArchive(t, eventTables(db), 3, false)
+	want := [][]int64{
+		{9, 8, 7},
+		{6, 5, 4},
+		{3, 2, 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("pages = %v, want %v", got, want)
+	}
+}
+
+func TestArchivedCursorPaginateOldestFirst(t *testing.T) {
+	var counts int
+	db := archiveDB(t, eventsWithIDs(5, 6, 7, 8, 9), archivedWithIDs(1, 2, 3, 4, 5), &counts)
+
+	got := walkArchive(t, eventTables(db), 3, true)
+	want := [][]int64{
+		{1, 2, 3},
+		{4, 5, 6},
+		{7, 8, 9},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("pages = %v, want %v", got, want)
+	}
+}
+
+func TestArchivedCursorPaginateLiveExhaustedAtCursor(t *testing.T) {
+	var counts int
+	db := archiveDB(t, eventsWithIDs(4, 5, 6), archivedWithIDs(1, 2, 3), &counts)
+
+	// The first page ends on the live table's last row; the next starts the archive
+	got := walkArchive(t, eventTables(db), 3, false)
+	want := [][]int64{
+		{6, 5, 4},
+		{3, 2, 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("pages = %v, want %v", got, want)
+	}
+}
+
+func TestArchivedCursorPaginateCountsBothTables(t *testing.T) {
+	var counts int
+	db := archiveDB(t, eventsWithIDs(6, 7, 8), archivedWithIDs(1, 2, 3, 4, 5), &counts)
+	tables := eventTables(db)
+	tables.Count = true
+	tables.CountCache = NewMemoryPageCache(10)
+
+	for i := 0; i < 2; i++ {
+		var events []event
+		result, err := ArchivedCursorPaginate(tables, &events, "", 2, "id", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.TotalItems == nil || *result.TotalItems != 8 {
+			t.Errorf("total = %v, want 3 live + 5 archived", result.TotalItems)
+		}
+	}
+	if counts != 1 {
+		t.Errorf("archive counted %d times, want once then cached", counts)
+	}
+}
+
+func TestArchivedCursorPaginateRejectsUnknownTable(t *testing.T) {
+	var counts int
+	db := archiveDB(t, eventsWithIDs(2), archivedWithIDs(1), &counts)
+
+	cursor, err := encodePartitionCursor(partitionCursor{Partition: "events_2019", Value: int64(1)}, applyOptions(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var events []event
+	if _, err := ArchivedCursorPaginate(eventTables(db), &events, cursor, 2, "id", false); err == nil {
+		t.Error("cursor into an unknown table: want ErrInvalidCursor")
+	}
+}
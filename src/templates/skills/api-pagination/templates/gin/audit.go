@@ -0,0 +1,292 @@
+package pagination
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// AuditActorKey is the gin context key the default AuditPolicy reads the actor from; set it in
+// the authentication middleware
+const AuditActorKey = "user_id"
+
+// DefaultAuditBuffer is the queue size of a GormAuditSink created with a buffer below 1
+const DefaultAuditBuffer = 1024
+
+// AuditEntry records one successful paginated read
+type AuditEntry struct {
+	// Actor is who read the page, and RequestID the request that read it
+	Actor     string
+	RequestID string
+
+	// Resource is the table read, and Filters the request's other query parameters in
+	// canonical form (e.g. "filter[status]=active&sort=-created_at")
+	Resource string
+	Filters  string
+
+	// Page is the offset page number (nil for cursor pages); Cursor is the cursor the page
+	// continued from, and FirstKey and LastKey the cursor field values of its first and last rows
+	Page     *int
+	Cursor   string
+	FirstKey string
+	LastKey  string
+
+	// Rows is the number of rows returned
+	Rows int
+
+	At time.Time
+}
+
+// AuditSink receives audit entries on the request path
+// Record must not block: queue the entry and write it elsewhere, dropping it when full.
+type AuditSink interface {
+	Record(ctx context.Context, entry AuditEntry)
+}
+
+// AuditMetrics is implemented by Metrics that also count audit entries a sink dropped
+type AuditMetrics interface {
+	AuditDropped(resource string)
+}
+
+// NoopAuditSink discards every entry; it is the sink of an AuditPolicy without one
+type NoopAuditSink struct{}
+
+// Record discards entry
+func (NoopAuditSink) Record(ctx context.Context, entry AuditEntry) {}
+
+// AuditPolicy decides which paginated reads are audited and how their entries are filled in
+// A nil policy audits nothing.
+//
+// Example usage:
+//
+//	var auditLog = pagination.NewGormAuditSink(db, 4096, metrics)
+//	defer auditLog.Close()
+//
+//	sensitive := &pagination.AuditPolicy{Sink: auditLog, Resources: []string{"patients", "invoices"}}
+//
+//	r.GET("/patients", func(c *gin.Context) {
+//	    _ = pagination.HandlePaginated[Patient](c, db.Model(&Patient{}), pagination.HandleOptions{Audit: sensitive})
+//	})
+type AuditPolicy struct {
+	// Sink receives the entries (nil = NoopAuditSink)
+	Sink AuditSink
+
+	// Resources lists the audited tables (nil = every table)
+	Resources []string
+
+	// Actor reads the reader's identity (nil = the AuditActorKey value of the gin context)
+	Actor func(c *gin.Context) string
+
+	// RequestID reads the request's ID (nil = the X-Request-ID header)
+	RequestID func(c *gin.Context) string
+}
+
+// audits reports whether reads of resource are audited
+func (p *AuditPolicy) audits(resource string) bool {
+	if p == nil || p.Sink == nil {
+		return false
+	}
+	if p.Resources == nil {
+		return true
+	}
+	for _, r := range p.Resources {
+		if r == resource {
+			return true
+		}
+	}
+	return false
+}
+
+// entry fills in the request's side of an entry for resource
+func (p *AuditPolicy) entry(c *gin.Context, resource string, rows int) AuditEntry {
+	entry := AuditEntry{
+		Resource: resource,
+		Filters:  auditFilters(c.Request.URL.Query()),
+		Rows:     rows,
+		At:       time.Now(),
+	}
+	if p.Actor != nil {
+		entry.Actor = p.Actor(c)
+	} else if actor, ok := c.Get(AuditActorKey); ok {
+		entry.Actor = fmt.Sprint(actor)
+	}
+	if p.RequestID != nil {
+		entry.RequestID = p.RequestID(c)
+	} else {
+		entry.RequestID = c.GetHeader("X-Request-ID")
+	}
+	return entry
+}
+
+// auditFilters renders the query parameters other than the pagination params
+func auditFilters(values url.Values) string {
+	filters := url.Values{}
+	for key, v := range values {
+		filters[key] = v
+	}
+	for _, key := range QueryKeys {
+		filters.Del(key)
+	}
+	return filters.Encode()
+}
+
+// AuditOffsetPage records an offset page read through db under policy, for handlers that do
+// not use HandlePaginated
+// It only enqueues: the sink writes the entry off the request path.
+func AuditOffsetPage[T any](c *gin.Context, policy *AuditPolicy, db *gorm.DB, result *OffsetPagination[T]) {
+	resource, ok := pageCacheTable[T](db)
+	if !ok || !policy.audits(resource) {
+		return
+	}
+	entry := policy.entry(c, resource, len(result.Items))
+	page := result.CurrentPage
+	entry.Page = &page
+	policy.Sink.Record(c.Request.Context(), entry)
+}
+
+// AuditCursorPage records a cursor page read through db from cursor under policy, for
+// handlers that do not use HandlePaginated
+func AuditCursorPage[T any](c *gin.Context, policy *AuditPolicy, db *gorm.DB, cursor string, result *CursorPagination[T]) {
+	resource, ok := pageCacheTable[T](db)
+	if !ok || !policy.audits(resource) {
+		return
+	}
+	entry := policy.entry(c, resource, len(result.Items))
+	entry.Cursor = cursor
+	if len(result.Items) > 0 {
+		entry.FirstKey, entry.LastKey = fmt.Sprint(result.FirstKey), fmt.Sprint(result.LastKey)
+	}
+	policy.Sink.Record(c.Request.Context(), entry)
+}
+
+// AuditRecord is a row of the audit log GormAuditSink writes
+// Create the table with db.AutoMigrate(&pagination.AuditRecord{}).
+type AuditRecord struct {
+	ID        uint64 `gorm:"primaryKey"`
+	Actor     string `gorm:"index"`
+	RequestID string
+	Resource  string `gorm:"index"`
+	Filters   string
+	Page      *int
+	Cursor    string
+	FirstKey  string
+	LastKey   string
+	Rows      int
+	ReadAt    time.Time `gorm:"index"`
+}
+
+// TableName keeps the audit log apart from the application's tables
+func (AuditRecord) TableName() string {
+	return "pagination_audit_log"
+}
+
+// GormAuditSink is an AuditSink writing entries to the AuditRecord table in the background
+// Record only enqueues; a full queue drops the entry and reports it to AuditMetrics, so a slow
+// database never slows reads. Entries queued together are written in one insert.
+type GormAuditSink struct {
+	db      *gorm.DB
+	metrics Metrics
+	entries chan AuditEntry
+	done    chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// auditBatchSize caps the entries written in one insert
+const auditBatchSize = 100
+
+// NewGormAuditSink starts a sink queuing up to buffer entries for db (default
+// DefaultAuditBuffer); metrics may be nil, or implement AuditMetrics to count dropped entries
+// Close it on shutdown to write the queued entries.
+func NewGormAuditSink(db *gorm.DB, buffer int, metrics Metrics) *GormAuditSink {
+	if buffer < 1 {
+		buffer = DefaultAuditBuffer
+	}
+	s := &GormAuditSink{
+		db:      db.Session(&gorm.Session{NewDB: true, Context: context.Background()}),
+		metrics: metrics,
+		entries: make(chan AuditEntry, buffer),
+		done:    make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// Record queues entry, or drops it when the queue is full or the sink is closed
+func (s *GormAuditSink) Record(ctx context.Context, entry AuditEntry) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		s.dropped(entry.Resource)
+		return
+	}
+
+	select {
+	case s.entries <- entry:
+	default:
+		s.dropped(entry.Resource)
+	}
+}
+
+// Close stops accepting entries and waits until the queued ones are written
+func (s *GormAuditSink) Close() error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.entries)
+	}
+	s.mu.Unlock()
+
+	<-s.done
+	return nil
+}
+
+// run writes queued entries until the sink is closed
+func (s *GormAuditSink) run() {
+	defer close(s.done)
+	for entry := range s.entries {
+		batch := []AuditRecord{auditRecord(entry)}
+		for len(batch) < auditBatchSize && len(s.entries) > 0 {
+			next, ok := <-s.entries
+			if !ok {
+				break
+			}
+			batch = append(batch, auditRecord(next))
+		}
+
+		if err := s.db.Create(&batch).Error; err != nil {
+			for _, record := range batch {
+				s.dropped(record.Resource)
+			}
+		}
+	}
+}
+
+// dropped reports a lost entry to the sink's AuditMetrics
+func (s *GormAuditSink) dropped(resource string) {
+	if m, ok := s.metrics.(AuditMetrics); ok {
+		m.AuditDropped(resource)
+	}
+}
+
+// auditRecord is the row of entry
+func auditRecord(entry AuditEntry) AuditRecord {
+	return AuditRecord{
+		Actor:     entry.Actor,
+		RequestID: entry.RequestID,
+		Resource:  entry.Resource,
+		Filters:   entry.Filters,
+		Page:      entry.Page,
+		Cursor:    entry.Cursor,
+		FirstKey:  entry.FirstKey,
+		LastKey:   entry.LastKey,
+		Rows:      entry.Rows,
+		ReadAt:    entry.At,
+	}
+}
@@ -0,0 +1,119 @@
+package pagination
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// recordingSink keeps every entry it is given
+type recordingSink struct {
+	entries []AuditEntry
+}
+
+func (s *recordingSink) Record(ctx context.Context, entry AuditEntry) {
+	s.entries = append(s.entries, entry)
+}
+
+// countingAuditMetrics counts dropped audit entries by resource
+type countingAuditMetrics struct {
+	noopMetrics
+	dropped map[string]int
+}
+
+func (m *countingAuditMetrics) AuditDropped(resource string) {
+	m.dropped[resource]++
+}
+
+// serveAudited runs HandlePaginated for a GET of target as user alice with a request ID
+func serveAudited(t *testing.T, db *gorm.DB, target string, policy *AuditPolicy) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/events", func(c *gin.Context) {
+		c.Set(AuditActorKey, "alice")
+	}, ParsePaginationParams, func(c *gin.Context) {
+		if err := HandlePaginated[event](c, db, HandleOptions{Audit: policy}); err != nil {
+			t.Error(err)
+		}
+	})
+
+	req := httptest.NewRequest("GET", target, nil)
+	req.Header.Set("X-Request-ID", "req-1")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func TestHandlePaginatedAuditsFlaggedResources(t *testing.T) {
+	rows := []event{
+		{ID: 3},
+		{ID: 4},
+	}
+	total := int64(6)
+	db := handlerDB(t, &rows, &total)
+
+	sink := &recordingSink{}
+	serveAudited(t, db, "/events?page=2&page_size=2&status=paid", &AuditPolicy{Sink: sink, Resources: []string{"events"}})
+	if len(sink.entries) != 1 {
+		t.Fatalf("entries = %+v, want one", sink.entries)
+	}
+	entry := sink.entries[0]
+	if entry.Actor != "alice" || entry.RequestID != "req-1" || entry.Resource != "events" ||
+		entry.Filters != "status=paid" || entry.Page == nil || *entry.Page != 2 || entry.Rows != 2 || entry.At.IsZero() {
+		t.Errorf("entry = %+v", entry)
+	}
+
+	// Reads of resources the policy does not flag are not audited
+	sink = &recordingSink{}
+	serveAudited(t, db, "/events", &AuditPolicy{Sink: sink, Resources: []string{"invoices"}})
+	if len(sink.entries) != 0 {
+		t.Errorf("unflagged resource audited: %+v", sink.entries)
+	}
+}
+
+func TestGormAuditSinkDropsWhenFull(t *testing.T) {
+	db, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	writing := make(chan struct{}, 1)
+	release := make(chan struct{})
+	var written []AuditRecord
+	err = db.Callback().Create().Register("test:audit", func(tx *gorm.DB) {
+		writing <- struct{}{}
+		<-release
+		written = append(written, *tx.Statement.Dest.(*[]AuditRecord)...)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	metrics := &countingAuditMetrics{dropped: map[string]int{}}
+	sink := NewGormAuditSink(db, 1, metrics)
+	ctx := context.Background()
+
+	// The first entry is being written, the second waits in the queue, the third is dropped
+	sink.Record(ctx, AuditEntry{Resource: "patients", Actor: "a"})
+	<-writing
+	sink.Record(ctx, AuditEntry{Resource: "patients", Actor: "b"})
+	sink.Record(ctx, AuditEntry{Resource: "patients", Actor: "c"})
+	if metrics.dropped["patients"] != 1 {
+		t.Errorf("dropped = %v, want one patients entry", metrics.dropped)
+	}
+
+	close(release)
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(written) != 2 || written[0].Actor != "a" || written[1].Actor != "b" {
+		t.Errorf("written = %+v, want the entries of a and b", written)
+	}
+
+	// A closed sink drops instead of panicking
+	sink.Record(ctx, AuditEntry{Resource: "patients"})
+	if metrics.dropped["patients"] != 2 {
+		t.Errorf("dropped after close = %v", metrics.dropped)
+	}
+}
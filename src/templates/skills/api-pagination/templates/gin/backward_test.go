@@ -0,0 +1,256 @@
+package pagination
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// postIDs returns the ids of a page of posts
+func postIDs(items []post) []int64 {
+	ids := make([]int64, len(items))
+	for i, p := range items {
+		ids[i] = p.ID
+	}
+	return ids
+}
+
+// deref returns the value p points at, or nil, for test messages
+func deref[T any](p *T) any {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
+
+// countedPostsDB is postsDB that also answers capped counts, applying the counted window's
+// cursor conditions and LIMIT
+func countedPostsDB(t *testing.T, posts []post) *gorm.DB {
+	t.Helper()
+	db := postsDB(t, posts)
+	err := db.Callback().Query().Register("test:posts-count", func(tx *gorm.DB) {
+		dest, ok := tx.Statement.Dest.(*int64)
+		if !ok || tx.Statement.TableExpr == nil {
+			return
+		}
+		window := tx.Statement.TableExpr.Vars[0].(*gorm.DB).Statement
+		conditions := postConditions(window)
+
+		var counted int64
+	next:
+		for _, p := range posts {
+			for _, past := range conditions {
+				if !past(p) {
+					continue next
+				}
+			}
+			counted++
+		}
+		if limit := window.Clauses["LIMIT"].Expression.(clause.Limit).Limit; counted > int64(*limit) {
+			counted = int64(*limit)
+		}
+		*dest = counted
+		tx.RowsAffected = 1
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestBackwardRetracesForwardPages(t *testing.T) {
+	for _, ascending := range []bool{true, false} {
+		t.Run(fmt.Sprintf("ascending=%t", ascending), func(t *testing.T) {
+			db := postsDB(t, postsOf(7, 10))
+			var page []post
+
+			// Forward twice from the first page
+			first, err := CursorPaginateInt(db, &page, "", 3, "id", ascending)
+			if err != nil {
+				t.Fatal(err)
+			}
+			second, err := CursorPaginateInt(db, &page, *first.NextCursor, 3, "id", ascending)
+			if err != nil {
+				t.Fatal(err)
+			}
+			third, err := CursorPaginateInt(db, &page, *second.NextCursor, 3, "id", ascending)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Backward twice lands on the same pages, in the same order
+			back, err := CursorPaginateInt(db, &page, *third.PreviousCursor, 3, "id", ascending, WithBackward())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(postIDs(back.Items), postIDs(second.Items)) || !back.HasNext || !back.HasPrevious {
+				t.Errorf("one page back = %v (next %t, previous %t), want %v with pages either side",
+					postIDs(back.Items), back.HasNext, back.HasPrevious, postIDs(second.Items))
+			}
+			start, err := CursorPaginateInt(db, &page, *back.PreviousCursor, 3, "id", ascending, WithBackward())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(postIDs(start.Items), postIDs(first.Items)) {
+				t.Errorf("two pages back = %v, want the first page %v", postIDs(start.Items), postIDs(first.Items))
+			}
+			if start.HasPrevious || start.PreviousCursor != nil || !start.HasNext {
+				t.Errorf("back on the first page: previous %t (%v), next %t", start.HasPrevious, start.PreviousCursor, start.HasNext)
+			}
+
+			// And forward again from there
+			again, err := CursorPaginateInt(db, &page, *start.NextCursor, 3, "id", ascending)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(postIDs(again.Items), postIDs(second.Items)) {
+				t.Errorf("forward from the first page again = %v, want %v", postIDs(again.Items), postIDs(second.Items))
+			}
+		})
+	}
+}
+
+func TestBackwardShortFirstPage(t *testing.T) {
+	db := postsDB(t, postsOf(7, 10))
+	var page []post
+
+	// Two rows precede post 3: a short page with nothing before it
+	result, err := CursorPaginateInt(db, &page, EncodeCursor("id", 3, true), 5, "id", true, WithBackward())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(postIDs(result.Items), []int64{1, 2}) || result.HasPrevious || !result.HasNext {
+		t.Errorf("page before post 3 = %v (previous %t, next %t), want [1 2] with only a next page",
+			postIDs(result.Items), result.HasPrevious, result.HasNext)
+	}
+
+	// Without a cursor, the last page
+	last, err := CursorPaginateInt(db, &page, "", 3, "id", true, WithBackward())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(postIDs(last.Items), []int64{5, 6, 7}) || !last.HasPrevious || last.HasNext {
+		t.Errorf("last page = %v (previous %t, next %t), want [5 6 7] with only a previous page",
+			postIDs(last.Items), last.HasPrevious, last.HasNext)
+	}
+}
+
+func TestPreviousCursorPagesBackward(t *testing.T) {
+	for _, ascending := range []bool{true, false} {
+		t.Run(fmt.Sprintf("ascending=%t", ascending), func(t *testing.T) {
+			db := postsDB(t, postsOf(7, 10))
+			var page []post
+
+			// Forward twice, then the second page's PreviousCursor as a plain cursor
+			first, err := CursorPaginateInt(db, &page, "", 3, "id", ascending)
+			if err != nil {
+				t.Fatal(err)
+			}
+			second, err := CursorPaginateInt(db, &page, *first.NextCursor, 3, "id", ascending)
+			if err != nil {
+				t.Fatal(err)
+			}
+			back, err := CursorPaginateInt(db, &page, *second.PreviousCursor, 3, "id", ascending)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(postIDs(back.Items), postIDs(first.Items)) || back.HasPrevious || !back.HasNext {
+				t.Errorf("page before the second = %v (previous %t, next %t), want the first page %v",
+					postIDs(back.Items), back.HasPrevious, back.HasNext, postIDs(first.Items))
+			}
+
+			// Its NextCursor pages forward again
+			again, err := CursorPaginateInt(db, &page, *back.NextCursor, 3, "id", ascending)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(postIDs(again.Items), postIDs(second.Items)) {
+				t.Errorf("forward from there = %v, want the second page %v", postIDs(again.Items), postIDs(second.Items))
+			}
+		})
+	}
+}
+
+func TestBackwardApproxRemaining(t *testing.T) {
+	for _, ascending := range []bool{true, false} {
+		t.Run(fmt.Sprintf("ascending=%t", ascending), func(t *testing.T) {
+			db := countedPostsDB(t, postsOf(7, 10))
+			var page []post
+
+			// Forward to the last page of 7 posts in pages of 3
+			first, err := CursorPaginateInt(db, &page, "", 3, "id", ascending)
+			if err != nil {
+				t.Fatal(err)
+			}
+			second, err := CursorPaginateInt(db, &page, *first.NextCursor, 3, "id", ascending)
+			if err != nil {
+				t.Fatal(err)
+			}
+			third, err := CursorPaginateInt(db, &page, *second.NextCursor, 3, "id", ascending)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Back to the middle page, only the last post follows it
+			back, err := CursorPaginateInt(db, &page, *third.PreviousCursor, 3, "id", ascending, WithApproxRemaining(100))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if back.ApproxRemaining == nil || *back.ApproxRemaining != 1 {
+				t.Errorf("remaining after %v = %v, want 1", postIDs(back.Items), deref(back.ApproxRemaining))
+			}
+
+			// Back to the first page, four posts follow it
+			start, err := CursorPaginateInt(db, &page, *back.PreviousCursor, 3, "id", ascending, WithApproxRemaining(100))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if start.ApproxRemaining == nil || *start.ApproxRemaining != 4 {
+				t.Errorf("remaining after %v = %v, want 4", postIDs(start.Items), deref(start.ApproxRemaining))
+			}
+		})
+	}
+}
+
+func TestBackwardHybridOffset(t *testing.T) {
+	for _, ascending := range []bool{true, false} {
+		t.Run(fmt.Sprintf("ascending=%t", ascending), func(t *testing.T) {
+			db := countedPostsDB(t, postsOf(7, 10))
+			var page []post
+
+			// Forward to the last page of 7 posts in pages of 3
+			first, err := CursorPaginateInt(db, &page, "", 3, "id", ascending)
+			if err != nil {
+				t.Fatal(err)
+			}
+			second, err := CursorPaginateInt(db, &page, *first.NextCursor, 3, "id", ascending)
+			if err != nil {
+				t.Fatal(err)
+			}
+			third, err := CursorPaginateInt(db, &page, *second.NextCursor, 3, "id", ascending)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			back, err := CursorPaginateInt(db, &page, *third.PreviousCursor, 3, "id", ascending, WithHybridOffset(100))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if back.CurrentPage == nil || *back.CurrentPage != 2 || *back.TotalPages != 3 || *back.TotalItems != 7 {
+				t.Fatalf("page of %v = %v, want 2 of 3 pages of 7 posts", postIDs(back.Items), deref(back.CurrentPage))
+			}
+
+			start, err := CursorPaginateInt(db, &page, *back.PreviousCursor, 3, "id", ascending, WithHybridOffset(100))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if start.CurrentPage == nil || *start.CurrentPage != 1 || start.HasPrevious {
+				t.Errorf("page of %v = %v (previous %t), want 1 with no previous page",
+					postIDs(start.Items), deref(start.CurrentPage), start.HasPrevious)
+			}
+		})
+	}
+}
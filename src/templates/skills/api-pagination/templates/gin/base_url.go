@@ -0,0 +1,229 @@
+package pagination
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// BaseURLResolver derives the external URL of a request's path for pagination links, when the
+// service sits behind proxies that terminate TLS, rewrite the host, or strip a path prefix
+// Forwarding headers are honored only when the request arrived from a trusted proxy, and only
+// the values written by the trusted proxies of the chain are read, so clients cannot spoof
+// them through the public edge. A nil resolver returns the request's path, as links were built
+// before.
+type BaseURLResolver struct {
+	// external replaces the scheme, host, and prefix of every link (set by StaticBaseURL)
+	external string
+
+	// trusted are the proxies whose forwarding headers are honored
+	trusted []netip.Prefix
+}
+
+// NewBaseURLResolver honors forwarding headers from trustedProxies (IPs or CIDRs, e.g.
+// "10.0.0.0/8"): Forwarded (RFC 7239) first, then X-Forwarded-Proto, X-Forwarded-Host,
+// X-Forwarded-Port, and X-Forwarded-Prefix
+// In a chain of proxies, each trusted hop (the peer, then X-Forwarded-For or the Forwarded
+// "for" values from the right) moves the read one value to the left, to the value the
+// outermost trusted proxy wrote. Requests from other peers link to the request as received.
+//
+// Example usage:
+//
+//	links, err := pagination.NewBaseURLResolver("10.0.0.0/8") // the ingress' pod network
+//	// ...
+//	_ = pagination.HandlePaginated[User](c, db.Model(&User{}), pagination.HandleOptions{BaseURL: links})
+//	// => "https://api.example.com/v2/users?page=2&page_size=20", not "http://10.0.3.7:8080/users?..."
+func NewBaseURLResolver(trustedProxies ...string) (*BaseURLResolver, error) {
+	r := &BaseURLResolver{}
+	for _, proxy := range trustedProxies {
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(proxy)
+			if addrErr != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		r.trusted = append(r.trusted, prefix.Masked())
+	}
+	return r, nil
+}
+
+// StaticBaseURL links every request under the fixed external URL (scheme, host, and any path
+// prefix, e.g. "https://api.example.com/v2"), ignoring forwarding headers altogether
+func StaticBaseURL(external string) *BaseURLResolver {
+	return &BaseURLResolver{external: strings.TrimSuffix(external, "/")}
+}
+
+// BaseURL returns the external URL of req's path, without its query
+func (r *BaseURLResolver) BaseURL(req *http.Request) string {
+	if r == nil {
+		return req.URL.Path
+	}
+	if r.external != "" {
+		return r.external + req.URL.Path
+	}
+
+	proto, host, prefix := "http", req.Host, ""
+	if req.TLS != nil {
+		proto = "https"
+	}
+
+	if hops := r.trustedHops(req); hops > 0 {
+		if forwarded := req.Header.Values("Forwarded"); len(forwarded) > 0 {
+			element := forwardedElement(forwarded, hops)
+			proto = forwardedValue(proto, element["proto"], validProto)
+			host = forwardedValue(host, element["host"], validHost)
+		} else {
+			proto = forwardedValue(proto, listValue(req.Header.Values("X-Forwarded-Proto"), hops), validProto)
+			host = forwardedValue(host, listValue(req.Header.Values("X-Forwarded-Host"), hops), validHost)
+			if port := listValue(req.Header.Values("X-Forwarded-Port"), hops); port != "" && !strings.Contains(host, ":") {
+				host = withPort(host, port, proto)
+			}
+		}
+		prefix = forwardedPrefix(listValue(req.Header.Values("X-Forwarded-Prefix"), hops))
+	}
+	return proto + "://" + host + prefix + req.URL.Path
+}
+
+// trustedHops counts the trusted proxies at the end of req's chain: the peer, then the hops
+// recorded before it (0 when the peer is not trusted)
+func (r *BaseURLResolver) trustedHops(req *http.Request) int {
+	peer, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		peer = req.RemoteAddr
+	}
+	if !r.trusts(peer) {
+		return 0
+	}
+
+	// Earlier hops, nearest last
+	var chain []string
+	if forwarded := req.Header.Values("Forwarded"); len(forwarded) > 0 {
+		for _, element := range forwardedElements(forwarded) {
+			chain = append(chain, element["for"])
+		}
+	} else {
+		chain = splitList(req.Header.Values("X-Forwarded-For"))
+	}
+
+	hops := 1
+	for i := len(chain) - 1; i > 0 && r.trusts(chain[i]); i-- {
+		hops++
+	}
+	return hops
+}
+
+// trusts reports whether ip is one of the trusted proxies
+func (r *BaseURLResolver) trusts(ip string) bool {
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	addr, err := netip.ParseAddr(strings.Trim(ip, "[]"))
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range r.trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitList splits a repeated, comma-separated header into its values
+func splitList(headers []string) []string {
+	var values []string
+	for _, header := range headers {
+		for _, value := range strings.Split(header, ",") {
+			values = append(values, strings.TrimSpace(value))
+		}
+	}
+	return values
+}
+
+// listValue returns the value the outermost of hops trusted proxies wrote: hops from the right,
+// or the first when fewer were written ("" when none)
+func listValue(headers []string, hops int) string {
+	values := splitList(headers)
+	if len(values) == 0 {
+		return ""
+	}
+	i := len(values) - hops
+	if i < 0 {
+		i = 0
+	}
+	return values[i]
+}
+
+// forwardedElements parses Forwarded headers into their elements' lowercased parameters
+func forwardedElements(headers []string) []map[string]string {
+	var elements []map[string]string
+	for _, element := range splitList(headers) {
+		params := map[string]string{}
+		for _, pair := range strings.Split(element, ";") {
+			key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if ok {
+				params[strings.ToLower(key)] = strings.Trim(value, "\"")
+			}
+		}
+		elements = append(elements, params)
+	}
+	return elements
+}
+
+// forwardedElement returns the Forwarded element the outermost of hops trusted proxies wrote
+func forwardedElement(headers []string, hops int) map[string]string {
+	elements := forwardedElements(headers)
+	i := len(elements) - hops
+	if i < 0 {
+		i = 0
+	}
+	return elements[i]
+}
+
+// forwardedValue returns value when valid accepts it, and fallback otherwise
+func forwardedValue(fallback, value string, valid func(string) bool) string {
+	if value == "" || !valid(value) {
+		return fallback
+	}
+	return value
+}
+
+// validProto accepts the schemes links are served over
+func validProto(proto string) bool {
+	return proto == "http" || proto == "https"
+}
+
+// validHost accepts a host[:port] without characters that would change the URL's shape
+func validHost(host string) bool {
+	return !strings.ContainsAny(host, "/?#@\\ \t")
+}
+
+// withPort appends port to host unless it is the scheme's default
+func withPort(host, port, proto string) string {
+	if (proto == "https" && port == "443") || (proto == "http" && port == "80") {
+		return host
+	}
+	for _, c := range port {
+		if c < '0' || c > '9' {
+			return host
+		}
+	}
+	return host + ":" + port
+}
+
+// forwardedPrefix normalizes X-Forwarded-Prefix to "/prefix" ("" when unset or unsafe)
+func forwardedPrefix(prefix string) string {
+	prefix = strings.TrimSuffix(prefix, "/")
+	if prefix == "" || strings.ContainsAny(prefix, "?#\\ \t") || strings.HasPrefix(prefix, "//") {
+		return ""
+	}
+	if !strings.HasPrefix(prefix, "/") {
+		prefix = "/" + prefix
+	}
+	return prefix
+}
@@ -0,0 +1,168 @@
+package pagination
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestBaseURLResolver(t *testing.T) {
+	resolver, err := NewBaseURLResolver("10.0.0.0/8", "192.168.1.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		peer    string
+		headers map[string]string
+		want    string
+	}{
+		{
+			name: "no proxy headers",
+			peer: "10.0.0.2:5000",
+			want: "http://10.0.3.7:8080/users",
+		},
+		{
+			name: "trusted ingress",
+			peer: "10.0.0.2:5000",
+			headers: map[string]string{
+				"X-Forwarded-Proto":  "https",
+				"X-Forwarded-Host":   "api.example.com",
+				"X-Forwarded-Prefix": "/v2/",
+				"X-Forwarded-For":    "203.0.113.9",
+			},
+			want: "https://api.example.com/v2/users",
+		},
+		{
+			name: "spoofed from the internet",
+			peer: "203.0.113.9:5000",
+			headers: map[string]string{
+				"X-Forwarded-Proto": "https",
+				"X-Forwarded-Host":  "evil.example",
+			},
+			want: "http://10.0.3.7:8080/users",
+		},
+		{
+			// client -> edge 192.168.1.5 -> ingress 10.0.0.1 -> pod; the client's own header is
+			// left of the edge's, and the ingress appended its view of the host
+			name: "chained proxies",
+			peer: "10.0.0.1:5000",
+			headers: map[string]string{
+				"X-Forwarded-For":   "203.0.113.9, 192.168.1.5",
+				"X-Forwarded-Proto": "http, https, http",
+				"X-Forwarded-Host":  "evil.example, api.example.com, ingress.local",
+			},
+			want: "https://api.example.com/users",
+		},
+		{
+			// An untrusted hop in the middle stops the walk at the nearest proxy's values
+			name: "untrusted hop in the chain",
+			peer: "10.0.0.1:5000",
+			headers: map[string]string{
+				"X-Forwarded-For":  "203.0.113.9, 198.51.100.7",
+				"X-Forwarded-Host": "evil.example, ingress.local",
+			},
+			want: "http://ingress.local/users",
+		},
+		{
+			name: "forwarded header",
+			peer: "10.0.0.1:5000",
+			headers: map[string]string{
+				"Forwarded":        `for=203.0.113.9;proto=https;host=api.example.com, for="10.0.0.9:4711";proto=http;host=ingress.local`,
+				"X-Forwarded-Host": "ignored.example",
+			},
+			want: "https://api.example.com/users",
+		},
+		{
+			name: "forwarded port",
+			peer: "10.0.0.2:5000",
+			headers: map[string]string{
+				"X-Forwarded-Proto": "https",
+				"X-Forwarded-Host":  "api.example.com",
+				"X-Forwarded-Port":  "8443",
+			},
+			want: "https://api.example.com:8443/users",
+		},
+		{
+			name: "default port dropped",
+			peer: "10.0.0.2:5000",
+			headers: map[string]string{
+				"X-Forwarded-Proto": "https",
+				"X-Forwarded-Host":  "api.example.com",
+				"X-Forwarded-Port":  "443",
+			},
+			want: "https://api.example.com/users",
+		},
+		{
+			name: "malformed values ignored",
+			peer: "10.0.0.2:5000",
+			headers: map[string]string{
+				"X-Forwarded-Proto":  "javascript",
+				"X-Forwarded-Host":   "api.example.com/evil?",
+				"X-Forwarded-Prefix": "//evil.example",
+			},
+			want: "http://10.0.3.7:8080/users",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "http://10.0.3.7:8080/users?page=2", nil)
+			req.RemoteAddr = tc.peer
+			for key, value := range tc.headers {
+				req.Header.Set(key, value)
+			}
+			if got := resolver.BaseURL(req); got != tc.want {
+				t.Errorf("BaseURL = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestStaticAndNilBaseURL(t *testing.T) {
+	req := httptest.NewRequest("GET", "http://10.0.3.7:8080/users", nil)
+	req.Header.Set("X-Forwarded-Host", "evil.example")
+
+	if got := StaticBaseURL("https://api.example.com/v2/").BaseURL(req); got != "https://api.example.com/v2/users" {
+		t.Errorf("static BaseURL = %q", got)
+	}
+	var none *BaseURLResolver
+	if got := none.BaseURL(req); got != "/users" {
+		t.Errorf("nil resolver BaseURL = %q, want the request path", got)
+	}
+	if _, err := NewBaseURLResolver("not-an-ip"); err == nil {
+		t.Error("invalid trusted proxy accepted")
+	}
+}
+
+func TestHandlePaginatedLinksBehindProxy(t *testing.T) {
+	rows := eventsWithIDs(1, 2)
+	total := int64(4)
+	db := handlerDB(t, &rows, &total)
+	resolver, err := NewBaseURLResolver("10.0.0.0/8")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/events", ParsePaginationParams, func(c *gin.Context) {
+		_ = HandlePaginated[event](c, db, HandleOptions{BaseURL: resolver})
+	})
+	req := httptest.NewRequest("GET", "/events?page_size=2", nil)
+	req.RemoteAddr = "10.0.0.2:5000"
+	req.Header.Set("X-Forwarded-Proto", "https")
+	req.Header.Set("X-Forwarded-Host", "api.example.com")
+	req.Header.Set("X-Forwarded-Prefix", "/v2")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var response PaginatedResponse[event]
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Links == nil || response.Links.Next == nil || *response.Links.Next != "https://api.example.com/v2/events?page=2&page_size=2" {
+		t.Errorf("links = %s, want them under the external URL", w.Body)
+	}
+}
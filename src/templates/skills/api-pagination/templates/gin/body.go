@@ -0,0 +1,239 @@
+package pagination
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaxParamsBodyBytes caps the request body read by ParseParamsFromBody
+const MaxParamsBodyBytes = 1 << 20
+
+// paramsBody is the JSON body read by ParamsFromBody
+// Numbers are kept raw so bad values fall back to defaults exactly like the query parser.
+type paramsBody struct {
+	Page      json.RawMessage            `json:"page"`
+	PageSize  json.RawMessage            `json:"page_size"`
+	Limit     json.RawMessage            `json:"limit"`
+	Cursor    string                     `json:"cursor"`
+	PageToken string                     `json:"page_token"`
+	MaxBytes  json.RawMessage            `json:"max_bytes"`
+	Sort      json.RawMessage            `json:"sort"`
+	Filter    map[string]json.RawMessage `json:"filter"`
+}
+
+// ParseParamsFromBody is ParsePaginationParams for POST endpoints that take their list
+// parameters in a JSON body, such as complex search forms
+// It reads page, page_size (or limit), cursor, sort, and filter, applies the same defaults and
+// per-request limit (MaxPageSizeFor) as ParsePaginationParams, and restores the body so the
+// handler can bind its own fields.
+// Malformed JSON is rejected with 400.
+//
+// The body's sort and filter are converted to their query-string forms, ready for the
+// sorting and filtering skills:
+//
+//	{"page": 2, "page_size": 50, "sort": ["-created_at", "name"],
+//	 "filter": {"status": "active", "price": {"gte": 10, "lt": 100}, "tag": {"in": ["a", "b"]}}}
+//
+//	=> Page 2, PageSize 50, Sort "-created_at,name",
+//	   Filter filter[status]=active, filter[price][gte]=10, filter[price][lt]=100, filter[tag][in]=a,b
+//
+// Example usage:
+//
+//	r.POST("/search", pagination.ParseParamsFromBody, Search)
+//
+//	func Search(c *gin.Context) {
+//	    params := pagination.GetPaginationParams(c)
+//	    filters, err := filtering.ParseFilters(params.Filter)
+//	    // ...
+//	    sortFields, err := sorting.ParseSort(params.Sort)
+//	    // ...
+//	}
+func ParseParamsFromBody(c *gin.Context) {
+	raw, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, MaxParamsBodyBytes))
+	if err != nil {
+		AbortWithError(c, 400, fmt.Errorf("failed to read request body: %w", err))
+		return
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(raw))
+
+	params, err := paramsFromBody(raw, MaxPageSizeFor(c.Request.Context()))
+	if err != nil {
+		AbortWithError(c, 400, err)
+		return
+	}
+
+	// Store in context for handler use
+	c.Set("pagination_params", params)
+
+	c.Next()
+}
+
+// ParamsFromBody parses pagination params from a JSON body with the same aliases, defaults,
+// and limits as ParamsFromQuery; an empty body yields the defaults
+func ParamsFromBody(raw []byte) (PaginationParams, error) {
+	return paramsFromBody(raw, CurrentConfig().MaxPageSize)
+}
+
+// paramsFromBody parses a JSON body, clamping the page size to maxPageSize
+func paramsFromBody(raw []byte, maxPageSize int) (PaginationParams, error) {
+	params := DefaultPaginationParams()
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return params, nil
+	}
+
+	var body paramsBody
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return params, fmt.Errorf("invalid pagination body: %w", err)
+	}
+
+	if page, ok := bodyInt(body.Page); ok && page > 0 {
+		params.Page = page
+	}
+	if pageSize, ok := bodyInt(body.PageSize); ok && pageSize > 0 {
+		params.PageSize = pageSize
+	} else if ok && pageSize == 0 {
+		params.MetadataOnly = true
+	}
+	if limit, ok := bodyInt(body.Limit); ok && limit > 0 {
+		params.PageSize = limit
+		params.MetadataOnly = false
+	} else if ok && limit == 0 {
+		params.MetadataOnly = true
+	}
+	params.Cursor = body.Cursor
+	params.PageToken = body.PageToken
+	if maxBytes, ok := bodyInt(body.MaxBytes); ok && maxBytes > 0 {
+		params.MaxBytes = maxBytes
+	}
+
+	// Constrain page size to maximum
+	if params.PageSize > maxPageSize {
+		params.PageSize = maxPageSize
+	}
+
+	sortValue, err := bodySort(body.Sort)
+	if err != nil {
+		return params, err
+	}
+	params.Sort = sortValue
+
+	filter, err := bodyFilter(body.Filter)
+	if err != nil {
+		return params, err
+	}
+	params.Filter = filter
+
+	// Fingerprint the body's filters and sort as their query-string form would be
+	fingerprinted := url.Values{}
+	for key, value := range filter {
+		fingerprinted[key] = value
+	}
+	if sortValue != "" {
+		fingerprinted.Set("sort", sortValue)
+	}
+	params.Fingerprint = QueryFingerprint(fingerprinted)
+
+	return params, nil
+}
+
+// bodyInt reads a JSON number or numeric string; anything else is reported as absent
+func bodyInt(raw json.RawMessage) (int, bool) {
+	text := strings.Trim(string(bytes.TrimSpace(raw)), `"`)
+	if text == "" || text == "null" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(text)
+	return n, err == nil
+}
+
+// bodySort accepts "-created_at,name" or ["-created_at", "name"]
+func bodySort(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", nil
+	}
+
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text, nil
+	}
+	var fields []string
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return "", fmt.Errorf("invalid sort: must be a string or an array of strings")
+	}
+	return strings.Join(fields, ","), nil
+}
+
+// bodyFilter converts {"field": value} and {"field": {"op": value}} to filter[...] params
+func bodyFilter(filter map[string]json.RawMessage) (url.Values, error) {
+	values := url.Values{}
+
+	for field, raw := range filter {
+		var ops map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &ops); err == nil && ops != nil {
+			for op, operand := range ops {
+				value, err := filterValue(operand)
+				if err != nil {
+					return nil, fmt.Errorf("invalid filter %s.%s: %w", field, op, err)
+				}
+				values.Set(fmt.Sprintf("filter[%s][%s]", field, op), value)
+			}
+			continue
+		}
+
+		value, err := filterValue(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter %s: %w", field, err)
+		}
+		values.Set(fmt.Sprintf("filter[%s]", field), value)
+	}
+
+	return values, nil
+}
+
+// filterValue renders a filter operand as the query parser expects it: scalars as text,
+// arrays (for in and between) comma-separated
+func filterValue(raw json.RawMessage) (string, error) {
+	var list []json.RawMessage
+	if err := json.Unmarshal(raw, &list); err == nil && list != nil {
+		parts := make([]string, len(list))
+		for i, item := range list {
+			part, err := scalarValue(item)
+			if err != nil {
+				return "", err
+			}
+			parts[i] = part
+		}
+		return strings.Join(parts, ","), nil
+	}
+	return scalarValue(raw)
+}
+
+// scalarValue renders a JSON string, number, or boolean as text
+func scalarValue(raw json.RawMessage) (string, error) {
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return "", err
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		// Keep the literal so large integers stay exact
+		return string(bytes.TrimSpace(raw)), nil
+	case nil:
+		return "", fmt.Errorf(`null is not a value; use {"is_null": true}`)
+	default:
+		return "", fmt.Errorf("must be a string, number, boolean, or array of them")
+	}
+}
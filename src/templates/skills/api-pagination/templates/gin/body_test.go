@@ -0,0 +1,86 @@
+package pagination
+
+import (
+	"testing"
+)
+
+func TestParamsFromBody(t *testing.T) {
+	body := []byte(`{
+		"page": 3,
+		"page_size": 50,
+		"sort": ["-created_at", "name"],
+		"filter": {
+			"status": "active",
+			"price": {"gte": 10, "lt": 99.5},
+			"tag": {"in": ["a", "b"]},
+			"archived": {"is_null": true}
+		}
+	}`)
+
+	params, err := ParamsFromBody(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.Page != 3 || params.PageSize != 50 || params.Cursor != "" {
+		t.Errorf("unexpected pagination: %+v", params)
+	}
+	if params.Sort != "-created_at,name" {
+		t.Errorf("Sort = %q", params.Sort)
+	}
+
+	want := map[string]string{
+		"filter[status]":            "active",
+		"filter[price][gte]":        "10",
+		"filter[price][lt]":         "99.5",
+		"filter[tag][in]":           "a,b",
+		"filter[archived][is_null]": "true",
+	}
+	if len(params.Filter) != len(want) {
+		t.Errorf("Filter = %v, want %v", params.Filter, want)
+	}
+	for key, value := range want {
+		if got := params.Filter.Get(key); got != value {
+			t.Errorf("%s = %q, want %q", key, got, value)
+		}
+	}
+}
+
+func TestParamsFromBodyClampsLikeQuery(t *testing.T) {
+	cases := []struct {
+		body         string
+		page, size   int
+		cursor, sort string
+	}{
+		{body: ``, size: {{defaultPageSize}}, page: 1},
+		{body: `{}`, size: {{defaultPageSize}}, page: 1},
+		{body: `{"page": 0, "page_size": -5}`, size: {{defaultPageSize}}, page: 1},
+		{body: `{"page": "2", "page_size": "abc"}`, size: {{defaultPageSize}}, page: 2},
+		{body: `{"page_size": 100000}`, size: {{maxPageSize}}, page: 1},
+		{body: `{"page_size": 10, "limit": 5}`, page: 1, size: 5},
+		{body: `{"cursor": "MTA=", "sort": "name"}`, page: 1, size: {{defaultPageSize}}, cursor: "MTA=", sort: "name"},
+	}
+
+	for _, tc := range cases {
+		params, err := ParamsFromBody([]byte(tc.body))
+		if err != nil {
+			t.Errorf("%s: %v", tc.body, err)
+			continue
+		}
+		if params.Page != tc.page || params.PageSize != tc.size || params.Cursor != tc.cursor || params.Sort != tc.sort {
+			t.Errorf("%s: got %+v", tc.body, params)
+		}
+	}
+}
+
+func TestParamsFromBodyRejectsMalformedBodies(t *testing.T) {
+	for _, body := range []string{
+		`{"page": `,
+		`{"sort": 5}`,
+		`{"filter": {"status": null}}`,
+		`{"filter": {"price": {"gte": {"nested": 1}}}}`,
+	} {
+		if _, err := ParamsFromBody([]byte(body)); err == nil {
+			t.Errorf("%s: expected an error", body)
+		}
+	}
+}
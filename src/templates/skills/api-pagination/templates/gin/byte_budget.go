@@ -0,0 +1,101 @@
+package pagination
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MaxBytesHeader is the request header carrying a response byte budget (the max_bytes query
+// parameter works too and takes precedence)
+const MaxBytesHeader = "X-Max-Bytes"
+
+// DefaultBudgetMinRows is the floor of rows a byte budget keeps on a page by default
+const DefaultBudgetMinRows = 5
+
+// WithByteBudget cuts the cursor paginators' pages at maxBytes of JSON instead of a row count
+// The fetched rows are encoded one by one and the page stops before the first row that would
+// push the response past maxBytes (EnvelopeBytes are set aside for the metadata); a page cut
+// short has HasNext set, and EndCursor continues from its last row, so the next page starts
+// at the first row left out. Pages keep at least minRows rows (< 1 means DefaultBudgetMinRows)
+// whatever their size, so a few huge rows never shrink pages to one row. Under a budget,
+// PageBytes and RowCount report the page's approximate size and rows.
+//
+// maxBytes < 1 leaves pages alone, so a client's params.MaxBytes can be passed as is: only
+// clients sending ?max_bytes= or X-Max-Bytes get budgeted pages. The size is approximate:
+// redaction and links are applied after the cut.
+//
+// Example usage:
+//
+//	params := pagination.GetPaginationParams(c) // ?max_bytes=65536 from a mobile client
+//	result, err := pagination.CursorPaginateInt(db, &articles, params.Cursor, params.PageSize, "id", false,
+//	    pagination.WithByteBudget(params.MaxBytes, 3),
+//	)
+func WithByteBudget(maxBytes, minRows int) Option {
+	return func(o *options) {
+		if maxBytes < 1 {
+			o.byteBudget, o.byteBudgetMinRows = 0, 0
+			return
+		}
+		if minRows < 1 {
+			minRows = DefaultBudgetMinRows
+		}
+		o.byteBudget, o.byteBudgetMinRows = maxBytes, minRows
+	}
+}
+
+// budgetedPage is the part of a page that fits a byte budget
+type budgetedPage struct {
+	rows      int
+	bytes     int
+	truncated bool
+}
+
+// fitByteBudget measures items against the call's byte budget
+func fitByteBudget[T any](items []T, o options) (budgetedPage, error) {
+	counter := &byteCounter{}
+	encoder := json.NewEncoder(counter)
+	used := EnvelopeBytes
+	for i := range items {
+		before := counter.n
+		if err := encoder.Encode(items[i]); err != nil {
+			return budgetedPage{}, fmt.Errorf("failed to encode item %d: %w", i, err)
+		}
+
+		// Encode ends each item with a newline; items after the first are comma-separated
+		size := counter.n - before - 1
+		if i > 0 {
+			size++
+		}
+		if i >= o.byteBudgetMinRows && used+size > o.byteBudget {
+			return budgetedPage{rows: i, bytes: used, truncated: true}, nil
+		}
+		used += size
+	}
+	return budgetedPage{rows: len(items), bytes: used}, nil
+}
+
+// applyByteBudget cuts items to the call's byte budget, reporting whether rows were left out
+// and the page's size (0 without a budget)
+func applyByteBudget[T any](items []T, hasNext bool, o options) ([]T, bool, int, error) {
+	if o.byteBudget < 1 {
+		return items, hasNext, 0, nil
+	}
+	fit, err := fitByteBudget(items, o)
+	if err != nil {
+		return nil, false, 0, err
+	}
+	if fit.truncated {
+		return items[:fit.rows], true, fit.bytes, nil
+	}
+	return items, hasNext, fit.bytes, nil
+}
+
+// byteCounter is an io.Writer counting the bytes written to it
+type byteCounter struct {
+	n int
+}
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	c.n += len(p)
+	return len(p), nil
+}
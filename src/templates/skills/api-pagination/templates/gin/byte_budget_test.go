@@ -0,0 +1,161 @@
+package pagination
+
+import (
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// post is a row whose body makes its size vary
+type post struct {
+	ID   int64
+	Body string
+}
+
+// postsDB serves posts (given in id order) that pass every cursor condition of the page
+// query, sorted by its whole ORDER BY as the database would
+// Clauses left over from an earlier query narrow or reorder the result, as they would in SQL.
+func postsDB(t *testing.T, posts []post) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Callback().Query().Register("test:posts", func(tx *gorm.DB) {
+		dest, ok := tx.Statement.Dest.(*[]post)
+		if !ok {
+			return
+		}
+		conditions := postConditions(tx.Statement)
+		var descending []bool
+		if c, ok := tx.Statement.Clauses["ORDER BY"]; ok {
+			for _, column := range c.Expression.(clause.OrderBy).Columns {
+				descending = append(descending, column.Desc || strings.HasSuffix(column.Column.Name, "DESC"))
+			}
+		}
+
+		ordered := append([]post(nil), posts...)
+		sort.SliceStable(ordered, func(i, j int) bool {
+			// Every column is an id column; the first one that tells two posts apart decides
+			for _, desc := range descending {
+				if ordered[i].ID != ordered[j].ID {
+					return (ordered[i].ID > ordered[j].ID) == desc
+				}
+			}
+			return false
+		})
+		limit := *tx.Statement.Clauses["LIMIT"].Expression.(clause.Limit).Limit
+	next:
+		for _, p := range ordered {
+			for _, past := range conditions {
+				if !past(p) {
+					continue next
+				}
+			}
+			if len(*dest) < limit {
+				*dest = append(*dest, p)
+			}
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db.Model(&post{})
+}
+
+// postConditions reads the cursor conditions of a posts query as filters
+func postConditions(stmt *gorm.Statement) []func(p post) bool {
+	var conditions []func(p post) bool
+	if c, ok := stmt.Clauses["WHERE"]; ok {
+		for _, e := range c.Expression.(clause.Where).Exprs {
+			if expr, ok := e.(clause.Expr); ok && len(expr.Vars) > 0 {
+				bound := expr.Vars[0].(int64)
+				if strings.Contains(expr.SQL, "<") {
+					conditions = append(conditions, func(p post) bool { return p.ID < bound })
+				} else {
+					conditions = append(conditions, func(p post) bool { return p.ID > bound })
+				}
+			}
+		}
+	}
+	return conditions
+}
+
+// postsOf returns n posts with bodies of size bytes
+func postsOf(n, size int) []post {
+	posts := make([]post, n)
+	for i := range posts {
+		posts[i] = post{ID: int64(i + 1), Body: strings.Repeat("x", size)}
+	}
+	return posts
+}
+
+func TestByteBudgetCutsPage(t *testing.T) {
+	db := postsDB(t, postsOf(10, 300))
+	budget := EnvelopeBytes + 1000
+
+	var page []post
+	result, err := CursorPaginateInt(db, &page, "", 20, "id", true, WithByteBudget(budget, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Items) != 3 || !result.HasNext {
+		t.Fatalf("%d rows (has next %t), want the 3 rows fitting ~1000 bytes", len(result.Items), result.HasNext)
+	}
+	if result.RowCount != 3 || result.PageBytes <= EnvelopeBytes || result.PageBytes > budget {
+		t.Errorf("row count %d, page bytes %d, want 3 rows within %d bytes", result.RowCount, result.PageBytes, budget)
+	}
+
+	// The next page starts at the first row left out
+	next, err := CursorPaginateInt(db, &page, *result.EndCursor, 20, "id", true, WithByteBudget(budget, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.Items[0].ID != 4 {
+		t.Errorf("next page starts at %d, want 4", next.Items[0].ID)
+	}
+}
+
+func TestByteBudgetKeepsMinimumRows(t *testing.T) {
+	db := postsDB(t, postsOf(10, 5000))
+
+	var page []post
+	result, err := CursorPaginateInt(db, &page, "", 20, "id", true, WithByteBudget(1000, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Items) != 2 || !result.HasNext {
+		t.Errorf("%d rows (has next %t), want the 2-row floor over budget", len(result.Items), result.HasNext)
+	}
+}
+
+func TestByteBudgetOnlyWhenRequested(t *testing.T) {
+	db := postsDB(t, postsOf(10, 300))
+
+	var page []post
+	result, err := CursorPaginateInt(db, &page, "", 5, "id", true, WithByteBudget(0, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Items) != 5 || result.PageBytes != 0 || result.RowCount != 0 {
+		t.Errorf("%d rows, %d bytes, row count %d, want a plain 5-row page", len(result.Items), result.PageBytes, result.RowCount)
+	}
+}
+
+func TestMaxBytesHeader(t *testing.T) {
+	req := httptest.NewRequest("GET", "/posts", nil)
+	req.Header.Set(MaxBytesHeader, "4096")
+	if got := ParamsFromRequest(req).MaxBytes; got != 4096 {
+		t.Errorf("MaxBytes = %d from the header, want 4096", got)
+	}
+
+	req = httptest.NewRequest("GET", "/posts?max_bytes=2048", nil)
+	req.Header.Set(MaxBytesHeader, "4096")
+	if got := ParamsFromRequest(req).MaxBytes; got != 2048 {
+		t.Errorf("MaxBytes = %d, want the query's 2048", got)
+	}
+}
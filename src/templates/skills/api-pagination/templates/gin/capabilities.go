@@ -0,0 +1,74 @@
+package pagination
+
+import (
+	"context"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Pagination modes listed in Capabilities
+const (
+	ModeOffset = "offset"
+	ModeCursor = "cursor"
+)
+
+// Capabilities is a self-describing summary of how an endpoint paginates, for clients that
+// configure themselves from the API instead of hardcoding limits and field names
+type Capabilities struct {
+	Modes           []string            `json:"modes"`
+	MaxPageSize     int                 `json:"max_page_size"`
+	DefaultPageSize int                 `json:"default_page_size"`
+	AllowedSorts    []string            `json:"allowed_sorts"`
+	AllowedFilters  map[string][]string `json:"allowed_filters"`
+}
+
+// NewCapabilities describes an endpoint paginating with modes (ModeOffset, ModeCursor) under
+// the request's page size limit (MaxPageSizeFor) and the configured default page size
+// Add the endpoint's sort and filter allowlists with WithSorts and WithFilters.
+//
+// Example usage:
+//
+//	sorts, _ := sorting.SchemaFor[Product]()
+//	filters, _ := filtering.SchemaFor[Product]()
+//
+//	r.OPTIONS("/products", func(c *gin.Context) {
+//	    caps := pagination.NewCapabilities(c.Request.Context(), pagination.ModeOffset, pagination.ModeCursor).
+//	        WithSorts(sorts.Names()).
+//	        WithFilters(filters.AllowedOperators())
+//	    pagination.WriteCapabilities(c, caps)
+//	})
+func NewCapabilities(ctx context.Context, modes ...string) Capabilities {
+	return Capabilities{
+		Modes:           append([]string{}, modes...),
+		MaxPageSize:     MaxPageSizeFor(ctx),
+		DefaultPageSize: CurrentConfig().DefaultPageSize,
+		AllowedSorts:    []string{},
+		AllowedFilters:  map[string][]string{},
+	}
+}
+
+// WithSorts returns c listing the sortable field names, in alphabetical order
+func (c Capabilities) WithSorts(names []string) Capabilities {
+	c.AllowedSorts = append([]string{}, names...)
+	sort.Strings(c.AllowedSorts)
+	return c
+}
+
+// WithFilters returns c listing the filterable fields and the operators each accepts
+func (c Capabilities) WithFilters(filters map[string][]string) Capabilities {
+	c.AllowedFilters = make(map[string][]string, len(filters))
+	for name, ops := range filters {
+		c.AllowedFilters[name] = append([]string{}, ops...)
+	}
+	return c
+}
+
+// WriteCapabilities writes caps as JSON; an OPTIONS request also gets an Allow header
+func WriteCapabilities(c *gin.Context, caps Capabilities) {
+	if c.Request.Method == http.MethodOptions {
+		c.Header("Allow", "GET, HEAD, OPTIONS")
+	}
+	c.JSON(http.StatusOK, caps)
+}
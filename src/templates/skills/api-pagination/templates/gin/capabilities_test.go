@@ -0,0 +1,53 @@
+package pagination
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestCapabilitiesReflectAllowlists(t *testing.T) {
+	caps := NewCapabilities(context.Background(), ModeOffset, ModeCursor).
+		WithSorts([]string{"name", "created_at"}).
+		WithFilters(map[string][]string{"price": {"gte", "lte"}, "category_id": {"eq", "null"}})
+
+	raw, err := json.Marshal(caps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Modes          []string            `json:"modes"`
+		MaxPageSize    int                 `json:"max_page_size"`
+		AllowedSorts   []string            `json:"allowed_sorts"`
+		AllowedFilters map[string][]string `json:"allowed_filters"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(doc.Modes, []string{"offset", "cursor"}) {
+		t.Errorf("modes = %v", doc.Modes)
+	}
+	if doc.MaxPageSize != MaxPageSize {
+		t.Errorf("max_page_size = %d, want %d", doc.MaxPageSize, MaxPageSize)
+	}
+	if !reflect.DeepEqual(doc.AllowedSorts, []string{"created_at", "name"}) {
+		t.Errorf("allowed_sorts = %v", doc.AllowedSorts)
+	}
+	if !reflect.DeepEqual(doc.AllowedFilters["category_id"], []string{"eq", "null"}) || len(doc.AllowedFilters) != 2 {
+		t.Errorf("allowed_filters = %v", doc.AllowedFilters)
+	}
+
+	// Endpoints without allowlists still serve an array and an object, never null
+	raw, err = json.Marshal(NewCapabilities(context.Background(), ModeCursor))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := fmt.Sprintf(`{"modes":["cursor"],"max_page_size":%d,"default_page_size":%d,"allowed_sorts":[],"allowed_filters":{}}`,
+		MaxPageSize, CurrentConfig().DefaultPageSize)
+	if string(raw) != want {
+		t.Errorf("empty capabilities = %s, want %s", raw, want)
+	}
+}
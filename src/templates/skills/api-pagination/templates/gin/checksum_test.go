@@ -0,0 +1,81 @@
+package pagination
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// versionedDoc is a row whose version is bumped on every write
+type versionedDoc struct {
+	ID      int64
+	Title   string
+	Version int
+}
+
+// docsChecksum returns the checksum of an offset page of docs
+func docsChecksum(docs []versionedDoc, versionField string) string {
+	page := &OffsetPagination[versionedDoc]{Items: docs, CurrentPage: 1, PageSize: 20}
+	return page.ToResponse("", WithPageChecksum(versionField)).Pagination.PageChecksum
+}
+
+func TestPageChecksumTracksVersions(t *testing.T) {
+	docs := func() []versionedDoc {
+		return []versionedDoc{
+			{ID: 1, Title: "a", Version: 1},
+			{ID: 2, Title: "b", Version: 4},
+		}
+	}
+	base := docsChecksum(docs(), "version")
+	if base == "" {
+		t.Fatal("no checksum")
+	}
+
+	// Unchanged rows keep the checksum, whatever their other columns hold
+	same := docs()
+	same[0].Title = "unversioned edit"
+	if got := docsChecksum(same, "version"); got != base {
+		t.Errorf("checksum of an unchanged page = %s, want %s", got, base)
+	}
+
+	bumped := docs()
+	bumped[1].Version = 5
+	if got := docsChecksum(bumped, "version"); got == base {
+		t.Error("checksum unchanged after a version bump")
+	}
+
+	reordered := docs()
+	reordered[0], reordered[1] = reordered[1], reordered[0]
+	if got := docsChecksum(reordered, "version"); got == base {
+		t.Error("checksum unchanged after the page was reordered")
+	}
+
+	if got := docsChecksum(docs()[:1], "version"); got == base {
+		t.Error("checksum unchanged after a row left the page")
+	}
+}
+
+func TestPageChecksumOptional(t *testing.T) {
+	page := &CursorPagination[versionedDoc]{Items: []versionedDoc{
+		{ID: 1, Version: 1},
+	}}
+	raw, err := json.Marshal(page.ToResponse(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body struct {
+		Pagination map[string]any `json:"pagination"`
+	}
+	if err := json.Unmarshal(raw, &body); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := body.Pagination["page_checksum"]; ok {
+		t.Errorf("page_checksum without WithPageChecksum: %s", raw)
+	}
+
+	if got := page.ToResponse("", WithPageChecksum("")).Pagination.PageChecksum; got == "" {
+		t.Error("no checksum over primary keys alone")
+	}
+	if got := page.ToResponse("", WithPageChecksum("revision")).Pagination.PageChecksum; got != "" {
+		t.Errorf("checksum %q over an unknown version field", got)
+	}
+}
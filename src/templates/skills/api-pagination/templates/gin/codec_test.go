@@ -0,0 +1,94 @@
+package pagination
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// hexCursorCodec writes cursors as hex text behind a version prefix, the way an application
+// swaps in its own token format, and counts its calls
+type hexCursorCodec struct {
+	encoded, decoded *int
+}
+
+func (c hexCursorCodec) Encode(value any) (string, error) {
+	*c.encoded++
+	return "v1." + hex.EncodeToString([]byte(fmt.Sprint(value))), nil
+}
+
+func (c hexCursorCodec) Decode(cursor string) (any, error) {
+	*c.decoded++
+	raw, ok := strings.CutPrefix(cursor, "v1.")
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown cursor version", ErrInvalidCursor)
+	}
+	text, err := hex.DecodeString(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	return string(text), nil
+}
+
+func TestCursorPaginateIntWithCustomCodec(t *testing.T) {
+	db := postsDB(t, postsOf(5, 1))
+	var encoded, decoded int
+	codec := WithCursorCodec(hexCursorCodec{encoded: &encoded, decoded: &decoded})
+
+	var page []post
+	first, err := CursorPaginateInt(db, &page, "", 2, "id", true, codec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.NextCursor == nil || *first.NextCursor != "v1."+hex.EncodeToString([]byte("2")) {
+		t.Fatalf("next cursor = %v, want the hex codec's token for 2", first.NextCursor)
+	}
+	if encoded == 0 || decoded != 0 {
+		t.Errorf("codec encoded %d and decoded %d times on the first page", encoded, decoded)
+	}
+
+	second, err := CursorPaginateInt(db, &page, *first.NextCursor, 2, "id", true, codec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(postIDs(second.Items), []int64{3, 4}) || decoded != 1 {
+		t.Errorf("second page = %v after %d decodes, want 3 and 4 through the codec", postIDs(second.Items), decoded)
+	}
+
+	if _, err := CursorPaginateInt(db, &page, "v0.32", 2, "id", true, codec); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("err = %v, want the codec's ErrInvalidCursor", err)
+	}
+}
+
+func TestCursorPaginateIntWithDefaultCodec(t *testing.T) {
+	db := postsDB(t, postsOf(5, 1))
+
+	var page []post
+	first, err := CursorPaginateInt(db, &page, "", 2, "id", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, err := DecodeCursor(*first.NextCursor, "id", true); err != nil || value != "2" {
+		t.Fatalf("next cursor decodes to %q (%v), want 2", value, err)
+	}
+
+	second, err := CursorPaginateInt(db, &page, *first.NextCursor, 2, "id", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(postIDs(second.Items), []int64{3, 4}) {
+		t.Errorf("second page = %v, want 3 and 4", postIDs(second.Items))
+	}
+
+	// A nil codec keeps the default
+	again, err := CursorPaginateInt(db, &page, "", 2, "id", true, WithCursorCodec(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *again.NextCursor != *first.NextCursor {
+		t.Errorf("next cursor with a nil codec = %q, want the default %q", *again.NextCursor, *first.NextCursor)
+	}
+}
@@ -0,0 +1,125 @@
+package pagination
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// Compact cursor values start with a mode byte: the full value, or a truncated one
+const (
+	compactFull      = "v"
+	compactTruncated = "c"
+
+	// compactHashLen is the hex length of the value hash a truncated value carries
+	compactHashLen = 8
+)
+
+// compactKey formats a cursor value for WithCompactCursor: values up to prefixLen bytes are
+// kept whole, longer ones become a hash of the value and its first prefixLen bytes
+func compactKey(value any, prefixLen int) string {
+	text, ok := value.(string)
+	if !ok {
+		text = fmt.Sprint(value)
+	}
+	if len(text) <= prefixLen {
+		return compactFull + text
+	}
+
+	// Cut on a rune boundary so the prefix stays valid UTF-8
+	prefix := text[:prefixLen]
+	for len(prefix) > 0 && !utf8.ValidString(prefix) {
+		prefix = prefix[:len(prefix)-1]
+	}
+	return compactTruncated + compactHash(text) + ":" + prefix
+}
+
+// compactHash is the short hash a truncated value is checked against
+func compactHash(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])[:compactHashLen]
+}
+
+// compactValueLookup reads the cursor field of the row whose tie-breaker is tieValue; ok is
+// false when the row no longer exists
+type compactValueLookup func(tieValue any) (value string, ok bool, err error)
+
+// compactSeek resolves a WithCompactCursor value to the value to seek past
+// A truncated value is restored from its row, found by the tie-breaker; exact is false when
+// the row is gone or its value changed since the cursor was issued, and the prefix is returned
+// to seek from instead.
+func compactSeek(value string, tieValue any, hasTie bool, lookup compactValueLookup) (seek string, exact bool, err error) {
+	switch {
+	case strings.HasPrefix(value, compactFull):
+		return strings.TrimPrefix(value, compactFull), true, nil
+	case !strings.HasPrefix(value, compactTruncated):
+		return "", false, fmt.Errorf("%w: not a compact cursor", ErrInvalidCursor)
+	}
+
+	hash, prefix, found := strings.Cut(strings.TrimPrefix(value, compactTruncated), ":")
+	if !found || len(hash) != compactHashLen {
+		return "", false, fmt.Errorf("%w: malformed compact cursor", ErrInvalidCursor)
+	}
+	if !hasTie {
+		return "", false, fmt.Errorf("%w: compact cursor without a tie-breaker", ErrInvalidCursor)
+	}
+
+	full, ok, err := lookup(tieValue)
+	if err != nil {
+		return "", false, err
+	}
+	if ok && strings.HasPrefix(full, prefix) && compactHash(full) == hash {
+		return full, true, nil
+	}
+	return prefix, false, nil
+}
+
+// prefixCondition is the WHERE condition of an inexact compact cursor: every row from prefix
+// on, which repeats rows already served past the prefix but never skips one
+// Descending pages compare against the prefix extended by the highest rune, which sorts after
+// every value starting with it.
+func prefixCondition(cursorField string, prefix string, ascending bool) (string, string) {
+	if ascending {
+		return cursorField + " >= ?", prefix
+	}
+	return cursorField + " <= ?", prefix + string(utf8.MaxRune)
+}
+
+// applyCompactCursor filters query past the WithCompactCursor value of a cursor
+// base is the query before the cursor filter; it finds the row of a truncated value.
+func applyCompactCursor(query, base *gorm.DB, value, cursorField string, tie *tieBreaker, tieValue any, hasTie, ascending bool, o options) (*gorm.DB, error) {
+	lookup := func(tieValue any) (string, bool, error) {
+		var values []string
+		err := o.fetchLimiter.do(queryContext(base), func() error {
+			return o.onFetchDB(base).Session(&gorm.Session{}).
+				Where(tie.column+" = ?", tieValue).
+				Limit(1).
+				Pluck(cursorField, &values).Error
+		})
+		if err != nil {
+			return "", false, fmt.Errorf("failed to resolve compact cursor: %w", err)
+		}
+		if len(values) == 0 {
+			return "", false, nil
+		}
+		return values[0], true, nil
+	}
+
+	seek, exact, err := compactSeek(value, tieValue, hasTie, lookup)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case !exact:
+		condition, arg := prefixCondition(cursorField, seek, ascending)
+		return query.Where(condition, arg), nil
+	case hasTie:
+		return query.Where(tieBreakerCondition(cursorField, tie.column, ascending, o.inclusiveCursor), seek, seek, tieValue), nil
+	default:
+		return query.Where(cursorCondition(cursorField, ascending, o.inclusiveCursor), seek), nil
+	}
+}
@@ -0,0 +1,157 @@
+package pagination
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// article is ordered by a long title shared in part by every row; ID is its primary key
+type article struct {
+	ID    int64
+	Title string
+}
+
+// longTitles returns articles 1-6, sorted by title then ID, whose titles differ only after a
+// 200-byte prefix; articles 3 and 4 share a title
+func longTitles() []article {
+	prefix := strings.Repeat("quarterly billing report ", 8)
+	var rows []article
+	for i, suffix := range []string{"a", "b", "c", "c", "d", "e"} {
+		rows = append(rows, article{ID: int64(i + 1), Title: prefix + suffix})
+	}
+	return rows
+}
+
+// fakeCompactPage pages rows the way CursorPaginateString does on title under
+// WithCompactCursor, resolving truncated cursors through rows by ID; it returns the page and
+// its end cursor
+func fakeCompactPage(t *testing.T, rows []article, cursor string, pageSize, prefixLen int) ([]article, string) {
+	t.Helper()
+	codec := DefaultCursorCodec
+
+	lookup := func(tieValue any) (string, bool, error) {
+		for _, row := range rows {
+			if row.ID == tieValue.(int64) {
+				return row.Title, true, nil
+			}
+		}
+		return "", false, nil
+	}
+
+	var seek string
+	var exact bool
+	var afterID int64
+	if cursor != "" {
+		page, tieValue, ok, err := splitTieBreaker(cursor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, err := codec.Decode(page)
+		if err != nil {
+			t.Fatal(err)
+		}
+		value, err := cursorString(decoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seek, exact, err = compactSeek(value, tieValue, ok, lookup); err != nil {
+			t.Fatal(err)
+		}
+		afterID = tieValue.(int64)
+	}
+
+	var items []article
+	for _, row := range rows {
+		if cursor != "" {
+			past := row.Title > seek || (row.Title == seek && row.ID > afterID)
+			if !exact {
+				past = row.Title >= seek
+			}
+			if !past {
+				continue
+			}
+		}
+		if len(items) < pageSize {
+			items = append(items, row)
+		}
+	}
+	if len(items) == 0 {
+		return nil, ""
+	}
+
+	last := items[len(items)-1]
+	end, err := codec.Encode(compactKey(last.Title, prefixLen))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tieToken, err := JSONCursorCodec{}.Encode(last.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return items, end + tieBreakerSeparator + tieToken
+}
+
+func TestCompactCursorsPaginateLongValues(t *testing.T) {
+	rows := longTitles()
+
+	var seen []int64
+	cursor := ""
+	for pages := 0; pages < len(rows); pages++ {
+		items, end := fakeCompactPage(t, rows, cursor, 2, 16)
+		for _, row := range items {
+			seen = append(seen, row.ID)
+		}
+		if len(items) < 2 {
+			break
+		}
+
+		full := encodeValueCursor(items[len(items)-1].Title)
+		if len(end) >= len(full) {
+			t.Errorf("compact cursor is %d bytes, want fewer than the %d of the full value", len(end), len(full))
+		}
+		cursor = end
+	}
+	if want := []int64{1, 2, 3, 4, 5, 6}; !reflect.DeepEqual(seen, want) {
+		t.Fatalf("ids seen = %v, want every row once: %v", seen, want)
+	}
+}
+
+func TestCompactCursorFallsBackToPrefixWhenRowIsGone(t *testing.T) {
+	rows := longTitles()
+	first, cursor := fakeCompactPage(t, rows, "", 2, 16)
+	if len(first) != 2 {
+		t.Fatalf("first page = %v", first)
+	}
+
+	// The cursor's row was deleted: the next page starts at the shared prefix, repeating the
+	// first page's remaining row rather than skipping anything
+	remaining := append([]article{rows[0]}, rows[2:]...)
+	items, _ := fakeCompactPage(t, remaining, cursor, 2, 16)
+	if len(items) != 2 || items[0].ID != 1 || items[1].ID != 3 {
+		t.Errorf("after a deleted cursor row = %v, want a repeat from the prefix", items)
+	}
+}
+
+func TestCompactKey(t *testing.T) {
+	if key := compactKey("short", 16); key != "vshort" {
+		t.Errorf("short value = %q, want it kept whole", key)
+	}
+
+	// A prefix never ends inside a multi-byte rune
+	key := compactKey(strings.Repeat("é", 20), 5)
+	seek, exact, err := compactSeek(key, int64(1), true, func(any) (string, bool, error) {
+		return "", false, nil
+	})
+	if err != nil || exact || seek != "éé" {
+		t.Errorf("truncated value seeks %q (exact %t, %v), want the whole runes of the prefix", seek, exact, err)
+	}
+
+	if _, _, err := compactSeek(key, nil, false, nil); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("truncated value without a tie-breaker: %v, want ErrInvalidCursor", err)
+	}
+	if _, _, err := compactSeek("plain", nil, false, nil); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("cursor issued without compaction: %v, want ErrInvalidCursor", err)
+	}
+}
@@ -0,0 +1,306 @@
+package pagination
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrNoCursorColumns is returned when CursorPaginateComposite is given no columns
+var ErrNoCursorColumns = errors.New("composite cursor pagination needs at least one column")
+
+// CursorColumn is one column of a composite cursor's order
+type CursorColumn struct {
+	Field      string
+	Descending bool
+}
+
+// rowValueDialects compare row values, e.g. (a, b) > (?, ?), in index order
+var rowValueDialects = map[string]bool{"postgres": true, "mysql": true, "sqlite": true}
+
+// CursorPaginateComposite paginates by several columns, ended by a unique tie-breaker, for
+// orders whose leading columns repeat (many rows sharing a created_at)
+// Each page continues strictly after the last row's values of every column, so rows sharing
+// them on a page boundary are neither skipped nor repeated. The cursor carries all the values;
+// the tie-breaker follows the last column's direction. When every column has the same
+// direction and the dialect compares row values (Postgres, MySQL, SQLite), the boundary is
+// one row-value comparison the index can seek; otherwise it is the equivalent OR expansion.
+//
+// Columns are columns of T, as named in its table. WithBackward, WithByteBudget, WithPeek,
+// WithInclusiveCursor, and WithCursorCodec apply as they do to CursorPaginateInt.
+//
+// Example usage:
+//
+//	columns := []pagination.CursorColumn{
+//	    {Field: "created_at", Descending: true},
+//	}
+//	result, err := pagination.CursorPaginateComposite(db.Model(&Event{}), &events, c.Query("cursor"), 50, columns, "id")
+//	// ORDER BY created_at DESC, id DESC; WHERE (created_at, id) < (?, ?)
+func CursorPaginateComposite[T any](
+	db *gorm.DB,
+	dest *[]T,
+	cursor string,
+	pageSize int,
+	columns []CursorColumn,
+	tieBreaker string,
+	opts ...Option,
+) (*CursorPagination[T], error) {
+	if len(columns) == 0 {
+		return nil, ErrNoCursorColumns
+	}
+	if tieBreaker == "" {
+		return nil, ErrTieBreakerRequired
+	}
+	o := applyOptions(opts)
+	db = o.bindContext(db)
+
+	// Verify the cursor's signature before anything else reads it
+	if o.cursorSecret != nil && !o.cursorVerified {
+		return signCursors(cursor, o.cursorSecret, func(cursor string, verified Option) (*CursorPagination[T], error) {
+			return CursorPaginateComposite(db, dest, cursor, pageSize, columns, tieBreaker, append(opts[:len(opts):len(opts)], verified)...)
+		})
+	}
+
+	// Reject stale cursors; inside the signature, which covers their issue time
+	if o.cursorTTL > 0 && !o.cursorStamped {
+		return expireCursors(cursor, o.cursorTTL, func(cursor string, stamped Option) (*CursorPagination[T], error) {
+			return CursorPaginateComposite(db, dest, cursor, pageSize, columns, tieBreaker, append(opts[:len(opts):len(opts)], stamped)...)
+		})
+	}
+
+	// A PreviousCursor carries its direction: it pages backward without WithBackward
+	if rest, before := splitBackwardCursor(cursor); before {
+		cursor, o.backward = rest, true
+	}
+
+	if err := checkDestType[T](db); err != nil {
+		return nil, err
+	}
+
+	// The tie-breaker is the last column of the order
+	columns = append(columns[:len(columns):len(columns)], CursorColumn{Field: tieBreaker, Descending: columns[len(columns)-1].Descending})
+	extractors := make([]*fieldExtractor, len(columns))
+	for i, column := range columns {
+		extractor, err := newFieldExtractor[T](db, column.Field)
+		if err != nil {
+			return nil, err
+		}
+		extractors[i] = extractor
+	}
+
+	// Constrain page size
+	if limit := queryMaxPageSize(db); pageSize > limit {
+		pageSize = limit
+	}
+	if pageSize < 1 {
+		pageSize = o.config.DefaultPageSize
+	}
+
+	// Decode the cursor; a codec may send the request back to the first page
+	decodedCursor, restarted, err := decodeCursor(cursor, o)
+	if err != nil {
+		return nil, err
+	}
+	if restarted {
+		cursor = ""
+	}
+
+	// A backward page scans from the cursor toward the start of the order
+	scan := make([]CursorColumn, len(columns))
+	for i, column := range columns {
+		scan[i] = CursorColumn{Field: column.Field, Descending: column.Descending != o.backward}
+	}
+
+	// A fresh session, so the cursor condition and order never stick to the caller's db
+	query := db.Session(&gorm.Session{})
+	if cursor != "" {
+		values, err := compositeValues(decodedCursor, extractors)
+		if err != nil {
+			return nil, err
+		}
+		condition, args := compositeCondition(db, scan, values, o.inclusiveCursor)
+		query = query.Where(condition, args...)
+	}
+	for _, column := range scan {
+		query = query.Order(tieBreakerOrder(column.Field, !column.Descending))
+	}
+
+	// Fetch one extra item to check for next page
+	var items []T
+	err = o.fetchLimiter.do(queryContext(query), func() error {
+		return o.onFetchDB(query).Limit(sqlLimit(query, pageSize+1)).Find(&items).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch items: %w", err)
+	}
+
+	hasNext := len(items) > pageSize
+	fetched := items
+	if hasNext {
+		items = items[:pageSize]
+	}
+
+	// Cut the page at the client's byte budget
+	items, hasNext, pageBytes, err := applyByteBudget(items, hasNext, o)
+	if err != nil {
+		return nil, err
+	}
+
+	// A backward page was read nearest the cursor first
+	hasPrevious := cursor != ""
+	if o.backward {
+		hasNext, hasPrevious = cursor != "", hasNext
+		reverseItems(items)
+	}
+
+	items = nonNilItems(items)
+	*dest = items
+
+	result := &CursorPagination[T]{
+		Items:       items,
+		HasNext:     hasNext,
+		HasPrevious: hasPrevious,
+		PageSize:    pageSize,
+		Restarted:   restarted,
+	}
+	if len(items) > 0 {
+		ctx := queryContext(db)
+		first, last := make([]any, len(columns)), make([]any, len(columns))
+		for i, extractor := range extractors {
+			first[i], last[i] = utcTime(extractor.value(ctx, items[0])), utcTime(extractor.value(ctx, items[len(items)-1]))
+		}
+		result.FirstKey, result.LastKey = first, last
+
+		if result.StartCursor, err = encodeComposite(o.cursorCodec(), first); err != nil {
+			return nil, err
+		}
+		if result.EndCursor, err = encodeComposite(o.cursorCodec(), last); err != nil {
+			return nil, err
+		}
+	}
+	if hasNext {
+		result.NextCursor = copyCursor(result.EndCursor)
+	}
+	if hasPrevious {
+		result.PreviousCursor = markBackwardCursor(result.StartCursor)
+	}
+	if o.byteBudget > 0 {
+		result.PageBytes, result.RowCount = pageBytes, len(items)
+	}
+	if o.peek && hasNext && !o.backward {
+		preview := fetched[len(items)]
+		result.NextPreview = &preview
+	}
+	return result, nil
+}
+
+// compositeCondition is the WHERE condition continuing after values in the columns' order
+// Uniform directions use a row-value comparison where the dialect has one; mixed directions
+// (or other dialects) expand to (a > ?) OR (a = ? AND b > ?) OR ...
+func compositeCondition(db *gorm.DB, columns []CursorColumn, values []any, inclusive bool) (string, []any) {
+	op := func(column CursorColumn) string {
+		if column.Descending {
+			return "<"
+		}
+		return ">"
+	}
+
+	uniform := true
+	for _, column := range columns[1:] {
+		uniform = uniform && column.Descending == columns[0].Descending
+	}
+	if uniform && db.Dialector != nil && rowValueDialects[db.Dialector.Name()] {
+		fields := make([]string, len(columns))
+		marks := make([]string, len(columns))
+		for i, column := range columns {
+			fields[i], marks[i] = column.Field, "?"
+		}
+		comparison := op(columns[0])
+		if inclusive {
+			comparison += "="
+		}
+		return fmt.Sprintf("(%s) %s (%s)", strings.Join(fields, ", "), comparison, strings.Join(marks, ", ")), values
+	}
+
+	var terms []string
+	var args []any
+	for i, column := range columns {
+		var term []string
+		for j := 0; j < i; j++ {
+			term = append(term, columns[j].Field+" = ?")
+			args = append(args, values[j])
+		}
+		comparison := op(column)
+		if inclusive && i == len(columns)-1 {
+			comparison += "="
+		}
+		term = append(term, fmt.Sprintf("%s %s ?", column.Field, comparison))
+		args = append(args, values[i])
+		terms = append(terms, "("+strings.Join(term, " AND ")+")")
+	}
+	return "(" + strings.Join(terms, " OR ") + ")", args
+}
+
+// encodeComposite encodes a row's column values as one cursor: their JSON array, passed
+// through the codec (times as RFC 3339 in UTC)
+func encodeComposite(codec CursorCodec, values []any) (*string, error) {
+	formatted := make([]any, len(values))
+	for i, value := range values {
+		formatted[i] = timeCursorValue(value)
+	}
+	raw, err := json.Marshal(formatted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	cursor, err := codec.Encode(string(raw))
+	if err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
+
+// compositeValues decodes a composite cursor's values, converted to the columns' field types
+// so drivers compare them as such (times are bound as time.Time, integers as int64)
+func compositeValues(decoded any, extractors []*fieldExtractor) ([]any, error) {
+	text, err := cursorString(decoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w value: %v", ErrInvalidCursor, err)
+	}
+	var raw []json.RawMessage
+	if err := json.Unmarshal([]byte(text), &raw); err != nil || len(raw) != len(extractors) {
+		return nil, fmt.Errorf("%w: want %d composite values", ErrInvalidCursor, len(extractors))
+	}
+
+	values := make([]any, len(raw))
+	for i, extractor := range extractors {
+		fieldType := extractor.field.FieldType
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		var value any
+		switch {
+		case fieldType == reflect.TypeOf(time.Time{}):
+			var text string
+			if err = json.Unmarshal(raw[i], &text); err == nil {
+				value, err = cursorTime(text)
+			}
+		case fieldType.Kind() >= reflect.Int && fieldType.Kind() <= reflect.Uint64:
+			var number json.Number
+			if err = json.Unmarshal(raw[i], &number); err == nil {
+				value, err = cursorInt(number)
+			}
+		default:
+			err = json.Unmarshal(raw[i], &value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w value %d: %v", ErrInvalidCursor, i, err)
+		}
+		values[i] = value
+	}
+	return values, nil
+}
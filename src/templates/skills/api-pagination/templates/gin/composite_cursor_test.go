@@ -0,0 +1,210 @@
+package pagination
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type logEntry struct {
+	ID        int64
+	CreatedAt time.Time
+}
+
+// burstEntries are 14 entries, 10 of them sharing one created_at, with ids out of time order
+func burstEntries() []logEntry {
+	burst := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	entries := []logEntry{
+		{ID: 40, CreatedAt: burst.Add(-time.Second)},
+		{ID: 3, CreatedAt: burst.Add(-time.Second)},
+		{ID: 50, CreatedAt: burst.Add(time.Second)},
+		{ID: 1, CreatedAt: burst.Add(time.Second)},
+	}
+	for _, id := range []int64{17, 5, 29, 11, 23, 2, 31, 7, 19, 13} {
+		entries = append(entries, logEntry{ID: id, CreatedAt: burst})
+	}
+	return entries
+}
+
+// entriesDB serves entries for CursorPaginateComposite's queries by (created_at, id), reading
+// the boundary from the row-value comparison or the OR expansion
+func entriesDB(t *testing.T, entries []logEntry, dialect string) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dialect != "" {
+		db.Dialector = namedDialector{name: dialect}
+	}
+	err = db.Callback().Query().Register("test:entries", func(tx *gorm.DB) {
+		dest, ok := tx.Statement.Dest.(*[]logEntry)
+		if !ok {
+			return
+		}
+		descending := strings.HasSuffix(tx.Statement.Clauses["ORDER BY"].Expression.(clause.OrderBy).Columns[0].Column.Name, "DESC")
+		// Strict in both directions: a row never seeks past itself
+		less := func(a, b logEntry) bool {
+			if !a.CreatedAt.Equal(b.CreatedAt) {
+				return a.CreatedAt.Before(b.CreatedAt) != descending
+			}
+			if descending {
+				return a.ID > b.ID
+			}
+			return a.ID < b.ID
+		}
+
+		var after *logEntry
+		if c, ok := tx.Statement.Clauses["WHERE"]; ok {
+			expr := c.Expression.(clause.Where).Exprs[0].(clause.Expr)
+			if rowValue := strings.HasPrefix(expr.SQL, "(created_at, id)"); rowValue != (dialect == "sqlite") {
+				t.Errorf("condition %q for dialect %q", expr.SQL, dialect)
+			}
+			after = &logEntry{CreatedAt: expr.Vars[0].(time.Time), ID: expr.Vars[len(expr.Vars)-1].(int64)}
+		}
+
+		sorted := append([]logEntry(nil), entries...)
+		sort.Slice(sorted, func(i, j int) bool { return less(sorted[i], sorted[j]) })
+		limit := *tx.Statement.Clauses["LIMIT"].Expression.(clause.Limit).Limit
+		for _, e := range sorted {
+			if (after == nil || less(*after, e)) && len(*dest) < limit {
+				*dest = append(*dest, e)
+			}
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db.Model(&logEntry{})
+}
+
+func TestCursorPaginateCompositeKeepsBurstRows(t *testing.T) {
+	for _, dialect := range []string{"sqlite", ""} {
+		for _, descending := range []bool{false, true} {
+			t.Run(fmt.Sprintf("dialect=%q descending=%t", dialect, descending), func(t *testing.T) {
+				entries := burstEntries()
+				db := entriesDB(t, entries, dialect)
+				columns := []CursorColumn{
+					{Field: "created_at", Descending: descending},
+				}
+
+				var seen []int64
+				var page []logEntry
+				cursor := ""
+				for pages := 0; pages < 10; pages++ {
+					result, err := CursorPaginateComposite(db, &page, cursor, 3, columns, "id")
+					if err != nil {
+						t.Fatalf("page %d: %v", pages+1, err)
+					}
+					for _, e := range result.Items {
+						seen = append(seen, e.ID)
+					}
+					if result.NextCursor == nil {
+						break
+					}
+					cursor = *result.NextCursor
+				}
+
+				sort.Slice(entries, func(i, j int) bool {
+					a, b := entries[i], entries[j]
+					if !a.CreatedAt.Equal(b.CreatedAt) {
+						return a.CreatedAt.Before(b.CreatedAt) != descending
+					}
+					return (a.ID < b.ID) != descending
+				})
+				var want []int64
+				for _, e := range entries {
+					want = append(want, e.ID)
+				}
+				if !reflect.DeepEqual(seen, want) {
+					t.Errorf("paged through %v, want %v", seen, want)
+				}
+			})
+		}
+	}
+}
+
+func TestCompositeCondition(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	db, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Dialector = namedDialector{name: "postgres"}
+
+	for _, tc := range []struct {
+		name      string
+		columns   []CursorColumn
+		inclusive bool
+		want      string
+		args      int
+	}{
+		{
+			name: "uniform",
+			columns: []CursorColumn{
+				{Field: "created_at", Descending: true},
+				{Field: "id", Descending: true},
+			},
+			want: "(created_at, id) < (?, ?)",
+			args: 2,
+		},
+		{
+			name: "uniform inclusive",
+			columns: []CursorColumn{
+				{Field: "created_at"},
+				{Field: "id"},
+			},
+			inclusive: true,
+			want:      "(created_at, id) >= (?, ?)",
+			args:      2,
+		},
+		{
+			name: "mixed directions",
+			columns: []CursorColumn{
+				{Field: "priority", Descending: true},
+				{Field: "created_at"},
+				{Field: "id"},
+			},
+			want: "((priority < ?) OR (priority = ? AND created_at > ?) OR (priority = ? AND created_at = ? AND id > ?))",
+			args: 6,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			values := make([]any, len(tc.columns))
+			for i := range values {
+				values[i] = at
+			}
+			got, args := compositeCondition(db, tc.columns, values, tc.inclusive)
+			if got != tc.want || len(args) != tc.args {
+				t.Errorf("condition = %q with %d args, want %q with %d", got, len(args), tc.want, tc.args)
+			}
+		})
+	}
+}
+
+func TestCursorPaginateCompositeRejectsBadInput(t *testing.T) {
+	db := entriesDB(t, burstEntries(), "sqlite")
+	columns := []CursorColumn{
+		{Field: "created_at"},
+	}
+	var page []logEntry
+
+	if _, err := CursorPaginateComposite(db, &page, "", 3, nil, "id"); !errors.Is(err, ErrNoCursorColumns) {
+		t.Errorf("no columns: %v", err)
+	}
+	if _, err := CursorPaginateComposite(db, &page, "", 3, columns, ""); !errors.Is(err, ErrTieBreakerRequired) {
+		t.Errorf("no tie-breaker: %v", err)
+	}
+	for _, cursor := range []string{encodeValueCursor(`["2024-05-01T12:00:00Z"]`), encodeValueCursor(`["yesterday", 3]`), encodeValueCursor("17")} {
+		if _, err := CursorPaginateComposite(db, &page, cursor, 3, columns, "id"); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("cursor %q: %v, want ErrInvalidCursor", cursor, err)
+		}
+	}
+}
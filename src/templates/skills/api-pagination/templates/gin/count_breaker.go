@@ -0,0 +1,233 @@
+package pagination
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CountSkipped is the CountMode of an offset page whose count was shed by an open
+// CountBreaker, or timed out with no WithCountFallback fallback: TotalItems and TotalPages are
+// unknown (omitted from ToResponse), and HasNext comes from fetching one extra row
+const CountSkipped CountMode = "skipped"
+
+// BreakerState is the state of a CountBreaker
+type BreakerState int
+
+const (
+	// BreakerClosed counts normally (the initial state)
+	BreakerClosed BreakerState = iota
+
+	// BreakerOpen skips every count until the cool-down ends
+	BreakerOpen
+
+	// BreakerHalfOpen lets a few probe counts through to decide whether to close again
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// BreakerMetrics is implemented by Metrics that also record CountBreaker state transitions
+// The paginators call it for the WithMetrics of the request that caused the transition.
+type BreakerMetrics interface {
+	CountBreakerStateChanged(from, to BreakerState)
+}
+
+// CountBreakerConfig tunes a CountBreaker; zero fields take the defaults noted on them
+type CountBreakerConfig struct {
+	// Window is the period failures are counted over (default 10s)
+	Window time.Duration
+
+	// MinCounts is how many counts a window needs before it can open the breaker (default 10)
+	MinCounts int
+
+	// FailureRatio is the fraction of failed counts that opens the breaker (default 0.5)
+	FailureRatio float64
+
+	// CoolDown is how long counts are skipped once the breaker opens (default 30s)
+	CoolDown time.Duration
+
+	// Probes is how many half-open counts run at once, and must succeed to close (default 1)
+	Probes int
+
+	// OnStateChange is called on every transition, e.g. to log it (nil = not called)
+	OnStateChange func(from, to BreakerState)
+}
+
+// CountBreaker sheds the COUNT(*) of offset pages while the database is struggling
+// It tracks the failures of the counts it guards (errors and timeouts; counts canceled by
+// their client are ignored). Once enough fail within a window it opens, and for the cool-down
+// pages are served without a total (CountMode CountSkipped). Then it half-opens: probes count
+// again, and it closes once they succeed or reopens on a failure. Share one breaker across
+// requests with WithCountBreaker; without it, counts are never skipped.
+type CountBreaker struct {
+	cfg CountBreakerConfig
+	now func() time.Time
+
+	mu          sync.Mutex
+	state       BreakerState
+	windowStart time.Time
+	counts      int
+	failures    int
+	openedAt    time.Time
+	probing     int
+	probed      int
+}
+
+// NewCountBreaker creates a closed breaker
+//
+// Example usage:
+//
+//	var countBreaker = pagination.NewCountBreaker(pagination.CountBreakerConfig{
+//	    FailureRatio:  0.3,
+//	    CoolDown:      time.Minute,
+//	    OnStateChange: func(from, to pagination.BreakerState) { log.Printf("count breaker %s -> %s", from, to) },
+//	})
+func NewCountBreaker(cfg CountBreakerConfig) *CountBreaker {
+	if cfg.Window <= 0 {
+		cfg.Window = 10 * time.Second
+	}
+	if cfg.MinCounts < 1 {
+		cfg.MinCounts = 10
+	}
+	if cfg.FailureRatio <= 0 {
+		cfg.FailureRatio = 0.5
+	}
+	if cfg.CoolDown <= 0 {
+		cfg.CoolDown = 30 * time.Second
+	}
+	if cfg.Probes < 1 {
+		cfg.Probes = 1
+	}
+	return &CountBreaker{cfg: cfg, now: time.Now}
+}
+
+// State returns the breaker's current state
+func (b *CountBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// breakerTransition is a state change to report once the lock is released
+type breakerTransition struct {
+	from, to BreakerState
+}
+
+// allow reports whether a count may run, and whether it runs as a half-open probe
+func (b *CountBreaker) allow() (ok, probe bool, changed *breakerTransition) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerOpen {
+		if b.now().Sub(b.openedAt) < b.cfg.CoolDown {
+			return false, false, nil
+		}
+		changed = b.transition(BreakerHalfOpen)
+	}
+	if b.state == BreakerHalfOpen {
+		if b.probing >= b.cfg.Probes {
+			return false, false, changed
+		}
+		b.probing++
+		return true, true, changed
+	}
+	return true, false, changed
+}
+
+// record feeds the outcome of an allowed count back into the breaker
+// A count canceled by its client says nothing about the database and is ignored.
+func (b *CountBreaker) record(probe bool, err error) *breakerTransition {
+	canceled := errors.Is(err, context.Canceled)
+	failed := err != nil && !canceled
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if probe {
+		b.probing--
+		if b.state != BreakerHalfOpen || canceled {
+			return nil
+		}
+		if failed {
+			return b.transition(BreakerOpen)
+		}
+		if b.probed++; b.probed >= b.cfg.Probes {
+			return b.transition(BreakerClosed)
+		}
+		return nil
+	}
+
+	if b.state != BreakerClosed || canceled {
+		return nil
+	}
+	if now := b.now(); now.Sub(b.windowStart) >= b.cfg.Window {
+		b.windowStart, b.counts, b.failures = now, 0, 0
+	}
+	b.counts++
+	if failed {
+		b.failures++
+	}
+	if b.counts >= b.cfg.MinCounts && float64(b.failures) >= b.cfg.FailureRatio*float64(b.counts) {
+		return b.transition(BreakerOpen)
+	}
+	return nil
+}
+
+// transition moves the breaker to state, resetting what the new state tracks
+func (b *CountBreaker) transition(state BreakerState) *breakerTransition {
+	changed := &breakerTransition{from: b.state, to: state}
+	b.state = state
+	switch state {
+	case BreakerOpen:
+		b.openedAt = b.now()
+	case BreakerHalfOpen:
+		b.probed = 0
+	case BreakerClosed:
+		b.windowStart, b.counts, b.failures = b.now(), 0, 0
+	}
+	return changed
+}
+
+// report passes a transition to the breaker's OnStateChange and the call's BreakerMetrics
+func (b *CountBreaker) report(changed *breakerTransition, o options) {
+	if changed == nil {
+		return
+	}
+	if b.cfg.OnStateChange != nil {
+		b.cfg.OnStateChange(changed.from, changed.to)
+	}
+	if m, ok := o.metrics.(BreakerMetrics); ok {
+		m.CountBreakerStateChanged(changed.from, changed.to)
+	}
+}
+
+// breakerCount counts query through WithCountBreaker's breaker, if one is configured
+func breakerCount(query *gorm.DB, o options) (pageTotal, error) {
+	if o.countBreaker == nil {
+		items, atLeast, err := countTotal(query, o)
+		return pageTotal{items: items, atLeast: atLeast}, err
+	}
+
+	ok, probe, changed := o.countBreaker.allow()
+	o.countBreaker.report(changed, o)
+	if !ok {
+		return pageTotal{skipped: true}, nil
+	}
+
+	items, atLeast, err := countTotal(query, o)
+	o.countBreaker.report(o.countBreaker.record(probe, err), o)
+	return pageTotal{items: items, atLeast: atLeast}, err
+}
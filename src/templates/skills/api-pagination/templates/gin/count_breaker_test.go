@@ -0,0 +1,134 @@
+package pagination
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type recordingBreakerMetrics struct {
+	noopMetrics
+	transitions []string
+}
+
+func (m *recordingBreakerMetrics) CountBreakerStateChanged(from, to BreakerState) {
+	m.transitions = append(m.transitions, from.String()+"->"+to.String())
+}
+
+func TestCountBreakerStates(t *testing.T) {
+	now := time.Unix(0, 0)
+	var logged []string
+	b := NewCountBreaker(CountBreakerConfig{
+		MinCounts:     4,
+		FailureRatio:  0.5,
+		CoolDown:      time.Minute,
+		OnStateChange: func(from, to BreakerState) { logged = append(logged, to.String()) },
+	})
+	b.now = func() time.Time { return now }
+
+	count := func(err error) bool {
+		ok, probe, changed := b.allow()
+		b.report(changed, options{})
+		if ok {
+			b.report(b.record(probe, err), options{})
+		}
+		return ok
+	}
+
+	// Canceled requests do not count as failures
+	for i := 0; i < 4; i++ {
+		count(context.Canceled)
+	}
+	if b.State() != BreakerClosed {
+		t.Fatalf("canceled counts opened the breaker")
+	}
+
+	// Half of the window's counts time out: open
+	count(nil)
+	count(nil)
+	count(context.DeadlineExceeded)
+	count(context.DeadlineExceeded)
+	if b.State() != BreakerOpen {
+		t.Fatalf("state = %s, want open", b.State())
+	}
+	if count(nil) {
+		t.Error("count ran while open")
+	}
+
+	// After the cool-down one probe runs; a failed probe reopens
+	now = now.Add(time.Minute)
+	if !count(errors.New("still down")) || b.State() != BreakerOpen {
+		t.Fatalf("failed probe: state = %s, want open", b.State())
+	}
+	now = now.Add(time.Minute)
+	if !count(nil) || b.State() != BreakerClosed {
+		t.Fatalf("successful probe: state = %s, want closed", b.State())
+	}
+
+	want := "open half-open open half-open closed"
+	if got := strings.Join(logged, " "); got != want {
+		t.Errorf("transitions = %s, want %s", got, want)
+	}
+}
+
+func TestOffsetPageSkipsCountWhileOpen(t *testing.T) {
+	// Every request gets its own session whose count times out
+	counted := 0
+	newDB := func() *gorm.DB {
+		db, err := gorm.Open(nil, &gorm.Config{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = db.Callback().Query().Register("test:breaker", func(tx *gorm.DB) {
+			switch dest := tx.Statement.Dest.(type) {
+			case *[]event:
+				*dest = append((*dest)[:0], event{ID: 1}, event{ID: 2}, event{ID: 3})
+			case *int64:
+				counted++
+				tx.AddError(context.DeadlineExceeded)
+			}
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return db.Model(&event{})
+	}
+
+	metrics := &recordingBreakerMetrics{}
+	breaker := NewCountBreaker(CountBreakerConfig{MinCounts: 1})
+	opts := []Option{WithCountBreaker(breaker), WithMetrics(metrics)}
+
+	var events []event
+	if _, err := OffsetPaginate(newDB(), &events, 1, 2, opts...); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the failing count to fail the page, got %v", err)
+	}
+
+	result, err := OffsetPaginate(newDB(), &events, 1, 2, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counted != 1 || result.CountMode != CountSkipped {
+		t.Fatalf("counts %d, count mode %q", counted, result.CountMode)
+	}
+	if !result.HasNext || len(result.Items) != 2 {
+		t.Errorf("has next %t with %d items, want the extra row to report a next page", result.HasNext, len(result.Items))
+	}
+	if len(metrics.transitions) != 1 || metrics.transitions[0] != "closed->open" {
+		t.Errorf("metrics transitions = %v", metrics.transitions)
+	}
+
+	raw, err := json.Marshal(result.ToResponse("/events"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, absent := range []string{`"total_items"`, `"total_pages"`, `"last"`} {
+		if strings.Contains(string(raw), absent) {
+			t.Errorf("response has %s: %s", absent, raw)
+		}
+	}
+}
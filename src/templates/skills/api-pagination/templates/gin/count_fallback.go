@@ -0,0 +1,239 @@
+package pagination
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CountSource records where an offset page's total came from under WithCountFallback or
+// WithCounterSource
+type CountSource string
+
+const (
+	// CountSourceExact is a count that finished within the timeout
+	CountSourceExact CountSource = "exact"
+
+	// CountSourceCache is an earlier exact count of the same query, no older than CacheMaxAge
+	CountSourceCache CountSource = "cache"
+
+	// CountSourceEstimate is the query planner's row estimate
+	CountSourceEstimate CountSource = "estimate"
+
+	// CountSourceNone means every fallback failed; the page is served like a shed count
+	// (CountMode CountSkipped, no totals)
+	CountSourceNone CountSource = "none"
+
+	// CountSourceCounter is a maintained counter read by WithCounterSource
+	CountSourceCounter CountSource = "counter"
+)
+
+// CountFallback is a step tried, in order, when the exact count times out
+type CountFallback int
+
+const (
+	// FallbackCache reuses the cached count of the same query (needs CountFallbackPolicy.Cache)
+	FallbackCache CountFallback = iota
+
+	// FallbackEstimate asks the query planner (needs a dialect the Estimator supports)
+	FallbackEstimate
+)
+
+// CountEstimator estimates the rows of query; ok is false when the dialect cannot estimate
+type CountEstimator func(ctx context.Context, query *gorm.DB) (estimate int64, ok bool, err error)
+
+// CountFallbackPolicy bounds the offset count and says what to report when it times out
+type CountFallbackPolicy struct {
+	// Timeout is how long the exact count may run (default 2s)
+	Timeout time.Duration
+
+	// Budget caps the count and its fallbacks together; fallbacks still running at the
+	// budget are abandoned (default Timeout plus a quarter)
+	Budget time.Duration
+
+	// Fallbacks are tried in order after a timeout (nil = FallbackCache, then FallbackEstimate)
+	Fallbacks []CountFallback
+
+	// Cache keeps every exact count for CacheMaxAge (default 1m), for FallbackCache (nil = no
+	// cache step); counts are not indexed by table, so writes do not drop them
+	Cache       PageCache
+	CacheMaxAge time.Duration
+
+	// Estimator serves FallbackEstimate (nil = PlannerEstimate)
+	Estimator CountEstimator
+}
+
+// withDefaults fills the zero fields of p
+func (p CountFallbackPolicy) withDefaults() CountFallbackPolicy {
+	if p.Timeout <= 0 {
+		p.Timeout = 2 * time.Second
+	}
+	if p.Budget < p.Timeout {
+		p.Budget = p.Timeout + p.Timeout/4
+	}
+	if p.Fallbacks == nil {
+		p.Fallbacks = []CountFallback{FallbackCache, FallbackEstimate}
+	}
+	if p.CacheMaxAge <= 0 {
+		p.CacheMaxAge = time.Minute
+	}
+	if p.Estimator == nil {
+		p.Estimator = PlannerEstimate
+	}
+	return p
+}
+
+// PlannerEstimate reads Postgres's planner row estimate for query (EXPLAIN, never executed);
+// other dialects report ok false
+func PlannerEstimate(ctx context.Context, query *gorm.DB) (int64, bool, error) {
+	if query.Dialector == nil || query.Dialector.Name() != "postgres" {
+		return 0, false, nil
+	}
+
+	rendered := query.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Find(&[]map[string]any{})
+	})
+
+	var plan string
+	err := query.Session(&gorm.Session{NewDB: true}).WithContext(ctx).
+		Raw("EXPLAIN (FORMAT JSON) " + rendered).
+		Scan(&plan).Error
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to explain count query: %w", err)
+	}
+
+	var explained []struct {
+		Plan struct {
+			Rows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal([]byte(plan), &explained); err != nil {
+		return 0, false, fmt.Errorf("failed to read query plan: %w", err)
+	}
+	if len(explained) == 0 {
+		return 0, false, nil
+	}
+	return int64(explained[0].Plan.Rows), true, nil
+}
+
+// fallbackCount counts query within WithCountFallback's timeout, falling back along its
+// policy when the count times out (without a policy it is breakerCount)
+// A count canceled by the request itself, or failing otherwise, still fails the page.
+func fallbackCount(query *gorm.DB, o options) (pageTotal, error) {
+	if o.countFallback == nil {
+		return breakerCount(query, o)
+	}
+	policy := *o.countFallback
+	ctx := queryContext(query)
+	deadline := time.Now().Add(policy.Budget)
+
+	countCtx, cancel := context.WithTimeout(ctx, policy.Timeout)
+	total, err := breakerCount(query.WithContext(countCtx), o)
+	cancel()
+
+	var key string
+	if policy.Cache != nil {
+		key = "count:" + countQueryHash(query, o)
+	}
+	if err == nil {
+		if total.skipped {
+			return total, nil
+		}
+		total.source = CountSourceExact
+		if policy.Cache != nil {
+			storeFallbackCount(ctx, policy, key, total.items)
+		}
+		return total, nil
+	}
+	if !errors.Is(err, context.DeadlineExceeded) || ctx.Err() != nil {
+		return total, err
+	}
+
+	// Every fallback shares what is left of the budget
+	fallbackCtx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+
+	for _, step := range policy.Fallbacks {
+		if fallbackCtx.Err() != nil {
+			break
+		}
+		switch step {
+		case FallbackCache:
+			if policy.Cache == nil {
+				continue
+			}
+			if items, age, ok := loadFallbackCount(fallbackCtx, policy, key); ok {
+				return pageTotal{
+					items:       items,
+					approximate: true,
+					source:      CountSourceCache,
+					warning:     fmt.Sprintf("count timed out; total_items is a count from %s ago", age.Round(time.Second)),
+				}, nil
+			}
+		case FallbackEstimate:
+			if estimate, ok := boundedEstimate(fallbackCtx, policy.Estimator, query); ok {
+				return pageTotal{
+					items:       estimate,
+					approximate: true,
+					source:      CountSourceEstimate,
+					warning:     "count timed out; total_items is an estimate",
+				}, nil
+			}
+		}
+	}
+
+	return pageTotal{
+		skipped: true,
+		source:  CountSourceNone,
+		warning: "count timed out; total_items is unknown",
+	}, nil
+}
+
+// boundedEstimate runs estimator until ctx is done, abandoning an estimator that ignores ctx
+func boundedEstimate(ctx context.Context, estimator CountEstimator, query *gorm.DB) (int64, bool) {
+	type estimated struct {
+		rows int64
+		ok   bool
+	}
+	done := make(chan estimated, 1)
+	go func() {
+		rows, ok, err := estimator(ctx, query)
+		done <- estimated{rows: rows, ok: ok && err == nil}
+	}()
+
+	select {
+	case result := <-done:
+		return result.rows, result.ok
+	case <-ctx.Done():
+		return 0, false
+	}
+}
+
+// storeFallbackCount caches an exact count with the time it was taken; failures are dropped
+func storeFallbackCount(ctx context.Context, policy CountFallbackPolicy, key string, items int64) {
+	value := strconv.FormatInt(items, 10) + " " + strconv.FormatInt(time.Now().Unix(), 10)
+	_ = policy.Cache.Set(ctx, key, []byte(value), policy.CacheMaxAge, "")
+}
+
+// loadFallbackCount returns the cached count of key and its age
+func loadFallbackCount(ctx context.Context, policy CountFallbackPolicy, key string) (int64, time.Duration, bool) {
+	raw, ok, err := policy.Cache.Get(ctx, key)
+	if err != nil || !ok {
+		return 0, 0, false
+	}
+
+	var items, countedAt int64
+	if _, err := fmt.Sscanf(string(raw), "%d %d", &items, &countedAt); err != nil {
+		return 0, 0, false
+	}
+	age := time.Since(time.Unix(countedAt, 0))
+	if age > policy.CacheMaxAge {
+		return 0, 0, false
+	}
+	return items, age, true
+}
@@ -0,0 +1,157 @@
+package pagination
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// slowCountDB returns a database holding three events whose count takes until its context
+// is done while *slow is set, like a count cut off by a statement timeout
+func slowCountDB(t *testing.T, slow *bool) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Callback().Query().Register("test:slow_count", func(tx *gorm.DB) {
+		switch dest := tx.Statement.Dest.(type) {
+		case *[]event:
+			*dest = append((*dest)[:0], event{ID: 1}, event{ID: 2}, event{ID: 3})
+		case *int64:
+			if *slow {
+				<-tx.Statement.Context.Done()
+				tx.AddError(tx.Statement.Context.Err())
+				return
+			}
+			*dest = 42
+			tx.RowsAffected = 1
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db.Model(&event{})
+}
+
+func TestCountFallbackServesCachedCount(t *testing.T) {
+	slow := false
+	db := slowCountDB(t, &slow)
+	policy := CountFallbackPolicy{Timeout: 20 * time.Millisecond, Cache: NewMemoryPageCache(0)}
+
+	var events []event
+	result, err := OffsetPaginate(db, &events, 1, 2, WithCountFallback(policy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.CountSource != CountSourceExact || len(result.Warnings) != 0 {
+		t.Fatalf("fast count: source %q, warnings %v", result.CountSource, result.Warnings)
+	}
+
+	slow = true
+	started := time.Now()
+	result, err = OffsetPaginate(db, &events, 1, 2, WithCountFallback(policy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(started); elapsed > policy.withDefaults().Budget+50*time.Millisecond {
+		t.Errorf("timed out count took %s, past the budget", elapsed)
+	}
+	if result.CountSource != CountSourceCache || result.TotalItems != 42 || len(result.Warnings) != 1 {
+		t.Fatalf("timed out count: source %q, total %d, warnings %v", result.CountSource, result.TotalItems, result.Warnings)
+	}
+	if !result.HasNext || len(result.Items) != 2 {
+		t.Errorf("has next %t with %d items, want the extra row to report a next page", result.HasNext, len(result.Items))
+	}
+}
+
+func TestCountFallbackOrderFollowsPolicy(t *testing.T) {
+	slow := false
+	db := slowCountDB(t, &slow)
+	policy := CountFallbackPolicy{
+		Timeout:   20 * time.Millisecond,
+		Fallbacks: []CountFallback{FallbackEstimate, FallbackCache},
+		Cache:     NewMemoryPageCache(0),
+		Estimator: func(ctx context.Context, query *gorm.DB) (int64, bool, error) {
+			return 12000, true, nil
+		},
+	}
+
+	var events []event
+	if _, err := OffsetPaginate(db, &events, 1, 2, WithCountFallback(policy)); err != nil {
+		t.Fatal(err)
+	}
+
+	// The cached count is fresh, but the policy asks the planner first
+	slow = true
+	result, err := OffsetPaginate(db, &events, 1, 2, WithCountFallback(policy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.CountSource != CountSourceEstimate || result.TotalItems != 12000 || result.TotalPages != 6000 {
+		t.Fatalf("source %q, total %d, pages %d", result.CountSource, result.TotalItems, result.TotalPages)
+	}
+
+	raw, err := json.Marshal(result.ToResponse(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(raw), `"count_source":"estimate"`) || !strings.Contains(string(raw), `"warnings":["count timed out`) {
+		t.Errorf("response = %s", raw)
+	}
+}
+
+func TestCountFallbackStaysWithinBudget(t *testing.T) {
+	slow := true
+	db := slowCountDB(t, &slow)
+	policy := CountFallbackPolicy{
+		Timeout: 20 * time.Millisecond,
+		Budget:  40 * time.Millisecond,
+		Estimator: func(ctx context.Context, query *gorm.DB) (int64, bool, error) {
+			time.Sleep(time.Second) // ignores ctx
+			return 12000, true, nil
+		},
+	}
+
+	var events []event
+	started := time.Now()
+	result, err := OffsetPaginate(db, &events, 1, 2, WithCountFallback(policy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(started); elapsed > policy.Budget+50*time.Millisecond {
+		t.Errorf("count and fallbacks took %s, past the %s budget", elapsed, policy.Budget)
+	}
+	if result.CountSource != CountSourceNone || result.CountMode != CountSkipped {
+		t.Fatalf("source %q, count mode %q", result.CountSource, result.CountMode)
+	}
+
+	raw, err := json.Marshal(result.ToResponse(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(raw), `"total_items"`) {
+		t.Errorf("response has total_items: %s", raw)
+	}
+}
+
+func TestCountFallbackKeepsRequestCancellation(t *testing.T) {
+	slow := true
+	db := slowCountDB(t, &slow)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var events []event
+	_, err := OffsetPaginate(db, &events, 1, 2,
+		WithContext(ctx),
+		WithCountFallback(CountFallbackPolicy{Timeout: time.Second}),
+	)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled request: err = %v, want context.Canceled", err)
+	}
+}
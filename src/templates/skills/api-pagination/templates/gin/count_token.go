@@ -0,0 +1,182 @@
+package pagination
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CountTokenHeader is the request header carrying a count token (the count_token query
+// parameter works too)
+const CountTokenHeader = "X-Count-Token"
+
+// CountTokens issues and verifies signed count tokens
+// A count token carries an offset page's total, when it was counted, and a hash of the count
+// query. Clients send it back on the next page (?count_token= or X-Count-Token), and the
+// paginator reuses the total instead of running COUNT(*) again while the token is younger than
+// maxAge and was issued for the same query. Tokens are HMAC-SHA256 signed, so a client cannot
+// make the server report a total of its choosing; invalid, expired, or foreign tokens are
+// ignored and the rows are counted.
+type CountTokens struct {
+	secret []byte
+	maxAge time.Duration
+	now    func() time.Time
+}
+
+// NewCountTokens creates a CountTokens signing with secret (share it with every instance)
+// whose tokens are reused for at most maxAge (0 = 5 minutes)
+//
+// Example usage:
+//
+//	var countTokens = pagination.NewCountTokens([]byte(os.Getenv("PAGINATION_SECRET")), time.Minute)
+//
+//	params := pagination.GetPaginationParams(c)
+//	result, err := pagination.OffsetPaginate(db.Model(&Order{}), &orders, params.Page, params.PageSize,
+//	    pagination.WithCountToken(countTokens, params.CountToken),
+//	)
+//	// result.CountToken goes back to the client; result.TotalFromToken reports a reused total
+func NewCountTokens(secret []byte, maxAge time.Duration) *CountTokens {
+	if maxAge <= 0 {
+		maxAge = 5 * time.Minute
+	}
+	return &CountTokens{secret: secret, maxAge: maxAge, now: time.Now}
+}
+
+// countTokenPayload is the signed content of a count token
+type countTokenPayload struct {
+	Total      int64  `json:"t"`
+	AtLeast    *int64 `json:"a,omitempty"`
+	ComputedAt int64  `json:"c"`
+	QueryHash  string `json:"h"`
+}
+
+// issue signs a token for a freshly counted total
+func (c *CountTokens) issue(total int64, atLeast *int64, queryHash string) (string, error) {
+	raw, err := json.Marshal(countTokenPayload{
+		Total:      total,
+		AtLeast:    atLeast,
+		ComputedAt: c.now().Unix(),
+		QueryHash:  queryHash,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode count token: %w", err)
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString(raw)
+	return payload + "." + c.sign(payload), nil
+}
+
+// verify returns the payload of token if it is authentic, fresh, and issued for queryHash
+func (c *CountTokens) verify(token string, queryHash string) (countTokenPayload, bool) {
+	var payload countTokenPayload
+
+	encoded, signature, found := strings.Cut(token, ".")
+	if !found || !hmac.Equal([]byte(signature), []byte(c.sign(encoded))) {
+		return payload, false
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || json.Unmarshal(raw, &payload) != nil {
+		return payload, false
+	}
+
+	age := c.now().Sub(time.Unix(payload.ComputedAt, 0))
+	if age < 0 || age > c.maxAge || payload.QueryHash != queryHash {
+		return payload, false
+	}
+	return payload, true
+}
+
+// sign returns the base64url HMAC-SHA256 of payload
+func (c *CountTokens) sign(payload string) string {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// pageTotal is the total of an offset page and where it came from
+type pageTotal struct {
+	items     int64
+	atLeast   *int64
+	token     string
+	fromToken bool
+
+	// skipped is set when an open CountBreaker shed the count, or WithCountFallback found
+	// no fallback
+	skipped bool
+
+	// approximate is set for a WithCountFallback total that was not counted just now;
+	// source and warning describe where it came from
+	approximate bool
+	source      CountSource
+	warning     string
+}
+
+// inexact reports whether the total cannot tell if rows follow a page
+func (t pageTotal) inexact() bool {
+	return t.skipped || t.approximate
+}
+
+// resolveTotal counts query, or reuses the total of a valid WithCountToken token
+// Fresh counts get a new token; a reused total keeps its token, so it expires maxAge after
+// the count it came from rather than sliding forever.
+func resolveTotal(query *gorm.DB, o options) (pageTotal, error) {
+	if o.countTokens == nil {
+		return counterCount(query, o)
+	}
+
+	hash := countQueryHash(query, o)
+	if o.countToken != "" {
+		if payload, ok := o.countTokens.verify(o.countToken, hash); ok {
+			return pageTotal{items: payload.Total, atLeast: payload.AtLeast, token: o.countToken, fromToken: true}, nil
+		}
+	}
+
+	total, err := counterCount(query, o)
+	if err != nil || total.inexact() {
+		return total, err
+	}
+
+	total.token, err = o.countTokens.issue(total.items, total.atLeast, hash)
+	if err != nil {
+		return pageTotal{}, err
+	}
+	return total, nil
+}
+
+// countQueryHash identifies the count a token may stand in for: the rendered count SQL
+// (arguments inlined) and the WithMaxReportedTotal cap
+// A db without a dialect (a test double) cannot render SQL; its hash covers the cap alone.
+func countQueryHash(query *gorm.DB, o options) string {
+	var rendered string
+	if query.Dialector != nil {
+		rendered = query.ToSQL(func(tx *gorm.DB) *gorm.DB {
+			var total int64
+			return tx.Count(&total)
+		})
+	}
+
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%d", rendered, o.maxReportedTotal)))
+	return hex.EncodeToString(sum[:16])
+}
+
+// withTotalSource records on p where its total came from
+func withTotalSource[T any](p *OffsetPagination[T], total pageTotal) *OffsetPagination[T] {
+	p.CountToken = total.token
+	p.TotalFromToken = total.fromToken
+	if total.skipped {
+		p.CountMode = CountSkipped
+	}
+	p.CountSource = total.source
+	if total.warning != "" {
+		p.Warnings = append(p.Warnings, total.warning)
+	}
+	return p
+}
@@ -0,0 +1,66 @@
+package pagination
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCountTokenReuse(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tokens := NewCountTokens([]byte("secret"), time.Minute)
+	tokens.now = func() time.Time { return now }
+
+	atLeast := int64(1000)
+	token, err := tokens.issue(1000, &atLeast, "orders-hash")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload, ok := tokens.verify(token, "orders-hash")
+	if !ok || payload.Total != 1000 || payload.AtLeast == nil || *payload.AtLeast != 1000 {
+		t.Fatalf("verify(fresh) = %+v, %t", payload, ok)
+	}
+
+	if _, ok := tokens.verify(token, "orders-with-filter-hash"); ok {
+		t.Error("token reused for a different query")
+	}
+
+	// Raise the total without re-signing
+	encoded, signature, _ := strings.Cut(token, ".")
+	forged, err := tokens.issue(5, nil, "orders-hash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	forgedPayload, _, _ := strings.Cut(forged, ".")
+	if _, ok := tokens.verify(forgedPayload+"."+signature, "orders-hash"); ok {
+		t.Error("tampered payload accepted")
+	}
+	if _, ok := NewCountTokens([]byte("other"), time.Minute).verify(encoded+"."+signature, "orders-hash"); ok {
+		t.Error("token accepted under another secret")
+	}
+
+	now = now.Add(time.Minute + time.Second)
+	if _, ok := tokens.verify(token, "orders-hash"); ok {
+		t.Error("expired token accepted")
+	}
+}
+
+func TestCountTokenInResponses(t *testing.T) {
+	page := &OffsetPagination[int]{
+		CurrentPage:    1,
+		TotalPages:     3,
+		HasNext:        true,
+		PageSize:       20,
+		CountToken:     "abc.def",
+		TotalFromToken: true,
+	}
+
+	response := page.ToResponse("/orders")
+	if response.Pagination.CountToken != "abc.def" || !response.Pagination.TotalFromToken {
+		t.Errorf("meta = %+v", response.Pagination)
+	}
+	if want := "/orders?page=2&page_size=20&count_token=abc.def"; *response.Links.Next != want {
+		t.Errorf("next = %s, want %s", *response.Links.Next, want)
+	}
+}
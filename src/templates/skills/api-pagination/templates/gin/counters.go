@@ -0,0 +1,193 @@
+package pagination
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DefaultCounterTable is the table CounterTable and ReconcileCounter use for a "" table name
+const DefaultCounterTable = "pagination_counters"
+
+// Counter is a row of a maintained counters table
+// Triggers or the application's writes keep Total equal to the rows matching Key; migrate it
+// with db.AutoMigrate(&pagination.Counter{}).
+type Counter struct {
+	Key       string `gorm:"column:counter_key;primaryKey;size:191"`
+	Total     int64
+	UpdatedAt time.Time
+}
+
+// TableName returns DefaultCounterTable
+func (Counter) TableName() string {
+	return DefaultCounterTable
+}
+
+// CounterLookup returns the maintained count for key; ok is false when no counter exists
+type CounterLookup func(ctx context.Context, key string) (total int64, ok bool, err error)
+
+// CounterKey names the counter of scope under filters, e.g. "invoices?status=paid"
+// Filters are sorted, so the same combination always maps to the same key; no filters is the
+// counter of the whole scope.
+//
+// Example usage:
+//
+//	key := pagination.CounterKey("invoices", map[string]string{"status": c.Query("status")})
+func CounterKey(scope string, filters map[string]string) string {
+	var pairs []string
+	for name, value := range filters {
+		if value != "" {
+			pairs = append(pairs, name+"="+value)
+		}
+	}
+	if len(pairs) == 0 {
+		return scope
+	}
+	sort.Strings(pairs)
+	return scope + "?" + strings.Join(pairs, "&")
+}
+
+// CounterTable reads counters from table (DefaultCounterTable for "") with db
+//
+// Example usage:
+//
+//	var invoiceCounters = pagination.CounterTable(db, "")
+func CounterTable(db *gorm.DB, table string) CounterLookup {
+	if table == "" {
+		table = DefaultCounterTable
+	}
+	return func(ctx context.Context, key string) (int64, bool, error) {
+		var totals []int64
+		err := db.WithContext(ctx).Table(table).Where("counter_key = ?", key).Limit(1).Pluck("total", &totals).Error
+		if err != nil {
+			return 0, false, err
+		}
+		if len(totals) == 0 {
+			return 0, false, nil
+		}
+		return totals[0], true, nil
+	}
+}
+
+// counterCount reads the WithCounterSource counter of the active filters, falling back to
+// fallbackCount when no counter exists for them
+func counterCount(query *gorm.DB, o options) (pageTotal, error) {
+	if o.counterLookup == nil {
+		return fallbackCount(query, o)
+	}
+	total, ok, err := counterTotal(query, o)
+	if err != nil || ok {
+		return total, err
+	}
+
+	total, err = fallbackCount(query, o)
+	if err == nil && total.source == "" && !total.skipped {
+		total.source = CountSourceExact
+	}
+	return total, err
+}
+
+// counterTotal reads the WithCounterSource total; ok is false when no counter exists for the key
+func counterTotal(query *gorm.DB, o options) (total pageTotal, ok bool, err error) {
+	items, ok, err := o.counterLookup(queryContext(query), o.counterKey)
+	if err != nil {
+		return pageTotal{}, false, fmt.Errorf("failed to read counter %q: %w", o.counterKey, err)
+	}
+	if !ok {
+		return pageTotal{}, false, nil
+	}
+
+	total = pageTotal{items: items, source: CountSourceCounter}
+	if o.maxReportedTotal > 0 {
+		total.items, total.atLeast = reportedTotal(items, int64(o.maxReportedTotal))
+	}
+	return total, true, nil
+}
+
+// ReconcileCounter recounts query and corrects the counter key in table (DefaultCounterTable
+// for ""), creating it if missing
+// correction is the counted total minus the stored one (the counted total for a new counter).
+//
+// Example usage:
+//
+//	paid := db.Model(&Invoice{}).Where("status = ?", "paid")
+//	correction, err := pagination.ReconcileCounter(ctx, db, "", "invoices?status=paid", paid)
+func ReconcileCounter(ctx context.Context, db *gorm.DB, table, key string, query *gorm.DB) (correction int64, err error) {
+	if table == "" {
+		table = DefaultCounterTable
+	}
+
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var counted int64
+		if err := query.Session(&gorm.Session{}).WithContext(ctx).Count(&counted).Error; err != nil {
+			return fmt.Errorf("failed to count items: %w", err)
+		}
+
+		stored, found, err := CounterTable(tx, table)(ctx, key)
+		if err != nil {
+			return fmt.Errorf("failed to read counter %q: %w", key, err)
+		}
+		correction = counted - stored
+
+		row := Counter{Key: key, Total: counted, UpdatedAt: time.Now()}
+		if found {
+			err = tx.Table(table).Where("counter_key = ?", key).Updates(map[string]any{
+				"total":      row.Total,
+				"updated_at": row.UpdatedAt,
+			}).Error
+		} else {
+			err = tx.Table(table).Create(&row).Error
+		}
+		if err != nil {
+			return fmt.Errorf("failed to correct counter %q: %w", key, err)
+		}
+		return nil
+	})
+	return correction, err
+}
+
+// ReconcileCounters runs ReconcileCounter for every key of queries each interval until ctx is
+// done, passing each drifted counter's correction (or error) to onDrift (nil = ignore)
+//
+// Example usage:
+//
+//	go pagination.ReconcileCounters(ctx, db, "", time.Hour, map[string]*gorm.DB{
+//	    "invoices":             db.Model(&Invoice{}),
+//	    "invoices?status=paid": db.Model(&Invoice{}).Where("status = ?", "paid"),
+//	}, func(key string, correction int64, err error) {
+//	    log.Printf("counter %s corrected by %d: %v", key, correction, err)
+//	})
+func ReconcileCounters(
+	ctx context.Context,
+	db *gorm.DB,
+	table string,
+	interval time.Duration,
+	queries map[string]*gorm.DB,
+	onDrift func(key string, correction int64, err error),
+) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for key, query := range queries {
+			correction, err := ReconcileCounter(ctx, db, table, key, query)
+			if errors.Is(err, context.Canceled) {
+				return
+			}
+			if onDrift != nil && (correction != 0 || err != nil) {
+				onDrift(key, correction, err)
+			}
+		}
+	}
+}
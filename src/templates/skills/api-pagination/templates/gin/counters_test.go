@@ -0,0 +1,58 @@
+package pagination
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCounterKeySortsFilters(t *testing.T) {
+	key := CounterKey("invoices", map[string]string{"status": "paid", "currency": "eur", "region": ""})
+	if key != "invoices?currency=eur&status=paid" {
+		t.Errorf("key %q, want sorted non-empty filters", key)
+	}
+	if key := CounterKey("invoices", nil); key != "invoices" {
+		t.Errorf("key %q without filters, want the scope", key)
+	}
+}
+
+func TestCounterSourceReadsCounter(t *testing.T) {
+	slow := false
+	db := slowCountDB(t, &slow)
+	counters := map[string]int64{"events?kind=paid": 7}
+	lookup := func(ctx context.Context, key string) (int64, bool, error) {
+		total, ok := counters[key]
+		return total, ok, nil
+	}
+
+	var events []event
+	result, err := OffsetPaginate(db, &events, 1, 2, WithCounterSource(lookup, "events?kind=paid"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.CountSource != CountSourceCounter || result.TotalItems != 7 || result.TotalPages != 4 {
+		t.Fatalf("counter: source %q, total %d, pages %d", result.CountSource, result.TotalItems, result.TotalPages)
+	}
+
+	result, err = OffsetPaginate(db, &events, 1, 2, WithCounterSource(lookup, "events?kind=refunded"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.CountSource != CountSourceExact || result.TotalItems != 42 {
+		t.Fatalf("missing counter: source %q, total %d, want the real count", result.CountSource, result.TotalItems)
+	}
+}
+
+func TestCounterSourceLookupErrorFailsPage(t *testing.T) {
+	slow := false
+	db := slowCountDB(t, &slow)
+	broken := errors.New("counters unavailable")
+	lookup := func(ctx context.Context, key string) (int64, bool, error) {
+		return 0, false, broken
+	}
+
+	var events []event
+	if _, err := OffsetPaginate(db, &events, 1, 2, WithCounterSource(lookup, "events")); !errors.Is(err, broken) {
+		t.Fatalf("err %v, want the lookup error", err)
+	}
+}
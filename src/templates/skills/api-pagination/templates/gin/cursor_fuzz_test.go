@@ -0,0 +1,73 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// cursorSeeds covers the malformed input clients actually send: truncated and re-padded
+// base64, wrong alphabets, invalid UTF-8, JSON fragments, legacy and mismatched cursors, and
+// oversized values
+func cursorSeeds() []string {
+	valid := EncodeCursor("id", 12345, true)
+	return []string{
+		"",
+		valid,
+		encodeValueCursor(12345),
+		EncodeCursor("name", "x", false),
+		valid[:len(valid)-1],
+		valid[:2],
+		valid + "=",
+		"====",
+		"MTA",
+		"-_-_",
+		"\xff\xfe\xfd",
+		base64.StdEncoding.EncodeToString([]byte("\xff\xfe\xfd")),
+		base64.RawURLEncoding.EncodeToString([]byte(`{"f":"id","t":"MTA="}`)),
+		base64.RawURLEncoding.EncodeToString([]byte(`{"f":`)),
+		base64.RawURLEncoding.EncodeToString([]byte(`[[[[[[[[[[`)),
+		base64.RawURLEncoding.EncodeToString([]byte(`null`)),
+		"snap~" + valid,
+		strings.Repeat("A", 1<<16),
+		strings.Repeat("\x00", 1024),
+	}
+}
+
+// FuzzDecodeCursor checks that every decoder returns either a value or an error wrapping
+// ErrInvalidCursor (or a field mismatch), and never panics, whatever the client sends
+// Run it with go test -fuzz FuzzDecodeCursor.
+func FuzzDecodeCursor(f *testing.F) {
+	for _, seed := range cursorSeeds() {
+		f.Add(seed)
+	}
+
+	decoders := map[string]func(string) (any, error){
+		"DecodeCursor":          func(c string) (any, error) { return DecodeCursor(c, "id", true) },
+		"JSONCursorCodec":       JSONCursorCodec{}.Decode,
+		"FieldCursorCodec":      FieldCursorCodec("id", nil, CursorFieldAliases{"legacy_id": "id"}).Decode,
+		"FieldCursorCodec/JSON": FieldCursorCodec("id", JSONCursorCodec{}, nil).Decode,
+	}
+
+	f.Fuzz(func(t *testing.T, cursor string) {
+		for name, decode := range decoders {
+			value, err := decode(cursor)
+			if err != nil {
+				if !errors.Is(err, ErrInvalidCursor) && !errors.Is(err, ErrCursorFieldMismatch) && !errors.Is(err, ErrCursorMismatch) {
+					t.Errorf("%s(%q): untyped error %v", name, cursor, err)
+				}
+				continue
+			}
+			if cursor != "" && value == nil {
+				t.Errorf("%s(%q): nil value without an error", name, cursor)
+			}
+		}
+
+		if cursor == "" {
+			if value, err := DecodeCursor(cursor, "id", true); value != "" || err != nil {
+				t.Errorf("empty cursor: got %q, %v", value, err)
+			}
+		}
+	})
+}
@@ -3,7 +3,7 @@ package pagination
 import (
 	"encoding/base64"
 	"fmt"
-	"strconv"
+	"math"
 
 	"gorm.io/gorm"
 )
@@ -17,23 +17,81 @@ type CursorPagination[T any] struct {
 	HasNext        bool    `json:"has_next"`
 	HasPrevious    bool    `json:"has_previous"`
 	PageSize       int     `json:"page_size"`
+
+	// ApproxRemaining is a capped count of rows after this page (see WithApproxRemaining)
+	ApproxRemaining *int64 `json:"approx_remaining,omitempty"`
+
+	// Offset-style metadata, populated only when WithHybridOffset applies
+	CurrentPage *int   `json:"current_page,omitempty"`
+	TotalPages  *int   `json:"total_pages,omitempty"`
+	TotalItems  *int64 `json:"total_items,omitempty"`
+
+	// Cursors of the first and last items (nil on an empty page)
+	// Clients caching pages can re-fetch exactly this window by passing StartCursor back with
+	// WithInclusiveCursor, or invalidate it by its boundaries
+	StartCursor *string `json:"start_cursor,omitempty"`
+	EndCursor   *string `json:"end_cursor,omitempty"`
+
+	// NewItemsAvailable is set when rows now sort before the first row of the session's first
+	// page (see WithDriftDetection), e.g. for a "new items, tap to refresh" banner
+	NewItemsAvailable bool `json:"new_items_available,omitempty"`
+
+	// Restarted is set when the cursor's codec sent the request back to the first page
+	// (ErrRestartPagination), e.g. because the filters changed mid-session
+	Restarted bool `json:"restarted,omitempty"`
+
+	// PageBytes is the approximate JSON size of the page and RowCount its rows, set only under
+	// WithByteBudget
+	PageBytes int `json:"page_bytes,omitempty"`
+	RowCount  int `json:"row_count,omitempty"`
+
+	// NextPreview is the first item of the next page, set only under WithPeek
+	NextPreview *T `json:"next_preview,omitempty"`
+
+	// Raw cursor field values of the first and last items (nil on an empty page)
+	// For server-side bookkeeping only; never serialized, so clients keep using the opaque cursors
+	FirstKey any `json:"-"`
+	LastKey  any `json:"-"`
+}
+
+// EncodeCursor encodes value as the cursor of a page ordered by field: the base64url JSON
+// payload {field, value, direction, v} the cursor paginators issue by default
+func EncodeCursor(field string, value interface{}, ascending bool) string {
+	// Cursor values are scalars, which always marshal
+	cursor, _ := PayloadCursorCodec(field, ascending).Encode(value)
+	return cursor
+}
+
+// DecodeCursor decodes a cursor from EncodeCursor for a page ordered by field
+// A cursor issued for another field or direction fails with ErrCursorMismatch. Plain base64
+// cursors, the encoding before payloads, are accepted for one release (see OnLegacyCursor).
+func DecodeCursor(cursor, field string, ascending bool) (string, error) {
+	if cursor == "" {
+		return "", nil
+	}
+
+	value, err := PayloadCursorCodec(field, ascending).Decode(cursor)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%v", value), nil
 }
 
-// EncodeCursor encodes a value as a base64 cursor
-func EncodeCursor(value interface{}) string {
+// encodeValueCursor encodes a value's fmt %v text as a plain base64 cursor
+func encodeValueCursor(value any) string {
 	str := fmt.Sprintf("%v", value)
 	return base64.StdEncoding.EncodeToString([]byte(str))
 }
 
-// DecodeCursor decodes a base64 cursor to a string
-func DecodeCursor(cursor string) (string, error) {
+// decodeValueCursor decodes a plain base64 cursor to a string
+func decodeValueCursor(cursor string) (string, error) {
 	if cursor == "" {
 		return "", nil
 	}
 
 	decoded, err := base64.StdEncoding.DecodeString(cursor)
 	if err != nil {
-		return "", fmt.Errorf("invalid cursor: %w", err)
+		return "", fmt.Errorf("%w: %v", ErrInvalidCursor, err)
 	}
 
 	return string(decoded), nil
@@ -55,6 +113,7 @@ func DecodeCursor(cursor string) (string, error) {
 //	        pageSize,
 //	        "id", // cursor field
 //	        true, // ascending
+//	        pagination.WithApproxRemaining(500), // optional
 //	    )
 //
 //	    if err != nil {
@@ -71,81 +130,250 @@ func CursorPaginateInt[T any](
 	pageSize int,
 	cursorField string,
 	ascending bool,
+	opts ...Option,
 ) (*CursorPagination[T], error) {
+	o := applyOptions(opts)
+	o.defaultPayloadCodec(cursorField, ascending)
+	db = o.bindContext(db)
+
+	// Verify the cursor's signature before anything else reads it
+	if o.cursorSecret != nil && !o.cursorVerified {
+		return signCursors(cursor, o.cursorSecret, func(cursor string, verified Option) (*CursorPagination[T], error) {
+			return CursorPaginateInt(db, dest, cursor, pageSize, cursorField, ascending, append(opts[:len(opts):len(opts)], verified)...)
+		})
+	}
+
+	// Reject stale cursors; inside the signature, which covers their issue time
+	if o.cursorTTL > 0 && !o.cursorStamped {
+		return expireCursors(cursor, o.cursorTTL, func(cursor string, stamped Option) (*CursorPagination[T], error) {
+			return CursorPaginateInt(db, dest, cursor, pageSize, cursorField, ascending, append(opts[:len(opts):len(opts)], stamped)...)
+		})
+	}
+
+	// Count the session's pages before anything else reads the cursor
+	if o.pageLimit != nil && !o.pageLimited {
+		return limitSessionPages(cursor, o.pageLimit, func(cursor string, counted Option) (*CursorPagination[T], error) {
+			return CursorPaginateInt(db, dest, cursor, pageSize, cursorField, ascending, append(opts[:len(opts):len(opts)], counted)...)
+		})
+	}
+
+	// A PreviousCursor carries its direction: it pages backward without WithBackward
+	if rest, before := splitBackwardCursor(cursor); before {
+		cursor, o.backward = rest, true
+		opts = append(opts[:len(opts):len(opts)], WithBackward())
+	}
+
+	// Re-enter inside the session's snapshot so every query reads from it
+	if o.snapshots != nil && !o.pinned {
+		return inSnapshot(db, cursor, o, func(tx *gorm.DB, pinned Option) (*CursorPagination[T], error) {
+			pinnedOpts := append(opts[:len(opts):len(opts)], pinned)
+			return CursorPaginateInt(tx, dest, cursor, pageSize, cursorField, ascending, pinnedOpts...)
+		})
+	}
+
+	// Serve repeated pages from the page cache
+	if o.usePageCache() {
+		result, cached, err := cachedCursorPage(db, dest, o, cursorField, func(filling Option) (*CursorPagination[T], error) {
+			return CursorPaginateInt(db, dest, cursor, pageSize, cursorField, ascending, append(opts[:len(opts):len(opts)], filling)...)
+		}, "cursor-int", cursor, pageSize, cursorField, ascending, queryMaxPageSize(db))
+		if cached {
+			return result, err
+		}
+	}
+
+	// Read from the replica, falling back to the primary
+	if o.usesReplica() {
+		return readReplicated(queryContext(db), o.replica, func(route func(*gorm.DB) *gorm.DB, routed Option) (*CursorPagination[T], error) {
+			return CursorPaginateInt(route(db), dest, cursor, pageSize, cursorField, ascending, append(opts[:len(opts):len(opts)], routed)...)
+		})
+	}
+
+	if err := checkDestType[T](db); err != nil {
+		return nil, err
+	}
+
+	// Resolve a joined table's cursor field to its quoted column and the column items carry
+	column, err := resolveCursorColumn(db, cursorField, o)
+	if err != nil {
+		return nil, err
+	}
+
 	// Constrain page size
-	if pageSize > {{maxPageSize}} {
-		pageSize = {{maxPageSize}}
+	if limit := queryMaxPageSize(db); pageSize > limit {
+		pageSize = limit
 	}
 	if pageSize < 1 {
-		pageSize = {{defaultPageSize}}
+		pageSize = o.config.DefaultPageSize
 	}
 
-	query := db
+	// Detach the session anchor WithDriftDetection appends to cursors
+	var anchor any
+	if o.driftDetection && cursor != "" {
+		var err error
+		if cursor, anchor, err = splitDriftAnchor(cursor); err != nil {
+			return nil, err
+		}
+		if anchor, err = cursorInt(anchor); err != nil {
+			return nil, fmt.Errorf("%w anchor: %v", ErrInvalidCursor, err)
+		}
+	}
 
-	// Apply cursor filter if provided
-	if cursor != "" {
-		decodedCursor, err := DecodeCursor(cursor)
-		if err != nil {
+	// Detach the tie-breaker value appended to cursors when the cursor field is not unique
+	tie, err := resolveTieBreaker[T](db, column.key, o)
+	if err != nil {
+		return nil, err
+	}
+	if err := qualifyTieBreaker[T](db, tie, column); err != nil {
+		return nil, err
+	}
+	var tieValue any
+	var hasTieValue bool
+	if tie != nil && cursor != "" {
+		if cursor, tieValue, hasTieValue, err = splitTieBreaker(cursor); err != nil {
 			return nil, err
 		}
+	}
+
+	// Decode the cursor; a codec may send the request back to the first page
+	decodedCursor, restarted, err := decodeCursor(cursor, o)
+	if err != nil {
+		return nil, err
+	}
+	if restarted {
+		cursor, anchor = "", nil
+	}
+
+	// Snapshot the query before the cursor filter for hybrid offset counting
+	base := db.Session(&gorm.Session{})
+	query := db.Session(&gorm.Session{})
+
+	// A backward page scans from the cursor toward the start of the order
+	scan := ascending != o.backward
 
-		cursorValue, err := strconv.ParseInt(decodedCursor, 10, 64)
+	// Apply cursor filter if provided
+	if cursor != "" {
+		cursorValue, err := cursorInt(decodedCursor)
 		if err != nil {
-			return nil, fmt.Errorf("invalid cursor value: %w", err)
+			return nil, fmt.Errorf("%w value: %v", ErrInvalidCursor, err)
 		}
 
-		if ascending {
-			query = query.Where(fmt.Sprintf("%s > ?", cursorField), cursorValue)
+		if hasTieValue {
+			query = query.Where(tieBreakerCondition(column.sql, tie.column, scan, o.inclusiveCursor), cursorValue, cursorValue, tieValue)
 		} else {
-			query = query.Where(fmt.Sprintf("%s < ?", cursorField), cursorValue)
+			query = query.Where(cursorCondition(column.sql, scan, o.inclusiveCursor), cursorValue)
 		}
 	}
 
 	// Order by cursor field
-	if ascending {
-		query = query.Order(fmt.Sprintf("%s ASC", cursorField))
+	if scan {
+		query = query.Order(fmt.Sprintf("%s ASC", column.sql))
 	} else {
-		query = query.Order(fmt.Sprintf("%s DESC", cursorField))
+		query = query.Order(fmt.Sprintf("%s DESC", column.sql))
+	}
+	if tie != nil {
+		query = query.Order(tieBreakerOrder(tie.column, scan))
 	}
 
 	// Fetch one extra item to check for next page
 	var items []T
-	if err := query.Limit(pageSize + 1).Find(&items).Error; err != nil {
+	err = o.fetchLimiter.do(queryContext(query), func() error {
+		return o.onFetchDB(query).Limit(sqlLimit(query, pageSize+1)).Find(&items).Error
+	})
+	if err != nil {
 		return nil, fmt.Errorf("failed to fetch items: %w", err)
 	}
 
 	hasNext := len(items) > pageSize
+	fetched := items
 	if hasNext {
 		items = items[:pageSize]
 	}
 
+	// Cut the page at the client's byte budget
+	items, hasNext, pageBytes, err := applyByteBudget(items, hasNext, o)
+	if err != nil {
+		return nil, err
+	}
+
+	// A backward page was read nearest the cursor first: the extra row tells whether a page
+	// precedes it, and the cursor's own row follows it
+	hasPrevious := cursor != ""
+	if o.backward {
+		hasNext, hasPrevious = cursor != "", hasNext
+		reverseItems(items)
+	}
+
+	items = nonNilItems(items)
 	*dest = items
 
-	// Generate cursors
+	firstTie, lastTie, err := tieBreakerValues(query, items, tie, o)
+	if err != nil {
+		return nil, err
+	}
+
+	firstKey, lastKey, err := boundaryKeys(db, items, column.key)
+	if err != nil {
+		return nil, err
+	}
+
+	tail, leading := pageTail(base, query, column.sql, tie, lastKey, lastTie, len(items), ascending, o)
+	approxRemaining, err := resolveApproxRemaining(tail, leading, hasNext, o)
+	if err != nil {
+		return nil, err
+	}
+
+	hybrid, err := resolveHybridOffset(base, tail, len(items)-leading, pageSize, o)
+	if err != nil {
+		return nil, err
+	}
+
+	startCursor, endCursor, err := boundaryCursors(o.cursorCodec(), len(items), firstKey, lastKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// Continue from the boundary items' cursor field values, as StartCursor and EndCursor do
 	var nextCursor *string
 	var previousCursor *string
+	if hasNext {
+		nextCursor = copyCursor(endCursor)
+	}
+	if hasPrevious {
+		previousCursor = copyCursor(startCursor)
+	}
 
-	if hasNext && len(items) > 0 {
-		// Get the cursor value from the last item
-		// This is simplified - in production you'd use reflection or a type-safe approach
-		lastCursor := EncodeCursor(items[len(items)-1])
-		nextCursor = &lastCursor
+	result := &CursorPagination[T]{
+		Items:           items,
+		NextCursor:      nextCursor,
+		PreviousCursor:  previousCursor,
+		HasNext:         hasNext,
+		HasPrevious:     hasPrevious,
+		PageSize:        pageSize,
+		ApproxRemaining: approxRemaining,
+		StartCursor:     startCursor,
+		EndCursor:       endCursor,
+		FirstKey:        firstKey,
+		LastKey:         lastKey,
+		Restarted:       restarted,
+	}
+	if o.byteBudget > 0 {
+		result.PageBytes, result.RowCount = pageBytes, len(items)
+	}
+	if o.peek && hasNext && !o.backward {
+		preview := fetched[len(items)]
+		result.NextPreview = &preview
+	}
+	applyHybridOffset(result, hybrid)
+	if err := applyTieBreaker(result, tie, firstTie, lastTie); err != nil {
+		return nil, err
 	}
 
-	if cursor != "" && len(items) > 0 {
-		// Get the cursor value from the first item
-		firstCursor := EncodeCursor(items[0])
-		previousCursor = &firstCursor
+	if err := applyDriftDetection(result, base, column.sql, ascending, anchor, o); err != nil {
+		return nil, err
 	}
+	result.PreviousCursor = markBackwardCursor(result.PreviousCursor)
 
-	return &CursorPagination[T]{
-		Items:          items,
-		NextCursor:     nextCursor,
-		PreviousCursor: previousCursor,
-		HasNext:        hasNext,
-		HasPrevious:    cursor != "",
-		PageSize:       pageSize,
-	}, nil
+	return result, nil
 }
 
 // CursorPaginateString paginates using a string cursor (like UUID or timestamp)
@@ -156,71 +384,465 @@ func CursorPaginateString[T any](
 	pageSize int,
 	cursorField string,
 	ascending bool,
+	opts ...Option,
 ) (*CursorPagination[T], error) {
+	o := applyOptions(opts)
+	o.defaultPayloadCodec(cursorField, ascending)
+	db = o.bindContext(db)
+
+	// Verify the cursor's signature before anything else reads it
+	if o.cursorSecret != nil && !o.cursorVerified {
+		return signCursors(cursor, o.cursorSecret, func(cursor string, verified Option) (*CursorPagination[T], error) {
+			return CursorPaginateString(db, dest, cursor, pageSize, cursorField, ascending, append(opts[:len(opts):len(opts)], verified)...)
+		})
+	}
+
+	// Reject stale cursors; inside the signature, which covers their issue time
+	if o.cursorTTL > 0 && !o.cursorStamped {
+		return expireCursors(cursor, o.cursorTTL, func(cursor string, stamped Option) (*CursorPagination[T], error) {
+			return CursorPaginateString(db, dest, cursor, pageSize, cursorField, ascending, append(opts[:len(opts):len(opts)], stamped)...)
+		})
+	}
+
+	// Count the session's pages before anything else reads the cursor
+	if o.pageLimit != nil && !o.pageLimited {
+		return limitSessionPages(cursor, o.pageLimit, func(cursor string, counted Option) (*CursorPagination[T], error) {
+			return CursorPaginateString(db, dest, cursor, pageSize, cursorField, ascending, append(opts[:len(opts):len(opts)], counted)...)
+		})
+	}
+
+	// A PreviousCursor carries its direction: it pages backward without WithBackward
+	if rest, before := splitBackwardCursor(cursor); before {
+		cursor, o.backward = rest, true
+		opts = append(opts[:len(opts):len(opts)], WithBackward())
+	}
+
+	// Re-enter inside the session's snapshot so every query reads from it
+	if o.snapshots != nil && !o.pinned {
+		return inSnapshot(db, cursor, o, func(tx *gorm.DB, pinned Option) (*CursorPagination[T], error) {
+			pinnedOpts := append(opts[:len(opts):len(opts)], pinned)
+			return CursorPaginateString(tx, dest, cursor, pageSize, cursorField, ascending, pinnedOpts...)
+		})
+	}
+
+	// Serve repeated pages from the page cache
+	if o.usePageCache() {
+		result, cached, err := cachedCursorPage(db, dest, o, cursorField, func(filling Option) (*CursorPagination[T], error) {
+			return CursorPaginateString(db, dest, cursor, pageSize, cursorField, ascending, append(opts[:len(opts):len(opts)], filling)...)
+		}, "cursor-string", cursor, pageSize, cursorField, ascending, queryMaxPageSize(db))
+		if cached {
+			return result, err
+		}
+	}
+
+	// Read from the replica, falling back to the primary
+	if o.usesReplica() {
+		return readReplicated(queryContext(db), o.replica, func(route func(*gorm.DB) *gorm.DB, routed Option) (*CursorPagination[T], error) {
+			return CursorPaginateString(route(db), dest, cursor, pageSize, cursorField, ascending, append(opts[:len(opts):len(opts)], routed)...)
+		})
+	}
+
+	if err := checkDestType[T](db); err != nil {
+		return nil, err
+	}
+
+	// Resolve a joined table's cursor field to its quoted column and the column items carry
+	column, err := resolveCursorColumn(db, cursorField, o)
+	if err != nil {
+		return nil, err
+	}
+
 	// Constrain page size
-	if pageSize > {{maxPageSize}} {
-		pageSize = {{maxPageSize}}
+	if limit := queryMaxPageSize(db); pageSize > limit {
+		pageSize = limit
 	}
 	if pageSize < 1 {
-		pageSize = {{defaultPageSize}}
+		pageSize = o.config.DefaultPageSize
 	}
 
-	query := db
+	// Detach the session anchor WithDriftDetection appends to cursors
+	var anchor any
+	if o.driftDetection && cursor != "" {
+		var err error
+		if cursor, anchor, err = splitDriftAnchor(cursor); err != nil {
+			return nil, err
+		}
+		if anchor, err = cursorString(anchor); err != nil {
+			return nil, fmt.Errorf("%w anchor: %v", ErrInvalidCursor, err)
+		}
+	}
+
+	// Detach the tie-breaker value appended to cursors when the cursor field is not unique
+	tie, err := resolveTieBreaker[T](db, column.key, o)
+	if err != nil {
+		return nil, err
+	}
+	if err := qualifyTieBreaker[T](db, tie, column); err != nil {
+		return nil, err
+	}
+	if o.compactCursor > 0 && tie == nil {
+		return nil, fmt.Errorf("%w: compact cursors find truncated values by it", ErrTieBreakerRequired)
+	}
+	var tieValue any
+	var hasTieValue bool
+	if tie != nil && cursor != "" {
+		if cursor, tieValue, hasTieValue, err = splitTieBreaker(cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	// Decode the cursor; a codec may send the request back to the first page
+	decodedCursor, restarted, err := decodeCursor(cursor, o)
+	if err != nil {
+		return nil, err
+	}
+	if restarted {
+		cursor, anchor = "", nil
+	}
+
+	// Snapshot the query before the cursor filter for hybrid offset counting
+	base := db.Session(&gorm.Session{})
+	query := db.Session(&gorm.Session{})
+
+	// A backward page scans from the cursor toward the start of the order
+	scan := ascending != o.backward
 
 	// Apply cursor filter if provided
 	if cursor != "" {
-		decodedCursor, err := DecodeCursor(cursor)
+		cursorValue, err := cursorString(decodedCursor)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("%w value: %v", ErrInvalidCursor, err)
 		}
 
-		if ascending {
-			query = query.Where(fmt.Sprintf("%s > ?", cursorField), decodedCursor)
+		if o.compactCursor > 0 {
+			if query, err = applyCompactCursor(query, base, cursorValue, column.sql, tie, tieValue, hasTieValue, scan, o); err != nil {
+				return nil, err
+			}
+		} else if hasTieValue {
+			query = query.Where(tieBreakerCondition(column.sql, tie.column, scan, o.inclusiveCursor), cursorValue, cursorValue, tieValue)
 		} else {
-			query = query.Where(fmt.Sprintf("%s < ?", cursorField), decodedCursor)
+			query = query.Where(cursorCondition(column.sql, scan, o.inclusiveCursor), cursorValue)
 		}
 	}
 
 	// Order by cursor field
-	if ascending {
-		query = query.Order(fmt.Sprintf("%s ASC", cursorField))
+	if scan {
+		query = query.Order(fmt.Sprintf("%s ASC", column.sql))
 	} else {
-		query = query.Order(fmt.Sprintf("%s DESC", cursorField))
+		query = query.Order(fmt.Sprintf("%s DESC", column.sql))
+	}
+	if tie != nil {
+		query = query.Order(tieBreakerOrder(tie.column, scan))
 	}
 
 	// Fetch one extra item to check for next page
 	var items []T
-	if err := query.Limit(pageSize + 1).Find(&items).Error; err != nil {
+	err = o.fetchLimiter.do(queryContext(query), func() error {
+		return o.onFetchDB(query).Limit(sqlLimit(query, pageSize+1)).Find(&items).Error
+	})
+	if err != nil {
 		return nil, fmt.Errorf("failed to fetch items: %w", err)
 	}
 
 	hasNext := len(items) > pageSize
+	fetched := items
 	if hasNext {
 		items = items[:pageSize]
 	}
 
+	// Cut the page at the client's byte budget
+	items, hasNext, pageBytes, err := applyByteBudget(items, hasNext, o)
+	if err != nil {
+		return nil, err
+	}
+
+	// A backward page was read nearest the cursor first: the extra row tells whether a page
+	// precedes it, and the cursor's own row follows it
+	hasPrevious := cursor != ""
+	if o.backward {
+		hasNext, hasPrevious = cursor != "", hasNext
+		reverseItems(items)
+	}
+
+	items = nonNilItems(items)
 	*dest = items
 
-	// Generate cursors
+	firstTie, lastTie, err := tieBreakerValues(query, items, tie, o)
+	if err != nil {
+		return nil, err
+	}
+
+	firstKey, lastKey, err := boundaryKeys(db, items, column.key)
+	if err != nil {
+		return nil, err
+	}
+
+	tail, leading := pageTail(base, query, column.sql, tie, lastKey, lastTie, len(items), ascending, o)
+	approxRemaining, err := resolveApproxRemaining(tail, leading, hasNext, o)
+	if err != nil {
+		return nil, err
+	}
+
+	hybrid, err := resolveHybridOffset(base, tail, len(items)-leading, pageSize, o)
+	if err != nil {
+		return nil, err
+	}
+
+	// Compact cursors carry the shortened keys
+	cursorFirstKey, cursorLastKey := firstKey, lastKey
+	if len(items) > 0 && o.compactCursor > 0 {
+		cursorFirstKey, cursorLastKey = compactKey(firstKey, o.compactCursor), compactKey(lastKey, o.compactCursor)
+	}
+
+	startCursor, endCursor, err := boundaryCursors(o.cursorCodec(), len(items), cursorFirstKey, cursorLastKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// Continue from the boundary items' cursor field values, as StartCursor and EndCursor do
 	var nextCursor *string
 	var previousCursor *string
+	if hasNext {
+		nextCursor = copyCursor(endCursor)
+	}
+	if hasPrevious {
+		previousCursor = copyCursor(startCursor)
+	}
+
+	result := &CursorPagination[T]{
+		Items:           items,
+		NextCursor:      nextCursor,
+		PreviousCursor:  previousCursor,
+		HasNext:         hasNext,
+		HasPrevious:     hasPrevious,
+		PageSize:        pageSize,
+		ApproxRemaining: approxRemaining,
+		StartCursor:     startCursor,
+		EndCursor:       endCursor,
+		FirstKey:        firstKey,
+		LastKey:         lastKey,
+		Restarted:       restarted,
+	}
+	if o.byteBudget > 0 {
+		result.PageBytes, result.RowCount = pageBytes, len(items)
+	}
+	if o.peek && hasNext && !o.backward {
+		preview := fetched[len(items)]
+		result.NextPreview = &preview
+	}
+	applyHybridOffset(result, hybrid)
+	if err := applyTieBreaker(result, tie, firstTie, lastTie); err != nil {
+		return nil, err
+	}
+
+	if err := applyDriftDetection(result, base, column.sql, ascending, anchor, o); err != nil {
+		return nil, err
+	}
+	result.PreviousCursor = markBackwardCursor(result.PreviousCursor)
+
+	return result, nil
+}
+
+// cursorCondition is the WHERE condition continuing after the cursor value, or from it
+// (including the cursor's own row) with WithInclusiveCursor
+func cursorCondition(cursorField string, ascending, inclusive bool) string {
+	op := ">"
+	if !ascending {
+		op = "<"
+	}
+	if inclusive {
+		op += "="
+	}
+	return fmt.Sprintf("%s %s ?", cursorField, op)
+}
+
+// boundaryCursors encodes the first and last cursor field values of a page of count items
+func boundaryCursors(codec CursorCodec, count int, firstKey, lastKey any) (start, end *string, err error) {
+	if count == 0 {
+		return nil, nil, nil
+	}
+
+	first, err := codec.Encode(firstKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	last, err := codec.Encode(lastKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &first, &last, nil
+}
+
+// reverseItems reverses items in place
+func reverseItems[T any](items []T) {
+	for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+		items[i], items[j] = items[j], items[i]
+	}
+}
+
+// copyCursor returns a copy of cursor (nil stays nil), so the tie-breaker and drift anchor
+// appended to each of a page's cursors are appended to it once
+func copyCursor(cursor *string) *string {
+	if cursor == nil {
+		return nil
+	}
+	copied := *cursor
+	return &copied
+}
+
+// backwardCursorPrefix marks a PreviousCursor so the paginators read the page before it
+// Codec tokens never start with it: base64 has no "~", and snapshot tokens precede theirs.
+const backwardCursorPrefix = "~"
+
+// markBackwardCursor prefixes cursor with backwardCursorPrefix (nil stays nil)
+func markBackwardCursor(cursor *string) *string {
+	if cursor == nil {
+		return nil
+	}
+	marked := backwardCursorPrefix + *cursor
+	return &marked
+}
+
+// splitBackwardCursor strips backwardCursorPrefix from cursor, reporting whether it was there
+func splitBackwardCursor(cursor string) (string, bool) {
+	if len(cursor) > len(backwardCursorPrefix) && cursor[:len(backwardCursorPrefix)] == backwardCursorPrefix {
+		return cursor[len(backwardCursorPrefix):], true
+	}
+	return cursor, false
+}
+
+// pageTail returns a query of the rows after a page in display order, and how many of the
+// page's own rows lead it
+// A forward page was read from the front of query, so its rows lead the tail. A backward page
+// was read from before its cursor, so its tail is the rows past its last item, matched with
+// the display order's comparison rather than the scan's.
+func pageTail(
+	base *gorm.DB,
+	query *gorm.DB,
+	column string,
+	tie *tieBreaker,
+	lastKey any,
+	lastTie any,
+	pageLen int,
+	ascending bool,
+	o options,
+) (*gorm.DB, int) {
+	if !o.backward {
+		return query, pageLen
+	}
+	if pageLen == 0 {
+		// Nothing precedes the cursor, so every row follows the empty page
+		return base, 0
+	}
+	if tie != nil {
+		return base.Where(tieBreakerCondition(column, tie.column, ascending, false), lastKey, lastKey, lastTie), 0
+	}
+	return base.Where(cursorCondition(column, ascending, false), lastKey), 0
+}
+
+// resolveApproxRemaining computes ApproxRemaining when enabled via WithApproxRemaining
+// tail is the query of the rows after the page (see pageTail); consumed is the number of page
+// rows leading it
+func resolveApproxRemaining(query *gorm.DB, consumed int, hasNext bool, o options) (*int64, error) {
+	if o.approxRemainingLimit <= 0 {
+		return nil, nil
+	}
+
+	// The look-ahead row already told us this is the last page
+	if !hasNext {
+		zero := int64(0)
+		return &zero, nil
+	}
+	query = o.onCountDB(query)
+
+	var counted int64
+	err := o.countLimiter.do(queryContext(query), func() (err error) {
+		counted, err = cappedCount(query, consumed+o.approxRemainingLimit)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count remaining items: %w", err)
+	}
 
-	if hasNext && len(items) > 0 {
-		lastCursor := EncodeCursor(items[len(items)-1])
-		nextCursor = &lastCursor
+	remaining := counted - int64(consumed)
+	if remaining < 0 {
+		remaining = 0
 	}
 
-	if cursor != "" && len(items) > 0 {
-		firstCursor := EncodeCursor(items[0])
-		previousCursor = &firstCursor
+	return &remaining, nil
+}
+
+// hybridOffset is the offset-style metadata attached to cursor results by WithHybridOffset
+type hybridOffset struct {
+	currentPage int
+	totalPages  int
+	totalItems  int64
+}
+
+// resolveHybridOffset computes offset-style metadata when the result set is within the threshold
+// base is the query without the cursor filter; tail is the query of the rows after the page
+// (see pageTail), and omitted counts the page's rows it leaves out
+func resolveHybridOffset(base *gorm.DB, tail *gorm.DB, omitted int, pageSize int, o options) (*hybridOffset, error) {
+	if o.hybridThreshold <= 0 {
+		return nil, nil
 	}
+	base, tail = o.onCountDB(base), o.onCountDB(tail)
+
+	// Counting one past the threshold is enough to know the set is too large
+	var total, fromPage int64
+	err := o.countLimiter.do(queryContext(tail), func() (err error) {
+		total, err = cappedCount(base, o.hybridThreshold+1)
+		if err != nil || total > int64(o.hybridThreshold) {
+			return err
+		}
 
-	return &CursorPagination[T]{
-		Items:          items,
-		NextCursor:     nextCursor,
-		PreviousCursor: previousCursor,
-		HasNext:        hasNext,
-		HasPrevious:    cursor != "",
-		PageSize:       pageSize,
+		// Rows from the start of this page onward tell us where the page sits
+		fromPage, err = cappedCount(tail, o.hybridThreshold+1)
+		fromPage += int64(omitted)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count items: %w", err)
+	}
+	if total > int64(o.hybridThreshold) {
+		return nil, nil
+	}
+	position := total - fromPage
+	if position < 0 {
+		position = 0
+	}
+
+	return &hybridOffset{
+		currentPage: int(position)/pageSize + 1,
+		totalPages:  int(math.Ceil(float64(total) / float64(pageSize))),
+		totalItems:  total,
 	}, nil
 }
+
+// applyHybridOffset copies hybrid metadata onto a cursor result (no-op when hybrid mode did not engage)
+func applyHybridOffset[T any](result *CursorPagination[T], h *hybridOffset) {
+	if h == nil {
+		return
+	}
+
+	result.CurrentPage = &h.currentPage
+	result.TotalPages = &h.totalPages
+	result.TotalItems = &h.totalItems
+}
+
+// cappedCount counts the rows of query, stopping after limit rows
+// The LIMIT sits inside a subquery so COUNT never walks the whole table. A grouped query keeps
+// its select list, which its HAVING may refer to, and counts its groups.
+func cappedCount(query *gorm.DB, limit int) (int64, error) {
+	var counted int64
+	window := query.Session(&gorm.Session{})
+	if !isGrouped(query) {
+		window = window.Select("1")
+	}
+	window = window.Limit(limit)
+	err := query.Session(&gorm.Session{NewDB: true}).
+		Table("(?) AS capped_window", window).
+		Count(&counted).Error
+
+	return counted, err
+}
@@ -0,0 +1,102 @@
+//go:build paginationsqlite
+
+package pagination
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// ticket's cursor column is named apart from its field, Sequence is a nullable column, and
+// Code a string key
+type ticket struct {
+	Serial   int64 `gorm:"column:ticket_no"`
+	Sequence *int64
+	Code     string
+}
+
+// seedTickets inserts n tickets out of key order, so only the paginators' ORDER BY lines
+// them up
+func seedTickets(t *testing.T, n int) *gorm.DB {
+	t.Helper()
+	db := openSQLite(t, &ticket{})
+	for _, i := range []int64{5, 2, 7, 1, 4, 6, 3}[:n] {
+		sequence := i * 10
+		item := ticket{Serial: i, Sequence: &sequence, Code: fmt.Sprintf("T-%03d", i)}
+		if err := db.Create(&item).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db.Model(&ticket{})
+}
+
+func TestCursorPaginatorsFollowNextCursorOnSQLite(t *testing.T) {
+	type paginate func(db *gorm.DB, dest *[]ticket, cursor string, pageSize int, cursorField string, ascending bool, opts ...Option) (*CursorPagination[ticket], error)
+
+	for _, tc := range []struct {
+		name     string
+		paginate paginate
+		column   string
+		key      func(ticket) any
+	}{
+		{
+			name:     "int column tag",
+			paginate: CursorPaginateInt[ticket],
+			column:   "ticket_no",
+			key:      func(t ticket) any { return t.Serial },
+		},
+		{
+			name:     "int pointer field",
+			paginate: CursorPaginateInt[ticket],
+			column:   "sequence",
+			key:      func(t ticket) any { return *t.Sequence },
+		},
+		{
+			name:     "string",
+			paginate: CursorPaginateString[ticket],
+			column:   "code",
+			key:      func(t ticket) any { return t.Code },
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			db := seedTickets(t, 7)
+
+			var seen []int64
+			var page []ticket
+			cursor := ""
+			for pages := 1; ; pages++ {
+				result, err := tc.paginate(db, &page, cursor, 3, tc.column, true)
+				if err != nil {
+					t.Fatalf("page %d: %v", pages, err)
+				}
+				for _, item := range result.Items {
+					seen = append(seen, item.Serial)
+				}
+				if cursor != "" {
+					marked, backward := splitBackwardCursor(*result.PreviousCursor)
+					previous, err := DecodeCursor(marked, tc.column, true)
+					if err != nil || !backward || previous != fmt.Sprint(tc.key(result.Items[0])) {
+						t.Errorf("page %d: previous cursor decodes to %q (%v), want the first item's key", pages, previous, err)
+					}
+				}
+				if result.NextCursor == nil {
+					if pages != 3 {
+						t.Errorf("ended after %d pages, want 3", pages)
+					}
+					break
+				}
+				next, err := DecodeCursor(*result.NextCursor, tc.column, true)
+				if err != nil || next != fmt.Sprint(tc.key(result.Items[len(result.Items)-1])) {
+					t.Fatalf("page %d: next cursor decodes to %q (%v), want the last item's key", pages, next, err)
+				}
+				cursor = *result.NextCursor
+			}
+			if !reflect.DeepEqual(seen, []int64{1, 2, 3, 4, 5, 6, 7}) {
+				t.Errorf("paged through %v, want every ticket once", seen)
+			}
+		})
+	}
+}
@@ -0,0 +1,306 @@
+package pagination
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeCursorPage pages ids the way CursorPaginateInt does, filtering rows with the
+// condition cursorCondition builds and returning the page's boundary cursors
+func fakeCursorPage(t *testing.T, rows []int64, cursor string, pageSize int, opts ...Option) *CursorPagination[int64] {
+	t.Helper()
+	o := applyOptions(opts)
+	codec := o.cursorCodec()
+
+	condition := cursorCondition("id", true, o.inclusiveCursor)
+	var op string
+	if _, err := fmt.Sscanf(condition, "id %s ?", &op); err != nil {
+		t.Fatalf("unexpected condition %q", condition)
+	}
+
+	var items []int64
+	for _, id := range rows {
+		if cursor != "" {
+			value, err := codec.Decode(cursor)
+			if err != nil {
+				t.Fatal(err)
+			}
+			after, err := cursorInt(value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (op == ">" && id <= after) || (op == ">=" && id < after) {
+				continue
+			}
+		}
+		if len(items) < pageSize {
+			items = append(items, id)
+		}
+	}
+
+	result := &CursorPagination[int64]{Items: items, PageSize: pageSize}
+	if len(items) > 0 {
+		result.FirstKey, result.LastKey = items[0], items[len(items)-1]
+	}
+	start, end, err := boundaryCursors(codec, len(items), result.FirstKey, result.LastKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result.StartCursor, result.EndCursor = start, end
+	return result
+}
+
+func TestCursorCondition(t *testing.T) {
+	cases := map[string]string{
+		cursorCondition("id", true, false):  "id > ?",
+		cursorCondition("id", false, false): "id < ?",
+		cursorCondition("id", true, true):   "id >= ?",
+		cursorCondition("id", false, true):  "id <= ?",
+	}
+	for got, want := range cases {
+		if got != want {
+			t.Errorf("condition = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestStartCursorReproducesPage(t *testing.T) {
+	rows := []int64{3, 5, 8, 13, 21, 34, 55, 89}
+
+	first := fakeCursorPage(t, rows, "", 3)
+	second := fakeCursorPage(t, rows, *first.EndCursor, 3)
+	if !reflect.DeepEqual(second.Items, []int64{13, 21, 34}) {
+		t.Fatalf("second page = %v", second.Items)
+	}
+	if second.StartCursor == nil || second.EndCursor == nil {
+		t.Fatal("a non-empty page should have start and end cursors")
+	}
+
+	// Re-issuing the start cursor inclusively fetches the identical window
+	again := fakeCursorPage(t, rows, *second.StartCursor, 3, WithInclusiveCursor())
+	if !reflect.DeepEqual(again.Items, second.Items) {
+		t.Errorf("inclusive re-fetch = %v, want %v", again.Items, second.Items)
+	}
+	if *again.StartCursor != *second.StartCursor || *again.EndCursor != *second.EndCursor {
+		t.Error("the re-fetched page should have the same boundary cursors")
+	}
+
+	// Without Inclusive mode the start cursor's own row is skipped
+	shifted := fakeCursorPage(t, rows, *second.StartCursor, 3)
+	if reflect.DeepEqual(shifted.Items, second.Items) {
+		t.Error("an exclusive cursor should continue after its row")
+	}
+
+	empty := fakeCursorPage(t, rows, *fakeCursorPage(t, rows, "", 8).EndCursor, 3)
+	if len(empty.Items) != 0 || empty.StartCursor != nil || empty.EndCursor != nil {
+		t.Errorf("empty page = %+v", empty)
+	}
+}
+
+func TestStartCursorInResponses(t *testing.T) {
+	start, end := "s", "e"
+	page := &CursorPagination[int]{Items: []int{1, 2}, StartCursor: &start, EndCursor: &end}
+
+	meta := page.ToResponse("").Pagination
+	if meta.StartCursor != &start || meta.EndCursor != &end {
+		t.Errorf("meta cursors = %v, %v", meta.StartCursor, meta.EndCursor)
+	}
+
+	info := page.ToRelayConnection(func(item int) string { return fmt.Sprint(item) }).PageInfo
+	if *info.StartCursor != "s" || *info.EndCursor != "e" {
+		t.Errorf("PageInfo cursors = %s, %s", *info.StartCursor, *info.EndCursor)
+	}
+}
+
+// restartCodec sends every cursor back to the first page, the way a filter-bound codec does
+// once the filters change
+type restartCodec struct{ Base64CursorCodec }
+
+func (restartCodec) Decode(cursor string) (any, error) {
+	return nil, fmt.Errorf("%w: filters changed", ErrRestartPagination)
+}
+
+func TestCursorRestartsOnCodecRequest(t *testing.T) {
+	var posts []feedPost
+	for id := int64(3); id >= 1; id-- {
+		posts = append(posts, feedPost{ID: id})
+	}
+	var boundary, anchor int64
+	db := feedDB(t, &posts, &boundary, &anchor)
+
+	var page []feedPost
+	result, err := CursorPaginateInt(db.Model(&feedPost{}), &page, "c3RhbGU", 2, "id", false, WithCursorCodec(restartCodec{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Restarted || result.HasPrevious {
+		t.Errorf("restarted %t, has previous %t", result.Restarted, result.HasPrevious)
+	}
+	if !result.ToResponse("/posts").Pagination.Restarted {
+		t.Error("restarted missing from the response")
+	}
+
+	// Other decode errors still fail the request
+	if _, err := CursorPaginateInt(db.Model(&feedPost{}), &page, "%%%", 2, "id", false); err == nil {
+		t.Error("expected an invalid cursor to fail")
+	}
+}
+
+func TestNextCursorTakesTieBreakerOnce(t *testing.T) {
+	next, end := "7", "7"
+	result := &CursorPagination[int64]{Items: []int64{7}, NextCursor: copyCursor(&next), EndCursor: &end}
+	if err := applyTieBreaker(result, &tieBreaker{column: "id"}, int64(7), int64(7)); err != nil {
+		t.Fatal(err)
+	}
+	if *result.NextCursor != *result.EndCursor || next != "7" {
+		t.Errorf("next cursor %q, end cursor %q, want the same token", *result.NextCursor, *result.EndCursor)
+	}
+}
+
+func TestHybridOffset(t *testing.T) {
+	var page []post
+
+	// Seven posts are within the threshold: every page gets page numbers
+	small := countedPostsDB(t, postsOf(7, 10))
+	first, err := CursorPaginateInt(small, &page, "", 3, "id", true, WithHybridOffset(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deref(first.CurrentPage) != 1 || deref(first.TotalPages) != 3 || deref(first.TotalItems) != int64(7) {
+		t.Errorf("first page: page %v of %v (%v items), want 1 of 3 (7 items)",
+			deref(first.CurrentPage), deref(first.TotalPages), deref(first.TotalItems))
+	}
+	second, err := CursorPaginateInt(small, &page, *first.NextCursor, 3, "id", true, WithHybridOffset(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deref(second.CurrentPage) != 2 || second.NextCursor == nil {
+		t.Errorf("second page: page %v (next %v), want 2 with a next cursor", deref(second.CurrentPage), second.NextCursor)
+	}
+
+	// Paging back from the second page lands on page 1 again
+	back, err := CursorPaginateInt(small, &page, *second.PreviousCursor, 3, "id", true, WithHybridOffset(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(postIDs(back.Items), []int64{1, 2, 3}) || deref(back.CurrentPage) != 1 || deref(back.TotalPages) != 3 {
+		t.Errorf("back: %v on page %v of %v, want [1 2 3] on 1 of 3",
+			postIDs(back.Items), deref(back.CurrentPage), deref(back.TotalPages))
+	}
+
+	// Thirty posts are past it: pure cursor metadata, forward and backward
+	large := countedPostsDB(t, postsOf(30, 10))
+	forward, err := CursorPaginateInt(large, &page, "", 3, "id", true, WithHybridOffset(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, err := CursorPaginateInt(large, &page, *forward.NextCursor, 3, "id", true, WithHybridOffset(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous, err := CursorPaginateInt(large, &page, *next.PreviousCursor, 3, "id", true, WithHybridOffset(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, result := range map[string]*CursorPagination[post]{"first": forward, "second": next, "back": previous} {
+		if result.CurrentPage != nil || result.TotalPages != nil || result.TotalItems != nil {
+			t.Errorf("%s page of a large set: page %v of %v (%v items), want no offset metadata",
+				name, deref(result.CurrentPage), deref(result.TotalPages), deref(result.TotalItems))
+		}
+		if result.NextCursor == nil {
+			t.Errorf("%s page of a large set has no next cursor", name)
+		}
+	}
+}
+
+func TestApproxRemainingBoundsTrueRemaining(t *testing.T) {
+	db := countedPostsDB(t, postsOf(10, 10))
+	var page []post
+
+	// Ten posts in pages of 3: 7, 4, 1, then 0 remain after each page
+	cursor := ""
+	for _, want := range []int64{7, 4, 1, 0} {
+		result, err := CursorPaginateInt(db, &page, cursor, 3, "id", true, WithApproxRemaining(100))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if deref(result.ApproxRemaining) != want {
+			t.Errorf("remaining after %v = %v, want %d", postIDs(result.Items), deref(result.ApproxRemaining), want)
+		}
+		if result.NextCursor == nil {
+			break
+		}
+		cursor = *result.NextCursor
+	}
+
+	// A cap below the true remaining count is reported as the cap, never more
+	capped, err := CursorPaginateInt(db, &page, "", 3, "id", true, WithApproxRemaining(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deref(capped.ApproxRemaining) != int64(5) {
+		t.Errorf("capped remaining = %v, want 5 of the 7", deref(capped.ApproxRemaining))
+	}
+}
+
+func TestCursorResultBoundaryKeys(t *testing.T) {
+	db := postsDB(t, postsOf(7, 10))
+	var page []post
+
+	// Newest first: 7 6 5, then 4 3 2, then back to 7 6 5, then the last page, 1
+	first, err := CursorPaginateInt(db, &page, "", 3, "id", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := CursorPaginateInt(db, &page, *first.NextCursor, 3, "id", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	back, err := CursorPaginateInt(db, &page, *second.PreviousCursor, 3, "id", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last, err := CursorPaginateInt(db, &page, *second.NextCursor, 3, "id", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, tc := range map[string]struct {
+		result      *CursorPagination[post]
+		first, last int64
+	}{
+		"first":  {first, 7, 5},
+		"second": {second, 4, 2},
+		"back":   {back, 7, 5},
+		"last":   {last, 1, 1},
+	} {
+		items := tc.result.Items
+		if tc.result.FirstKey != items[0].ID || tc.result.LastKey != items[len(items)-1].ID {
+			t.Errorf("%s page %v: keys %v..%v, want the first and last rows' ids", name, postIDs(items), tc.result.FirstKey, tc.result.LastKey)
+		}
+		if tc.result.FirstKey != tc.first || tc.result.LastKey != tc.last {
+			t.Errorf("%s page: keys %v..%v, want %d..%d", name, tc.result.FirstKey, tc.result.LastKey, tc.first, tc.last)
+		}
+	}
+
+	// The keys stay out of the JSON clients see
+	for _, v := range []any{first, first.ToResponse("/posts")} {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if body := strings.ToLower(string(raw)); strings.Contains(body, "firstkey") || strings.Contains(body, "first_key") {
+			t.Errorf("boundary keys serialized: %s", raw)
+		}
+	}
+
+	empty, err := CursorPaginateInt(postsDB(t, nil), &page, "", 3, "id", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if empty.FirstKey != nil || empty.LastKey != nil {
+		t.Errorf("empty page keys %v..%v, want nil", empty.FirstKey, empty.LastKey)
+	}
+}
@@ -0,0 +1,132 @@
+package pagination
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrCursorMismatch is returned when a cursor payload was issued for another cursor field or
+// direction than the endpoint pages by
+var ErrCursorMismatch = errors.New("cursor was issued for a different field or direction")
+
+// cursorPayloadVersion is the v of the payloads EncodeCursor and PayloadCursorCodec issue
+const cursorPayloadVersion = 1
+
+// cursorPayload is the structured cursor: the value and the field and direction it belongs to
+type cursorPayload struct {
+	Field     string `json:"field"`
+	Value     any    `json:"value"`
+	Direction string `json:"direction"`
+	Version   int    `json:"v"`
+}
+
+// payloadCodec encodes cursors as payloads for one field and direction
+// anyDirection accepts payloads of either direction, for endpoints that continue a listing
+// in the other order.
+type payloadCodec struct {
+	field        string
+	direction    string
+	anyDirection bool
+}
+
+// PayloadCursorCodec issues structured cursors: base64url JSON of {field, value, direction, v}
+// It is the codec of CursorPaginateInt, CursorPaginateString, and CursorPaginateTime unless
+// WithCursorCodec replaces it; pass it explicitly only to wrap it in another codec.
+// Decoding a payload issued for another field or direction fails with ErrCursorMismatch, and
+// one that is malformed, of an unknown version, or carrying a non-scalar value with
+// ErrInvalidCursor. Plain-value cursors (Base64CursorCodec, the encoding before payloads) are
+// still accepted for one release, with a warning via OnLegacyCursor.
+//
+// The payload is not signed: it stops clients reusing a cursor on the wrong endpoint, not
+// crafting values.
+//
+// Example usage:
+//
+//	result, err := pagination.CursorPaginateString(db, &events, cursor, 20, "created_at", false)
+//	if errors.Is(err, pagination.ErrCursorMismatch) {
+//	    c.JSON(400, gin.H{"error": "cursor belongs to another listing; restart pagination"})
+//	    return
+//	}
+func PayloadCursorCodec(field string, ascending bool) CursorCodec {
+	return payloadCodec{field: field, direction: cursorDirection(ascending)}
+}
+
+// defaultPayloadCodec makes payloads for field the codec of a single-field cursor paginator
+// called without WithCursorCodec
+func (o *options) defaultPayloadCodec(field string, ascending bool) {
+	if o.codec == nil {
+		o.codec = PayloadCursorCodec(field, ascending)
+	}
+}
+
+func (c payloadCodec) Encode(value any) (string, error) {
+	raw, err := json.Marshal(cursorPayload{
+		Field:     c.field,
+		Value:     value,
+		Direction: c.direction,
+		Version:   cursorPayloadVersion,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func (c payloadCodec) Decode(cursor string) (any, error) {
+	payload, err := parseCursorPayload(cursor)
+	if err != nil {
+		return nil, err
+	}
+	if payload == nil {
+		value, err := decodeValueCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		OnLegacyCursor(c.field)
+		return value, nil
+	}
+
+	if payload.Version != cursorPayloadVersion {
+		return nil, fmt.Errorf("%w: unsupported cursor version %d", ErrInvalidCursor, payload.Version)
+	}
+	reversed := c.anyDirection && payload.Direction == cursorDirection(c.direction == "desc")
+	if payload.Field != c.field || (payload.Direction != c.direction && !reversed) {
+		return nil, fmt.Errorf("%w: cursor is for %q %s, paginating by %q %s",
+			ErrCursorMismatch, payload.Field, payload.Direction, c.field, c.direction)
+	}
+	switch payload.Value.(type) {
+	case string, json.Number:
+		return payload.Value, nil
+	default:
+		return nil, fmt.Errorf("%w: cursor value of type %T", ErrInvalidCursor, payload.Value)
+	}
+}
+
+// parseCursorPayload decodes a payload cursor, or returns nil for one that is not a JSON
+// object: a legacy plain-value cursor (or garbage DecodeCursor rejects)
+// An object that does not parse as a payload is a forgery, not a legacy cursor.
+func parseCursorPayload(cursor string) (*cursorPayload, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !bytes.HasPrefix(raw, []byte("{")) {
+		return nil, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var payload cursorPayload
+	if err := decoder.Decode(&payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	return &payload, nil
+}
+
+// cursorDirection names an order's direction in cursor payloads
+func cursorDirection(ascending bool) string {
+	if ascending {
+		return "asc"
+	}
+	return "desc"
+}
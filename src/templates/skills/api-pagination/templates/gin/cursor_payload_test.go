@@ -0,0 +1,108 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCursorPaginateIssuesPayloadsByDefault(t *testing.T) {
+	db := postsDB(t, postsOf(7, 10))
+
+	var seen []int64
+	var page []post
+	cursor := ""
+	for pages := 0; pages < 5; pages++ {
+		result, err := CursorPaginateInt(db, &page, cursor, 3, "id", true)
+		if err != nil {
+			t.Fatalf("page %d: %v", pages+1, err)
+		}
+		seen = append(seen, postIDs(result.Items)...)
+		if result.NextCursor == nil {
+			break
+		}
+		cursor = *result.NextCursor
+	}
+	if !reflect.DeepEqual(seen, []int64{1, 2, 3, 4, 5, 6, 7}) {
+		t.Errorf("paged through %v, want every post once", seen)
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || string(raw) != `{"field":"id","value":6,"direction":"asc","v":1}` {
+		t.Errorf("cursor payload = %s (%v)", raw, err)
+	}
+}
+
+func TestPayloadCursorCodecMismatch(t *testing.T) {
+	issued, err := PayloadCursorCodec("created_at", true).Encode("2024-05-01T10:00:00Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, codec := range []CursorCodec{
+		PayloadCursorCodec("id", true),
+		PayloadCursorCodec("created_at", false),
+	} {
+		if _, err := codec.Decode(issued); !errors.Is(err, ErrCursorMismatch) {
+			t.Errorf("%+v: decoding a created_at ascending cursor returned %v, want ErrCursorMismatch", codec, err)
+		}
+	}
+	if value, err := PayloadCursorCodec("created_at", true).Decode(issued); err != nil || value != "2024-05-01T10:00:00Z" {
+		t.Errorf("matching codec decoded %v (%v)", value, err)
+	}
+
+	if _, err := DecodeCursor(EncodeCursor("id", 3, true), "id", false); !errors.Is(err, ErrCursorMismatch) {
+		t.Errorf("DecodeCursor of an ascending id cursor returned %v, want ErrCursorMismatch", err)
+	}
+	if value, err := DecodeCursor(EncodeCursor("id", 3, true), "id", true); err != nil || value != "3" {
+		t.Errorf("DecodeCursor = %q (%v), want 3", value, err)
+	}
+}
+
+func TestPayloadCursorCodecRejectsForgedPayloads(t *testing.T) {
+	codec := PayloadCursorCodec("id", true)
+	for name, payload := range map[string]string{
+		"object value":  `{"field":"id","value":{"$gt":0},"direction":"asc","v":1}`,
+		"array value":   `{"field":"id","value":[1,2],"direction":"asc","v":1}`,
+		"null value":    `{"field":"id","value":null,"direction":"asc","v":1}`,
+		"no version":    `{"field":"id","value":3,"direction":"asc"}`,
+		"future":        `{"field":"id","value":3,"direction":"asc","v":2}`,
+		"wrong types":   `{"field":1,"value":3,"direction":"asc","v":1}`,
+		"truncated":     `{"field":"id","value":3,`,
+		"no field":      `{"value":3,"direction":"asc","v":1}`,
+		"no direction":  `{"field":"id","value":3,"v":1}`,
+		"reversed dir":  `{"field":"id","value":3,"direction":"ASC","v":1}`,
+		"other columns": `{"field":"id OR 1=1","value":3,"direction":"asc","v":1}`,
+	} {
+		cursor := base64.RawURLEncoding.EncodeToString([]byte(payload))
+		_, err := codec.Decode(cursor)
+		if !errors.Is(err, ErrInvalidCursor) && !errors.Is(err, ErrCursorMismatch) {
+			t.Errorf("%s: decoded %s with %v, want it rejected", name, payload, err)
+		}
+	}
+}
+
+func TestPayloadCursorCodecAcceptsLegacyCursors(t *testing.T) {
+	var warned []string
+	saved := OnLegacyCursor
+	OnLegacyCursor = func(field string) { warned = append(warned, field) }
+	defer func() { OnLegacyCursor = saved }()
+
+	db := postsDB(t, postsOf(7, 10))
+	var page []post
+	result, err := CursorPaginateInt(db, &page, encodeValueCursor(3), 3, "id", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(postIDs(result.Items), []int64{4, 5, 6}) {
+		t.Errorf("page after legacy cursor 3 = %v, want [4 5 6]", postIDs(result.Items))
+	}
+	if !reflect.DeepEqual(warned, []string{"id"}) {
+		t.Errorf("legacy warnings = %v, want one for id", warned)
+	}
+
+	if _, err := PayloadCursorCodec("id", true).Decode("not base64!"); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("garbage cursor returned %v, want ErrInvalidCursor", err)
+	}
+}
@@ -0,0 +1,103 @@
+package pagination
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrCursorExpired is returned when a cursor is older than the endpoint's cursor TTL
+// Respond with 410 Gone; the client has to start over from the first page.
+var ErrCursorExpired = errors.New("cursor has expired")
+
+// cursorIssuedSeparator joins a cursor to its issued-at Unix time
+// It is outside both base64 alphabets and the other cursor separators.
+const cursorIssuedSeparator = "@"
+
+// cursorClock is the time cursors are stamped and checked with (replaced in tests)
+var cursorClock = time.Now
+
+// EncodeCursorWithIssuedAt encodes value as Base64CursorCodec does and stamps it with the
+// time it was issued, for DecodeCursorWithTTL
+// The stamp is not signed on its own: sign the result (see WithSignedCursors) or clients can
+// restamp it.
+//
+// Example usage:
+//
+//	next := pagination.EncodeCursorWithIssuedAt(lastID)
+//	// later
+//	value, err := pagination.DecodeCursorWithTTL(c.Query("cursor"), 10*time.Minute)
+//	if errors.Is(err, pagination.ErrCursorExpired) {
+//	    c.JSON(410, gin.H{"error": "cursor expired; reload the feed"})
+//	    return
+//	}
+func EncodeCursorWithIssuedAt(value any) string {
+	return stampCursor(encodeValueCursor(value), cursorClock())
+}
+
+// DecodeCursorWithTTL decodes a cursor from EncodeCursorWithIssuedAt, failing with
+// ErrCursorExpired once it is older than maxAge
+// A cursor without a readable stamp fails with ErrInvalidCursor.
+func DecodeCursorWithTTL(cursor string, maxAge time.Duration) (string, error) {
+	if cursor == "" {
+		return "", nil
+	}
+	value, err := checkCursorAge(cursor, maxAge)
+	if err != nil {
+		return "", err
+	}
+	return decodeValueCursor(value)
+}
+
+// stampCursor appends the Unix time at to cursor
+func stampCursor(cursor string, at time.Time) string {
+	return cursor + cursorIssuedSeparator + strconv.FormatInt(at.Unix(), 10)
+}
+
+// checkCursorAge strips the stamp stampCursor appended, failing if it is older than maxAge
+func checkCursorAge(cursor string, maxAge time.Duration) (string, error) {
+	i := strings.LastIndex(cursor, cursorIssuedSeparator)
+	if i < 0 {
+		return "", fmt.Errorf("%w: missing issued-at time", ErrInvalidCursor)
+	}
+	issued, err := strconv.ParseInt(cursor[i+1:], 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("%w issued-at time: %v", ErrInvalidCursor, err)
+	}
+	if age := cursorClock().Sub(time.Unix(issued, 0)); age > maxAge {
+		return "", fmt.Errorf("%w: issued %s ago, limit %s", ErrCursorExpired, age.Round(time.Second), maxAge)
+	}
+	return cursor[:i], nil
+}
+
+// expireCursors serves a page of a WithCursorTTL call
+// It checks and strips the cursor's issued-at time, runs the paginator on the bare cursor,
+// and stamps every cursor of the result with the current time.
+func expireCursors[T any](
+	cursor string,
+	maxAge time.Duration,
+	run func(cursor string, stamped Option) (*CursorPagination[T], error),
+) (*CursorPagination[T], error) {
+	if cursor != "" {
+		var err error
+		if cursor, err = checkCursorAge(cursor, maxAge); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := run(cursor, func(o *options) { o.cursorStamped = true })
+	if err != nil {
+		return nil, err
+	}
+
+	now := cursorClock()
+	for _, cursor := range []**string{&result.NextCursor, &result.PreviousCursor, &result.StartCursor, &result.EndCursor} {
+		if *cursor != nil {
+			stamped := stampCursor(**cursor, now)
+			*cursor = &stamped
+		}
+	}
+	return result, nil
+}
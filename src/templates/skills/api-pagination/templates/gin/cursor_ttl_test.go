@@ -0,0 +1,82 @@
+package pagination
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setCursorClock makes cursorClock return *now for the rest of the test
+func setCursorClock(t *testing.T, now *time.Time) {
+	t.Helper()
+	saved := cursorClock
+	cursorClock = func() time.Time { return *now }
+	t.Cleanup(func() { cursorClock = saved })
+}
+
+func TestDecodeCursorWithTTL(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	setCursorClock(t, &now)
+
+	cursor := EncodeCursorWithIssuedAt(42)
+
+	now = now.Add(10 * time.Minute)
+	if value, err := DecodeCursorWithTTL(cursor, 10*time.Minute); err != nil || value != "42" {
+		t.Errorf("at the TTL decoded %q (%v), want 42", value, err)
+	}
+
+	now = now.Add(time.Second)
+	if _, err := DecodeCursorWithTTL(cursor, 10*time.Minute); !errors.Is(err, ErrCursorExpired) {
+		t.Errorf("past the TTL returned %v, want ErrCursorExpired", err)
+	}
+
+	for _, unstamped := range []string{encodeValueCursor(42), encodeValueCursor(42) + cursorIssuedSeparator + "yesterday"} {
+		if _, err := DecodeCursorWithTTL(unstamped, time.Hour); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("cursor %q returned %v, want ErrInvalidCursor", unstamped, err)
+		}
+	}
+}
+
+func TestWithCursorTTLExpiresSignedCursors(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	setCursorClock(t, &now)
+
+	db := postsDB(t, postsOf(7, 10))
+	opts := []Option{WithSignedCursors(testCursorSecret), WithCursorTTL(10 * time.Minute)}
+	var page []post
+
+	first, err := CursorPaginateInt(db, &page, "", 3, "id", true, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each page restamps its cursors, so a steady scroll never expires
+	now = now.Add(9 * time.Minute)
+	second, err := CursorPaginateInt(db, &page, *first.NextCursor, 3, "id", true, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(postIDs(second.Items), []int64{4, 5, 6}) {
+		t.Errorf("second page = %v, want [4 5 6]", postIDs(second.Items))
+	}
+
+	// A scroll resumed after the TTL does
+	now = now.Add(11 * time.Minute)
+	if _, err := CursorPaginateInt(db, &page, *second.NextCursor, 3, "id", true, opts...); !errors.Is(err, ErrCursorExpired) {
+		t.Errorf("stale cursor returned %v, want ErrCursorExpired", err)
+	}
+
+	// The signature covers the stamp: restamping the stale cursor breaks it
+	stale, err := verifyCursor(*second.NextCursor, testCursorSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := strings.LastIndex(stale, cursorIssuedSeparator)
+	restamped := stale[:i+1] + strconv.FormatInt(now.Unix(), 10) + (*second.NextCursor)[len(stale):]
+	if _, err := CursorPaginateInt(db, &page, restamped, 3, "id", true, opts...); !errors.Is(err, ErrCursorTampered) {
+		t.Errorf("restamped cursor returned %v, want ErrCursorTampered", err)
+	}
+}
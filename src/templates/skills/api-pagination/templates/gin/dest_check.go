@@ -0,0 +1,50 @@
+package pagination
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// ErrDestTypeMismatch is returned when the dest element type does not fit the query's model
+// GORM would otherwise scan rows of one table into an unrelated struct without complaint
+var ErrDestTypeMismatch = errors.New("dest element type does not match query model")
+
+// schemaCache caches parsed model schemas for dest type checks and field extraction
+var schemaCache sync.Map
+
+// checkDestType verifies that T can hold rows of the model set on the query via db.Model
+// T passes when it is the model type itself or a projection whose columns all belong to the
+// model (e.g. a UserSummary DTO selected from User). Queries without a model, Raw/Table
+// queries, and non-struct element types cannot be checked and always pass.
+func checkDestType[T any](db *gorm.DB) error {
+	if db.Statement == nil || db.Statement.Model == nil {
+		return nil
+	}
+
+	modelSchema, err := schema.Parse(db.Statement.Model, &schemaCache, db.NamingStrategy)
+	if err != nil {
+		return nil
+	}
+
+	destSchema, err := schema.Parse(new(T), &schemaCache, db.NamingStrategy)
+	if err != nil {
+		return nil
+	}
+
+	if destSchema.ModelType == modelSchema.ModelType {
+		return nil
+	}
+
+	for _, column := range destSchema.DBNames {
+		if _, ok := modelSchema.FieldsByDBName[column]; !ok {
+			return fmt.Errorf("%w: %s has column %q, which model %s (table %s) does not",
+				ErrDestTypeMismatch, destSchema.Name, column, modelSchema.Name, modelSchema.Table)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package pagination
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// stockItem is the model the dest checks query
+type stockItem struct {
+	ID   int64
+	Name string
+}
+
+// stockItemName is a projection of stockItem
+type stockItemName struct {
+	ID   int64
+	Name string
+}
+
+// shipment shares no columns with stockItem beyond its primary key
+type shipment struct {
+	ID      int64
+	Carrier string
+}
+
+func TestCheckDestTypeRejectsOtherModels(t *testing.T) {
+	db, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := db.Model(&stockItem{})
+
+	if err := checkDestType[shipment](query); !errors.Is(err, ErrDestTypeMismatch) {
+		t.Errorf("shipment dest: err = %v, want ErrDestTypeMismatch", err)
+	}
+	if err := checkDestType[stockItem](query); err != nil {
+		t.Errorf("model dest: err = %v", err)
+	}
+	if err := checkDestType[stockItemName](query); err != nil {
+		t.Errorf("projection dest: err = %v", err)
+	}
+	if err := checkDestType[shipment](db); err != nil {
+		t.Errorf("query without a model: err = %v, want it unchecked", err)
+	}
+}
+
+func TestPaginatorsRejectMismatchedDest(t *testing.T) {
+	db, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var shipments []shipment
+	if _, err := CursorPaginateInt(db.Model(&stockItem{}), &shipments, "", 10, "id", true); !errors.Is(err, ErrDestTypeMismatch) {
+		t.Errorf("CursorPaginateInt: err = %v, want ErrDestTypeMismatch", err)
+	}
+	if _, err := OffsetPaginate(db.Model(&stockItem{}), &shipments, 1, 10); !errors.Is(err, ErrDestTypeMismatch) {
+		t.Errorf("OffsetPaginate: err = %v, want ErrDestTypeMismatch", err)
+	}
+	if len(shipments) != 0 {
+		t.Errorf("dest = %v, want it untouched", shipments)
+	}
+}
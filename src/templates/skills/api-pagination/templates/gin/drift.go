@@ -0,0 +1,86 @@
+package pagination
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// driftAnchorSeparator joins a WithDriftDetection cursor to its session anchor
+// The anchor is appended, so snapshot tokens and codec prefixes keep leading the cursor; it is
+// JSON-base64url encoded and never contains the separator itself.
+const driftAnchorSeparator = "."
+
+// splitDriftAnchor separates a WithDriftDetection cursor into the page cursor and the
+// session's anchor value
+func splitDriftAnchor(cursor string) (string, any, error) {
+	i := strings.LastIndex(cursor, driftAnchorSeparator)
+	if i < 0 {
+		return "", nil, fmt.Errorf("%w: missing drift anchor", ErrInvalidCursor)
+	}
+
+	anchor, err := JSONCursorCodec{}.Decode(cursor[i+1:])
+	if err != nil {
+		return "", nil, err
+	}
+	return cursor[:i], anchor, nil
+}
+
+// applyDriftDetection reports rows that now sort before the session's anchor and carries the
+// anchor into every cursor of result (no-op without WithDriftDetection)
+// anchor is the value read from the request cursor, or nil on the first page, whose first
+// row becomes the anchor. base is the query without the cursor filter.
+func applyDriftDetection[T any](
+	result *CursorPagination[T],
+	base *gorm.DB,
+	cursorField string,
+	ascending bool,
+	anchor any,
+	o options,
+) error {
+	if !o.driftDetection {
+		return nil
+	}
+
+	if anchor == nil {
+		anchor = result.FirstKey
+	} else {
+		newItems, err := resolveDrift(base, cursorField, ascending, anchor, o)
+		if err != nil {
+			return err
+		}
+		result.NewItemsAvailable = newItems
+	}
+	if anchor == nil {
+		return nil
+	}
+
+	token, err := JSONCursorCodec{}.Encode(anchor)
+	if err != nil {
+		return err
+	}
+	for _, cursor := range []*string{result.NextCursor, result.PreviousCursor, result.StartCursor, result.EndCursor} {
+		if cursor != nil {
+			*cursor += driftAnchorSeparator + token
+		}
+	}
+	return nil
+}
+
+// resolveDrift reports whether any row sorts before anchor
+// One row is enough, so the probe is a count capped at 1 (an EXISTS in effect)
+func resolveDrift(base *gorm.DB, cursorField string, ascending bool, anchor any, o options) (bool, error) {
+	// Before the anchor is the cursor condition in the opposite direction
+	before := cursorCondition(cursorField, !ascending, false)
+
+	var counted int64
+	err := o.countLimiter.do(queryContext(base), func() (err error) {
+		counted, err = cappedCount(base.Where(before, anchor), 1)
+		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to check for new items: %w", err)
+	}
+	return counted > 0, nil
+}
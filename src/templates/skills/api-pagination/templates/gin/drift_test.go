@@ -0,0 +1,115 @@
+package pagination
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+type feedPost struct {
+	ID int64
+}
+
+// feedDB serves a newest-first feed of posts without a database: fetches return rows before
+// the page's boundary and the drift probe (the only count) sees the posts newer than the anchor
+// (RowsAffected is what GORM's Count reports for a single-row result)
+func feedDB(t *testing.T, posts *[]feedPost, boundary *int64, anchor *int64) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.Callback().Query().Register("test:feed", func(tx *gorm.DB) {
+		switch dest := tx.Statement.Dest.(type) {
+		case *[]feedPost:
+			var page []feedPost
+			for _, post := range *posts {
+				if *boundary == 0 || post.ID < *boundary {
+					page = append(page, post)
+				}
+			}
+			*dest = page
+		case *int64:
+			*dest = 0
+			for _, post := range *posts {
+				if post.ID > *anchor {
+					*dest = 1
+				}
+			}
+			tx.RowsAffected = *dest
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestDriftDetection(t *testing.T) {
+	var posts []feedPost
+	for id := int64(5); id >= 1; id-- {
+		posts = append(posts, feedPost{ID: id})
+	}
+	var boundary, anchor int64
+	db := feedDB(t, &posts, &boundary, &anchor)
+
+	var page []feedPost
+	first, err := CursorPaginateInt(db.Model(&feedPost{}), &page, "", 2, "id", false, WithDriftDetection())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.NewItemsAvailable || first.EndCursor == nil {
+		t.Fatalf("first page: new items %t, end cursor %v", first.NewItemsAvailable, first.EndCursor)
+	}
+
+	// The cursors carry the first page's top row as the session anchor
+	_, anchorValue, err := splitDriftAnchor(*first.EndCursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, _ := cursorInt(anchorValue); id != 5 {
+		t.Fatalf("anchor = %v, want 5", anchorValue)
+	}
+	anchor = 5
+
+	boundary = 4
+	second, err := CursorPaginateInt(db.Model(&feedPost{}), &page, *first.EndCursor, 2, "id", false, WithDriftDetection())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.NewItemsAvailable {
+		t.Error("second page reports new items before any were added")
+	}
+
+	// A post published while the client scrolls sorts before the anchor
+	published := feedPost{ID: 6}
+	posts = append([]feedPost{published}, posts...)
+
+	boundary = 2
+	third, err := CursorPaginateInt(db.Model(&feedPost{}), &page, *second.EndCursor, 2, "id", false, WithDriftDetection())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !third.NewItemsAvailable {
+		t.Error("new post before the anchor not reported")
+	}
+	if !third.ToResponse("/feed").Pagination.NewItemsAvailable {
+		t.Error("new_items_available missing from the response")
+	}
+}
+
+func TestDriftDetectionRejectsUnanchoredCursors(t *testing.T) {
+	var page []feedPost
+	db, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = CursorPaginateInt(db.Model(&feedPost{}), &page, EncodeCursor("id", 5, false), 2, "id", false, WithDriftDetection())
+	if !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("err = %v, want ErrInvalidCursor", err)
+	}
+}
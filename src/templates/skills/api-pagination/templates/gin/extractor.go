@@ -0,0 +1,153 @@
+package pagination
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// fieldExtractor reads one column's value from page items
+// Columns are resolved through the GORM schema, so column tags and the naming strategy apply
+type fieldExtractor struct {
+	field *schema.Field
+}
+
+// extractorKey identifies a resolved field by model type and column
+// Keying on reflect.Type (not the type name) keeps same-named types from different packages apart
+type extractorKey struct {
+	modelType reflect.Type
+	column    string
+}
+
+// extractorCache caches resolved field extractors so repeated pagination of the same
+// model and column skips schema parsing and field lookup
+var extractorCache sync.Map
+
+// newFieldExtractor resolves column (optionally table-qualified, e.g. "users.id") on T
+// Results are cached per (T, column); the first resolution for a type wins, so models
+// must not be paginated under naming strategies that map the column to different fields
+func newFieldExtractor[T any](db *gorm.DB, column string) (*fieldExtractor, error) {
+	key := extractorKey{modelType: reflect.TypeOf((*T)(nil)).Elem(), column: column}
+	if cached, ok := extractorCache.Load(key); ok {
+		return cached.(*fieldExtractor), nil
+	}
+
+	extractor, err := resolveFieldExtractor[T](db, column)
+	if err != nil {
+		return nil, err
+	}
+
+	cached, _ := extractorCache.LoadOrStore(key, extractor)
+	return cached.(*fieldExtractor), nil
+}
+
+// resolveFieldExtractor parses T's schema and looks up column, bypassing the cache
+// Columns no field is named or tagged after fall back to the field whose json tag names them,
+// so a cursor field can be given as the API calls it.
+func resolveFieldExtractor[T any](db *gorm.DB, column string) (*fieldExtractor, error) {
+	modelSchema, err := schema.Parse(new(T), &schemaCache, db.NamingStrategy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse model schema: %w", err)
+	}
+
+	name := fieldName(column)
+	field := modelSchema.LookUpField(name)
+	if field == nil {
+		field = lookUpJSONField(modelSchema, name)
+	}
+	if field == nil {
+		return nil, fmt.Errorf("cursor field %q not found on %s", column, modelSchema.Name)
+	}
+
+	return &fieldExtractor{field: field}, nil
+}
+
+// lookUpJSONField returns the field of s whose json tag name is name, or nil
+func lookUpJSONField(s *schema.Schema, name string) *schema.Field {
+	for _, field := range s.Fields {
+		if jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ","); jsonName != "-" && jsonName == name {
+			return field
+		}
+	}
+	return nil
+}
+
+// fieldName strips the table qualifier and identifier quotes from a column
+func fieldName(column string) string {
+	name := column
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.Trim(name, "`\"")
+}
+
+// value returns the field's value for item, dereferencing pointer fields (nil stays nil)
+func (e *fieldExtractor) value(ctx context.Context, item any) any {
+	raw, _ := e.field.ValueOf(ctx, reflect.Indirect(reflect.ValueOf(item)))
+
+	rv := reflect.ValueOf(raw)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		return rv.Elem().Interface()
+	}
+	return raw
+}
+
+// CursorKeyed is implemented by page types that read their own cursor keys
+// The paginators check it before anything else: when T or *T implements it, the first and
+// last items' cursor keys come from CursorKey, and the GORM schema is not consulted for the
+// cursor field; otherwise the field is read by reflection. field is the cursor column as the
+// items carry it (the WithJoinedCursorFields alias for joined columns). The value must encode
+// like the field's own (an integer for CursorPaginateInt, a time for CursorPaginateTime).
+//
+// Example usage:
+//
+//	func (u User) CursorKey(field string) (any, error) {
+//	    switch field {
+//	    case "id":
+//	        return u.ID, nil
+//	    case "created_at":
+//	        return u.CreatedAt, nil
+//	    }
+//	    return nil, fmt.Errorf("no cursor key %q", field)
+//	}
+type CursorKeyed interface {
+	CursorKey(field string) (any, error)
+}
+
+// boundaryKeys extracts the cursor field of the first and last items of a page
+func boundaryKeys[T any](db *gorm.DB, items []T, column string) (first any, last any, err error) {
+	if len(items) == 0 {
+		return nil, nil, nil
+	}
+
+	if _, ok := any(&items[0]).(CursorKeyed); ok {
+		return cursorKeys(items, fieldName(column))
+	}
+
+	extractor, err := newFieldExtractor[T](db, column)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx := queryContext(db)
+	return extractor.value(ctx, items[0]), extractor.value(ctx, items[len(items)-1]), nil
+}
+
+// cursorKeys reads the first and last items' cursor keys through CursorKeyed
+func cursorKeys[T any](items []T, field string) (first any, last any, err error) {
+	if first, err = any(&items[0]).(CursorKeyed).CursorKey(field); err != nil {
+		return nil, nil, fmt.Errorf("failed to read cursor key %q: %w", field, err)
+	}
+	if last, err = any(&items[len(items)-1]).(CursorKeyed).CursorKey(field); err != nil {
+		return nil, nil, fmt.Errorf("failed to read cursor key %q: %w", field, err)
+	}
+	return first, last, nil
+}
@@ -0,0 +1,226 @@
+package pagination
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+type benchUser struct {
+	ID    uint   `gorm:"primaryKey"`
+	Email string `gorm:"column:email_address"`
+}
+
+func testDB() *gorm.DB {
+	return &gorm.DB{Config: &gorm.Config{NamingStrategy: schema.NamingStrategy{}}}
+}
+
+// BenchmarkFieldExtractorUncached measures schema parsing and field lookup on every call
+func BenchmarkFieldExtractorUncached(b *testing.B) {
+	db := testDB()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := resolveFieldExtractor[benchUser](db, "users.email_address"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkFieldExtractorCached measures the (type, column) cache hit path used by the paginators
+func BenchmarkFieldExtractorCached(b *testing.B) {
+	db := testDB()
+	if _, err := newFieldExtractor[benchUser](db, "users.email_address"); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := newFieldExtractor[benchUser](db, "users.email_address"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// record shares its name with the type declared inside TestFieldExtractorCacheConcurrent
+type record struct {
+	ID string
+}
+
+func extractPackageRecordID(db *gorm.DB) (any, error) {
+	extractor, err := newFieldExtractor[record](db, "id")
+	if err != nil {
+		return nil, err
+	}
+	return extractor.value(context.Background(), &record{ID: "abc"}), nil
+}
+
+func TestFieldExtractorCacheConcurrent(t *testing.T) {
+	// Two distinct types named record with the same column: a cache keyed on the type
+	// name would hand one type's field to the other
+	type record struct {
+		ID int64
+	}
+
+	db := testDB()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 64)
+	for g := 0; g < 32; g++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				extractor, err := newFieldExtractor[record](db, "id")
+				if err != nil {
+					errs <- err
+					return
+				}
+				if got := extractor.value(context.Background(), record{ID: 7}); got != int64(7) {
+					t.Errorf("local record: got %v (%T), want int64 7", got, got)
+					return
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				got, err := extractPackageRecordID(db)
+				if err != nil {
+					errs <- err
+					return
+				}
+				if got != "abc" {
+					t.Errorf("package record: got %v (%T), want \"abc\"", got, got)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Fatal(err)
+	}
+}
+
+func TestFieldExtractorCacheKeyedPerColumn(t *testing.T) {
+	db := testDB()
+
+	idExtractor, err := newFieldExtractor[benchUser](db, "id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	emailExtractor, err := newFieldExtractor[benchUser](db, "email_address")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user := benchUser{ID: 3, Email: "a@example.com"}
+	if got := idExtractor.value(context.Background(), user); got != uint(3) {
+		t.Errorf("id: got %v", got)
+	}
+	if got := emailExtractor.value(context.Background(), &user); got != "a@example.com" {
+		t.Errorf("email_address: got %v", got)
+	}
+}
+
+// apiOrder names its fields differently in Go, in the database, and in JSON
+type apiOrder struct {
+	ID       uint    `json:"id"`
+	PlacedAt int64   `gorm:"column:placed_unix" json:"placedAt"`
+	Total    float64 `json:"-"`
+}
+
+func TestFieldExtractorFallsBackToJSONTag(t *testing.T) {
+	db := testDB()
+
+	extractor, err := newFieldExtractor[apiOrder](db, "orders.placedAt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := extractor.value(context.Background(), apiOrder{ID: 1, PlacedAt: 1700000000}); got != int64(1700000000) {
+		t.Errorf("placedAt: got %v, want the PlacedAt field", got)
+	}
+
+	if _, err := newFieldExtractor[apiOrder](db, "placed_unix"); err != nil {
+		t.Errorf("column name no longer resolves: %v", err)
+	}
+	if _, err := newFieldExtractor[apiOrder](db, "-"); err == nil {
+		t.Error(`a field tagged json:"-" was matched`)
+	}
+}
+
+// rankedPlayer computes its "rank" cursor key, which no field or column maps, so reading it
+// by reflection would fail
+type rankedPlayer struct {
+	ID     int64
+	Points int64
+	calls  *int
+}
+
+func (p *rankedPlayer) CursorKey(field string) (any, error) {
+	*p.calls++
+	if field != "rank" {
+		return nil, fmt.Errorf("no cursor key %q", field)
+	}
+	return 1000 - p.Points, nil
+}
+
+func TestBoundaryKeysPreferCursorKeyed(t *testing.T) {
+	calls := 0
+	items := []rankedPlayer{
+		{ID: 1, Points: 990, calls: &calls},
+		{ID: 2, Points: 950, calls: &calls},
+	}
+
+	first, last, err := boundaryKeys(testDB(), items, "players.rank")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != int64(10) || last != int64(50) || calls != 2 {
+		t.Errorf("keys = %v, %v after %d calls, want 10 and 50 from CursorKey", first, last, calls)
+	}
+	if _, ok := extractorCache.Load(extractorKey{modelType: reflect.TypeOf(rankedPlayer{}), column: "players.rank"}); ok {
+		t.Error("the schema was consulted for a CursorKeyed type")
+	}
+
+	if _, _, err := boundaryKeys(testDB(), items, "points"); err == nil {
+		t.Error("CursorKey's error was dropped")
+	}
+}
+
+func TestCursorPaginateIntUsesCursorKey(t *testing.T) {
+	calls := 0
+	db, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Callback().Query().Register("test:players", func(tx *gorm.DB) {
+		if dest, ok := tx.Statement.Dest.(*[]rankedPlayer); ok {
+			*dest = []rankedPlayer{
+				{ID: 1, Points: 990, calls: &calls},
+				{ID: 2, Points: 950, calls: &calls},
+				{ID: 3, Points: 900, calls: &calls},
+			}
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var page []rankedPlayer
+	result, err := CursorPaginateInt(db.Model(&rankedPlayer{}), &page, "", 2, "rank", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next, err := DecodeCursor(*result.NextCursor, "rank", true); err != nil || next != "50" {
+		t.Errorf("next cursor decodes to %q (%v), want the computed rank 50", next, err)
+	}
+}
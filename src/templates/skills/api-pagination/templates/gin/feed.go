@@ -0,0 +1,57 @@
+package pagination
+
+import "gorm.io/gorm"
+
+// DefaultFeedField is the cursor field FeedPaginate orders by
+const DefaultFeedField = "id"
+
+// FeedPaginate serves a newest-first feed: rows ordered by id descending, each page continuing
+// with older rows
+// It is CursorPaginateInt with the feed defaults filled in (DefaultFeedField, descending, and
+// the default page size for pageSize 0), and NextCursor continues after the page's oldest row,
+// so clients load older items by sending next_cursor back. Use FeedPaginateBy for feeds
+// keyed by another increasing integer column.
+//
+// Example usage:
+//
+//	func GetTimeline(c *gin.Context) {
+//	    var posts []Post
+//	    result, err := pagination.FeedPaginate(db.Model(&Post{}), &posts, c.Query("cursor"), 0)
+//	    if err != nil {
+//	        c.JSON(400, gin.H{"error": err.Error()})
+//	        return
+//	    }
+//	    c.JSON(200, result) // scroll down: ?cursor=<next_cursor>
+//	}
+func FeedPaginate[T any](
+	db *gorm.DB,
+	dest *[]T,
+	cursor string,
+	pageSize int,
+	opts ...Option,
+) (*CursorPagination[T], error) {
+	return FeedPaginateBy(db, dest, cursor, pageSize, DefaultFeedField, opts...)
+}
+
+// FeedPaginateBy is FeedPaginate ordered by field descending, e.g. a created_unix column
+// field must be an integer that grows with insertion order, or the feed is not newest-first.
+func FeedPaginateBy[T any](
+	db *gorm.DB,
+	dest *[]T,
+	cursor string,
+	pageSize int,
+	field string,
+	opts ...Option,
+) (*CursorPagination[T], error) {
+	result, err := CursorPaginateInt(db, dest, cursor, pageSize, field, false, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Continue from the oldest row's field value rather than the encoded item
+	if result.HasNext && result.EndCursor != nil {
+		next := *result.EndCursor
+		result.NextCursor = &next
+	}
+	return result, nil
+}
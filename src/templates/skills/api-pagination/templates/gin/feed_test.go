@@ -0,0 +1,57 @@
+package pagination
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFeedPaginateNewestFirst(t *testing.T) {
+	var posts []feedPost
+	for id := int64(7); id >= 1; id-- {
+		posts = append(posts, feedPost{ID: id})
+	}
+	var boundary, anchor int64
+	db := feedDB(t, &posts, &boundary, &anchor)
+
+	var seen [][]int64
+	cursor := ""
+	for pages := 0; pages < len(posts); pages++ {
+		if cursor != "" {
+			value, err := DecodeCursor(cursor, "id", false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if boundary, err = cursorInt(value); err != nil {
+				t.Fatalf("next cursor %q does not continue from an id: %v", cursor, err)
+			}
+		}
+
+		var page []feedPost
+		result, err := FeedPaginate(db.Model(&feedPost{}), &page, cursor, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []int64
+		for _, post := range result.Items {
+			ids = append(ids, post.ID)
+		}
+		seen = append(seen, ids)
+
+		if !result.HasNext {
+			if result.NextCursor != nil {
+				t.Errorf("last page has next cursor %q", *result.NextCursor)
+			}
+			break
+		}
+		cursor = *result.NextCursor
+	}
+
+	want := [][]int64{
+		{7, 6, 5},
+		{4, 3, 2},
+		{1},
+	}
+	if !reflect.DeepEqual(seen, want) {
+		t.Errorf("feed pages = %v, want %v", seen, want)
+	}
+}
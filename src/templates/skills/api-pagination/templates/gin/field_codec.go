@@ -0,0 +1,119 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+)
+
+// ErrCursorFieldMismatch is returned when a cursor names a field other than the paginator's
+// cursor field and no alias maps it there
+// Ordering by one column with a cursor taken from another would skip or repeat rows.
+var ErrCursorFieldMismatch = errors.New("cursor was issued for a different cursor field")
+
+// CursorFieldAliases maps deprecated cursor field names to their replacements
+// ("created" -> "created_at"), so cursors issued before a column rename keep working
+// during the migration window; aliases may chain ("ts" -> "created" -> "created_at").
+type CursorFieldAliases map[string]string
+
+// OnDeprecatedCursorField is called whenever a cursor is decoded through an alias
+// The default logs the first translation per process; replace it to route every one to a
+// structured logger or a metric, and remove the alias once it stops firing.
+var OnDeprecatedCursorField = func(deprecated, current string) {
+	deprecatedFieldLogged.Do(func() {
+		log.Printf("pagination: cursor field %q is deprecated; translated to %q", deprecated, current)
+	})
+}
+
+// deprecatedFieldLogged keeps the default OnDeprecatedCursorField to one log line per process
+var deprecatedFieldLogged sync.Once
+
+// fieldCursor is the self-describing cursor payload: the field name and the base token
+type fieldCursor struct {
+	Field string `json:"f"`
+	Token string `json:"t"`
+}
+
+// fieldCodec tags cursors with the field they were issued for
+type fieldCodec struct {
+	base    CursorCodec
+	field   string
+	aliases CursorFieldAliases
+}
+
+// FieldCursorCodec makes cursors self-describing: each one records the cursor field it was
+// issued for, and decoding it for another field fails with ErrCursorFieldMismatch
+// base is the codec being wrapped (nil = DefaultCursorCodec). Cursors naming a deprecated
+// field are translated through aliases, with a warning via OnDeprecatedCursorField.
+//
+// Example usage:
+//
+//	// created was renamed to created_at; honor cursors issued before the deploy
+//	codec := pagination.FieldCursorCodec("created_at", nil, pagination.CursorFieldAliases{
+//	    "created": "created_at",
+//	})
+//	result, err := pagination.CursorPaginateString(db, &events, cursor, 20, "created_at", false,
+//	    pagination.WithCursorCodec(codec),
+//	)
+//	if errors.Is(err, pagination.ErrCursorFieldMismatch) {
+//	    c.JSON(400, gin.H{"error": "cursor no longer valid; restart pagination"})
+//	    return
+//	}
+func FieldCursorCodec(field string, base CursorCodec, aliases CursorFieldAliases) CursorCodec {
+	if base == nil {
+		base = DefaultCursorCodec
+	}
+	return fieldCodec{base: base, field: field, aliases: aliases}
+}
+
+func (c fieldCodec) Encode(value any) (string, error) {
+	token, err := c.base.Encode(value)
+	if err != nil {
+		return "", err
+	}
+
+	raw, err := json.Marshal(fieldCursor{Field: c.field, Token: token})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func (c fieldCodec) Decode(cursor string) (any, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	var payload fieldCursor
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	if payload.Field != c.field {
+		if !c.resolves(payload.Field) {
+			return nil, fmt.Errorf("%w: cursor is for %q, paginating by %q", ErrCursorFieldMismatch, payload.Field, c.field)
+		}
+		OnDeprecatedCursorField(payload.Field, c.field)
+	}
+	return c.base.Decode(payload.Token)
+}
+
+// resolves reports whether following the aliases from name reaches the codec's field
+// The hop limit stops alias cycles.
+func (c fieldCodec) resolves(name string) bool {
+	for hops := 0; hops < len(c.aliases); hops++ {
+		next, ok := c.aliases[name]
+		if !ok {
+			return false
+		}
+		if next == c.field {
+			return true
+		}
+		name = next
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package pagination
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFieldCursorCodecDecodesAliasedCursor(t *testing.T) {
+	var warnings [][2]string
+	saved := OnDeprecatedCursorField
+	OnDeprecatedCursorField = func(deprecated, current string) {
+		warnings = append(warnings, [2]string{deprecated, current})
+	}
+	defer func() { OnDeprecatedCursorField = saved }()
+
+	// Cursor issued before the created -> created_at rename
+	old, err := FieldCursorCodec("created", nil, nil).Encode("2024-05-01T10:00:00Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	current := FieldCursorCodec("created_at", nil, CursorFieldAliases{"created": "created_at"})
+	value, err := current.Decode(old)
+	if err != nil {
+		t.Fatalf("aliased cursor should decode: %v", err)
+	}
+	if value != "2024-05-01T10:00:00Z" {
+		t.Errorf("value = %v", value)
+	}
+	if len(warnings) != 1 || warnings[0] != [2]string{"created", "created_at"} {
+		t.Errorf("warnings = %v", warnings)
+	}
+
+	// Cursors for the current field decode silently
+	fresh, _ := current.Encode("2024-05-02T10:00:00Z")
+	if _, err := current.Decode(fresh); err != nil || len(warnings) != 1 {
+		t.Errorf("current cursor: err = %v, warnings = %v", err, warnings)
+	}
+}
+
+func TestFieldCursorCodecFollowsAliasChains(t *testing.T) {
+	saved := OnDeprecatedCursorField
+	OnDeprecatedCursorField = func(string, string) {}
+	defer func() { OnDeprecatedCursorField = saved }()
+
+	old, _ := FieldCursorCodec("ts", JSONCursorCodec{}, nil).Encode(42)
+	current := FieldCursorCodec("created_at", JSONCursorCodec{}, CursorFieldAliases{
+		"ts":      "created",
+		"created": "created_at",
+	})
+	value, err := current.Decode(old)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := cursorInt(value); err != nil || n != 42 {
+		t.Errorf("value = %v (%v)", value, err)
+	}
+}
+
+func TestFieldCursorCodecMismatch(t *testing.T) {
+	idCursor, _ := FieldCursorCodec("id", nil, nil).Encode(10)
+
+	codecs := []CursorCodec{
+		FieldCursorCodec("created_at", nil, nil),
+		FieldCursorCodec("created_at", nil, CursorFieldAliases{"created": "created_at"}),
+		FieldCursorCodec("created_at", nil, CursorFieldAliases{"id": "uuid", "uuid": "id"}),
+	}
+	for i, codec := range codecs {
+		if _, err := codec.Decode(idCursor); !errors.Is(err, ErrCursorFieldMismatch) {
+			t.Errorf("codec %d: err = %v, want ErrCursorFieldMismatch", i, err)
+		}
+	}
+}
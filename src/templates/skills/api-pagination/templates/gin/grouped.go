@@ -0,0 +1,61 @@
+package pagination
+
+import "gorm.io/gorm"
+
+// GroupedOffsetPagination is an offset page whose items are also grouped by a key
+// GroupKeys lists the keys in the order their first item appears on the page, since Groups (a
+// map) has no order of its own. Items keeps the flat page.
+type GroupedOffsetPagination[T any, K comparable] struct {
+	*OffsetPagination[T]
+	Groups    map[K][]T `json:"groups"`
+	GroupKeys []K       `json:"group_keys"`
+}
+
+// OffsetPaginateGrouped performs offset pagination and groups the page's items by keyFn, for
+// calendar and kanban-style UIs that render one column or day per key
+// Grouping is per page: a group can continue on the next page, where it starts again under
+// the same key, so order the query by the grouping column first to keep groups contiguous.
+// K must be a string, integer, or encoding.TextMarshaler type to marshal as a JSON object key.
+//
+// Example usage:
+//
+//	result, err := pagination.OffsetPaginateGrouped(db.Order("starts_at ASC"), &events, page, 50,
+//	    func(e Event) string { return e.StartsAt.Format("2006-01-02") },
+//	)
+//	// {"groups": {"2024-01-01": [...], "2024-01-02": [...]}, "group_keys": ["2024-01-01", "2024-01-02"], ...}
+func OffsetPaginateGrouped[T any, K comparable](
+	db *gorm.DB,
+	dest *[]T,
+	page int,
+	pageSize int,
+	keyFn func(T) K,
+	opts ...Option,
+) (*GroupedOffsetPagination[T, K], error) {
+	result, err := OffsetPaginate(db, dest, page, pageSize, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, keys := groupItems(result.Items, keyFn)
+	return &GroupedOffsetPagination[T, K]{
+		OffsetPagination: result,
+		Groups:           groups,
+		GroupKeys:        keys,
+	}, nil
+}
+
+// groupItems groups items by keyFn, returning the keys in first-appearance order
+func groupItems[T any, K comparable](items []T, keyFn func(T) K) (map[K][]T, []K) {
+	groups := make(map[K][]T)
+	keys := []K{}
+
+	for _, item := range items {
+		key := keyFn(item)
+		if _, seen := groups[key]; !seen {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], item)
+	}
+
+	return groups, keys
+}
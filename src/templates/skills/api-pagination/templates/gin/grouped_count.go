@@ -0,0 +1,23 @@
+package pagination
+
+import "gorm.io/gorm"
+
+// isGrouped reports whether query has a GROUP BY, and so possibly a HAVING
+// GORM keeps HAVING conditions in the GROUP BY clause.
+func isGrouped(query *gorm.DB) bool {
+	if query.Statement == nil {
+		return false
+	}
+	_, ok := query.Statement.Clauses["GROUP BY"]
+	return ok
+}
+
+// countGroups counts the result rows of a grouped query: SELECT COUNT(*) FROM (query)
+// Counting the query itself would replace its select list, breaking HAVING conditions on its
+// aliases (HAVING total > ?), and count the rows of each group instead of the groups
+// passing HAVING.
+func countGroups(query *gorm.DB, total *int64) error {
+	return query.Session(&gorm.Session{NewDB: true}).
+		Table("(?) AS grouped_rows", query.Session(&gorm.Session{})).
+		Count(total).Error
+}
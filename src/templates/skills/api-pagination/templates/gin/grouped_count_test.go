@@ -0,0 +1,140 @@
+package pagination
+
+import (
+	"reflect"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// customerTotal is a row of SELECT customer_id, SUM(amount) AS total ... GROUP BY customer_id
+type customerTotal struct {
+	CustomerID int64
+	Total      int
+}
+
+// havingMin returns the bound of a statement's HAVING total > ? condition
+func havingMin(stmt *gorm.Statement) (int, bool) {
+	c, ok := stmt.Clauses["GROUP BY"]
+	if !ok {
+		return 0, false
+	}
+	for _, e := range c.Expression.(clause.GroupBy).Having {
+		if expr, ok := e.(clause.Expr); ok && len(expr.Vars) == 1 {
+			return expr.Vars[0].(int), true
+		}
+	}
+	return 0, false
+}
+
+// groupedDB serves the groups of totals that pass the query's HAVING, and counts them only
+// through a subquery of the grouped query; selects records each counted subquery's select list
+// It quotes like SQLite, since Table and the count subquery quote their identifiers.
+func groupedDB(t *testing.T, totals []customerTotal, selects *[][]string) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Dialector = quotingDialector{namedDialector{name: "sqlite"}}
+
+	passing := func(stmt *gorm.Statement) []customerTotal {
+		bound, ok := havingMin(stmt)
+		if !ok {
+			t.Errorf("grouped query lost its HAVING")
+		}
+		var out []customerTotal
+		for _, row := range totals {
+			if row.Total > bound {
+				out = append(out, row)
+			}
+		}
+		return out
+	}
+
+	err = db.Callback().Query().Register("test:grouped", func(tx *gorm.DB) {
+		switch dest := tx.Statement.Dest.(type) {
+		case *[]customerTotal:
+			limit := tx.Statement.Clauses["LIMIT"].Expression.(clause.Limit)
+			rows := passing(tx.Statement)
+			for i := limit.Offset; i < len(rows) && len(*dest) < *limit.Limit; i++ {
+				*dest = append(*dest, rows[i])
+			}
+		case *int64:
+			if tx.Statement.TableExpr == nil || len(tx.Statement.TableExpr.Vars) != 1 {
+				t.Errorf("count of a grouped query without a subquery")
+				return
+			}
+			sub := tx.Statement.TableExpr.Vars[0].(*gorm.DB).Statement
+			*selects = append(*selects, sub.Selects)
+			counted := len(passing(sub))
+			if c, ok := sub.Clauses["LIMIT"]; ok && *c.Expression.(clause.Limit).Limit < counted {
+				counted = *c.Expression.(clause.Limit).Limit
+			}
+			*dest = int64(counted)
+			tx.RowsAffected = 1
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// salesTotals are the per-customer totals; three pass HAVING SUM(amount) > 100
+var salesTotals = []customerTotal{
+	{CustomerID: 1, Total: 250},
+	{CustomerID: 2, Total: 40},
+	{CustomerID: 3, Total: 120},
+	{CustomerID: 4, Total: 90},
+	{CustomerID: 5, Total: 300},
+}
+
+// bigCustomers is an analytics query filtering its groups with HAVING
+func bigCustomers(db *gorm.DB) *gorm.DB {
+	return db.Table("sales").
+		Select("customer_id, SUM(amount) AS total").
+		Group("customer_id").
+		Having("SUM(amount) > ?", 100)
+}
+
+func TestOffsetPaginateCountsHavingGroups(t *testing.T) {
+	var selects [][]string
+	db := groupedDB(t, salesTotals, &selects)
+
+	var rows []customerTotal
+	result, err := OffsetPaginate(bigCustomers(db), &rows, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.TotalItems != 3 || result.TotalPages != 2 || !result.HasNext {
+		t.Errorf("total %d in %d pages (has next %t), want the 3 groups passing HAVING in 2 pages",
+			result.TotalItems, result.TotalPages, result.HasNext)
+	}
+	if got := []int64{rows[0].CustomerID, rows[1].CustomerID}; !reflect.DeepEqual(got, []int64{1, 3}) {
+		t.Errorf("page = %v, want customers 1 and 3", rows)
+	}
+	if len(selects) != 1 || len(selects[0]) == 0 {
+		t.Errorf("counted subquery selects %v, want the grouped select list", selects)
+	}
+}
+
+func TestCappedCountKeepsGroupedSelect(t *testing.T) {
+	var selects [][]string
+	db := groupedDB(t, salesTotals, &selects)
+
+	var rows []customerTotal
+	result, err := OffsetPaginate(bigCustomers(db), &rows, 1, 2, WithMaxReportedTotal(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.TotalItems != 2 || result.TotalAtLeast == nil {
+		t.Errorf("total %d (at least %v), want the cap of 2 with more groups", result.TotalItems, result.TotalAtLeast)
+	}
+	for _, s := range selects {
+		if reflect.DeepEqual(s, []string{"1"}) {
+			t.Errorf("capped count replaced the grouped select list, which HAVING may refer to")
+		}
+	}
+}
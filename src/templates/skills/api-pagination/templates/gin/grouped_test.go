@@ -0,0 +1,66 @@
+package pagination
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type appointment struct {
+	ID       int
+	StartsAt time.Time
+}
+
+func TestGroupItemsByDate(t *testing.T) {
+	at := func(id int, day, hour int) appointment {
+		return appointment{ID: id, StartsAt: time.Date(2024, 1, day, hour, 0, 0, 0, time.UTC)}
+	}
+	items := []appointment{at(1, 2, 9), at(2, 2, 14), at(3, 1, 10), at(4, 2, 16)}
+
+	groups, keys := groupItems(items, func(a appointment) string { return a.StartsAt.Format("2006-01-02") })
+
+	// Keys keep the order of their first item, even when a later item returns to a key
+	if want := []string{"2024-01-02", "2024-01-01"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("keys = %v, want %v", keys, want)
+	}
+	var ids []int
+	for _, a := range groups["2024-01-02"] {
+		ids = append(ids, a.ID)
+	}
+	if want := []int{1, 2, 4}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("2024-01-02 = %v, want %v", ids, want)
+	}
+
+	page := &GroupedOffsetPagination[appointment, string]{
+		OffsetPagination: &OffsetPagination[appointment]{Items: items, CurrentPage: 1, PageSize: 4},
+		Groups:           groups,
+		GroupKeys:        keys,
+	}
+	data, err := json.Marshal(page)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"groups":{"2024-01-01":[`, `"group_keys":["2024-01-02","2024-01-01"]`, `"current_page":1`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("response missing %s: %s", want, data)
+		}
+	}
+}
+
+func TestGroupItemsEmptyPage(t *testing.T) {
+	groups, keys := groupItems(nil, func(a appointment) int { return a.ID })
+
+	data, err := json.Marshal(GroupedOffsetPagination[appointment, int]{
+		OffsetPagination: &OffsetPagination[appointment]{Items: []appointment{}},
+		Groups:           groups,
+		GroupKeys:        keys,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"groups":{}`) || !strings.Contains(string(data), `"group_keys":[]`) {
+		t.Errorf("empty page should marshal empty groups: %s", data)
+	}
+}
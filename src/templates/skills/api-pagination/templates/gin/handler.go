@@ -0,0 +1,163 @@
+package pagination
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// HandleOptions configures HandlePaginated
+// The zero value serves offset pages of the whole query with DefaultStatusPolicy.
+type HandleOptions struct {
+	// Scope applies the request's filters to the query (nil = no filters)
+	Scope func(c *gin.Context, db *gorm.DB) *gorm.DB
+
+	// CursorField switches to cursor pagination on this integer column ("" = offset
+	// pagination); Descending walks it newest first
+	CursorField string
+	Descending  bool
+
+	// Status chooses the status of empty and out-of-range pages
+	Status StatusPolicy
+
+	// Links is the JSON shape of the response's links ("" = LinksObject)
+	Links LinksFormat
+
+	// Checksum adds a PageChecksum over the rows' primary keys and VersionField values
+	// (see WithPageChecksum)
+	Checksum     bool
+	VersionField string
+
+	// Redaction masks the item fields it hides from the caller (nil = none, see WithRedaction)
+	Redaction RedactionPolicy
+
+	// BudgetMinRows is the floor of rows on cursor pages cut at a client's max_bytes
+	// (0 = DefaultBudgetMinRows, see WithByteBudget)
+	BudgetMinRows int
+
+	// Audit records each page read under the policy (nil = no audit)
+	Audit *AuditPolicy
+
+	// Options are passed to the paginator after the request's page numbering and context
+	Options []Option
+
+	// Render is passed to ToResponse after the options above, e.g. WithItemLinks
+	Render []ResponseOption
+
+	// BaseURL derives the links' external URL behind proxies (nil = the request's path)
+	BaseURL *BaseURLResolver
+}
+
+// HandlePaginated serves a paginated list of T in one call: it reads the request's params
+// (ParsePaginationParams' if the middleware ran), paginates db, and writes the response with
+// links to the request's path and the status opts.Status chooses
+// A failed page is answered with AbortWithError (400 for a bad cursor or page token, 503 when
+// too busy, 500 otherwise) and the error is returned for logging; the lower-level paginators
+// remain for handlers that need more control.
+//
+// Example usage:
+//
+//	r.GET("/orders", pagination.ParsePaginationParams, func(c *gin.Context) {
+//	    _ = pagination.HandlePaginated[Order](c, db.Model(&Order{}).Order("id"), pagination.HandleOptions{
+//	        Scope: func(c *gin.Context, db *gorm.DB) *gorm.DB {
+//	            if status := c.Query("status"); status != "" {
+//	                return db.Where("status = ?", status)
+//	            }
+//	            return db
+//	        },
+//	    })
+//	})
+func HandlePaginated[T any](c *gin.Context, db *gorm.DB, opts HandleOptions) error {
+	reply, err := servePage[T](c, db, requestParams(c), opts, opts.BaseURL.BaseURL(c.Request))
+	if err != nil {
+		AbortWithError(c, ErrorStatus(err), err)
+		return err
+	}
+	writePage(c, reply.status, reply.response, reply.outcomeErr)
+	return nil
+}
+
+// pageReply is a served page: its status under the policy, its response, and the error a
+// 4xx status responds with
+type pageReply struct {
+	status     int
+	response   any
+	outcomeErr error
+}
+
+// servePage paginates db for params the way HandlePaginated does, linking to baseURL
+func servePage[T any](c *gin.Context, db *gorm.DB, params PaginationParams, opts HandleOptions, baseURL string) (pageReply, error) {
+	if opts.Scope != nil {
+		db = opts.Scope(c, db)
+	}
+
+	options := append([]Option{
+		WithPageIndexing(params.Indexing),
+		WithContext(c.Request.Context()),
+		WithByteBudget(params.MaxBytes, opts.BudgetMinRows),
+	}, opts.Options...)
+	render := []ResponseOption{WithLinksFormat(opts.Links)}
+	if opts.Checksum {
+		render = append(render, WithPageChecksum(opts.VersionField))
+	}
+	if opts.Redaction != nil {
+		render = append(render, WithRedaction(c, opts.Redaction))
+	}
+	render = append(render, opts.Render...)
+
+	var items []T
+	if opts.CursorField != "" {
+		result, err := CursorPaginateInt(db, &items, params.Cursor, params.PageSize, opts.CursorField, !opts.Descending, options...)
+		if err != nil {
+			return pageReply{}, err
+		}
+		AuditCursorPage(c, opts.Audit, db, params.Cursor, result)
+		outcome := CursorOutcome(result)
+		return pageReply{opts.Status.Status(outcome), result.ToResponse(baseURL, render...), cursorOutcomeError(outcome)}, nil
+	}
+
+	result, err := OffsetPaginate(db, &items, params.Page, params.RequestedPageSize(), options...)
+	if err != nil {
+		return pageReply{}, err
+	}
+	AuditOffsetPage(c, opts.Audit, db, result)
+	outcome := OffsetOutcome(result)
+	return pageReply{opts.Status.Status(outcome), result.ToResponse(baseURL, render...), offsetOutcomeError(result, outcome)}, nil
+}
+
+// requestParams returns the params ParsePaginationParams stored, or parses the request's
+func requestParams(c *gin.Context) PaginationParams {
+	if _, exists := c.Get("pagination_params"); exists {
+		return GetPaginationParams(c)
+	}
+	return ParamsFromRequest(c.Request)
+}
+
+// ErrorStatus is the HTTP status of a failed page, matching the api-error-handling skill's problems
+func ErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrInvalidCursor),
+		errors.Is(err, ErrCursorFieldMismatch),
+		errors.Is(err, ErrCursorMismatch),
+		errors.Is(err, ErrCursorTampered),
+		errors.Is(err, ErrCursorFieldNotAllowed),
+		errors.Is(err, ErrInvalidPageToken),
+		errors.Is(err, ErrPageTokenMismatch):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrPageOutOfRange):
+		return http.StatusNotFound
+	case errors.Is(err, ErrSnapshotExpired),
+		errors.Is(err, ErrCursorExpired):
+		return http.StatusGone
+	case errors.Is(err, ErrResultTooLarge):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, ErrMaxPagesReached):
+		return http.StatusTooManyRequests
+	case errors.Is(err, ErrTooBusy):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,108 @@
+package pagination
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// handlerDB returns a database whose pages are *rows and whose count is *total
+func handlerDB(t *testing.T, rows *[]event, total *int64) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Callback().Query().Register("test:handler", func(tx *gorm.DB) {
+		switch dest := tx.Statement.Dest.(type) {
+		case *[]event:
+			*dest = append((*dest)[:0], *rows...)
+		case *int64:
+			*dest = *total
+			tx.RowsAffected = 1
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db.Model(&event{})
+}
+
+// serve runs handle for a GET of target and returns the recorded response
+func serve(target string, handle gin.HandlerFunc) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/events", ParsePaginationParams, handle)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", target, nil))
+	return w
+}
+
+func TestHandlePaginatedOneCall(t *testing.T) {
+	rows := []event{
+		{ID: 3},
+		{ID: 4},
+	}
+	total := int64(6)
+	db := handlerDB(t, &rows, &total)
+
+	var scoped string
+	var handleErr error
+	opts := HandleOptions{
+		Scope: func(c *gin.Context, db *gorm.DB) *gorm.DB {
+			scoped = c.Query("kind")
+			return db.Where("kind = ?", scoped)
+		},
+		Status: StatusPolicy{OutOfRange: 404},
+	}
+	handle := func(c *gin.Context) {
+		handleErr = HandlePaginated[event](c, db, opts)
+	}
+
+	w := serve("/events?page=2&page_size=2&kind=paid", handle)
+	if w.Code != 200 || handleErr != nil {
+		t.Fatalf("status %d, err %v: %s", w.Code, handleErr, w.Body)
+	}
+	if scoped != "paid" {
+		t.Errorf("scope saw kind %q, want paid", scoped)
+	}
+
+	var response PaginatedResponse[event]
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Data) != 2 || *response.Pagination.CurrentPage != 2 || *response.Pagination.TotalPages != 3 {
+		t.Fatalf("response %+v", response.Pagination)
+	}
+	if response.Links == nil || response.Links.Next == nil || !strings.HasPrefix(*response.Links.Next, "/events?page=3") {
+		t.Errorf("links %+v, want next to page 3 of /events", response.Links)
+	}
+
+	// An empty page of a non-empty result set is past the last page
+	rows = nil
+	if w := serve("/events?page=9&page_size=2", handle); w.Code != 404 {
+		t.Errorf("page past the end: status %d, want the policy's 404", w.Code)
+	}
+}
+
+func TestHandlePaginatedCursorError(t *testing.T) {
+	rows := []event{
+		{ID: 1},
+	}
+	total := int64(1)
+	db := handlerDB(t, &rows, &total)
+
+	var handleErr error
+	w := serve("/events?cursor=!!!", func(c *gin.Context) {
+		handleErr = HandlePaginated[event](c, db, HandleOptions{CursorField: "id"})
+	})
+	if w.Code != 400 || !errors.Is(handleErr, ErrInvalidCursor) {
+		t.Errorf("bad cursor: status %d, err %v, want 400 and ErrInvalidCursor", w.Code, handleErr)
+	}
+}
@@ -0,0 +1,40 @@
+package pagination
+
+// PageIndexing selects how clients number offset pages
+// Paginators and the middleware default to OneBased; ZeroBased serves API contracts where
+// page=0 is the first page. The mode is chosen per route, so endpoints can mix both.
+type PageIndexing int
+
+const (
+	// OneBased numbers the first page 1 (the default)
+	OneBased PageIndexing = iota
+
+	// ZeroBased numbers the first page 0
+	ZeroBased
+)
+
+// FirstPage returns the number of the first page
+func (i PageIndexing) FirstPage() int {
+	if i == ZeroBased {
+		return 0
+	}
+	return 1
+}
+
+// internalPage converts a client page number to the 1-based page the paginators compute with
+func (i PageIndexing) internalPage(page int) int {
+	return page - i.FirstPage() + 1
+}
+
+// clientPage converts a 1-based page back to the client's numbering
+func (i PageIndexing) clientPage(page int) int {
+	return page + i.FirstPage() - 1
+}
+
+// withPageIndexing renumbers p's current page for indexing and records the mode, so
+// ToResponse and HeadLinks build links in the client's numbering
+func withPageIndexing[T any](p *OffsetPagination[T], indexing PageIndexing) *OffsetPagination[T] {
+	p.CurrentPage = indexing.clientPage(p.CurrentPage)
+	p.Indexing = indexing
+	return p
+}
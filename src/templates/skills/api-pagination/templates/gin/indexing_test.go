@@ -0,0 +1,71 @@
+package pagination
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestZeroBasedParams(t *testing.T) {
+	cases := map[string]int{
+		"":        0,
+		"page=0":  0,
+		"page=2":  2,
+		"page=-1": 0,
+		"page=x":  0,
+	}
+
+	for query, want := range cases {
+		values, _ := url.ParseQuery(query)
+		params := ParamsFromQueryWithIndexing(values, ZeroBased)
+		if params.Page != want || params.Indexing != ZeroBased {
+			t.Errorf("%q: page = %d (%v), want %d", query, params.Page, params.Indexing, want)
+		}
+	}
+
+	// One-based parsing is unchanged: page=0 falls back to the first page
+	if params := ParamsFromQuery(url.Values{"page": {"0"}}); params.Page != 1 {
+		t.Errorf("one-based page=0: page = %d, want 1", params.Page)
+	}
+}
+
+func TestZeroBasedPageNumbering(t *testing.T) {
+	first := withPageIndexing(&OffsetPagination[int]{CurrentPage: 1, PageSize: 10, TotalPages: 3, HasNext: true}, ZeroBased)
+	if first.CurrentPage != 0 || first.HasPrevious {
+		t.Errorf("first page = %d (has_previous %t), want 0", first.CurrentPage, first.HasPrevious)
+	}
+
+	middle := withPageIndexing(&OffsetPagination[int]{CurrentPage: 2, PageSize: 10, TotalPages: 3, HasNext: true, HasPrevious: true}, ZeroBased)
+	links := middle.ToResponse("/orders").Links
+	want := map[string]string{
+		"first":    "/orders?page=0&page_size=10",
+		"previous": "/orders?page=0&page_size=10",
+		"next":     "/orders?page=2&page_size=10",
+		"last":     "/orders?page=2&page_size=10",
+	}
+	got := map[string]string{
+		"first":    *links.First,
+		"previous": *links.Previous,
+		"next":     *links.Next,
+		"last":     *links.Last,
+	}
+	for rel, href := range want {
+		if got[rel] != href {
+			t.Errorf("%s = %s, want %s", rel, got[rel], href)
+		}
+	}
+
+	// Page 0 is the canonical base URL, page 1 is the second page
+	head := string(first.HeadLinks("/orders"))
+	if !strings.Contains(head, `rel="canonical" href="/orders?page_size=10"`) ||
+		!strings.Contains(head, `rel="next" href="/orders?page=1&amp;page_size=10"`) {
+		t.Errorf("head links:\n%s", head)
+	}
+}
+
+func TestAllPageParamsWithIndexing(t *testing.T) {
+	pages := AllPageParamsWithIndexing(45, 20, ZeroBased)
+	if len(pages) != 3 || pages[0].Page != 0 || pages[2].Page != 2 {
+		t.Errorf("pages = %+v, want pages 0 through 2", pages)
+	}
+}
@@ -0,0 +1,51 @@
+package pagination
+
+// WithItemLinks gives each item of the response its own hypermedia links: the items are
+// rendered as {"data": item, "links": links(item)} instead of bare items
+// links receives items after redaction (WithRedaction), so links are built only from what the
+// caller may see; an item whose links are empty gets no "links". links must be for the page's
+// item type: a function for another type is ignored. Data keeps the bare items for Go callers;
+// only the JSON changes.
+//
+// Example usage:
+//
+//	c.JSON(200, result.ToResponse(c.Request.URL.Path, pagination.WithItemLinks(func(o Order) map[string]string {
+//	    return map[string]string{
+//	        "self":     fmt.Sprintf("/orders/%d", o.ID),
+//	        "customer": fmt.Sprintf("/customers/%d", o.CustomerID),
+//	    }
+//	})))
+func WithItemLinks[T any](links func(T) map[string]string) ResponseOption {
+	return func(o *responseOptions) {
+		if links != nil {
+			o.itemLinks = links
+		}
+	}
+}
+
+// linkedItem is an item rendered with its links
+type linkedItem[T any] struct {
+	Data  T                 `json:"data"`
+	Links map[string]string `json:"links,omitempty"`
+}
+
+// withItemLinks builds each item's links when the options give a builder for T
+func (r *PaginatedResponse[T]) withItemLinks(o responseOptions) {
+	links, ok := o.itemLinks.(func(T) map[string]string)
+	if !ok {
+		return
+	}
+	r.itemLinks = make([]map[string]string, len(r.Data))
+	for i, item := range r.Data {
+		r.itemLinks[i] = links(item)
+	}
+}
+
+// linkedData returns the items wrapped with their links
+func (r PaginatedResponse[T]) linkedData() []linkedItem[T] {
+	items := make([]linkedItem[T], len(r.Data))
+	for i, item := range r.Data {
+		items[i] = linkedItem[T]{Data: item, Links: r.itemLinks[i]}
+	}
+	return items
+}
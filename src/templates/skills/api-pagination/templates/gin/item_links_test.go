@@ -0,0 +1,80 @@
+package pagination
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+// selfLink links each event to its own resource
+func selfLink(e event) map[string]string {
+	return map[string]string{"self": fmt.Sprintf("/events/%d", e.ID)}
+}
+
+// linkedPage is the JSON of a response whose items carry links
+type linkedPage struct {
+	Data []struct {
+		Data  event             `json:"data"`
+		Links map[string]string `json:"links"`
+	} `json:"data"`
+	Links    map[string]any `json:"links"`
+	HALLinks map[string]any `json:"_links"`
+}
+
+func TestItemLinksFromIDs(t *testing.T) {
+	page := &OffsetPagination[event]{Items: eventsWithIDs(7, 9), CurrentPage: 1, TotalPages: 1, TotalItems: 2, PageSize: 20}
+
+	raw, err := json.Marshal(page.ToResponse("/events", WithItemLinks(selfLink)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got linkedPage
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Data) != 2 {
+		t.Fatalf("data = %s, want 2 linked items", raw)
+	}
+	for i, id := range []int64{7, 9} {
+		if got.Data[i].Data.ID != id || got.Data[i].Links["self"] != fmt.Sprintf("/events/%d", id) {
+			t.Errorf("item %d = %+v, want event %d linked to itself", i, got.Data[i], id)
+		}
+	}
+	if got.Links["first"] != "/events?page=1&page_size=20" {
+		t.Errorf("page links = %v, want them kept beside the item links", got.Links)
+	}
+
+	// HAL page links move to "_links" while the items stay wrapped
+	raw, err = json.Marshal(page.ToResponse("/events", WithItemLinks(selfLink), WithLinksFormat(LinksHAL)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = linkedPage{}
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.HALLinks == nil || got.Links != nil || got.Data[0].Links["self"] != "/events/7" {
+		t.Errorf("HAL response with item links = %s", raw)
+	}
+}
+
+func TestItemLinksOffByDefault(t *testing.T) {
+	page := &CursorPagination[event]{Items: eventsWithIDs(7)}
+
+	for _, opts := range [][]ResponseOption{
+		nil,
+		// A builder for another item type is ignored
+		{WithItemLinks(func(p post) map[string]string { return nil })},
+	} {
+		raw, err := json.Marshal(page.ToResponse("", opts...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got struct {
+			Data []event `json:"data"`
+		}
+		if err := json.Unmarshal(raw, &got); err != nil || len(got.Data) != 1 || got.Data[0].ID != 7 {
+			t.Errorf("data = %s, want bare items", raw)
+		}
+	}
+}
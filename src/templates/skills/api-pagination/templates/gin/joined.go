@@ -0,0 +1,85 @@
+package pagination
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// ErrCursorFieldNotAllowed is returned when a cursor paginator is given a table-qualified
+// cursor field that WithJoinedCursorFields does not allow
+var ErrCursorFieldNotAllowed = errors.New("cursor field not allowed")
+
+// qualifiedColumn matches a table-qualified column such as "products.name"
+var qualifiedColumn = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*\.[A-Za-z_][A-Za-z0-9_]*$`)
+
+// cursorColumn is a cursor field resolved for the cursor paginators
+type cursorColumn struct {
+	// sql is the column in WHERE and ORDER BY (quoted for joined columns)
+	sql string
+
+	// key is the column of T page items carry the value in
+	key string
+
+	// joined marks a column of another table, whose tie-breaker is qualified with T's table
+	joined bool
+}
+
+// resolveCursorColumn resolves cursorField against WithJoinedCursorFields
+// Fields without a table qualifier, and every field when no joined fields are allowed, are
+// used as given. Qualified fields must be allowed; they are quoted and read from the column of
+// T the query selects them into.
+func resolveCursorColumn(db *gorm.DB, cursorField string, o options) (cursorColumn, error) {
+	column := cursorColumn{sql: cursorField, key: cursorField}
+	if o.joinedCursorFields == nil || !strings.Contains(cursorField, ".") {
+		return column, nil
+	}
+
+	key, ok := o.joinedCursorFields[cursorField]
+	if !ok {
+		return column, fmt.Errorf("%w: %q (allowed: %s)", ErrCursorFieldNotAllowed, cursorField, strings.Join(joinedFieldNames(o), ", "))
+	}
+	if !qualifiedColumn.MatchString(cursorField) {
+		return column, fmt.Errorf("%w: %q is not a table.column name", ErrCursorFieldNotAllowed, cursorField)
+	}
+
+	return cursorColumn{sql: quoteColumn(db, cursorField), key: key, joined: true}, nil
+}
+
+// joinedFieldNames lists the allowed joined cursor fields for error messages
+func joinedFieldNames(o options) []string {
+	names := make([]string, 0, len(o.joinedCursorFields))
+	for name := range o.joinedCursorFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// quoteColumn quotes a (possibly qualified) column for db's dialect
+func quoteColumn(db *gorm.DB, column string) string {
+	if db.Dialector == nil {
+		return column
+	}
+	return db.Statement.Quote(column)
+}
+
+// qualifyTieBreaker qualifies the primary key tie-breaker of a joined cursor field with T's
+// table, which the unqualified column would be ambiguous with
+func qualifyTieBreaker[T any](db *gorm.DB, tie *tieBreaker, column cursorColumn) error {
+	if tie == nil || tie.field == nil || !column.joined {
+		return nil
+	}
+
+	modelSchema, err := schema.Parse(new(T), &schemaCache, db.NamingStrategy)
+	if err != nil {
+		return fmt.Errorf("failed to parse model schema: %w", err)
+	}
+	tie.column = quoteColumn(db, modelSchema.Table+"."+tie.column)
+	return nil
+}
@@ -0,0 +1,139 @@
+package pagination
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// orderLine is an order line selected with the name of its product
+type orderLine struct {
+	ID          int64
+	ProductID   int64
+	ProductName string
+}
+
+// quotingDialector quotes identifiers with double quotes and binds ? placeholders
+type quotingDialector struct {
+	namedDialector
+}
+
+func (quotingDialector) QuoteTo(w clause.Writer, str string) {
+	w.WriteByte('"')
+	w.WriteString(strings.ReplaceAll(str, ".", `"."`))
+	w.WriteByte('"')
+}
+
+func (quotingDialector) BindVarTo(w clause.Writer, stmt *gorm.Statement, v interface{}) {
+	w.WriteByte('?')
+}
+
+func (quotingDialector) Explain(sql string, vars ...interface{}) string {
+	return sql
+}
+
+// joinedDB returns a query of lines joined to their products that serves lines, sorted by
+// product name then ID, past the cursor condition; queries records each page's SQL
+func joinedDB(t *testing.T, lines []orderLine, queries *[]string) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Dialector = quotingDialector{namedDialector{name: "postgres"}}
+
+	err = db.Callback().Query().Register("test:joined", func(tx *gorm.DB) {
+		dest, ok := tx.Statement.Dest.(*[]orderLine)
+		if !ok {
+			return
+		}
+		tx.Statement.Build("WHERE", "ORDER BY")
+		*queries = append(*queries, tx.Statement.SQL.String())
+
+		vars := tx.Statement.Vars
+		for _, line := range lines {
+			if len(vars) == 3 {
+				name, id := vars[0].(string), vars[2].(int64)
+				if line.ProductName < name || (line.ProductName == name && line.ID <= id) {
+					continue
+				}
+			}
+			*dest = append(*dest, line)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db.Model(&orderLine{}).
+		Select("order_lines.*, products.name AS product_name").
+		Joins("JOIN products ON products.id = order_lines.product_id")
+}
+
+func TestCursorPaginateJoinedColumn(t *testing.T) {
+	lines := []orderLine{
+		{ID: 1, ProductID: 10, ProductName: "apple"},
+		{ID: 4, ProductID: 10, ProductName: "apple"},
+		{ID: 2, ProductID: 20, ProductName: "banana"},
+		{ID: 3, ProductID: 30, ProductName: "cherry"},
+	}
+	var queries []string
+	db := joinedDB(t, lines, &queries)
+	joined := WithJoinedCursorFields(map[string]string{"products.name": "product_name"})
+
+	var page []orderLine
+	first, err := CursorPaginateString(db, &page, "", 2, "products.name", true, joined)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.FirstKey != "apple" || !first.HasNext {
+		t.Fatalf("first page keys %v..%v (has next %t), want product names", first.FirstKey, first.LastKey, first.HasNext)
+	}
+	if want := `ORDER BY "products"."name" ASC,"order_lines"."id" ASC`; !strings.Contains(queries[0], want) {
+		t.Errorf("first page SQL %q, want %s", queries[0], want)
+	}
+
+	second, err := CursorPaginateString(db, &page, *first.EndCursor, 2, "products.name", true, joined)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `WHERE ("products"."name" > ? OR ("products"."name" = ? AND "order_lines"."id" > ?))`
+	if !strings.Contains(queries[1], want) {
+		t.Errorf("second page SQL %q, want %s", queries[1], want)
+	}
+
+	var ids []int64
+	for _, line := range append(first.Items, second.Items...) {
+		ids = append(ids, line.ID)
+	}
+	if want := []int64{1, 4, 2, 3}; !reflect.DeepEqual(ids, want) || second.HasNext {
+		t.Errorf("ids = %v (has next %t), want %v in product name order", ids, second.HasNext, want)
+	}
+}
+
+func TestCursorPaginateJoinedColumnAllowlist(t *testing.T) {
+	var queries []string
+	db := joinedDB(t, nil, &queries)
+	joined := WithJoinedCursorFields(map[string]string{
+		"products.name":       "product_name",
+		"products.name DESC;": "product_name",
+	})
+
+	var page []orderLine
+	for _, field := range []string{"customers.email", "products.name DESC;"} {
+		if _, err := CursorPaginateString(db, &page, "", 2, field, true, joined); !errors.Is(err, ErrCursorFieldNotAllowed) {
+			t.Errorf("%s: err = %v, want ErrCursorFieldNotAllowed", field, err)
+		}
+	}
+	if len(queries) != 0 {
+		t.Errorf("rejected fields ran %d queries", len(queries))
+	}
+
+	// Unqualified fields are the model's own, as without the option
+	if _, err := CursorPaginateInt(db, &page, "", 2, "id", true, joined); err != nil {
+		t.Errorf("own column: %v", err)
+	}
+}
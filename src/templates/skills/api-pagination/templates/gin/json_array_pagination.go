@@ -0,0 +1,191 @@
+package pagination
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"gorm.io/gorm"
+)
+
+// jsonArrayDialect holds the SQL used to count and slice a JSON array column
+// length is a format string taking the column; elements binds (row subquery, limit, offset)
+type jsonArrayDialect struct {
+	length   string
+	elements string
+}
+
+// jsonArrayDialects lists the dialects whose JSON functions can slice arrays in SQL
+// Dialects not listed here fall back to loading the column and slicing in memory
+var jsonArrayDialects = map[string]jsonArrayDialect{
+	// json_each yields objects and arrays as JSON text but scalars as SQL values, so strings
+	// and numbers are quoted back and true, false, and null are spelled out
+	"sqlite": {
+		length: "json_array_length(%s)",
+		elements: "SELECT CASE elem.type WHEN 'object' THEN elem.value WHEN 'array' THEN elem.value " +
+			"WHEN 'true' THEN 'true' WHEN 'false' THEN 'false' WHEN 'null' THEN 'null' " +
+			"ELSE json_quote(elem.value) END FROM (?) AS src, json_each(src.arr) AS elem ORDER BY elem.key LIMIT ? OFFSET ?",
+	},
+	"postgres": {
+		length:   "jsonb_array_length(%s::jsonb)",
+		elements: "SELECT elem.value::text FROM (?) AS src, jsonb_array_elements(src.arr::jsonb) WITH ORDINALITY AS elem(value, idx) ORDER BY elem.idx LIMIT ? OFFSET ?",
+	},
+	"mysql": {
+		length:   "JSON_LENGTH(%s)",
+		elements: "SELECT jt.value FROM (?) AS src, JSON_TABLE(src.arr, '$[*]' COLUMNS (idx FOR ORDINALITY, value JSON PATH '$')) AS jt ORDER BY jt.idx LIMIT ? OFFSET ?",
+	},
+}
+
+// JSONArrayPaginate offset-paginates the elements of a JSON array column on a single row
+// Best for: Document-oriented schemas that store child records (sections, line items) in a JSON column
+//
+// The query must be scoped to exactly one row. On SQLite, Postgres, and MySQL the array is
+// counted and sliced in SQL; other dialects load the column and slice it in memory.
+// The column name is interpolated into SQL, so never pass user input as column.
+//
+// Example usage:
+//
+//	type Section struct {
+//	    Title string `json:"title"`
+//	    Body  string `json:"body"`
+//	}
+//
+//	func GetDocumentSections(c *gin.Context) {
+//	    query := db.Model(&Document{}).Where("id = ?", c.Param("id"))
+//
+//	    result, err := pagination.JSONArrayPaginate[Section](
+//	        query,
+//	        "sections", // JSON array column
+//	        pagination.GetPage(c),
+//	        pagination.GetPageSize(c),
+//	    )
+//	    if errors.Is(err, gorm.ErrRecordNotFound) {
+//	        c.JSON(404, gin.H{"error": "document not found"})
+//	        return
+//	    }
+//	    if err != nil {
+//	        c.JSON(500, gin.H{"error": err.Error()})
+//	        return
+//	    }
+//
+//	    c.JSON(200, result)
+//	}
+func JSONArrayPaginate[E any](
+	db *gorm.DB,
+	column string,
+	page int,
+	pageSize int,
+) (*OffsetPagination[E], error) {
+	// Validate and constrain parameters
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = CurrentConfig().DefaultPageSize
+	}
+	if limit := queryMaxPageSize(db); pageSize > limit {
+		pageSize = limit
+	}
+
+	offset := (page - 1) * pageSize
+
+	var totalItems int64
+	var elements []string
+	var err error
+
+	if dialect, ok := jsonArrayDialects[db.Dialector.Name()]; ok {
+		totalItems, elements, err = sliceJSONArrayInSQL(db, dialect, column, offset, pageSize)
+	} else {
+		totalItems, elements, err = sliceJSONArrayInMemory(db, column, offset, pageSize)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode each element into the caller's element type
+	items := make([]E, 0, len(elements))
+	for i, element := range elements {
+		var item E
+		if err := json.Unmarshal([]byte(element), &item); err != nil {
+			return nil, fmt.Errorf("failed to decode array element %d: %w", offset+i, err)
+		}
+		items = append(items, item)
+	}
+
+	// Calculate total pages
+	totalPages := int(math.Ceil(float64(totalItems) / float64(pageSize)))
+
+	return &OffsetPagination[E]{
+		Items:       items,
+		CurrentPage: page,
+		PageSize:    pageSize,
+		TotalItems:  totalItems,
+		TotalPages:  totalPages,
+		HasNext:     page < totalPages,
+		HasPrevious: page > 1,
+	}, nil
+}
+
+// sliceJSONArrayInSQL counts and slices the array with the dialect's JSON functions
+func sliceJSONArrayInSQL(
+	db *gorm.DB,
+	dialect jsonArrayDialect,
+	column string,
+	offset int,
+	limit int,
+) (int64, []string, error) {
+	var length sql.NullInt64
+	result := db.Session(&gorm.Session{}).Select(fmt.Sprintf(dialect.length, column)).Limit(1).Scan(&length)
+	if result.Error != nil {
+		return 0, nil, fmt.Errorf("failed to count items: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return 0, nil, gorm.ErrRecordNotFound
+	}
+
+	row := db.Session(&gorm.Session{}).Select(fmt.Sprintf("%s AS arr", column)).Limit(1)
+
+	var elements []string
+	if err := db.Session(&gorm.Session{NewDB: true}).
+		Raw(dialect.elements, row, limit, offset).
+		Scan(&elements).Error; err != nil {
+		return 0, nil, fmt.Errorf("failed to fetch items: %w", err)
+	}
+
+	return length.Int64, elements, nil
+}
+
+// sliceJSONArrayInMemory loads the whole array and slices it in Go
+func sliceJSONArrayInMemory(db *gorm.DB, column string, offset int, limit int) (int64, []string, error) {
+	var raw sql.NullString
+	result := db.Session(&gorm.Session{}).Select(column).Limit(1).Scan(&raw)
+	if result.Error != nil {
+		return 0, nil, fmt.Errorf("failed to fetch items: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return 0, nil, gorm.ErrRecordNotFound
+	}
+
+	var all []json.RawMessage
+	if raw.Valid && raw.String != "" {
+		if err := json.Unmarshal([]byte(raw.String), &all); err != nil {
+			return 0, nil, fmt.Errorf("column %s is not a JSON array: %w", column, err)
+		}
+	}
+
+	end := offset + limit
+	if offset > len(all) {
+		offset = len(all)
+	}
+	if end > len(all) {
+		end = len(all)
+	}
+
+	elements := make([]string, 0, end-offset)
+	for _, element := range all[offset:end] {
+		elements = append(elements, string(element))
+	}
+
+	return int64(len(all)), elements, nil
+}
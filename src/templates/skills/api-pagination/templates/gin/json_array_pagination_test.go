@@ -0,0 +1,101 @@
+//go:build paginationsqlite
+
+package pagination
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// document stores its sections as a JSON array
+type document struct {
+	ID       int64
+	Sections string
+}
+
+// section is one element of a document's sections
+type section struct {
+	Title string `json:"title"`
+	Pages int    `json:"pages"`
+}
+
+func TestJSONArrayPaginateSlicesOnSQLite(t *testing.T) {
+	db := openSQLite(t, &document{})
+	doc := document{ID: 1, Sections: `[{"title":"Intro","pages":2},{"title":"Setup","pages":5},` +
+		`{"title":"Usage","pages":9},{"title":"FAQ","pages":1},{"title":"Index","pages":3}]`}
+	if err := db.Create(&doc).Error; err != nil {
+		t.Fatal(err)
+	}
+	query := db.Model(&document{}).Where("id = ?", doc.ID)
+
+	result, err := JSONArrayPaginate[section](query, "sections", 2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []section{
+		{Title: "Usage", Pages: 9},
+		{Title: "FAQ", Pages: 1},
+	}
+	if !reflect.DeepEqual(result.Items, want) {
+		t.Errorf("items = %+v, want %+v", result.Items, want)
+	}
+	if result.TotalItems != 5 || result.TotalPages != 3 || !result.HasNext || !result.HasPrevious {
+		t.Errorf("pagination = %+v, want 5 items over 3 pages with neighbours", result)
+	}
+
+	last, err := JSONArrayPaginate[section](query, "sections", 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(last.Items) != 1 || last.Items[0].Title != "Index" || last.HasNext {
+		t.Errorf("last page = %+v, want only Index", last)
+	}
+}
+
+func TestJSONArrayPaginateDecodesScalarsOnSQLite(t *testing.T) {
+	db := openSQLite(t, &document{})
+	docs := []document{
+		{ID: 1, Sections: `["alpha","beta, \"quoted\"","gamma"]`},
+		{ID: 2, Sections: `[3,1.5,-2]`},
+		{ID: 3, Sections: `[true,false,null,[1,2]]`},
+	}
+	if err := db.Create(&docs).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	strs, err := JSONArrayPaginate[string](db.Model(&document{}).Where("id = ?", 1), "sections", 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"alpha", `beta, "quoted"`, "gamma"}; !reflect.DeepEqual(strs.Items, want) {
+		t.Errorf("strings = %q, want %q", strs.Items, want)
+	}
+
+	nums, err := JSONArrayPaginate[float64](db.Model(&document{}).Where("id = ?", 2), "sections", 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []float64{3, 1.5, -2}; !reflect.DeepEqual(nums.Items, want) {
+		t.Errorf("numbers = %v, want %v", nums.Items, want)
+	}
+
+	mixed, err := JSONArrayPaginate[any](db.Model(&document{}).Where("id = ?", 3), "sections", 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []any{true, false, nil, []any{1.0, 2.0}}; !reflect.DeepEqual(mixed.Items, want) {
+		t.Errorf("elements = %#v, want %#v", mixed.Items, want)
+	}
+}
+
+func TestJSONArrayPaginateMissingRowOnSQLite(t *testing.T) {
+	db := openSQLite(t, &document{})
+
+	_, err := JSONArrayPaginate[section](db.Model(&document{}).Where("id = ?", 404), "sections", 1, 10)
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("err = %v, want gorm.ErrRecordNotFound", err)
+	}
+}
@@ -0,0 +1,35 @@
+package pagination
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// EnvSilenceLegacyCursorWarnings silences the legacy cursor warning when set to a true value
+// ("1", "true"), e.g. once the service's clients no longer hold cursors from before payloads
+const EnvSilenceLegacyCursorWarnings = "PAGINATION_SILENCE_LEGACY_CURSOR_WARNINGS"
+
+// OnLegacyCursor is called whenever a plain-value cursor is accepted in place of a payload
+// Legacy cursors are accepted for one release so sessions survive the deploy. The default logs
+// the first one per process, unless EnvSilenceLegacyCursorWarnings is set; replace it to count
+// every one in a metric, and expect the fallback to be removed once it stops firing.
+var OnLegacyCursor = func(field string) {
+	if legacyCursorWarningsSilenced() {
+		return
+	}
+	legacyCursorLogged.Do(func() {
+		log.Printf("pagination: accepted a legacy plain-value cursor for %q; it will be rejected in the next release "+
+			"(set %s=1 to silence this warning)", field, EnvSilenceLegacyCursorWarnings)
+	})
+}
+
+// legacyCursorLogged keeps the default OnLegacyCursor to one log line per process
+var legacyCursorLogged sync.Once
+
+// legacyCursorWarningsSilenced reports whether EnvSilenceLegacyCursorWarnings is set
+func legacyCursorWarningsSilenced() bool {
+	silenced, _ := strconv.ParseBool(os.Getenv(EnvSilenceLegacyCursorWarnings))
+	return silenced
+}
@@ -0,0 +1,61 @@
+package pagination
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// captureLegacyCursorLog resets the once-per-process warning and records the log until the
+// test ends
+func captureLegacyCursorLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	legacyCursorLogged = sync.Once{}
+
+	var logged bytes.Buffer
+	writer, flags := log.Writer(), log.Flags()
+	log.SetOutput(&logged)
+	log.SetFlags(0)
+	t.Cleanup(func() {
+		log.SetOutput(writer)
+		log.SetFlags(flags)
+	})
+	return &logged
+}
+
+func TestLegacyCursorWarnsOnce(t *testing.T) {
+	logged := captureLegacyCursorLog(t)
+	codec := PayloadCursorCodec("id", true)
+
+	for _, value := range []int{3, 4, 5} {
+		if _, err := codec.Decode(encodeValueCursor(value)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(logged.String()), "\n")
+	if len(lines) != 1 || !strings.Contains(lines[0], `legacy plain-value cursor for "id"`) {
+		t.Fatalf("log = %q, want one legacy cursor warning", logged.String())
+	}
+	if !strings.Contains(lines[0], EnvSilenceLegacyCursorWarnings) {
+		t.Errorf("warning = %q, want it to say how to silence it", lines[0])
+	}
+}
+
+func TestLegacyCursorWarningSilenced(t *testing.T) {
+	logged := captureLegacyCursorLog(t)
+	t.Setenv(EnvSilenceLegacyCursorWarnings, "1")
+
+	value, err := PayloadCursorCodec("id", true).Decode(encodeValueCursor(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != "3" {
+		t.Errorf("value = %v, want the legacy cursor still accepted", value)
+	}
+	if logged.Len() != 0 {
+		t.Errorf("log = %q while silenced", logged.String())
+	}
+}
@@ -0,0 +1,75 @@
+package pagination
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrTooBusy is returned when a QueryLimiter has no free slot for a pagination query
+// Respond with 503 (and Retry-After) so clients back off instead of piling on.
+var ErrTooBusy = errors.New("too many concurrent pagination queries")
+
+// LimitPolicy decides what a query does when every slot of its QueryLimiter is taken
+type LimitPolicy int
+
+const (
+	// WaitForSlot blocks until a slot frees up, or fails with ErrTooBusy once the query's
+	// context is done
+	WaitForSlot LimitPolicy = iota
+
+	// FailFast fails with ErrTooBusy immediately
+	FailFast
+)
+
+// QueryLimiter bounds how many expensive pagination queries run at once
+// It is a semaphore over a buffered channel; pass it with WithCountLimiter (deep COUNTs are
+// the usual thundering herd) and optionally WithFetchLimiter. A nil limiter does not limit.
+type QueryLimiter struct {
+	slots  chan struct{}
+	policy LimitPolicy
+}
+
+// NewQueryLimiter creates a limiter allowing limit concurrent queries (at least 1)
+//
+// Example usage:
+//
+//	// At most 4 report counts at once; queue the rest until the request is canceled
+//	var reportCounts = pagination.NewQueryLimiter(4, pagination.WaitForSlot)
+func NewQueryLimiter(limit int, policy LimitPolicy) *QueryLimiter {
+	if limit < 1 {
+		limit = 1
+	}
+	return &QueryLimiter{slots: make(chan struct{}, limit), policy: policy}
+}
+
+// InFlight returns the number of queries currently holding a slot
+func (l *QueryLimiter) InFlight() int {
+	if l == nil {
+		return 0
+	}
+	return len(l.slots)
+}
+
+// do runs query while holding a slot
+func (l *QueryLimiter) do(ctx context.Context, query func() error) error {
+	if l == nil {
+		return query()
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+	default:
+		if l.policy == FailFast {
+			return ErrTooBusy
+		}
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %v", ErrTooBusy, ctx.Err())
+		}
+	}
+	defer func() { <-l.slots }()
+
+	return query()
+}
@@ -0,0 +1,89 @@
+package pagination
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestQueryLimiterBoundsConcurrentCounts(t *testing.T) {
+	const limit = 3
+	l := NewQueryLimiter(limit, WaitForSlot)
+
+	var running, peak int32
+	count := func() error {
+		now := atomic.AddInt32(&running, 1)
+		for {
+			seen := atomic.LoadInt32(&peak)
+			if now <= seen || atomic.CompareAndSwapInt32(&peak, seen, now) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 30; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := l.do(context.Background(), count); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak > limit {
+		t.Errorf("%d count queries ran concurrently, limit is %d", peak, limit)
+	}
+	if peak < 2 {
+		t.Errorf("peak concurrency = %d; queries should still run in parallel up to the limit", peak)
+	}
+	if l.InFlight() != 0 {
+		t.Errorf("InFlight = %d after every query finished", l.InFlight())
+	}
+}
+
+func TestQueryLimiterPolicies(t *testing.T) {
+	release := make(chan struct{})
+	hold := func(l *QueryLimiter) {
+		started := make(chan struct{})
+		go func() {
+			_ = l.do(context.Background(), func() error {
+				close(started)
+				<-release
+				return nil
+			})
+		}()
+		<-started
+	}
+	defer close(release)
+
+	failFast := NewQueryLimiter(1, FailFast)
+	hold(failFast)
+	if err := failFast.do(context.Background(), func() error { return nil }); !errors.Is(err, ErrTooBusy) {
+		t.Errorf("FailFast err = %v, want ErrTooBusy", err)
+	}
+
+	wait := NewQueryLimiter(1, WaitForSlot)
+	hold(wait)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	ran := false
+	err := wait.do(ctx, func() error { ran = true; return nil })
+	if !errors.Is(err, ErrTooBusy) || ran {
+		t.Errorf("WaitForSlot past its deadline: err = %v, ran = %v", err, ran)
+	}
+
+	// A nil limiter (no WithCountLimiter) never limits
+	var unlimited *QueryLimiter
+	if err := unlimited.do(context.Background(), func() error { return nil }); err != nil {
+		t.Errorf("nil limiter err = %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package pagination
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// MaxPageSize is the generated page size limit, the RuntimeConfig default
+const MaxPageSize = {{maxPageSize}}
+
+// MaxPageSizeFor resolves the effective maximum page size of a request
+// The default returns the current RuntimeConfig's MaxPageSize. Replace it at startup to apply
+// per-tenant or per-plan limits from the request context; the middleware calls it with the
+// request's context and the paginators with the query's (db.WithContext(ctx)). It runs on
+// every paginated request, so keep it cheap, and return a positive value.
+//
+// Example usage:
+//
+//	pagination.MaxPageSizeFor = func(ctx context.Context) int {
+//	    if tenant, ok := tenants.FromContext(ctx); ok && tenant.Plan == "enterprise" {
+//	        return 500
+//	    }
+//	    return pagination.MaxPageSize
+//	}
+var MaxPageSizeFor = func(ctx context.Context) int {
+	return CurrentConfig().MaxPageSize
+}
+
+// EnvelopeBytes is the estimated size of a response's pagination metadata and links, set
+// aside from a byte budget before items are fitted into it
+const EnvelopeBytes = 512
+
+// SuggestPageSize estimates the page size whose response fits in budgetBytes when each item
+// serializes to about itemBytes (see ItemBytes)
+// It is an estimate, not a guarantee: items vary, so size a typical or large sample. The
+// result is between 1 and the configured MaxPageSize; without a positive sample size or
+// budget it is the default page size.
+//
+// Example usage:
+//
+//	size, _ := pagination.ItemBytes(Product{Name: strings.Repeat("x", 64)})
+//	pageSize := pagination.SuggestPageSize(size, 16<<10) // fit responses in ~16 KiB
+func SuggestPageSize(itemBytes, budgetBytes int) int {
+	cfg := CurrentConfig()
+	if itemBytes <= 0 || budgetBytes <= 0 {
+		return cfg.DefaultPageSize
+	}
+
+	// Items are comma-separated inside the data array
+	pageSize := (budgetBytes - EnvelopeBytes) / (itemBytes + 1)
+	if pageSize < 1 {
+		return 1
+	}
+	if pageSize > cfg.MaxPageSize {
+		return cfg.MaxPageSize
+	}
+	return pageSize
+}
+
+// ItemBytes returns the JSON-encoded size of item, the sample SuggestPageSize works from
+func ItemBytes(item any) (int, error) {
+	raw, err := json.Marshal(item)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode sample item: %w", err)
+	}
+	return len(raw), nil
+}
+
+// PageSizeForBudget returns PageSize, lowered to fit the client's max_bytes hint when it sent
+// one, for items of about itemBytes
+//
+// Example usage:
+//
+//	params := pagination.GetPaginationParams(c) // ?max_bytes=16384 from a mobile client
+//	result, err := pagination.OffsetPaginate(db, &products, params.Page, params.PageSizeForBudget(productBytes))
+func (p PaginationParams) PageSizeForBudget(itemBytes int) int {
+	if p.MaxBytes <= 0 {
+		return p.PageSize
+	}
+	if suggested := SuggestPageSize(itemBytes, p.MaxBytes); suggested < p.PageSize {
+		return suggested
+	}
+	return p.PageSize
+}
+
+// queryMaxPageSize resolves MaxPageSizeFor for a paginate call from the query's context
+func queryMaxPageSize(db *gorm.DB) int {
+	return MaxPageSizeFor(queryContext(db))
+}
+
+// sqlLimit returns the LIMIT of a page fetch, capped at the query's maximum page size plus
+// the one extra row the paginators fetch to detect a next page
+// The paginators clamp page sizes long before they query; this second clamp, right before
+// every .Limit(), keeps a page size that slipped past that clamping through a bug from reading
+// more rows. A negative limit, which GORM would drop from the SQL, is capped too.
+func sqlLimit(db *gorm.DB, limit int) int {
+	if hardCap := queryMaxPageSize(db) + 1; limit < 0 || limit > hardCap {
+		return hardCap
+	}
+	return limit
+}
+
+// queryContext returns the query's context (set with db.WithContext), or context.Background()
+func queryContext(db *gorm.DB) context.Context {
+	if db.Statement != nil && db.Statement.Context != nil {
+		return db.Statement.Context
+	}
+	return context.Background()
+}
@@ -0,0 +1,139 @@
+package pagination
+
+import (
+	"context"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+type planKey struct{}
+
+func TestMaxPageSizeForPerTenant(t *testing.T) {
+	saved := MaxPageSizeFor
+	MaxPageSizeFor = func(ctx context.Context) int {
+		if plan, _ := ctx.Value(planKey{}).(string); plan == "enterprise" {
+			return 500
+		}
+		return 50
+	}
+	defer func() { MaxPageSizeFor = saved }()
+
+	free := context.WithValue(context.Background(), planKey{}, "free")
+	enterprise := context.WithValue(context.Background(), planKey{}, "enterprise")
+
+	for _, tc := range []struct {
+		ctx  context.Context
+		want int
+	}{
+		{free, 50},
+		{enterprise, 300},
+	} {
+		req := httptest.NewRequest("GET", "/items?page_size=300", nil).WithContext(tc.ctx)
+		if got := ParamsFromRequest(req).PageSize; got != tc.want {
+			t.Errorf("request page size = %d, want %d", got, tc.want)
+		}
+
+		params, err := paramsFromBody([]byte(`{"page_size": 300}`), MaxPageSizeFor(tc.ctx))
+		if err != nil || params.PageSize != tc.want {
+			t.Errorf("body page size = %d (%v), want %d", params.PageSize, err, tc.want)
+		}
+	}
+
+	// Paginators resolve the limit from the query's context
+	db := &gorm.DB{Statement: &gorm.Statement{Context: free}}
+	if got := queryMaxPageSize(db); got != 50 {
+		t.Errorf("free query limit = %d", got)
+	}
+	db = &gorm.DB{Statement: &gorm.Statement{Context: enterprise}}
+	if got := queryMaxPageSize(db); got != 500 {
+		t.Errorf("enterprise query limit = %d", got)
+	}
+}
+
+func TestMaxPageSizeForDefault(t *testing.T) {
+	if got := queryMaxPageSize(&gorm.DB{}); got != MaxPageSize {
+		t.Errorf("default limit = %d, want MaxPageSize", got)
+	}
+	if got := ParamsFromQuery(map[string][]string{"page_size": {"100000"}}).PageSize; got != MaxPageSize {
+		t.Errorf("ParamsFromQuery page size = %d, want MaxPageSize", got)
+	}
+}
+
+func TestSuggestPageSizeShrinksWithBudget(t *testing.T) {
+	type product struct {
+		ID   int
+		Name string
+	}
+	itemBytes, err := ItemBytes(product{ID: 1, Name: "Trail running shoe, size 42"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	previous := MaxPageSize + 1
+	for _, budget := range []int{1 << 20, 8 << 10, 4 << 10, 2 << 10, 1 << 10} {
+		got := SuggestPageSize(itemBytes, budget)
+		if got > previous {
+			t.Errorf("budget %d: page size %d grew past %d", budget, got, previous)
+		}
+		if got < 1 || got > MaxPageSize {
+			t.Errorf("budget %d: page size %d outside 1..%d", budget, got, MaxPageSize)
+		}
+		previous = got
+	}
+
+	// A budget smaller than the envelope still returns one item
+	if got := SuggestPageSize(itemBytes, 100); got != 1 {
+		t.Errorf("tiny budget = %d, want 1", got)
+	}
+}
+
+func TestPageSizeForBudget(t *testing.T) {
+	params := ParamsFromQuery(url.Values{"page_size": []string{"50"}, "max_bytes": []string{"2048"}})
+	if params.MaxBytes != 2048 {
+		t.Fatalf("MaxBytes = %d, want 2048", params.MaxBytes)
+	}
+	if got := params.PageSizeForBudget(100); got != SuggestPageSize(100, 2048) || got >= 50 {
+		t.Errorf("PageSizeForBudget = %d, want %d", got, SuggestPageSize(100, 2048))
+	}
+
+	// A roomy budget never raises the requested page size
+	params.MaxBytes = 1 << 30
+	if got := params.PageSizeForBudget(100); got != 50 {
+		t.Errorf("roomy budget = %d, want 50", got)
+	}
+}
+
+func TestSQLLimitCapsBypassedPageSize(t *testing.T) {
+	// The first lookup is the paginator's own clamp; a buggy one lets any size through
+	saved := MaxPageSizeFor
+	lookups := 0
+	MaxPageSizeFor = func(ctx context.Context) int {
+		lookups++
+		if lookups == 1 {
+			return 1 << 20
+		}
+		return 10
+	}
+	defer func() { MaxPageSizeFor = saved }()
+
+	var page []post
+	result, err := CursorPaginateRaw(postsDB(t, postsOf(100, 1)), &page, "", nil, "id ASC", 100000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Items) != 11 {
+		t.Errorf("fetched %d rows, want the SQL limit to cap them at 10 plus the next-page probe", len(result.Items))
+	}
+
+	for _, limit := range []int{-1, 1 << 30} {
+		if got := sqlLimit(&gorm.DB{}, limit); got != 11 {
+			t.Errorf("sqlLimit(%d) = %d, want 11", limit, got)
+		}
+	}
+	if got := sqlLimit(&gorm.DB{}, 5); got != 5 {
+		t.Errorf("sqlLimit(5) = %d, want it unchanged", got)
+	}
+}
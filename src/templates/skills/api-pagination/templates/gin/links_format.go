@@ -0,0 +1,144 @@
+package pagination
+
+import (
+	"encoding/json"
+)
+
+// LinksFormat is the JSON shape of a response's navigation links
+type LinksFormat string
+
+const (
+	// LinksObject renders links as an object of URLs under "links" (the default):
+	// {"first": "...", "previous": "...", "next": "...", "last": "..."}
+	LinksObject LinksFormat = "object"
+
+	// LinksHAL renders HAL links under "_links", each relation an object with an href:
+	// {"first": {"href": "..."}, "prev": {"href": "..."}, ...}
+	LinksHAL LinksFormat = "hal"
+
+	// LinksArray renders links as an array under "links", in navigation order:
+	// [{"rel": "first", "href": "..."}, {"rel": "prev", "href": "..."}, ...]
+	LinksArray LinksFormat = "array"
+)
+
+// ResponseOption configures how ToResponse renders a page
+type ResponseOption func(*responseOptions)
+
+// responseOptions holds the resolved settings of a single ToResponse call
+type responseOptions struct {
+	// linksFormat is the JSON shape of the links ("" = LinksObject)
+	linksFormat LinksFormat
+
+	// checksum adds PageChecksum over the rows' primary keys and checksumField
+	checksum      bool
+	checksumField string
+
+	// hidden reports the visibility classes masked in the items (nil = none, see WithRedaction)
+	hidden func(tag string) bool
+
+	// itemLinks is WithItemLinks' func(T) map[string]string (nil = bare items)
+	itemLinks any
+}
+
+// applyResponseOptions resolves opts
+func applyResponseOptions(opts []ResponseOption) responseOptions {
+	var o responseOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithLinksFormat renders the response's links in format, for clients following a hypermedia
+// convention
+// The HAL and array formats name the previous page "prev", its IANA link relation; the
+// PaginationLinks fields are unchanged, so only the JSON differs.
+//
+// Example:
+//
+//	c.JSON(200, result.ToResponse(c.Request.URL.Path, pagination.WithLinksFormat(pagination.LinksHAL)))
+func WithLinksFormat(format LinksFormat) ResponseOption {
+	return func(o *responseOptions) {
+		o.linksFormat = format
+	}
+}
+
+// withLinksFormat sets the format the response's links marshal in
+func (r *PaginatedResponse[T]) withLinksFormat(format LinksFormat) {
+	if r.Links != nil {
+		r.Links.format = format
+	}
+}
+
+// link is one navigation link of the HAL and array formats
+type link struct {
+	Rel  string `json:"rel,omitempty"`
+	Href string `json:"href"`
+}
+
+// relations lists the links that are set, in navigation order, by IANA relation name
+func (l *PaginationLinks) relations() []link {
+	var out []link
+	for _, candidate := range []struct {
+		rel  string
+		href *string
+	}{
+		{"first", l.First},
+		{"prev", l.Previous},
+		{"next", l.Next},
+		{"last", l.Last},
+	} {
+		if candidate.href != nil {
+			out = append(out, link{Rel: candidate.rel, Href: *candidate.href})
+		}
+	}
+	return out
+}
+
+// MarshalJSON renders the links in the format ToResponse was given
+func (l PaginationLinks) MarshalJSON() ([]byte, error) {
+	switch l.format {
+	case LinksHAL:
+		hal := make(map[string]link)
+		for _, relation := range l.relations() {
+			hal[relation.Rel] = link{Href: relation.Href}
+		}
+		return json.Marshal(hal)
+	case LinksArray:
+		relations := l.relations()
+		if relations == nil {
+			relations = []link{}
+		}
+		return json.Marshal(relations)
+	default:
+		type objectLinks PaginationLinks
+		return json.Marshal(objectLinks(l))
+	}
+}
+
+// responseFields is PaginatedResponse without its MarshalJSON
+type responseFields[T any] PaginatedResponse[T]
+
+// MarshalJSON moves HAL links to "_links" (other formats marshal under "links") and wraps
+// items with their links under WithItemLinks
+func (r PaginatedResponse[T]) MarshalJSON() ([]byte, error) {
+	hal := r.Links != nil && r.Links.format == LinksHAL
+	if !hal && r.itemLinks == nil {
+		return json.Marshal(responseFields[T](r))
+	}
+
+	fields := responseFields[T](r)
+	var halLinks *PaginationLinks
+	if hal {
+		fields.Links, halLinks = nil, r.Links
+	}
+	var data any = fields.Data
+	if r.itemLinks != nil {
+		data = r.linkedData()
+	}
+	return json.Marshal(struct {
+		responseFields[T]
+		Data     any              `json:"data"`
+		HALLinks *PaginationLinks `json:"_links,omitempty"`
+	}{fields, data, halLinks})
+}
@@ -0,0 +1,106 @@
+package pagination
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// middlePage is page 2 of 3, so it links in every direction
+func middlePage() *OffsetPagination[int] {
+	return &OffsetPagination[int]{
+		Items:       []int{11},
+		CurrentPage: 2,
+		PageSize:    10,
+		TotalPages:  3,
+		TotalItems:  25,
+		HasNext:     true,
+		HasPrevious: true,
+	}
+}
+
+// decodeObject unmarshals raw JSON into generic values for comparison
+func decodeObject(t *testing.T, raw []byte) map[string]any {
+	t.Helper()
+	var out map[string]any
+	if err := json.Unmarshal(raw, &out); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestLinksFormats(t *testing.T) {
+	var (
+		first = "/orders?page=1&page_size=10"
+		last  = "/orders?page=3&page_size=10"
+	)
+	tests := []struct {
+		name    string
+		opts    []ResponseOption
+		key     string
+		want    string
+		omitted string
+	}{
+		{
+			name:    "default object",
+			key:     "links",
+			want:    `{"first": "` + first + `", "previous": "` + first + `", "next": "` + last + `", "last": "` + last + `"}`,
+			omitted: "_links",
+		},
+		{
+			name:    "HAL",
+			opts:    []ResponseOption{WithLinksFormat(LinksHAL)},
+			key:     "_links",
+			want:    `{"first": {"href": "` + first + `"}, "prev": {"href": "` + first + `"}, "next": {"href": "` + last + `"}, "last": {"href": "` + last + `"}}`,
+			omitted: "links",
+		},
+		{
+			name:    "array",
+			opts:    []ResponseOption{WithLinksFormat(LinksArray)},
+			key:     "links",
+			want:    `[{"rel": "first", "href": "` + first + `"}, {"rel": "prev", "href": "` + first + `"}, {"rel": "next", "href": "` + last + `"}, {"rel": "last", "href": "` + last + `"}]`,
+			omitted: "_links",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := middlePage().ToResponse("/orders", tt.opts...)
+			raw, err := json.Marshal(response)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body := decodeObject(t, raw)
+
+			want := decodeObject(t, []byte(`{"links": `+tt.want+`}`))["links"]
+			if !reflect.DeepEqual(body[tt.key], want) {
+				t.Errorf("%s = %v, want %v", tt.key, body[tt.key], want)
+			}
+			if _, ok := body[tt.omitted]; ok {
+				t.Errorf("%s present in %s", tt.omitted, raw)
+			}
+			if body["data"] == nil || body["pagination"] == nil {
+				t.Errorf("response %s lost its data or metadata", raw)
+			}
+
+			// The Go fields are the same in every format
+			if response.Links.Previous == nil || *response.Links.Previous != first {
+				t.Errorf("Links.Previous = %v", response.Links.Previous)
+			}
+		})
+	}
+}
+
+func TestLinksFormatWithoutBaseURL(t *testing.T) {
+	raw, err := json.Marshal(middlePage().ToResponse("", WithLinksFormat(LinksHAL)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := decodeObject(t, raw)
+	if _, ok := body["_links"]; ok {
+		t.Errorf("response without a base URL has links: %s", raw)
+	}
+	if _, ok := body["links"]; ok {
+		t.Errorf("response without a base URL has links: %s", raw)
+	}
+}
@@ -0,0 +1,225 @@
+package pagination
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Live feed defaults
+const (
+	DefaultLivePollInterval = 2 * time.Second
+	DefaultLiveHeartbeat    = 15 * time.Second
+	DefaultLiveMaxRows      = 10000
+	DefaultLiveEvent        = "item"
+)
+
+// LiveFeedConfig configures ServeLiveFeed
+type LiveFeedConfig struct {
+	// Field is the unique, increasing integer column new rows are found by (default
+	// DefaultFeedField); it must match the feed the client paged through
+	Field string
+
+	// BatchSize is the rows fetched per query (default: the default page size)
+	BatchSize int
+
+	// PollInterval is how often the table is checked for new rows (default
+	// DefaultLivePollInterval)
+	PollInterval time.Duration
+
+	// Notify wakes the stream to check for new rows before the next poll, e.g. from a
+	// Postgres LISTEN or a message bus (nil = poll only); send without blocking
+	Notify <-chan struct{}
+
+	// Heartbeat is how often a comment line keeps idle connections (and proxies) open
+	// (default DefaultLiveHeartbeat)
+	Heartbeat time.Duration
+
+	// MaxRows ends the connection after this many rows (default DefaultLiveMaxRows);
+	// EventSource clients reconnect with Last-Event-ID and resume where it stopped
+	MaxRows int64
+
+	// Event is the SSE event name of each row (default DefaultLiveEvent)
+	Event string
+
+	// Options are passed to every page query; WithCursorCodec must match the feed's
+	Options []Option
+}
+
+// withDefaults fills unset fields
+func (cfg LiveFeedConfig) withDefaults() LiveFeedConfig {
+	if cfg.Field == "" {
+		cfg.Field = DefaultFeedField
+	}
+	if cfg.BatchSize < 1 {
+		cfg.BatchSize = CurrentConfig().DefaultPageSize
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = DefaultLivePollInterval
+	}
+	if cfg.Heartbeat <= 0 {
+		cfg.Heartbeat = DefaultLiveHeartbeat
+	}
+	if cfg.MaxRows <= 0 {
+		cfg.MaxRows = DefaultLiveMaxRows
+	}
+	if cfg.Event == "" {
+		cfg.Event = DefaultLiveEvent
+	}
+	// Feed pages issue descending cursors, which the stream continues in ascending order
+	if applyOptions(cfg.Options).codec == nil {
+		codec := payloadCodec{field: cfg.Field, direction: cursorDirection(true), anyDirection: true}
+		cfg.Options = append(cfg.Options[:len(cfg.Options):len(cfg.Options)], WithCursorCodec(codec))
+	}
+	return cfg
+}
+
+// ServeLiveFeed continues a feed as a Server-Sent Events stream of rows newer than the client's
+// latest cursor
+// A client that paged back through history with FeedPaginate sends its newest item's cursor
+// (the first page's start_cursor) as ?cursor=, or reconnects with the Last-Event-ID header,
+// which takes precedence. Rows past it are streamed oldest first, each as an event whose id
+// is the row's cursor, so a reconnect resumes exactly after the last row received. Without a
+// cursor the stream starts after the current newest row.
+//
+// The stream then polls every PollInterval (or when Notify fires) for new rows, writes a
+// heartbeat comment every Heartbeat, and ends after MaxRows rows or when the client goes away.
+// It returns nil when the client disconnects or the row cap is reached; errors before the
+// stream starts are answered with AbortWithError, later ones end the stream.
+//
+// Example usage:
+//
+//	r.GET("/timeline/live", func(c *gin.Context) {
+//	    _ = pagination.ServeLiveFeed[Post](c, db.Model(&Post{}), pagination.LiveFeedConfig{
+//	        Notify: postNotifications, // e.g. fed by LISTEN new_posts
+//	    })
+//	})
+//
+//	// Browser: new EventSource("/timeline/live?cursor=" + page.start_cursor)
+//	//          .addEventListener("item", e => prepend(JSON.parse(e.data)))
+func ServeLiveFeed[T any](c *gin.Context, db *gorm.DB, cfg LiveFeedConfig) error {
+	cfg = cfg.withDefaults()
+	ctx := c.Request.Context()
+	o := applyOptions(cfg.Options)
+	codec := o.cursorCodec()
+
+	extractor, err := newFieldExtractor[T](db, cfg.Field)
+	if err != nil {
+		AbortWithError(c, ErrorStatus(err), err)
+		return err
+	}
+
+	cursor := c.GetHeader("Last-Event-ID")
+	if cursor == "" {
+		cursor = c.Query("cursor")
+	}
+	if cursor == "" {
+		// Start after the newest row, so the stream carries only rows that arrive from now on
+		var newest []T
+		result, err := FeedPaginateBy(db.WithContext(ctx), &newest, "", 1, cfg.Field, cfg.Options...)
+		if err != nil {
+			AbortWithError(c, ErrorStatus(err), err)
+			return err
+		}
+		if result.StartCursor != nil {
+			cursor = *result.StartCursor
+		}
+	} else if err := checkLiveCursor(cursor, o); err != nil {
+		// Reject a bad cursor while a status can still be sent
+		AbortWithError(c, ErrorStatus(err), err)
+		return err
+	}
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	c.Status(200)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(cfg.Heartbeat)
+	defer heartbeat.Stop()
+	poll := time.NewTimer(0)
+	defer poll.Stop()
+
+	var sent int64
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-heartbeat.C:
+			if _, err := io.WriteString(c.Writer, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+			c.Writer.Flush()
+			continue
+		case <-cfg.Notify:
+			if !poll.Stop() {
+				select {
+				case <-poll.C:
+				default:
+				}
+			}
+		case <-poll.C:
+		}
+
+		// Drain every row past the cursor, batch by batch, before waiting again
+		for {
+			limit := cfg.BatchSize
+			if remaining := cfg.MaxRows - sent; remaining < int64(limit) {
+				limit = int(remaining)
+			}
+
+			var batch []T
+			result, err := CursorPaginateInt(db.WithContext(ctx), &batch, cursor, limit, cfg.Field, true, cfg.Options...)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return fmt.Errorf("failed to fetch live rows: %w", err)
+			}
+
+			for i := range batch {
+				id, err := codec.Encode(extractor.value(ctx, batch[i]))
+				if err != nil {
+					return err
+				}
+				data, err := json.Marshal(batch[i])
+				if err != nil {
+					return fmt.Errorf("failed to encode live row: %w", err)
+				}
+				if _, err := fmt.Fprintf(c.Writer, "id: %s\nevent: %s\ndata: %s\n\n", id, cfg.Event, data); err != nil {
+					return nil
+				}
+				cursor = id
+				sent++
+			}
+			c.Writer.Flush()
+
+			if sent >= cfg.MaxRows {
+				return nil
+			}
+			if !result.HasNext {
+				break
+			}
+		}
+		poll.Reset(cfg.PollInterval)
+	}
+}
+
+// checkLiveCursor fails with ErrInvalidCursor unless cursor decodes to an integer
+func checkLiveCursor(cursor string, o options) error {
+	value, restarted, err := decodeCursor(cursor, o)
+	if err != nil || restarted {
+		return err
+	}
+	if _, err := cursorInt(value); err != nil {
+		return fmt.Errorf("%w value: %v", ErrInvalidCursor, err)
+	}
+	return nil
+}
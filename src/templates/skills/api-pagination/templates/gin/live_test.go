@@ -0,0 +1,147 @@
+package pagination
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// liveDB serves *rows (in ID order) the way the cursor query bounds, orders, and limits them;
+// fetched is called after each ascending fetch
+func liveDB(t *testing.T, rows *[]event, fetched func()) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Callback().Query().Register("test:live", func(tx *gorm.DB) {
+		dest, ok := tx.Statement.Dest.(*[]event)
+		if !ok {
+			return
+		}
+
+		after := int64(-1)
+		if c, ok := tx.Statement.Clauses["WHERE"]; ok {
+			for _, e := range c.Expression.(clause.Where).Exprs {
+				if expr, ok := e.(clause.Expr); ok && len(expr.Vars) > 0 {
+					after = expr.Vars[0].(int64)
+				}
+			}
+		}
+		descending := strings.HasSuffix(tx.Statement.Clauses["ORDER BY"].Expression.(clause.OrderBy).Columns[0].Column.Name, "DESC")
+		limit := *tx.Statement.Clauses["LIMIT"].Expression.(clause.Limit).Limit
+
+		for i := range *rows {
+			row := (*rows)[i]
+			if descending {
+				row = (*rows)[len(*rows)-1-i]
+			}
+			if after >= 0 && ((!descending && row.ID <= after) || (descending && row.ID >= after)) {
+				continue
+			}
+			if len(*dest) < limit {
+				*dest = append(*dest, row)
+			}
+		}
+		if !descending && fetched != nil {
+			fetched()
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db.Model(&event{})
+}
+
+// serveLive runs ServeLiveFeed for a GET of target until it returns
+func serveLive(ctx context.Context, db *gorm.DB, target, lastEventID string, cfg LiveFeedConfig) (*httptest.ResponseRecorder, error) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	var serveErr error
+	r.GET("/live", func(c *gin.Context) {
+		serveErr = ServeLiveFeed[event](c, db, cfg)
+	})
+
+	req := httptest.NewRequest("GET", target, nil).WithContext(ctx)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w, serveErr
+}
+
+func TestLiveFeedResumesFromLastEventID(t *testing.T) {
+	rows := eventsWithIDs(1, 2, 3, 4, 5)
+	db := liveDB(t, &rows, nil)
+
+	w, err := serveLive(context.Background(), db, "/live", EncodeCursor("id", 2, false), LiveFeedConfig{MaxRows: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("content type %q", ct)
+	}
+
+	// Rows after the cursor, oldest first, each identified by its own cursor; the row cap
+	// ends the stream after two
+	want := fmt.Sprintf("id: %s\nevent: item\ndata: {\"ID\":3}\n\nid: %s\nevent: item\ndata: {\"ID\":4}\n\n", EncodeCursor("id", 3, true), EncodeCursor("id", 4, true))
+	if body := w.Body.String(); body != want {
+		t.Errorf("stream =\n%s\nwant\n%s", body, want)
+	}
+}
+
+func TestLiveFeedWakesOnNotify(t *testing.T) {
+	rows := eventsWithIDs(1, 2)
+	notify := make(chan struct{}, 1)
+	fetches := 0
+	db := liveDB(t, &rows, func() {
+		// A row is inserted after the first check and its notification sent
+		fetches++
+		if fetches == 1 {
+			rows = append(rows, event{ID: 3})
+			notify <- struct{}{}
+		}
+	})
+
+	cfg := LiveFeedConfig{PollInterval: time.Hour, Notify: notify, MaxRows: 1}
+	w, err := serveLive(context.Background(), db, "/live?cursor="+EncodeCursor("id", 2, false), "", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(w.Body.String(), "id: "+EncodeCursor("id", 3, true)+"\n") || fetches != 2 {
+		t.Errorf("after %d fetches, stream %q; want row 3 pushed on notify", fetches, w.Body)
+	}
+}
+
+func TestLiveFeedHeartbeatUntilClientLeaves(t *testing.T) {
+	var rows []event
+	db := liveDB(t, &rows, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	w, err := serveLive(ctx, db, "/live", "", LiveFeedConfig{PollInterval: time.Hour, Heartbeat: 5 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("client going away: %v, want nil", err)
+	}
+	if !strings.Contains(w.Body.String(), ": heartbeat\n\n") {
+		t.Errorf("idle stream %q, want heartbeats", w.Body)
+	}
+}
+
+func TestLiveFeedRejectsBadCursor(t *testing.T) {
+	var rows []event
+	db := liveDB(t, &rows, nil)
+
+	w, err := serveLive(context.Background(), db, "/live?cursor=!!!", "", LiveFeedConfig{})
+	if w.Code != 400 || err == nil {
+		t.Errorf("bad cursor: status %d, err %v; want 400", w.Code, err)
+	}
+}
@@ -0,0 +1,31 @@
+package pagination
+
+// Metrics receives paginator instrumentation events
+// Implement it to export counters (Prometheus, OpenTelemetry, expvar) and pass it with
+// WithMetrics; methods are called synchronously on the request path, so keep them cheap.
+//
+// Example usage:
+//
+//	type promMetrics struct{ hits, misses *prometheus.CounterVec }
+//
+//	func (m promMetrics) PageCacheHit(table string)  { m.hits.WithLabelValues(table).Inc() }
+//	func (m promMetrics) PageCacheMiss(table string) { m.misses.WithLabelValues(table).Inc() }
+type Metrics interface {
+	// PageCacheHit and PageCacheMiss report page cache lookups (see WithPageCache)
+	PageCacheHit(table string)
+	PageCacheMiss(table string)
+}
+
+// noopMetrics is used when no WithMetrics option is given
+type noopMetrics struct{}
+
+func (noopMetrics) PageCacheHit(table string)  {}
+func (noopMetrics) PageCacheMiss(table string) {}
+
+// metricsOrNoop returns the configured Metrics, falling back to a no-op implementation
+func (o options) metricsOrNoop() Metrics {
+	if o.metrics == nil {
+		return noopMetrics{}
+	}
+	return o.metrics
+}
@@ -1,6 +1,8 @@
 package pagination
 
 import (
+	"net/http"
+	"net/url"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -11,22 +13,106 @@ type PaginationParams struct {
 	Page     int
 	PageSize int
 	Cursor   string
+
+	// Indexing is the page numbering Page was parsed with; pass it to WithPageIndexing
+	Indexing PageIndexing
+
+	// CountToken is the client's count token (?count_token= or the X-Count-Token header), for
+	// WithCountToken
+	CountToken string
+
+	// PageToken is the client's opaque page token (?page_token=), and Fingerprint the
+	// QueryFingerprint of the request's other params, for WithPageToken
+	PageToken   string
+	Fingerprint string
+
+	// MaxBytes is the client's response size hint in bytes (?max_bytes= or the X-Max-Bytes
+	// header), for PageSizeForBudget and WithByteBudget
+	MaxBytes int
+
+	// MetadataOnly is set when the client asked for page_size=0 (or limit=0); PageSize keeps
+	// the default so handlers that ignore it still page normally. Pass RequestedPageSize to a
+	// paginator given AllowZeroPageSize to serve totals without rows.
+	MetadataOnly bool
+
+	// All is set when the client asked for page_size=all (or -1, or the same for limit);
+	// PageSize keeps the default so routes that do not allow it page normally. Pass
+	// RequestedPageSize to a paginator given AllowAll to return every row.
+	All bool
+
+	// Sort and Filter are set only by ParseParamsFromBody, in query-string form
+	// ("-created_at,name" and filter[field][op] params); query requests leave them to the
+	// sorting and filtering middleware
+	Sort   string
+	Filter url.Values
 }
 
 // DefaultPaginationParams returns default pagination parameters
 func DefaultPaginationParams() PaginationParams {
 	return PaginationParams{
 		Page:     1,
-		PageSize: {{defaultPageSize}},
+		PageSize: CurrentConfig().DefaultPageSize,
 		Cursor:   "",
 	}
 }
 
+// PageSizeAll is the page size of a request for every row (see AllowAll)
+const PageSizeAll = -1
+
+// RequestedPageSize is PageSize, 0 for a metadata-only request, or PageSizeAll
+func (p PaginationParams) RequestedPageSize() int {
+	if p.MetadataOnly {
+		return 0
+	}
+	if p.All {
+		return PageSizeAll
+	}
+	return p.PageSize
+}
+
+// AllPageParams enumerates the params of every page for a known total
+// Useful for pre-generating page URLs (sitemaps, static exports) without querying each page
+// An empty result set has no pages; a total that is an exact multiple of pageSize has no trailing empty page
+//
+// Example usage:
+//
+//	for _, p := range pagination.AllPageParams(totalProducts, 50) {
+//	    sitemap.Add(fmt.Sprintf("https://shop.example.com/products?page=%d&page_size=%d", p.Page, p.PageSize))
+//	}
+func AllPageParams(totalItems int64, pageSize int) []PaginationParams {
+	return AllPageParamsWithIndexing(totalItems, pageSize, OneBased)
+}
+
+// AllPageParamsWithIndexing is AllPageParams numbering pages with indexing
+func AllPageParamsWithIndexing(totalItems int64, pageSize int, indexing PageIndexing) []PaginationParams {
+	cfg := CurrentConfig()
+	if pageSize < 1 {
+		pageSize = cfg.DefaultPageSize
+	}
+	if pageSize > cfg.MaxPageSize {
+		pageSize = cfg.MaxPageSize
+	}
+
+	if totalItems <= 0 {
+		return []PaginationParams{}
+	}
+
+	totalPages := int((totalItems + int64(pageSize) - 1) / int64(pageSize))
+	pages := make([]PaginationParams, 0, totalPages)
+	for page := 1; page <= totalPages; page++ {
+		pages = append(pages, PaginationParams{Page: indexing.clientPage(page), PageSize: pageSize, Indexing: indexing})
+	}
+
+	return pages
+}
+
 // ParsePaginationParams extracts pagination parameters from Gin context
 // This middleware parses query parameters and adds them to the context
 //
 // Example usage:
 //
+//	import "{{packageImportPath}}/pagination"
+//
 //	func main() {
 //	    r := gin.Default()
 //
@@ -42,43 +128,172 @@ func DefaultPaginationParams() PaginationParams {
 //	    // Use params.Page, params.PageSize, params.Cursor
 //	}
 func ParsePaginationParams(c *gin.Context) {
+	params := ParamsFromRequest(c.Request)
+
+	// Store in context for handler use
+	c.Set("pagination_params", params)
+
+	c.Next()
+}
+
+// ParsePaginationParamsWithIndexing is ParsePaginationParams for routes that number pages
+// with indexing
+// Under ZeroBased, page=0 is valid and is the default; negative pages still fall back to it.
+//
+// Example usage:
+//
+//	r.GET("/legacy/orders", pagination.ParsePaginationParamsWithIndexing(pagination.ZeroBased), ListLegacyOrders)
+func ParsePaginationParamsWithIndexing(indexing PageIndexing) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		params := ParamsFromRequestWithIndexing(c.Request, indexing)
+
+		// Store in context for handler use
+		c.Set("pagination_params", params)
+
+		c.Next()
+	}
+}
+
+// QueryKeys lists the query parameters read by ParamsFromQuery
+var QueryKeys = []string{"page", "page_size", "limit", "cursor", "count_token", "page_token", "max_bytes"}
+
+// ParamsFromRequest parses a request's query string like ParsePaginationParams, with the
+// page size limit resolved by MaxPageSizeFor from the request's context
+// A count token may also arrive in the X-Count-Token header; the query parameter wins.
+func ParamsFromRequest(r *http.Request) PaginationParams {
+	return ParamsFromRequestWithIndexing(r, OneBased)
+}
+
+// ParamsFromRequestWithIndexing is ParamsFromRequest numbering pages with indexing
+func ParamsFromRequestWithIndexing(r *http.Request, indexing PageIndexing) PaginationParams {
+	params := paramsFromQuery(r.URL.Query(), MaxPageSizeFor(r.Context()), indexing)
+	if params.CountToken == "" {
+		params.CountToken = r.Header.Get(CountTokenHeader)
+	}
+	if params.MaxBytes == 0 {
+		if maxBytes, err := strconv.Atoi(r.Header.Get(MaxBytesHeader)); err == nil && maxBytes > 0 {
+			params.MaxBytes = maxBytes
+		}
+	}
+	return params
+}
+
+// ParamsFromQuery parses pagination params from a query string with the same aliases and
+// defaults as ParsePaginationParams, limited to the configured MaxPageSize
+func ParamsFromQuery(values url.Values) PaginationParams {
+	return paramsFromQuery(values, CurrentConfig().MaxPageSize, OneBased)
+}
+
+// ParamsFromQueryWithIndexing is ParamsFromQuery numbering pages with indexing
+func ParamsFromQueryWithIndexing(values url.Values, indexing PageIndexing) PaginationParams {
+	return paramsFromQuery(values, CurrentConfig().MaxPageSize, indexing)
+}
+
+// paramsFromQuery parses pagination params, clamping the page size to maxPageSize
+func paramsFromQuery(values url.Values, maxPageSize int, indexing PageIndexing) PaginationParams {
 	params := DefaultPaginationParams()
+	params.Page = indexing.FirstPage()
+	params.Indexing = indexing
 
 	// Parse page number (offset pagination)
-	if pageStr := c.Query("page"); pageStr != "" {
-		if page, err := strconv.Atoi(pageStr); err == nil && page > 0 {
+	if pageStr := values.Get("page"); pageStr != "" {
+		if page, err := strconv.Atoi(pageStr); err == nil && page >= indexing.FirstPage() {
 			params.Page = page
 		}
 	}
 
 	// Parse page size
-	if pageSizeStr := c.Query("page_size"); pageSizeStr != "" {
+	if pageSizeStr := values.Get("page_size"); pageSizeStr != "" {
 		if pageSize, err := strconv.Atoi(pageSizeStr); err == nil && pageSize > 0 {
 			params.PageSize = pageSize
+		} else if err == nil && pageSize == 0 {
+			params.MetadataOnly = true
+		} else if pageSizeStr == "all" || pageSize == PageSizeAll {
+			params.All = true
 		}
 	}
 
 	// Also check "limit" as an alias for page_size
-	if limitStr := c.Query("limit"); limitStr != "" {
+	if limitStr := values.Get("limit"); limitStr != "" {
 		if limit, err := strconv.Atoi(limitStr); err == nil && limit > 0 {
 			params.PageSize = limit
+			params.MetadataOnly, params.All = false, false
+		} else if err == nil && limit == 0 {
+			params.MetadataOnly, params.All = true, false
+		} else if limitStr == "all" || limit == PageSizeAll {
+			params.MetadataOnly, params.All = false, true
 		}
 	}
 
 	// Parse cursor (cursor pagination)
-	if cursor := c.Query("cursor"); cursor != "" {
+	if cursor := values.Get("cursor"); cursor != "" {
 		params.Cursor = cursor
 	}
 
+	// Parse count token (offset totals reused across pages)
+	if token := values.Get("count_token"); token != "" {
+		params.CountToken = token
+	}
+
+	// Parse page token (offset pages bound to the request's filters and sort)
+	if token := values.Get("page_token"); token != "" {
+		params.PageToken = token
+	}
+	params.Fingerprint = QueryFingerprint(values)
+
+	// Parse the response size hint (page size tuned to a byte budget)
+	if maxBytes, err := strconv.Atoi(values.Get("max_bytes")); err == nil && maxBytes > 0 {
+		params.MaxBytes = maxBytes
+	}
+
 	// Constrain page size to maximum
-	if params.PageSize > {{maxPageSize}} {
-		params.PageSize = {{maxPageSize}}
+	if params.PageSize > maxPageSize {
+		params.PageSize = maxPageSize
 	}
 
-	// Store in context for handler use
-	c.Set("pagination_params", params)
+	return params
+}
 
-	c.Next()
+// CanonicalQuery renders params in a single normalized form, so requests that mean the same
+// page produce the same string (e.g. for cache keys)
+// "?page_size=20&page=2", "?page=2&limit=20", and "?limit=20&page=2&page_size=" all
+// yield "page=2&page_size=20"; defaults are filled in and the cursor, page token, and
+// max_bytes hint are included when set.
+// Metadata-only requests render page_size=0 and requests for every row page_size=-1, so they
+// never share a key with a page of rows.
+//
+// Example usage:
+//
+//	key := c.Request.URL.Path + "?" + pagination.ParamsFromQuery(c.Request.URL.Query()).CanonicalQuery()
+func (p PaginationParams) CanonicalQuery() string {
+	values := url.Values{}
+	values.Set("page", strconv.Itoa(p.Page))
+	values.Set("page_size", strconv.Itoa(p.RequestedPageSize()))
+	if p.Cursor != "" {
+		values.Set("cursor", p.Cursor)
+	}
+	if p.PageToken != "" {
+		values.Set("page_token", p.PageToken)
+	}
+	if p.MaxBytes > 0 {
+		values.Set("max_bytes", strconv.Itoa(p.MaxBytes))
+	}
+	return values.Encode()
+}
+
+// AbortWithError responds to a request whose pagination params cannot be used
+// The default writes ErrorBody with status; installing the api-error-handling skill replaces it
+// so these failures render as problem+json like every other error.
+var AbortWithError = func(c *gin.Context, status int, err error) {
+	c.AbortWithStatusJSON(status, ErrorBody(status, err))
+}
+
+// ErrorBody is the structured body of a pagination error outside an HTTP response, such as a
+// WebSocket error frame
+// The default is {"error": ...}; installing the api-error-handling skill replaces it with the problem
+// AbortWithError would render.
+var ErrorBody = func(status int, err error) any {
+	return gin.H{"error": err.Error()}
 }
 
 // GetPaginationParams retrieves pagination params from Gin context
@@ -105,7 +320,8 @@ func GetPage(c *gin.Context) int {
 
 // GetPageSize extracts page size from query params with validation
 func GetPageSize(c *gin.Context) int {
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", strconv.Itoa({{defaultPageSize}})))
+	defaultPageSize := CurrentConfig().DefaultPageSize
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", strconv.Itoa(defaultPageSize)))
 
 	// Also check "limit" as an alias
 	if limitStr := c.Query("limit"); limitStr != "" {
@@ -115,10 +331,10 @@ func GetPageSize(c *gin.Context) int {
 	}
 
 	if pageSize < 1 {
-		pageSize = {{defaultPageSize}}
+		pageSize = defaultPageSize
 	}
-	if pageSize > {{maxPageSize}} {
-		pageSize = {{maxPageSize}}
+	if limit := MaxPageSizeFor(c.Request.Context()); pageSize > limit {
+		pageSize = limit
 	}
 	return pageSize
 }
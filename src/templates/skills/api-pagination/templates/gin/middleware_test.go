@@ -0,0 +1,29 @@
+package pagination
+
+import "testing"
+
+func TestAllPageParams(t *testing.T) {
+	cases := []struct {
+		name  string
+		total int64
+		pages int
+	}{
+		{name: "empty", total: 0, pages: 0},
+		{name: "exact multiple", total: 60, pages: 3},
+		{name: "remainder", total: 45, pages: 3},
+		{name: "single short page", total: 7, pages: 1},
+	}
+
+	for _, tc := range cases {
+		pages := AllPageParams(tc.total, 20)
+		if pages == nil || len(pages) != tc.pages {
+			t.Errorf("%s: %d pages (%+v), want %d", tc.name, len(pages), pages, tc.pages)
+			continue
+		}
+		for i, p := range pages {
+			if p.Page != i+1 || p.PageSize != 20 || p.Indexing != OneBased {
+				t.Errorf("%s: page %d = %+v, want page %d of size 20, one-based", tc.name, i, p, i+1)
+			}
+		}
+	}
+}
@@ -1,10 +1,21 @@
 package pagination
 
+import (
+	"fmt"
+	"net/url"
+)
+
 // PaginatedResponse is a generic wrapper for paginated API responses
 type PaginatedResponse[T any] struct {
-	Data       []T             `json:"data"`
-	Pagination PaginationMeta  `json:"pagination"`
+	Data       []T              `json:"data"`
+	Pagination PaginationMeta   `json:"pagination"`
 	Links      *PaginationLinks `json:"links,omitempty"`
+
+	// NextPreview is the first item of the next page (cursor pages under WithPeek)
+	NextPreview *T `json:"next_preview,omitempty"`
+
+	// itemLinks are the links of each item in Data (nil = bare items, see WithItemLinks)
+	itemLinks []map[string]string
 }
 
 // PaginationMeta contains pagination metadata (works for both cursor and offset)
@@ -14,14 +25,56 @@ type PaginationMeta struct {
 	TotalPages  *int   `json:"total_pages,omitempty"`
 	TotalItems  *int64 `json:"total_items,omitempty"`
 
+	// CountToken and TotalFromToken describe a reusable total (see WithCountToken)
+	CountToken     string `json:"count_token,omitempty"`
+	TotalFromToken bool   `json:"total_from_token,omitempty"`
+
+	// CountMode is "skipped" when the total was shed by a count breaker (WithCountBreaker);
+	// the offset totals are then omitted
+	CountMode string `json:"count_mode,omitempty"`
+
+	// CountSource is where the total came from under a count fallback policy or counter
+	// source (exact, cache, estimate, none, or counter), and Warnings explains a total that is
+	// not an exact count
+	CountSource string   `json:"count_source,omitempty"`
+	Warnings    []string `json:"warnings,omitempty"`
+
 	// Common fields
 	PageSize    int  `json:"page_size"`
 	HasNext     bool `json:"has_next"`
 	HasPrevious bool `json:"has_previous"`
 
 	// Cursor pagination fields
-	NextCursor     *string `json:"next_cursor,omitempty"`
-	PreviousCursor *string `json:"previous_cursor,omitempty"`
+	NextCursor      *string `json:"next_cursor,omitempty"`
+	PreviousCursor  *string `json:"previous_cursor,omitempty"`
+	StartCursor     *string `json:"start_cursor,omitempty"`
+	EndCursor       *string `json:"end_cursor,omitempty"`
+	ApproxRemaining *int64  `json:"approx_remaining,omitempty"`
+
+	// NewItemsAvailable reports rows added before the session's first page (WithDriftDetection)
+	NewItemsAvailable bool `json:"new_items_available,omitempty"`
+
+	// Restarted reports a cursor sent back to the first page by its codec (ErrRestartPagination)
+	Restarted bool `json:"restarted,omitempty"`
+
+	// PageBytes and RowCount are the approximate size and rows of a byte-budgeted page
+	// (WithByteBudget)
+	PageBytes int `json:"page_bytes,omitempty"`
+	RowCount  int `json:"row_count,omitempty"`
+
+	// DeepPagination marks an offset page past WithDeepPaginationHint's depth; NextCursor
+	// then continues it with cursor pagination
+	DeepPagination bool `json:"deep_pagination,omitempty"`
+
+	// NextPageToken and PreviousPageToken are the opaque offset page tokens (WithPageToken)
+	NextPageToken     *string `json:"next_page_token,omitempty"`
+	PreviousPageToken *string `json:"previous_page_token,omitempty"`
+
+	// MetadataOnly marks a page size 0 response that carries totals but no rows
+	MetadataOnly bool `json:"metadata_only,omitempty"`
+
+	// PageChecksum hashes the page's keys and versions for change detection (WithPageChecksum)
+	PageChecksum string `json:"page_checksum,omitempty"`
 }
 
 // PaginationLinks contains HATEOAS links for pagination navigation
@@ -30,58 +83,131 @@ type PaginationLinks struct {
 	Previous *string `json:"previous,omitempty"`
 	Next     *string `json:"next,omitempty"`
 	Last     *string `json:"last,omitempty"`
+
+	// format is the JSON shape the links marshal in (see WithLinksFormat)
+	format LinksFormat
+}
+
+// nonNilItems returns items, or an empty slice when items is nil
+// Find leaves a nil slice on an empty page, which marshals to null; clients expect []
+func nonNilItems[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
 }
 
 // ToResponse converts OffsetPagination to PaginatedResponse
-func (p *OffsetPagination[T]) ToResponse(baseURL string) PaginatedResponse[T] {
+func (p *OffsetPagination[T]) ToResponse(baseURL string, opts ...ResponseOption) PaginatedResponse[T] {
 	response := PaginatedResponse[T]{
-		Data: p.Items,
+		Data: nonNilItems(p.Items),
 		Pagination: PaginationMeta{
-			CurrentPage: &p.CurrentPage,
-			TotalPages:  &p.TotalPages,
-			TotalItems:  &p.TotalItems,
-			PageSize:    p.PageSize,
-			HasNext:     p.HasNext,
-			HasPrevious: p.HasPrevious,
+			CurrentPage:    &p.CurrentPage,
+			TotalPages:     &p.TotalPages,
+			TotalItems:     &p.TotalItems,
+			PageSize:       p.PageSize,
+			HasNext:        p.HasNext,
+			HasPrevious:    p.HasPrevious,
+			MetadataOnly:   p.MetadataOnly,
+			CountToken:     p.CountToken,
+			TotalFromToken: p.TotalFromToken,
+			CountMode:      string(p.CountMode),
+			CountSource:    string(p.CountSource),
+			Warnings:       p.Warnings,
+			NextCursor:     p.NextCursor,
+			DeepPagination: p.DeepPagination,
+
+			NextPageToken:     p.NextPageToken,
+			PreviousPageToken: p.PreviousPageToken,
 		},
 	}
 
+	// A shed count leaves the totals unknown, not zero
+	if p.CountMode == CountSkipped {
+		response.Pagination.TotalPages, response.Pagination.TotalItems = nil, nil
+	}
+
 	// Add HATEOAS links if base URL provided
+	// Links carry the count token so clients following them skip the recount
 	if baseURL != "" {
-		first := fmt.Sprintf("%s?page=1&page_size=%d", baseURL, p.PageSize)
-		last := fmt.Sprintf("%s?page=%d&page_size=%d", baseURL, p.TotalPages, p.PageSize)
-
-		links := &PaginationLinks{
-			First: &first,
-			Last:  &last,
+		response.Links = p.links(func(page int) string {
+			link := fmt.Sprintf("%s?page=%d&page_size=%d", baseURL, page, p.PageSize)
+			if p.CountToken != "" {
+				link += "&count_token=" + url.QueryEscape(p.CountToken)
+			}
+			return link
+		})
+
+		// Under WithPageToken the neighbouring pages are reached by token, not page number
+		if p.NextPageToken != nil {
+			next := fmt.Sprintf("%s?page_token=%s", baseURL, url.QueryEscape(*p.NextPageToken))
+			response.Links.Next = &next
 		}
-
-		if p.HasPrevious {
-			prev := fmt.Sprintf("%s?page=%d&page_size=%d", baseURL, p.CurrentPage-1, p.PageSize)
-			links.Previous = &prev
+		if p.PreviousPageToken != nil {
+			prev := fmt.Sprintf("%s?page_token=%s", baseURL, url.QueryEscape(*p.PreviousPageToken))
+			response.Links.Previous = &prev
 		}
+	}
 
-		if p.HasNext {
-			next := fmt.Sprintf("%s?page=%d&page_size=%d", baseURL, p.CurrentPage+1, p.PageSize)
-			links.Next = &next
-		}
+	render := applyResponseOptions(opts)
+	response.withLinksFormat(render.linksFormat)
+	response.withChecksum(render)
+	response.withRedaction(render)
+	response.withItemLinks(render)
+	return response
+}
 
-		response.Links = links
+// links builds the navigation links of an offset page with pageURL
+// A metadata-only page links only to the first and last pages of PageSize rows
+func (p *OffsetPagination[T]) links(pageURL func(page int) string) *PaginationLinks {
+	first := pageURL(p.Indexing.FirstPage())
+	last := first
+	if p.TotalPages > 0 {
+		last = pageURL(p.Indexing.clientPage(p.TotalPages))
 	}
 
-	return response
+	links := &PaginationLinks{
+		First: &first,
+		Last:  &last,
+	}
+	if p.CountMode == CountSkipped {
+		links.Last = nil
+	}
+
+	if p.HasPrevious && !p.MetadataOnly {
+		prev := pageURL(p.CurrentPage - 1)
+		links.Previous = &prev
+	}
+
+	if p.HasNext && !p.MetadataOnly {
+		next := pageURL(p.CurrentPage + 1)
+		links.Next = &next
+	}
+
+	return links
 }
 
 // ToResponse converts CursorPagination to PaginatedResponse
-func (p *CursorPagination[T]) ToResponse(baseURL string) PaginatedResponse[T] {
+func (p *CursorPagination[T]) ToResponse(baseURL string, opts ...ResponseOption) PaginatedResponse[T] {
 	response := PaginatedResponse[T]{
-		Data: p.Items,
+		Data:        nonNilItems(p.Items),
+		NextPreview: p.NextPreview,
 		Pagination: PaginationMeta{
-			PageSize:       p.PageSize,
-			HasNext:        p.HasNext,
-			HasPrevious:    p.HasPrevious,
-			NextCursor:     p.NextCursor,
-			PreviousCursor: p.PreviousCursor,
+			CurrentPage:       p.CurrentPage,
+			TotalPages:        p.TotalPages,
+			TotalItems:        p.TotalItems,
+			PageSize:          p.PageSize,
+			HasNext:           p.HasNext,
+			HasPrevious:       p.HasPrevious,
+			NextCursor:        p.NextCursor,
+			PreviousCursor:    p.PreviousCursor,
+			StartCursor:       p.StartCursor,
+			EndCursor:         p.EndCursor,
+			ApproxRemaining:   p.ApproxRemaining,
+			NewItemsAvailable: p.NewItemsAvailable,
+			Restarted:         p.Restarted,
+			PageBytes:         p.PageBytes,
+			RowCount:          p.RowCount,
 		},
 	}
 
@@ -102,5 +228,10 @@ func (p *CursorPagination[T]) ToResponse(baseURL string) PaginatedResponse[T] {
 		response.Links = links
 	}
 
+	render := applyResponseOptions(opts)
+	response.withLinksFormat(render.linksFormat)
+	response.withChecksum(render)
+	response.withRedaction(render)
+	response.withItemLinks(render)
 	return response
 }
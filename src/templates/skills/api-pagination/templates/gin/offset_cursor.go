@@ -0,0 +1,78 @@
+package pagination
+
+import "fmt"
+
+// AsCursorResponse presents an offset page as a cursor page, for clients that only
+// understand cursor responses while endpoints migrate from offset to cursor pagination
+// The next and previous cursors are synthesized from field's value on the page's last and
+// first items (read with extractor), encoded with the same codec the cursor paginators use,
+// so a client can follow NextCursor into CursorPaginateInt/CursorPaginateString on field.
+// The offset query must be ordered by field ascending for that hand-off to continue where the
+// page ended. Page numbers and totals are kept in the cursor response's offset-style metadata.
+//
+// Pass WithCursorCodec when the cursor endpoint uses a custom codec.
+//
+// Example usage:
+//
+//	result, err := pagination.OffsetPaginate(db.Order("id ASC"), &users, page, pageSize)
+//	// ...
+//	response, err := result.AsCursorResponse("id", func(u User) any { return u.ID })
+//	// ...
+//	c.JSON(200, response) // next_cursor continues with CursorPaginateInt(db, &users, cursor, pageSize, "id", true)
+func (p *OffsetPagination[T]) AsCursorResponse(
+	field string,
+	extractor func(T) any,
+	opts ...Option,
+) (*CursorPagination[T], error) {
+	if extractor == nil {
+		return nil, fmt.Errorf("no cursor extractor for field %q", field)
+	}
+	o := applyOptions(opts)
+	o.defaultPayloadCodec(field, true)
+	codec := o.cursorCodec()
+
+	currentPage := p.CurrentPage
+	totalPages := p.TotalPages
+	totalItems := p.TotalItems
+
+	result := &CursorPagination[T]{
+		Items:       nonNilItems(p.Items),
+		HasNext:     p.HasNext,
+		HasPrevious: p.HasPrevious,
+		PageSize:    p.PageSize,
+		CurrentPage: &currentPage,
+		TotalPages:  &totalPages,
+		TotalItems:  &totalItems,
+	}
+	if len(p.Items) == 0 {
+		return result, nil
+	}
+
+	result.FirstKey = extractor(p.Items[0])
+	result.LastKey = extractor(p.Items[len(p.Items)-1])
+
+	start, end, err := boundaryCursors(codec, len(p.Items), result.FirstKey, result.LastKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to synthesize %s cursor: %w", field, err)
+	}
+	result.StartCursor = start
+	result.EndCursor = end
+
+	if p.HasNext {
+		next, err := codec.Encode(result.LastKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to synthesize %s cursor: %w", field, err)
+		}
+		result.NextCursor = &next
+	}
+
+	if p.HasPrevious {
+		previous, err := codec.Encode(result.FirstKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to synthesize %s cursor: %w", field, err)
+		}
+		result.PreviousCursor = &previous
+	}
+
+	return result, nil
+}
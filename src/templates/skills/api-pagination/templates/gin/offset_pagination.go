@@ -1,22 +1,65 @@
 package pagination
 
 import (
+	"errors"
 	"fmt"
 	"math"
 
 	"gorm.io/gorm"
 )
 
+// ErrPageOutOfRange is returned with WithStrictPageRange for a page past the last page
+var ErrPageOutOfRange = errors.New("page out of range")
+
+// ErrResultTooLarge is returned under AllowAll when the query has more rows than the ceiling
+var ErrResultTooLarge = errors.New("result too large to return at once")
+
 // OffsetPagination represents offset-based pagination result
 // Best for: Small to medium datasets, user-facing pagination with page numbers
 type OffsetPagination[T any] struct {
-	Items       []T  `json:"items"`
-	CurrentPage int  `json:"current_page"`
-	PageSize    int  `json:"page_size"`
+	Items       []T   `json:"items"`
+	CurrentPage int   `json:"current_page"`
+	PageSize    int   `json:"page_size"`
 	TotalItems  int64 `json:"total_items"`
-	TotalPages  int  `json:"total_pages"`
-	HasNext     bool `json:"has_next"`
-	HasPrevious bool `json:"has_previous"`
+	TotalPages  int   `json:"total_pages"`
+	HasNext     bool  `json:"has_next"`
+	HasPrevious bool  `json:"has_previous"`
+
+	// TotalAtLeast is set when the count stopped at WithMaxReportedTotal's cap: there are at
+	// least this many items (and TotalItems/TotalPages describe only the first TotalAtLeast)
+	TotalAtLeast *int64 `json:"total_at_least,omitempty"`
+
+	// Indexing is the page numbering CurrentPage uses (see WithPageIndexing)
+	Indexing PageIndexing `json:"-"`
+
+	// MetadataOnly is set for a page size 0 request under AllowZeroPageSize: no rows were
+	// fetched, and PageSize is the page size the totals were computed for
+	MetadataOnly bool `json:"metadata_only,omitempty"`
+
+	// NextCursor and DeepPagination are set past WithDeepPaginationHint's depth: the cursor
+	// continues after this page with cursor pagination, which stays fast at any depth
+	NextCursor     *string `json:"next_cursor,omitempty"`
+	DeepPagination bool    `json:"deep_pagination,omitempty"`
+
+	// NextPageToken and PreviousPageToken are set under WithPageToken: opaque tokens for the
+	// neighbouring pages, valid only with the filters and sort this page was served for
+	NextPageToken     *string `json:"next_page_token,omitempty"`
+	PreviousPageToken *string `json:"previous_page_token,omitempty"`
+
+	// CountToken is the signed total to send back with the next page (see WithCountToken);
+	// TotalFromToken is set when this page reused a token's total instead of counting
+	CountToken     string `json:"count_token,omitempty"`
+	TotalFromToken bool   `json:"total_from_token,omitempty"`
+
+	// CountMode is CountSkipped when WithCountBreaker's breaker shed the count or
+	// WithCountFallback found no total: TotalItems and TotalPages are 0 and unknown, and HasNext
+	// comes from an extra row (empty otherwise)
+	CountMode CountMode `json:"count_mode,omitempty"`
+
+	// CountSource is where the total came from under WithCountFallback or WithCounterSource,
+	// and Warnings explains a total that is not an exact count (both empty otherwise)
+	CountSource CountSource `json:"count_source,omitempty"`
+	Warnings    []string    `json:"warnings,omitempty"`
 }
 
 // OffsetPaginate performs offset-based pagination on a GORM query
@@ -43,47 +86,126 @@ func OffsetPaginate[T any](
 	dest *[]T,
 	page int,
 	pageSize int,
+	opts ...Option,
 ) (*OffsetPagination[T], error) {
-	// Validate and constrain parameters
+	o := applyOptions(opts)
+	db = o.bindContext(db)
+
+	// Serve the page a page token points to (WithPageToken)
+	page, pageSize, err := o.resolvePageToken(page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// Serve repeated pages from the page cache
+	if o.usePageCache() {
+		result, cached, err := cachedOffsetPage(db, dest, o, func(filling Option) (*OffsetPagination[T], error) {
+			return OffsetPaginate(db, dest, page, pageSize, append(opts[:len(opts):len(opts)], filling)...)
+		}, "offset", page, pageSize, queryMaxPageSize(db))
+		if cached {
+			return result, err
+		}
+	}
+
+	// Read from the replica, falling back to the primary
+	if o.usesReplica() {
+		return readReplicated(queryContext(db), o.replica, func(route func(*gorm.DB) *gorm.DB, routed Option) (*OffsetPagination[T], error) {
+			return OffsetPaginate(route(db), dest, page, pageSize, append(opts[:len(opts):len(opts)], routed)...)
+		})
+	}
+
+	// Return every row when the route allows it
+	if pageSize == PageSizeAll && o.allRowsCeiling > 0 {
+		return allRowsPage(db, dest, o)
+	}
+
+	// Validate and constrain parameters (1-based from here on, whatever WithPageIndexing says)
+	page = o.pageIndexing.internalPage(page)
 	if page < 1 {
 		page = 1
 	}
+	metadataOnly := pageSize == 0 && o.metadataPageSize > 0
+	if metadataOnly {
+		pageSize = o.metadataPageSize
+	}
 	if pageSize < 1 {
-		pageSize = {{defaultPageSize}}
+		pageSize = o.config.DefaultPageSize
 	}
-	if pageSize > {{maxPageSize}} {
-		pageSize = {{maxPageSize}}
+	if limit := queryMaxPageSize(db); pageSize > limit {
+		pageSize = limit
+	}
+
+	if err := checkDestType[T](db); err != nil {
+		return nil, err
 	}
 
 	// Get total count
-	var totalItems int64
-	if err := db.Model(dest).Count(&totalItems).Error; err != nil {
-		return nil, fmt.Errorf("failed to count items: %w", err)
+	total, err := resolveTotal(db.Model(dest), o)
+	if err != nil {
+		return nil, err
+	}
+	totalItems, totalAtLeast := total.items, total.atLeast
+
+	if !total.inexact() {
+		if err := checkPageRange(page, pageSize, totalItems, totalAtLeast, o); err != nil {
+			return nil, err
+		}
+	}
+
+	if metadataOnly {
+		result := metadataPage(dest, page, pageSize, totalItems, totalAtLeast)
+		return withPageIndexing(withTotalSource(result, total), o.pageIndexing), nil
 	}
 
 	// Calculate offset
 	offset := (page - 1) * pageSize
 
 	// Get items for current page
+	// A capped, skipped, or fallback count cannot tell whether rows follow this page; fetch
+	// one extra row
+	limit := pageSize
+	if totalAtLeast != nil || total.inexact() {
+		limit++
+	}
+
 	var items []T
-	if err := db.Offset(offset).Limit(pageSize).Find(&items).Error; err != nil {
+	err = o.fetchLimiter.do(queryContext(db), func() error {
+		return o.onFetchDB(db).Offset(offset).Limit(sqlLimit(db, limit)).Find(&items).Error
+	})
+	if err != nil {
 		return nil, fmt.Errorf("failed to fetch items: %w", err)
 	}
 
+	hasMore := len(items) > pageSize
+	if hasMore {
+		items = items[:pageSize]
+	}
+
+	items = nonNilItems(items)
 	*dest = items
 
 	// Calculate total pages
 	totalPages := int(math.Ceil(float64(totalItems) / float64(pageSize)))
 
-	return &OffsetPagination[T]{
-		Items:       items,
-		CurrentPage: page,
-		PageSize:    pageSize,
-		TotalItems:  totalItems,
-		TotalPages:  totalPages,
-		HasNext:     page < totalPages,
-		HasPrevious: page > 1,
-	}, nil
+	result := withPageIndexing(withTotalSource(&OffsetPagination[T]{
+		Items:        items,
+		CurrentPage:  page,
+		PageSize:     pageSize,
+		TotalItems:   totalItems,
+		TotalPages:   totalPages,
+		HasNext:      (page < totalPages && !total.inexact()) || hasMore,
+		HasPrevious:  page > 1,
+		TotalAtLeast: totalAtLeast,
+	}, total), o.pageIndexing)
+
+	if err := applyDeepPaginationHint(db, result, page, o); err != nil {
+		return nil, err
+	}
+	if err := applyPageTokens(result, o); err != nil {
+		return nil, err
+	}
+
+	return result, nil
 }
 
 // OffsetPaginateWithCount performs offset pagination with a separate count query
@@ -125,45 +247,251 @@ func OffsetPaginateWithCount[T any](
 	dest *[]T,
 	page int,
 	pageSize int,
+	opts ...Option,
 ) (*OffsetPagination[T], error) {
-	// Validate and constrain parameters
+	o := applyOptions(opts)
+	db = o.bindContext(db)
+	countDB = o.bindContext(countDB)
+
+	// Serve the page a page token points to (WithPageToken)
+	page, pageSize, err := o.resolvePageToken(page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// Serve repeated pages from the page cache
+	if o.usePageCache() {
+		countSQL := countDB.ToSQL(func(tx *gorm.DB) *gorm.DB {
+			var total int64
+			return tx.Count(&total)
+		})
+		result, cached, err := cachedOffsetPage(db, dest, o, func(filling Option) (*OffsetPagination[T], error) {
+			return OffsetPaginateWithCount(db, countDB, dest, page, pageSize, append(opts[:len(opts):len(opts)], filling)...)
+		}, "offset-with-count", countSQL, page, pageSize, queryMaxPageSize(db))
+		if cached {
+			return result, err
+		}
+	}
+
+	// Read from the replica, falling back to the primary
+	if o.usesReplica() {
+		return readReplicated(queryContext(db), o.replica, func(route func(*gorm.DB) *gorm.DB, routed Option) (*OffsetPagination[T], error) {
+			return OffsetPaginateWithCount(route(db), route(countDB), dest, page, pageSize, append(opts[:len(opts):len(opts)], routed)...)
+		})
+	}
+
+	// Return every row when the route allows it
+	if pageSize == PageSizeAll && o.allRowsCeiling > 0 {
+		return allRowsPage(db, dest, o)
+	}
+
+	// Validate and constrain parameters (1-based from here on, whatever WithPageIndexing says)
+	page = o.pageIndexing.internalPage(page)
 	if page < 1 {
 		page = 1
 	}
+	metadataOnly := pageSize == 0 && o.metadataPageSize > 0
+	if metadataOnly {
+		pageSize = o.metadataPageSize
+	}
 	if pageSize < 1 {
-		pageSize = {{defaultPageSize}}
+		pageSize = o.config.DefaultPageSize
 	}
-	if pageSize > {{maxPageSize}} {
-		pageSize = {{maxPageSize}}
+	if limit := queryMaxPageSize(db); pageSize > limit {
+		pageSize = limit
 	}
 
 	// Get total count using optimized query
-	var totalItems int64
-	if err := countDB.Count(&totalItems).Error; err != nil {
-		return nil, fmt.Errorf("failed to count items: %w", err)
+	total, err := resolveTotal(countDB, o)
+	if err != nil {
+		return nil, err
+	}
+	totalItems, totalAtLeast := total.items, total.atLeast
+
+	if !total.inexact() {
+		if err := checkPageRange(page, pageSize, totalItems, totalAtLeast, o); err != nil {
+			return nil, err
+		}
+	}
+
+	if metadataOnly {
+		result := metadataPage(dest, page, pageSize, totalItems, totalAtLeast)
+		return withPageIndexing(withTotalSource(result, total), o.pageIndexing), nil
 	}
 
 	// Calculate offset
 	offset := (page - 1) * pageSize
 
 	// Get items for current page
+	// A capped, skipped, or fallback count cannot tell whether rows follow this page; fetch
+	// one extra row
+	limit := pageSize
+	if totalAtLeast != nil || total.inexact() {
+		limit++
+	}
+
 	var items []T
-	if err := db.Offset(offset).Limit(pageSize).Find(&items).Error; err != nil {
+	err = o.fetchLimiter.do(queryContext(db), func() error {
+		return o.onFetchDB(db).Offset(offset).Limit(sqlLimit(db, limit)).Find(&items).Error
+	})
+	if err != nil {
 		return nil, fmt.Errorf("failed to fetch items: %w", err)
 	}
 
+	hasMore := len(items) > pageSize
+	if hasMore {
+		items = items[:pageSize]
+	}
+
+	items = nonNilItems(items)
 	*dest = items
 
 	// Calculate total pages
 	totalPages := int(math.Ceil(float64(totalItems) / float64(pageSize)))
 
+	result := withPageIndexing(withTotalSource(&OffsetPagination[T]{
+		Items:        items,
+		CurrentPage:  page,
+		PageSize:     pageSize,
+		TotalItems:   totalItems,
+		TotalPages:   totalPages,
+		HasNext:      (page < totalPages && !total.inexact()) || hasMore,
+		HasPrevious:  page > 1,
+		TotalAtLeast: totalAtLeast,
+	}, total), o.pageIndexing)
+
+	if err := applyDeepPaginationHint(db, result, page, o); err != nil {
+		return nil, err
+	}
+	if err := applyPageTokens(result, o); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// countTotal counts query's rows, stopping one past WithMaxReportedTotal's cap when set
+// A count past the cap reports the cap as the total plus a non-nil atLeast. A grouped query
+// counts its groups that pass HAVING.
+func countTotal(query *gorm.DB, o options) (total int64, atLeast *int64, err error) {
+	query = o.onCountDB(query)
+	if o.maxReportedTotal <= 0 {
+		err := o.countLimiter.do(queryContext(query), func() error {
+			if isGrouped(query) {
+				return countGroups(query, &total)
+			}
+			return query.Count(&total).Error
+		})
+		if err != nil {
+			return 0, nil, fmt.Errorf("failed to count items: %w", err)
+		}
+		return total, nil, nil
+	}
+
+	var counted int64
+	err = o.countLimiter.do(queryContext(query), func() (err error) {
+		counted, err = cappedCount(query, o.maxReportedTotal+1)
+		return err
+	})
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to count items: %w", err)
+	}
+	total, atLeast = reportedTotal(counted, int64(o.maxReportedTotal))
+	return total, atLeast, nil
+}
+
+// metadataPage builds the result of a metadata-only request from its count
+func metadataPage[T any](dest *[]T, page, pageSize int, totalItems int64, totalAtLeast *int64) *OffsetPagination[T] {
+	*dest = []T{}
+	totalPages := int(math.Ceil(float64(totalItems) / float64(pageSize)))
+
 	return &OffsetPagination[T]{
-		Items:       items,
+		Items:       []T{},
 		CurrentPage: page,
 		PageSize:    pageSize,
 		TotalItems:  totalItems,
 		TotalPages:  totalPages,
-		HasNext:     page < totalPages,
-		HasPrevious: page > 1,
-	}, nil
+		// A capped count means rows exist past the last counted page
+		HasNext:      page < totalPages || (totalAtLeast != nil && page == totalPages),
+		HasPrevious:  page > 1,
+		TotalAtLeast: totalAtLeast,
+		MetadataOnly: true,
+	}
+}
+
+// applyDeepPaginationHint adds WithDeepPaginationHint's cursor to an offset page past the
+// hint depth; page is the 1-based page number
+// The cursor is read from the page's last row, so the hint costs no query.
+func applyDeepPaginationHint[T any](db *gorm.DB, result *OffsetPagination[T], page int, o options) error {
+	if o.deepPageDepth <= 0 || page <= o.deepPageDepth {
+		return nil
+	}
+	result.DeepPagination = true
+
+	if !result.HasNext || len(result.Items) == 0 {
+		return nil
+	}
+
+	_, lastKey, err := boundaryKeys(db, result.Items, o.deepPageCursorField)
+	if err != nil {
+		return err
+	}
+	o.defaultPayloadCodec(o.deepPageCursorField, true)
+	next, err := o.cursorCodec().Encode(lastKey)
+	if err != nil {
+		return fmt.Errorf("failed to encode deep pagination cursor: %w", err)
+	}
+	result.NextCursor = &next
+	return nil
+}
+
+// allRowsPage fetches every row of query as a single page for AllowAll
+func allRowsPage[T any](db *gorm.DB, dest *[]T, o options) (*OffsetPagination[T], error) {
+	if err := checkDestType[T](db); err != nil {
+		return nil, err
+	}
+
+	// One row past the ceiling tells a full result from one that is too large
+	var items []T
+	err := o.fetchLimiter.do(queryContext(db), func() error {
+		return o.onFetchDB(db).Limit(o.allRowsCeiling + 1).Find(&items).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch items: %w", err)
+	}
+	if len(items) > o.allRowsCeiling {
+		return nil, fmt.Errorf("%w: more than %d rows", ErrResultTooLarge, o.allRowsCeiling)
+	}
+
+	items = nonNilItems(items)
+	*dest = items
+
+	return withPageIndexing(&OffsetPagination[T]{
+		Items:       items,
+		CurrentPage: 1,
+		PageSize:    o.allRowsCeiling,
+		TotalItems:  int64(len(items)),
+		TotalPages:  1,
+	}, o.pageIndexing), nil
+}
+
+// checkPageRange enforces WithStrictPageRange before the page query runs
+func checkPageRange(page, pageSize int, totalItems int64, totalAtLeast *int64, o options) error {
+	if !o.strictPageRange || totalAtLeast != nil || page == 1 {
+		return nil
+	}
+
+	totalPages := int(math.Ceil(float64(totalItems) / float64(pageSize)))
+	if page > totalPages {
+		return fmt.Errorf("%w: page %d of %d", ErrPageOutOfRange, o.pageIndexing.clientPage(page), totalPages)
+	}
+	return nil
+}
+
+// reportedTotal turns a count capped at limit+1 into the reported total
+func reportedTotal(counted int64, limit int64) (int64, *int64) {
+	if counted <= limit {
+		return counted, nil
+	}
+	return limit, &limit
 }
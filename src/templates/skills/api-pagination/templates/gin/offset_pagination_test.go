@@ -0,0 +1,329 @@
+package pagination
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestReportedTotalAtLeast(t *testing.T) {
+	cases := []struct {
+		counted   int64
+		wantTotal int64
+		wantPlus  bool
+	}{
+		{counted: 0, wantTotal: 0},
+		{counted: 999, wantTotal: 999},
+		{counted: 1000, wantTotal: 1000}, // exactly the cap is still exact
+		{counted: 1001, wantTotal: 1000, wantPlus: true},
+	}
+
+	for _, tc := range cases {
+		total, atLeast := reportedTotal(tc.counted, 1000)
+		if total != tc.wantTotal || (atLeast != nil) != tc.wantPlus {
+			t.Errorf("counted %d: got total %d, at least %v", tc.counted, total, atLeast)
+		}
+		if atLeast != nil && *atLeast != 1000 {
+			t.Errorf("counted %d: TotalAtLeast = %d, want the cap", tc.counted, *atLeast)
+		}
+	}
+}
+
+func TestTotalAtLeastJSON(t *testing.T) {
+	total, atLeast := reportedTotal(1001, 1000)
+	capped, err := json.Marshal(OffsetPagination[int]{TotalItems: total, TotalAtLeast: atLeast})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(capped), `"total_at_least":1000`) {
+		t.Errorf("capped result should render as 1000+: %s", capped)
+	}
+
+	total, atLeast = reportedTotal(42, 1000)
+	exact, err := json.Marshal(OffsetPagination[int]{TotalItems: total, TotalAtLeast: atLeast})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(exact), "total_at_least") {
+		t.Errorf("exact result should omit total_at_least: %s", exact)
+	}
+}
+
+func TestCheckPageRange(t *testing.T) {
+	strict := applyOptions([]Option{WithStrictPageRange()})
+	capped := int64(1000)
+
+	cases := []struct {
+		page    int
+		total   int64
+		atLeast *int64
+		o       options
+		wantErr bool
+	}{
+		{page: 3, total: 60, o: strict},
+		{page: 4, total: 60, o: strict, wantErr: true},
+		{page: 1, total: 0, o: strict},                       // an empty set still has page 1
+		{page: 40, total: 60, o: options{}},                  // lenient by default
+		{page: 99, total: 1000, atLeast: &capped, o: strict}, // last page unknown
+	}
+
+	for _, tc := range cases {
+		err := checkPageRange(tc.page, 20, tc.total, tc.atLeast, tc.o)
+		if (err != nil) != tc.wantErr || (err != nil && !errors.Is(err, ErrPageOutOfRange)) {
+			t.Errorf("page %d of %d items: err = %v", tc.page, tc.total, err)
+		}
+	}
+}
+
+func TestAsCursorResponseRoundTrip(t *testing.T) {
+	type user struct {
+		ID    int64
+		Email string
+	}
+	first := user{ID: 21, Email: "a@example.com"}
+	last := user{ID: 22, Email: "b@example.com"}
+	page := &OffsetPagination[user]{
+		Items:       []user{first, last},
+		CurrentPage: 2,
+		PageSize:    2,
+		TotalItems:  10,
+		TotalPages:  5,
+		HasNext:     true,
+		HasPrevious: true,
+	}
+
+	// The default codec, decoded the way CursorPaginateInt decodes its cursor
+	result, err := page.AsCursorResponse("id", func(u user) any { return u.ID })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.NextCursor == nil || result.PreviousCursor == nil {
+		t.Fatalf("cursors = %v, %v", result.NextCursor, result.PreviousCursor)
+	}
+	decoded, err := DecodeCursor(*result.NextCursor, "id", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next, err := cursorInt(decoded); err != nil || next != 22 {
+		t.Errorf("next cursor = %v (%v), want 22", next, err)
+	}
+	decoded, _ = DecodeCursor(*result.PreviousCursor, "id", true)
+	if previous, err := cursorInt(decoded); err != nil || previous != 21 {
+		t.Errorf("previous cursor = %v (%v), want 21", previous, err)
+	}
+	if *result.CurrentPage != 2 || *result.TotalItems != 10 || result.LastKey != int64(22) {
+		t.Errorf("metadata = page %d, total %d, last key %v", *result.CurrentPage, *result.TotalItems, result.LastKey)
+	}
+
+	// A custom codec, decoded the way CursorPaginateString decodes its cursor
+	codec := WithCursorCodec(JSONCursorCodec{})
+	result, err = page.AsCursorResponse("email", func(u user) any { return u.Email }, codec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := JSONCursorCodec{}.Decode(*result.NextCursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next, err := cursorString(value); err != nil || next != "b@example.com" {
+		t.Errorf("next cursor = %q (%v)", next, err)
+	}
+
+	// The last page has no next cursor, and an empty page has none at all
+	page.HasNext = false
+	if result, _ := page.AsCursorResponse("id", func(u user) any { return u.ID }); result.NextCursor != nil {
+		t.Errorf("last page should have no next cursor")
+	}
+	empty := &OffsetPagination[user]{CurrentPage: 1, PageSize: 2}
+	result, err = empty.AsCursorResponse("id", func(u user) any { return u.ID })
+	if err != nil || result.NextCursor != nil || result.PreviousCursor != nil || result.FirstKey != nil {
+		t.Errorf("empty page = %+v (%v)", result, err)
+	}
+}
+
+func TestMetadataOnlyPage(t *testing.T) {
+	params := ParamsFromQuery(map[string][]string{"page": {"2"}, "page_size": {"0"}})
+	if !params.MetadataOnly || params.PageSize != {{defaultPageSize}} || params.RequestedPageSize() != 0 {
+		t.Fatalf("params = %+v", params)
+	}
+	if ParamsFromQuery(map[string][]string{"page_size": {"-5"}}).MetadataOnly {
+		t.Error("negative page sizes should reset to the default, not request metadata")
+	}
+	if got := params.CanonicalQuery(); got != "page=2&page_size=0" {
+		t.Errorf("CanonicalQuery = %q", got)
+	}
+
+	var dest []int
+	result := metadataPage(&dest, 2, 25, 110, nil)
+	if dest == nil || len(result.Items) != 0 || result.Items == nil {
+		t.Errorf("metadata-only pages should have empty, non-nil items: %v %v", dest, result.Items)
+	}
+	if result.TotalPages != 5 || result.PageSize != 25 || !result.HasNext || !result.HasPrevious {
+		t.Errorf("result = %+v", result)
+	}
+
+	response := result.ToResponse("/orders")
+	links := response.Links
+	if links.First == nil || *links.First != "/orders?page=1&page_size=25" ||
+		links.Last == nil || *links.Last != "/orders?page=5&page_size=25" {
+		t.Errorf("first/last links = %v, %v", links.First, links.Last)
+	}
+	if links.Next != nil || links.Previous != nil {
+		t.Errorf("metadata-only responses should link only first and last: %+v", links)
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"data":[]`, `"total_items":110`, `"metadata_only":true`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("response missing %s: %s", want, data)
+		}
+	}
+}
+
+func TestEmptyPagesMarshalAsArrays(t *testing.T) {
+	// nil is what Find leaves behind on an empty page
+	offset := &OffsetPagination[int]{PageSize: 20}
+	cursor := &CursorPagination[int]{PageSize: 20}
+
+	asCursor, err := offset.AsCursorResponse("id", func(id int) any { return id })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]any{
+		"offset.ToResponse":   offset.ToResponse("/users"),
+		"cursor.ToResponse":   cursor.ToResponse(""),
+		"AsCursorResponse":    asCursor,
+		"nonNilItems applied": &OffsetPagination[int]{Items: nonNilItems[int](nil)},
+	}
+	for name, page := range cases {
+		raw, err := json.Marshal(page)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(raw), "null") || !strings.Contains(string(raw), "[]") {
+			t.Errorf("%s: %s, want an empty array", name, raw)
+		}
+	}
+}
+
+type country struct {
+	Code string
+}
+
+func TestAllRowsPage(t *testing.T) {
+	for _, query := range []string{"all", "-1"} {
+		params := ParamsFromQuery(map[string][]string{"page_size": {query}})
+		if !params.All || params.PageSize != {{defaultPageSize}} || params.RequestedPageSize() != PageSizeAll {
+			t.Errorf("page_size=%s: params = %+v", query, params)
+		}
+	}
+	if got := ParamsFromQuery(map[string][]string{"limit": {"all"}}).CanonicalQuery(); got != "page=1&page_size=-1" {
+		t.Errorf("CanonicalQuery = %q", got)
+	}
+
+	db, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rows []country
+	for _, code := range []string{"DE", "FR", "NL", "US"} {
+		rows = append(rows, country{Code: code})
+	}
+	err = db.Callback().Query().Register("test:countries", func(tx *gorm.DB) {
+		switch dest := tx.Statement.Dest.(type) {
+		case *[]country:
+			*dest = rows
+		case *int64:
+			*dest = int64(len(rows))
+			tx.RowsAffected = 1
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var countries []country
+	result, err := OffsetPaginate(db.Model(&country{}), &countries, 1, PageSizeAll, AllowAll(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Items) != 4 || result.TotalItems != 4 || result.TotalPages != 1 || result.HasNext || result.HasPrevious {
+		t.Errorf("all rows = %+v", result)
+	}
+
+	// The fetch reads one row past the ceiling, which is how a too-large result shows up
+	if _, err := OffsetPaginate(db.Model(&country{}), &countries, 1, PageSizeAll, AllowAll(3)); !errors.Is(err, ErrResultTooLarge) {
+		t.Errorf("past the ceiling: err = %v, want ErrResultTooLarge", err)
+	}
+
+	// Routes without AllowAll page with the default size as before
+	result, err = OffsetPaginate(db.Model(&country{}), &countries, 1, PageSizeAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.PageSize != {{defaultPageSize}} {
+		t.Errorf("without AllowAll: page size = %d, want the default", result.PageSize)
+	}
+}
+
+type event struct {
+	ID int64
+}
+
+func TestDeepPaginationHint(t *testing.T) {
+	db, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Every page holds events 21 and 22 out of 100; only the page number matters here
+	first, second := event{ID: 21}, event{ID: 22}
+	err = db.Callback().Query().Register("test:events", func(tx *gorm.DB) {
+		switch dest := tx.Statement.Dest.(type) {
+		case *[]event:
+			*dest = []event{first, second}
+		case *int64:
+			*dest = 100
+			tx.RowsAffected = 1
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paginate := func(page int) *OffsetPagination[event] {
+		t.Helper()
+		var events []event
+		result, err := OffsetPaginate(db.Model(&event{}), &events, page, 2, WithDeepPaginationHint(10, "id"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	if at := paginate(10); at.DeepPagination || at.NextCursor != nil {
+		t.Errorf("page 10 (the depth) got a hint: %+v", at)
+	}
+
+	past := paginate(11)
+	if !past.DeepPagination || past.NextCursor == nil {
+		t.Fatalf("page 11 (past the depth) got no hint: %+v", past)
+	}
+	// The cursor continues after the page's last row
+	if value, err := DecodeCursor(*past.NextCursor, "id", true); err != nil || value != "22" {
+		t.Errorf("next cursor = %v, %v, want 22", value, err)
+	}
+	if meta := past.ToResponse("").Pagination; !meta.DeepPagination || meta.NextCursor == nil {
+		t.Errorf("response meta = %+v", meta)
+	}
+
+	if last := paginate(50); !last.DeepPagination || last.NextCursor != nil {
+		t.Errorf("last page: deep %t, next cursor %v", last.DeepPagination, last.NextCursor)
+	}
+}
@@ -0,0 +1,147 @@
+package pagination
+
+// OpenAPIParameter is an OpenAPI 3 query parameter object
+type OpenAPIParameter struct {
+	Name        string        `json:"name"`
+	In          string        `json:"in"`
+	Description string        `json:"description,omitempty"`
+	Required    bool          `json:"required"`
+	Schema      OpenAPISchema `json:"schema"`
+}
+
+// OpenAPISchema is the subset of an OpenAPI 3 schema object used by pagination
+type OpenAPISchema struct {
+	Ref         string                   `json:"$ref,omitempty"`
+	Type        string                   `json:"type,omitempty"`
+	Format      string                   `json:"format,omitempty"`
+	Description string                   `json:"description,omitempty"`
+	Minimum     *int                     `json:"minimum,omitempty"`
+	Maximum     *int                     `json:"maximum,omitempty"`
+	Default     any                      `json:"default,omitempty"`
+	Nullable    bool                     `json:"nullable,omitempty"`
+	Items       *OpenAPISchema           `json:"items,omitempty"`
+	Properties  map[string]OpenAPISchema `json:"properties,omitempty"`
+	Required    []string                 `json:"required,omitempty"`
+}
+
+// OpenAPIParameters documents the query parameters read by ParsePaginationParams
+// The page size schema carries the same default and maximum the parser clamps to, so the
+// docs never promise larger pages than the API serves. cursor selects cursor parameters
+// (cursor, page_size) instead of offset ones (page, page_size).
+//
+// Example usage:
+//
+//	spec.Paths["/users"].Get.Parameters = append(
+//	    spec.Paths["/users"].Get.Parameters,
+//	    pagination.OpenAPIParameters(true)...,
+//	)
+func OpenAPIParameters(cursor bool) []OpenAPIParameter {
+	one, maxPageSize := 1, {{maxPageSize}}
+
+	pageSize := OpenAPIParameter{
+		Name:        "page_size",
+		In:          "query",
+		Description: "Items per page (alias: limit); larger values are clamped to the maximum",
+		Schema: OpenAPISchema{
+			Type:    "integer",
+			Minimum: &one,
+			Maximum: &maxPageSize,
+			Default: {{defaultPageSize}},
+		},
+	}
+
+	if cursor {
+		cursorParam := OpenAPIParameter{
+			Name:        "cursor",
+			In:          "query",
+			Description: "Opaque cursor from next_cursor of the previous page; omit for the first page",
+			Schema:      OpenAPISchema{Type: "string"},
+		}
+		return []OpenAPIParameter{cursorParam, pageSize}
+	}
+
+	page := OpenAPIParameter{
+		Name:        "page",
+		In:          "query",
+		Description: "1-based page number",
+		Schema:      OpenAPISchema{Type: "integer", Minimum: &one, Default: 1},
+	}
+	return []OpenAPIParameter{page, pageSize}
+}
+
+// OpenAPIPageSchema describes the JSON of OffsetPagination (or CursorPagination when cursor
+// is true) with items of the given schema
+//
+// Example usage:
+//
+//	schema := pagination.OpenAPIPageSchema(true, pagination.OpenAPISchema{Ref: "#/components/schemas/User"})
+func OpenAPIPageSchema(cursor bool, items OpenAPISchema) OpenAPISchema {
+	integer := OpenAPISchema{Type: "integer"}
+	boolean := OpenAPISchema{Type: "boolean"}
+
+	properties := map[string]OpenAPISchema{
+		"items":        {Type: "array", Items: &items},
+		"page_size":    integer,
+		"has_next":     boolean,
+		"has_previous": boolean,
+	}
+	required := []string{"items", "page_size", "has_next", "has_previous"}
+
+	if cursor {
+		properties["next_cursor"] = OpenAPISchema{Type: "string", Description: "Cursor of the next page"}
+		properties["previous_cursor"] = OpenAPISchema{Type: "string"}
+		properties["start_cursor"] = OpenAPISchema{Type: "string", Description: "Cursor of the first item on this page"}
+		properties["end_cursor"] = OpenAPISchema{Type: "string", Description: "Cursor of the last item on this page"}
+		properties["new_items_available"] = OpenAPISchema{
+			Type:        "boolean",
+			Description: "Set when rows were added before the first page of this pagination session",
+		}
+		properties["restarted"] = OpenAPISchema{
+			Type:        "boolean",
+			Description: "Set when the cursor no longer matched the request and the first page was served instead",
+		}
+	} else {
+		properties["current_page"] = integer
+		properties["total_items"] = OpenAPISchema{Type: "integer", Format: "int64"}
+		properties["total_pages"] = integer
+		properties["total_at_least"] = OpenAPISchema{
+			Type:        "integer",
+			Format:      "int64",
+			Description: "Set when the count was capped: at least this many items exist",
+		}
+		properties["count_token"] = OpenAPISchema{
+			Type:        "string",
+			Description: "Send back as count_token with the next page to reuse this total instead of recounting",
+		}
+		properties["total_from_token"] = OpenAPISchema{
+			Type:        "boolean",
+			Description: "Set when the total was reused from the request's count_token",
+		}
+		properties["count_mode"] = OpenAPISchema{
+			Type:        "string",
+			Description: "\"skipped\" when the count was shed under load; total_items and total_pages are then omitted",
+		}
+		properties["count_source"] = OpenAPISchema{
+			Type:        "string",
+			Description: "Where total_items came from when the count is bounded by a timeout: exact, cache, estimate, or none",
+		}
+		properties["warnings"] = OpenAPISchema{
+			Type:        "array",
+			Items:       &OpenAPISchema{Type: "string"},
+			Description: "Why the totals are not an exact count",
+		}
+		properties["deep_pagination"] = OpenAPISchema{
+			Type:        "boolean",
+			Description: "Set past the deep pagination depth; continue with next_cursor instead of page",
+		}
+		properties["next_cursor"] = OpenAPISchema{Type: "string", Description: "Cursor continuing after this page (deep pages only)"}
+		properties["next_page_token"] = OpenAPISchema{
+			Type:        "string",
+			Description: "Send as page_token for the next page; valid only with the same filters and sort",
+		}
+		properties["previous_page_token"] = OpenAPISchema{Type: "string", Description: "Send as page_token for the previous page"}
+		required = append(required, "current_page")
+	}
+
+	return OpenAPISchema{Type: "object", Properties: properties, Required: required}
+}
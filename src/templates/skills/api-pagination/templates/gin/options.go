@@ -0,0 +1,788 @@
+package pagination
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Option configures optional paginator behavior
+// Options are passed as trailing arguments so existing call sites keep working
+type Option func(*options)
+
+// options holds the resolved optional settings for a single paginate call
+type options struct {
+	// approxRemainingLimit caps the rows counted for ApproxRemaining (0 = disabled)
+	approxRemainingLimit int
+
+	// hybridThreshold enables offset-style metadata on cursor results at or below this total (0 = disabled)
+	hybridThreshold int
+
+	// codec encodes and decodes cursors (nil = DefaultCursorCodec, or PayloadCursorCodec for
+	// the single-field cursor paginators)
+	codec CursorCodec
+
+	// maxReportedTotal caps the count behind offset totals (0 = exact count)
+	maxReportedTotal int
+
+	// strictPageRange fails offset pages past the last page instead of returning them empty
+	strictPageRange bool
+
+	// pageIndexing is the numbering of the offset paginators' page argument and CurrentPage
+	pageIndexing PageIndexing
+
+	// driftDetection anchors cursor sessions at their first row and reports new rows before it
+	driftDetection bool
+
+	// inclusiveCursor makes the cursor paginators start at the cursor's row instead of after it
+	inclusiveCursor bool
+
+	// rowIDTieBreaker orders the cursor paginators by SQLite's rowid after the cursor field
+	rowIDTieBreaker bool
+
+	// compactCursor shortens string cursor values past this many bytes (0 = full values)
+	compactCursor int
+
+	// joinedCursorFields allows table-qualified cursor fields, mapped to the column of the page
+	// type holding each (nil = qualified fields used as given)
+	joinedCursorFields map[string]string
+
+	// noTieBreaker stops the cursor paginators ordering by the primary key after a non-unique
+	// cursor field
+	noTieBreaker bool
+
+	// shardTotals makes ShardedCursorPaginate sum per-shard counts into TotalItems
+	shardTotals bool
+
+	// deepPageDepth and deepPageCursorField add a cursor to offset pages past that depth
+	deepPageDepth       int
+	deepPageCursorField string
+
+	// allRowsCeiling makes offset page size PageSizeAll return every row, up to this many
+	allRowsCeiling int
+
+	// metadataPageSize makes offset page size 0 a count-only request whose totals are computed
+	// for pages of this size (0 = page size 0 resets to the default like negatives)
+	metadataPageSize int
+
+	// countBreaker skips offset counts while it is open (nil = always count)
+	countBreaker *CountBreaker
+
+	// countFallback bounds offset counts and reports a fallback total on timeout (nil = unbounded)
+	countFallback *CountFallbackPolicy
+
+	// counterLookup reads the offset total from counter counterKey instead of counting
+	// (nil = count)
+	counterLookup CounterLookup
+	counterKey    string
+
+	// countLimiter and fetchLimiter bound concurrent count and page queries (nil = unlimited)
+	countLimiter *QueryLimiter
+	fetchLimiter *QueryLimiter
+
+	// replica serves count and page queries, falling back to the primary (nil = primary only)
+	replica *ReadReplica
+
+	// replicaRouted marks a call already routed to the replica or the primary
+	replicaRouted bool
+
+	// countDB and fetchDB supply the connections of the count and page queries (nil = the query's)
+	countDB *gorm.DB
+	fetchDB *gorm.DB
+
+	// snapshots pins pagination sessions to a consistent snapshot (nil = disabled)
+	snapshots Snapshotter
+
+	// pinned marks a call already running inside its snapshot
+	pinned bool
+
+	// pageLimit caps the pages of a cursor session (nil = unlimited); pageLimited marks a call
+	// whose page was already counted
+	pageLimit   *SessionPageLimit
+	pageLimited bool
+
+	// pageCache serves repeated pages for pageCacheTTL (nil = disabled)
+	pageCache    PageCache
+	pageCacheTTL time.Duration
+
+	// pageCacheFilling marks a call already behind the page cache, filling it on a miss
+	pageCacheFilling bool
+
+	// metrics receives instrumentation events (nil = none)
+	metrics Metrics
+
+	// ctx is bound to every query the paginator runs (nil = the query's own context)
+	ctx context.Context
+
+	// countTokens verifies countToken and issues new tokens for offset totals (nil = disabled)
+	countTokens *CountTokens
+	countToken  string
+
+	// pageTokens issues opaque page tokens bound to pageTokenFingerprint and serves pageToken
+	pageTokens           bool
+	pageToken            string
+	pageTokenFingerprint string
+
+	// byteBudget cuts cursor pages at this many bytes of JSON, keeping at least
+	// byteBudgetMinRows rows (0 = row count only)
+	byteBudget        int
+	byteBudgetMinRows int
+
+	// peek returns the cursor paginators' extra row as NextPreview instead of discarding it
+	peek bool
+
+	// backward makes the cursor paginators fetch the page before the cursor
+	backward bool
+
+	// cursorSecret signs the cursor paginators' cursors (nil = unsigned); cursorVerified marks
+	// a call whose cursor was already verified
+	cursorSecret   []byte
+	cursorVerified bool
+
+	// cursorTTL rejects cursors issued longer ago (0 = never); cursorStamped marks a call whose
+	// cursor age was already checked
+	cursorTTL     time.Duration
+	cursorStamped bool
+
+	// config is the RuntimeConfig snapshot the call runs with
+	config RuntimeConfig
+}
+
+// WithApproxRemaining enables a cheap, capped count of the rows after the current page
+// The count stops after limit rows, so ApproxRemaining is at most limit
+// and should be read as "limit or more" when it equals the cap
+//
+// Example:
+//
+//	result, err := pagination.CursorPaginateInt(db, &users, cursor, 20, "id", true,
+//	    pagination.WithApproxRemaining(500), // "~120 more" hints, never counts past 500
+//	)
+func WithApproxRemaining(limit int) Option {
+	return func(o *options) {
+		if limit > 0 {
+			o.approxRemainingLimit = limit
+		}
+	}
+}
+
+// WithHybridOffset serves offset-style metadata (page numbers and totals) from the cursor
+// paginators when a capped count shows the whole result set has at most threshold rows
+// Cursors keep working either way; larger sets get pure cursor metadata
+//
+// Example:
+//
+//	// Small lists show "Page 2 of 3", large ones fall back to infinite scroll
+//	result, err := pagination.CursorPaginateInt(db, &tags, cursor, 20, "id", true,
+//	    pagination.WithHybridOffset(200),
+//	)
+func WithHybridOffset(threshold int) Option {
+	return func(o *options) {
+		if threshold > 0 {
+			o.hybridThreshold = threshold
+		}
+	}
+}
+
+// WithMaxReportedTotal stops the offset paginators' count after limit rows
+// When more rows exist, TotalItems reports limit and TotalAtLeast is set, so clients can
+// render "1000+" instead of paying for (or showing) an exact count
+//
+// Example:
+//
+//	result, err := pagination.OffsetPaginate(db, &orders, page, 20,
+//	    pagination.WithMaxReportedTotal(1000), // "1000+ orders", never counts past 1001
+//	)
+func WithMaxReportedTotal(limit int) Option {
+	return func(o *options) {
+		if limit > 0 {
+			o.maxReportedTotal = limit
+		}
+	}
+}
+
+// WithStrictPageRange makes the offset paginators fail with ErrPageOutOfRange for pages
+// past the last page, instead of returning an empty page
+// Page 1 of an empty result set is always in range, and so is any page when the count was
+// capped by WithMaxReportedTotal (the real last page is unknown).
+//
+// Example:
+//
+//	result, err := pagination.OffsetPaginate(db, &orders, page, 20, pagination.WithStrictPageRange())
+//	if errors.Is(err, pagination.ErrPageOutOfRange) {
+//	    c.JSON(404, gin.H{"error": err.Error()})
+//	    return
+//	}
+func WithStrictPageRange() Option {
+	return func(o *options) {
+		o.strictPageRange = true
+	}
+}
+
+// WithPageIndexing sets how the offset paginators number pages
+// Under ZeroBased, page 0 is the first page, CurrentPage counts from 0, and HasPrevious is
+// set from page 1 on; TotalPages is still a count. Links from ToResponse and HeadLinks follow
+// the same numbering. Pair it with ParsePaginationParamsWithIndexing on the route.
+//
+// Example:
+//
+//	r.GET("/legacy/orders", pagination.ParsePaginationParamsWithIndexing(pagination.ZeroBased), ListLegacyOrders)
+//
+//	params := pagination.GetPaginationParams(c)
+//	result, err := pagination.OffsetPaginate(db, &orders, params.Page, params.PageSize,
+//	    pagination.WithPageIndexing(params.Indexing),
+//	)
+func WithPageIndexing(indexing PageIndexing) Option {
+	return func(o *options) {
+		o.pageIndexing = indexing
+	}
+}
+
+// AllowZeroPageSize makes page size 0 a metadata-only request for the offset paginators
+// Only the count query runs: Items is empty (never nil), MetadataOnly is set, and TotalItems
+// and TotalPages are computed for pages of pageSize, which is also reported as PageSize
+// (pageSize < 1 means the configured default page size). Negative page sizes are still reset to the
+// default. ToResponse emits only the first and last links for a metadata-only page, since a
+// metadata-only request has no neighbors of its own.
+//
+// Example:
+//
+//	// GET /orders?page_size=0 returns {"data": [], "pagination": {"total_items": 1234, ...}}
+//	params := pagination.GetPaginationParams(c)
+//	result, err := pagination.OffsetPaginate(db, &orders, params.Page, params.RequestedPageSize(),
+//	    pagination.AllowZeroPageSize(0),
+//	)
+func AllowZeroPageSize(pageSize int) Option {
+	return func(o *options) {
+		o.metadataPageSize = pageSize
+		if pageSize < 1 {
+			o.metadataPageSize = o.config.DefaultPageSize
+		}
+	}
+}
+
+// WithDeepPaginationHint nudges deep offset requests toward cursor pagination without
+// failing them
+// Pages past depth (1-based) still come back as usual, plus DeepPagination and a NextCursor
+// read from the page's last row (no extra query), encoded with the paginator's cursor codec.
+// The cursor continues with CursorPaginateInt/CursorPaginateString on cursorField ascending,
+// so the offset query must be ordered by cursorField ascending for the hand-off to resume where
+// the page ended.
+//
+// Example:
+//
+//	result, err := pagination.OffsetPaginate(db.Order("id ASC"), &events, page, 50,
+//	    pagination.WithDeepPaginationHint(100, "id"),
+//	)
+//	// ?page=101 => {"deep_pagination": true, "next_cursor": "..."}; follow with ?cursor=
+func WithDeepPaginationHint(depth int, cursorField string) Option {
+	return func(o *options) {
+		o.deepPageDepth = depth
+		o.deepPageCursorField = cursorField
+	}
+}
+
+// AllowAll lets offset page size PageSizeAll (page_size=all or -1) return every row as one
+// page, for internal tools reading small reference tables
+// No count runs: up to ceiling+1 rows are fetched, more than ceiling fails with
+// ErrResultTooLarge, and the result is a single page (TotalPages 1, HasNext false) whose
+// PageSize is ceiling. Without AllowAll (and with ceiling < 1), PageSizeAll is reset to the
+// default page size like any other negative page size, so keep it off public routes.
+//
+// Example:
+//
+//	params := pagination.GetPaginationParams(c)
+//	result, err := pagination.OffsetPaginate(db.Model(&Country{}), &countries, params.Page, params.RequestedPageSize(),
+//	    pagination.AllowAll(500),
+//	)
+func AllowAll(ceiling int) Option {
+	return func(o *options) {
+		o.allRowsCeiling = ceiling
+	}
+}
+
+// WithInclusiveCursor makes the cursor paginators include the cursor's own row
+// With a page's StartCursor it re-fetches exactly that page, e.g. to refresh a cached window.
+//
+// Example:
+//
+//	// Refresh the page the client cached, starting from its start_cursor
+//	result, err := pagination.CursorPaginateInt(db, &users, startCursor, 20, "id", true,
+//	    pagination.WithInclusiveCursor(),
+//	)
+func WithInclusiveCursor() Option {
+	return func(o *options) {
+		o.inclusiveCursor = true
+	}
+}
+
+// WithDriftDetection makes the cursor paginators report rows added before the session's start
+// The first row of the first page becomes the session's anchor and is appended to every
+// cursor of the result; later pages probe for a row sorting before it (one capped count, an
+// EXISTS in effect) and set NewItemsAvailable. Removed rows are not detected. Enable it for
+// the whole session: cursors issued without it are rejected with ErrInvalidCursor.
+//
+// Example:
+//
+//	result, err := pagination.CursorPaginateInt(db.Model(&Post{}), &posts, cursor, 20, "id", false,
+//	    pagination.WithDriftDetection(),
+//	)
+//	// result.NewItemsAvailable => show "new posts, tap to refresh"
+func WithDriftDetection() Option {
+	return func(o *options) {
+		o.driftDetection = true
+	}
+}
+
+// WithRowIDTieBreaker breaks ties in the cursor field with SQLite's implicit rowid, so pages
+// stay stable when the cursor field has duplicates and the table has no other unique column
+// Rows are ordered by the cursor field, then rowid, and every cursor of the result carries the
+// rowid of its row. It replaces the default primary key tie-breaker. On other dialects the
+// paginators fail with ErrTieBreakerRequired; sort by a unique column there.
+//
+// Example:
+//
+//	// Many events share a created_at second
+//	result, err := pagination.CursorPaginateString(db.Model(&Event{}), &events, cursor, 20, "created_at", true,
+//	    pagination.WithRowIDTieBreaker(),
+//	)
+func WithRowIDTieBreaker() Option {
+	return func(o *options) {
+		o.rowIDTieBreaker = true
+	}
+}
+
+// WithCompactCursor keeps CursorPaginateString's cursors short when the cursor field holds
+// long strings: a value longer than prefixLen bytes is encoded as its first prefixLen bytes
+// and a short hash, and the next request restores it from the row the primary key
+// tie-breaker names
+// The trade-off is one extra primary key lookup per request, and precision when the cursor's
+// row was deleted or its value changed: the page then starts at the prefix, repeating rows
+// that share it rather than skipping any. The paginator needs the tie-breaker (it fails with
+// ErrTieBreakerRequired under WithoutTieBreaker or on a primary key cursor field), and cursors
+// issued without this option are rejected with ErrInvalidCursor.
+//
+// Example:
+//
+//	// Titles run to hundreds of bytes; keep ?cursor= under a URL length limit
+//	result, err := pagination.CursorPaginateString(db.Model(&Article{}), &articles, cursor, 20, "title", true,
+//	    pagination.WithCompactCursor(32),
+//	)
+func WithCompactCursor(prefixLen int) Option {
+	return func(o *options) {
+		o.compactCursor = prefixLen
+	}
+}
+
+// WithJoinedCursorFields lets the cursor paginators order by columns of joined tables
+// fields maps each allowed table-qualified column (e.g. "products.name") to the column of the
+// page type the query selects it into; the paginator orders and filters by the quoted
+// qualified column, reads cursor values from the mapped column, and qualifies the primary key
+// tie-breaker with the model's table so neither is ambiguous. Other qualified cursor fields
+// fail with ErrCursorFieldNotAllowed, so the cursor field may come from a request.
+//
+// Example:
+//
+//	// Order lines sorted by product name
+//	query := db.Model(&OrderLine{}).
+//	    Select("order_lines.*, products.name AS product_name").
+//	    Joins("JOIN products ON products.id = order_lines.product_id")
+//	result, err := pagination.CursorPaginateString(query, &lines, cursor, 20, "products.name", true,
+//	    pagination.WithJoinedCursorFields(map[string]string{"products.name": "product_name"}),
+//	)
+func WithJoinedCursorFields(fields map[string]string) Option {
+	return func(o *options) {
+		o.joinedCursorFields = fields
+	}
+}
+
+// WithoutTieBreaker orders the cursor paginators by the cursor field alone
+// By default a cursor field that is neither the primary key nor tagged unique is followed by
+// the model's primary key in ORDER BY and in every cursor, so rows sharing a value are never
+// skipped or repeated across pages. Opt out when the column is unique without a gorm tag
+// saying so, or the extra sort key defeats an index.
+//
+// Example:
+//
+//	// slug has a unique index the model does not declare
+//	result, err := pagination.CursorPaginateString(db, &articles, cursor, 20, "slug", true,
+//	    pagination.WithoutTieBreaker(),
+//	)
+func WithoutTieBreaker() Option {
+	return func(o *options) {
+		o.noTieBreaker = true
+	}
+}
+
+// WithShardTotals makes ShardedCursorPaginate report TotalItems, the sum of each shard's
+// count (one COUNT per shard per page, through WithCountLimiter)
+//
+// Example:
+//
+//	result, err := pagination.ShardedCursorPaginate(shards, &orders, cursor, 20, "created_unix", false,
+//	    pagination.WithShardTotals(),
+//	)
+func WithShardTotals() Option {
+	return func(o *options) {
+		o.shardTotals = true
+	}
+}
+
+// WithCursorCodec replaces the default base64 cursor codec for a paginate call
+//
+// Example:
+//
+//	result, err := pagination.CursorPaginateInt(db, &users, cursor, 20, "id", true,
+//	    pagination.WithCursorCodec(pagination.JSONCursorCodec{}),
+//	)
+func WithCursorCodec(codec CursorCodec) Option {
+	return func(o *options) {
+		o.codec = codec
+	}
+}
+
+// WithCountLimiter runs the paginators' count queries (offset totals, WithApproxRemaining,
+// and WithHybridOffset) through l, so at most its limit of them hit the database at once
+// Share one limiter across every endpoint that counts the same tables.
+//
+// Example:
+//
+//	var counts = pagination.NewQueryLimiter(8, pagination.FailFast)
+//
+//	result, err := pagination.OffsetPaginate(db, &orders, page, 20, pagination.WithCountLimiter(counts))
+//	if errors.Is(err, pagination.ErrTooBusy) {
+//	    c.JSON(503, gin.H{"error": "try again shortly"})
+//	    return
+//	}
+func WithCountLimiter(l *QueryLimiter) Option {
+	return func(o *options) {
+		o.countLimiter = l
+	}
+}
+
+// WithCountBreaker guards the offset paginators' counts with b, shedding them while it is open
+// Pages served without a count have CountMode CountSkipped and no total in ToResponse. Share
+// one breaker across the endpoints counting the same database.
+//
+// Example:
+//
+//	var countBreaker = pagination.NewCountBreaker(pagination.CountBreakerConfig{})
+//
+//	result, err := pagination.OffsetPaginate(db, &orders, page, 20, pagination.WithCountBreaker(countBreaker))
+//	// result.CountMode == pagination.CountSkipped => render "page 3" without "of N"
+func WithCountBreaker(b *CountBreaker) Option {
+	return func(o *options) {
+		o.countBreaker = b
+	}
+}
+
+// WithCountFallback bounds the offset paginators' counts by policy's Timeout and, when a count
+// times out, reports the first total its Fallbacks find instead of failing
+// CountSource records the source and Warnings explains it; a fallback total skips
+// WithStrictPageRange, and HasNext comes from an extra row. When no fallback answers, the page
+// is served without totals (CountSourceNone, CountMode CountSkipped). The count and its
+// fallbacks never run past policy's Budget.
+//
+// Example:
+//
+//	var counts = pagination.NewMemoryPageCache(0)
+//
+//	result, err := pagination.OffsetPaginate(db, &orders, page, 20, pagination.WithCountFallback(pagination.CountFallbackPolicy{
+//	    Timeout:     500 * time.Millisecond,
+//	    Cache:       counts,
+//	    CacheMaxAge: 5 * time.Minute,
+//	}))
+//	// result.CountSource == pagination.CountSourceEstimate => render "about 12,000 results"
+func WithCountFallback(policy CountFallbackPolicy) Option {
+	policy = policy.withDefaults()
+	return func(o *options) {
+		o.countFallback = &policy
+	}
+}
+
+// WithCounterSource reads the offset paginators' total from the counter key of lookup (a
+// CounterTable or a callback) instead of running COUNT(*)
+// Build key with CounterKey from the active filters; a combination without a counter is
+// counted as usual. CountSource is "counter" or "exact", so a response says which it was.
+//
+// Example:
+//
+//	var invoiceCounters = pagination.CounterTable(db, "")
+//
+//	key := pagination.CounterKey("invoices", map[string]string{"status": c.Query("status")})
+//	result, err := pagination.OffsetPaginate(query, &invoices, page, 20, pagination.WithCounterSource(invoiceCounters, key))
+func WithCounterSource(lookup CounterLookup, key string) Option {
+	return func(o *options) {
+		o.counterLookup = lookup
+		o.counterKey = key
+	}
+}
+
+// WithFetchLimiter runs the paginators' page queries through l
+// It may be the same limiter as WithCountLimiter to bound every pagination query together.
+func WithFetchLimiter(l *QueryLimiter) Option {
+	return func(o *options) {
+		o.fetchLimiter = l
+	}
+}
+
+// WithReadReplica runs the paginator's count and page queries on a read replica
+// The query is still built on the primary handle passed to the paginator; the replica only
+// supplies the connection. A lagging replica sends the page to the primary, and a failed
+// replica query retries the whole page (count and rows) on the primary once.
+//
+// Example:
+//
+//	result, err := pagination.OffsetPaginate(db.Model(&Order{}), &orders, page, pageSize,
+//	    pagination.WithReadReplica(reads),
+//	)
+func WithReadReplica(r *ReadReplica) Option {
+	return func(o *options) {
+		o.replica = r
+	}
+}
+
+// WithCountDB runs the paginators' count queries (offset totals, WithApproxRemaining, and
+// WithHybridOffset) on db's connection, e.g. a replica, while the page keeps its own
+// As with WithReadReplica, the query is built on the handle passed to the paginator. Totals
+// may then lag the page: a row committed on the primary can be on the page but not yet in
+// TotalItems. The split is skipped inside the caller's transaction and snapshot sessions,
+// where every query must see the same data, and takes precedence over WithReadReplica.
+//
+// Example:
+//
+//	// Count on the replica; fetch from the primary so a just-created order is listed
+//	result, err := pagination.OffsetPaginate(primaryDB.Model(&Order{}), &orders, page, pageSize,
+//	    pagination.WithCountDB(replicaDB),
+//	)
+func WithCountDB(db *gorm.DB) Option {
+	return func(o *options) {
+		o.countDB = db
+	}
+}
+
+// WithFetchDB runs the paginators' page queries on db's connection; see WithCountDB
+func WithFetchDB(db *gorm.DB) Option {
+	return func(o *options) {
+		o.fetchDB = db
+	}
+}
+
+// WithSnapshot makes every page of a pagination session read the same snapshot of the data
+// The first page pins a snapshot and embeds its token in the returned cursors; later pages
+// read inside it, so rows inserted, updated, or deleted mid-session never shift the results.
+// Databases the Snapshotter does not support paginate without a snapshot.
+//
+// Example:
+//
+//	result, err := pagination.CursorPaginateInt(db, &entries, cursor, 20, "id", true,
+//	    pagination.WithSnapshot(snapshots), // e.g. pagination.NewPostgresSnapshots(0, 0)
+//	)
+func WithSnapshot(s Snapshotter) Option {
+	return func(o *options) {
+		o.snapshots = s
+	}
+}
+
+// WithPageCache serves repeated pages from cache for ttl (0 = DefaultPageCacheTTL)
+// Entries are keyed by the query's rendered SQL and arguments, the page or cursor, the page
+// size, and the other options, so only identical requests share a page. Pair it with
+// RegisterPageCacheInvalidation so writes through GORM drop stale pages; ttl bounds how stale
+// a page can get otherwise. Snapshot sessions (WithSnapshot) are never cached.
+//
+// Example:
+//
+//	result, err := pagination.OffsetPaginate(db.Model(&Product{}), &products, page, pageSize,
+//	    pagination.WithPageCache(pages, 10*time.Second),
+//	    pagination.WithMetrics(metrics), // page cache hits and misses
+//	)
+func WithPageCache(cache PageCache, ttl time.Duration) Option {
+	return func(o *options) {
+		if ttl <= 0 {
+			ttl = DefaultPageCacheTTL
+		}
+		o.pageCache = cache
+		o.pageCacheTTL = ttl
+	}
+}
+
+// WithMetrics reports paginator events, such as page cache hits and misses, to m
+func WithMetrics(m Metrics) Option {
+	return func(o *options) {
+		o.metrics = m
+	}
+}
+
+// WithContext runs every query of a paginate call (counts, the page fetch, look-aheads) with
+// ctx, usually the request's context
+// With a tracing plugin registered on the *gorm.DB (e.g. gorm.io/plugin/opentelemetry), the
+// queries then appear as child spans of the request's span whenever one is present; without a
+// span the plugin records nothing. It is equivalent to passing db.WithContext(ctx).
+//
+// Example:
+//
+//	// once at startup: db.Use(tracing.NewPlugin())
+//	result, err := pagination.OffsetPaginate(db.Model(&Order{}), &orders, page, pageSize,
+//	    pagination.WithContext(c.Request.Context()),
+//	)
+func WithContext(ctx context.Context) Option {
+	return func(o *options) {
+		o.ctx = ctx
+	}
+}
+
+// WithCountToken reuses the total carried by token (the client's count_token) instead of
+// counting, while tokens says it is authentic, fresh, and issued for the same query
+// Otherwise the rows are counted as usual. Either way the page's CountToken is the token to
+// send with the next request, and TotalFromToken reports whether the count was skipped.
+//
+// Example:
+//
+//	params := pagination.GetPaginationParams(c) // reads ?count_token= and X-Count-Token
+//	result, err := pagination.OffsetPaginate(db.Model(&Order{}), &orders, params.Page, params.PageSize,
+//	    pagination.WithCountToken(countTokens, params.CountToken),
+//	)
+func WithCountToken(tokens *CountTokens, token string) Option {
+	return func(o *options) {
+		o.countTokens = tokens
+		o.countToken = token
+	}
+}
+
+// WithSessionPageLimit stops a cursor session after limit's pages with ErrMaxPagesReached
+// Cursors carry the signed number of pages served; the first page (no cursor) is page 1.
+//
+// Example:
+//
+//	result, err := pagination.CursorPaginateInt(db.Model(&Post{}), &posts, cursor, 20, "id", false,
+//	    pagination.WithSessionPageLimit(feedPages),
+//	)
+func WithSessionPageLimit(limit *SessionPageLimit) Option {
+	return func(o *options) {
+		o.pageLimit = limit
+	}
+}
+
+// WithPageToken switches an offset route to opaque page tokens bound to the request's
+// filters and sort (fingerprint, usually QueryFingerprint of the query string)
+// The page's NextPageToken and PreviousPageToken encode page, page size, and fingerprint
+// with the paginator's cursor codec, so a signing codec signs them too. A presented token
+// overrides the page and pageSize arguments; one issued under a different fingerprint fails
+// with ErrPageTokenMismatch, telling the client to restart from the first page.
+//
+// Example:
+//
+//	params := pagination.GetPaginationParams(c) // reads ?page_token= and fingerprints the rest
+//	result, err := pagination.OffsetPaginate(query, &products, params.Page, params.PageSize,
+//	    pagination.WithPageToken(params.PageToken, params.Fingerprint),
+//	)
+//	// errors.Is(err, pagination.ErrPageTokenMismatch) => 400, restart without page_token
+func WithPageToken(token, fingerprint string) Option {
+	return func(o *options) {
+		o.pageTokens = true
+		o.pageToken = token
+		o.pageTokenFingerprint = fingerprint
+	}
+}
+
+// WithPeek returns the first item of the next page as NextPreview, for "more" teasers that
+// show what follows without a second request
+// The cursor paginators already fetch one row past the page to set HasNext, so the preview
+// costs no query; it is nil on the last page. The preview is not consumed: the next page
+// still starts with it, and WithRedaction masks it with the page's items.
+//
+// Example:
+//
+//	result, err := pagination.CursorPaginateInt(db, &posts, cursor, 20, "id", false, pagination.WithPeek())
+//	// result.NextPreview.Title => "Up next: ..."
+func WithPeek() Option {
+	return func(o *options) {
+		o.peek = true
+	}
+}
+
+// WithBackward makes the cursor paginators fetch the page before the cursor instead of after it
+// A page's PreviousCursor already carries it, so passing that back steps back on its own;
+// pass WithBackward with a StartCursor or a cursor of your own. The comparison and ORDER
+// BY are flipped to read the rows nearest the cursor, then reversed, so items keep the
+// display order of forward pages. HasPrevious reports whether rows precede the page (false
+// once back on the first page), and HasNext whether the cursor had rows after it. Without a
+// cursor it fetches the last page. NextPreview is not set on backward pages.
+//
+// Example:
+//
+//	// ?before=<start_cursor>
+//	if before := c.Query("before"); before != "" {
+//	    result, err = pagination.CursorPaginateInt(db, &posts, before, 20, "id", true, pagination.WithBackward())
+//	}
+func WithBackward() Option {
+	return func(o *options) {
+		o.backward = true
+	}
+}
+
+// WithSignedCursors signs every cursor the cursor paginators issue with HMAC-SHA256 under secret
+// (share it with every instance), so clients cannot edit one to probe other rows; a cursor
+// that was changed, or issued without this option, fails with ErrCursorTampered. The
+// signature covers the whole cursor, tie-breaker and drift anchor included.
+//
+// Example:
+//
+//	result, err := pagination.CursorPaginateInt(db.Model(&Post{}), &posts, cursor, 20, "id", false,
+//	    pagination.WithSignedCursors([]byte(os.Getenv("PAGINATION_SECRET"))),
+//	)
+func WithSignedCursors(secret []byte) Option {
+	return func(o *options) {
+		o.cursorSecret = secret
+	}
+}
+
+// WithCursorTTL stamps every cursor the cursor paginators issue with its issue time and fails
+// cursors older than maxAge with ErrCursorExpired, so clients cannot resume very stale
+// scrolls of a real-time feed
+// Combine it with WithSignedCursors, whose signature then covers the stamp; on its own a
+// client can restamp a cursor.
+//
+// Example:
+//
+//	result, err := pagination.CursorPaginateInt(db.Model(&Event{}), &events, cursor, 50, "id", false,
+//	    pagination.WithSignedCursors(secret),
+//	    pagination.WithCursorTTL(10*time.Minute),
+//	)
+func WithCursorTTL(maxAge time.Duration) Option {
+	return func(o *options) {
+		o.cursorTTL = maxAge
+	}
+}
+
+// bindContext returns db running with WithContext's context, if one was given
+func (o options) bindContext(db *gorm.DB) *gorm.DB {
+	if o.ctx == nil {
+		return db
+	}
+	return db.WithContext(o.ctx)
+}
+
+// cursorCodec returns the configured codec, falling back to DefaultCursorCodec
+func (o options) cursorCodec() CursorCodec {
+	if o.codec == nil {
+		return DefaultCursorCodec
+	}
+	return o.codec
+}
+
+// applyOptions resolves the given options into a settings struct
+func applyOptions(opts []Option) options {
+	o := options{config: CurrentConfig()}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+
+	// CountCapped caps routes that do not set their own WithMaxReportedTotal
+	if o.maxReportedTotal == 0 && o.config.CountMode == CountCapped {
+		o.maxReportedTotal = o.config.CountCap
+	}
+	return o
+}
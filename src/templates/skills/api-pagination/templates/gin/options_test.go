@@ -0,0 +1,52 @@
+package pagination
+
+import (
+	"context"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+type traceKey struct{}
+
+type tracedOrder struct {
+	ID uint
+}
+
+// TestWithContextReachesQueries checks that the context a tracing plugin reads its parent
+// span from is the one every paginator query runs with
+func TestWithContextReachesQueries(t *testing.T) {
+	db, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Stands in for a tracing plugin's query callback; no rows are returned without a dialector
+	var seen []context.Context
+	err = db.Callback().Query().Register("test:capture_context", func(tx *gorm.DB) {
+		seen = append(seen, tx.Statement.Context)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.WithValue(context.Background(), traceKey{}, "span-1")
+
+	var orders []tracedOrder
+	if _, err := OffsetPaginate(db.Model(&tracedOrder{}), &orders, 1, 10, WithContext(ctx)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CursorPaginateInt(db.Model(&tracedOrder{}), &orders, "", 10, "id", true, WithContext(ctx)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Offset: count and fetch; cursor: fetch
+	if len(seen) != 3 {
+		t.Fatalf("saw %d queries, want 3", len(seen))
+	}
+	for i, queryCtx := range seen {
+		if queryCtx.Value(traceKey{}) != "span-1" {
+			t.Errorf("query %d ran without the request context", i)
+		}
+	}
+}
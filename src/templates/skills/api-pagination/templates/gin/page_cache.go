@@ -0,0 +1,412 @@
+package pagination
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// DefaultPageCacheTTL is how long cached pages are served when WithPageCache is given no TTL
+const DefaultPageCacheTTL = 30 * time.Second
+
+// PageCache stores encoded pages so hot pages (page 1 of a popular list) skip the database
+// Entries are indexed by table so writes can drop every cached page of a model. Cache errors
+// never fail a request: a failed Get reads from the database and a failed Set is dropped.
+type PageCache interface {
+	// Get returns the page stored under key; ok is false on a miss or an expired entry
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+
+	// Set stores a page under key for ttl and indexes it under table
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration, table string) error
+
+	// InvalidateTable removes every page indexed under table
+	InvalidateTable(ctx context.Context, table string) error
+}
+
+// pageCacheEntry is one cached page in a MemoryPageCache
+type pageCacheEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+	table   string
+}
+
+// MemoryPageCache is an in-process LRU PageCache bounded by entry count
+// Suitable for a single instance; with several instances use a shared store
+// (responsecache.PageCache adapts the response cache's RedisStore).
+type MemoryPageCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List               // front = most recently used
+	entries    map[string]*list.Element // key -> element holding *pageCacheEntry
+	tables     map[string]map[string]struct{}
+}
+
+// NewMemoryPageCache creates a MemoryPageCache holding at most maxEntries pages (0 = 1000)
+//
+// Example usage:
+//
+//	var pages = pagination.NewMemoryPageCache(0)
+//
+//	result, err := pagination.OffsetPaginate(db.Model(&Product{}), &products, page, pageSize,
+//	    pagination.WithPageCache(pages, 10*time.Second),
+//	)
+func NewMemoryPageCache(maxEntries int) *MemoryPageCache {
+	if maxEntries <= 0 {
+		maxEntries = 1000
+	}
+	return &MemoryPageCache{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+		tables:     make(map[string]map[string]struct{}),
+	}
+}
+
+// Get implements PageCache
+func (c *MemoryPageCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	entry := element.Value.(*pageCacheEntry)
+	if time.Now().After(entry.expires) {
+		c.remove(element)
+		return nil, false, nil
+	}
+
+	c.order.MoveToFront(element)
+	return entry.value, true, nil
+}
+
+// Set implements PageCache
+func (c *MemoryPageCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration, table string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+
+	entry := &pageCacheEntry{key: key, value: value, expires: time.Now().Add(ttl), table: table}
+	c.entries[key] = c.order.PushFront(entry)
+	if c.tables[table] == nil {
+		c.tables[table] = make(map[string]struct{})
+	}
+	c.tables[table][key] = struct{}{}
+
+	for c.order.Len() > c.maxEntries {
+		c.remove(c.order.Back())
+	}
+	return nil
+}
+
+// InvalidateTable implements PageCache
+func (c *MemoryPageCache) InvalidateTable(ctx context.Context, table string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.tables[table] {
+		if element, ok := c.entries[key]; ok {
+			c.remove(element)
+		}
+	}
+	delete(c.tables, table)
+	return nil
+}
+
+// Len returns the number of cached pages, expired ones included until they are read or evicted
+func (c *MemoryPageCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// remove drops an entry and its table index entry; the caller holds mu
+func (c *MemoryPageCache) remove(element *list.Element) {
+	entry := element.Value.(*pageCacheEntry)
+	c.order.Remove(element)
+	delete(c.entries, entry.key)
+
+	delete(c.tables[entry.table], entry.key)
+	if len(c.tables[entry.table]) == 0 {
+		delete(c.tables, entry.table)
+	}
+}
+
+// InvalidateModel drops every cached page of model's table
+// Call it after writes the GORM callbacks do not see (Raw/Exec statements, other services).
+//
+// Example usage:
+//
+//	db.Exec("UPDATE products SET price = price * 1.1")
+//	pagination.InvalidateModel(db, pages, &Product{})
+func InvalidateModel(db *gorm.DB, cache PageCache, model any) error {
+	modelSchema, err := schema.Parse(model, &schemaCache, db.NamingStrategy)
+	if err != nil {
+		return fmt.Errorf("failed to parse model schema: %w", err)
+	}
+	return cache.InvalidateTable(queryContext(db), modelSchema.Table)
+}
+
+// RegisterPageCacheInvalidation drops a table's cached pages after every successful GORM
+// create, update, and delete on it
+// Invalidation is best effort: writes bypassing GORM's callbacks (Raw/Exec, other services)
+// and pages joining other tables stay cached until their TTL, which bounds the staleness.
+//
+// Example usage:
+//
+//	if err := pagination.RegisterPageCacheInvalidation(db, pages); err != nil {
+//	    log.Fatal(err)
+//	}
+func RegisterPageCacheInvalidation(db *gorm.DB, cache PageCache) error {
+	invalidate := func(tx *gorm.DB) {
+		if tx.Error != nil || tx.Statement.Table == "" {
+			return
+		}
+		_ = cache.InvalidateTable(queryContext(tx), tx.Statement.Table)
+	}
+
+	const name = "pagination:invalidate_page_cache"
+	if err := db.Callback().Create().After("gorm:create").Register(name, invalidate); err != nil {
+		return fmt.Errorf("failed to register create callback: %w", err)
+	}
+	if err := db.Callback().Update().After("gorm:update").Register(name, invalidate); err != nil {
+		return fmt.Errorf("failed to register update callback: %w", err)
+	}
+	if err := db.Callback().Delete().After("gorm:delete").Register(name, invalidate); err != nil {
+		return fmt.Errorf("failed to register delete callback: %w", err)
+	}
+	return nil
+}
+
+// pageCacheTable returns the table a paginated query reads, preferring the query's model
+// over T so projections (UserSummary from User) share their model's invalidation
+func pageCacheTable[T any](db *gorm.DB) (string, bool) {
+	var model any = new(T)
+	if db.Statement != nil && db.Statement.Model != nil {
+		model = db.Statement.Model
+	}
+
+	modelSchema, err := schema.Parse(model, &schemaCache, db.NamingStrategy)
+	if err != nil {
+		return "", false
+	}
+	return modelSchema.Table, true
+}
+
+// pageCacheKey hashes the query's rendered SQL (arguments inlined) with the page position
+// and every setting that changes the page, so equal requests share one entry
+func pageCacheKey[T any](db *gorm.DB, o options, position ...any) string {
+	rendered := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Find(&[]T{})
+	})
+
+	parts := []string{rendered, fmt.Sprintf("%T", new(T)), o.pageFingerprint()}
+	for _, p := range position {
+		parts = append(parts, fmt.Sprint(p))
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return "page:" + hex.EncodeToString(sum[:])
+}
+
+// pageFingerprint renders the options that change what a page contains
+func (o options) pageFingerprint() string {
+	return fmt.Sprintf("approx=%d hybrid=%d total=%d strict=%t inclusive=%t meta=%d all=%d indexing=%d drift=%t rowid=%t tie=%t deep=%d:%s tokens=%t:%s codec=%T default=%d budget=%d:%d peek=%t backward=%t",
+		o.approxRemainingLimit, o.hybridThreshold, o.maxReportedTotal, o.strictPageRange,
+		o.inclusiveCursor, o.metadataPageSize, o.allRowsCeiling, o.pageIndexing, o.driftDetection,
+		o.rowIDTieBreaker, !o.noTieBreaker, o.deepPageDepth, o.deepPageCursorField, o.pageTokens, o.pageTokenFingerprint, o.cursorCodec(), o.config.DefaultPageSize,
+		o.byteBudget, o.byteBudgetMinRows, o.peek, o.backward)
+}
+
+// usePageCache reports whether a paginate call should go through the page cache
+// Snapshot sessions are never cached: their pages belong to one client's snapshot.
+func (o options) usePageCache() bool {
+	return o.pageCache != nil && o.snapshots == nil && !o.pageCacheFilling
+}
+
+// cachedPage serves a page from the cache, or runs fill (the paginator re-entered with
+// filling, so it does not consult the cache again) and caches its result
+// The hit is reported to o.metrics; pages that fail to encode are returned uncached.
+func cachedPage[P any](
+	db *gorm.DB,
+	o options,
+	key string,
+	table string,
+	encode func(*P) ([]byte, error),
+	decode func([]byte) (*P, error),
+	fill func(filling Option) (*P, error),
+) (*P, error) {
+	ctx := queryContext(db)
+	metrics := o.metricsOrNoop()
+
+	if raw, ok, err := o.pageCache.Get(ctx, key); err == nil && ok {
+		if page, err := decode(raw); err == nil {
+			metrics.PageCacheHit(table)
+			return page, nil
+		}
+	}
+	metrics.PageCacheMiss(table)
+
+	page, err := fill(func(o *options) {
+		o.pageCacheFilling = true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if raw, err := encode(page); err == nil {
+		_ = o.pageCache.Set(ctx, key, raw, o.pageCacheTTL, table)
+	}
+	return page, nil
+}
+
+// cachedOffsetPage routes an offset paginator call through the page cache
+// cached is false when the query's table cannot be resolved; the caller then pages uncached.
+func cachedOffsetPage[T any](
+	db *gorm.DB,
+	dest *[]T,
+	o options,
+	fill func(filling Option) (*OffsetPagination[T], error),
+	position ...any,
+) (result *OffsetPagination[T], cached bool, err error) {
+	table, ok := pageCacheTable[T](db)
+	if !ok {
+		return nil, false, nil
+	}
+
+	key := pageCacheKey[T](db, o, position...)
+	result, err = cachedPage(db, o, key, table, encodeOffsetPage[T], decodeOffsetPage[T], fill)
+	if err != nil {
+		return nil, true, err
+	}
+
+	*dest = result.Items
+	return result, true, nil
+}
+
+// cachedCursorPage routes a cursor paginator call through the page cache, restoring the
+// boundary keys of cached pages from their items
+func cachedCursorPage[T any](
+	db *gorm.DB,
+	dest *[]T,
+	o options,
+	cursorField string,
+	fill func(filling Option) (*CursorPagination[T], error),
+	position ...any,
+) (result *CursorPagination[T], cached bool, err error) {
+	table, ok := pageCacheTable[T](db)
+	if !ok {
+		return nil, false, nil
+	}
+
+	key := pageCacheKey[T](db, o, position...)
+	result, err = cachedPage(db, o, key, table, encodeCursorPage[T], decodeCursorPage[T], fill)
+	if err != nil {
+		return nil, true, err
+	}
+
+	if result.FirstKey == nil && len(result.Items) > 0 {
+		column, err := resolveCursorColumn(db, cursorField, o)
+		if err != nil {
+			return nil, true, err
+		}
+		if result.FirstKey, result.LastKey, err = boundaryKeys(db, result.Items, column.key); err != nil {
+			return nil, true, err
+		}
+	}
+
+	*dest = result.Items
+	return result, true, nil
+}
+
+// encodeOffsetPage gob-encodes an offset page; unlike JSON, gob keeps fields tagged json:"-"
+func encodeOffsetPage[T any](page *OffsetPagination[T]) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(page); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeOffsetPage reverses encodeOffsetPage
+func decodeOffsetPage[T any](raw []byte) (*OffsetPagination[T], error) {
+	var page OffsetPagination[T]
+	if err := gob.NewDecoder(bytes.NewReader(raw)).Decode(&page); err != nil {
+		return nil, err
+	}
+	page.Items = nonNilItems(page.Items)
+	return &page, nil
+}
+
+// cursorPageEntry is a cached cursor page
+// gob decodes a pointer to zero as nil, so the optional counts that were zero are flagged;
+// FirstKey and LastKey may hold unregistered types and are re-read from the items on a hit.
+type cursorPageEntry[T any] struct {
+	Page *CursorPagination[T]
+
+	ZeroApproxRemaining bool
+	ZeroTotalPages      bool
+	ZeroTotalItems      bool
+}
+
+// encodeCursorPage gob-encodes a cursor page without its boundary keys
+func encodeCursorPage[T any](page *CursorPagination[T]) ([]byte, error) {
+	stored := *page
+	stored.FirstKey, stored.LastKey = nil, nil
+
+	entry := cursorPageEntry[T]{
+		Page:                &stored,
+		ZeroApproxRemaining: page.ApproxRemaining != nil && *page.ApproxRemaining == 0,
+		ZeroTotalPages:      page.TotalPages != nil && *page.TotalPages == 0,
+		ZeroTotalItems:      page.TotalItems != nil && *page.TotalItems == 0,
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(entry); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeCursorPage reverses encodeCursorPage; the caller restores FirstKey and LastKey
+func decodeCursorPage[T any](raw []byte) (*CursorPagination[T], error) {
+	var entry cursorPageEntry[T]
+	if err := gob.NewDecoder(bytes.NewReader(raw)).Decode(&entry); err != nil {
+		return nil, err
+	}
+	if entry.Page == nil {
+		return nil, fmt.Errorf("empty cached page")
+	}
+
+	page := entry.Page
+	if entry.ZeroApproxRemaining {
+		zero := int64(0)
+		page.ApproxRemaining = &zero
+	}
+	if entry.ZeroTotalPages {
+		zero := 0
+		page.TotalPages = &zero
+	}
+	if entry.ZeroTotalItems {
+		zero := int64(0)
+		page.TotalItems = &zero
+	}
+	page.Items = nonNilItems(page.Items)
+	return page, nil
+}
@@ -0,0 +1,135 @@
+package pagination
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type countingMetrics struct {
+	hits, misses int
+}
+
+func (m *countingMetrics) PageCacheHit(table string)  { m.hits++ }
+func (m *countingMetrics) PageCacheMiss(table string) { m.misses++ }
+
+func TestMemoryPageCacheBounds(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryPageCache(2)
+
+	cache.Set(ctx, "a", []byte("1"), time.Minute, "users")
+	cache.Set(ctx, "b", []byte("2"), time.Minute, "users")
+	cache.Get(ctx, "a") // a is now the most recently used
+	cache.Set(ctx, "c", []byte("3"), time.Minute, "orders")
+
+	if _, ok, _ := cache.Get(ctx, "b"); ok {
+		t.Error("least recently used entry survived past maxEntries")
+	}
+	if cache.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", cache.Len())
+	}
+
+	cache.InvalidateTable(ctx, "users")
+	if _, ok, _ := cache.Get(ctx, "a"); ok {
+		t.Error("users page survived InvalidateTable")
+	}
+	if _, ok, _ := cache.Get(ctx, "c"); !ok {
+		t.Error("orders page dropped by invalidating users")
+	}
+
+	cache.Set(ctx, "d", []byte("4"), time.Millisecond, "users")
+	time.Sleep(5 * time.Millisecond)
+	if _, ok, _ := cache.Get(ctx, "d"); ok {
+		t.Error("expired page served")
+	}
+}
+
+// TestCachedPageStaleness mutates the rows behind a cached page and checks that the stale
+// page is served at most until its TTL or an invalidation, whichever comes first
+func TestCachedPageStaleness(t *testing.T) {
+	ctx := context.Background()
+	rows := []int{1, 2, 3}
+	fetches := 0
+
+	metrics := &countingMetrics{}
+	ttl := 50 * time.Millisecond
+	cache := NewMemoryPageCache(0)
+	o := applyOptions([]Option{WithPageCache(cache, ttl), WithMetrics(metrics)})
+
+	page := func() []int {
+		t.Helper()
+		result, err := cachedPage(testDB(), o, "page-1", "numbers", encodeOffsetPage[int], decodeOffsetPage[int],
+			func(filling Option) (*OffsetPagination[int], error) {
+				if !applyOptions([]Option{filling}).pageCacheFilling {
+					t.Fatal("fill was not marked as filling the cache")
+				}
+				fetches++
+				items := append([]int(nil), rows...)
+				return &OffsetPagination[int]{Items: items, TotalItems: int64(len(items))}, nil
+			})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result.Items
+	}
+
+	cachedAt := time.Now()
+	if got := page(); len(got) != 3 {
+		t.Fatalf("first page = %v", got)
+	}
+
+	rows = append(rows, 4)
+	got := page()
+	if time.Since(cachedAt) < ttl && len(got) != 3 {
+		t.Errorf("page within TTL = %v, want the cached 3 rows", got)
+	}
+	if metrics.hits != 1 || metrics.misses != 1 {
+		t.Errorf("hits/misses = %d/%d, want 1/1", metrics.hits, metrics.misses)
+	}
+
+	// A write invalidates the table; the next read sees it immediately
+	cache.InvalidateTable(ctx, "numbers")
+	if got := page(); len(got) != 4 {
+		t.Errorf("page after invalidation = %v, want 4 rows", got)
+	}
+
+	// A write the callbacks miss is visible once the TTL passes
+	rows = append(rows, 5)
+	time.Sleep(ttl + 10*time.Millisecond)
+	if got := page(); len(got) != 5 {
+		t.Errorf("page after TTL = %v, want 5 rows", got)
+	}
+	if fetches != 3 {
+		t.Errorf("fetches = %d, want 3", fetches)
+	}
+}
+
+func TestCursorPageEntryKeepsZeroCounts(t *testing.T) {
+	zero, zeroPages, next := int64(0), 0, "abc"
+	page := &CursorPagination[int]{
+		Items:           []int{1},
+		NextCursor:      &next,
+		ApproxRemaining: &zero,
+		TotalPages:      &zeroPages,
+		FirstKey:        time.Now(), // not registered with gob; dropped and re-read on a hit
+	}
+
+	raw, err := encodeCursorPage(page)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := decodeCursorPage[int](raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.ApproxRemaining == nil || *decoded.ApproxRemaining != 0 {
+		t.Errorf("ApproxRemaining = %v, want a pointer to 0", decoded.ApproxRemaining)
+	}
+	if decoded.TotalPages == nil || decoded.TotalItems != nil {
+		t.Errorf("TotalPages/TotalItems = %v/%v, want 0/nil", decoded.TotalPages, decoded.TotalItems)
+	}
+	if decoded.NextCursor == nil || *decoded.NextCursor != next || decoded.FirstKey != nil {
+		t.Errorf("decoded page = %+v", decoded)
+	}
+}
@@ -0,0 +1,99 @@
+package pagination
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidPageToken is returned when a page token cannot be decoded
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// ErrPageTokenMismatch is returned when a page token is presented with different filters or
+// sort than it was issued for; the client should restart from the first page
+var ErrPageTokenMismatch = errors.New("page token was issued for different filters or sort")
+
+// QueryFingerprint hashes a query string without its pagination params (QueryKeys), so it
+// changes with filters and sort but not as the client moves between pages
+// Parameters are hashed in name order; the values of a repeated parameter keep theirs.
+func QueryFingerprint(values url.Values) string {
+	rest := url.Values{}
+	for key, value := range values {
+		rest[key] = value
+	}
+	for _, key := range QueryKeys {
+		rest.Del(key)
+	}
+
+	sum := sha256.Sum256([]byte(rest.Encode()))
+	return base64.RawURLEncoding.EncodeToString(sum[:9])
+}
+
+// resolvePageToken returns the page and page size a WithPageToken token points to, or page
+// and pageSize unchanged when no token was presented
+func (o options) resolvePageToken(page, pageSize int) (int, int, error) {
+	if o.pageToken == "" {
+		return page, pageSize, nil
+	}
+
+	decoded, err := o.cursorCodec().Decode(o.pageToken)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: %v", ErrInvalidPageToken, err)
+	}
+	payload, err := cursorString(decoded)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: %v", ErrInvalidPageToken, err)
+	}
+
+	parts := strings.SplitN(payload, ".", 3)
+	if len(parts) != 3 {
+		return 0, 0, fmt.Errorf("%w: malformed payload", ErrInvalidPageToken)
+	}
+	tokenPage, pageErr := strconv.Atoi(parts[0])
+	tokenPageSize, sizeErr := strconv.Atoi(parts[1])
+	if pageErr != nil || sizeErr != nil {
+		return 0, 0, fmt.Errorf("%w: malformed payload", ErrInvalidPageToken)
+	}
+
+	if parts[2] != o.pageTokenFingerprint {
+		return 0, 0, ErrPageTokenMismatch
+	}
+	return tokenPage, tokenPageSize, nil
+}
+
+// encodePageToken encodes a page token for page with the paginator's cursor codec
+func (o options) encodePageToken(page, pageSize int) (string, error) {
+	token, err := o.cursorCodec().Encode(fmt.Sprintf("%d.%d.%s", page, pageSize, o.pageTokenFingerprint))
+	if err != nil {
+		return "", fmt.Errorf("failed to encode page token: %w", err)
+	}
+	return token, nil
+}
+
+// applyPageTokens adds the next and previous page tokens to an offset page under WithPageToken
+func applyPageTokens[T any](result *OffsetPagination[T], o options) error {
+	if !o.pageTokens {
+		return nil
+	}
+
+	if result.HasNext {
+		next, err := o.encodePageToken(result.CurrentPage+1, result.PageSize)
+		if err != nil {
+			return err
+		}
+		result.NextPageToken = &next
+	}
+
+	if result.HasPrevious {
+		previous, err := o.encodePageToken(result.CurrentPage-1, result.PageSize)
+		if err != nil {
+			return err
+		}
+		result.PreviousPageToken = &previous
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package pagination
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+)
+
+func TestPageTokenRoundTrip(t *testing.T) {
+	filtered := url.Values{"filter[status]": []string{"active"}, "sort": []string{"-created_at"}, "page": []string{"7"}}
+	fingerprint := QueryFingerprint(filtered)
+
+	o := applyOptions([]Option{WithPageToken("", fingerprint)})
+	token, err := o.encodePageToken(7, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	o = applyOptions([]Option{WithPageToken(token, fingerprint)})
+	page, pageSize, err := o.resolvePageToken(1, 50)
+	if err != nil || page != 7 || pageSize != 20 {
+		t.Fatalf("resolvePageToken = %d, %d, %v; want 7, 20", page, pageSize, err)
+	}
+
+	// Moving between pages keeps the fingerprint; changing a filter or the sort does not
+	filtered.Set("page", "8")
+	if QueryFingerprint(filtered) != fingerprint {
+		t.Error("page number changed the fingerprint")
+	}
+	filtered.Set("filter[status]", "archived")
+	o = applyOptions([]Option{WithPageToken(token, QueryFingerprint(filtered))})
+	if _, _, err := o.resolvePageToken(1, 50); !errors.Is(err, ErrPageTokenMismatch) {
+		t.Errorf("changed filters: err = %v, want ErrPageTokenMismatch", err)
+	}
+
+	o = applyOptions([]Option{WithPageToken("not-a-token", fingerprint)})
+	if _, _, err := o.resolvePageToken(1, 50); !errors.Is(err, ErrInvalidPageToken) {
+		t.Errorf("garbage token: err = %v, want ErrInvalidPageToken", err)
+	}
+}
+
+func TestApplyPageTokens(t *testing.T) {
+	o := applyOptions([]Option{WithPageToken("", "fp")})
+	result := &OffsetPagination[int]{CurrentPage: 2, PageSize: 10, HasNext: true, HasPrevious: true}
+	if err := applyPageTokens(result, o); err != nil {
+		t.Fatal(err)
+	}
+	if result.NextPageToken == nil || result.PreviousPageToken == nil {
+		t.Fatalf("tokens = %v, %v; want both", result.NextPageToken, result.PreviousPageToken)
+	}
+
+	o.pageToken = *result.PreviousPageToken
+	if page, _, err := o.resolvePageToken(0, 0); err != nil || page != 1 {
+		t.Errorf("previous token page = %d, %v; want 1", page, err)
+	}
+
+	// Without WithPageToken pages carry no tokens
+	plain := &OffsetPagination[int]{CurrentPage: 2, PageSize: 10, HasNext: true}
+	if err := applyPageTokens(plain, options{}); err != nil || plain.NextPageToken != nil {
+		t.Errorf("plain page token = %v, %v", plain.NextPageToken, err)
+	}
+}
@@ -0,0 +1,62 @@
+package pagination
+
+// Paginated is a page from either paginator
+// Generic handler helpers accept it to serve offset and cursor pages alike; the concrete
+// *OffsetPagination and *CursorPagination keep their paginator-specific fields.
+//
+// Example usage:
+//
+//	func respond[T any](c *gin.Context, page pagination.Paginated[T]) {
+//	    if !page.HasNextPage() {
+//	        c.Header("X-Last-Page", "true")
+//	    }
+//	    c.JSON(200, page.ToResponse(c.Request.URL.Path))
+//	}
+type Paginated[T any] interface {
+	// GetItems returns the page's items
+	GetItems() []T
+
+	// GetPageSize returns the page size the page was fetched with
+	GetPageSize() int
+
+	// HasNextPage reports whether another page follows
+	HasNextPage() bool
+
+	// ToResponse converts the page to its API response
+	ToResponse(baseURL string, opts ...ResponseOption) PaginatedResponse[T]
+}
+
+var (
+	_ Paginated[any] = (*OffsetPagination[any])(nil)
+	_ Paginated[any] = (*CursorPagination[any])(nil)
+)
+
+// GetItems returns the page's items
+func (p *OffsetPagination[T]) GetItems() []T {
+	return p.Items
+}
+
+// GetPageSize returns the page size the page was fetched with
+func (p *OffsetPagination[T]) GetPageSize() int {
+	return p.PageSize
+}
+
+// HasNextPage reports whether another page follows
+func (p *OffsetPagination[T]) HasNextPage() bool {
+	return p.HasNext
+}
+
+// GetItems returns the page's items
+func (p *CursorPagination[T]) GetItems() []T {
+	return p.Items
+}
+
+// GetPageSize returns the page size the page was fetched with
+func (p *CursorPagination[T]) GetPageSize() int {
+	return p.PageSize
+}
+
+// HasNextPage reports whether another page follows
+func (p *CursorPagination[T]) HasNextPage() bool {
+	return p.HasNext
+}
@@ -0,0 +1,37 @@
+package pagination
+
+import (
+	"reflect"
+	"testing"
+)
+
+// lastItem is the kind of generic helper Paginated exists for
+func lastItem[T any](page Paginated[T]) (T, bool) {
+	var zero T
+	items := page.GetItems()
+	if len(items) == 0 {
+		return zero, false
+	}
+	return items[len(items)-1], page.HasNextPage()
+}
+
+func TestPaginatedAcceptsBothPaginators(t *testing.T) {
+	next := "Mw"
+	pages := []Paginated[int]{
+		&OffsetPagination[int]{Items: []int{1, 2}, PageSize: 2, HasNext: true, CurrentPage: 1, TotalPages: 2, TotalItems: 3},
+		&CursorPagination[int]{Items: []int{1, 2}, PageSize: 2, HasNext: true, NextCursor: &next},
+	}
+
+	for _, page := range pages {
+		last, more := lastItem(page)
+		if last != 2 || !more {
+			t.Errorf("%T: last item %d, more %t", page, last, more)
+		}
+		if page.GetPageSize() != 2 {
+			t.Errorf("%T: page size %d", page, page.GetPageSize())
+		}
+		if response := page.ToResponse(""); !reflect.DeepEqual(response.Data, []int{1, 2}) {
+			t.Errorf("%T: response data %v", page, response.Data)
+		}
+	}
+}
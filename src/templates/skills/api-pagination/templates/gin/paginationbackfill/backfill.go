@@ -0,0 +1,341 @@
+// Package paginationbackfill runs data migrations as resumable, checkpointed backfills
+// A Job walks a model in key order with the pagination package's keyset paginator, transforms
+// each batch inside a transaction, and records the last key it committed in the same
+// transaction, so a crashed or interrupted run resumes where it stopped. Main (command.go)
+// wraps a Job in a small command line.
+package paginationbackfill
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+
+	"{{packageImportPath}}/pagination"
+)
+
+// DefaultBatchSize is the batch size of a Job without one
+const DefaultBatchSize = 500
+
+// ErrNoName is returned when a Job has no Name to checkpoint under
+var ErrNoName = errors.New("backfill job needs a name")
+
+// ErrNoTransform is returned when a Job has no Transform
+var ErrNoTransform = errors.New("backfill job needs a transform")
+
+// ErrTooManyFailures is returned when more batches failed than a Job's MaxErrors
+var ErrTooManyFailures = errors.New("backfill aborted after too many failed batches")
+
+// errDryRun rolls back a dry run's transaction once the transform has run
+var errDryRun = errors.New("dry run")
+
+// Checkpoint is the last committed position of a backfill, one row per Job name
+// Create the table with db.AutoMigrate(&paginationbackfill.Checkpoint{}).
+type Checkpoint struct {
+	Name string `gorm:"primaryKey;size:191"`
+
+	// LastKey is the JSON of the key column's value in the last committed row
+	LastKey string
+
+	// Processed counts the rows transformed so far, and Failed the batches skipped after
+	// their transform failed
+	Processed int64
+	Failed    int64
+
+	UpdatedAt time.Time
+}
+
+// TableName keeps the checkpoints apart from the application's tables
+func (Checkpoint) TableName() string {
+	return "pagination_backfill_checkpoints"
+}
+
+// Job walks the Ts of DB in KeyColumn order and transforms them batch by batch
+//
+// Example usage:
+//
+//	job := paginationbackfill.Job[User]{
+//	    Name: "normalize-emails",
+//	    DB:   db,
+//	    Scope: func(db *gorm.DB) *gorm.DB {
+//	        return db.Where("email_normalized IS NULL")
+//	    },
+//	    Transform: func(ctx context.Context, tx *gorm.DB, users []User) error {
+//	        for _, u := range users {
+//	            err := tx.Model(&u).Update("email_normalized", strings.ToLower(u.Email)).Error
+//	            if err != nil {
+//	                return err
+//	            }
+//	        }
+//	        return nil
+//	    },
+//	}
+//	result, err := job.Run(ctx)
+type Job[T any] struct {
+	// Name identifies the job's checkpoint; rename the job to start over
+	Name string
+
+	// DB reads the rows and runs the transactions and checkpoints
+	DB *gorm.DB
+
+	// Scope filters the rows to backfill (nil = every row of T)
+	Scope func(db *gorm.DB) *gorm.DB
+
+	// KeyColumn is the unique column the rows are walked by (default "id"); its values must
+	// be integers, strings, or times
+	KeyColumn string
+
+	// Transform changes one batch through tx; an error rolls the batch back and skips it
+	Transform func(ctx context.Context, tx *gorm.DB, batch []T) error
+
+	// BatchSize is the number of rows per transaction (default DefaultBatchSize)
+	BatchSize int
+
+	// DryRun runs every transform but rolls its transaction back, and saves no checkpoint
+	DryRun bool
+
+	// Rate caps the rows transformed per second (0 = unlimited)
+	Rate float64
+
+	// MaxErrors is the number of failed batches tolerated before the run aborts (0 = abort
+	// on the first)
+	MaxErrors int
+
+	// Restart ignores the saved checkpoint and walks from the first row
+	Restart bool
+
+	// Progress receives a line per batch with the progress and ETA (default os.Stderr)
+	Progress io.Writer
+}
+
+// Result summarizes a run; Processed and Failed include earlier runs of the checkpoint
+type Result struct {
+	Processed int64
+	Failed    int64
+	Total     int64
+	Batches   int
+	Elapsed   time.Duration
+}
+
+// keySchemas caches the schemas KeyColumn is resolved on
+var keySchemas sync.Map
+
+// Run walks the job's rows from its checkpoint until none are left, ctx is done, or more
+// than MaxErrors batches failed
+// A failed batch is rolled back and skipped (its keys are written to Progress, to replay
+// with a narrower Scope); it is retried only when the run stops before a later batch commits.
+func (j Job[T]) Run(ctx context.Context) (Result, error) {
+	if j.Name == "" {
+		return Result{}, ErrNoName
+	}
+	if j.Transform == nil {
+		return Result{}, ErrNoTransform
+	}
+	if j.KeyColumn == "" {
+		j.KeyColumn = "id"
+	}
+	if j.BatchSize < 1 {
+		j.BatchSize = DefaultBatchSize
+	}
+	if j.Progress == nil {
+		j.Progress = os.Stderr
+	}
+
+	db := j.DB.WithContext(ctx)
+	key, err := keyField[T](db, j.KeyColumn)
+	if err != nil {
+		return Result{}, err
+	}
+
+	checkpoint := Checkpoint{Name: j.Name}
+	if !j.Restart {
+		if err := db.Where("name = ?", j.Name).Limit(1).Find(&checkpoint).Error; err != nil {
+			return Result{}, fmt.Errorf("failed to load checkpoint: %w", err)
+		}
+	}
+	last, err := decodeKey(checkpoint.LastKey)
+	if err != nil {
+		return Result{}, fmt.Errorf("invalid checkpoint %q: %w", j.Name, err)
+	}
+
+	// Count what is left, so filters that drop transformed rows keep the total right
+	var remaining int64
+	if err := j.boundary(db, last).Count(&remaining).Error; err != nil {
+		return Result{}, fmt.Errorf("failed to count rows: %w", err)
+	}
+
+	result := Result{Processed: checkpoint.Processed, Failed: checkpoint.Failed, Total: checkpoint.Processed + remaining}
+	start, runProcessed := time.Now(), int64(0)
+	for {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		batchStart := time.Now()
+
+		boundary, args := "", []any(nil)
+		if last != nil {
+			boundary, args = j.KeyColumn+" > ?", []any{last}
+		}
+		var batch []T
+		page, err := pagination.CursorPaginateRaw(j.query(db), &batch, boundary, args, j.KeyColumn+" ASC", j.BatchSize)
+		if err != nil {
+			return result, err
+		}
+		if len(page.Items) == 0 {
+			break
+		}
+		first, zero := key.ValueOf(ctx, reflect.ValueOf(&page.Items[0]).Elem())
+		if zero {
+			return result, fmt.Errorf("row without a %s: every row needs a key to resume from", j.KeyColumn)
+		}
+		value, zero := key.ValueOf(ctx, reflect.ValueOf(&page.Items[len(page.Items)-1]).Elem())
+		if zero {
+			return result, fmt.Errorf("row without a %s: every row needs a key to resume from", j.KeyColumn)
+		}
+		last = value
+		lastKey, err := json.Marshal(last)
+		if err != nil {
+			return result, fmt.Errorf("failed to encode key: %w", err)
+		}
+
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := j.Transform(ctx, tx, page.Items); err != nil {
+				return err
+			}
+			if j.DryRun {
+				return errDryRun
+			}
+			saved := checkpoint
+			saved.LastKey = string(lastKey)
+			saved.Processed = result.Processed + int64(len(page.Items))
+			saved.Failed = result.Failed
+			return tx.Save(&saved).Error
+		})
+		result.Batches++
+		switch {
+		case err == nil || errors.Is(err, errDryRun):
+			result.Processed += int64(len(page.Items))
+			runProcessed += int64(len(page.Items))
+		default:
+			result.Failed++
+			fmt.Fprintf(j.Progress, "backfill %s: batch %v..%v failed: %v\n", j.Name, first, last, err)
+			if result.Failed > int64(j.MaxErrors) {
+				return result, fmt.Errorf("%w: %d (last: %v)", ErrTooManyFailures, result.Failed, err)
+			}
+		}
+		result.Elapsed = time.Since(start)
+		j.report(result, runProcessed)
+
+		if !page.HasNext {
+			break
+		}
+		if err := j.throttle(ctx, batchStart, len(page.Items)); err != nil {
+			return result, err
+		}
+	}
+	result.Elapsed = time.Since(start)
+	return result, nil
+}
+
+// query is the job's rows, filtered by Scope
+func (j Job[T]) query(db *gorm.DB) *gorm.DB {
+	query := db.Model(new(T))
+	if j.Scope != nil {
+		query = j.Scope(query)
+	}
+	return query
+}
+
+// boundary is the job's rows past last (all of them when last is nil)
+func (j Job[T]) boundary(db *gorm.DB, last any) *gorm.DB {
+	query := j.query(db)
+	if last != nil {
+		query = query.Where(j.KeyColumn+" > ?", last)
+	}
+	return query
+}
+
+// report writes the run's progress and, once rows were processed, its ETA
+func (j Job[T]) report(result Result, runProcessed int64) {
+	line := fmt.Sprintf("backfill %s: %d/%d rows", j.Name, result.Processed, result.Total)
+	if result.Total > 0 {
+		line += fmt.Sprintf(" (%.1f%%)", 100*float64(result.Processed)/float64(result.Total))
+	}
+	if result.Failed > 0 {
+		line += fmt.Sprintf(", %d failed batches", result.Failed)
+	}
+	if left := result.Total - result.Processed; runProcessed > 0 && left > 0 {
+		eta := time.Duration(float64(result.Elapsed) / float64(runProcessed) * float64(left))
+		line += fmt.Sprintf(", ETA %s", eta.Round(time.Second))
+	}
+	if j.DryRun {
+		line += " [dry run]"
+	}
+	fmt.Fprintln(j.Progress, line)
+}
+
+// throttle waits until rows transformed since batchStart are within Rate
+func (j Job[T]) throttle(ctx context.Context, batchStart time.Time, rows int) error {
+	if j.Rate <= 0 {
+		return nil
+	}
+	wait := time.Duration(float64(rows)/j.Rate*float64(time.Second)) - time.Since(batchStart)
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// keyField resolves column on T through the GORM schema, so column tags apply
+func keyField[T any](db *gorm.DB, column string) (*schema.Field, error) {
+	modelSchema, err := schema.Parse(new(T), &keySchemas, db.NamingStrategy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse model schema: %w", err)
+	}
+	field := modelSchema.LookUpField(column)
+	if field == nil {
+		return nil, fmt.Errorf("key column %q not found on %s", column, modelSchema.Name)
+	}
+	return field, nil
+}
+
+// decodeKey reads a checkpoint's LastKey back into a bindable value (nil when unset)
+// Integers come back as int64; strings, including formatted times, as strings.
+func decodeKey(lastKey string) (any, error) {
+	if lastKey == "" {
+		return nil, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader([]byte(lastKey)))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	switch v := value.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n, nil
+		}
+		return v.Float64()
+	case string:
+		return v, nil
+	default:
+		return nil, fmt.Errorf("unsupported key %s", lastKey)
+	}
+}
@@ -0,0 +1,226 @@
+package paginationbackfill
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type account struct {
+	ID    int64
+	Email string
+}
+
+// fakePool counts the transactions committed and rolled back; it runs no SQL
+type fakePool struct {
+	commits, rollbacks int
+}
+
+func (p *fakePool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return nil, errors.New("fake pool runs no SQL")
+}
+
+func (p *fakePool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return nil, errors.New("fake pool runs no SQL")
+}
+
+func (p *fakePool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, errors.New("fake pool runs no SQL")
+}
+
+func (p *fakePool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return nil
+}
+
+func (p *fakePool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	return &fakeTx{p}, nil
+}
+
+// fakeTx is a transaction of fakePool
+type fakeTx struct {
+	*fakePool
+}
+
+func (t *fakeTx) Commit() error {
+	t.commits++
+	return nil
+}
+
+func (t *fakeTx) Rollback() error {
+	t.rollbacks++
+	return nil
+}
+
+// whereVar returns the first bound value of tx's WHERE clause (nil without one)
+func whereVar(tx *gorm.DB) any {
+	if c, ok := tx.Statement.Clauses["WHERE"]; ok {
+		for _, e := range c.Expression.(clause.Where).Exprs {
+			if expr, ok := e.(clause.Expr); ok && len(expr.Vars) > 0 {
+				return expr.Vars[0]
+			}
+		}
+	}
+	return nil
+}
+
+// backfillDB serves n accounts and keeps checkpoints in memory, through fake callbacks
+func backfillDB(t *testing.T, n int) (*gorm.DB, *fakePool, map[string]Checkpoint) {
+	t.Helper()
+	pool := &fakePool{}
+	db, err := gorm.Open(nil, &gorm.Config{ConnPool: pool})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkpoints := map[string]Checkpoint{}
+
+	err = db.Callback().Query().Register("test:accounts", func(tx *gorm.DB) {
+		after, _ := whereVar(tx).(int64)
+		switch dest := tx.Statement.Dest.(type) {
+		case *[]account:
+			limit := *tx.Statement.Clauses["LIMIT"].Expression.(clause.Limit).Limit
+			for id := after + 1; id <= int64(n) && len(*dest) < limit; id++ {
+				*dest = append(*dest, account{ID: id, Email: fmt.Sprintf("User%d@Example.com", id)})
+			}
+		case *int64:
+			*dest = int64(n) - after
+			tx.RowsAffected = 1
+		case *Checkpoint:
+			name, _ := whereVar(tx).(string)
+			if saved, ok := checkpoints[name]; ok {
+				*dest = saved
+			}
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Callback().Update().Register("test:checkpoints", func(tx *gorm.DB) {
+		if saved, ok := tx.Statement.Dest.(*Checkpoint); ok {
+			checkpoints[saved.Name] = *saved
+			tx.RowsAffected = 1
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, pool, checkpoints
+}
+
+func TestRunResumesFromCheckpoint(t *testing.T) {
+	db, pool, checkpoints := backfillDB(t, 7)
+
+	// The first run is interrupted after one batch
+	ctx, cancel := context.WithCancel(context.Background())
+	var seen []int64
+	job := Job[account]{
+		Name:      "normalize-emails",
+		DB:        db,
+		BatchSize: 3,
+		Progress:  &bytes.Buffer{},
+		Transform: func(ctx context.Context, tx *gorm.DB, batch []account) error {
+			for _, a := range batch {
+				seen = append(seen, a.ID)
+			}
+			cancel()
+			return nil
+		},
+	}
+	if _, err := job.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("interrupted run returned %v", err)
+	}
+	if saved := checkpoints["normalize-emails"]; saved.LastKey != "3" || saved.Processed != 3 || pool.commits != 1 {
+		t.Fatalf("checkpoint = %+v after %d commits, want key 3 after 1", saved, pool.commits)
+	}
+
+	// The second run picks up at account 4
+	progress := &bytes.Buffer{}
+	job.Progress = progress
+	result, err := job.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(seen) != "[1 2 3 4 5 6 7]" {
+		t.Errorf("transformed %v, want every account once", seen)
+	}
+	if result.Processed != 7 || result.Total != 7 || result.Batches != 2 {
+		t.Errorf("result = %+v, want 7/7 rows in 2 batches", result)
+	}
+	if saved := checkpoints["normalize-emails"]; saved.LastKey != "7" || saved.Processed != 7 {
+		t.Errorf("checkpoint = %+v, want key 7", saved)
+	}
+	if !strings.Contains(progress.String(), "normalize-emails: 7/7 rows (100.0%)") {
+		t.Errorf("progress = %q", progress)
+	}
+}
+
+func TestRunFailedBatches(t *testing.T) {
+	failing := func(ctx context.Context, tx *gorm.DB, batch []account) error {
+		if batch[0].ID == 4 {
+			return errors.New("constraint violated")
+		}
+		return nil
+	}
+
+	db, pool, checkpoints := backfillDB(t, 7)
+	job := Job[account]{Name: "tolerant", DB: db, BatchSize: 3, MaxErrors: 1, Progress: &bytes.Buffer{}, Transform: failing}
+	result, err := job.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Processed != 4 || result.Failed != 1 || pool.rollbacks != 1 {
+		t.Errorf("result = %+v with %d rollbacks, want 4 rows and the failed batch rolled back", result, pool.rollbacks)
+	}
+	if saved := checkpoints["tolerant"]; saved.LastKey != "7" || saved.Failed != 1 {
+		t.Errorf("checkpoint = %+v, want key 7 past the skipped batch", saved)
+	}
+
+	// Without tolerance the run stops at the failure, keeping the batch before it
+	db, _, checkpoints = backfillDB(t, 7)
+	job = Job[account]{Name: "strict", DB: db, BatchSize: 3, Progress: &bytes.Buffer{}, Transform: failing}
+	if _, err := job.Run(context.Background()); !errors.Is(err, ErrTooManyFailures) {
+		t.Errorf("strict run returned %v, want ErrTooManyFailures", err)
+	}
+	if saved := checkpoints["strict"]; saved.LastKey != "3" {
+		t.Errorf("checkpoint = %+v, want key 3", saved)
+	}
+}
+
+func TestMainDryRun(t *testing.T) {
+	db, pool, checkpoints := backfillDB(t, 5)
+	var batches []int
+	job := Job[account]{
+		Name:     "dry",
+		DB:       db,
+		Progress: &bytes.Buffer{},
+		Transform: func(ctx context.Context, tx *gorm.DB, batch []account) error {
+			batches = append(batches, len(batch))
+			return nil
+		},
+	}
+
+	if err := Main([]string{"-dry-run", "-batch-size", "2"}, job); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(batches) != "[2 2 1]" {
+		t.Errorf("batches = %v, want the flag's batch size", batches)
+	}
+	if len(checkpoints) != 0 || pool.commits != 0 || pool.rollbacks != 3 {
+		t.Errorf("dry run saved %v with %d commits and %d rollbacks, want every batch rolled back", checkpoints, pool.commits, pool.rollbacks)
+	}
+}
+
+func TestRunRequiresNameAndTransform(t *testing.T) {
+	if _, err := (Job[account]{Transform: func(context.Context, *gorm.DB, []account) error { return nil }}).Run(context.Background()); !errors.Is(err, ErrNoName) {
+		t.Errorf("unnamed job returned %v", err)
+	}
+	if _, err := (Job[account]{Name: "noop"}).Run(context.Background()); !errors.Is(err, ErrNoTransform) {
+		t.Errorf("job without transform returned %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package paginationbackfill
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// Main runs job as a command with the flags below, which default to the job's own settings
+// SIGINT and SIGTERM stop the run between batches, keeping the last checkpoint; run the
+// command again to resume. -h returns flag.ErrHelp after printing the usage.
+//
+//	-batch-size  rows per transaction
+//	-dry-run     transform every batch, then roll it back
+//	-rate        maximum rows per second (0 = unlimited)
+//	-max-errors  failed batches tolerated before aborting
+//	-restart     ignore the checkpoint and start from the first row
+//
+// Example usage (cmd/backfill-emails/main.go):
+//
+//	func main() {
+//	    db := openDatabase()
+//	    err := paginationbackfill.Main(os.Args[1:], paginationbackfill.Job[User]{
+//	        Name:      "normalize-emails",
+//	        DB:        db,
+//	        Transform: normalizeEmails,
+//	    })
+//	    if err != nil && !errors.Is(err, flag.ErrHelp) {
+//	        log.Fatal(err)
+//	    }
+//	}
+//
+//	// go run ./cmd/backfill-emails -dry-run -batch-size 200
+//	// go run ./cmd/backfill-emails -rate 1000 -max-errors 5
+func Main[T any](args []string, job Job[T]) error {
+	flags := flag.NewFlagSet(job.Name, flag.ContinueOnError)
+	flags.IntVar(&job.BatchSize, "batch-size", job.BatchSize, "rows per transaction (0 = default)")
+	flags.BoolVar(&job.DryRun, "dry-run", job.DryRun, "transform every batch, then roll it back")
+	flags.Float64Var(&job.Rate, "rate", job.Rate, "maximum rows per second (0 = unlimited)")
+	flags.IntVar(&job.MaxErrors, "max-errors", job.MaxErrors, "failed batches tolerated before aborting")
+	flags.BoolVar(&job.Restart, "restart", job.Restart, "ignore the checkpoint and start from the first row")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	result, err := job.Run(ctx)
+	if err != nil {
+		return fmt.Errorf("backfill %s stopped after %d/%d rows: %w", job.Name, result.Processed, result.Total, err)
+	}
+	fmt.Fprintf(flags.Output(), "backfill %s done: %d rows, %d failed batches in %s\n",
+		job.Name, result.Processed, result.Failed, result.Elapsed.Round(time.Millisecond))
+	return nil
+}
@@ -0,0 +1,51 @@
+//go:build paginationfx
+
+package paginationdi
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/fx"
+
+	"{{packageImportPath}}/pagination"
+)
+
+// Module provides the Paginator to an fx application
+// It needs a Config in the graph and picks up the Deps implementations the graph happens to
+// provide. It registers nothing by itself; add Middleware to parse params on every route.
+//
+// Example usage:
+//
+//	fx.New(
+//	    fx.Provide(func(cfg AppConfig) paginationdi.Config { return cfg.Pagination }),
+//	    paginationdi.Module,
+//	    paginationdi.Middleware, // optional: r.Use(p.Middleware()) on the *gin.Engine
+//	    fx.Provide(NewOrderHandler), // takes a paginationdi.Paginator
+//	)
+var Module = fx.Module("pagination", fx.Provide(newFromGraph))
+
+// Middleware registers the Paginator's middleware on the graph's *gin.Engine
+var Middleware = fx.Invoke(func(engine *gin.Engine, p Paginator) {
+	engine.Use(p.Middleware())
+})
+
+// graphDeps are the Module's inputs; everything but Config is optional
+type graphDeps struct {
+	fx.In
+
+	Config      Config
+	Metrics     pagination.Metrics      `optional:"true"`
+	PageCache   pagination.PageCache    `optional:"true"`
+	CursorCodec pagination.CursorCodec  `optional:"true"`
+	CountTokens *pagination.CountTokens `optional:"true"`
+	ReadReplica *pagination.ReadReplica `optional:"true"`
+}
+
+func newFromGraph(in graphDeps) Paginator {
+	return New(in.Config, Deps{
+		Metrics:     in.Metrics,
+		PageCache:   in.PageCache,
+		CursorCodec: in.CursorCodec,
+		CountTokens: in.CountTokens,
+		ReadReplica: in.ReadReplica,
+	})
+}
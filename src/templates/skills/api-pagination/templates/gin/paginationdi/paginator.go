@@ -0,0 +1,145 @@
+// Package paginationdi assembles the pagination package's shared dependencies for
+// dependency-injection containers
+// The fx module (fx.go, built with -tags paginationfx) and the wire provider set (wire.go,
+// built with -tags paginationwire) are opt-in, so services that never paginate pull in
+// neither container. Nothing here runs on import: no init functions and no package-level
+// state of the pagination package is modified.
+package paginationdi
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"{{packageImportPath}}/pagination"
+)
+
+// Config is the pagination section of a service's configuration
+//
+// Example usage:
+//
+//	type AppConfig struct {
+//	    Pagination paginationdi.Config `yaml:"pagination"`
+//	}
+type Config struct {
+	// ZeroBasedPages numbers offset pages from 0 instead of 1
+	ZeroBasedPages bool `yaml:"zero_based_pages" json:"zero_based_pages"`
+
+	// MaxConcurrentCounts caps concurrent COUNT queries (0 = unlimited); past it, counts wait
+	// for a slot, or fail with pagination.ErrTooBusy when CountFailFast is set
+	MaxConcurrentCounts int  `yaml:"max_concurrent_counts" json:"max_concurrent_counts"`
+	CountFailFast       bool `yaml:"count_fail_fast" json:"count_fail_fast"`
+
+	// PageCacheTTL is how long cached pages are served; used only when Deps.PageCache is set
+	PageCacheTTL time.Duration `yaml:"page_cache_ttl" json:"page_cache_ttl"`
+
+	// EmptyStatus and OutOfRangeStatus override the 200 answered for empty and out-of-range
+	// pages (see pagination.StatusPolicy)
+	EmptyStatus      int `yaml:"empty_status" json:"empty_status"`
+	OutOfRangeStatus int `yaml:"out_of_range_status" json:"out_of_range_status"`
+}
+
+// Deps are the optional implementations the paginators use when present
+// Nil fields are skipped, so a service provides only what it has.
+type Deps struct {
+	Metrics     pagination.Metrics
+	PageCache   pagination.PageCache
+	CursorCodec pagination.CursorCodec
+	CountTokens *pagination.CountTokens
+	ReadReplica *pagination.ReadReplica
+}
+
+// Paginator hands the configured pagination setup to handler constructors
+// The paginate functions are generic, so it provides their options rather than wrapping them.
+//
+// Example usage:
+//
+//	func NewOrderHandler(db *gorm.DB, p paginationdi.Paginator) *OrderHandler {
+//	    return &OrderHandler{db: db, p: p}
+//	}
+//
+//	func (h *OrderHandler) List(c *gin.Context) {
+//	    params := pagination.GetPaginationParams(c)
+//	    var orders []Order
+//	    result, err := pagination.OffsetPaginate(h.db.Model(&Order{}), &orders, params.Page, params.PageSize,
+//	        h.p.Options(c)...,
+//	    )
+//	    // ...
+//	    pagination.WriteOffsetPage(c, h.p.StatusPolicy(), result)
+//	}
+type Paginator interface {
+	// Middleware parses pagination params with the configured page numbering
+	Middleware() gin.HandlerFunc
+
+	// Options returns the configured options for a request, followed by extra
+	Options(c *gin.Context, extra ...pagination.Option) []pagination.Option
+
+	// StatusPolicy returns the configured status codes for WriteOffsetPage/WriteCursorPage
+	StatusPolicy() pagination.StatusPolicy
+}
+
+// paginator is the Paginator built by New
+type paginator struct {
+	indexing pagination.PageIndexing
+	status   pagination.StatusPolicy
+	tokens   *pagination.CountTokens
+	shared   []pagination.Option
+}
+
+// New builds the Paginator for cfg and deps
+//
+// Example usage:
+//
+//	p := paginationdi.New(cfg.Pagination, paginationdi.Deps{Metrics: promMetrics})
+//	r.GET("/orders", p.Middleware(), orders.List)
+func New(cfg Config, deps Deps) Paginator {
+	p := &paginator{
+		status: pagination.StatusPolicy{Empty: cfg.EmptyStatus, OutOfRange: cfg.OutOfRangeStatus},
+		tokens: deps.CountTokens,
+	}
+	if cfg.ZeroBasedPages {
+		p.indexing = pagination.ZeroBased
+	}
+
+	// Options shared by every request; the limiter is created once so its slots are shared too
+	if cfg.MaxConcurrentCounts > 0 {
+		policy := pagination.WaitForSlot
+		if cfg.CountFailFast {
+			policy = pagination.FailFast
+		}
+		p.shared = append(p.shared, pagination.WithCountLimiter(pagination.NewQueryLimiter(cfg.MaxConcurrentCounts, policy)))
+	}
+	if deps.Metrics != nil {
+		p.shared = append(p.shared, pagination.WithMetrics(deps.Metrics))
+	}
+	if deps.PageCache != nil && cfg.PageCacheTTL > 0 {
+		p.shared = append(p.shared, pagination.WithPageCache(deps.PageCache, cfg.PageCacheTTL))
+	}
+	if deps.CursorCodec != nil {
+		p.shared = append(p.shared, pagination.WithCursorCodec(deps.CursorCodec))
+	}
+	if deps.ReadReplica != nil {
+		p.shared = append(p.shared, pagination.WithReadReplica(deps.ReadReplica))
+	}
+	return p
+}
+
+func (p *paginator) Middleware() gin.HandlerFunc {
+	return pagination.ParsePaginationParamsWithIndexing(p.indexing)
+}
+
+func (p *paginator) Options(c *gin.Context, extra ...pagination.Option) []pagination.Option {
+	params := pagination.GetPaginationParams(c)
+
+	opts := make([]pagination.Option, 0, len(p.shared)+3+len(extra))
+	opts = append(opts, p.shared...)
+	opts = append(opts, pagination.WithPageIndexing(p.indexing), pagination.WithContext(c.Request.Context()))
+	if p.tokens != nil {
+		opts = append(opts, pagination.WithCountToken(p.tokens, params.CountToken))
+	}
+	return append(opts, extra...)
+}
+
+func (p *paginator) StatusPolicy() pagination.StatusPolicy {
+	return p.status
+}
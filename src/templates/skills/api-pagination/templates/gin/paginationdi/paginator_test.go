@@ -0,0 +1,92 @@
+package paginationdi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"{{packageImportPath}}/pagination"
+)
+
+type order struct {
+	ID int64
+}
+
+func TestPaginatorAppliesConfig(t *testing.T) {
+	db, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Callback().Query().Register("test:orders", func(tx *gorm.DB) {
+		switch dest := tx.Statement.Dest.(type) {
+		case *[]order:
+			*dest = append((*dest)[:0], order{ID: 1})
+		case *int64:
+			*dest = 1
+			tx.RowsAffected = 1
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := New(Config{ZeroBasedPages: true, EmptyStatus: http.StatusNoContent}, Deps{})
+	c := &gin.Context{Request: httptest.NewRequest(http.MethodGet, "/orders", nil)}
+
+	var orders []order
+	result, err := pagination.OffsetPaginate(db.Model(&order{}), &orders, 0, 10, p.Options(c)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.CurrentPage != 0 || result.HasPrevious {
+		t.Errorf("zero-based first page: current page %d, has previous %t", result.CurrentPage, result.HasPrevious)
+	}
+	if status := p.StatusPolicy().Status(pagination.OutcomeEmpty); status != http.StatusNoContent {
+		t.Errorf("empty status = %d, want 204", status)
+	}
+}
+
+func TestPaginatorSharesCountLimiter(t *testing.T) {
+	db, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	release := make(chan struct{})
+	counting := make(chan struct{})
+	err = db.Callback().Query().Register("test:slow-count", func(tx *gorm.DB) {
+		if dest, ok := tx.Statement.Dest.(*int64); ok {
+			counting <- struct{}{}
+			<-release
+			*dest = 1
+			tx.RowsAffected = 1
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := New(Config{MaxConcurrentCounts: 1, CountFailFast: true}, Deps{})
+	c := &gin.Context{Request: httptest.NewRequest(http.MethodGet, "/orders", nil)}
+
+	// The first request holds the only count slot; the second fails fast
+	done := make(chan error)
+	go func() {
+		var orders []order
+		_, err := pagination.OffsetPaginate(db.Model(&order{}), &orders, 1, 10, p.Options(c)...)
+		done <- err
+	}()
+	<-counting
+
+	var orders []order
+	if _, err := pagination.OffsetPaginate(db.Model(&order{}), &orders, 1, 10, p.Options(c)...); !errors.Is(err, pagination.ErrTooBusy) {
+		t.Errorf("expected ErrTooBusy, got %v", err)
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,23 @@
+//go:build paginationwire
+
+package paginationdi
+
+import "github.com/google/wire"
+
+// ProviderSet provides the Paginator to a wire injector from a Config and Deps
+// Wire has no optional inputs, so build Deps with wire.Struct naming only the fields the
+// graph provides; the others stay nil and are skipped.
+//
+// Example usage:
+//
+//	func InitializeServer(cfg AppConfig) (*Server, error) {
+//	    wire.Build(
+//	        paginationdi.ProviderSet,
+//	        wire.FieldsOf(new(AppConfig), "Pagination"),
+//	        wire.Struct(new(paginationdi.Deps), "Metrics"),
+//	        NewMetrics, // returns pagination.Metrics
+//	        NewServer,
+//	    )
+//	    return nil, nil
+//	}
+var ProviderSet = wire.NewSet(New)
@@ -0,0 +1,150 @@
+package paginationrepo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"{{packageImportPath}}/pagination"
+)
+
+// MemoryRepository is an in-memory PaginatedRepository for unit tests
+// Items are kept in the order FindAfter walks them; specs filter with their match functions
+// and FindPage orders with their less functions. Page sizes follow the pagination package's
+// runtime config, like the GORM repository's. Cursors are opaque positions in the filtered
+// items, valid only for the same specs.
+//
+// Example usage:
+//
+//	repo := paginationrepo.NewMemoryRepository([]Order{
+//	    {ID: 1, Status: "paid"},
+//	    {ID: 2, Status: "open"},
+//	})
+//	service := &OrderService{orders: repo}
+type MemoryRepository[T any] struct {
+	Items []T
+}
+
+var _ PaginatedRepository[struct{}] = (*MemoryRepository[struct{}])(nil)
+
+// NewMemoryRepository returns a MemoryRepository of items
+func NewMemoryRepository[T any](items []T) *MemoryRepository[T] {
+	return &MemoryRepository[T]{Items: items}
+}
+
+// matching returns the items every spec matches, in stored order
+func (r *MemoryRepository[T]) matching(specs []Spec[T]) []T {
+	items := make([]T, 0, len(r.Items))
+	for _, item := range r.Items {
+		matched := true
+		for _, spec := range specs {
+			if spec.match != nil && !spec.match(item) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// pageSize resolves a requested page size the way the paginators do
+func pageSize(size, all int) int {
+	config := pagination.CurrentConfig()
+	switch {
+	case size == pagination.PageSizeAll:
+		return all
+	case size < 1:
+		return config.DefaultPageSize
+	case size > config.MaxPageSize:
+		return config.MaxPageSize
+	}
+	return size
+}
+
+func (r *MemoryRepository[T]) FindPage(ctx context.Context, params pagination.PaginationParams, specs ...Spec[T]) (*pagination.OffsetPagination[T], error) {
+	items := r.matching(specs)
+	sort.SliceStable(items, func(i, j int) bool {
+		for _, spec := range specs {
+			if spec.less == nil {
+				continue
+			}
+			if spec.less(items[i], items[j]) {
+				return true
+			}
+			if spec.less(items[j], items[i]) {
+				return false
+			}
+		}
+		return false
+	})
+
+	size := pageSize(params.RequestedPageSize(), len(items))
+	page := params.Page - params.Indexing.FirstPage() + 1
+	if page < 1 {
+		page = 1
+	}
+	totalPages := 0
+	if size > 0 {
+		totalPages = (len(items) + size - 1) / size
+	}
+
+	from := (page - 1) * size
+	if from > len(items) {
+		from = len(items)
+	}
+	to := from + size
+	if to > len(items) {
+		to = len(items)
+	}
+
+	return &pagination.OffsetPagination[T]{
+		Items:       append([]T{}, items[from:to]...),
+		CurrentPage: page + params.Indexing.FirstPage() - 1,
+		PageSize:    size,
+		TotalItems:  int64(len(items)),
+		TotalPages:  totalPages,
+		HasNext:     page < totalPages,
+		HasPrevious: page > 1,
+		Indexing:    params.Indexing,
+	}, nil
+}
+
+func (r *MemoryRepository[T]) FindAfter(ctx context.Context, cursor string, size int, specs ...Spec[T]) (*pagination.CursorPagination[T], error) {
+	items := r.matching(specs)
+
+	from := 0
+	if cursor != "" {
+		decoded, err := pagination.DecodeCursor(cursor, "position", true)
+		if err != nil {
+			return nil, err
+		}
+		if from, err = strconv.Atoi(decoded); err != nil || from < 0 {
+			return nil, fmt.Errorf("%w: not a position", pagination.ErrInvalidCursor)
+		}
+		if from > len(items) {
+			from = len(items)
+		}
+	}
+
+	size = pageSize(size, len(items)-from)
+	to := from + size
+	if to > len(items) {
+		to = len(items)
+	}
+
+	result := &pagination.CursorPagination[T]{
+		Items:       append([]T{}, items[from:to]...),
+		HasNext:     to < len(items),
+		HasPrevious: cursor != "",
+		PageSize:    size,
+	}
+	if result.HasNext {
+		next := pagination.EncodeCursor("position", to, true)
+		result.NextCursor = &next
+	}
+	return result, nil
+}
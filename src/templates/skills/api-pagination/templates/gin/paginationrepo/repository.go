@@ -0,0 +1,164 @@
+// Package paginationrepo puts pagination behind a data-access interface
+// Services depend on PaginatedRepository and compose query specifications, so handlers and
+// services never touch gorm; the GORM implementation runs the pagination package's paginators,
+// and the in-memory implementation (memory.go) stands in for it in unit tests.
+package paginationrepo
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"{{packageImportPath}}/pagination"
+)
+
+// PaginatedRepository pages through the Ts matching a set of specifications
+//
+// Example usage:
+//
+//	type OrderService struct {
+//	    orders paginationrepo.PaginatedRepository[Order]
+//	}
+//
+//	func (s *OrderService) PaidOrders(ctx context.Context, params pagination.PaginationParams) (*pagination.OffsetPagination[Order], error) {
+//	    return s.orders.FindPage(ctx, params,
+//	        paginationrepo.Where(func(o Order) bool { return o.Status == "paid" }, "status = ?", "paid"),
+//	        paginationrepo.OrderBy("created_at", true, func(a, b Order) bool { return a.CreatedAt.After(b.CreatedAt) }),
+//	        paginationrepo.Preload[Order]("Customer"),
+//	    )
+//	}
+//
+//	func (h *OrderHandler) List(c *gin.Context) {
+//	    result, err := h.service.PaidOrders(c.Request.Context(), pagination.GetPaginationParams(c))
+//	    if err != nil {
+//	        pagination.AbortWithError(c, pagination.ErrorStatus(err), err)
+//	        return
+//	    }
+//	    c.JSON(200, result.ToResponse(c.Request.URL.Path))
+//	}
+//
+//	// Production: paginationrepo.NewGormRepository[Order](db, paginationrepo.Config{})
+//	// Unit tests: paginationrepo.NewMemoryRepository(fixtures)
+type PaginatedRepository[T any] interface {
+	// FindPage returns the offset page params asks for
+	FindPage(ctx context.Context, params pagination.PaginationParams, specs ...Spec[T]) (*pagination.OffsetPagination[T], error)
+
+	// FindAfter returns the size items after cursor in the repository's cursor order
+	// Ordering specs do not apply: the cursor order is what keeps pages stable.
+	FindAfter(ctx context.Context, cursor string, size int, specs ...Spec[T]) (*pagination.CursorPagination[T], error)
+}
+
+// Spec is a composable query specification: a filter, an ordering, or a preload
+// Each spec carries its GORM form and, where it changes the result, its in-memory form, so
+// the same specs drive both repositories.
+type Spec[T any] struct {
+	// apply narrows or orders a GORM query
+	apply func(db *gorm.DB) *gorm.DB
+
+	// match filters items in memory (nil = every item)
+	match func(item T) bool
+
+	// less orders items in memory (nil = not an ordering)
+	less func(a, b T) bool
+}
+
+// Where filters by query and args in GORM, and by match in memory
+func Where[T any](match func(item T) bool, query any, args ...any) Spec[T] {
+	return Spec[T]{
+		apply: func(db *gorm.DB) *gorm.DB { return db.Where(query, args...) },
+		match: match,
+	}
+}
+
+// OrderBy orders FindPage results by column in GORM, and by less in memory
+// Later orderings break ties of earlier ones.
+func OrderBy[T any](column string, descending bool, less func(a, b T) bool) Spec[T] {
+	direction := " ASC"
+	if descending {
+		direction = " DESC"
+	}
+	return Spec[T]{
+		apply: func(db *gorm.DB) *gorm.DB { return db.Order(column + direction) },
+		less:  less,
+	}
+}
+
+// Preload loads association with GORM; in memory, items are returned as stored
+func Preload[T any](association string, args ...any) Spec[T] {
+	return Spec[T]{
+		apply: func(db *gorm.DB) *gorm.DB { return db.Preload(association, args...) },
+	}
+}
+
+// Scope applies a GORM scope, matching items in memory with match (nil = every item)
+// Use it for conditions Where cannot express, such as joins.
+func Scope[T any](scope func(db *gorm.DB) *gorm.DB, match func(item T) bool) Spec[T] {
+	return Spec[T]{apply: scope, match: match}
+}
+
+// Config configures a repository's cursor order and paginator options
+type Config struct {
+	// CursorField is the column FindAfter pages by (default "id")
+	CursorField string
+
+	// StringCursor pages FindAfter with CursorPaginateString instead of CursorPaginateInt
+	StringCursor bool
+
+	// Descending reverses FindAfter's cursor order
+	Descending bool
+
+	// Options are passed to every paginator call
+	Options []pagination.Option
+}
+
+// withDefaults fills unset fields
+func (cfg Config) withDefaults() Config {
+	if cfg.CursorField == "" {
+		cfg.CursorField = "id"
+	}
+	return cfg
+}
+
+// gormRepository is the PaginatedRepository built by NewGormRepository
+type gormRepository[T any] struct {
+	db  *gorm.DB
+	cfg Config
+}
+
+// NewGormRepository returns a PaginatedRepository of T's table in db
+func NewGormRepository[T any](db *gorm.DB, cfg Config) PaginatedRepository[T] {
+	return &gormRepository[T]{db: db, cfg: cfg.withDefaults()}
+}
+
+// query is T's table narrowed by specs
+func (r *gormRepository[T]) query(specs []Spec[T], orderings bool) *gorm.DB {
+	query := r.db.Model(new(T))
+	for _, spec := range specs {
+		if spec.apply != nil && (orderings || spec.less == nil) {
+			query = spec.apply(query)
+		}
+	}
+	return query
+}
+
+// options are the config's options for a call under ctx
+func (r *gormRepository[T]) options(ctx context.Context, extra ...pagination.Option) []pagination.Option {
+	opts := append([]pagination.Option{pagination.WithContext(ctx)}, extra...)
+	return append(opts, r.cfg.Options...)
+}
+
+func (r *gormRepository[T]) FindPage(ctx context.Context, params pagination.PaginationParams, specs ...Spec[T]) (*pagination.OffsetPagination[T], error) {
+	var items []T
+	return pagination.OffsetPaginate(r.query(specs, true), &items, params.Page, params.RequestedPageSize(),
+		r.options(ctx, pagination.WithPageIndexing(params.Indexing))...,
+	)
+}
+
+func (r *gormRepository[T]) FindAfter(ctx context.Context, cursor string, size int, specs ...Spec[T]) (*pagination.CursorPagination[T], error) {
+	var items []T
+	query := r.query(specs, false)
+	if r.cfg.StringCursor {
+		return pagination.CursorPaginateString(query, &items, cursor, size, r.cfg.CursorField, !r.cfg.Descending, r.options(ctx)...)
+	}
+	return pagination.CursorPaginateInt(query, &items, cursor, size, r.cfg.CursorField, !r.cfg.Descending, r.options(ctx)...)
+}
@@ -0,0 +1,159 @@
+package paginationrepo
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"{{packageImportPath}}/pagination"
+)
+
+type invoice struct {
+	ID     int64
+	Status string
+	Amount int
+}
+
+// paid and byAmount are the specs the service below composes
+var (
+	paid     = Where(func(i invoice) bool { return i.Status == "paid" }, "status = ?", "paid")
+	byAmount = OrderBy("amount", true, func(a, b invoice) bool { return a.Amount > b.Amount })
+)
+
+// invoiceService is a service layer that only knows the repository interface
+type invoiceService struct {
+	invoices PaginatedRepository[invoice]
+}
+
+func (s *invoiceService) LargestPaid(ctx context.Context, params pagination.PaginationParams) (*pagination.OffsetPagination[invoice], error) {
+	return s.invoices.FindPage(ctx, params, paid, byAmount, Preload[invoice]("Customer"))
+}
+
+// ids lists the IDs of invoices
+func ids(invoices []invoice) []int64 {
+	var out []int64
+	for _, i := range invoices {
+		out = append(out, i.ID)
+	}
+	return out
+}
+
+func TestMemoryRepositoryBehindService(t *testing.T) {
+	repo := NewMemoryRepository([]invoice{
+		{ID: 1, Status: "paid", Amount: 10},
+		{ID: 2, Status: "open", Amount: 99},
+		{ID: 3, Status: "paid", Amount: 30},
+		{ID: 4, Status: "paid", Amount: 20},
+	})
+	service := &invoiceService{invoices: repo}
+
+	params := pagination.PaginationParams{Page: 0, PageSize: 2, Indexing: pagination.ZeroBased}
+	page, err := service.LargestPaid(context.Background(), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(page.Items); !reflect.DeepEqual(got, []int64{3, 4}) {
+		t.Errorf("first page = %v, want the two largest paid invoices", got)
+	}
+	if page.CurrentPage != 0 || page.TotalItems != 3 || page.TotalPages != 2 || !page.HasNext || page.HasPrevious {
+		t.Errorf("first page metadata %+v", page)
+	}
+
+	params.Page = 1
+	page, err = service.LargestPaid(context.Background(), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(page.Items); !reflect.DeepEqual(got, []int64{1}) || page.HasNext {
+		t.Errorf("second page = %v (has next %t), want the last paid invoice", got, page.HasNext)
+	}
+}
+
+func TestMemoryRepositoryFindAfter(t *testing.T) {
+	repo := NewMemoryRepository([]invoice{
+		{ID: 1, Status: "paid"},
+		{ID: 2, Status: "open"},
+		{ID: 3, Status: "paid"},
+		{ID: 4, Status: "paid"},
+	})
+
+	var seen []int64
+	cursor := ""
+	for pages := 0; pages < 5; pages++ {
+		result, err := repo.FindAfter(context.Background(), cursor, 2, paid, byAmount)
+		if err != nil {
+			t.Fatal(err)
+		}
+		seen = append(seen, ids(result.Items)...)
+		if !result.HasNext {
+			break
+		}
+		cursor = *result.NextCursor
+	}
+	if want := []int64{1, 3, 4}; !reflect.DeepEqual(seen, want) {
+		t.Errorf("walked %v, want paid invoices in stored order %v", seen, want)
+	}
+
+	if _, err := repo.FindAfter(context.Background(), "!!!", 2); err == nil {
+		t.Error("malformed cursor: want an error")
+	}
+}
+
+func TestGormRepositoryAppliesSpecs(t *testing.T) {
+	db, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var orders [][]string
+	var preloads []string
+	err = db.Callback().Query().Register("test:invoices", func(tx *gorm.DB) {
+		switch dest := tx.Statement.Dest.(type) {
+		case *[]invoice:
+			var columns []string
+			if c, ok := tx.Statement.Clauses["ORDER BY"]; ok {
+				for _, column := range c.Expression.(clause.OrderBy).Columns {
+					columns = append(columns, column.Column.Name)
+				}
+			}
+			orders = append(orders, columns)
+			for association := range tx.Statement.Preloads {
+				preloads = append(preloads, association)
+			}
+			*dest = append((*dest)[:0], invoice{ID: 3, Status: "paid"})
+		case *int64:
+			*dest = 1
+			tx.RowsAffected = 1
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	service := &invoiceService{invoices: NewGormRepository[invoice](db, Config{})}
+	page, err := service.LargestPaid(context.Background(), pagination.PaginationParams{Page: 1, PageSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 1 || page.TotalItems != 1 {
+		t.Fatalf("page %+v", page)
+	}
+	if len(orders) != 1 || !reflect.DeepEqual(orders[0], []string{"amount DESC"}) {
+		t.Errorf("FindPage ordered by %v, want the ordering spec", orders)
+	}
+	if !reflect.DeepEqual(preloads, []string{"Customer"}) {
+		t.Errorf("preloads = %v, want Customer", preloads)
+	}
+
+	// FindAfter keeps its cursor order and drops ordering specs
+	repo := NewGormRepository[invoice](db, Config{Descending: true})
+	if _, err := repo.FindAfter(context.Background(), "", 10, paid, byAmount); err != nil {
+		t.Fatal(err)
+	}
+	if last := orders[len(orders)-1]; !reflect.DeepEqual(last, []string{"id DESC"}) {
+		t.Errorf("FindAfter ordered by %v, want only the cursor field", last)
+	}
+}
@@ -0,0 +1,362 @@
+package pagination
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrNoPartitions is returned when PartitionedCursorPaginate is given no partitions
+var ErrNoPartitions = errors.New("no partitions to paginate")
+
+// Partition is a range partition of a table, holding the rows whose partition key is at least
+// From (nil = no lower bound) and below the next partition's From
+type Partition struct {
+	// Name identifies the partition inside cursors, so partitions may be added around it
+	Name string
+	From any
+}
+
+// partitionCursor is the last row a PartitionedCursorPaginate page served and its partition
+type partitionCursor struct {
+	Partition string `json:"k"`
+	Value     any    `json:"v"`
+	Tie       any    `json:"t,omitempty"`
+}
+
+// partitionFetch returns up to limit rows of partition i in page order, past p when it is set
+type partitionFetch[T any] func(i int, p *partitionCursor, limit int) ([]T, error)
+
+// MonthlyPartitions describes months monthly partitions starting with the month of from,
+// named by formatting each month's first day with layout (e.g. "events_2006_01")
+//
+// Example usage:
+//
+//	// events_2024_01 … events_2024_12
+//	var eventPartitions = pagination.MonthlyPartitions("events_2006_01", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 12)
+func MonthlyPartitions(layout string, from time.Time, months int) []Partition {
+	start := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, from.Location())
+	partitions := make([]Partition, months)
+	for i := range partitions {
+		month := start.AddDate(0, i, 0)
+		partitions[i] = Partition{Name: month.Format(layout), From: month}
+	}
+	return partitions
+}
+
+// PostgresPartitions reads the range partitions of table from the Postgres catalog, ordered by
+// their lower bound
+// Only single-column range partitions are described; a DEFAULT partition has no range and is
+// left out, but its rows are still served (see PartitionedCursorPaginate).
+//
+// Example usage:
+//
+//	partitions, err := pagination.PostgresPartitions(ctx, db, "events")
+func PostgresPartitions(ctx context.Context, db *gorm.DB, table string) ([]Partition, error) {
+	var rows []struct {
+		Name  string
+		Bound string
+	}
+	err := db.Session(&gorm.Session{NewDB: true}).WithContext(ctx).Raw(`
+		SELECT c.relname AS name, pg_get_expr(c.relpartbound, c.oid) AS bound
+		FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = ?::regclass`, table).Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to read partitions of %s: %w", table, err)
+	}
+
+	partitions := make([]Partition, 0, len(rows))
+	for _, row := range rows {
+		from, ok := partitionLowerBound(row.Bound)
+		if !ok {
+			continue
+		}
+		partitions = append(partitions, Partition{Name: row.Name, From: from})
+	}
+	sort.SliceStable(partitions, func(i, j int) bool {
+		return compareKeys(partitions[i].From, partitions[j].From) < 0
+	})
+	return partitions, nil
+}
+
+// partitionLowerBound parses the FROM value of a range partition bound such as
+// "FOR VALUES FROM ('2024-01-01 00:00:00') TO ('2024-02-01 00:00:00')"
+// MINVALUE is nil; ok is false for DEFAULT and bounds of other shapes.
+func partitionLowerBound(bound string) (from any, ok bool) {
+	const prefix = "FOR VALUES FROM ("
+	if !strings.HasPrefix(bound, prefix) {
+		return nil, false
+	}
+	end := strings.Index(bound, ") TO (")
+	if end < 0 {
+		return nil, false
+	}
+	value := bound[len(prefix):end]
+	if strings.Contains(value, ",") {
+		return nil, false
+	}
+
+	switch {
+	case value == "MINVALUE":
+		return nil, true
+	case strings.HasPrefix(value, "'"):
+		return strings.ReplaceAll(strings.Trim(value, "'"), "''", "'"), true
+	}
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return n, true
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return f, true
+	}
+	return nil, false
+}
+
+// PartitionedCursorPaginate pages a range-partitioned table one partition at a time
+// partitions are ordered by From; every page query is bounded to one partition's range on
+// partitionKey, so the planner prunes the others. Rows are served partition by partition
+// (last partition first when descending), ordered by cursorField (then the tie-breaker) within
+// each; when a partition runs out the page continues in the next, skipping empty ones. The
+// cursor records the partition it stopped in, so partitions may be added before or after it;
+// a cursor whose partition is gone restarts from the first page with Restarted set.
+// The first partition has no lower bound and the last no upper bound, so rows below, above,
+// or between the described ranges (a DEFAULT partition's) are still served. Pages move
+// forward only: PreviousCursor is never set, and WithRowIDTieBreaker is rejected.
+//
+// Example usage:
+//
+//	func ListEvents(c *gin.Context) {
+//	    var events []Event
+//	    result, err := pagination.PartitionedCursorPaginate(
+//	        db.Model(&Event{}),
+//	        eventPartitions,
+//	        "created_at", // partition key
+//	        &events,
+//	        c.Query("cursor"),
+//	        50,
+//	        "id",
+//	        true,
+//	    )
+//	    if err != nil {
+//	        c.JSON(400, gin.H{"error": err.Error()})
+//	        return
+//	    }
+//	    c.JSON(200, result)
+//	}
+func PartitionedCursorPaginate[T any](
+	db *gorm.DB,
+	partitions []Partition,
+	partitionKey string,
+	dest *[]T,
+	cursor string,
+	pageSize int,
+	cursorField string,
+	ascending bool,
+	opts ...Option,
+) (*CursorPagination[T], error) {
+	if len(partitions) == 0 {
+		return nil, ErrNoPartitions
+	}
+	o := applyOptions(opts)
+	db = o.bindContext(db).Session(&gorm.Session{})
+
+	if err := checkDestType[T](db); err != nil {
+		return nil, err
+	}
+
+	// Constrain page size
+	if limit := queryMaxPageSize(db); pageSize > limit {
+		pageSize = limit
+	}
+	if pageSize < 1 {
+		pageSize = o.config.DefaultPageSize
+	}
+
+	tie, err := resolveTieBreaker[T](db, cursorField, o)
+	if err != nil {
+		return nil, err
+	}
+	if tie != nil && tie.field == nil {
+		return nil, fmt.Errorf("%w: rowid cannot be recorded in partition cursors", ErrTieBreakerRequired)
+	}
+
+	fetch := func(i int, p *partitionCursor, limit int) ([]T, error) {
+		query := db.Session(&gorm.Session{})
+		if i > 0 {
+			query = query.Where(partitionKey+" >= ?", partitions[i].From)
+		}
+		if i < len(partitions)-1 {
+			query = query.Where(partitionKey+" < ?", partitions[i+1].From)
+		}
+		if p != nil {
+			if tie != nil && p.Tie != nil {
+				query = query.Where(tieBreakerCondition(cursorField, tie.column, ascending, false), p.Value, p.Value, p.Tie)
+			} else {
+				query = query.Where(cursorCondition(cursorField, ascending, false), p.Value)
+			}
+		}
+		query = query.Order(tieBreakerOrder(cursorField, ascending))
+		if tie != nil {
+			query = query.Order(tieBreakerOrder(tie.column, ascending))
+		}
+
+		var rows []T
+		err := o.fetchLimiter.do(queryContext(query), func() error {
+			return o.onFetchDB(query).Limit(sqlLimit(query, limit)).Find(&rows).Error
+		})
+		return rows, err
+	}
+
+	return paginatePartitions(db, fetch, partitions, dest, cursor, pageSize, cursorField, tie, ascending, o)
+}
+
+// paginatePartitions builds a PartitionedCursorPaginate page over partitions read through fetch
+func paginatePartitions[T any](
+	db *gorm.DB,
+	fetch partitionFetch[T],
+	partitions []Partition,
+	dest *[]T,
+	cursor string,
+	pageSize int,
+	cursorField string,
+	tie *tieBreaker,
+	ascending bool,
+	o options,
+) (*CursorPagination[T], error) {
+	key, err := newFieldExtractor[T](db, cursorField)
+	if err != nil {
+		return nil, err
+	}
+
+	position, restarted, err := decodePartitionCursor(cursor, o)
+	if err != nil {
+		return nil, err
+	}
+
+	// Walk the partitions in page order, from the cursor's
+	order := make([]int, len(partitions))
+	for i := range order {
+		order[i] = i
+		if !ascending {
+			order[i] = len(partitions) - 1 - i
+		}
+	}
+	start := 0
+	if position != nil {
+		start = -1
+		for step, i := range order {
+			if partitions[i].Name == position.Partition {
+				start = step
+			}
+		}
+		// The cursor's partition was dropped or renamed; its position no longer exists
+		if start < 0 {
+			position, restarted, start = nil, true, 0
+		}
+	}
+
+	// Fetch one row past the page, rolling into the next partition when one runs out
+	var rows []T
+	var owners []int
+	for step := start; step < len(order) && len(rows) <= pageSize; step++ {
+		i := order[step]
+		var after *partitionCursor
+		if step == start {
+			after = position
+		}
+		fetched, err := fetch(i, after, pageSize+1-len(rows))
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch items from partition %s: %w", partitions[i].Name, err)
+		}
+		rows = append(rows, fetched...)
+		for range fetched {
+			owners = append(owners, i)
+		}
+	}
+
+	hasNext := len(rows) > pageSize
+	if hasNext {
+		rows = rows[:pageSize]
+	}
+	items := nonNilItems(rows)
+	*dest = items
+
+	result := &CursorPagination[T]{
+		Items:       items,
+		HasNext:     hasNext,
+		HasPrevious: cursor != "" && !restarted,
+		PageSize:    pageSize,
+		Restarted:   restarted,
+	}
+	if len(items) > 0 {
+		ctx := queryContext(db)
+		last := items[len(items)-1]
+		result.FirstKey = key.value(ctx, items[0])
+		result.LastKey = key.value(ctx, last)
+
+		after := partitionCursor{Partition: partitions[owners[len(items)-1]].Name, Value: result.LastKey}
+		if tie != nil {
+			after.Tie = tie.field.value(ctx, last)
+		}
+		endCursor, err := encodePartitionCursor(after, o)
+		if err != nil {
+			return nil, err
+		}
+		result.EndCursor = &endCursor
+		if hasNext {
+			nextCursor := endCursor
+			result.NextCursor = &nextCursor
+		}
+	}
+	return result, nil
+}
+
+// encodePartitionCursor encodes a partition position as base64url JSON wrapped by the
+// paginator's codec
+func encodePartitionCursor(c partitionCursor, o options) (string, error) {
+	token, err := JSONCursorCodec{}.Encode(c)
+	if err != nil {
+		return "", err
+	}
+	return o.cursorCodec().Encode(token)
+}
+
+// decodePartitionCursor reverses encodePartitionCursor (nil for an empty cursor)
+// restarted reports a codec asking to start over.
+func decodePartitionCursor(cursor string, o options) (c *partitionCursor, restarted bool, err error) {
+	value, restarted, err := decodeCursor(cursor, o)
+	if err != nil || restarted || cursor == "" {
+		return nil, restarted, err
+	}
+
+	token, err := cursorString(value)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	c = &partitionCursor{}
+	if err := decoder.Decode(c); err != nil {
+		return nil, false, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	if c.Partition == "" || c.Value == nil {
+		return nil, false, fmt.Errorf("%w: missing partition position", ErrInvalidCursor)
+	}
+
+	c.Value = jsonNumber(c.Value)
+	c.Tie = jsonNumber(c.Tie)
+	return c, false, nil
+}
@@ -0,0 +1,213 @@
+package pagination
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// idPartitions splits events by id at 10, 20, and 30; events_10 is empty
+var idPartitions = []Partition{
+	{Name: "events_0", From: nil},
+	{Name: "events_10", From: int64(10)},
+	{Name: "events_20", From: int64(20)},
+	{Name: "events_30", From: int64(30)},
+}
+
+// eventsWithIDs returns an event per id
+func eventsWithIDs(ids ...int64) []event {
+	events := make([]event, len(ids))
+	for i, id := range ids {
+		events[i].ID = id
+	}
+	return events
+}
+
+// fakePartitionFetch serves the events of partition i past p the way the partition query
+// bounds and orders them; fetches lists the partitions queried
+func fakePartitionFetch(partitions []Partition, events []event, ascending bool, fetches *[]string) partitionFetch[event] {
+	return func(i int, p *partitionCursor, limit int) ([]event, error) {
+		*fetches = append(*fetches, partitions[i].Name)
+		var rows []event
+		for j := range events {
+			row := events[j]
+			if !ascending {
+				row = events[len(events)-1-j]
+			}
+			if i > 0 && row.ID < partitions[i].From.(int64) {
+				continue
+			}
+			if i < len(partitions)-1 && row.ID >= partitions[i+1].From.(int64) {
+				continue
+			}
+			if p != nil && ((ascending && row.ID <= p.Value.(int64)) || (!ascending && row.ID >= p.Value.(int64))) {
+				continue
+			}
+			if len(rows) < limit {
+				rows = append(rows, row)
+			}
+		}
+		return rows, nil
+	}
+}
+
+// walkPartitions pages through every partition and returns the ids of each page
+func walkPartitions(t *testing.T, fetch partitionFetch[event], partitions []Partition, pageSize int, ascending bool) [][]int64 {
+	t.Helper()
+	db, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	o := applyOptions(nil)
+
+	var seen [][]int64
+	cursor := ""
+	for pages := 0; pages < 10; pages++ {
+		var events []event
+		result, err := paginatePartitions(db, fetch, partitions, &events, cursor, pageSize, "id", nil, ascending, o)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []int64
+		for _, e := range result.Items {
+			ids = append(ids, e.ID)
+		}
+		seen = append(seen, ids)
+
+		if !result.HasNext {
+			if result.NextCursor != nil {
+				t.Errorf("last page has next cursor %q", *result.NextCursor)
+			}
+			break
+		}
+		cursor = *result.NextCursor
+	}
+	return seen
+}
+
+func TestPartitionedPagesRollOverEmptyPartitions(t *testing.T) {
+	events := eventsWithIDs(1, 2, 3, 21, 22, 31)
+	var fetches []string
+	fetch := fakePartitionFetch(idPartitions, events, true, &fetches)
+
+	seen := walkPartitions(t, fetch, idPartitions, 2, true)
+	want := [][]int64{
+		{1, 2},
+		{3, 21},
+		{22, 31},
+	}
+	if !reflect.DeepEqual(seen, want) {
+		t.Fatalf("partitioned pages = %v, want %v", seen, want)
+	}
+
+	// The first page stops inside events_0 without touching the later partitions
+	if fetches[0] != "events_0" || fetches[1] != "events_0" {
+		t.Errorf("fetches = %v, want the first page served by events_0 alone", fetches)
+	}
+}
+
+func TestPartitionedPageEndingAtPartitionBoundary(t *testing.T) {
+	events := eventsWithIDs(1, 2, 3, 21, 22, 31)
+	var fetches []string
+	fetch := fakePartitionFetch(idPartitions, events, true, &fetches)
+
+	// Page one ends with the last row of events_0; the next page must still be announced
+	seen := walkPartitions(t, fetch, idPartitions, 3, true)
+	want := [][]int64{
+		{1, 2, 3},
+		{21, 22, 31},
+	}
+	if !reflect.DeepEqual(seen, want) {
+		t.Fatalf("partitioned pages = %v, want %v", seen, want)
+	}
+
+	// Trailing empty partitions do not leave a next page behind
+	seen = walkPartitions(t, fakePartitionFetch(idPartitions, events[:3], true, &fetches), idPartitions, 3, true)
+	if want := [][]int64{
+		{1, 2, 3},
+	}; !reflect.DeepEqual(seen, want) {
+		t.Fatalf("pages with only events_0 filled = %v, want %v", seen, want)
+	}
+}
+
+func TestPartitionedPagesDescending(t *testing.T) {
+	events := eventsWithIDs(1, 2, 3, 21, 22, 31)
+	var fetches []string
+	fetch := fakePartitionFetch(idPartitions, events, false, &fetches)
+
+	seen := walkPartitions(t, fetch, idPartitions, 4, false)
+	want := [][]int64{
+		{31, 22, 21, 3},
+		{2, 1},
+	}
+	if !reflect.DeepEqual(seen, want) {
+		t.Fatalf("descending pages = %v, want %v", seen, want)
+	}
+	if fetches[0] != "events_30" {
+		t.Errorf("first fetch from %s, want the last partition", fetches[0])
+	}
+}
+
+func TestPartitionedCursorRestartsWhenPartitionDropped(t *testing.T) {
+	db, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	o := applyOptions(nil)
+	events := eventsWithIDs(1, 2, 3, 21, 22)
+	var fetches []string
+	fetch := fakePartitionFetch(idPartitions, events, true, &fetches)
+
+	var page []event
+	first, err := paginatePartitions(db, fetch, idPartitions, &page, "", 2, "id", nil, true, o)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// events_0 was detached with its rows: its cursor starts the remaining partitions over
+	remaining := idPartitions[1:]
+	fetch = fakePartitionFetch(remaining, events[3:], true, &fetches)
+	result, err := paginatePartitions(db, fetch, remaining, &page, *first.NextCursor, 2, "id", nil, true, o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Restarted || result.HasPrevious || len(result.Items) != 2 || result.Items[0].ID != 21 {
+		t.Errorf("dropped partition = %+v, want a restarted first page", result)
+	}
+}
+
+func TestPartitionLowerBound(t *testing.T) {
+	cases := []struct {
+		bound string
+		from  any
+		ok    bool
+	}{
+		{"FOR VALUES FROM ('2024-01-01 00:00:00') TO ('2024-02-01 00:00:00')", "2024-01-01 00:00:00", true},
+		{"FOR VALUES FROM (MINVALUE) TO (100)", nil, true},
+		{"FOR VALUES FROM (100) TO (200)", int64(100), true},
+		{"FOR VALUES FROM (1, 'a') TO (2, 'b')", nil, false},
+		{"DEFAULT", nil, false},
+	}
+	for _, tc := range cases {
+		from, ok := partitionLowerBound(tc.bound)
+		if ok != tc.ok || from != tc.from {
+			t.Errorf("%s: from %v (ok %t), want %v (ok %t)", tc.bound, from, ok, tc.from, tc.ok)
+		}
+	}
+}
+
+func TestMonthlyPartitions(t *testing.T) {
+	partitions := MonthlyPartitions("events_2006_01", time.Date(2024, 11, 15, 0, 0, 0, 0, time.UTC), 3)
+	var names []string
+	for _, p := range partitions {
+		names = append(names, p.Name)
+	}
+	if want := []string{"events_2024_11", "events_2024_12", "events_2025_01"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("names = %v, want %v", names, want)
+	}
+	if from := partitions[0].From.(time.Time); from.Day() != 1 {
+		t.Errorf("first partition starts %s, want the first of the month", from)
+	}
+}
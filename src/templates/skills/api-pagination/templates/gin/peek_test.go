@@ -0,0 +1,64 @@
+package pagination
+
+import (
+	"testing"
+)
+
+func TestPeekPreviewsNextPage(t *testing.T) {
+	db := postsDB(t, postsOf(5, 10))
+
+	var page []post
+	result, err := CursorPaginateInt(db, &page, "", 2, "id", true, WithPeek())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Items) != 2 || result.NextPreview == nil {
+		t.Fatalf("%d items, preview %v, want 2 items and a preview", len(result.Items), result.NextPreview)
+	}
+
+	next, err := CursorPaginateInt(db, &page, *result.EndCursor, 2, "id", true, WithPeek())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *result.NextPreview != next.Items[0] {
+		t.Errorf("preview = %+v, want the next page's first item %+v", *result.NextPreview, next.Items[0])
+	}
+	if response := result.ToResponse(""); response.NextPreview == nil || response.NextPreview.ID != 3 {
+		t.Errorf("response preview = %v, want post 3", response.NextPreview)
+	}
+
+	last, err := CursorPaginateInt(db, &page, *next.EndCursor, 2, "id", true, WithPeek())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last.HasNext || last.NextPreview != nil {
+		t.Errorf("last page has next %t, preview %v, want neither", last.HasNext, last.NextPreview)
+	}
+}
+
+func TestPeekOffByDefault(t *testing.T) {
+	db := postsDB(t, postsOf(5, 10))
+
+	var page []post
+	result, err := CursorPaginateInt(db, &page, "", 2, "id", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.HasNext || result.NextPreview != nil {
+		t.Errorf("preview %v without WithPeek", result.NextPreview)
+	}
+}
+
+func TestPeekAfterByteBudget(t *testing.T) {
+	db := postsDB(t, postsOf(10, 300))
+
+	// The budget keeps 3 rows, so the preview is the first row it left out
+	var page []post
+	result, err := CursorPaginateInt(db, &page, "", 20, "id", true, WithByteBudget(EnvelopeBytes+1000, 1), WithPeek())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Items) != 3 || result.NextPreview == nil || result.NextPreview.ID != 4 {
+		t.Errorf("%d items, preview %v, want 3 items and post 4", len(result.Items), result.NextPreview)
+	}
+}
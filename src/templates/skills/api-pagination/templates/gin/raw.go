@@ -0,0 +1,88 @@
+package pagination
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// ErrRawOrderRequired is returned when CursorPaginateRaw is given no order clause
+var ErrRawOrderRequired = errors.New("raw cursor pagination needs an order clause")
+
+// CursorPaginateRaw pages db past a caller-written boundary, for seeks the structured
+// paginators cannot express (row-value comparisons, expressions, mixed directions)
+// boundaryClause is the WHERE condition past the previous page with args as its placeholders
+// ("" on the first page), and orderClause the ORDER BY it seeks along; it must order by a
+// unique key, and agree with the boundary, or rows repeat or go missing between pages.
+//
+// The clauses are used verbatim: never build them from request input, bind values through
+// args. The result carries the page, HasNext (fetched with one extra row) and HasPrevious, but
+// no cursors; build the next boundary's args from the last item, and encode them however the
+// API's cursors look.
+//
+// Example usage:
+//
+//	// Leaderboard by score, then id, both descending
+//	boundary, args := "", []any(nil)
+//	if last != nil {
+//	    boundary, args = "(score, id) < (?, ?)", []any{last.Score, last.ID}
+//	}
+//	result, err := pagination.CursorPaginateRaw(db.Model(&Player{}), &players,
+//	    boundary, args, "score DESC, id DESC", 50)
+func CursorPaginateRaw[T any](
+	db *gorm.DB,
+	dest *[]T,
+	boundaryClause string,
+	args []any,
+	orderClause string,
+	pageSize int,
+	opts ...Option,
+) (*CursorPagination[T], error) {
+	if orderClause == "" {
+		return nil, ErrRawOrderRequired
+	}
+	o := applyOptions(opts)
+	db = o.bindContext(db)
+
+	if err := checkDestType[T](db); err != nil {
+		return nil, err
+	}
+
+	// Constrain page size
+	if limit := queryMaxPageSize(db); pageSize > limit {
+		pageSize = limit
+	}
+	if pageSize < 1 {
+		pageSize = o.config.DefaultPageSize
+	}
+
+	query := db.Session(&gorm.Session{})
+	if boundaryClause != "" {
+		query = query.Where(boundaryClause, args...)
+	}
+	query = query.Order(orderClause)
+
+	// Fetch one extra item to check for next page
+	var items []T
+	err := o.fetchLimiter.do(queryContext(query), func() error {
+		return o.onFetchDB(query).Limit(sqlLimit(query, pageSize+1)).Find(&items).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch items: %w", err)
+	}
+
+	hasNext := len(items) > pageSize
+	if hasNext {
+		items = items[:pageSize]
+	}
+	items = nonNilItems(items)
+	*dest = items
+
+	return &CursorPagination[T]{
+		Items:       items,
+		HasNext:     hasNext,
+		HasPrevious: boundaryClause != "",
+		PageSize:    pageSize,
+	}, nil
+}
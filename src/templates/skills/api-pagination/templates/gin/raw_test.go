@@ -0,0 +1,109 @@
+package pagination
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// player is a leaderboard row, ordered by score then id, both descending
+type player struct {
+	ID    int64
+	Score int
+}
+
+// leaderboardDB serves players past a (score, id) < (?, ?) boundary, checking the clauses
+// arrive verbatim
+func leaderboardDB(t *testing.T, players []player) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Callback().Query().Register("test:raw", func(tx *gorm.DB) {
+		dest, ok := tx.Statement.Dest.(*[]player)
+		if !ok {
+			return
+		}
+		if order := tx.Statement.Clauses["ORDER BY"].Expression.(clause.OrderBy).Columns[0].Column.Name; order != "score DESC, id DESC" {
+			t.Errorf("order = %q", order)
+		}
+
+		past := func(p player) bool { return true }
+		if c, ok := tx.Statement.Clauses["WHERE"]; ok {
+			expr := c.Expression.(clause.Where).Exprs[0].(clause.Expr)
+			if expr.SQL != "(score, id) < (?, ?)" {
+				t.Errorf("boundary = %q", expr.SQL)
+			}
+			score, id := expr.Vars[0].(int), expr.Vars[1].(int64)
+			past = func(p player) bool { return p.Score < score || (p.Score == score && p.ID < id) }
+		}
+
+		limit := *tx.Statement.Clauses["LIMIT"].Expression.(clause.Limit).Limit
+		for _, p := range players {
+			if past(p) && len(*dest) < limit {
+				*dest = append(*dest, p)
+			}
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db.Model(&player{})
+}
+
+func TestCursorPaginateRawSeeksPastBoundary(t *testing.T) {
+	// Already in leaderboard order; players 4 and 2 tie on score
+	db := leaderboardDB(t, []player{
+		{ID: 5, Score: 90},
+		{ID: 4, Score: 70},
+		{ID: 2, Score: 70},
+		{ID: 3, Score: 40},
+		{ID: 1, Score: 10},
+	})
+
+	var pages [][]int64
+	boundary, args := "", []any(nil)
+	for i := 0; i < 5; i++ {
+		var players []player
+		result, err := CursorPaginateRaw(db, &players, boundary, args, "score DESC, id DESC", 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.HasPrevious != (boundary != "") {
+			t.Errorf("page %d has previous = %t", i, result.HasPrevious)
+		}
+
+		var ids []int64
+		for _, p := range result.Items {
+			ids = append(ids, p.ID)
+		}
+		pages = append(pages, ids)
+		if !result.HasNext {
+			break
+		}
+		last := result.Items[len(result.Items)-1]
+		boundary, args = "(score, id) < (?, ?)", []any{last.Score, last.ID}
+	}
+
+	// The tie on score is split across pages without repeating or skipping a player
+	want := [][]int64{
+		{5, 4},
+		{2, 3},
+		{1},
+	}
+	if !reflect.DeepEqual(pages, want) {
+		t.Errorf("pages = %v, want %v", pages, want)
+	}
+}
+
+func TestCursorPaginateRawRequiresOrder(t *testing.T) {
+	db := leaderboardDB(t, nil)
+	var players []player
+	if _, err := CursorPaginateRaw(db, &players, "", nil, "", 10); !errors.Is(err, ErrRawOrderRequired) {
+		t.Errorf("err = %v, want ErrRawOrderRequired", err)
+	}
+}
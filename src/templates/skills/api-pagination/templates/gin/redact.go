@@ -0,0 +1,314 @@
+package pagination
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RedactTag is the struct tag naming a field's visibility class, e.g. `redact:"pii"`
+// Append ",omit" to zero the field instead of masking it; with a json omitempty tag the field
+// then leaves the response.
+const RedactTag = "redact"
+
+// RedactedMask replaces the value of masked string fields; other masked fields are zeroed
+const RedactedMask = "[redacted]"
+
+// RedactionPolicy reports whether fields of class tag are hidden from the request's caller,
+// typically by checking its auth claims
+// It is asked once per class per response.
+type RedactionPolicy func(c *gin.Context, tag string) bool
+
+// WithRedaction masks the fields of the response's items that policy hides from c's caller
+// The items are copied before they are masked, so cached or shared pages are left intact. Fields
+// are found through RedactTag tags and RegisterRedaction, in nested structs, slices, arrays
+// and through pointers; unexported fields and map values are not inspected.
+//
+// Example usage:
+//
+//	type Customer struct {
+//	    ID    int64  `json:"id"`
+//	    Email string `json:"email" redact:"pii"`
+//	    TaxID string `json:"tax_id,omitempty" redact:"finance,omit"`
+//	}
+//
+//	hide := func(c *gin.Context, tag string) bool {
+//	    return !auth.Claims(c).Has("read:" + tag)
+//	}
+//	c.JSON(200, result.ToResponse(c.Request.URL.Path, pagination.WithRedaction(c, hide)))
+func WithRedaction(c *gin.Context, policy RedactionPolicy) ResponseOption {
+	return func(o *responseOptions) {
+		if policy != nil {
+			o.hidden = policy.decisions(c)
+		}
+	}
+}
+
+// Redact returns items with the fields policy hides from c's caller masked, for handlers that
+// do not render through ToResponse
+// items is returned as is when nothing is hidden, and copied otherwise.
+func Redact[T any](c *gin.Context, policy RedactionPolicy, items []T) []T {
+	if policy == nil {
+		return items
+	}
+	return redactItems(items, policy.decisions(c))
+}
+
+// decisions asks the policy about each class once, remembering its answers
+func (p RedactionPolicy) decisions(c *gin.Context) func(tag string) bool {
+	decided := map[string]bool{}
+	return func(tag string) bool {
+		hidden, ok := decided[tag]
+		if !ok {
+			hidden = p(c, tag)
+			decided[tag] = hidden
+		}
+		return hidden
+	}
+}
+
+// withRedaction masks the response's items when the options hide any of their fields
+func (r *PaginatedResponse[T]) withRedaction(o responseOptions) {
+	if o.hidden != nil {
+		r.Data = redactItems(r.Data, o.hidden)
+		if r.NextPreview != nil {
+			preview := redactItems([]T{*r.NextPreview}, o.hidden)[0]
+			r.NextPreview = &preview
+		}
+	}
+}
+
+// RegisterRedaction gives T's field (its Go name) the visibility class tag, in RedactTag's
+// syntax, for types whose struct tags cannot be changed (e.g. generated models)
+// Register at init: it overrides the field's own tag and resets the cached plans.
+func RegisterRedaction[T any](field, tag string) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+
+	redactionRegistryMu.Lock()
+	if redactionRegistry[t] == nil {
+		redactionRegistry[t] = map[string]string{}
+	}
+	redactionRegistry[t][field] = tag
+	redactionRegistryMu.Unlock()
+
+	redactionPlans.Range(func(key, _ any) bool {
+		redactionPlans.Delete(key)
+		return true
+	})
+}
+
+var (
+	// redactionRegistry holds RegisterRedaction's classes by type and field name
+	redactionRegistryMu sync.RWMutex
+	redactionRegistry   = map[reflect.Type]map[string]string{}
+
+	// redactionPlans caches the *redactedType of each item type
+	redactionPlans sync.Map
+)
+
+// redactedType is an item type's plan and every class its fields use
+type redactedType struct {
+	plan *redactionPlan
+	tags []string
+}
+
+// redactionPlan is how to redact a value of one type: the fields of a struct, or the element
+// of a pointer, slice, or array
+// Types with nothing to redact have no plan (nil).
+type redactionPlan struct {
+	fields []redactedField
+	elem   *redactionPlan
+}
+
+// redactedField is a struct field that is redacted itself, holds redacted fields, or both
+type redactedField struct {
+	index int
+
+	// class is the field's visibility class ("" = only nested fields are redacted), and omit
+	// zeroes instead of masking it
+	class string
+	omit  bool
+
+	nested *redactionPlan
+}
+
+// redactionFor returns the cached plan of t, building it on first use
+func redactionFor(t reflect.Type) *redactedType {
+	if cached, ok := redactionPlans.Load(t); ok {
+		return cached.(*redactedType)
+	}
+
+	plan := buildRedactionPlan(t, map[reflect.Type]*redactionPlan{})
+	tagSet := map[string]bool{}
+	redactionTags(t, tagSet, map[reflect.Type]bool{})
+	rt := &redactedType{plan: plan}
+	for tag := range tagSet {
+		rt.tags = append(rt.tags, tag)
+	}
+
+	cached, _ := redactionPlans.LoadOrStore(t, rt)
+	return cached.(*redactedType)
+}
+
+// buildRedactionPlan builds t's plan; building holds the structs being built, so recursive
+// types refer back to their own plan
+func buildRedactionPlan(t reflect.Type, building map[reflect.Type]*redactionPlan) *redactionPlan {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		elem := buildRedactionPlan(t.Elem(), building)
+		if elem == nil {
+			return nil
+		}
+		return &redactionPlan{elem: elem}
+
+	case reflect.Struct:
+		if plan, ok := building[t]; ok {
+			return plan
+		}
+		plan := &redactionPlan{}
+		building[t] = plan
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			class, omit := fieldRedaction(t, f)
+			nested := buildRedactionPlan(f.Type, building)
+			if class != "" || nested != nil {
+				plan.fields = append(plan.fields, redactedField{index: i, class: class, omit: omit, nested: nested})
+			}
+		}
+		if len(plan.fields) == 0 {
+			return nil
+		}
+		return plan
+	}
+	return nil
+}
+
+// redactionTags collects the classes used by t's fields, nested ones included
+func redactionTags(t reflect.Type, tags map[string]bool, seen map[reflect.Type]bool) {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		redactionTags(t.Elem(), tags, seen)
+	case reflect.Struct:
+		if seen[t] {
+			return
+		}
+		seen[t] = true
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			if class, _ := fieldRedaction(t, f); class != "" {
+				tags[class] = true
+			}
+			redactionTags(f.Type, tags, seen)
+		}
+	}
+}
+
+// fieldRedaction returns the class of struct t's field f and whether it is omitted
+func fieldRedaction(t reflect.Type, f reflect.StructField) (string, bool) {
+	redactionRegistryMu.RLock()
+	tag, ok := redactionRegistry[t][f.Name]
+	redactionRegistryMu.RUnlock()
+	if !ok {
+		tag = f.Tag.Get(RedactTag)
+	}
+
+	class, option, _ := strings.Cut(tag, ",")
+	return strings.TrimSpace(class), strings.TrimSpace(option) == "omit"
+}
+
+// redactItems returns items with the classes hidden reports masked
+// Nothing is copied when no class the item type uses is hidden.
+func redactItems[T any](items []T, hidden func(tag string) bool) []T {
+	rt := redactionFor(reflect.TypeOf((*T)(nil)).Elem())
+	if rt.plan == nil {
+		return items
+	}
+	anyHidden := false
+	for _, tag := range rt.tags {
+		if hidden(tag) {
+			anyHidden = true
+			break
+		}
+	}
+	if !anyHidden {
+		return items
+	}
+
+	out := make([]T, len(items))
+	for i := range items {
+		reflect.ValueOf(&out[i]).Elem().Set(rt.plan.apply(reflect.ValueOf(&items[i]).Elem(), hidden))
+	}
+	return out
+}
+
+// apply returns a copy of v with the hidden classes masked; v is not modified
+// Only the values on the way to a redacted field are copied; the rest is shared with v.
+func (p *redactionPlan) apply(v reflect.Value, hidden func(tag string) bool) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type().Elem())
+		out.Elem().Set(p.elem.apply(v.Elem(), hidden))
+		return out
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(p.elem.apply(v.Index(i), hidden))
+		}
+		return out
+
+	case reflect.Array:
+		out := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(p.elem.apply(v.Index(i), hidden))
+		}
+		return out
+
+	case reflect.Struct:
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v)
+		for _, f := range p.fields {
+			field := out.Field(f.index)
+			if f.class != "" && hidden(f.class) {
+				maskField(field, f.omit)
+				continue
+			}
+			if f.nested != nil {
+				field.Set(f.nested.apply(field, hidden))
+			}
+		}
+		return out
+	}
+	return v
+}
+
+// maskField replaces a hidden field's value with RedactedMask, or zeroes it
+func maskField(field reflect.Value, omit bool) {
+	if !omit {
+		switch {
+		case field.Kind() == reflect.String:
+			field.SetString(RedactedMask)
+			return
+		case field.Kind() == reflect.Ptr && field.Type().Elem().Kind() == reflect.String && !field.IsNil():
+			mask := reflect.New(field.Type().Elem())
+			mask.Elem().SetString(RedactedMask)
+			field.Set(mask)
+			return
+		}
+	}
+	field.Set(reflect.Zero(field.Type()))
+}
@@ -0,0 +1,167 @@
+package pagination
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// contactDetails holds personal data masked for callers without the pii class
+type contactDetails struct {
+	Email string  `json:"email" redact:"pii"`
+	Phone *string `json:"phone,omitempty" redact:"pii"`
+	City  string  `json:"city"`
+}
+
+// taxProfile holds finance data, zeroed rather than masked
+type taxProfile struct {
+	TaxID   string `json:"tax_id,omitempty" redact:"finance,omit"`
+	Country string `json:"country"`
+}
+
+// customerRow nests the classes through a struct, a pointer and a slice
+type customerRow struct {
+	ID       int64            `json:"id"`
+	Name     string           `json:"name"`
+	Contact  contactDetails   `json:"contact"`
+	Billing  *contactDetails  `json:"billing,omitempty"`
+	History  []contactDetails `json:"history"`
+	Tax      *taxProfile      `json:"tax,omitempty"`
+	Balance  int              `json:"balance"`
+	Internal string           `json:"internal"`
+}
+
+func init() {
+	RegisterRedaction[customerRow]("Balance", "finance")
+}
+
+// newCustomer returns a customer with every nested field set
+func newCustomer(id int64) customerRow {
+	phone := fmt.Sprintf("555-%04d", id)
+	return customerRow{
+		ID:      id,
+		Name:    fmt.Sprintf("customer %d", id),
+		Contact: contactDetails{Email: fmt.Sprintf("c%d@example.com", id), Phone: &phone, City: "Oslo"},
+		Billing: &contactDetails{Email: "billing@example.com", City: "Bergen"},
+		History: []contactDetails{
+			{Email: "old@example.com", City: "Tromsø"},
+		},
+		Tax:      &taxProfile{TaxID: "NO-123", Country: "NO"},
+		Balance:  1200,
+		Internal: "kept",
+	}
+}
+
+// hideClasses is a policy hiding the given classes
+func hideClasses(classes ...string) RedactionPolicy {
+	return func(c *gin.Context, tag string) bool {
+		for _, class := range classes {
+			if class == tag {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// testContext returns a gin context for a request without claims
+func testContext() *gin.Context {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/customers", nil)
+	return c
+}
+
+func TestRedactMasksHiddenClasses(t *testing.T) {
+	items := []customerRow{newCustomer(1)}
+	original := newCustomer(1)
+
+	page := &OffsetPagination[customerRow]{Items: items, CurrentPage: 1, PageSize: 20}
+	got := page.ToResponse("", WithRedaction(testContext(), hideClasses("pii"))).Data[0]
+
+	if got.Contact.Email != RedactedMask || got.Contact.Phone == nil || *got.Contact.Phone != RedactedMask {
+		t.Errorf("contact = %+v, want email and phone masked", got.Contact)
+	}
+	if got.Billing.Email != RedactedMask || got.History[0].Email != RedactedMask {
+		t.Errorf("billing %+v, history %+v, want emails masked behind pointers and in slices", got.Billing, got.History)
+	}
+	if got.Contact.City != "Oslo" || got.Tax.TaxID != "NO-123" || got.Balance != 1200 || got.Internal != "kept" {
+		t.Errorf("visible fields changed: %+v", got)
+	}
+
+	// The page's own items are never modified
+	if !reflect.DeepEqual(items[0], original) {
+		t.Errorf("items modified in place: %+v", items[0])
+	}
+}
+
+func TestRedactOmitsAndRegisteredFields(t *testing.T) {
+	got := Redact(testContext(), hideClasses("finance"), []customerRow{newCustomer(2)})[0]
+	if got.Tax.TaxID != "" || got.Tax.Country != "NO" {
+		t.Errorf("tax = %+v, want the tax id zeroed for omitempty", got.Tax)
+	}
+	if got.Balance != 0 {
+		t.Errorf("balance = %d, want the registered finance field zeroed", got.Balance)
+	}
+	if got.Contact.Email == RedactedMask {
+		t.Error("pii masked although only finance is hidden")
+	}
+}
+
+func TestRedactAsksPolicyOncePerClass(t *testing.T) {
+	asked := map[string]int{}
+	policy := func(c *gin.Context, tag string) bool {
+		asked[tag]++
+		return false
+	}
+	items := []customerRow{newCustomer(1), newCustomer(2), newCustomer(3)}
+	got := Redact(testContext(), policy, items)
+	if &got[0] != &items[0] {
+		t.Error("items copied although nothing is hidden")
+	}
+	for class, n := range asked {
+		if n != 1 {
+			t.Errorf("policy asked about %s %d times, want once", class, n)
+		}
+	}
+}
+
+// benchmarkPage is a 100-item page of nested customers
+func benchmarkPage() []customerRow {
+	items := make([]customerRow, 100)
+	for i := range items {
+		items[i] = newCustomer(int64(i))
+	}
+	return items
+}
+
+// BenchmarkRedactPage masks pii across a 100-item page, copying each item
+func BenchmarkRedactPage(b *testing.B) {
+	c := testContext()
+	items := benchmarkPage()
+	policy := hideClasses("pii")
+	Redact(c, policy, items)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Redact(c, policy, items)
+	}
+}
+
+// BenchmarkRedactPageVisible measures a caller allowed every class, which skips the copy
+func BenchmarkRedactPageVisible(b *testing.B) {
+	c := testContext()
+	items := benchmarkPage()
+	policy := hideClasses()
+	Redact(c, policy, items)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Redact(c, policy, items)
+	}
+}
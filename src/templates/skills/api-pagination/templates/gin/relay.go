@@ -0,0 +1,106 @@
+package pagination
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RelayConnection is a GraphQL Relay connection over a page of cursor results
+// Field names follow the Relay Cursor Connections spec, so it can be returned from resolvers as-is
+type RelayConnection[T any] struct {
+	Edges    []RelayEdge[T] `json:"edges"`
+	PageInfo RelayPageInfo  `json:"pageInfo"`
+}
+
+// RelayEdge is one node of a connection with its opaque cursor
+type RelayEdge[T any] struct {
+	Node   T      `json:"node"`
+	Cursor string `json:"cursor"`
+}
+
+// RelayPageInfo is the Relay PageInfo type
+// StartCursor and EndCursor are nil on an empty page
+type RelayPageInfo struct {
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	HasNextPage     bool    `json:"hasNextPage"`
+	StartCursor     *string `json:"startCursor"`
+	EndCursor       *string `json:"endCursor"`
+}
+
+// ToRelayConnection converts CursorPagination to a Relay connection
+// cursorOf returns the opaque cursor of one item; pass it to the paginator as the cursor
+// to continue after that item. PageInfo's startCursor and endCursor are the page's
+// StartCursor and EndCursor when the paginator set them, and the first and last edge
+// cursors otherwise.
+//
+// Example usage:
+//
+//	result, err := pagination.CursorPaginateInt(db, &users, after, first, "id", true)
+//	// ...
+//	return result.ToRelayConnection(func(u User) string {
+//	    return pagination.EncodeCursor("id", u.ID, true)
+//	}), nil
+func (p *CursorPagination[T]) ToRelayConnection(cursorOf func(item T) string) RelayConnection[T] {
+	connection := RelayConnection[T]{
+		Edges: make([]RelayEdge[T], len(p.Items)),
+		PageInfo: RelayPageInfo{
+			HasPreviousPage: p.HasPrevious,
+			HasNextPage:     p.HasNext,
+		},
+	}
+
+	for i, item := range p.Items {
+		connection.Edges[i] = RelayEdge[T]{Node: item, Cursor: cursorOf(item)}
+	}
+
+	if len(connection.Edges) > 0 {
+		connection.PageInfo.StartCursor = &connection.Edges[0].Cursor
+		connection.PageInfo.EndCursor = &connection.Edges[len(connection.Edges)-1].Cursor
+		if p.StartCursor != nil && p.EndCursor != nil {
+			connection.PageInfo.StartCursor = p.StartCursor
+			connection.PageInfo.EndCursor = p.EndCursor
+		}
+	}
+
+	return connection
+}
+
+// relayPageInfoSDL is the Relay PageInfo type, shared by every connection
+const relayPageInfoSDL = `type PageInfo {
+  hasPreviousPage: Boolean!
+  hasNextPage: Boolean!
+  startCursor: String
+  endCursor: String
+}
+`
+
+// graphQLName matches valid GraphQL type names
+var graphQLName = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// RelaySDL returns GraphQL SDL for the connection and edge types of each node type, plus a
+// single PageInfo type, matching the shapes produced by ToRelayConnection
+// Node types must already be defined in your schema.
+//
+// Example usage:
+//
+//	sdl, err := pagination.RelaySDL("User", "Order")
+//	// type UserConnection { edges: [UserEdge] pageInfo: PageInfo! }
+//	// type UserEdge { node: User cursor: String! }
+//	// ...
+//	// type PageInfo { hasPreviousPage: Boolean! hasNextPage: Boolean! startCursor: String endCursor: String }
+func RelaySDL(nodeTypes ...string) (string, error) {
+	var sdl strings.Builder
+
+	for _, nodeType := range nodeTypes {
+		if !graphQLName.MatchString(nodeType) {
+			return "", fmt.Errorf("invalid GraphQL type name %q", nodeType)
+		}
+
+		fmt.Fprintf(&sdl, "type %sConnection {\n  edges: [%sEdge]\n  pageInfo: PageInfo!\n}\n\n", nodeType, nodeType)
+		fmt.Fprintf(&sdl, "type %sEdge {\n  node: %s\n  cursor: String!\n}\n\n", nodeType, nodeType)
+	}
+
+	sdl.WriteString(relayPageInfoSDL)
+	return sdl.String(), nil
+}
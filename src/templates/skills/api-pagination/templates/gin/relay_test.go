@@ -0,0 +1,64 @@
+package pagination
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestRelaySDL(t *testing.T) {
+	sdl, err := RelaySDL("User")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `type UserConnection {
+  edges: [UserEdge]
+  pageInfo: PageInfo!
+}
+
+type UserEdge {
+  node: User
+  cursor: String!
+}
+
+type PageInfo {
+  hasPreviousPage: Boolean!
+  hasNextPage: Boolean!
+  startCursor: String
+  endCursor: String
+}
+`
+	if sdl != want {
+		t.Errorf("unexpected SDL:\n%s\nwant:\n%s", sdl, want)
+	}
+}
+
+func TestRelaySDLRejectsInvalidNames(t *testing.T) {
+	for _, name := range []string{"", "1User", "User-Type", "User Edge"} {
+		if _, err := RelaySDL(name); err == nil {
+			t.Errorf("%q: expected an error", name)
+		}
+	}
+}
+
+func TestToRelayConnection(t *testing.T) {
+	page := &CursorPagination[int]{Items: []int{4, 5, 6}, HasNext: true, HasPrevious: true}
+
+	connection := page.ToRelayConnection(func(item int) string { return strconv.Itoa(item) })
+
+	if len(connection.Edges) != 3 || connection.Edges[1].Node != 5 || connection.Edges[1].Cursor != "5" {
+		t.Fatalf("unexpected edges: %+v", connection.Edges)
+	}
+	info := connection.PageInfo
+	if !info.HasNextPage || !info.HasPreviousPage {
+		t.Errorf("unexpected page flags: %+v", info)
+	}
+	if info.StartCursor == nil || *info.StartCursor != "4" || info.EndCursor == nil || *info.EndCursor != "6" {
+		t.Errorf("unexpected start/end cursors: %v %v", info.StartCursor, info.EndCursor)
+	}
+
+	empty := (&CursorPagination[int]{}).ToRelayConnection(func(item int) string { return "" })
+	if empty.PageInfo.StartCursor != nil || empty.PageInfo.EndCursor != nil {
+		t.Error("empty page should have nil start and end cursors")
+	}
+}
@@ -0,0 +1,160 @@
+package pagination
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ReplicaLagFunc reports how far a read replica is behind the primary
+// Typical implementations query the replica, e.g. on Postgres
+// SELECT now() - pg_last_xact_replay_timestamp().
+type ReplicaLagFunc func(ctx context.Context) (time.Duration, error)
+
+// ReadReplica routes paginator reads to a replica, falling back to the primary
+// Count and page queries keep the query built on the primary handle and run on the
+// replica's connection pool. The primary serves the page instead when the replica lags more
+// than maxLag, and is retried once when a query on the replica fails, so a replica outage
+// slows list endpoints down rather than failing them.
+type ReadReplica struct {
+	db     *gorm.DB
+	maxLag time.Duration
+	lag    ReplicaLagFunc
+}
+
+// NewReadReplica creates a ReadReplica reading from replica
+// lag may be nil to skip the lag check; otherwise a lag above maxLag, or an error measuring
+// it, sends the page to the primary.
+//
+// Example usage:
+//
+//	var reads = pagination.NewReadReplica(replicaDB, 5*time.Second, func(ctx context.Context) (time.Duration, error) {
+//	    var seconds float64
+//	    err := replicaDB.WithContext(ctx).
+//	        Raw("SELECT COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)").
+//	        Scan(&seconds).Error
+//	    return time.Duration(seconds * float64(time.Second)), err
+//	})
+func NewReadReplica(replica *gorm.DB, maxLag time.Duration, lag ReplicaLagFunc) *ReadReplica {
+	return &ReadReplica{db: replica, maxLag: maxLag, lag: lag}
+}
+
+// usable reports whether the replica is close enough to the primary to read from
+func (r *ReadReplica) usable(ctx context.Context) bool {
+	if r.lag == nil {
+		return true
+	}
+	lag, err := r.lag(ctx)
+	return err == nil && lag <= r.maxLag
+}
+
+// replicaPool is the replica's connection pool, recording whether any query on it failed
+type replicaPool struct {
+	gorm.ConnPool
+	failures int32
+}
+
+func (p *replicaPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	stmt, err := p.ConnPool.PrepareContext(ctx, query)
+	p.record(err)
+	return stmt, err
+}
+
+func (p *replicaPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	result, err := p.ConnPool.ExecContext(ctx, query, args...)
+	p.record(err)
+	return result, err
+}
+
+func (p *replicaPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	rows, err := p.ConnPool.QueryContext(ctx, query, args...)
+	p.record(err)
+	return rows, err
+}
+
+func (p *replicaPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	row := p.ConnPool.QueryRowContext(ctx, query, args...)
+	if row != nil {
+		p.record(row.Err())
+	}
+	return row
+}
+
+// record counts err as a replica failure; a canceled request is not the replica's fault
+func (p *replicaPool) record(err error) {
+	if err != nil && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
+		atomic.AddInt32(&p.failures, 1)
+	}
+}
+
+// failed reports whether a query on the replica failed
+func (p *replicaPool) failed() bool {
+	return atomic.LoadInt32(&p.failures) > 0
+}
+
+// usesReplica reports whether a paginate call should read from WithReadReplica's replica
+// Snapshot sessions stay on the handle they were given: their snapshot lives on its connection.
+func (o options) usesReplica() bool {
+	return o.replica != nil && !o.replicaRouted && o.snapshots == nil
+}
+
+// readReplicated runs paginate on the replica, or on the primary when the replica lags or a
+// query on it fails
+// route moves a query onto the chosen connection pool; routed marks the re-entered call so it
+// does not route again.
+func readReplicated[R any](ctx context.Context, r *ReadReplica, paginate func(route func(*gorm.DB) *gorm.DB, routed Option) (R, error)) (R, error) {
+	routed := func(o *options) { o.replicaRouted = true }
+
+	if r.usable(ctx) {
+		pool := &replicaPool{ConnPool: r.db.Statement.ConnPool}
+		onReplica := func(db *gorm.DB) *gorm.DB {
+			// WithContext clones the statement, so the caller's query keeps the primary pool
+			tx := db.WithContext(queryContext(db))
+			tx.Statement.ConnPool = pool
+			return tx
+		}
+
+		result, err := paginate(onReplica, routed)
+		if err == nil || !pool.failed() {
+			return result, err
+		}
+	}
+
+	return paginate(func(db *gorm.DB) *gorm.DB { return db }, routed)
+}
+
+// onCountDB moves a count query onto WithCountDB's connection
+func (o options) onCountDB(query *gorm.DB) *gorm.DB {
+	return o.splitConnection(query, o.countDB)
+}
+
+// onFetchDB moves a page query onto WithFetchDB's connection
+func (o options) onFetchDB(query *gorm.DB) *gorm.DB {
+	return o.splitConnection(query, o.fetchDB)
+}
+
+// splitConnection runs query on target's connection pool, unless query is part of the
+// caller's transaction or a snapshot session (nil target keeps the query's own)
+func (o options) splitConnection(query, target *gorm.DB) *gorm.DB {
+	if target == nil || target.Statement == nil || o.snapshots != nil || inTransaction(query) {
+		return query
+	}
+
+	// WithContext clones the statement, so the caller's query keeps its pool
+	tx := query.WithContext(queryContext(query))
+	tx.Statement.ConnPool = target.Statement.ConnPool
+	return tx
+}
+
+// inTransaction reports whether query runs inside a transaction (db.Begin or db.Transaction)
+func inTransaction(query *gorm.DB) bool {
+	if query.Statement == nil {
+		return false
+	}
+	_, ok := query.Statement.ConnPool.(gorm.TxCommitter)
+	return ok
+}
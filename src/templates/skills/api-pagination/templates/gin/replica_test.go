@@ -0,0 +1,238 @@
+package pagination
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type invoice struct {
+	ID int
+}
+
+// downPool is a replica connection pool whose every query fails
+type downPool struct {
+	queries int
+}
+
+var errReplicaDown = errors.New("replica unreachable")
+
+func (p *downPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	p.queries++
+	return nil, errReplicaDown
+}
+
+func (p *downPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	p.queries++
+	return nil, errReplicaDown
+}
+
+func (p *downPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	p.queries++
+	return nil, errReplicaDown
+}
+
+func (p *downPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	p.queries++
+	return nil
+}
+
+func TestReadReplicaFallsBackToPrimary(t *testing.T) {
+	db, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The primary has no pool in this test; the callback stands in for gorm's query
+	// callback, which runs on the statement's pool
+	first, second := invoice{ID: 3}, invoice{ID: 4}
+	err = db.Callback().Query().Register("test:invoices", func(tx *gorm.DB) {
+		if pool := tx.Statement.ConnPool; pool != nil {
+			if _, err := pool.QueryContext(tx.Statement.Context, "SELECT"); err != nil {
+				tx.AddError(err)
+				return
+			}
+		}
+		switch dest := tx.Statement.Dest.(type) {
+		case *[]invoice:
+			*dest = []invoice{first, second}
+		case *int64:
+			*dest = 5
+			tx.RowsAffected = 1
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replicaDB, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	down := &downPool{}
+	replicaDB.Statement.ConnPool = down
+
+	var invoices []invoice
+	result, err := OffsetPaginate(db, &invoices, 2, 2, WithReadReplica(NewReadReplica(replicaDB, 0, nil)))
+	if err != nil {
+		t.Fatalf("OffsetPaginate = %v, want the primary's page", err)
+	}
+	if down.queries == 0 {
+		t.Error("replica was never tried")
+	}
+	if len(result.Items) != 2 || result.Items[0].ID != 3 || result.TotalItems != 5 || result.TotalPages != 3 {
+		t.Errorf("result = %+v", result)
+	}
+	if db.Statement.ConnPool != nil {
+		t.Error("routing to the replica changed the caller's query")
+	}
+
+	// A replica lagging past maxLag is not tried at all
+	down.queries = 0
+	lagging := NewReadReplica(replicaDB, time.Second, func(ctx context.Context) (time.Duration, error) {
+		return time.Minute, nil
+	})
+	cursorResult, err := CursorPaginateInt(db, &invoices, "", 2, "id", true, WithReadReplica(lagging))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if down.queries != 0 {
+		t.Errorf("lagging replica ran %d queries", down.queries)
+	}
+	if len(cursorResult.Items) != 2 {
+		t.Errorf("cursor items = %+v", cursorResult.Items)
+	}
+}
+
+func TestReplicaPoolIgnoresCanceledRequests(t *testing.T) {
+	pool := &replicaPool{ConnPool: &downPool{}}
+	pool.record(context.Canceled)
+	if pool.failed() {
+		t.Error("canceled request counted as a replica failure")
+	}
+	pool.record(errReplicaDown)
+	if !pool.failed() {
+		t.Error("replica error not recorded")
+	}
+}
+
+// namedPool is a connection pool that only identifies itself; txPool is one inside a transaction
+type namedPool struct {
+	name string
+}
+
+func (p *namedPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return nil, nil
+}
+
+func (p *namedPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return nil, nil
+}
+
+func (p *namedPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, nil
+}
+
+func (p *namedPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return nil
+}
+
+func (p *namedPool) poolName() string {
+	return p.name
+}
+
+type txPool struct {
+	namedPool
+}
+
+func (p *txPool) Commit() error   { return nil }
+func (p *txPool) Rollback() error { return nil }
+
+// splitDB serves five invoices on any pool; counts on the replica lag one row behind
+func splitDB(t *testing.T, pool gorm.ConnPool, counted, fetched *string) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Statement.ConnPool = pool
+	err = db.Callback().Query().Register("test:split", func(tx *gorm.DB) {
+		name := tx.Statement.ConnPool.(interface{ poolName() string }).poolName()
+		switch dest := tx.Statement.Dest.(type) {
+		case *[]invoice:
+			*fetched = name
+			*dest = append((*dest)[:0], invoice{ID: 1}, invoice{ID: 2})
+		case *int64:
+			*counted = name
+			if tx.Statement.Table == "" {
+				t.Errorf("count on %s lost the query's model", name)
+			}
+			*dest = 5
+			if name == "replica" {
+				*dest = 4
+			}
+			tx.RowsAffected = 1
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestCountAndFetchOnSeparateConnections(t *testing.T) {
+	var counted, fetched string
+	db := splitDB(t, &namedPool{name: "primary"}, &counted, &fetched)
+	replicaDB, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	replicaDB.Statement.ConnPool = &namedPool{name: "replica"}
+
+	var invoices []invoice
+	result, err := OffsetPaginate(db, &invoices, 1, 2, WithCountDB(replicaDB))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counted != "replica" || fetched != "primary" {
+		t.Errorf("count on %s, fetch on %s; want replica and primary", counted, fetched)
+	}
+	// The replica has not seen the newest invoice yet: the total lags the primary's rows
+	if result.TotalItems != 4 {
+		t.Errorf("total = %d, want the replica's 4", result.TotalItems)
+	}
+	if pool, ok := db.Statement.ConnPool.(*namedPool); !ok || pool.name != "primary" {
+		t.Error("routing the count changed the caller's query")
+	}
+
+	counted, fetched = "", ""
+	_, err = CursorPaginateInt(db, &invoices, "", 1, "id", true, WithFetchDB(replicaDB), WithApproxRemaining(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counted != "primary" || fetched != "replica" {
+		t.Errorf("cursor: count on %s, fetch on %s; want primary and replica", counted, fetched)
+	}
+}
+
+func TestConnectionSplitSkippedInTransaction(t *testing.T) {
+	var counted, fetched string
+	tx := splitDB(t, &txPool{namedPool{name: "tx"}}, &counted, &fetched)
+	replicaDB, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	replicaDB.Statement.ConnPool = &namedPool{name: "replica"}
+
+	var invoices []invoice
+	result, err := OffsetPaginate(tx, &invoices, 1, 2, WithCountDB(replicaDB), WithFetchDB(replicaDB))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counted != "tx" || fetched != "tx" || result.TotalItems != 5 {
+		t.Errorf("count on %s, fetch on %s, total %d; want both inside the transaction", counted, fetched, result.TotalItems)
+	}
+}
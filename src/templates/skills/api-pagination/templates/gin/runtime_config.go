@@ -0,0 +1,258 @@
+package pagination
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// ErrInvalidConfig is returned when a RuntimeConfig fails validation
+// The previous configuration stays in effect.
+var ErrInvalidConfig = errors.New("invalid pagination config")
+
+// CountMode selects how the offset paginators count totals
+type CountMode string
+
+const (
+	// CountExact counts every matching row (the default)
+	CountExact CountMode = "exact"
+
+	// CountCapped stops counting at CountCap rows, as WithMaxReportedTotal does, for routes
+	// that do not set their own cap
+	CountCapped CountMode = "capped"
+)
+
+// RuntimeConfig holds the pagination limits that can change without a deploy
+// Load it from the environment at startup with Reload (or ConfigFromEnv and SetConfig);
+// the default MaxPageSizeFor, the middleware, and the paginators read the current one on every
+// request. A replaced MaxPageSizeFor still decides the page size limit itself.
+type RuntimeConfig struct {
+	MaxPageSize     int
+	DefaultPageSize int
+	CountMode       CountMode
+
+	// CountCap is where CountCapped stops counting
+	CountCap int
+}
+
+// Environment variables read by ConfigFromEnv; unset variables keep the generated defaults
+const (
+	EnvMaxPageSize     = "PAGINATION_MAX_PAGE_SIZE"
+	EnvDefaultPageSize = "PAGINATION_DEFAULT_PAGE_SIZE"
+	EnvCountMode       = "PAGINATION_COUNT_MODE"
+	EnvCountCap        = "PAGINATION_COUNT_CAP"
+)
+
+// DefaultRuntimeConfig returns the configuration the package was generated with
+func DefaultRuntimeConfig() RuntimeConfig {
+	return RuntimeConfig{
+		MaxPageSize:     MaxPageSize,
+		DefaultPageSize: {{defaultPageSize}},
+		CountMode:       CountExact,
+		CountCap:        10000,
+	}
+}
+
+// Validate checks that the configuration can be served
+func (c RuntimeConfig) Validate() error {
+	if c.MaxPageSize < 1 {
+		return fmt.Errorf("%w: max page size %d is below 1", ErrInvalidConfig, c.MaxPageSize)
+	}
+	if c.DefaultPageSize < 1 || c.DefaultPageSize > c.MaxPageSize {
+		return fmt.Errorf("%w: default page size %d is outside 1..%d", ErrInvalidConfig, c.DefaultPageSize, c.MaxPageSize)
+	}
+	switch c.CountMode {
+	case CountExact:
+	case CountCapped:
+		if c.CountCap < 1 {
+			return fmt.Errorf("%w: count cap %d is below 1", ErrInvalidConfig, c.CountCap)
+		}
+	default:
+		return fmt.Errorf("%w: unknown count mode %q", ErrInvalidConfig, c.CountMode)
+	}
+	return nil
+}
+
+// currentConfig holds the *RuntimeConfig in effect (empty = DefaultRuntimeConfig); it is
+// swapped whole, so readers never see fields of two different configurations
+var currentConfig atomic.Value
+
+// CurrentConfig returns the configuration in effect
+// Read it once per request and use that snapshot, rather than reading it again midway.
+func CurrentConfig() RuntimeConfig {
+	if cfg, ok := currentConfig.Load().(*RuntimeConfig); ok {
+		return *cfg
+	}
+	return DefaultRuntimeConfig()
+}
+
+// SetConfig validates cfg and makes it the configuration in effect
+// Requests already running keep the snapshot they started with.
+func SetConfig(cfg RuntimeConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	currentConfig.Store(&cfg)
+	return nil
+}
+
+// ConfigFromEnv reads a RuntimeConfig from the PAGINATION_* environment variables
+func ConfigFromEnv() (RuntimeConfig, error) {
+	return configFromLookup(os.LookupEnv)
+}
+
+// ConfigFromFile reads a RuntimeConfig from a file of KEY=VALUE lines using the same
+// variable names as ConfigFromEnv (blank lines and # comments are ignored)
+func ConfigFromFile(path string) (RuntimeConfig, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return RuntimeConfig{}, fmt.Errorf("failed to open pagination config: %w", err)
+	}
+	defer file.Close()
+
+	values := map[string]string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return RuntimeConfig{}, fmt.Errorf("%w: line %q is not KEY=VALUE", ErrInvalidConfig, line)
+		}
+		values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	if err := scanner.Err(); err != nil {
+		return RuntimeConfig{}, fmt.Errorf("failed to read pagination config: %w", err)
+	}
+
+	return configFromLookup(func(key string) (string, bool) {
+		value, ok := values[key]
+		return value, ok
+	})
+}
+
+// configFromLookup builds a validated RuntimeConfig from lookup over the defaults
+func configFromLookup(lookup func(key string) (string, bool)) (RuntimeConfig, error) {
+	cfg := DefaultRuntimeConfig()
+
+	for key, target := range map[string]*int{
+		EnvMaxPageSize:     &cfg.MaxPageSize,
+		EnvDefaultPageSize: &cfg.DefaultPageSize,
+		EnvCountCap:        &cfg.CountCap,
+	} {
+		raw, ok := lookup(key)
+		if !ok || raw == "" {
+			continue
+		}
+		value, err := strconv.Atoi(raw)
+		if err != nil {
+			return RuntimeConfig{}, fmt.Errorf("%w: %s=%q is not an integer", ErrInvalidConfig, key, raw)
+		}
+		*target = value
+	}
+	if raw, ok := lookup(EnvCountMode); ok && raw != "" {
+		cfg.CountMode = CountMode(strings.ToLower(raw))
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return RuntimeConfig{}, err
+	}
+	return cfg, nil
+}
+
+// Reload re-reads the environment and swaps in the result
+// An invalid environment fails with ErrInvalidConfig and leaves the current configuration in place.
+//
+// Example usage:
+//
+//	// Incident: shrink pages without a deploy
+//	os.Setenv("PAGINATION_MAX_PAGE_SIZE", "25")
+//	if err := pagination.Reload(); err != nil {
+//	    log.Printf("pagination config not reloaded: %v", err)
+//	}
+func Reload() error {
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		return err
+	}
+	return SetConfig(cfg)
+}
+
+// OnReloadError receives the errors of reloads triggered by ReloadOnSignal and WatchConfigFile
+// Replace it to log them; the default discards them.
+var OnReloadError = func(err error) {}
+
+// ReloadOnSignal calls Reload on every SIGHUP until ctx is done
+// It blocks, so run it in its own goroutine; without it, SIGHUP keeps its default behavior.
+//
+// Example usage:
+//
+//	go pagination.ReloadOnSignal(ctx) // kill -HUP <pid> after editing the environment
+func ReloadOnSignal(ctx context.Context) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+			if err := Reload(); err != nil {
+				OnReloadError(err)
+			}
+		}
+	}
+}
+
+// WatchConfigFile polls path every interval and swaps in its configuration (see
+// ConfigFromFile) whenever its modification time changes, until ctx is done
+// The file is read once at start; a missing or invalid file keeps the current configuration.
+//
+// Example usage:
+//
+//	go pagination.WatchConfigFile(ctx, "/etc/myapp/pagination.env", 10*time.Second)
+func WatchConfigFile(ctx context.Context, path string, interval time.Duration) {
+	var modified time.Time
+	check := func() {
+		info, err := os.Stat(path)
+		if err != nil {
+			OnReloadError(fmt.Errorf("failed to stat pagination config: %w", err))
+			return
+		}
+		if info.ModTime().Equal(modified) {
+			return
+		}
+		modified = info.ModTime()
+
+		cfg, err := ConfigFromFile(path)
+		if err == nil {
+			err = SetConfig(cfg)
+		}
+		if err != nil {
+			OnReloadError(err)
+		}
+	}
+
+	check()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			check()
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package pagination
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// restoreConfig puts the generated defaults back after a test swaps the configuration
+func restoreConfig(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		if err := SetConfig(DefaultRuntimeConfig()); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestConfigFromLookup(t *testing.T) {
+	env := func(values map[string]string) func(string) (string, bool) {
+		return func(key string) (string, bool) {
+			value, ok := values[key]
+			return value, ok
+		}
+	}
+
+	cfg, err := configFromLookup(env(map[string]string{
+		EnvMaxPageSize:     "50",
+		EnvDefaultPageSize: "10",
+		EnvCountMode:       "CAPPED",
+		EnvCountCap:        "1000",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg != (RuntimeConfig{MaxPageSize: 50, DefaultPageSize: 10, CountMode: CountCapped, CountCap: 1000}) {
+		t.Errorf("config = %+v", cfg)
+	}
+	if cfg, err := configFromLookup(env(nil)); err != nil || cfg != DefaultRuntimeConfig() {
+		t.Errorf("empty environment: %+v, %v", cfg, err)
+	}
+
+	for name, values := range map[string]map[string]string{
+		"non-integer":          {EnvMaxPageSize: "lots"},
+		"default above max":    {EnvMaxPageSize: "5", EnvDefaultPageSize: "10"},
+		"unknown count mode":   {EnvCountMode: "guess"},
+		"capped without a cap": {EnvCountMode: "capped", EnvCountCap: "0"},
+	} {
+		if _, err := configFromLookup(env(values)); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("%s: expected ErrInvalidConfig, got %v", name, err)
+		}
+	}
+}
+
+func TestConfigFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pagination.env")
+	content := "# incident limits\nPAGINATION_MAX_PAGE_SIZE=25\n\nPAGINATION_DEFAULT_PAGE_SIZE = 5\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := ConfigFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.MaxPageSize != 25 || cfg.DefaultPageSize != 5 || cfg.CountMode != CountExact {
+		t.Errorf("config = %+v", cfg)
+	}
+}
+
+func TestSetConfigAppliesToRequests(t *testing.T) {
+	restoreConfig(t)
+
+	if err := SetConfig(RuntimeConfig{MaxPageSize: 0, DefaultPageSize: 1}); !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("expected ErrInvalidConfig, got %v", err)
+	}
+	if CurrentConfig() != DefaultRuntimeConfig() {
+		t.Fatal("invalid config replaced the current one")
+	}
+
+	if err := SetConfig(RuntimeConfig{MaxPageSize: 8, DefaultPageSize: 4, CountMode: CountCapped, CountCap: 100}); err != nil {
+		t.Fatal(err)
+	}
+	if got := ParamsFromQuery(map[string][]string{"page_size": {"100"}}).PageSize; got != 8 {
+		t.Errorf("clamped page size = %d, want 8", got)
+	}
+	if got := ParamsFromQuery(nil).PageSize; got != 4 {
+		t.Errorf("default page size = %d, want 4", got)
+	}
+	if o := applyOptions(nil); o.maxReportedTotal != 100 {
+		t.Errorf("capped count mode: max reported total = %d, want 100", o.maxReportedTotal)
+	}
+	if o := applyOptions([]Option{WithMaxReportedTotal(7)}); o.maxReportedTotal != 7 {
+		t.Errorf("route cap overridden: max reported total = %d, want 7", o.maxReportedTotal)
+	}
+}
+
+// Run with -race: readers must only ever see one of the configurations whole
+func TestConcurrentReloads(t *testing.T) {
+	restoreConfig(t)
+
+	small := RuntimeConfig{MaxPageSize: 10, DefaultPageSize: 5, CountMode: CountExact, CountCap: 1}
+	large := RuntimeConfig{MaxPageSize: 500, DefaultPageSize: 50, CountMode: CountCapped, CountCap: 1000}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				cfg := small
+				if (i+j)%2 == 0 {
+					cfg = large
+				}
+				if err := SetConfig(cfg); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				if cfg := applyOptions(nil).config; cfg != small && cfg != large && cfg != DefaultRuntimeConfig() {
+					t.Errorf("torn config %+v", cfg)
+					return
+				}
+				if size := ParamsFromQuery(map[string][]string{"page_size": {"1000"}}).PageSize; size != 10 && size != 500 && size != MaxPageSize {
+					t.Errorf("page size %d from no configuration", size)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,67 @@
+package pagination
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"strings"
+)
+
+// HeadLinks renders the canonical, rel="prev", and rel="next" link elements of an offset
+// page for a server-rendered listing's HTML <head>
+// URLs are normalized so each page has exactly one crawlable address: the first page is
+// baseURL itself (never ?page=1, or ?page=0 under ZeroBased), and page_size is included only
+// when it differs from the configured default page size. The first page has no rel="prev" and the last
+// page no rel="next". baseURL may already carry a query string (e.g. "/shoes?color=red").
+//
+// Example usage:
+//
+//	result, err := pagination.OffsetPaginate(db, &products, page, pageSize)
+//	// ...
+//	c.HTML(200, "products.html", gin.H{
+//	    "Products":  result.Items,
+//	    "HeadLinks": result.HeadLinks("https://shop.example.com/products"),
+//	})
+//	// products.html prints .HeadLinks inside <head>; as template.HTML it is not escaped again
+func (p *OffsetPagination[T]) HeadLinks(baseURL string) template.HTML {
+	pageURL := func(page int) string {
+		return canonicalPageURL(baseURL, page, p.PageSize, p.Indexing)
+	}
+	links := p.links(pageURL)
+
+	var b strings.Builder
+	writeLinkTag(&b, "canonical", pageURL(p.CurrentPage))
+	if links.Previous != nil {
+		writeLinkTag(&b, "prev", *links.Previous)
+	}
+	if links.Next != nil {
+		writeLinkTag(&b, "next", *links.Next)
+	}
+
+	return template.HTML(b.String())
+}
+
+// canonicalPageURL is the normalized URL of one page of a listing
+func canonicalPageURL(baseURL string, page, pageSize int, indexing PageIndexing) string {
+	var params []string
+	if page > indexing.FirstPage() {
+		params = append(params, fmt.Sprintf("page=%d", page))
+	}
+	if pageSize != CurrentConfig().DefaultPageSize {
+		params = append(params, fmt.Sprintf("page_size=%d", pageSize))
+	}
+	if len(params) == 0 {
+		return baseURL
+	}
+
+	separator := "?"
+	if strings.Contains(baseURL, "?") {
+		separator = "&"
+	}
+	return baseURL + separator + strings.Join(params, "&")
+}
+
+// writeLinkTag writes one <link> element with an escaped href
+func writeLinkTag(b *strings.Builder, rel, href string) {
+	fmt.Fprintf(b, "<link rel=\"%s\" href=\"%s\">\n", rel, html.EscapeString(href))
+}
@@ -0,0 +1,63 @@
+package pagination
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHeadLinks(t *testing.T) {
+	page := func(current int) *OffsetPagination[int] {
+		return &OffsetPagination[int]{
+			CurrentPage: current,
+			PageSize:    {{defaultPageSize}},
+			TotalPages:  3,
+			HasPrevious: current > 1,
+			HasNext:     current < 3,
+		}
+	}
+	base := "https://shop.example.com/products"
+
+	cases := []struct {
+		name    string
+		current int
+		want    []string
+	}{
+		{"first", 1, []string{
+			`<link rel="canonical" href="https://shop.example.com/products">`,
+			`<link rel="next" href="https://shop.example.com/products?page=2">`,
+		}},
+		{"middle", 2, []string{
+			`<link rel="canonical" href="https://shop.example.com/products?page=2">`,
+			`<link rel="prev" href="https://shop.example.com/products">`,
+			`<link rel="next" href="https://shop.example.com/products?page=3">`,
+		}},
+		{"last", 3, []string{
+			`<link rel="canonical" href="https://shop.example.com/products?page=3">`,
+			`<link rel="prev" href="https://shop.example.com/products?page=2">`,
+		}},
+	}
+
+	for _, tc := range cases {
+		got := strings.Split(strings.TrimSpace(string(page(tc.current).HeadLinks(base))), "\n")
+		if strings.Join(got, "\n") != strings.Join(tc.want, "\n") {
+			t.Errorf("%s page:\n got %q\nwant %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestHeadLinksNormalizesURLs(t *testing.T) {
+	p := &OffsetPagination[int]{CurrentPage: 1, PageSize: 50, TotalPages: 2, HasNext: true}
+
+	got := string(p.HeadLinks("/shoes?color=red&size=<9>"))
+	for _, want := range []string{
+		`<link rel="canonical" href="/shoes?color=red&amp;size=&lt;9&gt;&amp;page_size=50">`,
+		`<link rel="next" href="/shoes?color=red&amp;size=&lt;9&gt;&amp;page=2&amp;page_size=50">`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %s in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, `rel="prev"`) {
+		t.Errorf("page 1 should have no rel=prev:\n%s", got)
+	}
+}
@@ -0,0 +1,118 @@
+package pagination
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrMaxPagesReached is returned when a cursor session has already been served its
+// SessionPageLimit's pages
+// Respond with 429; the client has to start a new scroll session from the first page.
+var ErrMaxPagesReached = errors.New("maximum pages per pagination session reached")
+
+// sessionPagesSeparator joins a cursor to its signed page count
+// It is appended after the drift anchor and tie-breaker and is outside both base64 alphabets.
+const sessionPagesSeparator = "!"
+
+// SessionPageLimit caps how many pages one scroll session of the cursor paginators may read
+// Row caps bound how much a client reads per request; this bounds how long it keeps scrolling,
+// whatever the page sizes. The number of pages served so far rides in every cursor,
+// HMAC-SHA256 signed together with the cursor, so a client cannot reset or edit it; cursors
+// without a valid count fail with ErrInvalidCursor.
+type SessionPageLimit struct {
+	secret   []byte
+	maxPages int
+}
+
+// NewSessionPageLimit creates a limit of maxPages pages per session (at least 1), signing page
+// counts with secret (share it with every instance)
+//
+// Example usage:
+//
+//	var feedPages = pagination.NewSessionPageLimit([]byte(os.Getenv("PAGINATION_SECRET")), 50)
+//
+//	result, err := pagination.CursorPaginateInt(db.Model(&Post{}), &posts, cursor, 20, "id", false,
+//	    pagination.WithSessionPageLimit(feedPages),
+//	)
+//	if errors.Is(err, pagination.ErrMaxPagesReached) {
+//	    c.JSON(429, gin.H{"error": err.Error()})
+//	    return
+//	}
+func NewSessionPageLimit(secret []byte, maxPages int) *SessionPageLimit {
+	if maxPages < 1 {
+		maxPages = 1
+	}
+	return &SessionPageLimit{secret: secret, maxPages: maxPages}
+}
+
+// MaxPages returns the number of pages a session may read
+func (l *SessionPageLimit) MaxPages() int {
+	return l.maxPages
+}
+
+// split separates a cursor into the page cursor and the verified number of pages its session
+// was served (0 for the first page's empty cursor)
+func (l *SessionPageLimit) split(cursor string) (string, int, error) {
+	if cursor == "" {
+		return "", 0, nil
+	}
+
+	i := strings.LastIndex(cursor, sessionPagesSeparator)
+	if i < 0 {
+		return "", 0, fmt.Errorf("%w: missing session page count", ErrInvalidCursor)
+	}
+	counted, signature, found := strings.Cut(cursor[i+1:], ".")
+	pages, err := strconv.Atoi(counted)
+	if !found || err != nil || pages < 1 || !hmac.Equal([]byte(signature), []byte(l.sign(cursor[:i], pages))) {
+		return "", 0, fmt.Errorf("%w: session page count was tampered with", ErrInvalidCursor)
+	}
+	return cursor[:i], pages, nil
+}
+
+// join appends the signed page count to cursor
+func (l *SessionPageLimit) join(cursor string, pages int) string {
+	return cursor + sessionPagesSeparator + strconv.Itoa(pages) + "." + l.sign(cursor, pages)
+}
+
+// sign returns the base64url HMAC-SHA256 of cursor and its page count
+func (l *SessionPageLimit) sign(cursor string, pages int) string {
+	mac := hmac.New(sha256.New, l.secret)
+	mac.Write([]byte(strconv.Itoa(pages) + sessionPagesSeparator + cursor))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// limitSessionPages serves a page of a WithSessionPageLimit session
+// It checks the cursor's page count, runs the paginator on the bare cursor, and signs the
+// incremented count into every cursor of the result.
+func limitSessionPages[T any](
+	cursor string,
+	limit *SessionPageLimit,
+	run func(cursor string, counted Option) (*CursorPagination[T], error),
+) (*CursorPagination[T], error) {
+	cursor, pages, err := limit.split(cursor)
+	if err != nil {
+		return nil, err
+	}
+	if pages >= limit.maxPages {
+		return nil, fmt.Errorf("%w: %d pages", ErrMaxPagesReached, limit.maxPages)
+	}
+
+	result, err := run(cursor, func(o *options) { o.pageLimited = true })
+	if err != nil {
+		return nil, err
+	}
+
+	pages++
+	for _, cursor := range []**string{&result.NextCursor, &result.PreviousCursor, &result.StartCursor, &result.EndCursor} {
+		if *cursor != nil {
+			signed := limit.join(**cursor, pages)
+			*cursor = &signed
+		}
+	}
+	return result, nil
+}
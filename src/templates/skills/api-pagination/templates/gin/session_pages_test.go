@@ -0,0 +1,64 @@
+package pagination
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSessionPageLimitStopsScrolling(t *testing.T) {
+	var posts []feedPost
+	for id := int64(7); id >= 1; id-- {
+		posts = append(posts, feedPost{ID: id})
+	}
+	var boundary, anchor int64
+	db := feedDB(t, &posts, &boundary, &anchor)
+	limit := NewSessionPageLimit([]byte("secret"), 2)
+
+	page := func(cursor string) (*CursorPagination[feedPost], error) {
+		boundary = 0
+		if cursor != "" {
+			bare, _, err := limit.split(cursor)
+			if err != nil {
+				return nil, err
+			}
+			value, err := DecodeCursor(bare, "id", false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if boundary, err = cursorInt(value); err != nil {
+				t.Fatal(err)
+			}
+		}
+		var page []feedPost
+		return FeedPaginate(db.Model(&feedPost{}), &page, cursor, 2, WithSessionPageLimit(limit))
+	}
+
+	first, err := page("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := page(*first.NextCursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(second.Items) != 2 || second.Items[0].ID != 5 || !second.HasNext {
+		t.Fatalf("second page = %+v, want posts 5 and 4", second.Items)
+	}
+
+	// The third page of the session is one past the limit, though rows remain
+	if _, err := page(*second.NextCursor); !errors.Is(err, ErrMaxPagesReached) {
+		t.Errorf("third page: err = %v, want ErrMaxPagesReached", err)
+	}
+
+	// Lowering the count or dropping it invalidates the cursor
+	next := *second.NextCursor
+	forged := strings.Replace(next, sessionPagesSeparator+"2.", sessionPagesSeparator+"1.", 1)
+	if _, err := page(forged); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("forged count: err = %v, want ErrInvalidCursor", err)
+	}
+	stripped := next[:strings.LastIndex(next, sessionPagesSeparator)]
+	if _, err := page(stripped); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("missing count: err = %v, want ErrInvalidCursor", err)
+	}
+}
@@ -0,0 +1,427 @@
+package pagination
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrNoShards is returned when ShardedCursorPaginate is given no shard queries
+var ErrNoShards = errors.New("no shards to paginate")
+
+// shardedCursor is the position of every shard after a ShardedCursorPaginate page
+type shardedCursor struct {
+	// Shards is the shard count the cursor was issued for
+	Shards    int             `json:"n"`
+	Positions []shardPosition `json:"p"`
+}
+
+// shardPosition is the last row a shard contributed, or its start while Set is false
+type shardPosition struct {
+	Set   bool `json:"s,omitempty"`
+	Value any  `json:"v,omitempty"`
+	Tie   any  `json:"t,omitempty"`
+
+	// Done marks a shard whose rows were all served; it is not queried again
+	Done bool `json:"d,omitempty"`
+}
+
+// shardFetch returns up to limit rows of shard i past p, in page order
+type shardFetch[T any] func(i int, p shardPosition, limit int) ([]T, error)
+
+// ShardTables builds one query per shard table, named by format and the shard index
+//
+// Example usage:
+//
+//	// orders_0 … orders_3
+//	var orderShards = pagination.ShardTables(db.Model(&Order{}), "orders_%d", 4)
+func ShardTables(db *gorm.DB, format string, count int) []*gorm.DB {
+	shards := make([]*gorm.DB, count)
+	for i := range shards {
+		shards[i] = db.Table(fmt.Sprintf(format, i))
+	}
+	return shards
+}
+
+// ShardedCursorPaginate pages rows spread over several shards as one keyset-ordered list
+// Each shard is queried concurrently for up to pageSize+1 rows past its own position, the
+// results are merge-sorted by cursorField (then the tie-breaker, then shard index), and the
+// next cursor records where every shard stopped. A shard that runs out is marked done and
+// skipped on later pages. A cursor issued for a different number of shards cannot resume
+// them, so the request restarts from the first page with Restarted set.
+// Shard queries may be reused across requests. WithShardTotals adds TotalItems, the sum of the
+// shards' counts. Sharded pages move forward only: PreviousCursor is never set, and
+// WithRowIDTieBreaker is rejected because rowids are not comparable across tables.
+//
+// Example usage:
+//
+//	func ListOrders(c *gin.Context) {
+//	    var orders []Order
+//	    result, err := pagination.ShardedCursorPaginate(
+//	        orderShards,
+//	        &orders,
+//	        c.Query("cursor"),
+//	        20,
+//	        "created_unix",
+//	        false, // newest first
+//	        pagination.WithShardTotals(), // optional
+//	    )
+//	    if err != nil {
+//	        c.JSON(400, gin.H{"error": err.Error()})
+//	        return
+//	    }
+//	    c.JSON(200, result)
+//	}
+func ShardedCursorPaginate[T any](
+	shards []*gorm.DB,
+	dest *[]T,
+	cursor string,
+	pageSize int,
+	cursorField string,
+	ascending bool,
+	opts ...Option,
+) (*CursorPagination[T], error) {
+	if len(shards) == 0 {
+		return nil, ErrNoShards
+	}
+	o := applyOptions(opts)
+
+	// Start every request from a fresh session so shard queries can be shared
+	bound := make([]*gorm.DB, len(shards))
+	for i, shard := range shards {
+		bound[i] = o.bindContext(shard).Session(&gorm.Session{})
+	}
+	db := bound[0]
+
+	if err := checkDestType[T](db); err != nil {
+		return nil, err
+	}
+
+	// Constrain page size
+	if limit := queryMaxPageSize(db); pageSize > limit {
+		pageSize = limit
+	}
+	if pageSize < 1 {
+		pageSize = o.config.DefaultPageSize
+	}
+
+	tie, err := resolveTieBreaker[T](db, cursorField, o)
+	if err != nil {
+		return nil, err
+	}
+	if tie != nil && tie.field == nil {
+		return nil, fmt.Errorf("%w: rowid is not comparable across shards", ErrTieBreakerRequired)
+	}
+
+	fetch := func(i int, p shardPosition, limit int) ([]T, error) {
+		query := bound[i]
+		if p.Set {
+			if tie != nil && p.Tie != nil {
+				query = query.Where(tieBreakerCondition(cursorField, tie.column, ascending, false), p.Value, p.Value, p.Tie)
+			} else {
+				query = query.Where(cursorCondition(cursorField, ascending, false), p.Value)
+			}
+		}
+		query = query.Order(tieBreakerOrder(cursorField, ascending))
+		if tie != nil {
+			query = query.Order(tieBreakerOrder(tie.column, ascending))
+		}
+
+		var rows []T
+		err := o.fetchLimiter.do(queryContext(query), func() error {
+			return o.onFetchDB(query).Limit(sqlLimit(query, limit)).Find(&rows).Error
+		})
+		return rows, err
+	}
+
+	result, err := paginateShards(db, fetch, len(shards), dest, cursor, pageSize, cursorField, tie, ascending, o)
+	if err != nil {
+		return nil, err
+	}
+
+	if o.shardTotals {
+		total, err := shardTotal(bound, o)
+		if err != nil {
+			return nil, err
+		}
+		result.TotalItems = &total
+	}
+	return result, nil
+}
+
+// paginateShards builds a ShardedCursorPaginate page over count shards read through fetch
+func paginateShards[T any](
+	db *gorm.DB,
+	fetch shardFetch[T],
+	count int,
+	dest *[]T,
+	cursor string,
+	pageSize int,
+	cursorField string,
+	tie *tieBreaker,
+	ascending bool,
+	o options,
+) (*CursorPagination[T], error) {
+	key, err := newFieldExtractor[T](db, cursorField)
+	if err != nil {
+		return nil, err
+	}
+
+	position, restarted, err := decodeShardedCursor(cursor, count, o)
+	if err != nil {
+		return nil, err
+	}
+	if position == nil {
+		position = &shardedCursor{Shards: count, Positions: make([]shardPosition, count)}
+	}
+
+	// Fetch one extra row per shard to tell an exhausted shard from a full one
+	rows := make([][]T, count)
+	err = eachShard(count, func(i int) error {
+		if position.Positions[i].Done {
+			return nil
+		}
+		var err error
+		if rows[i], err = fetch(i, position.Positions[i], pageSize+1); err != nil {
+			return fmt.Errorf("failed to fetch items from shard %d: %w", i, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Merge the shards' sorted rows, taking the smallest head until the page is full
+	ctx := queryContext(db)
+	less := func(a, b T) bool {
+		c := compareKeys(key.value(ctx, a), key.value(ctx, b))
+		if c == 0 && tie != nil {
+			c = compareKeys(tie.field.value(ctx, a), tie.field.value(ctx, b))
+		}
+		if !ascending {
+			c = -c
+		}
+		return c < 0
+	}
+	heads := make([]int, count)
+	items := []T{}
+	for len(items) < pageSize {
+		next := -1
+		for i := range rows {
+			if heads[i] < len(rows[i]) && (next < 0 || less(rows[i][heads[i]], rows[next][heads[next]])) {
+				next = i
+			}
+		}
+		if next < 0 {
+			break
+		}
+		items = append(items, rows[next][heads[next]])
+		heads[next]++
+	}
+	*dest = items
+
+	// Advance each shard past the rows it contributed
+	hasNext := false
+	after := shardedCursor{Shards: count, Positions: make([]shardPosition, count)}
+	for i, p := range position.Positions {
+		if heads[i] > 0 {
+			last := rows[i][heads[i]-1]
+			p.Set, p.Value = true, key.value(ctx, last)
+			if tie != nil {
+				p.Tie = tie.field.value(ctx, last)
+			}
+		}
+		if !p.Done && heads[i] == len(rows[i]) && len(rows[i]) <= pageSize {
+			p.Done = true
+		}
+		hasNext = hasNext || !p.Done
+		after.Positions[i] = p
+	}
+
+	result := &CursorPagination[T]{
+		Items:       items,
+		HasNext:     hasNext,
+		HasPrevious: cursor != "" && !restarted,
+		PageSize:    pageSize,
+		Restarted:   restarted,
+	}
+	if len(items) > 0 {
+		result.FirstKey = key.value(ctx, items[0])
+		result.LastKey = key.value(ctx, items[len(items)-1])
+
+		endCursor, err := encodeShardedCursor(after, o)
+		if err != nil {
+			return nil, err
+		}
+		result.EndCursor = &endCursor
+		if hasNext {
+			nextCursor := endCursor
+			result.NextCursor = &nextCursor
+		}
+	}
+	return result, nil
+}
+
+// shardTotal sums the row counts of every shard
+func shardTotal(shards []*gorm.DB, o options) (int64, error) {
+	counts := make([]int64, len(shards))
+	err := eachShard(len(shards), func(i int) error {
+		var err error
+		if counts[i], _, err = countTotal(shards[i], o); err != nil {
+			return fmt.Errorf("shard %d: %w", i, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, count := range counts {
+		total += count
+	}
+	return total, nil
+}
+
+// eachShard runs fn for shards 0 to count-1 concurrently and returns the first shard's error
+func eachShard(count int, fn func(i int) error) error {
+	errs := make([]error, count)
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = fn(i)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeShardedCursor encodes shard positions as base64url JSON wrapped by the paginator's codec
+func encodeShardedCursor(c shardedCursor, o options) (string, error) {
+	token, err := JSONCursorCodec{}.Encode(c)
+	if err != nil {
+		return "", err
+	}
+	return o.cursorCodec().Encode(token)
+}
+
+// decodeShardedCursor reverses encodeShardedCursor (nil for an empty cursor)
+// restarted reports a codec asking to start over or a cursor issued for a different shard count.
+func decodeShardedCursor(cursor string, shards int, o options) (c *shardedCursor, restarted bool, err error) {
+	value, restarted, err := decodeCursor(cursor, o)
+	if err != nil || restarted || cursor == "" {
+		return nil, restarted, err
+	}
+
+	token, err := cursorString(value)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	c = &shardedCursor{}
+	if err := decoder.Decode(c); err != nil {
+		return nil, false, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	if c.Shards != len(c.Positions) {
+		return nil, false, fmt.Errorf("%w: %d positions for %d shards", ErrInvalidCursor, len(c.Positions), c.Shards)
+	}
+
+	// Shards were added or removed since the cursor was issued; its positions no longer line up
+	if c.Shards != shards {
+		return nil, true, nil
+	}
+
+	for i := range c.Positions {
+		c.Positions[i].Value = jsonNumber(c.Positions[i].Value)
+		c.Positions[i].Tie = jsonNumber(c.Positions[i].Tie)
+	}
+	return c, false, nil
+}
+
+// compareKeys orders two cursor field values of rows from different shards
+// Integers, unsigned integers, floats, strings, and times compare by value; nil sorts first, and
+// anything else falls back to comparing its formatted value.
+func compareKeys(a, b any) int {
+	if a == nil || b == nil {
+		switch {
+		case a == nil && b == nil:
+			return 0
+		case a == nil:
+			return -1
+		default:
+			return 1
+		}
+	}
+
+	if x, ok := a.(time.Time); ok {
+		if y, ok := b.(time.Time); ok {
+			switch {
+			case x.Before(y):
+				return -1
+			case x.After(y):
+				return 1
+			default:
+				return 0
+			}
+		}
+	}
+
+	x, y := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch {
+	case isIntKind(x.Kind()) && isIntKind(y.Kind()):
+		return compareOrdered(x.Int(), y.Int())
+	case isUintKind(x.Kind()) && isUintKind(y.Kind()):
+		return compareOrdered(x.Uint(), y.Uint())
+	case isFloatKind(x.Kind()) && isFloatKind(y.Kind()):
+		return compareOrdered(x.Float(), y.Float())
+	case x.Kind() == reflect.String && y.Kind() == reflect.String:
+		return strings.Compare(x.String(), y.String())
+	default:
+		return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+	}
+}
+
+// compareOrdered returns -1, 0, or 1 as a is less than, equal to, or greater than b
+func compareOrdered[V int64 | uint64 | float64](a, b V) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func isIntKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isUintKind(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uint64
+}
+
+func isFloatKind(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
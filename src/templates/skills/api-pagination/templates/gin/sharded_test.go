@@ -0,0 +1,173 @@
+package pagination
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// skewedShards splits posts 1-9 (in score then id order) over three shards; shard 1 holds a
+// single post and runs out first
+func skewedShards() [][]rankedPost {
+	shard := func(posts ...int64) []rankedPost {
+		var rows []rankedPost
+		for i := 0; i < len(posts); i += 2 {
+			rows = append(rows, rankedPost{ID: posts[i], Score: posts[i+1]})
+		}
+		return rows
+	}
+	return [][]rankedPost{
+		shard(1, 1, 4, 5, 5, 5, 9, 9), // id, score pairs
+		shard(2, 2),
+		shard(3, 5, 6, 5, 7, 8, 8, 8),
+	}
+}
+
+// fakeShardFetch serves each shard's rows past its position the way the shard query filters
+// them (by score, then id); fetches counts the queries per shard
+func fakeShardFetch(shards [][]rankedPost, fetches []int) shardFetch[rankedPost] {
+	return func(i int, p shardPosition, limit int) ([]rankedPost, error) {
+		fetches[i]++
+		var rows []rankedPost
+		for _, row := range shards[i] {
+			if p.Set {
+				score, id := p.Value.(int64), p.Tie.(int64)
+				if row.Score < score || (row.Score == score && row.ID <= id) {
+					continue
+				}
+			}
+			if len(rows) < limit {
+				rows = append(rows, row)
+			}
+		}
+		return rows, nil
+	}
+}
+
+func TestShardedPagesMergeAcrossSkewedShards(t *testing.T) {
+	db, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	o := applyOptions(nil)
+	tie, err := resolveTieBreaker[rankedPost](db, "score", o)
+	if err != nil || tie == nil {
+		t.Fatalf("tie-breaker = %+v, %v", tie, err)
+	}
+	shards := skewedShards()
+	fetches := make([]int, len(shards))
+	fetch := fakeShardFetch(shards, fetches)
+
+	var seen [][]int64
+	cursor := ""
+	for pages := 0; pages < 9; pages++ {
+		var posts []rankedPost
+		result, err := paginateShards(db, fetch, len(shards), &posts, cursor, 2, "score", tie, true, o)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []int64
+		for _, post := range result.Items {
+			ids = append(ids, post.ID)
+		}
+		seen = append(seen, ids)
+
+		if !result.HasNext {
+			if result.NextCursor != nil {
+				t.Errorf("last page has next cursor %q", *result.NextCursor)
+			}
+			break
+		}
+		cursor = *result.NextCursor
+	}
+
+	want := [][]int64{
+		{1, 2},
+		{3, 4},
+		{5, 6},
+		{7, 8},
+		{9},
+	}
+	if !reflect.DeepEqual(seen, want) {
+		t.Fatalf("sharded pages = %v, want %v", seen, want)
+	}
+	// The single-post shard is exhausted by the first page and skipped after it
+	if fetches[1] != 1 {
+		t.Errorf("exhausted shard fetched %d times, want 1", fetches[1])
+	}
+}
+
+func TestShardedCursorRestartsWhenShardsChange(t *testing.T) {
+	db, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	o := applyOptions(nil)
+	tie, _ := resolveTieBreaker[rankedPost](db, "score", o)
+	shards := skewedShards()
+	fetch := fakeShardFetch(shards, make([]int, len(shards)))
+
+	var posts []rankedPost
+	first, err := paginateShards(db, fetch, len(shards), &posts, "", 2, "score", tie, true, o)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A shard was removed: the three-shard cursor starts the two remaining shards over
+	result, err := paginateShards(db, fetch, 2, &posts, *first.NextCursor, 2, "score", tie, true, o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Restarted || result.HasPrevious || len(result.Items) != 2 || result.Items[0].ID != 1 {
+		t.Errorf("changed shard count = %+v, want a restarted first page", result)
+	}
+}
+
+func TestShardedCursorPaginateSumsShardTotals(t *testing.T) {
+	var shards []*gorm.DB
+	for i, rows := range skewedShards()[:2] {
+		rows := rows
+		db, err := gorm.Open(nil, &gorm.Config{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = db.Callback().Query().Register("test:shard", func(tx *gorm.DB) {
+			switch dest := tx.Statement.Dest.(type) {
+			case *[]rankedPost:
+				*dest = append((*dest)[:0], rows...)
+			case *int64:
+				*dest = int64(len(rows))
+				tx.RowsAffected = 1
+			}
+		})
+		if err != nil {
+			t.Fatalf("shard %d: %v", i, err)
+		}
+		shards = append(shards, db.Model(&rankedPost{}))
+	}
+
+	var posts []rankedPost
+	result, err := ShardedCursorPaginate(shards, &posts, "", 3, "score", true, WithShardTotals())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []int64
+	for _, post := range posts {
+		ids = append(ids, post.ID)
+	}
+	if want := []int64{1, 2, 4}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("first page ids = %v, want %v", ids, want)
+	}
+	if result.TotalItems == nil || *result.TotalItems != 5 {
+		t.Errorf("total items = %v, want the shards' 4 + 1", result.TotalItems)
+	}
+	if !result.HasNext || result.NextCursor == nil {
+		t.Errorf("has next = %t, next cursor = %v; want more rows", result.HasNext, result.NextCursor)
+	}
+
+	if _, err := ShardedCursorPaginate[rankedPost](nil, &posts, "", 3, "score", true); !errors.Is(err, ErrNoShards) {
+		t.Errorf("no shards: err = %v, want ErrNoShards", err)
+	}
+}
@@ -0,0 +1,141 @@
+package pagination
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrCursorTampered is returned when a signed cursor's HMAC does not match its contents
+// The client edited or forged the cursor (or it was signed with another secret); respond with
+// 400 and have it restart from the first page.
+var ErrCursorTampered = errors.New("cursor signature does not match")
+
+// signedCursorSeparator joins a cursor to its signature
+// Signatures are base64url, so the last separator is always the signature's.
+const signedCursorSeparator = "."
+
+// EncodeCursorSigned encodes value as Base64CursorCodec does and appends its HMAC-SHA256 tag
+//
+// Example usage:
+//
+//	next := pagination.EncodeCursorSigned(lastID, secret)
+//	// later
+//	value, err := pagination.DecodeCursorSigned(c.Query("cursor"), secret)
+//	if errors.Is(err, pagination.ErrCursorTampered) {
+//	    c.JSON(400, gin.H{"error": "invalid cursor"})
+//	    return
+//	}
+func EncodeCursorSigned(value any, secret []byte) string {
+	return signCursor(encodeValueCursor(value), secret)
+}
+
+// DecodeCursorSigned verifies cursor's tag and decodes it as Base64CursorCodec does
+// A cursor whose tag does not match fails with ErrCursorTampered.
+func DecodeCursorSigned(cursor string, secret []byte) (string, error) {
+	if cursor == "" {
+		return "", nil
+	}
+	payload, err := verifyCursor(cursor, secret)
+	if err != nil {
+		return "", err
+	}
+	return decodeValueCursor(payload)
+}
+
+// signCursor appends the HMAC-SHA256 tag of cursor under secret
+func signCursor(cursor string, secret []byte) string {
+	return cursor + signedCursorSeparator + cursorSignature(cursor, secret)
+}
+
+// verifyCursor strips and checks the tag signCursor appended under any of secrets, comparing
+// in constant time
+func verifyCursor(cursor string, secrets ...[]byte) (string, error) {
+	i := strings.LastIndex(cursor, signedCursorSeparator)
+	if i < 0 {
+		return "", fmt.Errorf("%w: missing signature", ErrCursorTampered)
+	}
+	for _, secret := range secrets {
+		if hmac.Equal([]byte(cursor[i+1:]), []byte(cursorSignature(cursor[:i], secret))) {
+			return cursor[:i], nil
+		}
+	}
+	return "", ErrCursorTampered
+}
+
+// cursorSignature returns the base64url HMAC-SHA256 of cursor under secret
+func cursorSignature(cursor string, secret []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(cursor))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// signedCodec signs base64 cursors with its first secret and verifies them with any
+type signedCodec struct {
+	secrets [][]byte
+}
+
+// NewCursorCodec returns a codec issuing value.signature cursors: the base64 value as
+// Base64CursorCodec encodes it and its HMAC-SHA256 under secret
+// Decode verifies the signature in constant time and fails with ErrInvalidCursor when it does
+// not match. Cursors signed with a previous secret keep
+// verifying while it is listed, so the secret can be rotated without breaking open sessions:
+// deploy the new secret with the old one as previous, then drop the old one once its cursors
+// have expired. Pass the codec with WithCursorCodec; without it cursors stay unsigned.
+//
+// Unlike WithSignedCursors, only the value is signed: the tie-breaker and drift anchor the
+// paginators append stay outside the signature.
+//
+// Example usage:
+//
+//	codec := pagination.NewCursorCodec([]byte(os.Getenv("CURSOR_SECRET")), []byte(os.Getenv("CURSOR_SECRET_PREVIOUS")))
+//	result, err := pagination.CursorPaginateInt(db, &users, cursor, 20, "id", true,
+//	    pagination.WithCursorCodec(codec),
+//	)
+func NewCursorCodec(secret []byte, previous ...[]byte) CursorCodec {
+	return signedCodec{secrets: append([][]byte{secret}, previous...)}
+}
+
+func (c signedCodec) Encode(value any) (string, error) {
+	return signCursor(encodeValueCursor(value), c.secrets[0]), nil
+}
+
+func (c signedCodec) Decode(cursor string) (any, error) {
+	value, err := verifyCursor(cursor, c.secrets...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	return decodeValueCursor(value)
+}
+
+// signCursors serves a page of a WithSignedCursors call
+// It verifies and strips the cursor's signature, runs the paginator on the bare cursor, and
+// signs every cursor of the result.
+func signCursors[T any](
+	cursor string,
+	secret []byte,
+	run func(cursor string, verified Option) (*CursorPagination[T], error),
+) (*CursorPagination[T], error) {
+	if cursor != "" {
+		var err error
+		if cursor, err = verifyCursor(cursor, secret); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := run(cursor, func(o *options) { o.cursorVerified = true })
+	if err != nil {
+		return nil, err
+	}
+
+	for _, cursor := range []**string{&result.NextCursor, &result.PreviousCursor, &result.StartCursor, &result.EndCursor} {
+		if *cursor != nil {
+			signed := signCursor(**cursor, secret)
+			*cursor = &signed
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,150 @@
+package pagination
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+var testCursorSecret = []byte("test-cursor-secret")
+
+func TestSignedCursorRoundTrip(t *testing.T) {
+	cursor := EncodeCursorSigned(42, testCursorSecret)
+	value, err := DecodeCursorSigned(cursor, testCursorSecret)
+	if err != nil || value != "42" {
+		t.Fatalf("decoded %q (%v), want 42", value, err)
+	}
+
+	if _, err := DecodeCursorSigned(cursor, []byte("another-secret")); !errors.Is(err, ErrCursorTampered) {
+		t.Errorf("other secret returned %v, want ErrCursorTampered", err)
+	}
+	if _, err := DecodeCursorSigned(encodeValueCursor(42), testCursorSecret); !errors.Is(err, ErrCursorTampered) {
+		t.Errorf("unsigned cursor returned %v, want ErrCursorTampered", err)
+	}
+}
+
+func TestSignedCursorDetectsEveryFlippedByte(t *testing.T) {
+	cursor := EncodeCursorSigned("2024-05-01T10:00:00Z", testCursorSecret)
+	for i := range cursor {
+		for _, bit := range []byte{0x01, 0x20} {
+			flipped := []byte(cursor)
+			flipped[i] ^= bit
+			if _, err := DecodeCursorSigned(string(flipped), testCursorSecret); !errors.Is(err, ErrCursorTampered) {
+				t.Errorf("byte %d ^ %#x: %q decoded with %v, want ErrCursorTampered", i, bit, flipped, err)
+			}
+		}
+	}
+}
+
+func TestWithSignedCursors(t *testing.T) {
+	db := postsDB(t, postsOf(7, 10))
+	signed := WithSignedCursors(testCursorSecret)
+	var page []post
+
+	first, err := CursorPaginateInt(db, &page, "", 3, "id", true, signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := CursorPaginateInt(db, &page, *first.NextCursor, 3, "id", true, signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(postIDs(second.Items), []int64{4, 5, 6}) {
+		t.Errorf("second page = %v, want [4 5 6]", postIDs(second.Items))
+	}
+
+	// The marked PreviousCursor is signed too and still pages back
+	back, err := CursorPaginateInt(db, &page, *second.PreviousCursor, 3, "id", true, signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(postIDs(back.Items), []int64{1, 2, 3}) {
+		t.Errorf("page back = %v, want [1 2 3]", postIDs(back.Items))
+	}
+
+	// A bumped value keeps the old signature; an unsigned cursor has none
+	payload, err := verifyCursor(*first.NextCursor, testCursorSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bumped := EncodeCursor("id", 5, true) + (*first.NextCursor)[len(payload):]
+	for _, cursor := range []string{bumped, EncodeCursor("id", 3, true)} {
+		if _, err := CursorPaginateInt(db, &page, cursor, 3, "id", true, signed); !errors.Is(err, ErrCursorTampered) {
+			t.Errorf("cursor %q returned %v, want ErrCursorTampered", cursor, err)
+		}
+	}
+}
+
+func TestCursorCodecRejectsTruncatedAndSwappedSignatures(t *testing.T) {
+	codec := NewCursorCodec(testCursorSecret)
+	first, err := codec.Encode(41)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := codec.Encode(42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, err := codec.Decode(second); err != nil || value != "42" {
+		t.Fatalf("decoded %v (%v), want 42", value, err)
+	}
+
+	value, _ := verifyCursor(first, testCursorSecret)
+	signature := first[len(value)+1:]
+	otherValue, _ := verifyCursor(second, testCursorSecret)
+	for name, cursor := range map[string]string{
+		"truncated signature": first[:len(first)-4],
+		"empty signature":     value + signedCursorSeparator,
+		"no signature":        value,
+		"swapped value":       otherValue + signedCursorSeparator + signature,
+	} {
+		if _, err := codec.Decode(cursor); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("%s: %q returned %v, want ErrInvalidCursor", name, cursor, err)
+		}
+	}
+}
+
+func TestCursorCodecRotatesSecrets(t *testing.T) {
+	oldSecret, newSecret := []byte("old-secret"), []byte("new-secret")
+	issuedBefore, _ := NewCursorCodec(oldSecret).Encode(7)
+
+	// During the rotation, old cursors still verify and new ones are signed with the new secret
+	rotating := NewCursorCodec(newSecret, oldSecret)
+	if value, err := rotating.Decode(issuedBefore); err != nil || value != "7" {
+		t.Errorf("cursor signed with the previous secret decoded %v (%v)", value, err)
+	}
+	issuedDuring, _ := rotating.Encode(8)
+	if _, err := NewCursorCodec(oldSecret).Decode(issuedDuring); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("new cursor verified with the old secret alone: %v", err)
+	}
+
+	// Once the old secret is dropped, its cursors fail
+	rotated := NewCursorCodec(newSecret)
+	if _, err := rotated.Decode(issuedBefore); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("old cursor after rotation returned %v, want ErrInvalidCursor", err)
+	}
+	if value, err := rotated.Decode(issuedDuring); err != nil || value != "8" {
+		t.Errorf("cursor signed during the rotation decoded %v (%v)", value, err)
+	}
+}
+
+func TestCursorCodecPaginates(t *testing.T) {
+	db := postsDB(t, postsOf(7, 10))
+	codec := WithCursorCodec(NewCursorCodec(testCursorSecret))
+	var page []post
+
+	first, err := CursorPaginateInt(db, &page, "", 3, "id", true, codec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := CursorPaginateInt(db, &page, *first.NextCursor, 3, "id", true, codec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(postIDs(second.Items), []int64{4, 5, 6}) {
+		t.Errorf("second page = %v, want [4 5 6]", postIDs(second.Items))
+	}
+	if _, err := CursorPaginateInt(db, &page, encodeValueCursor(3), 3, "id", true, codec); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("unsigned cursor returned %v, want ErrInvalidCursor", err)
+	}
+}
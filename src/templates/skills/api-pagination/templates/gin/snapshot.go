@@ -0,0 +1,262 @@
+package pagination
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrSnapshotExpired is returned when a cursor's snapshot can no longer be used
+// Restart pagination without a cursor to pin a fresh snapshot
+var ErrSnapshotExpired = errors.New("pagination snapshot expired")
+
+// Snapshotter pins pagination sessions to a consistent view of the data
+// Begin is called for the first page and returns an opaque token ("" = no snapshot, e.g. on
+// an unsupported database); Use runs a later page's queries against that token's snapshot.
+type Snapshotter interface {
+	Begin(db *gorm.DB) (token string, err error)
+	Use(db *gorm.DB, token string, fn func(tx *gorm.DB) error) error
+}
+
+// snapshotSeparator splits the snapshot token from the wrapped cursor
+const snapshotSeparator = "~"
+
+// snapshotCodec embeds a snapshot token in every cursor it encodes
+type snapshotCodec struct {
+	base  CursorCodec
+	token string
+}
+
+func (c snapshotCodec) Encode(value any) (string, error) {
+	cursor, err := c.base.Encode(value)
+	if err != nil || c.token == "" {
+		return cursor, err
+	}
+	return c.token + snapshotSeparator + cursor, nil
+}
+
+func (c snapshotCodec) Decode(cursor string) (any, error) {
+	if _, rest, found := strings.Cut(cursor, snapshotSeparator); found {
+		cursor = rest
+	}
+	return c.base.Decode(cursor)
+}
+
+// snapshotToken returns the snapshot token embedded in cursor ("" if none)
+func snapshotToken(cursor string) string {
+	token, _, found := strings.Cut(cursor, snapshotSeparator)
+	if !found {
+		return ""
+	}
+	return token
+}
+
+// inSnapshot runs a paginator call inside the cursor's snapshot, pinning a new one for the
+// first page; pinned must be passed back to the paginator so it encodes the token into its
+// cursors and does not pin again
+func inSnapshot[R any](
+	db *gorm.DB,
+	cursor string,
+	o options,
+	run func(tx *gorm.DB, pinned Option) (R, error),
+) (R, error) {
+	var result R
+
+	token := snapshotToken(cursor)
+	if cursor == "" {
+		var err error
+		if token, err = o.snapshots.Begin(db); err != nil {
+			return result, fmt.Errorf("failed to pin snapshot: %w", err)
+		}
+	}
+
+	base := o.cursorCodec()
+	pinned := func(o *options) {
+		o.pinned = true
+		o.codec = snapshotCodec{base: base, token: token}
+	}
+
+	// Unsupported databases (or a full snapshot pool) page without a snapshot
+	if token == "" {
+		return run(db, pinned)
+	}
+
+	err := o.snapshots.Use(db, token, func(tx *gorm.DB) error {
+		var err error
+		result, err = run(tx, pinned)
+		return err
+	})
+	return result, err
+}
+
+// pinnedTxOptions are the options of transactions reading from a snapshot
+var pinnedTxOptions = &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+
+// PostgresSnapshots pins pagination sessions with exported Postgres snapshots
+// Begin opens a read-only REPEATABLE READ transaction, exports its snapshot with
+// pg_export_snapshot(), and keeps the transaction open for ttl; every later page imports the
+// snapshot with SET TRANSACTION SNAPSHOT. Any instance connected to the same server can import
+// it while the exporting instance holds it. Each held snapshot pins a connection, so at most
+// max are held; beyond that (and on other databases) pages run without a snapshot.
+//
+// Example usage:
+//
+//	var snapshots = pagination.NewPostgresSnapshots(5*time.Minute, 20)
+//
+//	func GetLedger(c *gin.Context) {
+//	    var entries []LedgerEntry
+//	    result, err := pagination.CursorPaginateInt(db.Model(&LedgerEntry{}), &entries,
+//	        pagination.GetCursor(c), pagination.GetPageSize(c), "id", true,
+//	        pagination.WithSnapshot(snapshots),
+//	    )
+//	    if errors.Is(err, pagination.ErrSnapshotExpired) {
+//	        c.JSON(409, gin.H{"error": "snapshot expired; restart from the first page"})
+//	        return
+//	    }
+//	    // ...
+//	}
+type PostgresSnapshots struct {
+	ttl time.Duration
+	max int
+
+	mu   sync.Mutex
+	held map[string]*gorm.DB
+}
+
+// NewPostgresSnapshots creates a Snapshotter holding up to max snapshots for ttl each
+// (0 = {{snapshotTTL}}s and {{maxSnapshots}})
+func NewPostgresSnapshots(ttl time.Duration, max int) *PostgresSnapshots {
+	if ttl <= 0 {
+		ttl = {{snapshotTTL}} * time.Second
+	}
+	if max <= 0 {
+		max = {{maxSnapshots}}
+	}
+	return &PostgresSnapshots{ttl: ttl, max: max, held: make(map[string]*gorm.DB)}
+}
+
+// postgresSnapshotID matches snapshot identifiers such as "00000003-0000001B-1"
+var postgresSnapshotID = regexp.MustCompile(`^[0-9A-Fa-f]+(-[0-9A-Fa-f]+)+$`)
+
+// Begin implements Snapshotter
+func (s *PostgresSnapshots) Begin(db *gorm.DB) (string, error) {
+	if db.Dialector.Name() != "postgres" {
+		return "", nil
+	}
+
+	s.mu.Lock()
+	full := len(s.held) >= s.max
+	s.mu.Unlock()
+	if full {
+		return "", nil
+	}
+
+	// The exporting transaction outlives the request that started it
+	tx := db.Session(&gorm.Session{NewDB: true, Context: context.Background()}).Begin(pinnedTxOptions)
+	if tx.Error != nil {
+		return "", tx.Error
+	}
+
+	var id string
+	if err := tx.Raw("SELECT pg_export_snapshot()").Scan(&id).Error; err != nil {
+		tx.Rollback()
+		return "", fmt.Errorf("failed to export snapshot: %w", err)
+	}
+
+	s.mu.Lock()
+	s.held[id] = tx
+	s.mu.Unlock()
+
+	time.AfterFunc(s.ttl, func() { s.release(id) })
+	return id, nil
+}
+
+// Use implements Snapshotter
+func (s *PostgresSnapshots) Use(db *gorm.DB, token string, fn func(tx *gorm.DB) error) error {
+	// SET TRANSACTION SNAPSHOT takes no bind parameters, so the token is validated instead
+	if !postgresSnapshotID.MatchString(token) {
+		return ErrSnapshotExpired
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		// Must be the first statement of the transaction
+		set := fmt.Sprintf("SET TRANSACTION SNAPSHOT '%s'", token)
+		if err := tx.Session(&gorm.Session{NewDB: true}).Exec(set).Error; err != nil {
+			return fmt.Errorf("%w: %v", ErrSnapshotExpired, err)
+		}
+		return fn(tx)
+	}, pinnedTxOptions)
+}
+
+// Close releases every held snapshot (call on shutdown)
+func (s *PostgresSnapshots) Close() {
+	s.mu.Lock()
+	ids := make([]string, 0, len(s.held))
+	for id := range s.held {
+		ids = append(ids, id)
+	}
+	s.mu.Unlock()
+
+	for _, id := range ids {
+		s.release(id)
+	}
+}
+
+// release ends the exporting transaction of a snapshot
+func (s *PostgresSnapshots) release(id string) {
+	s.mu.Lock()
+	tx, ok := s.held[id]
+	delete(s.held, id)
+	s.mu.Unlock()
+
+	if ok {
+		tx.Rollback()
+	}
+}
+
+// AsOfSystemTimeSnapshots pins pagination sessions to a timestamp on CockroachDB
+// Begin records the cluster's current logical timestamp; later pages read with
+// AS OF SYSTEM TIME, so nothing is held open between requests. Timestamps older than the
+// table's GC TTL fail with ErrSnapshotExpired.
+type AsOfSystemTimeSnapshots struct{}
+
+// cockroachTimestamp matches cluster_logical_timestamp() values
+var cockroachTimestamp = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+
+// Begin implements Snapshotter
+func (AsOfSystemTimeSnapshots) Begin(db *gorm.DB) (string, error) {
+	if db.Dialector.Name() != "postgres" {
+		return "", nil
+	}
+
+	var timestamp string
+	err := db.Session(&gorm.Session{NewDB: true}).
+		Raw("SELECT cluster_logical_timestamp()::STRING").
+		Scan(&timestamp).Error
+	if err != nil {
+		return "", fmt.Errorf("failed to read cluster timestamp: %w", err)
+	}
+	return timestamp, nil
+}
+
+// Use implements Snapshotter
+func (AsOfSystemTimeSnapshots) Use(db *gorm.DB, token string, fn func(tx *gorm.DB) error) error {
+	if !cockroachTimestamp.MatchString(token) {
+		return ErrSnapshotExpired
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		set := fmt.Sprintf("SET TRANSACTION AS OF SYSTEM TIME %s", token)
+		if err := tx.Session(&gorm.Session{NewDB: true}).Exec(set).Error; err != nil {
+			return fmt.Errorf("%w: %v", ErrSnapshotExpired, err)
+		}
+		return fn(tx)
+	}, &sql.TxOptions{ReadOnly: true})
+}
@@ -0,0 +1,161 @@
+package pagination
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// fakeSnapshots freezes a copy of rows per token, like a database snapshot
+type fakeSnapshots struct {
+	rows      *[]int64
+	supported bool
+	frozen    map[string][]int64
+	begins    int
+	reading   []int64
+}
+
+func (s *fakeSnapshots) Begin(db *gorm.DB) (string, error) {
+	s.begins++
+	if !s.supported {
+		return "", nil
+	}
+	token := "snap" + strconv.Itoa(s.begins)
+	s.frozen[token] = append([]int64(nil), *s.rows...)
+	return token, nil
+}
+
+func (s *fakeSnapshots) Use(db *gorm.DB, token string, fn func(tx *gorm.DB) error) error {
+	rows, ok := s.frozen[token]
+	if !ok {
+		return ErrSnapshotExpired
+	}
+	s.reading = rows
+	defer func() { s.reading = nil }()
+	return fn(db)
+}
+
+// fakePage pages ascending ids after the cursor, reading the snapshot when one is in use
+func fakePage(s *fakeSnapshots, cursor string, pageSize int) (ids []int64, next string, err error) {
+	next, err = inSnapshot(nil, cursor, applyOptions([]Option{WithSnapshot(s)}),
+		func(tx *gorm.DB, pinned Option) (string, error) {
+			codec := applyOptions([]Option{pinned}).cursorCodec()
+
+			var after int64
+			if cursor != "" {
+				value, err := codec.Decode(cursor)
+				if err != nil {
+					return "", err
+				}
+				if after, err = cursorInt(value); err != nil {
+					return "", err
+				}
+			}
+
+			rows := *s.rows
+			if s.reading != nil {
+				rows = s.reading
+			}
+			for _, id := range rows {
+				if id > after && len(ids) < pageSize {
+					ids = append(ids, id)
+				}
+			}
+			if len(ids) == 0 {
+				return "", nil
+			}
+			return codec.Encode(ids[len(ids)-1])
+		})
+	return ids, next, err
+}
+
+func TestSnapshotContinuity(t *testing.T) {
+	rows := []int64{1, 2, 3, 4, 5, 6}
+	snapshots := &fakeSnapshots{rows: &rows, supported: true, frozen: map[string][]int64{}}
+
+	first, cursor, err := fakePage(snapshots, "", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(cursor, "snap1"+snapshotSeparator) {
+		t.Fatalf("cursor %q does not carry the snapshot token", cursor)
+	}
+	if len(first) != 3 || first[2] != 3 {
+		t.Fatalf("unexpected first page: %v", first)
+	}
+
+	// Rows change between pages: 4 is deleted and 7 is inserted
+	rows = []int64{1, 2, 3, 5, 6, 7}
+
+	second, _, err := fakePage(snapshots, cursor, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int64{4, 5, 6}; !equalIDs(second, want) {
+		t.Errorf("second page = %v, want the snapshot's %v", second, want)
+	}
+	if snapshots.begins != 1 {
+		t.Errorf("later pages pinned a new snapshot (%d begins)", snapshots.begins)
+	}
+
+	// A fresh session sees the current data
+	fresh, _, err := fakePage(snapshots, "", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int64{1, 2, 3, 5, 6, 7}; !equalIDs(fresh, want) {
+		t.Errorf("fresh session = %v, want %v", fresh, want)
+	}
+}
+
+func TestSnapshotFallback(t *testing.T) {
+	rows := []int64{1, 2, 3, 4}
+	snapshots := &fakeSnapshots{rows: &rows, frozen: map[string][]int64{}}
+
+	_, cursor, err := fakePage(snapshots, "", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(cursor, snapshotSeparator) {
+		t.Fatalf("unsupported store produced a snapshot cursor %q", cursor)
+	}
+
+	rows = []int64{1, 2, 4, 5}
+	second, _, err := fakePage(snapshots, cursor, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int64{4, 5}; !equalIDs(second, want) {
+		t.Errorf("second page = %v, want live rows %v", second, want)
+	}
+}
+
+func TestSnapshotExpired(t *testing.T) {
+	rows := []int64{1, 2, 3}
+	snapshots := &fakeSnapshots{rows: &rows, supported: true, frozen: map[string][]int64{}}
+
+	_, cursor, err := fakePage(snapshots, "", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	delete(snapshots.frozen, "snap1")
+
+	if _, _, err := fakePage(snapshots, cursor, 1); !errors.Is(err, ErrSnapshotExpired) {
+		t.Errorf("err = %v, want ErrSnapshotExpired", err)
+	}
+}
+
+func equalIDs(got, want []int64) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
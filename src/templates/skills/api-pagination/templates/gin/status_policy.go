@@ -0,0 +1,144 @@
+package pagination
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrEmptyPage is the error a StatusPolicy answering an empty result with a 4xx responds with
+var ErrEmptyPage = errors.New("no items")
+
+// PageOutcome classifies a paginated result for StatusPolicy
+type PageOutcome int
+
+const (
+	// OutcomeSuccess is a page with items (or a metadata-only page)
+	OutcomeSuccess PageOutcome = iota
+
+	// OutcomeEmpty is a page of an empty result set
+	OutcomeEmpty
+
+	// OutcomeOutOfRange is an empty offset page past the last page of a non-empty result set
+	OutcomeOutOfRange
+)
+
+// StatusPolicy maps pagination outcomes to HTTP status codes, so a whole API answers empty
+// and out-of-range pages the same way instead of deciding in every handler
+// Zero fields mean 200, which makes the zero value (DefaultStatusPolicy) answer 200 for every
+// outcome. 204 writes no body; statuses of 400 and above abort with AbortWithError and
+// ErrPageOutOfRange or ErrEmptyPage, so the api-error-handling skill renders them as problems.
+//
+// Routes using WithStrictPageRange get ErrPageOutOfRange from the paginator instead of an
+// out-of-range page; their error handling decides that status.
+//
+// Example usage:
+//
+//	var listStatus = pagination.StatusPolicy{OutOfRange: 404}
+//
+//	func ListOrders(c *gin.Context) {
+//	    params := pagination.GetPaginationParams(c)
+//
+//	    var orders []Order
+//	    result, err := pagination.OffsetPaginate(db.Model(&Order{}), &orders, params.Page, params.PageSize)
+//	    if err != nil {
+//	        c.JSON(500, gin.H{"error": err.Error()})
+//	        return
+//	    }
+//
+//	    pagination.WriteOffsetPage(c, listStatus, result) // ?page=99 of 3 => 404
+//	}
+type StatusPolicy struct {
+	Success    int
+	Empty      int
+	OutOfRange int
+}
+
+// DefaultStatusPolicy answers 200 for every outcome
+var DefaultStatusPolicy = StatusPolicy{}
+
+// Status returns the HTTP status for outcome
+func (p StatusPolicy) Status(outcome PageOutcome) int {
+	var status int
+	switch outcome {
+	case OutcomeEmpty:
+		status = p.Empty
+	case OutcomeOutOfRange:
+		status = p.OutOfRange
+	default:
+		status = p.Success
+	}
+
+	if status == 0 {
+		return http.StatusOK
+	}
+	return status
+}
+
+// OffsetOutcome classifies an offset page
+// An empty page of a non-empty result set can only lie past the last page, which also covers
+// counts capped by WithMaxReportedTotal.
+func OffsetOutcome[T any](p *OffsetPagination[T]) PageOutcome {
+	switch {
+	case len(p.Items) > 0 || p.MetadataOnly:
+		return OutcomeSuccess
+	case p.TotalItems > 0:
+		return OutcomeOutOfRange
+	default:
+		return OutcomeEmpty
+	}
+}
+
+// CursorOutcome classifies a cursor page
+func CursorOutcome[T any](p *CursorPagination[T]) PageOutcome {
+	if len(p.Items) == 0 {
+		return OutcomeEmpty
+	}
+	return OutcomeSuccess
+}
+
+// WriteOffsetPage writes an offset page as JSON with the status policy chooses for it
+func WriteOffsetPage[T any](c *gin.Context, policy StatusPolicy, result *OffsetPagination[T]) {
+	outcome := OffsetOutcome(result)
+	writePage(c, policy.Status(outcome), result, offsetOutcomeError(result, outcome))
+}
+
+// WriteCursorPage writes a cursor page as JSON with the status policy chooses for it
+func WriteCursorPage[T any](c *gin.Context, policy StatusPolicy, result *CursorPagination[T]) {
+	outcome := CursorOutcome(result)
+	writePage(c, policy.Status(outcome), result, cursorOutcomeError(outcome))
+}
+
+// offsetOutcomeError is the error a 4xx status for outcome responds with
+func offsetOutcomeError[T any](result *OffsetPagination[T], outcome PageOutcome) error {
+	switch outcome {
+	case OutcomeOutOfRange:
+		return fmt.Errorf("%w: page %d of %d", ErrPageOutOfRange, result.CurrentPage, result.TotalPages)
+	case OutcomeEmpty:
+		return ErrEmptyPage
+	default:
+		return nil
+	}
+}
+
+// cursorOutcomeError is the error a 4xx status for outcome responds with
+func cursorOutcomeError(outcome PageOutcome) error {
+	if outcome == OutcomeEmpty {
+		return ErrEmptyPage
+	}
+	return nil
+}
+
+// writePage writes body with status: no body for 204, an error response from 400 on
+func writePage(c *gin.Context, status int, body any, err error) {
+	switch {
+	case status == http.StatusNoContent:
+		c.Status(status)
+	case status >= http.StatusBadRequest && err != nil:
+		AbortWithError(c, status, err)
+	default:
+		c.JSON(status, body)
+	}
+}
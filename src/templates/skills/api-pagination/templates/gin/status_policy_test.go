@@ -0,0 +1,36 @@
+package pagination
+
+import "testing"
+
+func TestStatusPolicy(t *testing.T) {
+	policy := StatusPolicy{OutOfRange: 404}
+
+	cases := []struct {
+		name string
+		page *OffsetPagination[int]
+		want int
+	}{
+		{"success", &OffsetPagination[int]{Items: []int{1, 2}, CurrentPage: 1, TotalItems: 2, TotalPages: 1}, 200},
+		{"empty result set", &OffsetPagination[int]{Items: []int{}, CurrentPage: 1}, 200},
+		{"past the last page", &OffsetPagination[int]{Items: []int{}, CurrentPage: 9, TotalItems: 40, TotalPages: 2}, 404},
+		{"metadata only", &OffsetPagination[int]{Items: []int{}, CurrentPage: 9, TotalItems: 40, TotalPages: 2, MetadataOnly: true}, 200},
+	}
+
+	for _, tc := range cases {
+		if got := policy.Status(OffsetOutcome(tc.page)); got != tc.want {
+			t.Errorf("%s: status = %d, want %d", tc.name, got, tc.want)
+		}
+	}
+
+	// The default answers 200 whatever the outcome
+	for _, outcome := range []PageOutcome{OutcomeSuccess, OutcomeEmpty, OutcomeOutOfRange} {
+		if got := DefaultStatusPolicy.Status(outcome); got != 200 {
+			t.Errorf("default policy: outcome %d => %d, want 200", outcome, got)
+		}
+	}
+
+	feedEnd := &CursorPagination[int]{Items: nil, HasPrevious: true}
+	if got := (StatusPolicy{Empty: 204}).Status(CursorOutcome(feedEnd)); got != 204 {
+		t.Errorf("empty cursor page: status = %d, want 204", got)
+	}
+}
@@ -0,0 +1,194 @@
+package pagination
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// ErrTieBreakerRequired is returned when WithRowIDTieBreaker is used on a dialect without
+// SQLite's implicit rowid
+// Sort by a unique column (or add one after the cursor field) instead.
+var ErrTieBreakerRequired = errors.New("cursor pagination needs a unique tie-breaker column")
+
+// tieBreakerSeparator joins a cursor to the tie-breaker value of its row
+// It is appended before the drift anchor and is outside both base64 alphabets; the value is
+// JSON-base64url encoded.
+const tieBreakerSeparator = "~"
+
+// tieBreaker is the unique column ordering rows that share a cursor field value
+type tieBreaker struct {
+	column string
+
+	// field reads the column from page items; nil for rowid, which models do not map
+	field *fieldExtractor
+}
+
+// resolveTieBreaker picks the cursor paginators' tie-breaker for cursorField on T
+// WithRowIDTieBreaker selects SQLite's rowid. Otherwise T's primary key breaks ties, unless
+// the cursor field is the primary key or tagged unique (unless WithCompactCursor needs the key
+// to find truncated values), T has no single primary key, or WithoutTieBreaker opts out
+// (nil = order by the cursor field alone).
+func resolveTieBreaker[T any](db *gorm.DB, cursorField string, o options) (*tieBreaker, error) {
+	if o.rowIDTieBreaker {
+		if err := checkRowIDDialect(db); err != nil {
+			return nil, err
+		}
+		return &tieBreaker{column: "rowid"}, nil
+	}
+	if o.noTieBreaker {
+		return nil, nil
+	}
+
+	// Models the schema cannot describe are reported by boundaryKeys
+	modelSchema, err := schema.Parse(new(T), &schemaCache, db.NamingStrategy)
+	if err != nil {
+		return nil, nil
+	}
+	field := modelSchema.LookUpField(fieldName(cursorField))
+	primary := modelSchema.PrioritizedPrimaryField
+	if field == nil || primary == nil || field.PrimaryKey || (field.Unique && o.compactCursor == 0) {
+		return nil, nil
+	}
+
+	column := primary.DBName
+	if i := strings.LastIndex(cursorField, "."); i >= 0 {
+		column = cursorField[:i+1] + column
+	}
+	return &tieBreaker{column: column, field: &fieldExtractor{field: primary}}, nil
+}
+
+// checkRowIDDialect fails with ErrTieBreakerRequired unless db is SQLite
+func checkRowIDDialect(db *gorm.DB) error {
+	name := "an unknown dialect"
+	if db.Dialector != nil {
+		name = db.Dialector.Name()
+	}
+	if name != "sqlite" {
+		return fmt.Errorf("%w: %s has no rowid", ErrTieBreakerRequired, name)
+	}
+	return nil
+}
+
+// splitTieBreaker separates a cursor into the page cursor and its row's tie-breaker value
+// ok is false for a cursor issued without one, which continues on the cursor field alone.
+func splitTieBreaker(cursor string) (page string, value any, ok bool, err error) {
+	i := strings.LastIndex(cursor, tieBreakerSeparator)
+	if i < 0 {
+		return cursor, nil, false, nil
+	}
+
+	value, err = JSONCursorCodec{}.Decode(cursor[i+1:])
+	if err != nil {
+		return "", nil, false, fmt.Errorf("%w tie-breaker: %v", ErrInvalidCursor, err)
+	}
+	return cursor[:i], jsonNumber(value), true, nil
+}
+
+// jsonNumber converts a decoded json.Number to int64 (or float64) for use as a query argument
+// Other values are returned unchanged.
+func jsonNumber(value any) any {
+	number, ok := value.(json.Number)
+	if !ok {
+		return value
+	}
+	if n, err := number.Int64(); err == nil {
+		return n
+	}
+	if f, err := number.Float64(); err == nil {
+		return f
+	}
+	return value
+}
+
+// tieBreakerCondition is cursorCondition with ties in the cursor field broken by column
+// It takes the cursor value twice, then the tie-breaker value.
+func tieBreakerCondition(cursorField, column string, ascending, inclusive bool) string {
+	op := ">"
+	if !ascending {
+		op = "<"
+	}
+	tieOp := op
+	if inclusive {
+		tieOp += "="
+	}
+	return fmt.Sprintf("(%s %s ? OR (%s = ? AND %s %s ?))", cursorField, op, cursorField, column, tieOp)
+}
+
+// tieBreakerOrder is the ORDER BY term following the cursor field's
+func tieBreakerOrder(column string, ascending bool) string {
+	if ascending {
+		return column + " ASC"
+	}
+	return column + " DESC"
+}
+
+// tieBreakerValues reads the tie-breaker values of the first and last items of a page
+func tieBreakerValues[T any](query *gorm.DB, items []T, tie *tieBreaker, o options) (first, last any, err error) {
+	if tie == nil || len(items) == 0 {
+		return nil, nil, nil
+	}
+	if tie.field == nil {
+		rowIDs, err := pageRowIDs(query, len(items), o)
+		if err != nil {
+			return nil, nil, err
+		}
+		// A backward page's items were reversed after the scan the rowids follow
+		if o.backward {
+			return rowIDs[len(rowIDs)-1], rowIDs[0], nil
+		}
+		return rowIDs[0], rowIDs[len(rowIDs)-1], nil
+	}
+
+	ctx := queryContext(query)
+	return tie.field.value(ctx, items[0]), tie.field.value(ctx, items[len(items)-1]), nil
+}
+
+// pageRowIDs reads the rowids of the first count rows of query, in scan order
+// The order ends in rowid, so the rows are the ones the page fetched unless a write landed in
+// between.
+func pageRowIDs(query *gorm.DB, count int, o options) ([]int64, error) {
+	var rowIDs []int64
+	err := o.fetchLimiter.do(queryContext(query), func() error {
+		return o.onFetchDB(query).Session(&gorm.Session{}).Limit(count).Pluck("rowid", &rowIDs).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch rowids: %w", err)
+	}
+	if len(rowIDs) != count {
+		return nil, fmt.Errorf("failed to fetch rowids: got %d for %d items", len(rowIDs), count)
+	}
+	return rowIDs, nil
+}
+
+// applyTieBreaker appends the tie-breaker value of each cursor's row to the cursors of
+// result (no-op without a tie-breaker); first and last are those of the page's items
+func applyTieBreaker[T any](result *CursorPagination[T], tie *tieBreaker, first, last any) error {
+	if tie == nil || len(result.Items) == 0 {
+		return nil
+	}
+
+	firstToken, err := JSONCursorCodec{}.Encode(first)
+	if err != nil {
+		return err
+	}
+	lastToken, err := JSONCursorCodec{}.Encode(last)
+	if err != nil {
+		return err
+	}
+	for _, cursor := range []*string{result.PreviousCursor, result.StartCursor} {
+		if cursor != nil {
+			*cursor += tieBreakerSeparator + firstToken
+		}
+	}
+	for _, cursor := range []*string{result.NextCursor, result.EndCursor} {
+		if cursor != nil {
+			*cursor += tieBreakerSeparator + lastToken
+		}
+	}
+	return nil
+}
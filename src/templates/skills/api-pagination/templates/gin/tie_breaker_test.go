@@ -0,0 +1,313 @@
+package pagination
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// rankedPost is ordered by score, which has duplicates; ID is its primary key
+type rankedPost struct {
+	ID    int64
+	Score int64
+}
+
+// fakeTiePage pages rows (sorted by score, then their tie-breaker column) the way
+// CursorPaginateInt does on score, filtering with the condition tieBreakerCondition builds
+// tieOf reads a row's tie-breaker value, standing in for the column the database compares.
+func fakeTiePage(t *testing.T, rows []rankedPost, tie *tieBreaker, tieOf func(rankedPost) int64, cursor string, pageSize int, opts ...Option) *CursorPagination[rankedPost] {
+	t.Helper()
+	o := applyOptions(opts)
+	codec := o.cursorCodec()
+
+	condition := tieBreakerCondition("score", tie.column, true, o.inclusiveCursor)
+	var op, tieOp string
+	want := fmt.Sprintf("(score %%s ? OR (score = ? AND %s %%s ?))", tie.column)
+	if _, err := fmt.Sscanf(condition, want, &op, &tieOp); err != nil {
+		t.Fatalf("unexpected condition %q", condition)
+	}
+
+	var after, afterTie int64
+	if cursor != "" {
+		page, value, ok, err := splitTieBreaker(cursor)
+		if err != nil || !ok {
+			t.Fatalf("cursor %q has no tie-breaker: %v", cursor, err)
+		}
+		decoded, err := codec.Decode(page)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if after, err = cursorInt(decoded); err != nil {
+			t.Fatal(err)
+		}
+		afterTie = value.(int64)
+	}
+
+	var items []rankedPost
+	for _, row := range rows {
+		if cursor != "" {
+			tied := row.Score == after && (tieOf(row) > afterTie || (tieOp == ">=" && tieOf(row) == afterTie))
+			if row.Score <= after && !tied {
+				continue
+			}
+		}
+		if len(items) < pageSize {
+			items = append(items, row)
+		}
+	}
+
+	result := &CursorPagination[rankedPost]{Items: items, PageSize: pageSize}
+	if len(items) > 0 {
+		result.FirstKey, result.LastKey = items[0].Score, items[len(items)-1].Score
+	}
+	start, end, err := boundaryCursors(codec, len(items), result.FirstKey, result.LastKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result.StartCursor, result.EndCursor = start, end
+
+	var first, last any
+	if len(items) > 0 {
+		first, last = tieOf(items[0]), tieOf(items[len(items)-1])
+	}
+	if err := applyTieBreaker(result, tie, first, last); err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+// duplicateScores returns posts 1-7 whose scores are mostly equal, sorted by score then ID
+func duplicateScores() []rankedPost {
+	var rows []rankedPost
+	for i, score := range []int64{1, 5, 5, 5, 5, 5, 9} {
+		rows = append(rows, rankedPost{ID: int64(i + 1), Score: score})
+	}
+	return rows
+}
+
+func TestPrimaryKeyTieBreakerPagesThroughDuplicates(t *testing.T) {
+	db, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tie, err := resolveTieBreaker[rankedPost](db, "score", applyOptions(nil))
+	if err != nil || tie == nil || tie.column != "id" {
+		t.Fatalf("tie-breaker = %+v, %v; want the primary key", tie, err)
+	}
+	rows := duplicateScores()
+	tieOf := func(row rankedPost) int64 { return row.ID }
+
+	var seen []int64
+	cursor := ""
+	for pages := 0; pages < len(rows); pages++ {
+		page := fakeTiePage(t, rows, tie, tieOf, cursor, 2)
+		for _, row := range page.Items {
+			seen = append(seen, row.ID)
+		}
+		if len(page.Items) < 2 {
+			break
+		}
+		cursor = *page.EndCursor
+	}
+	if want := []int64{1, 2, 3, 4, 5, 6, 7}; !reflect.DeepEqual(seen, want) {
+		t.Fatalf("ids seen = %v, want every row once: %v", seen, want)
+	}
+
+	// Re-issuing a start cursor inclusively fetches the identical window
+	second := fakeTiePage(t, rows, tie, tieOf, *fakeTiePage(t, rows, tie, tieOf, "", 2).EndCursor, 2)
+	again := fakeTiePage(t, rows, tie, tieOf, *second.StartCursor, 2, WithInclusiveCursor())
+	if !reflect.DeepEqual(again.Items, second.Items) {
+		t.Errorf("inclusive start cursor = %v, want %v", again.Items, second.Items)
+	}
+}
+
+func TestRowIDTieBreakerPagesThroughDuplicates(t *testing.T) {
+	rows := duplicateScores()
+	tie := &tieBreaker{column: "rowid"}
+	tieOf := func(row rankedPost) int64 { return row.ID + 100 } // rowids the model does not map
+
+	var seen []int64
+	cursor := ""
+	for pages := 0; pages < len(rows); pages++ {
+		page := fakeTiePage(t, rows, tie, tieOf, cursor, 3)
+		for _, row := range page.Items {
+			seen = append(seen, row.ID)
+		}
+		if len(page.Items) < 3 {
+			break
+		}
+		cursor = *page.EndCursor
+	}
+	if want := []int64{1, 2, 3, 4, 5, 6, 7}; !reflect.DeepEqual(seen, want) {
+		t.Fatalf("ids seen = %v, want every row once: %v", seen, want)
+	}
+}
+
+// uniqueSlugPost declares its sort column unique
+type uniqueSlugPost struct {
+	ID   int64
+	Slug string `gorm:"unique"`
+	Rank int64
+}
+
+func TestResolveTieBreaker(t *testing.T) {
+	db, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name    string
+		resolve func() (*tieBreaker, error)
+		column  string
+	}{
+		{"non-unique column", func() (*tieBreaker, error) {
+			return resolveTieBreaker[uniqueSlugPost](db, "rank", applyOptions(nil))
+		}, "id"},
+		{"qualified column", func() (*tieBreaker, error) {
+			return resolveTieBreaker[uniqueSlugPost](db, "posts.rank", applyOptions(nil))
+		}, "posts.id"},
+		{"primary key", func() (*tieBreaker, error) {
+			return resolveTieBreaker[uniqueSlugPost](db, "id", applyOptions(nil))
+		}, ""},
+		{"unique column", func() (*tieBreaker, error) {
+			return resolveTieBreaker[uniqueSlugPost](db, "slug", applyOptions(nil))
+		}, ""},
+		{"opted out", func() (*tieBreaker, error) {
+			return resolveTieBreaker[uniqueSlugPost](db, "rank", applyOptions([]Option{WithoutTieBreaker()}))
+		}, ""},
+	}
+	for _, tc := range cases {
+		tie, err := tc.resolve()
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		column := ""
+		if tie != nil {
+			column = tie.column
+		}
+		if column != tc.column {
+			t.Errorf("%s: tie-breaker column = %q, want %q", tc.name, column, tc.column)
+		}
+	}
+}
+
+func TestCursorCarriesPrimaryKeyTieBreaker(t *testing.T) {
+	db, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Callback().Query().Register("test:ranked", func(tx *gorm.DB) {
+		if dest, ok := tx.Statement.Dest.(*[]rankedPost); ok {
+			*dest = append((*dest)[:0], duplicateScores()[1:5]...)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var posts []rankedPost
+	result, err := CursorPaginateInt(db.Model(&rankedPost{}), &posts, "", 3, "score", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, value, ok, err := splitTieBreaker(*result.EndCursor)
+	if err != nil || !ok || value != int64(4) {
+		t.Fatalf("end cursor tie-breaker = %v (%t, %v), want the last post's id 4", value, ok, err)
+	}
+	if decoded, _ := DecodeCursor(page, "score", true); decoded != "5" {
+		t.Errorf("end cursor value = %v, want score 5", decoded)
+	}
+
+	// Cursors issued before the tie-breaker applied continue on the cursor field alone
+	if _, _, ok, err := splitTieBreaker(EncodeCursor("score", 5, true)); ok || err != nil {
+		t.Errorf("plain cursor: ok %t, err %v", ok, err)
+	}
+	if _, _, _, err := splitTieBreaker(EncodeCursor("score", 5, true) + tieBreakerSeparator + "!"); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("malformed tie-breaker: err = %v, want ErrInvalidCursor", err)
+	}
+}
+
+// namedDialector reports a dialect name and nothing else
+type namedDialector struct {
+	gorm.Dialector
+	name string
+}
+
+func (d namedDialector) Name() string {
+	return d.name
+}
+
+func TestRowIDTieBreakerRequiresSQLite(t *testing.T) {
+	db, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db.Dialector = namedDialector{name: "sqlite"}
+	if err := checkRowIDDialect(db); err != nil {
+		t.Errorf("sqlite: %v", err)
+	}
+
+	db.Dialector = namedDialector{name: "postgres"}
+	var events []event
+	_, err = CursorPaginateInt(db, &events, "", 10, "id", true, WithRowIDTieBreaker())
+	if !errors.Is(err, ErrTieBreakerRequired) {
+		t.Errorf("postgres: err = %v, want ErrTieBreakerRequired", err)
+	}
+}
+
+func TestRowIDTieBreakerBackwardPage(t *testing.T) {
+	db, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Dialector = namedDialector{name: "sqlite"}
+
+	// A backward page scans posts 6, 5, 4 (and the extra 3) nearest the cursor first; each
+	// post's rowid is ten times its id
+	scanned := []rankedPost{
+		{ID: 6, Score: 5},
+		{ID: 5, Score: 5},
+		{ID: 4, Score: 5},
+		{ID: 3, Score: 5},
+	}
+	err = db.Callback().Query().Register("test:rowids", func(tx *gorm.DB) {
+		switch dest := tx.Statement.Dest.(type) {
+		case *[]rankedPost:
+			*dest = append((*dest)[:0], scanned...)
+		case *[]int64:
+			limit := *tx.Statement.Clauses["LIMIT"].Expression.(clause.Limit).Limit
+			for _, row := range scanned[:limit] {
+				*dest = append(*dest, row.ID*10)
+			}
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var posts []rankedPost
+	result, err := CursorPaginateInt(db.Model(&rankedPost{}), &posts, EncodeCursor("score", 9, true), 3, "score", true, WithRowIDTieBreaker(), WithBackward())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := []int64{posts[0].ID, posts[1].ID, posts[2].ID}; !reflect.DeepEqual(got, []int64{4, 5, 6}) {
+		t.Fatalf("page = %v, want posts 4, 5, 6", got)
+	}
+	for name, tc := range map[string]struct {
+		cursor *string
+		want   int64
+	}{
+		"start": {result.StartCursor, 40},
+		"end":   {result.EndCursor, 60},
+	} {
+		if _, value, ok, err := splitTieBreaker(*tc.cursor); err != nil || !ok || value != tc.want {
+			t.Errorf("%s cursor rowid = %v (%t, %v), want %d", name, value, ok, err, tc.want)
+		}
+	}
+}
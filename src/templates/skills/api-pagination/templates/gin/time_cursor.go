@@ -0,0 +1,312 @@
+package pagination
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CursorPaginateTime paginates using a timestamp cursor (like created_at)
+// Cursors carry the boundary rows' timestamps as RFC 3339 text in UTC with nanosecond
+// precision, and are bound to the WHERE clause as time.Time, so drivers compare timestamps
+// rather than formatted strings and sub-second rows are not skipped. Rows whose cursor column
+// is NULL are left out of every page. Timestamps that collide are kept apart by the primary key
+// tie-breaker, as with the other cursor paginators.
+//
+// The column must keep the precision the cursor carries (e.g. MySQL DATETIME(6)), and SQLite,
+// which stores timestamps as text, must store them in UTC to compare in order.
+//
+// Example usage:
+//
+//	result, err := pagination.CursorPaginateTime(db.Model(&Event{}), &events, c.Query("cursor"), 50, "created_at", false)
+//	// next_cursor decodes to e.g. "2024-03-01T09:30:00.123456Z"
+func CursorPaginateTime[T any](
+	db *gorm.DB,
+	dest *[]T,
+	cursor string,
+	pageSize int,
+	cursorField string,
+	ascending bool,
+	opts ...Option,
+) (*CursorPagination[T], error) {
+	o := applyOptions(opts)
+	o.defaultPayloadCodec(cursorField, ascending)
+	db = o.bindContext(db)
+
+	// Verify the cursor's signature before anything else reads it
+	if o.cursorSecret != nil && !o.cursorVerified {
+		return signCursors(cursor, o.cursorSecret, func(cursor string, verified Option) (*CursorPagination[T], error) {
+			return CursorPaginateTime(db, dest, cursor, pageSize, cursorField, ascending, append(opts[:len(opts):len(opts)], verified)...)
+		})
+	}
+
+	// Reject stale cursors; inside the signature, which covers their issue time
+	if o.cursorTTL > 0 && !o.cursorStamped {
+		return expireCursors(cursor, o.cursorTTL, func(cursor string, stamped Option) (*CursorPagination[T], error) {
+			return CursorPaginateTime(db, dest, cursor, pageSize, cursorField, ascending, append(opts[:len(opts):len(opts)], stamped)...)
+		})
+	}
+
+	// Count the session's pages before anything else reads the cursor
+	if o.pageLimit != nil && !o.pageLimited {
+		return limitSessionPages(cursor, o.pageLimit, func(cursor string, counted Option) (*CursorPagination[T], error) {
+			return CursorPaginateTime(db, dest, cursor, pageSize, cursorField, ascending, append(opts[:len(opts):len(opts)], counted)...)
+		})
+	}
+
+	// A PreviousCursor carries its direction: it pages backward without WithBackward
+	if rest, before := splitBackwardCursor(cursor); before {
+		cursor, o.backward = rest, true
+		opts = append(opts[:len(opts):len(opts)], WithBackward())
+	}
+
+	// Re-enter inside the session's snapshot so every query reads from it
+	if o.snapshots != nil && !o.pinned {
+		return inSnapshot(db, cursor, o, func(tx *gorm.DB, pinned Option) (*CursorPagination[T], error) {
+			pinnedOpts := append(opts[:len(opts):len(opts)], pinned)
+			return CursorPaginateTime(tx, dest, cursor, pageSize, cursorField, ascending, pinnedOpts...)
+		})
+	}
+
+	// Serve repeated pages from the page cache
+	if o.usePageCache() {
+		result, cached, err := cachedCursorPage(db, dest, o, cursorField, func(filling Option) (*CursorPagination[T], error) {
+			return CursorPaginateTime(db, dest, cursor, pageSize, cursorField, ascending, append(opts[:len(opts):len(opts)], filling)...)
+		}, "cursor-time", cursor, pageSize, cursorField, ascending, queryMaxPageSize(db))
+		if cached {
+			return result, err
+		}
+	}
+
+	// Read from the replica, falling back to the primary
+	if o.usesReplica() {
+		return readReplicated(queryContext(db), o.replica, func(route func(*gorm.DB) *gorm.DB, routed Option) (*CursorPagination[T], error) {
+			return CursorPaginateTime(route(db), dest, cursor, pageSize, cursorField, ascending, append(opts[:len(opts):len(opts)], routed)...)
+		})
+	}
+
+	if err := checkDestType[T](db); err != nil {
+		return nil, err
+	}
+
+	// Resolve a joined table's cursor field to its quoted column and the column items carry
+	column, err := resolveCursorColumn(db, cursorField, o)
+	if err != nil {
+		return nil, err
+	}
+
+	// Constrain page size
+	if limit := queryMaxPageSize(db); pageSize > limit {
+		pageSize = limit
+	}
+	if pageSize < 1 {
+		pageSize = o.config.DefaultPageSize
+	}
+
+	// Detach the session anchor WithDriftDetection appends to cursors
+	var anchor any
+	if o.driftDetection && cursor != "" {
+		var err error
+		if cursor, anchor, err = splitDriftAnchor(cursor); err != nil {
+			return nil, err
+		}
+		if anchor, err = cursorTime(anchor); err != nil {
+			return nil, fmt.Errorf("%w anchor: %v", ErrInvalidCursor, err)
+		}
+	}
+
+	// Detach the tie-breaker value appended to cursors when the cursor field is not unique
+	tie, err := resolveTieBreaker[T](db, column.key, o)
+	if err != nil {
+		return nil, err
+	}
+	if err := qualifyTieBreaker[T](db, tie, column); err != nil {
+		return nil, err
+	}
+	var tieValue any
+	var hasTieValue bool
+	if tie != nil && cursor != "" {
+		if cursor, tieValue, hasTieValue, err = splitTieBreaker(cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	// Decode the cursor; a codec may send the request back to the first page
+	decodedCursor, restarted, err := decodeCursor(cursor, o)
+	if err != nil {
+		return nil, err
+	}
+	if restarted {
+		cursor, anchor = "", nil
+	}
+
+	// Rows without a timestamp have no place in the order; leave them out of every page
+	db = db.Session(&gorm.Session{}).Where(column.sql + " IS NOT NULL")
+
+	// Snapshot the query before the cursor filter for hybrid offset counting
+	base := db.Session(&gorm.Session{})
+	query := db.Session(&gorm.Session{})
+
+	// A backward page scans from the cursor toward the start of the order
+	scan := ascending != o.backward
+
+	// Apply cursor filter if provided
+	if cursor != "" {
+		cursorValue, err := cursorTime(decodedCursor)
+		if err != nil {
+			return nil, fmt.Errorf("%w value: %v", ErrInvalidCursor, err)
+		}
+
+		if hasTieValue {
+			query = query.Where(tieBreakerCondition(column.sql, tie.column, scan, o.inclusiveCursor), cursorValue, cursorValue, tieValue)
+		} else {
+			query = query.Where(cursorCondition(column.sql, scan, o.inclusiveCursor), cursorValue)
+		}
+	}
+
+	// Order by cursor field
+	if scan {
+		query = query.Order(fmt.Sprintf("%s ASC", column.sql))
+	} else {
+		query = query.Order(fmt.Sprintf("%s DESC", column.sql))
+	}
+	if tie != nil {
+		query = query.Order(tieBreakerOrder(tie.column, scan))
+	}
+
+	// Fetch one extra item to check for next page
+	var items []T
+	err = o.fetchLimiter.do(queryContext(query), func() error {
+		return o.onFetchDB(query).Limit(sqlLimit(query, pageSize+1)).Find(&items).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch items: %w", err)
+	}
+
+	hasNext := len(items) > pageSize
+	fetched := items
+	if hasNext {
+		items = items[:pageSize]
+	}
+
+	// Cut the page at the client's byte budget
+	items, hasNext, pageBytes, err := applyByteBudget(items, hasNext, o)
+	if err != nil {
+		return nil, err
+	}
+
+	// A backward page was read nearest the cursor first: the extra row tells whether a page
+	// precedes it, and the cursor's own row follows it
+	hasPrevious := cursor != ""
+	if o.backward {
+		hasNext, hasPrevious = cursor != "", hasNext
+		reverseItems(items)
+	}
+
+	items = nonNilItems(items)
+	*dest = items
+
+	firstTie, lastTie, err := tieBreakerValues(query, items, tie, o)
+	if err != nil {
+		return nil, err
+	}
+
+	firstKey, lastKey, err := boundaryKeys(db, items, column.key)
+	if err != nil {
+		return nil, err
+	}
+
+	// Encode the keys in UTC at full precision, whatever the driver's location
+	firstKey, lastKey = utcTime(firstKey), utcTime(lastKey)
+	startCursor, endCursor, err := boundaryCursors(o.cursorCodec(), len(items), timeCursorValue(firstKey), timeCursorValue(lastKey))
+	if err != nil {
+		return nil, err
+	}
+
+	tail, leading := pageTail(base, query, column.sql, tie, lastKey, lastTie, len(items), ascending, o)
+	approxRemaining, err := resolveApproxRemaining(tail, leading, hasNext, o)
+	if err != nil {
+		return nil, err
+	}
+
+	hybrid, err := resolveHybridOffset(base, tail, len(items)-leading, pageSize, o)
+	if err != nil {
+		return nil, err
+	}
+
+	// Continue from the boundary items' cursor field values, as StartCursor and EndCursor do
+	var nextCursor *string
+	var previousCursor *string
+	if hasNext {
+		nextCursor = copyCursor(endCursor)
+	}
+	if hasPrevious {
+		previousCursor = copyCursor(startCursor)
+	}
+
+	result := &CursorPagination[T]{
+		Items:           items,
+		NextCursor:      nextCursor,
+		PreviousCursor:  previousCursor,
+		HasNext:         hasNext,
+		HasPrevious:     hasPrevious,
+		PageSize:        pageSize,
+		ApproxRemaining: approxRemaining,
+		StartCursor:     startCursor,
+		EndCursor:       endCursor,
+		FirstKey:        firstKey,
+		LastKey:         lastKey,
+		Restarted:       restarted,
+	}
+	if o.byteBudget > 0 {
+		result.PageBytes, result.RowCount = pageBytes, len(items)
+	}
+	if o.peek && hasNext && !o.backward {
+		preview := fetched[len(items)]
+		result.NextPreview = &preview
+	}
+	applyHybridOffset(result, hybrid)
+	if err := applyTieBreaker(result, tie, firstTie, lastTie); err != nil {
+		return nil, err
+	}
+
+	if err := applyDriftDetection(result, base, column.sql, ascending, anchor, o); err != nil {
+		return nil, err
+	}
+	result.PreviousCursor = markBackwardCursor(result.PreviousCursor)
+
+	return result, nil
+}
+
+// cursorTime converts a decoded cursor value to a time cursor in UTC
+func cursorTime(value any) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v.UTC(), nil
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return t.UTC(), nil
+	default:
+		return time.Time{}, fmt.Errorf("unsupported cursor value type %T", value)
+	}
+}
+
+// utcTime returns a page key read from a time column in UTC (other values are unchanged)
+func utcTime(key any) any {
+	if t, ok := key.(time.Time); ok {
+		return t.UTC()
+	}
+	return key
+}
+
+// timeCursorValue formats a time key as the RFC 3339 text its cursors carry
+func timeCursorValue(key any) any {
+	if t, ok := key.(time.Time); ok {
+		return t.Format(time.RFC3339Nano)
+	}
+	return key
+}
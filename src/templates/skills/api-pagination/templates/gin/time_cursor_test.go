@@ -0,0 +1,177 @@
+package pagination
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type reading struct {
+	ID      int64
+	TakenAt *time.Time
+}
+
+// readingsDB serves readings the way a database would for CursorPaginateTime's queries: NULL
+// timestamps filtered by IS NOT NULL, rows past the bound time (then ID) in the query's order
+// Bound times that are not in UTC fail the test.
+func readingsDB(t *testing.T, rows []reading) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Callback().Query().Register("test:readings", func(tx *gorm.DB) {
+		dest, ok := tx.Statement.Dest.(*[]reading)
+		if !ok {
+			return
+		}
+		notNull, descending := false, false
+		var bound []any
+		if c, ok := tx.Statement.Clauses["WHERE"]; ok {
+			for _, e := range c.Expression.(clause.Where).Exprs {
+				expr, ok := e.(clause.Expr)
+				if !ok {
+					continue
+				}
+				if strings.HasSuffix(expr.SQL, "IS NOT NULL") {
+					notNull = true
+				} else if len(expr.Vars) > 0 {
+					bound = expr.Vars
+				}
+			}
+		}
+		if c, ok := tx.Statement.Clauses["ORDER BY"]; ok {
+			descending = strings.HasSuffix(c.Expression.(clause.OrderBy).Columns[0].Column.Name, "DESC")
+		}
+
+		sorted := append([]reading(nil), rows...)
+		sort.Slice(sorted, func(i, j int) bool {
+			a, b := sorted[i], sorted[j]
+			if a.TakenAt == nil || b.TakenAt == nil {
+				return a.TakenAt == nil && b.TakenAt != nil
+			}
+			if !a.TakenAt.Equal(*b.TakenAt) {
+				return a.TakenAt.Before(*b.TakenAt) != descending
+			}
+			if descending {
+				return a.ID > b.ID
+			}
+			return a.ID < b.ID
+		})
+
+		limit := *tx.Statement.Clauses["LIMIT"].Expression.(clause.Limit).Limit
+		for _, r := range sorted {
+			if r.TakenAt == nil {
+				if notNull {
+					continue
+				}
+			} else if len(bound) > 0 {
+				at := bound[0].(time.Time)
+				if at.Location() != time.UTC {
+					t.Errorf("bound time %v is not in UTC", at)
+				}
+				past := r.TakenAt.After(at)
+				if descending {
+					past = r.TakenAt.Before(at)
+				}
+				if len(bound) == 3 && r.TakenAt.Equal(at) {
+					past = r.ID > bound[2].(int64)
+					if descending {
+						past = r.ID < bound[2].(int64)
+					}
+				}
+				if !past {
+					continue
+				}
+			}
+			if len(*dest) < limit {
+				*dest = append(*dest, r)
+			}
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db.Model(&reading{})
+}
+
+// microsecondReadings are rows a microsecond apart, some sharing a timestamp, recorded in a
+// non-UTC zone, plus one without a timestamp
+func microsecondReadings() []reading {
+	zone := time.FixedZone("UTC+2", 2*60*60)
+	base := time.Date(2024, 3, 1, 11, 30, 0, 123456000, zone)
+	at := func(micros int) *time.Time {
+		t := base.Add(time.Duration(micros) * time.Microsecond)
+		return &t
+	}
+	return []reading{
+		{ID: 1, TakenAt: at(0)},
+		{ID: 2, TakenAt: at(1)},
+		{ID: 3, TakenAt: at(1)},
+		{ID: 4, TakenAt: at(2)},
+		{ID: 5, TakenAt: nil},
+		{ID: 6, TakenAt: at(3)},
+	}
+}
+
+func TestCursorPaginateTimeOrdersMicroseconds(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		ascending bool
+		want      []int64
+	}{
+		{name: "ascending", ascending: true, want: []int64{1, 2, 3, 4, 6}},
+		{name: "descending", ascending: false, want: []int64{6, 4, 3, 2, 1}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			db := readingsDB(t, microsecondReadings())
+
+			var seen []int64
+			var page []reading
+			cursor := ""
+			for pages := 0; pages < 5; pages++ {
+				result, err := CursorPaginateTime(db, &page, cursor, 2, "taken_at", tc.ascending)
+				if err != nil {
+					t.Fatalf("page %d: %v", pages+1, err)
+				}
+				for _, r := range result.Items {
+					seen = append(seen, r.ID)
+				}
+				if result.NextCursor == nil {
+					break
+				}
+				cursor = *result.NextCursor
+			}
+			if !reflect.DeepEqual(seen, tc.want) {
+				t.Errorf("paged through %v, want %v with the NULL row left out", seen, tc.want)
+			}
+		})
+	}
+}
+
+func TestCursorPaginateTimeEncodesUTCNanoseconds(t *testing.T) {
+	db := readingsDB(t, microsecondReadings())
+
+	var page []reading
+	result, err := CursorPaginateTime(db, &page, "", 2, "taken_at", true, WithoutTieBreaker())
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := DecodeCursor(*result.EndCursor, "taken_at", true)
+	if err != nil || value != "2024-03-01T09:30:00.123457Z" {
+		t.Errorf("end cursor decodes to %q (%v), want the UTC timestamp with its microseconds", value, err)
+	}
+	if last, ok := result.LastKey.(time.Time); !ok || last.Location() != time.UTC {
+		t.Errorf("last key = %v, want a UTC time", result.LastKey)
+	}
+
+	if _, err := CursorPaginateTime(db, &page, EncodeCursor("taken_at", "yesterday", true), 2, "taken_at", true); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("unparseable cursor returned %v, want ErrInvalidCursor", err)
+	}
+}
@@ -0,0 +1,198 @@
+//go:build paginationws
+
+package pagination
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"gorm.io/gorm"
+)
+
+// Page request rate defaults of a WebSocket connection
+const (
+	DefaultPageRequestsPerSecond = 10
+	DefaultPageRequestBurst      = 10
+)
+
+// Frame types of the WebSocket page protocol
+const (
+	FramePage  = "page"
+	FrameError = "error"
+)
+
+// ErrPageRequestRate is the error frame of a connection asking for pages faster than its
+// rate limit; the connection stays open
+var ErrPageRequestRate = errors.New("too many page requests on this connection")
+
+// PageRequest asks for one page over a WebSocket connection
+// It is read with the same aliases, defaults, and page size limit as ParamsFromBody (limit for
+// page_size, page_token, max_bytes); ID is echoed on the reply so clients can pipeline requests.
+type PageRequest struct {
+	ID       string `json:"id,omitempty"`
+	Cursor   string `json:"cursor,omitempty"`
+	Page     int    `json:"page,omitempty"`
+	PageSize int    `json:"page_size,omitempty"`
+}
+
+// PageFrame is a reply on a WebSocket connection: the standard response envelope under "page",
+// or the structured error body (ErrorBody) under "error"
+// Status is what the same request over HTTP would answer; a 204 page frame has no page.
+type PageFrame[T any] struct {
+	Type   string                `json:"type"`
+	ID     string                `json:"id,omitempty"`
+	Status int                   `json:"status"`
+	Page   *PaginatedResponse[T] `json:"page,omitempty"`
+	Error  json.RawMessage       `json:"error,omitempty"`
+}
+
+// WebSocketOptions configures ServeWebSocketPages
+type WebSocketOptions struct {
+	// HandleOptions choose the query, paginator, and status policy as for HandlePaginated
+	HandleOptions
+
+	// RequestsPerSecond and Burst rate limit each connection's page requests (defaults
+	// DefaultPageRequestsPerSecond and DefaultPageRequestBurst); requests over the limit get
+	// an ErrPageRequestRate error frame
+	RequestsPerSecond float64
+	Burst             int
+
+	// Upgrader upgrades the request (nil = a default Upgrader, which only accepts same-origin
+	// browsers)
+	Upgrader *websocket.Upgrader
+}
+
+// withDefaults fills unset fields
+func (opts WebSocketOptions) withDefaults() WebSocketOptions {
+	if opts.RequestsPerSecond <= 0 {
+		opts.RequestsPerSecond = DefaultPageRequestsPerSecond
+	}
+	if opts.Burst < 1 {
+		opts.Burst = DefaultPageRequestBurst
+	}
+	if opts.Upgrader == nil {
+		opts.Upgrader = &websocket.Upgrader{}
+	}
+	return opts
+}
+
+// ServeWebSocketPages upgrades the request and serves successive pages of T over the connection,
+// one reply frame per request frame, until the client closes it
+// Each request is validated and clamped like an HTTP request (including MaxPageSizeFor of the
+// upgrade request's context) and paginated like HandlePaginated with the request's scope; bad
+// params, failed queries, and statuses of 400 and above become error frames, and the connection
+// stays open. Responses carry no links, since the next page is asked for on the same connection.
+// It returns nil when the client disconnects; a failed upgrade has already been answered.
+//
+// Example usage:
+//
+//	r.GET("/orders/ws", func(c *gin.Context) {
+//	    _ = pagination.ServeWebSocketPages[Order](c, db.Model(&Order{}), pagination.WebSocketOptions{
+//	        HandleOptions: pagination.HandleOptions{CursorField: "id"},
+//	    })
+//	})
+//
+//	// Client: {"id": "1", "page_size": 50}, then {"id": "2", "cursor": "<next_cursor>"}, ...
+func ServeWebSocketPages[T any](c *gin.Context, db *gorm.DB, opts WebSocketOptions) error {
+	opts = opts.withDefaults()
+	conn, err := opts.Upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return fmt.Errorf("failed to upgrade page connection: %w", err)
+	}
+	defer conn.Close()
+	conn.SetReadLimit(MaxParamsBodyBytes)
+
+	limiter := newRequestRate(opts.RequestsPerSecond, opts.Burst)
+	maxPageSize := MaxPageSizeFor(c.Request.Context())
+	for {
+		_, raw, err := conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				return fmt.Errorf("failed to read page request: %w", err)
+			}
+			return nil
+		}
+
+		frame := servePageFrame[T](c, db, raw, maxPageSize, limiter, opts)
+		if err := conn.WriteJSON(frame); err != nil {
+			return fmt.Errorf("failed to write page frame: %w", err)
+		}
+	}
+}
+
+// servePageFrame answers one raw page request
+func servePageFrame[T any](c *gin.Context, db *gorm.DB, raw []byte, maxPageSize int, limiter *requestRate, opts WebSocketOptions) PageFrame[T] {
+	// Only the ID is read here; paramsFromBody reports a malformed request
+	var request PageRequest
+	_ = json.Unmarshal(raw, &request)
+
+	if !limiter.allow() {
+		return errorFrame[T](request.ID, http.StatusTooManyRequests, ErrPageRequestRate)
+	}
+	params, err := paramsFromBody(raw, maxPageSize)
+	if err != nil {
+		return errorFrame[T](request.ID, http.StatusBadRequest, err)
+	}
+
+	reply, err := servePage[T](c, db, params, opts.HandleOptions, "")
+	switch {
+	case err != nil:
+		return errorFrame[T](request.ID, ErrorStatus(err), err)
+	case reply.status >= http.StatusBadRequest && reply.outcomeErr != nil:
+		return errorFrame[T](request.ID, reply.status, reply.outcomeErr)
+	}
+
+	frame := PageFrame[T]{Type: FramePage, ID: request.ID, Status: reply.status}
+	if reply.status != http.StatusNoContent {
+		page := reply.response.(PaginatedResponse[T])
+		frame.Page = &page
+	}
+	return frame
+}
+
+// errorFrame is the error frame of err with status
+func errorFrame[T any](id string, status int, err error) PageFrame[T] {
+	body, marshalErr := json.Marshal(ErrorBody(status, err))
+	if marshalErr != nil {
+		body, _ = json.Marshal(gin.H{"error": err.Error()})
+	}
+	return PageFrame[T]{Type: FrameError, ID: id, Status: status, Error: body}
+}
+
+// requestRate is a token bucket of one connection's page requests; requests on a connection
+// are served one at a time, so it needs no lock
+type requestRate struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+// newRequestRate allows rate requests per second, and bursts of burst
+func newRequestRate(rate float64, burst int) *requestRate {
+	return &requestRate{rate: rate, burst: float64(burst), tokens: float64(burst), now: time.Now}
+}
+
+// allow takes a token if one is available
+func (r *requestRate) allow() bool {
+	now := r.now()
+	if !r.last.IsZero() {
+		r.tokens += now.Sub(r.last).Seconds() * r.rate
+		if r.tokens > r.burst {
+			r.tokens = r.burst
+		}
+	}
+	r.last = now
+
+	if r.tokens < 1 {
+		return false
+	}
+	r.tokens--
+	return true
+}
@@ -0,0 +1,127 @@
+//go:build paginationws
+
+package pagination
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// ClientFrameError is an error frame received by a PageClient
+type ClientFrameError struct {
+	// Status is the HTTP status the same request would have answered
+	Status int
+
+	// Body is the structured error body (ErrorBody on the server)
+	Body json.RawMessage
+}
+
+func (e *ClientFrameError) Error() string {
+	return fmt.Sprintf("page request failed with status %d: %s", e.Status, e.Body)
+}
+
+// PageClient requests successive pages of T over one WebSocket connection to
+// ServeWebSocketPages
+// Requests are sent one at a time; a PageClient is not safe for concurrent use.
+//
+// Example usage:
+//
+//	client, err := pagination.DialPages[Order](ctx, "wss://api.example.com/orders/ws", nil)
+//	if err != nil {
+//	    return err
+//	}
+//	defer client.Close()
+//
+//	request := pagination.PageRequest{PageSize: 100}
+//	for {
+//	    page, err := client.Request(ctx, request)
+//	    if err != nil {
+//	        return err // *pagination.ClientFrameError for an error frame
+//	    }
+//	    render(page.Data)
+//
+//	    next, ok := pagination.NextPageRequest(page)
+//	    if !ok {
+//	        return nil
+//	    }
+//	    request = next
+//	}
+type PageClient[T any] struct {
+	conn *websocket.Conn
+	sent int
+}
+
+// DialPages connects to a ServeWebSocketPages endpoint at url (ws:// or wss://) with header
+func DialPages[T any](ctx context.Context, url string, header http.Header) (*PageClient[T], error) {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, url, header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial page connection: %w", err)
+	}
+	return &PageClient[T]{conn: conn}, nil
+}
+
+// Request sends request and waits for its page
+// An empty request.ID is filled in with a sequence number. A 204 page frame returns a nil page;
+// an error frame returns a *ClientFrameError, and the connection stays usable.
+func (c *PageClient[T]) Request(ctx context.Context, request PageRequest) (*PaginatedResponse[T], error) {
+	c.sent++
+	if request.ID == "" {
+		request.ID = strconv.Itoa(c.sent)
+	}
+
+	deadline, _ := ctx.Deadline()
+	if err := c.conn.SetWriteDeadline(deadline); err != nil {
+		return nil, err
+	}
+	if err := c.conn.WriteJSON(request); err != nil {
+		return nil, fmt.Errorf("failed to send page request: %w", err)
+	}
+
+	if err := c.conn.SetReadDeadline(deadline); err != nil {
+		return nil, err
+	}
+	var frame PageFrame[T]
+	if err := c.conn.ReadJSON(&frame); err != nil {
+		return nil, fmt.Errorf("failed to read page frame: %w", err)
+	}
+	if frame.ID != request.ID {
+		return nil, fmt.Errorf("page frame %q answers another request than %q", frame.ID, request.ID)
+	}
+	if frame.Type == FrameError {
+		return nil, &ClientFrameError{Status: frame.Status, Body: frame.Error}
+	}
+	return frame.Page, nil
+}
+
+// Close closes the connection, telling the server it is done
+func (c *PageClient[T]) Close() error {
+	message := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+	_ = c.conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(time.Second))
+	return c.conn.Close()
+}
+
+// NextPageRequest is the request for the page after page: its next cursor, or the next page
+// number of an offset page (whose deep-pagination cursor is meant for a cursor endpoint)
+// It reports false on the last page.
+func NextPageRequest[T any](page *PaginatedResponse[T]) (PageRequest, bool) {
+	if page == nil || !page.Pagination.HasNext {
+		return PageRequest{}, false
+	}
+
+	request := PageRequest{PageSize: page.Pagination.PageSize}
+	switch {
+	case page.Pagination.NextCursor != nil && !page.Pagination.DeepPagination:
+		request.Cursor = *page.Pagination.NextCursor
+	case page.Pagination.CurrentPage != nil:
+		request.Page = *page.Pagination.CurrentPage + 1
+	default:
+		return PageRequest{}, false
+	}
+	return request, true
+}
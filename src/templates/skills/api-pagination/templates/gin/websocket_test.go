@@ -0,0 +1,150 @@
+//go:build paginationws
+
+package pagination
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// offsetDB serves rows by the offset query's LIMIT and OFFSET, and counts them
+func offsetDB(t *testing.T, rows []event) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(nil, &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Callback().Query().Register("test:offset", func(tx *gorm.DB) {
+		switch dest := tx.Statement.Dest.(type) {
+		case *[]event:
+			limit := tx.Statement.Clauses["LIMIT"].Expression.(clause.Limit)
+			for i := limit.Offset; i < len(rows) && len(*dest) < *limit.Limit; i++ {
+				*dest = append(*dest, rows[i])
+			}
+		case *int64:
+			*dest = int64(len(rows))
+			tx.RowsAffected = 1
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db.Model(&event{})
+}
+
+// dialTestPages serves ServeWebSocketPages for db and connects a PageClient to it
+func dialTestPages(t *testing.T, db *gorm.DB, opts WebSocketOptions) *PageClient[event] {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/events/ws", func(c *gin.Context) {
+		_ = ServeWebSocketPages[event](c, db, opts)
+	})
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	client, err := DialPages[event](ctx, "ws"+strings.TrimPrefix(server.URL, "http")+"/events/ws", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestWebSocketWalksPages(t *testing.T) {
+	client := dialTestPages(t, offsetDB(t, eventsWithIDs(1, 2, 3, 4, 5)), WebSocketOptions{})
+	ctx := context.Background()
+
+	var pages [][]int64
+	request := PageRequest{PageSize: 2}
+	for len(pages) < 10 {
+		page, err := client.Request(ctx, request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []int64
+		for _, e := range page.Data {
+			ids = append(ids, e.ID)
+		}
+		pages = append(pages, ids)
+
+		next, ok := NextPageRequest(page)
+		if !ok {
+			break
+		}
+		request = next
+	}
+
+	want := [][]int64{
+		{1, 2},
+		{3, 4},
+		{5},
+	}
+	if !reflect.DeepEqual(pages, want) {
+		t.Errorf("pages = %v, want %v", pages, want)
+	}
+}
+
+func TestWebSocketInvalidCursorErrorFrame(t *testing.T) {
+	rows := eventsWithIDs(1, 2, 3)
+	client := dialTestPages(t, liveDB(t, &rows, nil), WebSocketOptions{
+		HandleOptions: HandleOptions{CursorField: "id"},
+	})
+	ctx := context.Background()
+
+	_, err := client.Request(ctx, PageRequest{Cursor: "!!!", PageSize: 2})
+	var frameErr *ClientFrameError
+	if !errors.As(err, &frameErr) || frameErr.Status != 400 {
+		t.Fatalf("invalid cursor: %v, want a 400 error frame", err)
+	}
+	var body map[string]string
+	if err := json.Unmarshal(frameErr.Body, &body); err != nil || !strings.Contains(body["error"], "cursor") {
+		t.Errorf("error body %s, want the structured error body", frameErr.Body)
+	}
+
+	// The connection survives the error
+	page, err := client.Request(ctx, PageRequest{PageSize: 2})
+	if err != nil || len(page.Data) != 2 {
+		t.Errorf("request after the error frame: %v, %v", page, err)
+	}
+}
+
+func TestWebSocketRateLimitsRequests(t *testing.T) {
+	client := dialTestPages(t, offsetDB(t, eventsWithIDs(1, 2)), WebSocketOptions{RequestsPerSecond: 0.001, Burst: 1})
+	ctx := context.Background()
+
+	if _, err := client.Request(ctx, PageRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := client.Request(ctx, PageRequest{})
+	var frameErr *ClientFrameError
+	if !errors.As(err, &frameErr) || frameErr.Status != 429 {
+		t.Errorf("second request: %v, want a 429 error frame", err)
+	}
+}
+
+func TestRequestRateRefills(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	rate := newRequestRate(2, 1)
+	rate.now = func() time.Time { return now }
+
+	if !rate.allow() || rate.allow() {
+		t.Fatal("burst of 1: want one request, then none")
+	}
+	now = now.Add(500 * time.Millisecond)
+	if !rate.allow() {
+		t.Error("after half a second at 2/s: want a request")
+	}
+}
@@ -0,0 +1,84 @@
+package ratelimit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+)
+
+// KeyFunc identifies the caller of a request ("" = not identifiable by this key)
+type KeyFunc func(c *gin.Context) string
+
+// KeyByIP identifies callers by client IP
+// Configure gin's trusted proxies (engine.SetTrustedProxies) so ClientIP cannot be spoofed
+// with X-Forwarded-For.
+func KeyByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// KeyByAPIKey identifies callers by the API key in header (e.g. "X-API-Key")
+// Keys are hashed so raw credentials never reach the store.
+func KeyByAPIKey(header string) KeyFunc {
+	return func(c *gin.Context) string {
+		key := c.GetHeader(header)
+		if key == "" {
+			return ""
+		}
+		sum := sha256.Sum256([]byte(key))
+		return "key:" + hex.EncodeToString(sum[:12])
+	}
+}
+
+// KeyByClaim identifies callers by a claim (e.g. "sub") of the auth claims that an
+// authentication middleware stored with c.Set(contextKey, claims)
+// Claims may be any map with string keys (such as jwt.MapClaims), or a plain string user id.
+//
+// Example usage:
+//
+//	policy := ratelimit.Policy{Limit: 300, Key: ratelimit.FirstKey(
+//	    ratelimit.KeyByClaim("claims", "sub"), // signed-in users get their own budget
+//	    ratelimit.KeyByIP,                      // anonymous callers share their IP's budget
+//	)}
+func KeyByClaim(contextKey, claim string) KeyFunc {
+	return func(c *gin.Context) string {
+		value, exists := c.Get(contextKey)
+		if !exists || value == nil {
+			return ""
+		}
+
+		if id, ok := value.(string); ok {
+			if id == "" {
+				return ""
+			}
+			return "user:" + id
+		}
+
+		claims := reflect.ValueOf(value)
+		if claims.Kind() != reflect.Map || claims.Type().Key().Kind() != reflect.String {
+			return ""
+		}
+		id := claims.MapIndex(reflect.ValueOf(claim).Convert(claims.Type().Key()))
+		if !id.IsValid() || id.IsZero() {
+			return ""
+		}
+		if user := fmt.Sprint(id.Interface()); user != "" {
+			return "user:" + user
+		}
+		return ""
+	}
+}
+
+// FirstKey uses the first of keys that identifies the caller, falling back to KeyByIP
+func FirstKey(keys ...KeyFunc) KeyFunc {
+	return func(c *gin.Context) string {
+		for _, key := range keys {
+			if id := key(c); id != "" {
+				return id
+			}
+		}
+		return KeyByIP(c)
+	}
+}
@@ -0,0 +1,121 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrStoreUnavailable is returned by Limiter.Allow under FailClosed when the store fails
+var ErrStoreUnavailable = errors.New("rate limit store unavailable")
+
+// Clock tells the limiter the time (replace it in tests)
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock is the real clock
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// FailureMode decides requests when the store fails (e.g. Redis is down)
+type FailureMode int
+
+const (
+	// FailOpen allows every request while the store is failing
+	FailOpen FailureMode = iota
+
+	// FailClosed rejects every request with 503 while the store is failing
+	FailClosed
+
+	// FailLocal enforces policies per instance with an in-memory store while the store is failing
+	FailLocal
+)
+
+// Limiter checks requests against policies
+type Limiter struct {
+	store    Store
+	clock    Clock
+	mode     FailureMode
+	fallback Store
+	onError  func(err error)
+}
+
+// LimiterOption configures a Limiter
+type LimiterOption func(*Limiter)
+
+// WithClock replaces the system clock
+func WithClock(clock Clock) LimiterOption {
+	return func(l *Limiter) {
+		l.clock = clock
+	}
+}
+
+// WithFailureMode decides requests when the store fails (default FailOpen)
+func WithFailureMode(mode FailureMode) LimiterOption {
+	return func(l *Limiter) {
+		l.mode = mode
+	}
+}
+
+// WithFallbackStore replaces the in-memory store FailLocal falls back to
+func WithFallbackStore(store Store) LimiterOption {
+	return func(l *Limiter) {
+		l.fallback = store
+	}
+}
+
+// WithErrorHandler is called with every store error (e.g. to log or count them)
+func WithErrorHandler(fn func(err error)) LimiterOption {
+	return func(l *Limiter) {
+		l.onError = fn
+	}
+}
+
+// NewLimiter creates a Limiter keeping state in store
+//
+// Example usage:
+//
+//	limiter := ratelimit.NewLimiter(ratelimit.NewRedisStore(client, "rl:"),
+//	    ratelimit.WithFailureMode(ratelimit.FailLocal),
+//	    ratelimit.WithErrorHandler(func(err error) { log.Printf("rate limit: %v", err) }),
+//	)
+func NewLimiter(store Store, opts ...LimiterOption) *Limiter {
+	l := &Limiter{store: store, clock: systemClock{}}
+	for _, opt := range opts {
+		opt(l)
+	}
+	if l.mode == FailLocal && l.fallback == nil {
+		l.fallback = NewMemoryStore(0)
+	}
+	return l
+}
+
+// Allow checks one request by key against p
+func (l *Limiter) Allow(ctx context.Context, key string, p Policy) (Decision, error) {
+	now := l.clock.Now()
+
+	decision, err := l.store.Allow(ctx, key, p, now)
+	if err == nil {
+		return decision, nil
+	}
+	if l.onError != nil {
+		l.onError(err)
+	}
+
+	switch l.mode {
+	case FailClosed:
+		return Decision{}, fmt.Errorf("%w: %v", ErrStoreUnavailable, err)
+	case FailLocal:
+		decision, err = l.fallback.Allow(ctx, key, p, now)
+		if err != nil {
+			return Decision{}, fmt.Errorf("%w: %v", ErrStoreUnavailable, err)
+		}
+	default:
+		decision = Decision{Allowed: true, Limit: int(p.capacity()), Remaining: int(p.capacity()), Reset: now}
+	}
+	decision.Degraded = true
+	return decision, nil
+}
@@ -0,0 +1,194 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+)
+
+// Algorithm selects how a Policy counts requests
+type Algorithm string
+
+const (
+	// TokenBucket allows bursts of up to Burst requests, refilling Limit tokens per Window
+	TokenBucket Algorithm = "token_bucket"
+
+	// SlidingWindow allows Limit requests in any rolling Window (sliding window counter:
+	// the previous window's count is weighted by how much of it still overlaps)
+	SlidingWindow Algorithm = "sliding_window"
+)
+
+// Policy is a rate limit applied to a route or group of routes
+type Policy struct {
+	// Name identifies the budget; routes with the same Name share it (default: the route)
+	Name string
+
+	// Algorithm defaults to TokenBucket
+	Algorithm Algorithm
+
+	// Limit is the number of requests allowed per Window
+	Limit int
+
+	// Window is the period Limit applies to (default 1 minute)
+	Window time.Duration
+
+	// Burst is the token bucket capacity (default Limit); ignored by SlidingWindow
+	Burst int
+
+	// Key identifies the caller (default KeyByIP)
+	Key KeyFunc
+}
+
+// DefaultPolicy is the suggested default for APIs without tuned limits
+var DefaultPolicy = Policy{Limit: {{defaultLimit}}, Window: {{defaultWindow}} * time.Second}
+
+// Decision is the outcome of one rate limit check
+type Decision struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Time     // when the caller's budget is fully restored
+	RetryAfter time.Duration // when a rejected request may be retried (0 if allowed)
+
+	// Degraded is true when the store failed and the fallback mode decided instead
+	Degraded bool
+}
+
+func (p Policy) window() time.Duration {
+	if p.Window <= 0 {
+		return time.Minute
+	}
+	return p.Window
+}
+
+// capacity is the most requests a caller can make at once
+func (p Policy) capacity() float64 {
+	if p.Burst > 0 && p.Algorithm != SlidingWindow {
+		return float64(p.Burst)
+	}
+	return float64(p.Limit)
+}
+
+// refillRate returns tokens added per millisecond
+func (p Policy) refillRate() float64 {
+	return float64(p.Limit) / float64(p.window().Milliseconds())
+}
+
+// bucketState is a token bucket at a point in time
+type bucketState struct {
+	Tokens float64
+	At     int64 // unix milliseconds of the last update
+}
+
+// takeToken refills the bucket up to nowMs and spends one token if available
+// A clock behind the last update (skew between instances, or a clock step back) refills
+// nothing instead of going negative, so skew can never mint tokens.
+func takeToken(p Policy, state *bucketState, nowMs int64) (allowed bool) {
+	capacity := p.capacity()
+	if state.At == 0 {
+		state.Tokens, state.At = capacity, nowMs
+	}
+	if nowMs > state.At {
+		state.Tokens = math.Min(capacity, state.Tokens+float64(nowMs-state.At)*p.refillRate())
+		state.At = nowMs
+	}
+
+	if state.Tokens >= 1 {
+		state.Tokens--
+		return true
+	}
+	return false
+}
+
+// bucketDecision describes a token bucket after takeToken
+func bucketDecision(p Policy, state bucketState, allowed bool) Decision {
+	rate := p.refillRate()
+	at := time.UnixMilli(state.At)
+
+	d := Decision{
+		Allowed:   allowed,
+		Limit:     int(p.capacity()),
+		Remaining: int(math.Floor(state.Tokens)),
+		Reset:     at.Add(millis((p.capacity() - state.Tokens) / rate)),
+	}
+	if !allowed {
+		d.RetryAfter = millis((1 - state.Tokens) / rate)
+	}
+	return d
+}
+
+// windowState is a sliding window counter at a point in time
+type windowState struct {
+	Index    int64 // window number (unix milliseconds / window length)
+	Current  int
+	Previous int
+}
+
+// countRequest advances the counter to nowMs and counts the request if it fits
+// A clock behind the current window (skew) is treated as still being in it.
+func countRequest(p Policy, state *windowState, nowMs int64) (allowed bool, elapsedMs int64) {
+	length := p.window().Milliseconds()
+	index := nowMs / length
+	if index < state.Index {
+		index, nowMs = state.Index, state.Index*length
+	}
+
+	switch {
+	case index == state.Index+1:
+		state.Previous, state.Current = state.Current, 0
+	case index > state.Index+1:
+		state.Previous, state.Current = 0, 0
+	}
+	state.Index = index
+
+	elapsedMs = nowMs - index*length
+	if estimate(p, *state, elapsedMs)+1 <= float64(p.Limit) {
+		state.Current++
+		return true, elapsedMs
+	}
+	return false, elapsedMs
+}
+
+// estimate is the weighted request count of the rolling window
+func estimate(p Policy, state windowState, elapsedMs int64) float64 {
+	length := float64(p.window().Milliseconds())
+	weight := (length - float64(elapsedMs)) / length
+	return float64(state.Previous)*weight + float64(state.Current)
+}
+
+// windowDecision describes a sliding window counter after countRequest
+func windowDecision(p Policy, state windowState, elapsedMs int64, allowed bool) Decision {
+	length := p.window().Milliseconds()
+	limit := float64(p.Limit)
+	start := time.UnixMilli(state.Index * length)
+
+	d := Decision{
+		Allowed:   allowed,
+		Limit:     p.Limit,
+		Remaining: int(math.Max(0, math.Floor(limit-estimate(p, state, elapsedMs)))),
+		Reset:     start.Add(2 * p.window()),
+	}
+	if state.Current == 0 {
+		d.Reset = start.Add(p.window())
+	}
+	if allowed {
+		return d
+	}
+
+	// Wait until the previous window's weight has decayed enough for one more request
+	var wait float64
+	if state.Current+1 > p.Limit {
+		// The current window alone is full: it becomes the weighted previous window
+		weight := (limit - 1) / float64(state.Current)
+		wait = float64(length-elapsedMs) + float64(length)*(1-weight)
+	} else {
+		weight := (limit - float64(state.Current) - 1) / float64(state.Previous)
+		wait = float64(length)*(1-weight) - float64(elapsedMs)
+	}
+	d.RetryAfter = millis(math.Max(wait, 1))
+	return d
+}
+
+// millis converts fractional milliseconds to a duration, rounding up
+func millis(ms float64) time.Duration {
+	return time.Duration(math.Ceil(ms * float64(time.Millisecond)))
+}
@@ -0,0 +1,222 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock tests move by hand
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time          { return c.now }
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+// failingStore stands in for an unreachable Redis
+type failingStore struct{ calls int }
+
+func (s *failingStore) Allow(ctx context.Context, key string, p Policy, now time.Time) (Decision, error) {
+	s.calls++
+	return Decision{}, errors.New("dial tcp 127.0.0.1:6379: connect: connection refused")
+}
+
+// allowN sends n requests and counts the allowed ones
+func allowN(t *testing.T, l *Limiter, p Policy, n int) int {
+	t.Helper()
+	allowed := 0
+	for i := 0; i < n; i++ {
+		d, err := l.Allow(context.Background(), "caller", p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.Allowed {
+			allowed++
+		}
+	}
+	return allowed
+}
+
+func TestTokenBucketBurst(t *testing.T) {
+	clock := newFakeClock()
+	limiter := NewLimiter(NewMemoryStore(0), WithClock(clock))
+	policy := Policy{Limit: 60, Window: time.Minute, Burst: 10}
+
+	if got := allowN(t, limiter, policy, 15); got != 10 {
+		t.Fatalf("burst allowed %d requests, want 10", got)
+	}
+
+	d, _ := limiter.Allow(context.Background(), "caller", policy)
+	if d.Allowed || d.Remaining != 0 {
+		t.Fatalf("expected rejection with no remaining tokens: %+v", d)
+	}
+	if d.RetryAfter <= 0 || d.RetryAfter > time.Second {
+		t.Errorf("RetryAfter = %v, want at most the 1s refill of one token", d.RetryAfter)
+	}
+
+	// 60 per minute refills one token per second
+	clock.Advance(3 * time.Second)
+	if got := allowN(t, limiter, policy, 5); got != 3 {
+		t.Errorf("after 3s allowed %d requests, want 3", got)
+	}
+
+	// A long pause refills only up to the burst
+	clock.Advance(time.Hour)
+	if got := allowN(t, limiter, policy, 15); got != 10 {
+		t.Errorf("after an hour allowed %d requests, want the burst of 10", got)
+	}
+}
+
+func TestSlidingWindowAcrossBoundary(t *testing.T) {
+	clock := newFakeClock()
+	limiter := NewLimiter(NewMemoryStore(0), WithClock(clock))
+	policy := Policy{Algorithm: SlidingWindow, Limit: 10, Window: time.Minute}
+
+	// Fill the window just before its end, then cross the boundary
+	clock.Advance(50 * time.Second)
+	if got := allowN(t, limiter, policy, 12); got != 10 {
+		t.Fatalf("allowed %d requests, want 10", got)
+	}
+
+	// 15s into the next window, 75% of the previous window still counts: 7.5 of 10
+	clock.Advance(25 * time.Second)
+	if got := allowN(t, limiter, policy, 5); got != 2 {
+		t.Errorf("after the boundary allowed %d requests, want 2 (no double burst)", got)
+	}
+
+	d, _ := limiter.Allow(context.Background(), "caller", policy)
+	if d.Allowed || d.RetryAfter <= 0 {
+		t.Errorf("expected a rejection with RetryAfter: %+v", d)
+	}
+}
+
+func TestClockSkewCannotMintTokens(t *testing.T) {
+	clock := newFakeClock()
+	store := NewMemoryStore(0)
+	// Two instances share the store; the second one's clock runs 30s behind
+	ahead := NewLimiter(store, WithClock(clock))
+	behindClock := &fakeClock{now: clock.now.Add(-30 * time.Second)}
+	behind := NewLimiter(store, WithClock(behindClock))
+
+	policy := Policy{Limit: 60, Window: time.Minute, Burst: 5}
+	if got := allowN(t, ahead, policy, 5); got != 5 {
+		t.Fatalf("allowed %d requests, want 5", got)
+	}
+
+	// The lagging instance must not see a refill, nor push state backwards
+	if got := allowN(t, behind, policy, 5); got != 0 {
+		t.Errorf("lagging clock allowed %d requests, want 0", got)
+	}
+	behindClock.Advance(10 * time.Second) // still 20s behind the last update
+	if got := allowN(t, behind, policy, 5); got != 0 {
+		t.Errorf("lagging clock allowed %d requests after advancing, want 0", got)
+	}
+
+	// The clock stepping back (NTP correction) is no different
+	clock.Advance(-time.Minute)
+	if got := allowN(t, ahead, policy, 1); got != 0 {
+		t.Errorf("clock step back allowed %d requests, want 0", got)
+	}
+
+	// Real time passing refills from the latest update
+	clock.Advance(time.Minute + 2*time.Second)
+	if got := allowN(t, ahead, policy, 5); got != 2 {
+		t.Errorf("after 2s allowed %d requests, want 2", got)
+	}
+}
+
+func TestSlidingWindowClockSkew(t *testing.T) {
+	clock := newFakeClock()
+	limiter := NewLimiter(NewMemoryStore(0), WithClock(clock))
+	policy := Policy{Algorithm: SlidingWindow, Limit: 3, Window: time.Minute}
+
+	if got := allowN(t, limiter, policy, 3); got != 3 {
+		t.Fatalf("allowed %d requests, want 3", got)
+	}
+
+	// Stepping into an earlier window must not reopen it with fresh counters
+	clock.Advance(-2 * time.Minute)
+	if got := allowN(t, limiter, policy, 3); got != 0 {
+		t.Errorf("skewed clock allowed %d requests, want 0", got)
+	}
+}
+
+func TestStoreFailureModes(t *testing.T) {
+	policy := Policy{Limit: 60, Window: time.Minute, Burst: 2}
+
+	t.Run("fail open", func(t *testing.T) {
+		var reported []error
+		limiter := NewLimiter(&failingStore{}, WithErrorHandler(func(err error) {
+			reported = append(reported, err)
+		}))
+
+		d, err := limiter.Allow(context.Background(), "caller", policy)
+		if err != nil || !d.Allowed || !d.Degraded {
+			t.Fatalf("want an allowed, degraded decision: %+v, %v", d, err)
+		}
+		if len(reported) != 1 {
+			t.Errorf("store errors reported %d times, want 1", len(reported))
+		}
+	})
+
+	t.Run("fail closed", func(t *testing.T) {
+		limiter := NewLimiter(&failingStore{}, WithFailureMode(FailClosed))
+
+		_, err := limiter.Allow(context.Background(), "caller", policy)
+		if !errors.Is(err, ErrStoreUnavailable) {
+			t.Fatalf("err = %v, want ErrStoreUnavailable", err)
+		}
+	})
+
+	t.Run("fail local", func(t *testing.T) {
+		store := &failingStore{}
+		limiter := NewLimiter(store, WithFailureMode(FailLocal), WithClock(newFakeClock()))
+
+		if got := allowN(t, limiter, policy, 5); got != 2 {
+			t.Errorf("local fallback allowed %d requests, want the burst of 2", got)
+		}
+		if store.calls != 5 {
+			t.Errorf("primary store tried %d times, want every request (5)", store.calls)
+		}
+	})
+}
+
+func TestRegistryLookup(t *testing.T) {
+	registry := NewRegistry()
+	if err := registry.Default(Policy{Limit: 100}); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.Set("GET", "/products", Policy{Limit: 10}); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.Set("", "/search", Policy{Limit: 5, Name: "search"}); err != nil {
+		t.Fatal(err)
+	}
+	registry.Exempt("GET", "/healthz")
+
+	cases := []struct {
+		method, route string
+		limit         int
+		name          string
+		ok            bool
+	}{
+		{"GET", "/products", 10, "GET /products", true},
+		{"POST", "/products", 100, "POST /products", true},
+		{"POST", "/search", 5, "search", true},
+		{"GET", "/healthz", 0, "", false},
+	}
+	for _, tc := range cases {
+		p, ok := registry.Lookup(tc.method, tc.route)
+		if ok != tc.ok || p.Limit != tc.limit || p.Name != tc.name {
+			t.Errorf("%s %s: got %+v (%v)", tc.method, tc.route, p, ok)
+		}
+	}
+
+	if err := registry.Set("GET", "/broken", Policy{}); err == nil {
+		t.Error("expected a policy without a limit to be rejected")
+	}
+}
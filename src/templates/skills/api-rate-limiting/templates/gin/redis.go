@@ -0,0 +1,157 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// tokenBucketScript mirrors takeToken atomically in Redis
+// KEYS[1] = bucket hash; ARGV = capacity, refill rate per ms, now (ms), ttl (ms)
+var tokenBucketScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'at')
+local tokens = tonumber(state[1]) or capacity
+local at = tonumber(state[2]) or now
+
+if now > at then
+  tokens = math.min(capacity, tokens + (now - at) * rate)
+  at = now
+end
+
+local allowed = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'at', at)
+redis.call('PEXPIRE', KEYS[1], ARGV[4])
+return {allowed, tostring(tokens), at}
+`)
+
+// slidingWindowScript mirrors countRequest atomically in Redis
+// KEYS[1] = counter hash; ARGV = limit, window (ms), now (ms)
+var slidingWindowScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local length = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local index = math.floor(now / length)
+
+local state = redis.call('HMGET', KEYS[1], 'index', 'current', 'previous')
+local stored = tonumber(state[1]) or index
+local current = tonumber(state[2]) or 0
+local previous = tonumber(state[3]) or 0
+
+if index < stored then
+  index = stored
+  now = stored * length
+end
+if index == stored + 1 then
+  previous = current
+  current = 0
+elseif index > stored + 1 then
+  previous = 0
+  current = 0
+end
+
+local elapsed = now - index * length
+local allowed = 0
+if previous * (length - elapsed) / length + current + 1 <= limit then
+  current = current + 1
+  allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'index', index, 'current', current, 'previous', previous)
+redis.call('PEXPIRE', KEYS[1], length * 2)
+return {allowed, index, current, previous, elapsed}
+`)
+
+// RedisStore is a Store shared by every instance through Redis
+// Each check is a single Lua script, so it is atomic across instances. The caller's clock is
+// passed in; the scripts never move a key's state backwards, so an instance whose clock lags
+// cannot refill budgets. Requires Redis 4+ (multi-field HSET).
+type RedisStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisStore creates a RedisStore whose keys start with prefix (e.g. "rl:")
+//
+// Example usage:
+//
+//	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+//	limiter := ratelimit.NewLimiter(ratelimit.NewRedisStore(client, "rl:"),
+//	    ratelimit.WithFailureMode(ratelimit.FailLocal),
+//	)
+func NewRedisStore(client redis.UniversalClient, prefix string) *RedisStore {
+	return &RedisStore{client: client, prefix: prefix}
+}
+
+// Allow implements Store
+func (s *RedisStore) Allow(ctx context.Context, key string, p Policy, now time.Time) (Decision, error) {
+	nowMs := now.UnixMilli()
+	length := p.window().Milliseconds()
+
+	if p.Algorithm == SlidingWindow {
+		values, err := slidingWindowScript.Run(ctx, s.client, []string{s.prefix + key},
+			p.Limit, length, nowMs,
+		).Slice()
+		if err != nil {
+			return Decision{}, fmt.Errorf("failed to check rate limit: %w", err)
+		}
+
+		ints, err := scriptInts(values, 5)
+		if err != nil {
+			return Decision{}, err
+		}
+		state := windowState{Index: ints[1], Current: int(ints[2]), Previous: int(ints[3])}
+		return windowDecision(p, state, ints[4], ints[0] == 1), nil
+	}
+
+	// The bucket is full again after capacity / rate; keep it a little longer
+	ttl := int64(p.capacity()/p.refillRate()) + length
+	values, err := tokenBucketScript.Run(ctx, s.client, []string{s.prefix + key},
+		p.capacity(), p.refillRate(), nowMs, ttl,
+	).Slice()
+	if err != nil {
+		return Decision{}, fmt.Errorf("failed to check rate limit: %w", err)
+	}
+
+	if len(values) != 3 {
+		return Decision{}, fmt.Errorf("unexpected rate limit script result: %v", values)
+	}
+	tokens, err := strconv.ParseFloat(fmt.Sprint(values[1]), 64)
+	if err != nil {
+		return Decision{}, fmt.Errorf("unexpected rate limit script result: %w", err)
+	}
+	at, okAt := values[2].(int64)
+	allowed, okAllowed := values[0].(int64)
+	if !okAt || !okAllowed {
+		return Decision{}, fmt.Errorf("unexpected rate limit script result: %v", values)
+	}
+	return bucketDecision(p, bucketState{Tokens: tokens, At: at}, allowed == 1), nil
+}
+
+// scriptInts converts a script's integer replies
+func scriptInts(values []interface{}, n int) ([]int64, error) {
+	if len(values) != n {
+		return nil, fmt.Errorf("unexpected rate limit script result: %v", values)
+	}
+
+	ints := make([]int64, n)
+	for i, value := range values {
+		v, ok := value.(int64)
+		if !ok {
+			return nil, fmt.Errorf("unexpected rate limit script result: %v", values)
+		}
+		ints[i] = v
+	}
+	return ints, nil
+}
@@ -0,0 +1,115 @@
+package ratelimit
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Registry maps routes to policies
+// Routes are gin route patterns as returned by c.FullPath() ("/users/:id"). Lookups try the
+// method and route, then the route for any method, then the default policy.
+//
+// Example usage:
+//
+//	policies := ratelimit.NewRegistry()
+//	_ = policies.Default(ratelimit.Policy{Limit: 600, Window: time.Minute})
+//	_ = policies.Set("GET", "/products", ratelimit.Policy{Limit: 120, Burst: 30})
+//	_ = policies.Set("POST", "/auth/login", ratelimit.Policy{
+//	    Algorithm: ratelimit.SlidingWindow, Limit: 5, Window: 15 * time.Minute,
+//	})
+//	policies.Exempt("GET", "/healthz")
+//
+//	r.Use(ratelimit.Middleware(limiter, policies))
+type Registry struct {
+	mu       sync.RWMutex
+	fallback *Policy
+	routes   map[string]Policy
+	exempt   map[string]bool
+}
+
+// NewRegistry creates an empty Registry (no default policy: unlisted routes are unlimited)
+func NewRegistry() *Registry {
+	return &Registry{routes: make(map[string]Policy), exempt: make(map[string]bool)}
+}
+
+// routeKey keys a route; method "" matches any method
+func routeKey(method, route string) string {
+	return method + " " + route
+}
+
+// Default sets the policy of routes without their own
+// Without a Name, every unlisted route has its own budget.
+func (r *Registry) Default(p Policy) error {
+	if err := p.validate(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fallback = &p
+	return nil
+}
+
+// Set sets the policy of a route; method "" applies it to every method
+func (r *Registry) Set(method, route string, p Policy) error {
+	if err := p.validate(); err != nil {
+		return fmt.Errorf("invalid policy for %s: %w", routeKey(method, route), err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.routes[routeKey(method, route)] = p
+	delete(r.exempt, routeKey(method, route))
+	return nil
+}
+
+// Exempt removes a route from rate limiting, including the default policy
+func (r *Registry) Exempt(method, route string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.exempt[routeKey(method, route)] = true
+	delete(r.routes, routeKey(method, route))
+}
+
+// Lookup returns the policy of a route, with Name defaulted to the route
+func (r *Registry) Lookup(method, route string) (Policy, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, key := range []string{routeKey(method, route), routeKey("", route)} {
+		if r.exempt[key] {
+			return Policy{}, false
+		}
+		if p, ok := r.routes[key]; ok {
+			if p.Name == "" {
+				p.Name = key
+			}
+			return p, true
+		}
+	}
+
+	if r.fallback == nil {
+		return Policy{}, false
+	}
+	p := *r.fallback
+	if p.Name == "" {
+		p.Name = routeKey(method, route)
+	}
+	return p, true
+}
+
+// validate rejects policies that cannot admit any request
+func (p Policy) validate() error {
+	if p.Limit < 1 {
+		return fmt.Errorf("limit must be at least 1, got %d", p.Limit)
+	}
+	if p.Burst < 0 {
+		return fmt.Errorf("burst must not be negative, got %d", p.Burst)
+	}
+	switch p.Algorithm {
+	case "", TokenBucket, SlidingWindow:
+		return nil
+	default:
+		return fmt.Errorf("unknown algorithm %q", p.Algorithm)
+	}
+}
@@ -0,0 +1,90 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Store keeps rate limit state
+// Allow must check and update the key's state atomically, so concurrent requests (and, for
+// shared stores, other instances) can never spend the same budget twice.
+type Store interface {
+	Allow(ctx context.Context, key string, p Policy, now time.Time) (Decision, error)
+}
+
+// memoryEntry is the state of one key in a MemoryStore
+type memoryEntry struct {
+	bucket   bucketState
+	window   windowState
+	lastSeen int64
+}
+
+// MemoryStore is an in-process Store
+// Limits are enforced per instance; use RedisStore to share budgets between instances.
+type MemoryStore struct {
+	mu      sync.Mutex
+	maxKeys int
+	entries map[string]*memoryEntry
+}
+
+// NewMemoryStore creates a MemoryStore tracking at most maxKeys callers (0 = {{maxMemoryKeys}})
+// When full, idle keys are dropped first; if none are idle, an arbitrary key is dropped,
+// which resets that caller's budget.
+//
+// Example usage:
+//
+//	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(0))
+func NewMemoryStore(maxKeys int) *MemoryStore {
+	if maxKeys <= 0 {
+		maxKeys = {{maxMemoryKeys}}
+	}
+	return &MemoryStore{maxKeys: maxKeys, entries: make(map[string]*memoryEntry)}
+}
+
+// Allow implements Store
+func (s *MemoryStore) Allow(ctx context.Context, key string, p Policy, now time.Time) (Decision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	nowMs := now.UnixMilli()
+	entry, ok := s.entries[key]
+	if !ok {
+		if len(s.entries) >= s.maxKeys {
+			s.evict(nowMs)
+		}
+		entry = &memoryEntry{window: windowState{Index: nowMs / p.window().Milliseconds()}}
+		s.entries[key] = entry
+	}
+	if nowMs > entry.lastSeen {
+		entry.lastSeen = nowMs
+	}
+
+	if p.Algorithm == SlidingWindow {
+		allowed, elapsed := countRequest(p, &entry.window, nowMs)
+		return windowDecision(p, entry.window, elapsed, allowed), nil
+	}
+	allowed := takeToken(p, &entry.bucket, nowMs)
+	return bucketDecision(p, entry.bucket, allowed), nil
+}
+
+// evict makes room for one key, dropping keys unseen for idleEviction first; the caller holds mu
+func (s *MemoryStore) evict(nowMs int64) {
+	idle := nowMs - idleEviction.Milliseconds()
+	for key, entry := range s.entries {
+		if entry.lastSeen < idle {
+			delete(s.entries, key)
+		}
+	}
+	if len(s.entries) < s.maxKeys {
+		return
+	}
+	for key := range s.entries {
+		delete(s.entries, key)
+		return
+	}
+}
+
+// idleEviction is how long a MemoryStore key must be unseen before it is considered idle
+// It should exceed the longest policy window, after which an unseen key's budget is full anyway
+const idleEviction = 10 * time.Minute
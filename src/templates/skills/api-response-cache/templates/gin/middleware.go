@@ -0,0 +1,223 @@
+package responsecache
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"{{packageImportPath}}/pagination"
+)
+
+// Config configures the caching middleware for one route
+type Config struct {
+	// Store holds the cached responses
+	Store Store
+
+	// Resource tags entries so InvalidateResources(ctx, store, Resource) purges them
+	Resource string
+
+	// TTL is how long responses stay cached (default {{cacheTTL}}s)
+	TTL time.Duration
+
+	// VaryHeaders are request headers that change the response (e.g. Accept-Language)
+	VaryHeaders []string
+
+	// PageIndexing must match the route's page numbering, so ?page=0 on a ZeroBased route
+	// is not folded into page 1's entry
+	PageIndexing pagination.PageIndexing
+
+	// CacheAuthorized caches requests that carry Authorization. Only enable this when the
+	// response does not depend on who is asking; the header is never part of the key.
+	CacheAuthorized bool
+}
+
+// cachedResponse is the serialized form of a stored response
+type cachedResponse struct {
+	Status      int       `json:"status"`
+	ContentType string    `json:"content_type"`
+	Body        []byte    `json:"body"`
+	StoredAt    time.Time `json:"stored_at"`
+}
+
+// Key returns the cache key of a request
+// The key covers the method, path, vary headers, and the query string in canonical form:
+// pagination params are normalized with PaginationParams.CanonicalQuery (so "?page_size=20&page=2"
+// and "?page=2&limit=20" share an entry) and the remaining params are sorted by name, which
+// makes filter[...] order irrelevant. Sort values keep their order, since ?sort=a,b and
+// ?sort=b,a are different lists.
+func Key(c *gin.Context, cfg Config) string {
+	values := c.Request.URL.Query()
+	canonical := pagination.ParamsFromQueryWithIndexing(values, cfg.PageIndexing).CanonicalQuery()
+	for _, key := range pagination.QueryKeys {
+		values.Del(key)
+	}
+
+	var b strings.Builder
+	b.WriteString(c.Request.Method)
+	b.WriteString(" ")
+	b.WriteString(c.Request.URL.Path)
+	b.WriteString("?")
+	b.WriteString(canonical)
+	if rest := values.Encode(); rest != "" {
+		b.WriteString("&")
+		b.WriteString(rest)
+	}
+	for _, header := range cfg.VaryHeaders {
+		b.WriteString("\n")
+		b.WriteString(http.CanonicalHeaderKey(header))
+		b.WriteString(": ")
+		b.WriteString(c.GetHeader(header))
+	}
+
+	sum := sha256.Sum256([]byte(b.String()))
+	return cfg.Resource + ":" + hex.EncodeToString(sum[:16])
+}
+
+// Middleware caches successful GET responses of a list route
+// Requests bypass the cache when they send Authorization (unless CacheAuthorized) or
+// Cache-Control: no-cache / no-store / max-age=0. Responses are stored only when they are
+// 200 OK, set no cookies, and are not marked private or no-store. Store errors never fail
+// the request; the handler simply runs uncached. X-Cache reports HIT, MISS, or BYPASS.
+//
+// Example usage:
+//
+//	store := responsecache.NewMemoryStore(0)
+//	cached := responsecache.Config{Store: store, Resource: "products", TTL: 30 * time.Second}
+//
+//	r.GET("/products",
+//	    responsecache.Middleware(cached),
+//	    pagination.ParsePaginationParams,
+//	    ListProducts,
+//	)
+//	r.POST("/products", responsecache.InvalidateOnWrite(store, "products"), CreateProduct)
+func Middleware(cfg Config) gin.HandlerFunc {
+	if cfg.TTL <= 0 {
+		cfg.TTL = {{cacheTTL}} * time.Second
+	}
+
+	return func(c *gin.Context) {
+		if bypass(c, cfg) {
+			c.Header("X-Cache", "BYPASS")
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		key := Key(c, cfg)
+
+		if !requestDirective(c, "no-cache") {
+			if raw, ok, err := cfg.Store.Get(ctx, key); err == nil && ok {
+				var cached cachedResponse
+				if json.Unmarshal(raw, &cached) == nil {
+					age := int(time.Since(cached.StoredAt).Seconds())
+					c.Header("X-Cache", "HIT")
+					c.Header("Age", strconv.Itoa(age))
+					c.Data(cached.Status, cached.ContentType, cached.Body)
+					c.Abort()
+					return
+				}
+			}
+		}
+
+		c.Header("X-Cache", "MISS")
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		if !storable(recorder) {
+			return
+		}
+
+		raw, err := json.Marshal(cachedResponse{
+			Status:      recorder.Status(),
+			ContentType: recorder.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+			StoredAt:    time.Now(),
+		})
+		if err == nil {
+			_ = cfg.Store.Set(ctx, key, raw, cfg.TTL, cfg.Resource)
+		}
+	}
+}
+
+// InvalidateResources purges every cached response tagged with one of resources
+//
+// Example usage:
+//
+//	if err := db.Create(&product).Error; err == nil {
+//	    _ = responsecache.InvalidateResources(c.Request.Context(), store, "products", "categories")
+//	}
+func InvalidateResources(ctx context.Context, store Store, resources ...string) error {
+	return store.InvalidateTags(ctx, resources...)
+}
+
+// InvalidateOnWrite returns middleware that purges resources after a successful (2xx) write
+func InvalidateOnWrite(store Store, resources ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if status := c.Writer.Status(); status >= 200 && status < 300 {
+			if err := InvalidateResources(c.Request.Context(), store, resources...); err != nil {
+				_ = c.Error(err)
+			}
+		}
+	}
+}
+
+// bypass reports whether a request must skip the cache entirely
+func bypass(c *gin.Context, cfg Config) bool {
+	if c.Request.Method != http.MethodGet {
+		return true
+	}
+	if c.GetHeader("Authorization") != "" && !cfg.CacheAuthorized {
+		return true
+	}
+	return requestDirective(c, "no-store") || requestDirective(c, "max-age=0")
+}
+
+// requestDirective reports whether the request's Cache-Control (or Pragma) has directive
+func requestDirective(c *gin.Context, directive string) bool {
+	if directive == "no-cache" && strings.EqualFold(c.GetHeader("Pragma"), "no-cache") {
+		return true
+	}
+	for _, part := range strings.Split(c.GetHeader("Cache-Control"), ",") {
+		if strings.EqualFold(strings.TrimSpace(part), directive) {
+			return true
+		}
+	}
+	return false
+}
+
+// storable reports whether a recorded response may be cached
+func storable(r *responseRecorder) bool {
+	if r.Status() != http.StatusOK || r.Header().Get("Set-Cookie") != "" {
+		return false
+	}
+
+	control := strings.ToLower(r.Header().Get("Cache-Control"))
+	return !strings.Contains(control, "private") && !strings.Contains(control, "no-store")
+}
+
+// responseRecorder copies the response body while it is written to the client
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.Write([]byte(s))
+}
@@ -0,0 +1,43 @@
+package responsecache
+
+import (
+	"context"
+	"time"
+
+	"{{packageImportPath}}/pagination"
+)
+
+// pageCacheTagPrefix keeps the paginator's table tags apart from resource tags
+const pageCacheTagPrefix = "pagination:"
+
+// pageCache adapts a Store to pagination.PageCache
+type pageCache struct {
+	store Store
+}
+
+// PageCache lets the paginators cache pages in store, e.g. a RedisStore shared by every
+// instance, so a write invalidating a table's pages on one instance clears them for all
+//
+// Example usage:
+//
+//	pages := responsecache.PageCache(responsecache.NewRedisStore(client, "pages:"))
+//	pagination.RegisterPageCacheInvalidation(db, pages)
+//
+//	result, err := pagination.OffsetPaginate(db.Model(&Product{}), &products, page, pageSize,
+//	    pagination.WithPageCache(pages, 10*time.Second),
+//	)
+func PageCache(store Store) pagination.PageCache {
+	return pageCache{store: store}
+}
+
+func (c pageCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return c.store.Get(ctx, key)
+}
+
+func (c pageCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration, table string) error {
+	return c.store.Set(ctx, key, value, ttl, pageCacheTagPrefix+table)
+}
+
+func (c pageCache) InvalidateTable(ctx context.Context, table string) error {
+	return c.store.InvalidateTags(ctx, pageCacheTagPrefix+table)
+}
@@ -0,0 +1,74 @@
+package responsecache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore is a Store shared by every instance through Redis
+// Each tag is a Redis set of the keys cached under it; invalidating a tag deletes the keys
+// and the set. A tag set's expiry is refreshed by each Set, so use one TTL per resource.
+type RedisStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisStore creates a RedisStore whose keys start with prefix (e.g. "rc:")
+//
+// Example usage:
+//
+//	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+//	store := responsecache.NewRedisStore(client, "rc:")
+func NewRedisStore(client redis.UniversalClient, prefix string) *RedisStore {
+	return &RedisStore{client: client, prefix: prefix}
+}
+
+func (s *RedisStore) tagKey(tag string) string {
+	return s.prefix + "tag:" + tag
+}
+
+// Get implements Store
+func (s *RedisStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := s.client.Get(ctx, s.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read cached response: %w", err)
+	}
+	return value, true, nil
+}
+
+// Set implements Store
+func (s *RedisStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	pipe := s.client.TxPipeline()
+	pipe.Set(ctx, s.prefix+key, value, ttl)
+	for _, tag := range tags {
+		pipe.SAdd(ctx, s.tagKey(tag), s.prefix+key)
+		pipe.Expire(ctx, s.tagKey(tag), ttl)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to cache response: %w", err)
+	}
+	return nil
+}
+
+// InvalidateTags implements Store
+func (s *RedisStore) InvalidateTags(ctx context.Context, tags ...string) error {
+	for _, tag := range tags {
+		keys, err := s.client.SMembers(ctx, s.tagKey(tag)).Result()
+		if err != nil {
+			return fmt.Errorf("failed to read cache tag %s: %w", tag, err)
+		}
+
+		if err := s.client.Del(ctx, append(keys, s.tagKey(tag))...).Err(); err != nil {
+			return fmt.Errorf("failed to invalidate cache tag %s: %w", tag, err)
+		}
+	}
+	return nil
+}
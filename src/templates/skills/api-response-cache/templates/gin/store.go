@@ -0,0 +1,133 @@
+package responsecache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// Store persists serialized responses
+// Entries carry tags (resource names) so a write can purge every cached list of a resource
+type Store interface {
+	// Get returns the value for key; ok is false on a miss or an expired entry
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+
+	// Set stores value under key for ttl and indexes it under each tag
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error
+
+	// InvalidateTags removes every entry indexed under any of tags
+	InvalidateTags(ctx context.Context, tags ...string) error
+}
+
+// memoryEntry is one cached response in a MemoryStore
+type memoryEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+	tags    []string
+}
+
+// MemoryStore is an in-process LRU Store
+// Suitable for a single instance; use RedisStore when several instances must share
+// entries and invalidations
+type MemoryStore struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List               // front = most recently used
+	entries    map[string]*list.Element // key -> element holding *memoryEntry
+	tags       map[string]map[string]struct{}
+}
+
+// NewMemoryStore creates a MemoryStore holding at most maxEntries responses
+// (0 = {{maxMemoryEntries}})
+//
+// Example usage:
+//
+//	store := responsecache.NewMemoryStore(0)
+//	r.GET("/products", responsecache.Middleware(responsecache.Config{Store: store, Resource: "products"}), ListProducts)
+func NewMemoryStore(maxEntries int) *MemoryStore {
+	if maxEntries <= 0 {
+		maxEntries = {{maxMemoryEntries}}
+	}
+	return &MemoryStore{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+		tags:       make(map[string]map[string]struct{}),
+	}
+}
+
+// Get implements Store
+func (s *MemoryStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element, ok := s.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	entry := element.Value.(*memoryEntry)
+	if time.Now().After(entry.expires) {
+		s.remove(element)
+		return nil, false, nil
+	}
+
+	s.order.MoveToFront(element)
+	return entry.value, true, nil
+}
+
+// Set implements Store
+func (s *MemoryStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration, tags ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.entries[key]; ok {
+		s.remove(element)
+	}
+
+	entry := &memoryEntry{key: key, value: value, expires: time.Now().Add(ttl), tags: tags}
+	s.entries[key] = s.order.PushFront(entry)
+	for _, tag := range tags {
+		if s.tags[tag] == nil {
+			s.tags[tag] = make(map[string]struct{})
+		}
+		s.tags[tag][key] = struct{}{}
+	}
+
+	for s.order.Len() > s.maxEntries {
+		s.remove(s.order.Back())
+	}
+	return nil
+}
+
+// InvalidateTags implements Store
+func (s *MemoryStore) InvalidateTags(ctx context.Context, tags ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, tag := range tags {
+		for key := range s.tags[tag] {
+			if element, ok := s.entries[key]; ok {
+				s.remove(element)
+			}
+		}
+		delete(s.tags, tag)
+	}
+	return nil
+}
+
+// remove drops an entry and its tag index entries; the caller holds mu
+func (s *MemoryStore) remove(element *list.Element) {
+	entry := element.Value.(*memoryEntry)
+	s.order.Remove(element)
+	delete(s.entries, entry.key)
+
+	for _, tag := range entry.tags {
+		delete(s.tags[tag], entry.key)
+		if len(s.tags[tag]) == 0 {
+			delete(s.tags, tag)
+		}
+	}
+}
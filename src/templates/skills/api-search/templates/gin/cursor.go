@@ -0,0 +1,104 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+
+	"{{packageImportPath}}/pagination"
+)
+
+// searchCursor is the keyset position after the last hit of a page
+// Rank is captured as returned by the database, so the next page resumes exactly after it
+// instead of recomputing scores that may have shifted
+type searchCursor struct {
+	Query string  `json:"q"`
+	Rank  float64 `json:"r"`
+	ID    any     `json:"id"`
+}
+
+// queryHash fingerprints a parsed query so cursors cannot be replayed against another query
+func queryHash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return base64.RawURLEncoding.EncodeToString(sum[:6])
+}
+
+// encodeCursor builds the opaque cursor for the position after (rank, id)
+func encodeCursor(codec pagination.CursorCodec, query string, rank float64, id any) (string, error) {
+	token, err := codec.Encode(searchCursor{Query: queryHash(query), Rank: rank, ID: id})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode search cursor: %w", err)
+	}
+	return token, nil
+}
+
+// decodeCursor reverses encodeCursor and checks the cursor belongs to query
+// The codec must round-trip structured values (pagination.JSONCursorCodec or a wrapper of it)
+func decodeCursor(codec pagination.CursorCodec, cursor string, query string) (*searchCursor, error) {
+	value, err := codec.Decode(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var decoded searchCursor
+	if err := decoder.Decode(&decoded); err != nil || decoded.ID == nil {
+		return nil, ErrInvalidCursor
+	}
+	if decoded.Query != queryHash(query) {
+		return nil, ErrQueryChanged
+	}
+
+	// Integer IDs come back as json.Number; bind them as integers so the comparison uses the index
+	if number, ok := decoded.ID.(json.Number); ok {
+		if id, err := number.Int64(); err == nil {
+			decoded.ID = id
+		} else {
+			decoded.ID = number.String()
+		}
+	}
+
+	return &decoded, nil
+}
+
+// schemaCache caches parsed model schemas for ID extraction
+var schemaCache sync.Map
+
+// idExtractor resolves column on T and returns a function reading it from an item
+func idExtractor[T any](db *gorm.DB, column string) (func(ctx context.Context, item *T) any, error) {
+	modelSchema, err := schema.Parse(new(T), &schemaCache, db.NamingStrategy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse model schema: %w", err)
+	}
+
+	name := column
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+
+	field := modelSchema.LookUpField(strings.Trim(name, "`\""))
+	if field == nil {
+		return nil, fmt.Errorf("id column %q not found on %s", column, modelSchema.Name)
+	}
+
+	return func(ctx context.Context, item *T) any {
+		value, _ := field.ValueOf(ctx, reflect.ValueOf(item).Elem())
+		return value
+	}, nil
+}
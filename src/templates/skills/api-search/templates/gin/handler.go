@@ -0,0 +1,54 @@
+package search
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"{{packageImportPath}}/pagination"
+)
+
+// Handler returns a search endpoint for T that reads ?{{queryParam}}= alongside the
+// standard pagination params (ParsePaginationParams must run first)
+//
+// Example usage:
+//
+//	import "{{packageImportPath}}/search"
+//
+//	r.GET("/articles/search",
+//	    pagination.ParsePaginationParams,
+//	    search.Handler[Article](db, articleSearch),
+//	)
+//
+//	// GET /articles/search?{{queryParam}}=postgres+"full text"&page_size=20
+//	// GET /articles/search?{{queryParam}}=postgres+"full text"&cursor=eyJxIjoi...
+func Handler[T any](db *gorm.DB, cfg Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result, err := Paginate[T](
+			db.WithContext(c.Request.Context()),
+			cfg,
+			c.Query("{{queryParam}}"),
+			pagination.GetCursor(c),
+			pagination.GetPageSize(c),
+		)
+		if err != nil {
+			AbortWithSearchError(c, err)
+			return
+		}
+
+		c.JSON(200, result)
+	}
+}
+
+// AbortWithSearchError responds 400 for missing queries and bad or stale cursors, 500 otherwise
+func AbortWithSearchError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrEmptyQuery):
+		c.AbortWithStatusJSON(400, gin.H{"error": err.Error(), "param": "{{queryParam}}"})
+	case errors.Is(err, ErrInvalidCursor), errors.Is(err, ErrQueryChanged):
+		c.AbortWithStatusJSON(400, gin.H{"error": err.Error(), "param": "cursor"})
+	default:
+		c.AbortWithStatusJSON(500, gin.H{"error": err.Error()})
+	}
+}
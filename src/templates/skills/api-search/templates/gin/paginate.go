@@ -0,0 +1,325 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// Hit is one search result with its relevance score and highlighted snippet
+type Hit[T any] struct {
+	Item     T       `json:"item"`
+	Rank     float64 `json:"rank"`
+	Headline string  `json:"headline,omitempty"`
+}
+
+// Result represents one page of search results
+// Hits are ordered by rank (descending) and then by ID, which keeps the order total and stable
+type Result[T any] struct {
+	Query       string   `json:"query"`
+	Hits        []Hit[T] `json:"hits"`
+	NextCursor  *string  `json:"next_cursor,omitempty"`
+	HasNext     bool     `json:"has_next"`
+	HasPrevious bool     `json:"has_previous"`
+	PageSize    int      `json:"page_size"`
+
+	// Total is counted on the first page only and stops at Config.TotalCap
+	// TotalCapped reports that more matches exist than were counted
+	Total       *int64 `json:"total,omitempty"`
+	TotalCapped bool   `json:"total_capped,omitempty"`
+
+	// FullText is false when the ILIKE fallback was used (no ranking, hits ordered by ID)
+	FullText bool `json:"full_text"`
+}
+
+// hitRow scans a row of T together with the computed search columns
+type hitRow[T any] struct {
+	Item           T `gorm:"embedded"`
+	SearchRank     float64
+	SearchHeadline string
+}
+
+// searchSQL holds the dialect-specific SQL for one search
+type searchSQL struct {
+	match      string
+	matchArgs  []any
+	columns    string
+	columnArgs []any
+	rank       string // empty when the dialect cannot rank
+	rankArgs   []any
+	idColumn   string
+	order      string
+}
+
+// Paginate runs a relevance-ranked search and returns one page of hits
+// On Postgres the query is parsed with websearch_to_tsquery (quotes, OR, and -term work),
+// ranked with ts_rank, and highlighted with ts_headline. Pages are keyset-paginated on
+// (rank, id): the cursor carries the last rank so the next page continues right after it.
+// Other databases fall back to case-insensitive LIKE matching of every term, ordered by ID.
+//
+// Example usage:
+//
+//	func SearchArticles(c *gin.Context) {
+//	    result, err := search.Paginate[Article](
+//	        db.WithContext(c.Request.Context()),
+//	        articleSearch,
+//	        c.Query("q"),
+//	        pagination.GetCursor(c),
+//	        pagination.GetPageSize(c),
+//	    )
+//	    if err != nil {
+//	        search.AbortWithSearchError(c, err)
+//	        return
+//	    }
+//
+//	    c.JSON(200, result)
+//	}
+func Paginate[T any](db *gorm.DB, cfg Config, rawQuery string, cursor string, pageSize int) (*Result[T], error) {
+	cfg, err := cfg.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	query, err := ParseQuery(rawQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	if pageSize < 1 {
+		pageSize = 1
+	}
+
+	idOf, err := idExtractor[T](db, cfg.IDColumn)
+	if err != nil {
+		return nil, err
+	}
+
+	var after *searchCursor
+	if cursor != "" {
+		after, err = decodeCursor(cfg.Codec, cursor, query)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	fullText := db.Dialector.Name() == "postgres"
+	words := terms(query)
+
+	var s searchSQL
+	if fullText {
+		s = fullTextSQL(cfg, query)
+	} else {
+		s = fallbackSQL(cfg, query, words)
+	}
+
+	base := db.Session(&gorm.Session{}).Table(cfg.Table).Where(s.match, s.matchArgs...)
+
+	result := &Result[T]{
+		Query:       query,
+		HasPrevious: after != nil,
+		PageSize:    pageSize,
+		FullText:    fullText,
+	}
+
+	// Counting every match is the expensive part of search, so only the first page
+	// counts, and only up to the cap
+	if after == nil {
+		total, err := cappedCount(base, cfg.TotalCap+1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count search results: %w", err)
+		}
+		if total > int64(cfg.TotalCap) {
+			total = int64(cfg.TotalCap)
+			result.TotalCapped = true
+		}
+		result.Total = &total
+	}
+
+	page := base.Session(&gorm.Session{}).Select(s.columns, s.columnArgs...)
+	if after != nil {
+		condition, args := s.after(after)
+		page = page.Where(condition, args...)
+	}
+
+	// Fetch one extra row to detect a next page
+	var rows []hitRow[T]
+	if err := page.Order(s.order).Limit(pageSize + 1).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to run search: %w", err)
+	}
+
+	result.HasNext = len(rows) > pageSize
+	if result.HasNext {
+		rows = rows[:pageSize]
+	}
+
+	result.Hits = make([]Hit[T], len(rows))
+	for i, row := range rows {
+		headline := row.SearchHeadline
+		if !fullText {
+			headline = snippet(headline, words)
+		}
+		result.Hits[i] = Hit[T]{Item: row.Item, Rank: row.SearchRank, Headline: headline}
+	}
+
+	if result.HasNext {
+		ctx := context.Background()
+		if db.Statement != nil && db.Statement.Context != nil {
+			ctx = db.Statement.Context
+		}
+
+		last := rows[len(rows)-1]
+		token, err := encodeCursor(cfg.Codec, query, last.SearchRank, idOf(ctx, &last.Item))
+		if err != nil {
+			return nil, err
+		}
+		result.NextCursor = &token
+	}
+
+	return result, nil
+}
+
+// fullTextSQL builds the Postgres tsvector search
+func fullTextSQL(cfg Config, query string) searchSQL {
+	tsquery := "websearch_to_tsquery(?::regconfig, ?)"
+	tsqueryArgs := []any{cfg.Language, query}
+
+	vector, vectorArgs := cfg.Vector, []any(nil)
+	if vector == "" {
+		parts := make([]string, len(cfg.Columns))
+		for i, column := range cfg.Columns {
+			parts[i] = fmt.Sprintf("coalesce(%s, '')", cfg.column(column))
+		}
+		vector = fmt.Sprintf("to_tsvector(?::regconfig, %s)", strings.Join(parts, " || ' ' || "))
+		vectorArgs = []any{cfg.Language}
+	}
+
+	// Cast to float8 so the rank bound from the cursor compares exactly with the recomputed one
+	rank := fmt.Sprintf("ts_rank(%s, %s)::float8", vector, tsquery)
+	rankArgs := concatArgs(vectorArgs, tsqueryArgs)
+
+	headline := fmt.Sprintf("ts_headline(?::regconfig, coalesce(%s, ''), %s, ?)",
+		cfg.column(cfg.HeadlineColumn), tsquery)
+
+	idColumn := cfg.column(cfg.IDColumn)
+	return searchSQL{
+		match:     fmt.Sprintf("%s @@ %s", vector, tsquery),
+		matchArgs: concatArgs(vectorArgs, tsqueryArgs),
+		columns:   fmt.Sprintf("%s.*, %s AS search_rank, %s AS search_headline", cfg.Table, rank, headline),
+		columnArgs: concatArgs(rankArgs, []any{cfg.Language}, tsqueryArgs,
+			[]any{cfg.HeadlineOptions}),
+		rank:     rank,
+		rankArgs: rankArgs,
+		idColumn: idColumn,
+		order:    fmt.Sprintf("search_rank DESC, %s ASC", idColumn),
+	}
+}
+
+// fallbackSQL builds a LIKE search for databases without full-text support
+// Every term must appear in at least one column; rank is always 0
+func fallbackSQL(cfg Config, query string, words []string) searchSQL {
+	if len(words) == 0 {
+		words = []string{query}
+	}
+
+	var conditions []string
+	var args []any
+	for _, word := range words {
+		pattern := "%" + escapeLike(strings.ToLower(word)) + "%"
+
+		matches := make([]string, len(cfg.Columns))
+		for i, column := range cfg.Columns {
+			matches[i] = fmt.Sprintf("LOWER(%s) LIKE ? ESCAPE '!'", cfg.column(column))
+			args = append(args, pattern)
+		}
+		conditions = append(conditions, "("+strings.Join(matches, " OR ")+")")
+	}
+
+	idColumn := cfg.column(cfg.IDColumn)
+	return searchSQL{
+		match:     strings.Join(conditions, " AND "),
+		matchArgs: args,
+		columns: fmt.Sprintf("%s.*, 0 AS search_rank, coalesce(%s, '') AS search_headline",
+			cfg.Table, cfg.column(cfg.HeadlineColumn)),
+		idColumn: idColumn,
+		order:    idColumn + " ASC",
+	}
+}
+
+// after returns the keyset condition for rows following the cursor
+func (s searchSQL) after(c *searchCursor) (string, []any) {
+	if s.rank == "" {
+		return s.idColumn + " > ?", []any{c.ID}
+	}
+
+	condition := fmt.Sprintf("(%s < ? OR (%s = ? AND %s > ?))", s.rank, s.rank, s.idColumn)
+	return condition, concatArgs(s.rankArgs, []any{c.Rank}, s.rankArgs, []any{c.Rank, c.ID})
+}
+
+// concatArgs joins bind-argument lists in order
+func concatArgs(lists ...[]any) []any {
+	var args []any
+	for _, list := range lists {
+		args = append(args, list...)
+	}
+	return args
+}
+
+// snippet approximates ts_headline for the fallback: a window of text around the first matching term
+func snippet(text string, words []string) string {
+	const before, after = 60, 140
+
+	runes := []rune(text)
+	lower := strings.ToLower(text)
+
+	start := 0
+	for _, word := range words {
+		if i := strings.Index(lower, strings.ToLower(word)); i >= 0 {
+			start = utf8.RuneCountInString(lower[:i])
+			break
+		}
+	}
+	if start > len(runes) {
+		start = 0
+	}
+
+	from, to := start-before, start+after
+	if from < 0 {
+		from = 0
+	}
+	if to > len(runes) {
+		to = len(runes)
+	}
+
+	// Widen to word boundaries so the snippet does not start or end mid-word
+	for from > 0 && !unicode.IsSpace(runes[from-1]) {
+		from--
+	}
+	for to < len(runes) && !unicode.IsSpace(runes[to]) {
+		to++
+	}
+
+	out := strings.TrimSpace(string(runes[from:to]))
+	if from > 0 {
+		out = "…" + out
+	}
+	if to < len(runes) {
+		out += "…"
+	}
+	return out
+}
+
+// cappedCount counts the rows of query, stopping after limit rows
+// The LIMIT sits inside a subquery so COUNT never walks every match
+func cappedCount(query *gorm.DB, limit int) (int64, error) {
+	var counted int64
+	window := query.Session(&gorm.Session{}).Select("1").Limit(limit)
+	err := query.Session(&gorm.Session{NewDB: true}).
+		Table("(?) AS capped_window", window).
+		Count(&counted).Error
+
+	return counted, err
+}
@@ -0,0 +1,132 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"{{packageImportPath}}/pagination"
+)
+
+// MaxQueryLength caps the length of search queries in characters; longer queries are truncated
+const MaxQueryLength = {{maxQueryLength}}
+
+// ErrEmptyQuery is returned when the search query is missing or only whitespace
+var ErrEmptyQuery = errors.New("search query is required")
+
+// ErrQueryChanged is returned when a cursor is replayed with a different search query
+// Ranks are only comparable within one query, so the cursor would land at an arbitrary position
+var ErrQueryChanged = errors.New("cursor was issued for a different search query")
+
+// ErrInvalidCursor is returned when a search cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid search cursor")
+
+// Config describes a searchable table
+//
+// Example usage:
+//
+//	var articleSearch = search.Config{
+//	    Table:   "articles",
+//	    Vector:  "articles.search_vector", // stored tsvector column with a GIN index
+//	    Columns: []string{"title", "body"},
+//	}
+type Config struct {
+	// Table is the table being searched; columns below are qualified with it
+	Table string
+
+	// IDColumn is the unique tiebreaker for equal ranks (default "id")
+	IDColumn string
+
+	// Vector is the tsvector expression to match against, ideally a stored column with a GIN index
+	// When empty, to_tsvector is computed over Columns on every query
+	Vector string
+
+	// Columns are the text columns searched; also used by the ILIKE fallback
+	Columns []string
+
+	// HeadlineColumn is the column snippets are extracted from (default: first of Columns)
+	HeadlineColumn string
+
+	// Language is the text search configuration (default "{{searchLanguage}}")
+	Language string
+
+	// HeadlineOptions are passed to ts_headline (default "MaxFragments=2, MaxWords=20, MinWords=5")
+	HeadlineOptions string
+
+	// TotalCap bounds the counted total (default {{totalCap}}); larger result sets report TotalCapped
+	TotalCap int
+
+	// Codec encodes cursors (nil = pagination.JSONCursorCodec)
+	Codec pagination.CursorCodec
+}
+
+// withDefaults fills unset fields and rejects unusable configs
+func (cfg Config) withDefaults() (Config, error) {
+	if cfg.Table == "" {
+		return cfg, errors.New("search config requires a table")
+	}
+	if len(cfg.Columns) == 0 {
+		return cfg, fmt.Errorf("search config for %s requires at least one column", cfg.Table)
+	}
+	if cfg.IDColumn == "" {
+		cfg.IDColumn = "id"
+	}
+	if cfg.HeadlineColumn == "" {
+		cfg.HeadlineColumn = cfg.Columns[0]
+	}
+	if cfg.Language == "" {
+		cfg.Language = "{{searchLanguage}}"
+	}
+	if cfg.HeadlineOptions == "" {
+		cfg.HeadlineOptions = "MaxFragments=2, MaxWords=20, MinWords=5"
+	}
+	if cfg.TotalCap <= 0 {
+		cfg.TotalCap = {{totalCap}}
+	}
+	if cfg.Codec == nil {
+		cfg.Codec = pagination.JSONCursorCodec{}
+	}
+	return cfg, nil
+}
+
+// column qualifies name with the table unless it already is
+func (cfg Config) column(name string) string {
+	if strings.Contains(name, ".") {
+		return name
+	}
+	return cfg.Table + "." + name
+}
+
+// ParseQuery normalizes a raw search query: whitespace is collapsed and the result is
+// truncated to MaxQueryLength characters. Operators (quotes, OR, -term) are left for
+// websearch_to_tsquery, which never fails on malformed input.
+func ParseQuery(raw string) (string, error) {
+	query := strings.Join(strings.Fields(raw), " ")
+	if query == "" {
+		return "", ErrEmptyQuery
+	}
+
+	if utf8.RuneCountInString(query) > MaxQueryLength {
+		query = strings.TrimSpace(string([]rune(query)[:MaxQueryLength]))
+	}
+	return query, nil
+}
+
+// terms splits a parsed query into words for the ILIKE fallback, dropping websearch operators
+func terms(query string) []string {
+	var words []string
+	for _, word := range strings.Fields(query) {
+		word = strings.Trim(word, `"`)
+		if word == "" || strings.EqualFold(word, "or") || strings.HasPrefix(word, "-") {
+			continue
+		}
+		words = append(words, word)
+	}
+	return words
+}
+
+// escapeLike escapes LIKE wildcards so user input is matched literally (used with ESCAPE '!')
+func escapeLike(value string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(value)
+}
@@ -0,0 +1,83 @@
+package sorting
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ApplySort orders db by the given fields using the schema registered for the query's model
+// The query must have a model set (db.Model(&User{})). Fields are validated against the
+// allowlist, required joins are added once each, and the schema's unique tiebreaker is
+// always appended so pages never overlap or skip rows. Empty fields use the schema default.
+//
+// Example usage:
+//
+//	func ListUsers(c *gin.Context) {
+//	    params := sorting.GetListParams(c)
+//
+//	    query, err := sorting.ApplySort(db.Model(&User{}), params.Sort)
+//	    if err != nil {
+//	        sorting.AbortWithSortError(c, err)
+//	        return
+//	    }
+//
+//	    var users []User
+//	    result, err := pagination.OffsetPaginate(query, &users, params.Page, params.PageSize)
+//	    if err != nil {
+//	        c.JSON(500, gin.H{"error": err.Error()})
+//	        return
+//	    }
+//
+//	    c.JSON(200, result)
+//	}
+func ApplySort(db *gorm.DB, fields []SortField) (*gorm.DB, error) {
+	var model interface{}
+	if db.Statement != nil {
+		model = db.Statement.Model
+	}
+
+	schema, ok := lookupSchema(model)
+	if !ok {
+		return nil, fmt.Errorf("no sort schema registered for model %T (call sorting.Register first)", model)
+	}
+
+	return ApplySortWithSchema(db, schema, fields)
+}
+
+// ApplySortWithSchema is ApplySort with an explicit schema (for Raw/Table queries without a model)
+func ApplySortWithSchema(db *gorm.DB, schema *SortSchema, fields []SortField) (*gorm.DB, error) {
+	if len(fields) == 0 {
+		fields = schema.defaults
+	}
+
+	if err := schema.Validate(fields); err != nil {
+		return nil, err
+	}
+
+	query := db
+	joined := make(map[string]bool)
+	lastDirection := Asc
+
+	for _, field := range fields {
+		column := schema.columns[field.Name]
+
+		for _, join := range column.Joins {
+			if !joined[join] {
+				joined[join] = true
+				query = query.Joins(join)
+			}
+		}
+
+		query = query.Order(fmt.Sprintf("%s %s", column.Column, field.Direction))
+		lastDirection = field.Direction
+	}
+
+	// Append the unique tiebreaker unless the caller already ended on it
+	if len(fields) == 0 || !strings.EqualFold(schema.columns[fields[len(fields)-1].Name].Column, schema.tiebreaker) {
+		query = query.Order(fmt.Sprintf("%s %s", schema.tiebreaker, lastDirection))
+	}
+
+	return query, nil
+}
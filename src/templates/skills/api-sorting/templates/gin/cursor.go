@@ -0,0 +1,334 @@
+package sorting
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+
+	"{{packageImportPath}}/pagination"
+)
+
+// ErrCursorSortMismatch is returned when a cursor was issued for a different sort than the
+// current request's
+// It wraps pagination.ErrCursorFieldMismatch, so code that restarts pagination on a changed
+// cursor field handles a changed ?sort= the same way.
+var ErrCursorSortMismatch = fmt.Errorf("%w: sort changed", pagination.ErrCursorFieldMismatch)
+
+// sortCursor is the cursor payload: the sort it was issued for and the boundary row's
+// value for every sort key, tiebreaker last
+type sortCursor struct {
+	Sort   string            `json:"s"`
+	Values []json.RawMessage `json:"v"`
+}
+
+// sortKey is one ORDER BY key of a cursor sort and the model field holding its value
+type sortKey struct {
+	column    string
+	direction SortDirection
+	field     *schema.Field
+}
+
+// cursorSchemas caches parsed model schemas for reading sort key values
+var cursorSchemas sync.Map
+
+// SortedCursorPaginate paginates T with a cursor under any allowlisted sort
+// fields is the parsed ?sort= (empty = the schema default) and is validated against the
+// schema registered for T. The query is ordered like ApplySort, and the cursor records the
+// sort together with the last row's value for every key, so the next page continues with
+// a keyset predicate in the requested directions:
+//
+//	-created_at,name => (created_at < ?) OR (created_at = ? AND name > ?) OR (... AND id > ?)
+//
+// A cursor issued for another sort fails with ErrCursorSortMismatch. Sort columns must belong
+// to T (joined columns are rejected) and be non-null. Pagination is forward only, so results
+// carry NextCursor but no PreviousCursor; FirstKey and LastKey hold the boundary rows' values.
+//
+// Example usage:
+//
+//	func ListUsers(c *gin.Context) {
+//	    params := sorting.GetListParams(c)
+//
+//	    var users []User
+//	    result, err := sorting.SortedCursorPaginate(db.Model(&User{}), &users,
+//	        params.Cursor, params.PageSize, params.Sort)
+//	    if err != nil {
+//	        sorting.AbortWithSortError(c, err)
+//	        return
+//	    }
+//
+//	    c.JSON(200, result) // follow next_cursor with the same ?sort=
+//	}
+func SortedCursorPaginate[T any](
+	db *gorm.DB,
+	dest *[]T,
+	cursor string,
+	pageSize int,
+	fields []SortField,
+) (*pagination.CursorPagination[T], error) {
+	sortSchema, ok := SchemaFor[T]()
+	if !ok {
+		return nil, fmt.Errorf("no sort schema registered for model %T (call sorting.Register first)", *new(T))
+	}
+	if len(fields) == 0 {
+		fields = sortSchema.defaults
+	}
+
+	keys, err := resolveSortKeys[T](db, sortSchema, fields)
+	if err != nil {
+		return nil, err
+	}
+	spec := sortSpec(fields)
+
+	ctx := queryContext(db)
+
+	// Constrain page size
+	if limit := pagination.MaxPageSizeFor(ctx); pageSize > limit {
+		pageSize = limit
+	}
+	if pageSize < 1 {
+		pageSize = pagination.DefaultPaginationParams().PageSize
+	}
+
+	query, err := ApplySortWithSchema(db, sortSchema, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply the keyset predicate if a cursor is provided
+	if cursor != "" {
+		values, err := decodeSortCursor(cursor, spec, keys)
+		if err != nil {
+			return nil, err
+		}
+
+		predicate, args := keysetPredicate(keys, values)
+		query = query.Where(predicate, args...)
+	}
+
+	// Fetch one extra item to check for next page
+	var items []T
+	if err := query.Limit(pageSize + 1).Find(&items).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch items: %w", err)
+	}
+
+	hasNext := len(items) > pageSize
+	if hasNext {
+		items = items[:pageSize]
+	}
+
+	// Empty pages marshal as [] rather than null
+	if items == nil {
+		items = []T{}
+	}
+	*dest = items
+
+	result := &pagination.CursorPagination[T]{
+		Items:       items,
+		HasNext:     hasNext,
+		HasPrevious: cursor != "",
+		PageSize:    pageSize,
+	}
+	if len(items) == 0 {
+		return result, nil
+	}
+
+	first, err := keyValues(ctx, keys, items[0])
+	if err != nil {
+		return nil, err
+	}
+	last, err := keyValues(ctx, keys, items[len(items)-1])
+	if err != nil {
+		return nil, err
+	}
+	result.FirstKey = first
+	result.LastKey = last
+
+	if hasNext {
+		next, err := encodeSortCursor(spec, last)
+		if err != nil {
+			return nil, err
+		}
+		result.NextCursor = &next
+	}
+
+	return result, nil
+}
+
+// resolveSortKeys maps fields to their columns and T's fields, appending the schema's
+// tiebreaker exactly as ApplySortWithSchema orders by it
+func resolveSortKeys[T any](db *gorm.DB, s *SortSchema, fields []SortField) ([]sortKey, error) {
+	if err := s.Validate(fields); err != nil {
+		return nil, err
+	}
+
+	modelSchema, err := schema.Parse(new(T), &cursorSchemas, db.NamingStrategy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse model schema: %w", err)
+	}
+
+	lookup := func(column string) (*schema.Field, error) {
+		field := modelSchema.LookUpField(columnName(column))
+		if field == nil {
+			return nil, fmt.Errorf("sort column %q not found on %s", column, modelSchema.Name)
+		}
+		return field, nil
+	}
+
+	keys := make([]sortKey, 0, len(fields)+1)
+	lastDirection := Asc
+
+	for _, f := range fields {
+		column := s.columns[f.Name]
+		if len(column.Joins) > 0 {
+			return nil, &SortError{Field: f.Name, Reason: "cannot be combined with cursor pagination"}
+		}
+
+		field, err := lookup(column.Column)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, sortKey{column: column.Column, direction: f.Direction, field: field})
+		lastDirection = f.Direction
+	}
+
+	if len(keys) == 0 || !strings.EqualFold(keys[len(keys)-1].column, s.tiebreaker) {
+		field, err := lookup(s.tiebreaker)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, sortKey{column: s.tiebreaker, direction: lastDirection, field: field})
+	}
+
+	return keys, nil
+}
+
+// keysetPredicate selects the rows after values in the keys' order
+// Mixed directions rule out a row-value comparison, so the predicate is expanded:
+// (a > ?) OR (a = ? AND b < ?) OR (a = ? AND b = ? AND c > ?)
+func keysetPredicate(keys []sortKey, values []any) (string, []any) {
+	terms := make([]string, 0, len(keys))
+	var args []any
+
+	for i, key := range keys {
+		op := ">"
+		if key.direction == Desc {
+			op = "<"
+		}
+
+		conditions := make([]string, 0, i+1)
+		for _, prefix := range keys[:i] {
+			conditions = append(conditions, prefix.column+" = ?")
+		}
+		conditions = append(conditions, fmt.Sprintf("%s %s ?", key.column, op))
+		args = append(args, values[:i+1]...)
+
+		terms = append(terms, "("+strings.Join(conditions, " AND ")+")")
+	}
+
+	return strings.Join(terms, " OR "), args
+}
+
+// keyValues reads every sort key's value from item
+func keyValues(ctx context.Context, keys []sortKey, item any) ([]any, error) {
+	values := make([]any, len(keys))
+	for i, key := range keys {
+		raw, _ := key.field.ValueOf(ctx, reflect.Indirect(reflect.ValueOf(item)))
+
+		rv := reflect.ValueOf(raw)
+		if rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				return nil, fmt.Errorf("sort column %s is NULL; cursor sorts need non-null columns", key.column)
+			}
+			raw = rv.Elem().Interface()
+		}
+		values[i] = raw
+	}
+	return values, nil
+}
+
+// encodeSortCursor builds the base64url JSON cursor for spec and a row's key values
+func encodeSortCursor(spec string, values []any) (string, error) {
+	payload := sortCursor{Sort: spec, Values: make([]json.RawMessage, len(values))}
+	for i, value := range values {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode cursor: %w", err)
+		}
+		payload.Values[i] = raw
+	}
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// decodeSortCursor checks cursor against the current sort and decodes its values into the
+// keys' Go types, so times and large integers compare exactly as they were read
+func decodeSortCursor(cursor string, spec string, keys []sortKey) ([]any, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", pagination.ErrInvalidCursor, err)
+	}
+
+	var payload sortCursor
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", pagination.ErrInvalidCursor, err)
+	}
+
+	if payload.Sort != spec {
+		return nil, fmt.Errorf("%w: cursor is for sort %q, request sorts by %q", ErrCursorSortMismatch, payload.Sort, spec)
+	}
+	if len(payload.Values) != len(keys) {
+		return nil, fmt.Errorf("%w: cursor has %d sort values, sort has %d keys", ErrCursorSortMismatch, len(payload.Values), len(keys))
+	}
+
+	values := make([]any, len(keys))
+	for i, key := range keys {
+		t := key.field.FieldType
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		value := reflect.New(t)
+		if err := json.Unmarshal(payload.Values[i], value.Interface()); err != nil {
+			return nil, fmt.Errorf("%w: %s value: %v", pagination.ErrInvalidCursor, key.column, err)
+		}
+		values[i] = value.Elem().Interface()
+	}
+	return values, nil
+}
+
+// sortSpec renders fields in query-string form ("-created_at,name"), the sort a cursor is bound to
+func sortSpec(fields []SortField) string {
+	parts := make([]string, len(fields))
+	for i, f := range fields {
+		parts[i] = f.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// columnName strips the table qualifier and identifier quotes from a column
+func columnName(column string) string {
+	name := column
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.Trim(name, "`\"")
+}
+
+// queryContext returns the query's context (set with db.WithContext), or context.Background()
+func queryContext(db *gorm.DB) context.Context {
+	if db.Statement != nil && db.Statement.Context != nil {
+		return db.Statement.Context
+	}
+	return context.Background()
+}
@@ -0,0 +1,108 @@
+package sorting
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+
+	"{{packageImportPath}}/pagination"
+)
+
+type article struct {
+	ID          uint
+	Title       string
+	PublishedAt time.Time
+}
+
+func articleSchema() *SortSchema {
+	return NewSortSchema("articles.id").
+		Allow("title", "articles.title").
+		Allow("published_at", "articles.published_at").
+		Allow("author.name", "authors.name", "JOIN authors ON authors.id = articles.author_id")
+}
+
+func testDB() *gorm.DB {
+	return &gorm.DB{Config: &gorm.Config{NamingStrategy: schema.NamingStrategy{}}}
+}
+
+func TestKeysetPredicateMixedDirections(t *testing.T) {
+	fields, err := ParseSort("-published_at,title")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := resolveSortKeys[article](testDB(), articleSchema(), fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	published := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	predicate, args := keysetPredicate(keys, []any{published, "Go", uint(7)})
+
+	// The tiebreaker follows the last field's direction, like ApplySort's ORDER BY
+	want := "(articles.published_at < ?) OR " +
+		"(articles.published_at = ? AND articles.title > ?) OR " +
+		"(articles.published_at = ? AND articles.title = ? AND articles.id > ?)"
+	if predicate != want {
+		t.Errorf("predicate = %q, want %q", predicate, want)
+	}
+
+	wantArgs := []any{published, published, "Go", published, "Go", uint(7)}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("args = %v, want %v", args, wantArgs)
+	}
+}
+
+func TestSortCursorRoundTrip(t *testing.T) {
+	newest := SortField{Name: "published_at", Direction: Desc}
+	fields := []SortField{newest}
+	keys, err := resolveSortKeys[article](testDB(), articleSchema(), fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	row := article{ID: 42, PublishedAt: time.Date(2024, 5, 1, 12, 0, 0, 123, time.UTC)}
+	values, err := keyValues(context.Background(), keys, &row)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cursor, err := encodeSortCursor(sortSpec(fields), values)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := decodeSortCursor(cursor, "-published_at", keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Values come back as the fields' Go types, not JSON strings and floats
+	if got := decoded[0].(time.Time); !got.Equal(row.PublishedAt) {
+		t.Errorf("published_at = %v, want %v", got, row.PublishedAt)
+	}
+	if got := decoded[1].(uint); got != 42 {
+		t.Errorf("id = %v, want 42", got)
+	}
+
+	_, err = decodeSortCursor(cursor, "published_at", keys)
+	if !errors.Is(err, ErrCursorSortMismatch) || !errors.Is(err, pagination.ErrCursorFieldMismatch) {
+		t.Errorf("changed direction: err = %v, want ErrCursorSortMismatch", err)
+	}
+
+	if _, err := decodeSortCursor("not a cursor!", "-published_at", keys); !errors.Is(err, pagination.ErrInvalidCursor) {
+		t.Errorf("garbage cursor: err = %v, want ErrInvalidCursor", err)
+	}
+}
+
+func TestSortedCursorRejectsJoinedColumns(t *testing.T) {
+	byAuthor := SortField{Name: "author.name", Direction: Asc}
+	_, err := resolveSortKeys[article](testDB(), articleSchema(), []SortField{byAuthor})
+
+	var sortErr *SortError
+	if !errors.As(err, &sortErr) || sortErr.Field != "author.name" {
+		t.Errorf("err = %v, want a SortError for author.name", err)
+	}
+}
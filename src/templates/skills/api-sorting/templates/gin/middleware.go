@@ -0,0 +1,94 @@
+package sorting
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+
+	"{{packageImportPath}}/pagination"
+)
+
+// ListParams combines the pagination and sort parameters of a list request
+type ListParams struct {
+	pagination.PaginationParams
+	Sort []SortField
+}
+
+// ParseSortParams returns middleware that parses ?sort= and validates it against M's schema
+// Invalid sorts are rejected with 400 before the handler runs; valid ones are stored on the context.
+// Use it after pagination.ParsePaginationParams so GetListParams sees both.
+//
+// Example usage:
+//
+//	import (
+//	    "{{packageImportPath}}/pagination"
+//	    "{{packageImportPath}}/sorting"
+//	)
+//
+//	r.GET("/users",
+//	    pagination.ParsePaginationParams,
+//	    sorting.ParseSortParams[User](),
+//	    ListUsers,
+//	)
+func ParseSortParams[M any]() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		fields, err := ParseSort(c.Query("{{sortParam}}"))
+		if err == nil {
+			if schema, ok := SchemaFor[M](); ok {
+				err = schema.Validate(fields)
+			}
+		}
+		if err != nil {
+			AbortWithSortError(c, err)
+			return
+		}
+
+		// Store in context for handler use
+		c.Set("sort_fields", fields)
+
+		c.Next()
+	}
+}
+
+// GetSortFields retrieves the parsed sort fields from Gin context
+// Returns nil (schema default order) if not set
+func GetSortFields(c *gin.Context) []SortField {
+	if fields, exists := c.Get("sort_fields"); exists {
+		if f, ok := fields.([]SortField); ok {
+			return f
+		}
+	}
+	return nil
+}
+
+// GetListParams retrieves pagination and sort params together
+func GetListParams(c *gin.Context) ListParams {
+	return ListParams{
+		PaginationParams: pagination.GetPaginationParams(c),
+		Sort:             GetSortFields(c),
+	}
+}
+
+// AbortWithSortError responds 400 for sort validation and cursor errors and 500 for anything else
+func AbortWithSortError(c *gin.Context, err error) {
+	if errors.Is(err, pagination.ErrInvalidCursor) || errors.Is(err, pagination.ErrCursorFieldMismatch) {
+		c.AbortWithStatusJSON(400, gin.H{
+			"error": err.Error(),
+			"param": "cursor",
+		})
+		return
+	}
+
+	var sortErr *SortError
+	if errors.As(err, &sortErr) {
+		c.AbortWithStatusJSON(400, gin.H{
+			"error":  sortErr.Error(),
+			"param":  "{{sortParam}}",
+			"field":  sortErr.Field,
+			"reason": sortErr.Reason,
+		})
+		return
+	}
+
+	c.AbortWithStatusJSON(500, gin.H{"error": err.Error()})
+}
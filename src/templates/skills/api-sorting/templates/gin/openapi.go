@@ -0,0 +1,64 @@
+package sorting
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// OpenAPIParameter is an OpenAPI 3 query parameter object
+type OpenAPIParameter struct {
+	Name        string        `json:"name"`
+	In          string        `json:"in"`
+	Description string        `json:"description,omitempty"`
+	Required    bool          `json:"required"`
+	Schema      OpenAPISchema `json:"schema"`
+}
+
+// OpenAPISchema is the subset of an OpenAPI 3 schema object used by the sort parameter
+type OpenAPISchema struct {
+	Type    string `json:"type"`
+	Pattern string `json:"pattern,omitempty"`
+	Example string `json:"example,omitempty"`
+}
+
+// OpenAPIParameter documents the sort query parameter of the schema's list endpoint,
+// including the allowed names and MaxSortFields
+//
+// Example usage:
+//
+//	schema, _ := sorting.SchemaFor[User]()
+//	param := schema.OpenAPIParameter() // add to the list operation's parameters
+func (s *SortSchema) OpenAPIParameter() OpenAPIParameter {
+	names := s.Names()
+
+	description := fmt.Sprintf(
+		"Comma-separated fields to sort by, at most %d; prefix a field with - for descending order. Allowed: %s",
+		MaxSortFields, strings.Join(names, ", "),
+	)
+	if len(s.defaults) > 0 {
+		defaults := make([]string, len(s.defaults))
+		for i, field := range s.defaults {
+			defaults[i] = field.String()
+		}
+		description += fmt.Sprintf(". Default: %s", strings.Join(defaults, ","))
+	}
+
+	schema := OpenAPISchema{Type: "string"}
+	if len(names) > 0 {
+		quoted := make([]string, len(names))
+		for i, name := range names {
+			quoted[i] = regexp.QuoteMeta(name)
+		}
+		field := "[-+]?(" + strings.Join(quoted, "|") + ")"
+		schema.Pattern = fmt.Sprintf("^%s(,%s){0,%d}$", field, field, MaxSortFields-1)
+		schema.Example = "-" + names[0]
+	}
+
+	return OpenAPIParameter{
+		Name:        "{{sortParam}}",
+		In:          "query",
+		Description: description,
+		Schema:      schema,
+	}
+}
@@ -0,0 +1,227 @@
+package sorting
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// SortableColumn maps a public sort name to the SQL expression it orders by
+// Joins lists the JOIN clauses the column needs (e.g. sorting users by "company.name")
+type SortableColumn struct {
+	Column string
+	Joins  []string
+}
+
+// SortSchema is the sort allowlist for one model
+// Only registered names can be sorted on, so clients never reach raw column names
+type SortSchema struct {
+	columns    map[string]SortableColumn
+	tiebreaker string
+	defaults   []SortField
+}
+
+// NewSortSchema creates a schema whose results are always made deterministic by tiebreaker
+// tiebreaker must be a unique, non-null column (typically the primary key, e.g. "users.id")
+func NewSortSchema(tiebreaker string) *SortSchema {
+	return &SortSchema{
+		columns:    make(map[string]SortableColumn),
+		tiebreaker: tiebreaker,
+	}
+}
+
+// Allow registers a sortable API name, the column it maps to, and any joins it requires
+func (s *SortSchema) Allow(name string, column string, joins ...string) *SortSchema {
+	s.columns[name] = SortableColumn{Column: column, Joins: joins}
+	return s
+}
+
+// Default sets the order used when the request has no sort parameter
+func (s *SortSchema) Default(fields ...SortField) *SortSchema {
+	s.defaults = fields
+	return s
+}
+
+// Names returns the allowed sort names in alphabetical order (handy for error messages and docs)
+func (s *SortSchema) Names() []string {
+	names := make([]string, 0, len(s.columns))
+	for name := range s.columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate checks every field against the allowlist
+func (s *SortSchema) Validate(fields []SortField) error {
+	for _, field := range fields {
+		if _, ok := s.columns[field.Name]; !ok {
+			return &SortError{
+				Field:  field.Name,
+				Reason: fmt.Sprintf("not sortable (allowed: %s)", strings.Join(s.Names(), ", ")),
+			}
+		}
+	}
+	return nil
+}
+
+// FromStruct builds a schema from `sort` struct tags on M
+// A tagged field is sortable under its json name, ordering by its gorm column (or the
+// snake_cased field name); `sort:"default"` or `sort:"-default"` also makes it the default
+// order, ascending or descending. The tiebreaker is the primary key: the field tagged
+// gorm:"primaryKey", or ID.
+//
+// Example usage:
+//
+//	type Product struct {
+//	    ID        uint      `json:"id" sort:""`
+//	    Name      string    `json:"name" sort:""`
+//	    Price     float64   `json:"price" sort:""`
+//	    CreatedAt time.Time `json:"created_at" sort:"-default"`
+//	}
+//
+//	func init() {
+//	    sorting.Register[Product](sorting.FromStruct[Product]())
+//	}
+func FromStruct[M any]() *SortSchema {
+	t := reflect.TypeOf(new(M)).Elem()
+	schema := NewSortSchema(primaryKeyColumn(t))
+	collectSortableFields(schema, t)
+	return schema
+}
+
+// collectSortableFields walks struct fields (including embedded structs) for sort tags
+func collectSortableFields(schema *SortSchema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			collectSortableFields(schema, sf.Type)
+			continue
+		}
+
+		tag, ok := sf.Tag.Lookup("sort")
+		if !ok || tag == "-" {
+			continue
+		}
+
+		name := apiName(sf)
+		schema.Allow(name, fieldColumn(sf))
+		switch strings.TrimSpace(tag) {
+		case "default":
+			schema.defaults = append(schema.defaults, SortField{Name: name, Direction: Asc})
+		case "-default":
+			schema.defaults = append(schema.defaults, SortField{Name: name, Direction: Desc})
+		}
+	}
+}
+
+// primaryKeyColumn returns the column of the field tagged gorm:"primaryKey", or of ID
+func primaryKeyColumn(t reflect.Type) string {
+	column := "id"
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			if embedded := primaryKeyColumn(sf.Type); embedded != "id" {
+				column = embedded
+			}
+			continue
+		}
+		for _, setting := range strings.Split(sf.Tag.Get("gorm"), ";") {
+			if strings.EqualFold(strings.TrimSpace(setting), "primaryKey") {
+				return fieldColumn(sf)
+			}
+		}
+		if sf.Name == "ID" {
+			column = fieldColumn(sf)
+		}
+	}
+	return column
+}
+
+// apiName returns the json name of a struct field, falling back to snake_case
+func apiName(sf reflect.StructField) string {
+	if name := strings.Split(sf.Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+		return name
+	}
+	return snakeCase(sf.Name)
+}
+
+// fieldColumn returns the gorm column of a struct field, falling back to snake_case
+func fieldColumn(sf reflect.StructField) string {
+	for _, setting := range strings.Split(sf.Tag.Get("gorm"), ";") {
+		if strings.HasPrefix(strings.TrimSpace(setting), "column:") {
+			return strings.TrimPrefix(strings.TrimSpace(setting), "column:")
+		}
+	}
+	return snakeCase(sf.Name)
+}
+
+// snakeCase converts a Go field name to snake_case ("CategoryID" => "category_id")
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			prevLower := i > 0 && unicode.IsLower(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if i > 0 && (prevLower || (nextLower && unicode.IsUpper(runes[i-1]))) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// registry holds one SortSchema per model type
+var registry sync.Map
+
+// Register sets the sort allowlist for model M
+// Call once at startup, next to route registration
+//
+// Example usage:
+//
+//	func init() {
+//	    sorting.Register[User](
+//	        sorting.NewSortSchema("users.id").
+//	            Allow("created_at", "users.created_at").
+//	            Allow("name", "users.name").
+//	            Allow("company.name", "companies.name", "LEFT JOIN companies ON companies.id = users.company_id").
+//	            Default(sorting.SortField{Name: "created_at", Direction: sorting.Desc}),
+//	    )
+//	}
+func Register[M any](schema *SortSchema) {
+	registry.Store(modelType(new(M)), schema)
+}
+
+// SchemaFor returns the sort schema registered for model M
+func SchemaFor[M any]() (*SortSchema, bool) {
+	return lookupSchema(new(M))
+}
+
+// lookupSchema finds the schema for a model value (struct, pointer, or slice of either)
+func lookupSchema(model interface{}) (*SortSchema, bool) {
+	if model == nil {
+		return nil, false
+	}
+
+	schema, ok := registry.Load(modelType(model))
+	if !ok {
+		return nil, false
+	}
+	return schema.(*SortSchema), true
+}
+
+// modelType unwraps pointers and slices down to the model's struct type
+func modelType(model interface{}) reflect.Type {
+	t := reflect.TypeOf(model)
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,109 @@
+package sorting
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// SortDirection is the SQL direction of a sort key
+type SortDirection string
+
+const (
+	Asc  SortDirection = "ASC"
+	Desc SortDirection = "DESC"
+)
+
+// SortField is a single parsed sort key
+// Name is the public API name; the registry maps it to a column
+type SortField struct {
+	Name      string        `json:"name"`
+	Direction SortDirection `json:"direction"`
+}
+
+// String renders the field back in query-string form ("-created_at")
+func (f SortField) String() string {
+	if f.Direction == Desc {
+		return "-" + f.Name
+	}
+	return f.Name
+}
+
+// ErrInvalidSort is the sentinel wrapped by every sort validation error
+var ErrInvalidSort = errors.New("invalid sort")
+
+// SortError describes why a sort parameter was rejected
+// It matches errors.Is(err, ErrInvalidSort) and is safe to show to API clients
+type SortError struct {
+	Field  string `json:"field,omitempty"`
+	Reason string `json:"reason"`
+}
+
+func (e *SortError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("%s: %s", ErrInvalidSort, e.Reason)
+	}
+	return fmt.Sprintf("%s: %s: %s", ErrInvalidSort, e.Field, e.Reason)
+}
+
+func (e *SortError) Unwrap() error {
+	return ErrInvalidSort
+}
+
+// MaxSortFields caps how many keys a single request may sort by
+const MaxSortFields = {{maxSortFields}}
+
+// sortNamePattern restricts API names so they can never smuggle SQL
+var sortNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// ParseSort parses a sort query value such as "-created_at,name"
+// A leading "-" sorts descending, a leading "+" (or none) ascending.
+// Names are only syntax-checked here; use a registered SortSchema to allowlist them.
+//
+// Example usage:
+//
+//	fields, err := sorting.ParseSort(c.Query("sort"))
+//	// "-created_at,name" => [{created_at DESC} {name ASC}]
+func ParseSort(raw string) ([]SortField, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(raw, ",")
+	if len(parts) > MaxSortFields {
+		return nil, &SortError{Reason: fmt.Sprintf("at most %d sort fields are allowed", MaxSortFields)}
+	}
+
+	fields := make([]SortField, 0, len(parts))
+	seen := make(map[string]bool, len(parts))
+
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+
+		direction := Asc
+		switch {
+		case strings.HasPrefix(part, "-"):
+			direction = Desc
+			part = part[1:]
+		case strings.HasPrefix(part, "+"):
+			part = part[1:]
+		}
+
+		if part == "" {
+			return nil, &SortError{Reason: "empty sort field"}
+		}
+		if !sortNamePattern.MatchString(part) {
+			return nil, &SortError{Field: part, Reason: "malformed sort field"}
+		}
+		if seen[part] {
+			return nil, &SortError{Field: part, Reason: "sort field listed more than once"}
+		}
+		seen[part] = true
+
+		fields = append(fields, SortField{Name: part, Direction: direction})
+	}
+
+	return fields, nil
+}
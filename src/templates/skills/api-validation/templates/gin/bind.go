@@ -0,0 +1,135 @@
+package validation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// MaxBodyBytes caps the JSON body read by BindJSON
+const MaxBodyBytes = 1 << 20
+
+// SearchRequest holds the list parameters of a POST search body
+// Embed it in an endpoint's request type so pagination, sort, and cursor are validated with
+// the same limits as the query parser, and add the endpoint's own (nested) criteria:
+//
+//	type UserSearch struct {
+//	    validation.SearchRequest
+//	    Filters []UserFilter `json:"filters" {{tagName}}:"max=10,dive"`
+//	}
+//
+//	type UserFilter struct {
+//	    Field string `json:"field" {{tagName}}:"required,enum=status|role|created_at"`
+//	    Op    string `json:"op"    {{tagName}}:"required,enum=eq|ne|gt|lt|in"`
+//	    Value any    `json:"value" {{tagName}}:"required"`
+//	}
+type SearchRequest struct {
+	Page     int    `json:"page"      {{tagName}}:"omitempty,min=1"`
+	PageSize int    `json:"page_size" {{tagName}}:"omitempty,min=1,max={{maxPageSize}}"`
+	Cursor   string `json:"cursor"    {{tagName}}:"omitempty,cursor"`
+	Sort     string `json:"sort"      {{tagName}}:"omitempty,sort_expr"`
+}
+
+// BindJSON decodes the request body into dest and validates it
+// Unknown fields are rejected so typos ("pagesize") fail loudly instead of being ignored, and
+// the body is restored for later middleware. On failure it aborts with the structured 400
+// body and returns false.
+//
+// Example usage:
+//
+//	func SearchUsers(c *gin.Context) {
+//	    var req UserSearch
+//	    if !validation.BindJSON(c, &req) {
+//	        return
+//	    }
+//	    // req.Page, req.PageSize, req.Filters[i] are all valid here
+//	}
+func BindJSON(c *gin.Context, dest any) bool {
+	raw, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, MaxBodyBytes))
+	if err != nil {
+		c.AbortWithStatusJSON(400, NewError(FieldError{In: InBody, Rule: "size", Message: "request body is too large or unreadable"}))
+		return false
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(raw))
+
+	if err := Default.DecodeJSON(raw, dest); err != nil {
+		Abort(c, err, InBody)
+		return false
+	}
+	return true
+}
+
+// BindQuery binds query parameters into dest (with form tags) and validates it
+// Binding does not go through gin's validator, so the custom rules work without Install.
+// On failure it aborts with the structured 400 body and returns false.
+func BindQuery(c *gin.Context, dest any) bool {
+	if err := binding.MapFormWithTag(dest, c.Request.URL.Query(), "form"); err != nil {
+		c.AbortWithStatusJSON(400, NewError(FieldError{In: InQuery, Rule: "type", Message: err.Error()}))
+		return false
+	}
+	if err := Default.Struct(dest); err != nil {
+		Abort(c, err, InQuery)
+		return false
+	}
+	return true
+}
+
+// DecodeJSON strictly decodes raw into dest and validates the result, including nested
+// structs and dive-tagged slices and maps
+// An empty body is validated as the zero value, so required fields still fail.
+func (v *Validator) DecodeJSON(raw []byte, dest any) error {
+	if len(bytes.TrimSpace(raw)) > 0 {
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(dest); err != nil {
+			return err
+		}
+		if decoder.More() {
+			return NewError(FieldError{In: InBody, Rule: "json", Message: "body must contain a single JSON value"})
+		}
+	}
+
+	if err := v.ValidateStruct(dest); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Prefix nests the fields of a validation error under path
+// Use it when validating a sub-document separately, for example one element of a batch:
+//
+//	for i, item := range req.Items {
+//	    if err := validation.Default.Struct(item); err != nil {
+//	        return validation.Prefix(fmt.Sprintf("items[%d]", i), err)
+//	    }
+//	}
+func Prefix(path string, err error) error {
+	validationErr := Translate(err, InBody)
+	if validationErr == nil {
+		return err
+	}
+
+	fields := make([]FieldError, len(validationErr.Fields))
+	for i, f := range validationErr.Fields {
+		f.Field = joinPath(path, f.Field)
+		fields[i] = f
+	}
+	return NewError(fields...)
+}
+
+// joinPath appends field to path, keeping index segments attached ("items" + "[0]")
+func joinPath(path, field string) string {
+	switch {
+	case field == "":
+		return path
+	case field[0] == '[':
+		return path + field
+	default:
+		return fmt.Sprintf("%s.%s", path, field)
+	}
+}
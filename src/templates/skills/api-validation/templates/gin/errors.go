@@ -0,0 +1,196 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// Locations of an invalid field
+const (
+	InQuery = "query"
+	InBody  = "body"
+)
+
+// FieldError describes one invalid field in a structured, client-facing form
+type FieldError struct {
+	Field   string `json:"field"`           // JSON path, e.g. "page_size" or "filters[0].value"
+	In      string `json:"in"`              // InQuery or InBody
+	Rule    string `json:"rule"`            // Failed rule, e.g. "max", "enum", "cursor"
+	Param   string `json:"param,omitempty"` // Rule parameter, e.g. the maximum
+	Message string `json:"message"`
+}
+
+// Error is the 400 response body shared by every validated endpoint, including strict-mode
+// pagination, so clients parse a single error shape
+//
+//	{
+//	  "error":  "invalid request",
+//	  "code":   "validation_failed",
+//	  "fields": [{"field": "page_size", "in": "query", "rule": "max", "param": "100", "message": "must be at most 100"}]
+//	}
+type Error struct {
+	Message string       `json:"error"`
+	Code    string       `json:"code"`
+	Fields  []FieldError `json:"fields"`
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	parts := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		parts[i] = f.Field + ": " + f.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Message, strings.Join(parts, "; "))
+}
+
+// StatusCode is the HTTP status of a validation failure
+// It (with ProblemExtensions) lets the api-error-handling skill render an Error as a problem+json
+// response with the fields as an extension member.
+func (e *Error) StatusCode() int {
+	return 400
+}
+
+// ProblemExtensions returns the code and fields as problem+json extension members
+func (e *Error) ProblemExtensions() map[string]any {
+	return map[string]any{"code": e.Code, "fields": e.Fields}
+}
+
+// NewError builds an Error from field errors
+func NewError(fields ...FieldError) *Error {
+	return &Error{Message: "invalid request", Code: "validation_failed", Fields: fields}
+}
+
+// Translate converts a validation or decoding error into an *Error
+// Validator errors become one FieldError per failed rule, JSON syntax and type errors a single
+// body error; anything else (such as an *InvalidValidationError from a programming mistake)
+// returns nil and should be treated as a 500.
+func Translate(err error, in string) *Error {
+	var validationErr *Error
+	if errors.As(err, &validationErr) {
+		return validationErr
+	}
+
+	var fieldErrs validator.ValidationErrors
+	if errors.As(err, &fieldErrs) {
+		fields := make([]FieldError, len(fieldErrs))
+		for i, fe := range fieldErrs {
+			fields[i] = FieldError{
+				Field:   fieldPath(fe.Namespace()),
+				In:      in,
+				Rule:    fe.Tag(),
+				Param:   fe.Param(),
+				Message: message(fe.Tag(), fe.Param()),
+			}
+		}
+		return NewError(fields...)
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return NewError(FieldError{In: InBody, Rule: "json", Message: "malformed JSON: " + syntaxErr.Error()})
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return NewError(FieldError{
+			Field:   typeErr.Field,
+			In:      InBody,
+			Rule:    "type",
+			Param:   typeErr.Type.String(),
+			Message: "must be " + jsonKind(typeErr.Type.Kind().String()),
+		})
+	}
+	if field, ok := unknownField(err); ok {
+		return NewError(FieldError{Field: field, In: InBody, Rule: "unknown", Message: "is not a recognized field"})
+	}
+	return nil
+}
+
+// Abort responds 400 with the structured body for validation errors and 500 for anything else
+func Abort(c *gin.Context, err error, in string) {
+	if validationErr := Translate(err, in); validationErr != nil {
+		c.AbortWithStatusJSON(400, validationErr)
+		return
+	}
+
+	c.AbortWithStatusJSON(500, gin.H{"error": err.Error()})
+}
+
+// fieldPath drops the root struct name from a validator namespace
+// "SearchRequest.filters[0].value" becomes "filters[0].value"
+func fieldPath(namespace string) string {
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return namespace
+}
+
+// unknownField extracts the name from encoding/json's DisallowUnknownFields error
+func unknownField(err error) (string, bool) {
+	const prefix = `json: unknown field "`
+	text := err.Error()
+	if !strings.HasPrefix(text, prefix) {
+		return "", false
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(text, prefix), `"`), true
+}
+
+// jsonKind names a Go kind the way API clients see it
+func jsonKind(kind string) string {
+	switch {
+	case strings.HasPrefix(kind, "int"), strings.HasPrefix(kind, "uint"), strings.HasPrefix(kind, "float"):
+		return "a number"
+	case kind == "bool":
+		return "a boolean"
+	case kind == "slice", kind == "array":
+		return "an array"
+	case kind == "map", kind == "struct":
+		return "an object"
+	default:
+		return "a " + kind
+	}
+}
+
+// message renders a human-readable message for a failed rule
+func message(rule, param string) string {
+	switch rule {
+	case "required":
+		return "is required"
+	case "min", "gte":
+		return "must be at least " + param
+	case "max", "lte":
+		return "must be at most " + param
+	case "gt":
+		return "must be greater than " + param
+	case "lt":
+		return "must be less than " + param
+	case "len":
+		return "must have length " + param
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(param), ", ")
+	case "enum":
+		return "must be one of: " + strings.Join(strings.Split(param, "|"), ", ")
+	case "sort_expr":
+		if param != "" {
+			return "must be a comma-separated list of sort fields (" + strings.Join(strings.Split(param, "|"), ", ") + "), each optionally prefixed with -"
+		}
+		return "must be a comma-separated list of sort fields, each optionally prefixed with -"
+	case "cursor":
+		return "is not a valid cursor; pass the next_cursor value from a previous response unchanged"
+	case "email":
+		return "must be an email address"
+	case "uuid", "uuid4":
+		return "must be a UUID"
+	case "datetime":
+		return "must be a date/time in the layout " + param
+	default:
+		if param != "" {
+			return fmt.Sprintf("failed the %s=%s rule", rule, param)
+		}
+		return "failed the " + rule + " rule"
+	}
+}
@@ -0,0 +1,91 @@
+package validation
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// MaxCursorLength is the longest cursor accepted by the cursor rule
+// Real cursors are a few dozen bytes; the cap rejects garbage before it reaches a decoder
+const MaxCursorLength = {{maxCursorLength}}
+
+// MaxSortFields is the most keys accepted by the sort_expr rule (match api-sorting's limit)
+const MaxSortFields = {{maxSortFields}}
+
+// cursorPattern matches every cursor the pagination skill produces: standard and URL-safe
+// base64 (with or without padding), plus the "~" separator of snapshot-pinned cursors
+var cursorPattern = regexp.MustCompile(`^[A-Za-z0-9+/_=~-]+$`)
+
+// sortFieldPattern matches one sort key, the same names api-sorting accepts
+var sortFieldPattern = regexp.MustCompile(`^-?[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// ValidEnum reports whether value is one of the |-separated allowed values
+func ValidEnum(value, allowed string) bool {
+	for _, option := range strings.Split(allowed, "|") {
+		if value == option {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidSortExpression reports whether expr is a well-formed sort expression such as
+// "-created_at,name": at most MaxSortFields comma-separated keys, each optionally
+// prefixed with "-", without duplicates, and limited to the |-separated allowed names when
+// allowed is not empty
+// It checks syntax only; api-sorting's schema remains the authority on sortable columns.
+func ValidSortExpression(expr, allowed string) bool {
+	keys := strings.Split(expr, ",")
+	if len(keys) > MaxSortFields {
+		return false
+	}
+
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		key = strings.TrimSpace(key)
+		if !sortFieldPattern.MatchString(key) {
+			return false
+		}
+		name := strings.TrimPrefix(key, "-")
+		if seen[name] || (allowed != "" && !ValidEnum(name, allowed)) {
+			return false
+		}
+		seen[name] = true
+	}
+	return true
+}
+
+// ValidCursorFormat reports whether cursor looks like a pagination cursor
+// It does not decode the cursor; a well-formed but stale cursor is still rejected by the
+// paginator.
+func ValidCursorFormat(cursor string) bool {
+	return len(cursor) <= MaxCursorLength && cursorPattern.MatchString(cursor)
+}
+
+// registerRules adds the pagination-related rules to v
+//
+//	Status string `json:"status" {{tagName}}:"omitempty,enum=active|archived"`
+//	Sort   string `json:"sort"   {{tagName}}:"omitempty,sort_expr=name|created_at"`
+//	Cursor string `json:"cursor" {{tagName}}:"omitempty,cursor"`
+func registerRules(v *validator.Validate) error {
+	rules := map[string]validator.Func{
+		"enum": func(fl validator.FieldLevel) bool {
+			return ValidEnum(fl.Field().String(), fl.Param())
+		},
+		"sort_expr": func(fl validator.FieldLevel) bool {
+			return ValidSortExpression(fl.Field().String(), fl.Param())
+		},
+		"cursor": func(fl validator.FieldLevel) bool {
+			return ValidCursorFormat(fl.Field().String())
+		},
+	}
+
+	for tag, fn := range rules {
+		if err := v.RegisterValidation(tag, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
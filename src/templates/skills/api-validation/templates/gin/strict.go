@@ -0,0 +1,97 @@
+package validation
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"{{packageImportPath}}/pagination"
+)
+
+// StrictPaginationParams is pagination.ParsePaginationParams in strict mode
+// Instead of silently falling back to defaults or clamping, it rejects malformed values with
+// the same structured 400 body as every other validated endpoint:
+//
+//   - page and page_size (or limit) must be positive integers (page may be 0 under
+//     StrictPaginationParamsWithIndexing(pagination.ZeroBased))
+//   - page_size above the request's limit (pagination.MaxPageSizeFor) is an error instead of
+//     being clamped
+//   - page_size and limit must agree when both are sent
+//   - cursor must look like a pagination cursor, and cannot be combined with page
+//
+// Valid requests store the same PaginationParams as the lenient middleware, so handlers read
+// them with pagination.GetPaginationParams.
+//
+// Example usage:
+//
+//	r.GET("/users", validation.StrictPaginationParams, GetUsers)
+func StrictPaginationParams(c *gin.Context) {
+	strictPaginationParams(c, pagination.OneBased)
+}
+
+// StrictPaginationParamsWithIndexing is StrictPaginationParams for routes that number pages
+// with indexing; under pagination.ZeroBased, page=0 is valid and negative pages are rejected
+//
+// Example usage:
+//
+//	r.GET("/legacy/orders", validation.StrictPaginationParamsWithIndexing(pagination.ZeroBased), ListLegacyOrders)
+func StrictPaginationParamsWithIndexing(indexing pagination.PageIndexing) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		strictPaginationParams(c, indexing)
+	}
+}
+
+// strictPaginationParams validates and stores a request's pagination params
+func strictPaginationParams(c *gin.Context, indexing pagination.PageIndexing) {
+	query := c.Request.URL.Query()
+	maxPageSize := pagination.MaxPageSizeFor(c.Request.Context())
+	var fields []FieldError
+
+	positive := func(name string) {
+		value, ok := query[name]
+		if !ok || len(value) == 0 {
+			return
+		}
+		minimum := 1
+		if name == "page" {
+			minimum = indexing.FirstPage()
+		}
+		n, err := strconv.Atoi(value[0])
+		switch {
+		case err != nil:
+			fields = append(fields, FieldError{Field: name, In: InQuery, Rule: "type", Message: "must be an integer"})
+		case n < minimum:
+			minText := strconv.Itoa(minimum)
+			fields = append(fields, FieldError{Field: name, In: InQuery, Rule: "min", Param: minText, Message: message("min", minText)})
+		case name != "page" && n > maxPageSize:
+			maxText := strconv.Itoa(maxPageSize)
+			fields = append(fields, FieldError{Field: name, In: InQuery, Rule: "max", Param: maxText, Message: message("max", maxText)})
+		}
+	}
+	positive("page")
+	positive("page_size")
+	positive("limit")
+
+	if pageSize, limit := query.Get("page_size"), query.Get("limit"); pageSize != "" && limit != "" && pageSize != limit {
+		fields = append(fields, FieldError{Field: "limit", In: InQuery, Rule: "conflict", Param: "page_size", Message: "conflicts with page_size; send only one"})
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		if !ValidCursorFormat(cursor) {
+			fields = append(fields, FieldError{Field: "cursor", In: InQuery, Rule: "cursor", Message: message("cursor", "")})
+		}
+		if query.Get("page") != "" {
+			fields = append(fields, FieldError{Field: "page", In: InQuery, Rule: "conflict", Param: "cursor", Message: "cannot be combined with cursor"})
+		}
+	}
+
+	if len(fields) > 0 {
+		c.AbortWithStatusJSON(400, NewError(fields...))
+		return
+	}
+
+	// Store in context for handler use
+	c.Set("pagination_params", pagination.ParamsFromRequestWithIndexing(c.Request, indexing))
+
+	c.Next()
+}
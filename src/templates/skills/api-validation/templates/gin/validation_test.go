@@ -0,0 +1,85 @@
+package validation
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestValidSortExpression(t *testing.T) {
+	cases := []struct {
+		expr, allowed string
+		want          bool
+	}{
+		{"name", "", true},
+		{"-created_at,name", "", true},
+		{"-created_at,name", "name|created_at", true},
+		{"author.name", "", true},
+		{"-created_at,email", "name|created_at", false},
+		{"name,-name", "", false},
+		{"", "", false},
+		{"name,", "", false},
+		{"--name", "", false},
+		{"name;drop", "", false},
+		{"a,b,c,d", "", false},
+	}
+
+	for _, tc := range cases {
+		if got := ValidSortExpression(tc.expr, tc.allowed); got != tc.want {
+			t.Errorf("ValidSortExpression(%q, %q) = %v, want %v", tc.expr, tc.allowed, got, tc.want)
+		}
+	}
+}
+
+func TestValidCursorFormat(t *testing.T) {
+	for _, cursor := range []string{"MTAw", "MTAw==", "eyJpZCI6MX0", "a-b_c", "snap1~MTAw"} {
+		if !ValidCursorFormat(cursor) {
+			t.Errorf("%q should be a valid cursor", cursor)
+		}
+	}
+	for _, cursor := range []string{"", "not a cursor", "<script>", strings.Repeat("A", MaxCursorLength+1)} {
+		if ValidCursorFormat(cursor) {
+			t.Errorf("%q should be rejected", cursor)
+		}
+	}
+}
+
+func TestValidEnum(t *testing.T) {
+	if !ValidEnum("archived", "active|archived") || ValidEnum("deleted", "active|archived") {
+		t.Error("enum membership is wrong")
+	}
+}
+
+func TestTranslateDecodingErrors(t *testing.T) {
+	var dest struct {
+		PageSize int `json:"page_size"`
+	}
+
+	err := json.Unmarshal([]byte(`{"page_size": "big"}`), &dest)
+	body := Translate(err, InBody)
+	if body == nil || len(body.Fields) != 1 {
+		t.Fatalf("Translate(%v) = %+v", err, body)
+	}
+	if f := body.Fields[0]; f.Field != "page_size" || f.Rule != "type" || f.Message != "must be a number" {
+		t.Errorf("unexpected field error: %+v", f)
+	}
+
+	if body := Translate(json.Unmarshal([]byte(`{"page_size":`), &dest), InBody); body == nil || body.Fields[0].Rule != "json" {
+		t.Errorf("malformed JSON should translate to a json rule error, got %+v", body)
+	}
+}
+
+func TestPrefixNestsFields(t *testing.T) {
+	err := Prefix("filters[1]", NewError(
+		FieldError{Field: "value", In: InBody, Rule: "required"},
+		FieldError{Field: "[0]", In: InBody, Rule: "enum"},
+	))
+
+	body := Translate(err, InBody)
+	if body.Fields[0].Field != "filters[1].value" || body.Fields[1].Field != "filters[1][0]" {
+		t.Errorf("unexpected paths: %+v", body.Fields)
+	}
+	if body.Code != "validation_failed" {
+		t.Errorf("Code = %q", body.Code)
+	}
+}
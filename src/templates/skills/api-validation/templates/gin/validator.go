@@ -0,0 +1,110 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Validator wraps go-playground/validator with the rules and field naming every handler uses
+// Errors name fields by their JSON (or form) names, so "PageSize" is reported as
+// "page_size", and the enum, sort_expr, and cursor rules are registered.
+type Validator struct {
+	validate *validator.Validate
+}
+
+// New creates a Validator
+func New() (*Validator, error) {
+	v := validator.New()
+	v.SetTagName("{{tagName}}")
+	v.RegisterTagNameFunc(jsonFieldName)
+
+	if err := registerRules(v); err != nil {
+		return nil, fmt.Errorf("failed to register validation rules: %w", err)
+	}
+	return &Validator{validate: v}, nil
+}
+
+// Default is the Validator used by the package-level Bind helpers
+var Default = mustNew()
+
+func mustNew() *Validator {
+	v, err := New()
+	if err != nil {
+		panic("validation: " + err.Error())
+	}
+	return v
+}
+
+// Struct validates s, descending into nested structs and, with the dive tag, slices and maps
+func (v *Validator) Struct(s any) error {
+	return v.validate.Struct(s)
+}
+
+// Var validates a single value against a tag, e.g. v.Var(sort, "sort_expr=name|created_at")
+func (v *Validator) Var(value any, tag string) error {
+	return v.validate.Var(value, tag)
+}
+
+// Engine returns the underlying validator for registering application-specific rules
+func (v *Validator) Engine() any {
+	return v.validate
+}
+
+// ValidateStruct implements gin's binding.StructValidator
+func (v *Validator) ValidateStruct(obj any) error {
+	if obj == nil {
+		return nil
+	}
+
+	value := reflect.ValueOf(obj)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		return v.validate.Struct(obj)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if err := v.ValidateStruct(value.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Install makes v gin's binding validator, so c.ShouldBind* and binding tags use the same
+// rules and error names as Bind
+//
+// Example usage:
+//
+//	func main() {
+//	    validation.Install(validation.Default)
+//	    r := gin.Default()
+//	    // ...
+//	}
+func Install(v *Validator) {
+	binding.Validator = v
+}
+
+// jsonFieldName names a field by its json tag, then its form tag, then its Go name
+func jsonFieldName(field reflect.StructField) string {
+	for _, key := range []string{"json", "form"} {
+		name := strings.SplitN(field.Tag.Get(key), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
@@ -0,0 +1,60 @@
+package indexes
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// GeneratedMigration is the migration installed for PaginationIndexes
+const GeneratedMigration = "{{migrationsPath}}/{{migrationVersion}}_create_idx_{{tableName}}_pagination.up.sql"
+
+// Warning reports a pagination index missing from the database
+type Warning struct {
+	Index Index
+	Fix   string // How to create it
+}
+
+// String renders the warning for logs
+func (w Warning) String() string {
+	return fmt.Sprintf("missing pagination index %s on %s; keyset pages will scan and sort the table. %s",
+		w.Index.Name, w.Index, w.Fix)
+}
+
+// Advise checks that each index exists and returns a warning for every missing one
+// The warning points at the generated migration for PaginationIndexes, and at
+// WriteMigrations or AutoMigrate for indexes declared elsewhere.
+func Advise(db *gorm.DB, indexes ...Index) []Warning {
+	var warnings []Warning
+	for _, ix := range indexes {
+		if db.Migrator().HasIndex(ix.Table, ix.Name) {
+			continue
+		}
+		warnings = append(warnings, Warning{Index: ix, Fix: fix(ix)})
+	}
+	return warnings
+}
+
+// CheckIndexes logs Advise's warnings; call it at startup
+//
+// Example usage:
+//
+//	indexes.CheckIndexes(db, indexes.PaginationIndexes...)
+//	// missing pagination index idx_users_pagination on users (created_at DESC, id DESC) WHERE
+//	// deleted_at IS NULL; ... Apply {{migrationsPath}}/..._create_idx_users_pagination.up.sql ...
+func CheckIndexes(db *gorm.DB, indexes ...Index) {
+	for _, warning := range Advise(db, indexes...) {
+		log.Printf("indexes: %s", warning)
+	}
+}
+
+// fix suggests how to create ix
+func fix(ix Index) string {
+	for _, generated := range PaginationIndexes {
+		if generated.Name == ix.Name && generated.Table == ix.Table {
+			return fmt.Sprintf("Apply %s (migrate -path {{migrationsPath}} up) or call indexes.AutoMigrate.", GeneratedMigration)
+		}
+	}
+	return "Generate a migration with indexes.WriteMigrations or call indexes.AutoMigrate."
+}
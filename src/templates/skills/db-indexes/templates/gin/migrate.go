@@ -0,0 +1,67 @@
+package indexes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gorm.io/gorm"
+)
+
+// AutoMigrate creates the missing indexes on db, the gorm counterpart of the golang-migrate
+// files
+// Call it after db.AutoMigrate(&Model{}); existing indexes are left untouched.
+//
+// Example usage:
+//
+//	if err := db.AutoMigrate(&User{}); err != nil {
+//	    return err
+//	}
+//	if err := indexes.AutoMigrate(db, indexes.PaginationIndexes...); err != nil {
+//	    return err
+//	}
+func AutoMigrate(db *gorm.DB, indexes ...Index) error {
+	dialect := Dialect(db.Dialector.Name())
+	for _, ix := range indexes {
+		if db.Migrator().HasIndex(ix.Table, ix.Name) {
+			continue
+		}
+		if err := db.Exec(ix.CreateSQL(dialect)).Error; err != nil {
+			return fmt.Errorf("failed to create index %s: %w", ix.Name, err)
+		}
+	}
+	return nil
+}
+
+// WriteMigrations writes a golang-migrate up/down pair per index into dir, numbered from
+// version, and returns the up file paths
+// Each file holds a single statement, so Postgres' CREATE INDEX CONCURRENTLY is not
+// wrapped in a transaction.
+//
+// Example usage:
+//
+//	ix, _ := indexes.PaginationIndex("orders", "created_at DESC, id DESC", "deleted_at")
+//	files, err := indexes.WriteMigrations("migrations", 20240601120000, indexes.Postgres, ix)
+func WriteMigrations(dir string, version uint64, d Dialect, indexes ...Index) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create migrations directory: %w", err)
+	}
+
+	ups := make([]string, 0, len(indexes))
+	for i, ix := range indexes {
+		base := filepath.Join(dir, MigrationName(version+uint64(i), ix))
+		if err := os.WriteFile(base+".up.sql", []byte(ix.CreateSQL(d)+";\n"), 0o644); err != nil {
+			return nil, fmt.Errorf("failed to write migration: %w", err)
+		}
+		if err := os.WriteFile(base+".down.sql", []byte(ix.DropSQL(d)+";\n"), 0o644); err != nil {
+			return nil, fmt.Errorf("failed to write migration: %w", err)
+		}
+		ups = append(ups, base+".up.sql")
+	}
+	return ups, nil
+}
+
+// MigrationName is the golang-migrate file name (without .up.sql/.down.sql) for ix
+func MigrationName(version uint64, ix Index) string {
+	return fmt.Sprintf("%d_create_%s", version, ix.Name)
+}
@@ -0,0 +1 @@
+DROP INDEX CONCURRENTLY IF EXISTS idx_{{tableName}}_pagination;
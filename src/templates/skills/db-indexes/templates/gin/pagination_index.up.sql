@@ -0,0 +1,3 @@
+-- Keyset pagination index for {{tableName}}: matches ORDER BY {{indexColumns}}
+-- CONCURRENTLY avoids locking writes; golang-migrate runs this single statement outside a transaction
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_{{tableName}}_pagination ON {{tableName}} ({{indexColumns}}){{#if softDeleteColumn}} WHERE {{softDeleteColumn}} IS NULL{{/if}};
@@ -0,0 +1,16 @@
+package indexes
+
+// PaginationIndexes are the indexes created by the generated migration: the keyset order of
+// {{tableName}}, scoped to live rows when the table soft-deletes
+// Append the indexes of other paginated tables to check them with CheckIndexes.
+var PaginationIndexes = []Index{
+	mustPaginationIndex("{{tableName}}", "{{indexColumns}}", "{{softDeleteColumn}}"),
+}
+
+func mustPaginationIndex(table, columns, softDeleteColumn string) Index {
+	ix, err := PaginationIndex(table, columns, softDeleteColumn)
+	if err != nil {
+		panic("indexes: " + err.Error())
+	}
+	return ix
+}
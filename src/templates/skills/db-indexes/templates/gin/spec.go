@@ -0,0 +1,145 @@
+package indexes
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Dialect selects the SQL flavor of generated statements
+type Dialect string
+
+// Supported dialects (the names gorm's dialectors report)
+const (
+	Postgres Dialect = "postgres"
+	MySQL    Dialect = "mysql"
+	SQLite   Dialect = "sqlite"
+)
+
+// Column is one key of a composite index
+type Column struct {
+	Name  string
+	Desc  bool
+	Lower bool // Index LOWER(name), for case-insensitive sorts and lookups
+}
+
+// Index describes a composite pagination index
+type Index struct {
+	Name    string
+	Table   string
+	Columns []Column
+
+	// SoftDeleteColumn scopes the index to live rows (WHERE deleted_at IS NULL), matching
+	// gorm's soft-delete filter; empty for tables without soft deletes
+	SoftDeleteColumn string
+}
+
+// columnPattern matches "name", "name DESC", "LOWER(name)", and "LOWER(name) DESC"
+var columnPattern = regexp.MustCompile(`(?i)^(?:(lower)\(\s*([A-Za-z_][A-Za-z0-9_]*)\s*\)|([A-Za-z_][A-Za-z0-9_]*))(?:\s+(asc|desc))?$`)
+
+// ParseColumns parses an index column list in SQL order, e.g. "LOWER(name), id DESC"
+func ParseColumns(list string) ([]Column, error) {
+	var columns []Column
+	for _, part := range strings.Split(list, ",") {
+		part = strings.TrimSpace(part)
+		match := columnPattern.FindStringSubmatch(part)
+		if match == nil {
+			return nil, fmt.Errorf("invalid index column %q", part)
+		}
+
+		column := Column{Name: match[3], Desc: strings.EqualFold(match[4], "desc")}
+		if match[1] != "" {
+			column = Column{Name: match[2], Desc: column.Desc, Lower: true}
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// PaginationIndex builds the index behind keyset pagination of table
+// columns lists the cursor and sort columns in ORDER BY order, ending with the unique
+// tiebreaker, e.g. "created_at DESC, id DESC"; softDeleteColumn may be empty.
+//
+// Example usage:
+//
+//	index, err := indexes.PaginationIndex("users", "LOWER(name), id", "deleted_at")
+func PaginationIndex(table, columns, softDeleteColumn string) (Index, error) {
+	parsed, err := ParseColumns(columns)
+	if err != nil {
+		return Index{}, err
+	}
+	return Index{
+		Name:             "idx_" + table + "_pagination",
+		Table:            table,
+		Columns:          parsed,
+		SoftDeleteColumn: softDeleteColumn,
+	}, nil
+}
+
+// CreateSQL renders the CREATE INDEX statement for d
+// Postgres builds the index CONCURRENTLY (run it outside a transaction) as a partial index.
+// MySQL has no partial indexes, so the soft-delete column leads the key instead, and
+// expression keys use MySQL 8's double parentheses.
+func (ix Index) CreateSQL(d Dialect) string {
+	keys := make([]string, 0, len(ix.Columns)+1)
+	if d == MySQL && ix.SoftDeleteColumn != "" {
+		keys = append(keys, ix.SoftDeleteColumn)
+	}
+	for _, column := range ix.Columns {
+		keys = append(keys, column.sql(d))
+	}
+
+	var sql strings.Builder
+	switch d {
+	case Postgres:
+		fmt.Fprintf(&sql, "CREATE INDEX CONCURRENTLY IF NOT EXISTS %s ON %s (%s)", ix.Name, ix.Table, strings.Join(keys, ", "))
+	case MySQL:
+		fmt.Fprintf(&sql, "CREATE INDEX %s ON %s (%s)", ix.Name, ix.Table, strings.Join(keys, ", "))
+	default:
+		fmt.Fprintf(&sql, "CREATE INDEX IF NOT EXISTS %s ON %s (%s)", ix.Name, ix.Table, strings.Join(keys, ", "))
+	}
+	if d != MySQL && ix.SoftDeleteColumn != "" {
+		fmt.Fprintf(&sql, " WHERE %s IS NULL", ix.SoftDeleteColumn)
+	}
+	return sql.String()
+}
+
+// DropSQL renders the statement that reverses CreateSQL
+func (ix Index) DropSQL(d Dialect) string {
+	switch d {
+	case Postgres:
+		return fmt.Sprintf("DROP INDEX CONCURRENTLY IF EXISTS %s", ix.Name)
+	case MySQL:
+		return fmt.Sprintf("DROP INDEX %s ON %s", ix.Name, ix.Table)
+	default:
+		return fmt.Sprintf("DROP INDEX IF EXISTS %s", ix.Name)
+	}
+}
+
+// String describes the index for warnings, e.g. "users (LOWER(name), id) WHERE deleted_at IS NULL"
+func (ix Index) String() string {
+	keys := make([]string, len(ix.Columns))
+	for i, column := range ix.Columns {
+		keys[i] = column.sql(Postgres)
+	}
+	desc := fmt.Sprintf("%s (%s)", ix.Table, strings.Join(keys, ", "))
+	if ix.SoftDeleteColumn != "" {
+		desc += " WHERE " + ix.SoftDeleteColumn + " IS NULL"
+	}
+	return desc
+}
+
+// sql renders the column as an index key
+func (c Column) sql(d Dialect) string {
+	key := c.Name
+	if c.Lower {
+		key = "LOWER(" + c.Name + ")"
+		if d == MySQL {
+			key = "(" + key + ")"
+		}
+	}
+	if c.Desc {
+		key += " DESC"
+	}
+	return key
+}
@@ -0,0 +1,90 @@
+package indexes
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseColumns(t *testing.T) {
+	columns, err := ParseColumns("LOWER(name) DESC, created_at, id desc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := Column{Name: "name", Desc: true, Lower: true}
+	createdAt := Column{Name: "created_at"}
+	id := Column{Name: "id", Desc: true}
+	want := []Column{name, createdAt, id}
+	if len(columns) != len(want) {
+		t.Fatalf("columns = %+v", columns)
+	}
+	for i := range want {
+		if columns[i] != want[i] {
+			t.Errorf("column %d = %+v, want %+v", i, columns[i], want[i])
+		}
+	}
+
+	for _, bad := range []string{"", "id;", "UPPER(name)", "id, ", "name DESC DESC"} {
+		if _, err := ParseColumns(bad); err == nil {
+			t.Errorf("%q should be rejected", bad)
+		}
+	}
+}
+
+func TestCreateSQL(t *testing.T) {
+	ix, err := PaginationIndex("users", "LOWER(name), id DESC", "deleted_at")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[Dialect]string{
+		Postgres: "CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_users_pagination ON users (LOWER(name), id DESC) WHERE deleted_at IS NULL",
+		SQLite:   "CREATE INDEX IF NOT EXISTS idx_users_pagination ON users (LOWER(name), id DESC) WHERE deleted_at IS NULL",
+		MySQL:    "CREATE INDEX idx_users_pagination ON users (deleted_at, (LOWER(name)), id DESC)",
+	}
+	for dialect, want := range cases {
+		if got := ix.CreateSQL(dialect); got != want {
+			t.Errorf("%s:\n got %s\nwant %s", dialect, got, want)
+		}
+	}
+
+	plain, _ := PaginationIndex("events", "created_at DESC, id DESC", "")
+	if got := plain.CreateSQL(Postgres); strings.Contains(got, "WHERE") {
+		t.Errorf("index without soft deletes should not be partial: %s", got)
+	}
+}
+
+func TestWriteMigrations(t *testing.T) {
+	orders, _ := PaginationIndex("orders", "created_at DESC, id DESC", "deleted_at")
+	users, _ := PaginationIndex("users", "id", "")
+
+	dir := t.TempDir()
+	ups, err := WriteMigrations(dir, 100, Postgres, orders, users)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ups) != 2 || filepath.Base(ups[1]) != "101_create_idx_users_pagination.up.sql" {
+		t.Fatalf("ups = %v", ups)
+	}
+
+	down, err := os.ReadFile(filepath.Join(dir, "100_create_idx_orders_pagination.down.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(down) != "DROP INDEX CONCURRENTLY IF EXISTS idx_orders_pagination;\n" {
+		t.Errorf("down = %q", down)
+	}
+}
+
+func TestAdvisorPointsAtGeneratedMigration(t *testing.T) {
+	warning := Warning{Index: PaginationIndexes[0], Fix: fix(PaginationIndexes[0])}
+	if !strings.Contains(warning.String(), GeneratedMigration) {
+		t.Errorf("warning should name %s: %s", GeneratedMigration, warning)
+	}
+
+	other, _ := PaginationIndex("audit_log", "id", "")
+	if strings.Contains(fix(other), GeneratedMigration) {
+		t.Error("indexes without a generated migration should not point at it")
+	}
+}
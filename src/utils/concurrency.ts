@@ -0,0 +1,58 @@
+import os from 'os';
+
+/**
+ * Bounded concurrency helpers for CPU/IO heavy generation steps
+ */
+
+/**
+ * Default worker count: one per available CPU
+ */
+export function defaultConcurrency(): number {
+  const available =
+    typeof os.availableParallelism === 'function' ? os.availableParallelism() : os.cpus().length;
+  return Math.max(1, available);
+}
+
+/**
+ * Parse a --concurrency option value (undefined falls back to defaultConcurrency)
+ */
+export function parseConcurrency(value: string | number | undefined): number {
+  if (value === undefined) return defaultConcurrency();
+
+  const parsed = typeof value === 'number' ? value : Number.parseInt(value, 10);
+  if (!Number.isInteger(parsed) || parsed < 1) {
+    throw new Error(`Invalid concurrency "${value}" (expected a positive integer)`);
+  }
+
+  return parsed;
+}
+
+/**
+ * Run fn over items with at most `limit` calls in flight
+ * - Results are returned in input order regardless of completion order
+ * - A rejection never cancels other items; each outcome is reported individually
+ */
+export async function mapWithConcurrency<T, R>(
+  items: readonly T[],
+  limit: number,
+  fn: (item: T, index: number) => Promise<R>
+): Promise<PromiseSettledResult<R>[]> {
+  const results: PromiseSettledResult<R>[] = new Array(items.length);
+  let next = 0;
+
+  const worker = async (): Promise<void> => {
+    while (next < items.length) {
+      const index = next++;
+      try {
+        results[index] = { status: 'fulfilled', value: await fn(items[index], index) };
+      } catch (reason) {
+        results[index] = { status: 'rejected', reason };
+      }
+    }
+  };
+
+  const workers = Math.max(1, Math.min(limit, items.length));
+  await Promise.all(Array.from({ length: workers }, worker));
+
+  return results;
+}
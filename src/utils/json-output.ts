@@ -0,0 +1,105 @@
+/**
+ * Machine-readable output for CLI commands (`--output json`)
+ *
+ * Every JSON document is wrapped in a versioned envelope so automation can detect schema
+ * changes. Bump OUTPUT_SCHEMA_VERSION whenever a field is renamed or removed; adding
+ * fields is backwards compatible and does not require a bump.
+ */
+
+export const OUTPUT_SCHEMA_VERSION = 1;
+
+export type OutputFormat = 'text' | 'json';
+
+/**
+ * Stable error codes emitted in JSON error envelopes
+ */
+export type CliErrorCode =
+  | 'TEMPLATES_LIST_FAILED'
+  | 'VALIDATION_FAILED'
+  | 'VALIDATION_ERROR'
+  | 'INIT_REQUIRES_YES'
+  | 'INIT_TARGET_EXISTS'
+  | 'INIT_FAILED';
+
+export interface JsonSuccessEnvelope<T> {
+  schemaVersion: number;
+  command: string;
+  ok: true;
+  data: T;
+}
+
+export interface JsonErrorEnvelope {
+  schemaVersion: number;
+  command: string;
+  ok: false;
+  error: {
+    code: CliErrorCode;
+    message: string;
+    details?: unknown;
+  };
+}
+
+/**
+ * Parse the --output option value
+ */
+export function parseOutputFormat(value: string | undefined): OutputFormat {
+  if (value === undefined || value === 'text') return 'text';
+  if (value === 'json') return 'json';
+
+  throw new Error(`Unsupported output format "${value}" (expected "text" or "json")`);
+}
+
+/**
+ * Build a success envelope
+ */
+export function jsonSuccess<T>(command: string, data: T): JsonSuccessEnvelope<T> {
+  return { schemaVersion: OUTPUT_SCHEMA_VERSION, command, ok: true, data };
+}
+
+/**
+ * Build an error envelope
+ */
+export function jsonError(
+  command: string,
+  code: CliErrorCode,
+  message: string,
+  details?: unknown
+): JsonErrorEnvelope {
+  return {
+    schemaVersion: OUTPUT_SCHEMA_VERSION,
+    command,
+    ok: false,
+    error: details === undefined ? { code, message } : { code, message, details },
+  };
+}
+
+/**
+ * Write a success envelope to stdout
+ */
+export function writeJsonSuccess<T>(command: string, data: T): void {
+  process.stdout.write(`${JSON.stringify(jsonSuccess(command, data), null, 2)}\n`);
+}
+
+/**
+ * Send console.log output to stderr while a JSON command runs, so stdout carries only the
+ * envelope; returns a function restoring console.log
+ */
+export function routeConsoleToStderr(): () => void {
+  const log = console.log;
+  console.log = (...args: unknown[]) => console.error(...args);
+  return () => {
+    console.log = log;
+  };
+}
+
+/**
+ * Write an error envelope to stderr
+ */
+export function writeJsonError(
+  command: string,
+  code: CliErrorCode,
+  message: string,
+  details?: unknown
+): void {
+  process.stderr.write(`${JSON.stringify(jsonError(command, code, message, details), null, 2)}\n`);
+}
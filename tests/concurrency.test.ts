@@ -0,0 +1,48 @@
+import { describe, it, expect } from 'vitest';
+import { mapWithConcurrency, parseConcurrency } from '../src/utils/concurrency.js';
+
+const delay = (ms: number) => new Promise((resolve) => setTimeout(resolve, ms));
+
+describe('mapWithConcurrency', () => {
+  it('should return results in input order regardless of completion order', async () => {
+    const results = await mapWithConcurrency([30, 10, 20], 3, async (ms) => {
+      await delay(ms);
+      return ms;
+    });
+
+    expect(results.map((result) => (result as PromiseFulfilledResult<number>).value)).toEqual([
+      30, 10, 20,
+    ]);
+  });
+
+  it('should never run more than the limit at once', async () => {
+    let inFlight = 0;
+    let peak = 0;
+
+    await mapWithConcurrency([1, 2, 3, 4, 5, 6], 2, async () => {
+      peak = Math.max(peak, ++inFlight);
+      await delay(5);
+      inFlight--;
+    });
+
+    expect(peak).toBe(2);
+  });
+
+  it('should keep running other items when one fails', async () => {
+    const results = await mapWithConcurrency(['a', 'bad', 'c'], 1, async (item) => {
+      if (item === 'bad') throw new Error('boom');
+      return item;
+    });
+
+    expect(results.map((result) => result.status)).toEqual(['fulfilled', 'rejected', 'fulfilled']);
+  });
+});
+
+describe('parseConcurrency', () => {
+  it('should accept positive integers and reject anything else', () => {
+    expect(parseConcurrency('4')).toBe(4);
+    expect(parseConcurrency(undefined)).toBeGreaterThanOrEqual(1);
+    expect(() => parseConcurrency('0')).toThrow(/Invalid concurrency/);
+    expect(() => parseConcurrency('many')).toThrow(/Invalid concurrency/);
+  });
+});
@@ -0,0 +1,133 @@
+import { describe, it, expect, beforeEach, afterEach } from 'vitest';
+import fs from 'fs-extra';
+import path from 'path';
+import os from 'os';
+import { execFileSync } from 'child_process';
+import { GenerationError, GenerationTransaction } from '../src/lib/generation-transaction.js';
+
+function hasGoimports(): boolean {
+  try {
+    execFileSync('goimports', ['-l', os.devNull]);
+    return true;
+  } catch {
+    return false;
+  }
+}
+
+describe('GenerationTransaction', () => {
+  let testDir: string;
+
+  beforeEach(async () => {
+    testDir = path.join(os.tmpdir(), `agentweaver-txn-${Date.now()}`);
+    await fs.ensureDir(testDir);
+  });
+
+  afterEach(async () => {
+    if (await fs.pathExists(testDir)) {
+      await fs.remove(testDir);
+    }
+  });
+
+  it('should not touch the project until commit', async () => {
+    const transaction = new GenerationTransaction(testDir);
+
+    await transaction.stage(path.join(testDir, 'internal', 'a.txt'), 'a');
+    expect(await fs.pathExists(path.join(testDir, 'internal', 'a.txt'))).toBe(false);
+
+    await transaction.verify();
+    await transaction.commit();
+    await transaction.dispose();
+
+    expect(await fs.readFile(path.join(testDir, 'internal', 'a.txt'), 'utf-8')).toBe('a');
+    expect(transaction.stagedFiles).toEqual([path.join('internal', 'a.txt')]);
+  });
+
+  it('should restore originals and remove new files when a write fails', async () => {
+    await fs.writeFile(path.join(testDir, 'existing.txt'), 'original');
+    // A regular file where a directory is expected makes the second write fail
+    await fs.writeFile(path.join(testDir, 'blocked'), 'not a directory');
+
+    const transaction = new GenerationTransaction(testDir);
+    await transaction.stage(path.join(testDir, 'existing.txt'), 'updated');
+    await transaction.stage(path.join(testDir, 'created.txt'), 'new');
+    await transaction.stage(path.join(testDir, 'blocked', 'child.txt'), 'child');
+
+    await expect(transaction.commit()).rejects.toThrow(GenerationError);
+    await transaction.dispose();
+
+    expect(await fs.readFile(path.join(testDir, 'existing.txt'), 'utf-8')).toBe('original');
+    expect(await fs.pathExists(path.join(testDir, 'created.txt'))).toBe(false);
+    expect(await fs.readFile(path.join(testDir, 'blocked'), 'utf-8')).toBe('not a directory');
+  });
+
+  it('should remove directories it created when a write fails', async () => {
+    await fs.ensureDir(path.join(testDir, 'internal'));
+    await fs.writeFile(path.join(testDir, 'zz-blocked'), 'not a directory');
+
+    // Commits run in path order, so both new files are written before the blocked one fails
+    const transaction = new GenerationTransaction(testDir);
+    await transaction.stage(path.join(testDir, 'internal', 'api', 'pagination', 'a.go'), 'a');
+    await transaction.stage(path.join(testDir, 'internal', 'api', 'sorting', 'b.go'), 'b');
+    await transaction.stage(path.join(testDir, 'zz-blocked', 'child.txt'), 'child');
+
+    await expect(transaction.commit()).rejects.toThrow(GenerationError);
+    await transaction.dispose();
+
+    expect(await fs.pathExists(path.join(testDir, 'internal', 'api'))).toBe(false);
+    expect(await fs.readdir(path.join(testDir, 'internal'))).toEqual([]);
+  });
+
+  it('should fail verification for invalid JSON before anything is written', async () => {
+    const transaction = new GenerationTransaction(testDir);
+    await transaction.stage(path.join(testDir, 'config.json'), '{ "broken": ');
+
+    await expect(transaction.verify()).rejects.toThrow(/Verification failed for config.json/);
+    await transaction.dispose();
+
+    expect(await fs.pathExists(path.join(testDir, 'config.json'))).toBe(false);
+  });
+
+  it('should report every failing file in path order', async () => {
+    const transaction = new GenerationTransaction(testDir);
+    await Promise.all([
+      transaction.stage(path.join(testDir, 'b.json'), '{'),
+      transaction.stage(path.join(testDir, 'a.json'), '['),
+      transaction.stage(path.join(testDir, 'ok.json'), '{}'),
+    ]);
+
+    const progress: string[] = [];
+    const error = await transaction
+      .verify({ concurrency: 3, onProgress: (_done, _total, file) => progress.push(file) })
+      .catch((e: Error) => e);
+    await transaction.dispose();
+
+    expect((error as Error).message).toMatch(
+      /Verification failed for 2 files:\n {2}a.json: .*\n {2}b.json: /
+    );
+    expect(progress.sort()).toEqual(['a.json', 'b.json', 'ok.json']);
+    expect(transaction.stagedFiles).toEqual(['a.json', 'b.json', 'ok.json']);
+  });
+
+  it.skipIf(!hasGoimports())('should fail verification for a missing Go import', async () => {
+    const transaction = new GenerationTransaction(testDir);
+    await transaction.stage(
+      path.join(testDir, 'models.go'),
+      'package models\n\nfunc Link(page int) string { return fmt.Sprintf("?page=%d", page) }\n'
+    );
+    await transaction.stage(path.join(testDir, 'ok.go'), 'package models\n\nconst Page = 1\n');
+
+    await expect(transaction.verify()).rejects.toThrow(
+      /Verification failed for models.go: imports are missing or unused/
+    );
+    await transaction.dispose();
+  });
+
+  it('should refuse targets outside the project root', async () => {
+    const transaction = new GenerationTransaction(testDir);
+
+    await expect(
+      transaction.stage(path.join(testDir, '..', 'outside.txt'), 'nope')
+    ).rejects.toThrow(/outside the project/);
+    await transaction.dispose();
+  });
+});
@@ -0,0 +1,187 @@
+import { describe, it, expect, beforeAll, afterAll } from 'vitest';
+import fs from 'fs-extra';
+import path from 'path';
+import os from 'os';
+import { execFileSync } from 'child_process';
+import { fileURLToPath } from 'url';
+import Handlebars from 'handlebars';
+import { SkillsInstaller } from '../src/lib/skills-installer.js';
+import type { TemplatePackManifest } from '../src/lib/template-pack.js';
+
+const __filename = fileURLToPath(import.meta.url);
+const __dirname = path.dirname(__filename);
+
+const skillsDir = path.join(__dirname, '..', 'src', 'templates', 'skills');
+const modulePath = 'github.com/acme/shop';
+
+interface GinPack {
+  skill: string;
+  packPath: string;
+  manifest: TemplatePackManifest;
+}
+
+/**
+ * Every gin pack, discovered from the manifests so new skills are covered automatically
+ */
+async function ginPacks(): Promise<GinPack[]> {
+  const packs: GinPack[] = [];
+  for (const skill of await fs.readdir(skillsDir)) {
+    const packPath = path.join(skillsDir, skill, 'templates', 'gin');
+    if (await fs.pathExists(path.join(packPath, 'manifest.json'))) {
+      const manifest = await fs.readJson(path.join(packPath, 'manifest.json'));
+      packs.push({ skill, packPath, manifest });
+    }
+  }
+  return packs;
+}
+
+function hasGo(): boolean {
+  try {
+    execFileSync('go', ['version']);
+    return true;
+  } catch {
+    return false;
+  }
+}
+
+/**
+ * Variables that switch {{#if}} / {{#unless}} sections on or off in a pack's Go files
+ */
+async function conditionalVariables(pack: GinPack): Promise<string[]> {
+  const names = new Set<string>();
+  for (const file of pack.manifest.files) {
+    if (!file.source.endsWith('.go') || file.templateEngine !== 'handlebars') continue;
+
+    const content = await fs.readFile(path.join(pack.packPath, file.source), 'utf-8');
+    for (const match of content.matchAll(/\{\{#(?:if|unless)\s+([\w.]+)\s*\}\}/g)) {
+      names.add(match[1]);
+    }
+  }
+  return [...names];
+}
+
+/**
+ * The variables the installer renders a pack with: manifest defaults plus the go.mod context
+ */
+function defaultContext(pack: GinPack): Record<string, unknown> {
+  const context: Record<string, unknown> = {};
+  for (const [name, variable] of Object.entries(pack.manifest.variables || {})) {
+    context[name] = variable.default || '';
+  }
+  context.moduleName = modulePath;
+  context.packageImportPath = `${modulePath}/${context.packagePath}`;
+  return context;
+}
+
+/**
+ * Re-render a pack's Go files over the installed ones with some variables overridden
+ */
+async function renderGoFiles(
+  pack: GinPack,
+  projectRoot: string,
+  overrides: Record<string, unknown>
+): Promise<void> {
+  const context = { ...defaultContext(pack), ...overrides };
+
+  for (const file of pack.manifest.files) {
+    if (!file.source.endsWith('.go')) continue;
+
+    let content = await fs.readFile(path.join(pack.packPath, file.source), 'utf-8');
+    if (file.templateEngine === 'handlebars') {
+      content = Handlebars.compile(content)(context);
+    }
+    await fs.outputFile(path.join(projectRoot, Handlebars.compile(file.target)(context)), content);
+  }
+}
+
+function go(projectRoot: string, ...args: string[]): void {
+  try {
+    execFileSync('go', args, { cwd: projectRoot, encoding: 'utf-8', stdio: 'pipe' });
+  } catch (error) {
+    const { stdout, stderr } = error as { stdout?: string; stderr?: string };
+    throw new Error(`go ${args.join(' ')} failed:\n${stderr || ''}${stdout || ''}`);
+  }
+}
+
+describe('Gin templates compile', () => {
+  let testDir: string;
+  let packs: GinPack[];
+
+  beforeAll(async () => {
+    testDir = path.join(os.tmpdir(), `agentweaver-gin-compile-${Date.now()}`);
+    await fs.ensureDir(testDir);
+    await fs.writeFile(path.join(testDir, 'go.mod'), `module ${modulePath}\n\ngo 1.22\n`);
+
+    packs = await ginPacks();
+    const installer = new SkillsInstaller(skillsDir);
+    const result = await installer.installSkills({
+      targetDirectory: path.join(testDir, '.claude', 'skills'),
+      skillsToInstall: packs.map((pack) => pack.skill),
+      techStackContext: { techStack: { language: 'go', framework: 'gin' } },
+      projectRoot: testDir,
+    });
+
+    expect(result.errors).toHaveLength(0);
+  }, 120000);
+
+  afterAll(async () => {
+    if (testDir && (await fs.pathExists(testDir))) {
+      await fs.remove(testDir);
+    }
+  });
+
+  it('should render every gin template with its default variables', async () => {
+    const goFiles: string[] = [];
+    for (const pack of packs) {
+      const context = defaultContext(pack);
+      for (const file of pack.manifest.files.filter((f) => f.source.endsWith('.go'))) {
+        goFiles.push(path.join(testDir, Handlebars.compile(file.target)(context)));
+      }
+    }
+
+    expect(goFiles.length).toBeGreaterThan(0);
+    for (const file of goFiles) {
+      const content = await fs.readFile(file, 'utf-8');
+      // A leftover mustache means Go code was mistaken for a template expression
+      expect(content, file).not.toContain('{{');
+    }
+  });
+
+  it.skipIf(!hasGo())(
+    'should build and vet the full gin variant',
+    () => {
+      // Resolves gin, gorm, and the other third-party imports of the generated packages
+      go(testDir, 'mod', 'tidy');
+      // vet type-checks every package and its tests, so a missing or unused import fails here
+      go(testDir, 'vet', './...');
+    },
+    600000
+  );
+
+  it.skipIf(!hasGo())(
+    'should build every combination of conditional sections',
+    async () => {
+      go(testDir, 'mod', 'tidy');
+
+      for (const pack of packs) {
+        const variables = await conditionalVariables(pack);
+
+        // Each {{#if}} variable is rendered both on and off, one combination at a time
+        for (let mask = 1; mask < 1 << variables.length; mask++) {
+          const overrides: Record<string, unknown> = {};
+          variables.forEach((name, bit) => {
+            if (mask & (1 << bit)) {
+              overrides[name] = pack.manifest.variables?.[name]?.default ? '' : 'true';
+            }
+          });
+
+          await renderGoFiles(pack, testDir, overrides);
+          go(testDir, 'vet', './...');
+        }
+
+        await renderGoFiles(pack, testDir, {});
+      }
+    },
+    600000
+  );
+});
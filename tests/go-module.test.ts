@@ -0,0 +1,120 @@
+import { describe, it, expect, beforeEach, afterEach } from 'vitest';
+import fs from 'fs-extra';
+import path from 'path';
+import os from 'os';
+import { fileURLToPath } from 'url';
+import {
+  parseModulePath,
+  findGoModule,
+  goImportPath,
+  resolveGoImportPath,
+  GoModuleNotFoundError,
+} from '../src/lib/go-module.js';
+import { SkillsInstaller } from '../src/lib/skills-installer.js';
+
+const __filename = fileURLToPath(import.meta.url);
+const __dirname = path.dirname(__filename);
+
+describe('Go module resolution', () => {
+  let testDir: string;
+
+  beforeEach(async () => {
+    testDir = path.join(os.tmpdir(), `agentweaver-gomod-${Date.now()}`);
+    await fs.ensureDir(testDir);
+  });
+
+  afterEach(async () => {
+    if (await fs.pathExists(testDir)) {
+      await fs.remove(testDir);
+    }
+  });
+
+  describe('parseModulePath', () => {
+    it('should read plain, quoted, and commented module directives', () => {
+      expect(parseModulePath('module github.com/acme/shop\n\ngo 1.22\n')).toBe(
+        'github.com/acme/shop'
+      );
+      expect(parseModulePath('module "example.com/quoted"\n')).toBe('example.com/quoted');
+      expect(parseModulePath('// header\nmodule example.com/c // trailing\n')).toBe(
+        'example.com/c'
+      );
+      expect(parseModulePath('go 1.22\n')).toBeNull();
+    });
+  });
+
+  describe('findGoModule', () => {
+    it('should resolve import paths for directories that do not exist yet', async () => {
+      await fs.writeFile(path.join(testDir, 'go.mod'), 'module github.com/acme/shop\n');
+
+      const target = path.join(testDir, 'internal', 'api', 'pagination');
+      const { importPath } = await resolveGoImportPath(target);
+
+      expect(importPath).toBe('github.com/acme/shop/internal/api/pagination');
+    });
+
+    it('should prefer the nearest nested module', async () => {
+      await fs.writeFile(path.join(testDir, 'go.mod'), 'module github.com/acme/mono\n');
+      await fs.ensureDir(path.join(testDir, 'services', 'orders'));
+      await fs.writeFile(
+        path.join(testDir, 'services', 'orders', 'go.mod'),
+        'module github.com/acme/mono/services/orders\n'
+      );
+
+      const target = path.join(testDir, 'services', 'orders', 'internal', 'api');
+      const module = await findGoModule(target);
+
+      expect(module?.moduleRoot).toBe(path.join(testDir, 'services', 'orders'));
+      expect(goImportPath(module!, target)).toBe(
+        'github.com/acme/mono/services/orders/internal/api'
+      );
+    });
+
+    it('should report guidance when generating outside any module', async () => {
+      await expect(resolveGoImportPath(path.join(testDir, 'internal'))).rejects.toBeInstanceOf(
+        GoModuleNotFoundError
+      );
+      await expect(resolveGoImportPath(path.join(testDir, 'internal'))).rejects.toThrow(
+        /go mod init/
+      );
+    });
+  });
+
+  describe('Gin template installation', () => {
+    it('should render import paths from the project go.mod', async () => {
+      await fs.writeFile(path.join(testDir, 'go.mod'), 'module github.com/acme/shop\n\ngo 1.22\n');
+
+      const installer = new SkillsInstaller(
+        path.join(__dirname, '..', 'src', 'templates', 'skills')
+      );
+      const result = await installer.installSkills({
+        targetDirectory: path.join(testDir, '.claude', 'skills'),
+        skillsToInstall: ['api-pagination'],
+        techStackContext: { techStack: { language: 'go', framework: 'gin' } },
+        projectRoot: testDir,
+      });
+
+      expect(result.errors).toHaveLength(0);
+
+      const middleware = await fs.readFile(
+        path.join(testDir, 'internal', 'api', 'pagination', 'middleware.go'),
+        'utf-8'
+      );
+      expect(middleware).toContain('import "github.com/acme/shop/internal/api/pagination"');
+    });
+
+    it('should fail the skill when the project has no go.mod', async () => {
+      const installer = new SkillsInstaller(
+        path.join(__dirname, '..', 'src', 'templates', 'skills')
+      );
+      const result = await installer.installSkills({
+        targetDirectory: path.join(testDir, '.claude', 'skills'),
+        skillsToInstall: ['api-pagination'],
+        techStackContext: { techStack: { language: 'go', framework: 'gin' } },
+        projectRoot: testDir,
+      });
+
+      expect(result.errors).toHaveLength(1);
+      expect(result.errors[0].error).toMatch(/No go.mod found/);
+    });
+  });
+});
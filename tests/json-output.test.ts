@@ -0,0 +1,157 @@
+import { describe, it, expect } from 'vitest';
+import fs from 'fs-extra';
+import path from 'path';
+import { fileURLToPath } from 'url';
+import { buildInitOutput } from '../src/cli/commands/init.js';
+import { buildTemplatesOutput } from '../src/cli/commands/templates.js';
+import { buildValidationOutput } from '../src/cli/commands/validate.js';
+import { jsonError, jsonSuccess, parseOutputFormat } from '../src/utils/json-output.js';
+import type { InstallResult as AgentInstallResult } from '../src/lib/agent-installer.js';
+import type { SkillInstallResult } from '../src/lib/skills-installer.js';
+import type { StackTemplate } from '../src/lib/stack-template.js';
+import type { ValidationResult } from '../src/lib/template-pack-validator.js';
+
+const __filename = fileURLToPath(import.meta.url);
+const __dirname = path.dirname(__filename);
+
+/**
+ * Golden files lock the JSON schema; update them deliberately (and bump
+ * OUTPUT_SCHEMA_VERSION for breaking changes) when the output shape changes.
+ */
+async function readGolden(name: string): Promise<unknown> {
+  return fs.readJson(path.join(__dirname, 'fixtures', 'json-output', name));
+}
+
+describe('JSON Output Mode', () => {
+  describe('parseOutputFormat', () => {
+    it('should default to text and accept json', () => {
+      expect(parseOutputFormat(undefined)).toBe('text');
+      expect(parseOutputFormat('text')).toBe('text');
+      expect(parseOutputFormat('json')).toBe('json');
+    });
+
+    it('should reject unknown formats', () => {
+      expect(() => parseOutputFormat('yaml')).toThrow(/Unsupported output format/);
+    });
+  });
+
+  describe('templates --output json', () => {
+    it('should match the golden schema', async () => {
+      const template = {
+        id: 'golden-stack',
+        name: 'Golden Stack',
+        description: 'Fixture template used to lock the JSON schema',
+        complexity: 'intermediate',
+        architecture: 'clean',
+        tags: ['api', 'go'],
+        techStack: {
+          backend: { framework: 'gin', language: 'go' },
+          database: { primary: 'postgresql', orm: 'gorm' },
+        },
+        features: { authentication: true, payments: false },
+        files: [{}, {}],
+        dockerServices: [{}],
+      } as unknown as StackTemplate;
+
+      const envelope = jsonSuccess('templates', buildTemplatesOutput([template]));
+
+      expect(envelope).toEqual(await readGolden('templates.golden.json'));
+    });
+  });
+
+  describe('init --output json', () => {
+    it('should report written, skipped, and merged files in the golden schema', async () => {
+      const agents = {
+        installed: [{ name: 'backend-dev' }, { name: 'qa-tester' }],
+        skipped: [],
+        errors: [],
+      } as unknown as AgentInstallResult;
+      const skills = {
+        installed: [{ name: 'api-pagination' }, { name: 'api-sorting' }],
+        skipped: [],
+        errors: [{ skill: 'api-docs', error: 'Generation of gin-docs failed' }],
+        templatePacksUsed: [
+          { skill: 'api-pagination', templatePack: 'gin-pagination', score: 0.9 },
+        ],
+        files: [
+          {
+            skill: 'api-pagination',
+            path: 'internal/api/pagination/relay.go',
+            action: 'written',
+          },
+          {
+            skill: 'api-pagination',
+            path: 'internal/api/pagination/cursor_pagination.go',
+            action: 'written',
+          },
+          { skill: 'api-sorting', path: 'internal/api/sorting/fields.go', action: 'skipped' },
+          { skill: 'api-sorting', path: 'docs/openapi.yaml', action: 'merged' },
+        ],
+      } as unknown as SkillInstallResult;
+
+      const output = buildInitOutput({
+        template: null,
+        templateFiles: [],
+        configFiles: ['.claude/agentweaver.config.yml', '.claude/CLAUDE.md', '.gitignore'],
+        agents,
+        skills,
+      });
+
+      expect(jsonSuccess('init', output)).toEqual(await readGolden('init.golden.json'));
+    });
+  });
+
+  describe('validate --output json', () => {
+    const results: ValidationResult[] = [
+      {
+        valid: true,
+        packName: 'gin-pagination',
+        packPath: 'skills/api-pagination/templates/gin',
+        errors: [],
+        warnings: [
+          {
+            type: 'warning',
+            field: 'references',
+            message: 'Documentation references help users understand the template',
+          },
+        ],
+      },
+      {
+        valid: false,
+        packName: 'broken-pack',
+        packPath: 'skills/api-pagination/templates/broken',
+        errors: [
+          {
+            type: 'error',
+            field: 'files[missing.go]',
+            message: "Source file 'missing.go' does not exist",
+            severity: 'critical',
+          },
+        ],
+        warnings: [],
+      },
+    ];
+
+    it('should match the golden report schema', async () => {
+      const output = buildValidationOutput(
+        results.map((result) => ({ skill: 'api-pagination', result }))
+      );
+
+      expect(jsonSuccess('validate', output)).toEqual(await readGolden('validate.golden.json'));
+    });
+
+    it('should match the golden error envelope', async () => {
+      const output = buildValidationOutput(
+        results.map((result) => ({ skill: 'api-pagination', result }))
+      );
+      const envelope = jsonError(
+        'validate',
+        'VALIDATION_FAILED',
+        `${output.summary.invalid} template pack(s) failed validation`,
+        output.summary
+      );
+
+      expect(envelope).toEqual(await readGolden('validation-error.golden.json'));
+    });
+  });
+});
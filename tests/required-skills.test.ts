@@ -0,0 +1,239 @@
+import { describe, it, expect, beforeEach, afterEach } from 'vitest';
+import fs from 'fs-extra';
+import path from 'path';
+import os from 'os';
+import { fileURLToPath } from 'url';
+import { SkillsInstaller } from '../src/lib/skills-installer.js';
+import { TemplatePackValidator } from '../src/lib/template-pack-validator.js';
+
+const __filename = fileURLToPath(import.meta.url);
+const __dirname = path.dirname(__filename);
+
+const skillsDir = path.join(__dirname, '..', 'src', 'templates', 'skills');
+
+describe('Required skills', () => {
+  let testDir: string;
+
+  beforeEach(async () => {
+    testDir = path.join(os.tmpdir(), `agentweaver-required-${Date.now()}`);
+    await fs.ensureDir(testDir);
+  });
+
+  afterEach(async () => {
+    if (await fs.pathExists(testDir)) {
+      await fs.remove(testDir);
+    }
+  });
+
+  it('should validate the gin-sorting pack and its required skills', async () => {
+    const validator = new TemplatePackValidator();
+    const result = await validator.validateTemplatePack(
+      path.join(skillsDir, 'api-sorting', 'templates', 'gin')
+    );
+
+    expect(result.valid).toBe(true);
+    expect(result.packName).toBe('gin-sorting');
+    expect(result.warnings.filter((w) => w.field.startsWith('requiredSkills'))).toHaveLength(0);
+  });
+
+  it('should validate the gin-filtering pack', async () => {
+    const validator = new TemplatePackValidator();
+    const result = await validator.validateTemplatePack(
+      path.join(skillsDir, 'api-filtering', 'templates', 'gin')
+    );
+
+    expect(result.valid).toBe(true);
+    expect(result.packName).toBe('gin-filtering');
+  });
+
+  it('should validate the gin-search pack', async () => {
+    const validator = new TemplatePackValidator();
+    const result = await validator.validateTemplatePack(
+      path.join(skillsDir, 'api-search', 'templates', 'gin')
+    );
+
+    expect(result.valid).toBe(true);
+    expect(result.packName).toBe('gin-search');
+  });
+
+  it('should validate the gin-response-cache pack', async () => {
+    const validator = new TemplatePackValidator();
+    const result = await validator.validateTemplatePack(
+      path.join(skillsDir, 'api-response-cache', 'templates', 'gin')
+    );
+
+    expect(result.valid).toBe(true);
+    expect(result.packName).toBe('gin-response-cache');
+  });
+
+  it('should validate the gin-rate-limit pack', async () => {
+    const validator = new TemplatePackValidator();
+    const result = await validator.validateTemplatePack(
+      path.join(skillsDir, 'api-rate-limiting', 'templates', 'gin')
+    );
+
+    expect(result.valid).toBe(true);
+    expect(result.packName).toBe('gin-rate-limit');
+  });
+
+  it('should validate the gorm-pagination-indexes pack', async () => {
+    const validator = new TemplatePackValidator();
+    const result = await validator.validateTemplatePack(
+      path.join(skillsDir, 'db-indexes', 'templates', 'gin')
+    );
+
+    expect(result.valid).toBe(true);
+    expect(result.packName).toBe('gorm-pagination-indexes');
+  });
+
+  it('should install api-pagination when api-sorting is selected', async () => {
+    await fs.writeFile(path.join(testDir, 'go.mod'), 'module github.com/acme/shop\n\ngo 1.22\n');
+
+    const installer = new SkillsInstaller(skillsDir);
+    const result = await installer.installSkills({
+      targetDirectory: path.join(testDir, '.claude', 'skills'),
+      skillsToInstall: ['api-sorting'],
+      techStackContext: { techStack: { language: 'go', framework: 'gin' } },
+      projectRoot: testDir,
+    });
+
+    expect(result.errors).toHaveLength(0);
+    expect(result.installed.map((skill) => skill.dirName)).toEqual([
+      'api-sorting',
+      'api-pagination',
+    ]);
+
+    const sortingMiddleware = await fs.readFile(
+      path.join(testDir, 'internal', 'api', 'sorting', 'middleware.go'),
+      'utf-8'
+    );
+    expect(sortingMiddleware).toContain('"github.com/acme/shop/internal/api/pagination"');
+    expect(
+      await fs.pathExists(path.join(testDir, 'internal', 'api', 'pagination', 'middleware.go'))
+    ).toBe(true);
+  });
+
+  it('should install every required skill of api-bulk-export once', async () => {
+    await fs.writeFile(path.join(testDir, 'go.mod'), 'module github.com/acme/shop\n\ngo 1.22\n');
+
+    const installer = new SkillsInstaller(skillsDir);
+    const result = await installer.installSkills({
+      targetDirectory: path.join(testDir, '.claude', 'skills'),
+      skillsToInstall: ['api-bulk-export'],
+      techStackContext: { techStack: { language: 'go', framework: 'gin' } },
+      projectRoot: testDir,
+    });
+
+    expect(result.errors).toHaveLength(0);
+    expect(result.installed.map((skill) => skill.dirName)).toEqual([
+      'api-bulk-export',
+      'api-pagination',
+      'api-filtering',
+      'api-sorting',
+    ]);
+
+    const handler = await fs.readFile(
+      path.join(testDir, 'internal', 'api', 'export', 'handler.go'),
+      'utf-8'
+    );
+    expect(handler).toContain('"github.com/acme/shop/internal/api/filtering"');
+    expect(handler).toContain('"github.com/acme/shop/internal/api/sorting"');
+  });
+
+  it('should install api-pagination and api-sorting with api-docs', async () => {
+    await fs.writeFile(path.join(testDir, 'go.mod'), 'module github.com/acme/shop\n\ngo 1.22\n');
+
+    const installer = new SkillsInstaller(skillsDir);
+    const result = await installer.installSkills({
+      targetDirectory: path.join(testDir, '.claude', 'skills'),
+      skillsToInstall: ['api-docs'],
+      techStackContext: { techStack: { language: 'go', framework: 'gin' } },
+      projectRoot: testDir,
+    });
+
+    expect(result.errors).toHaveLength(0);
+    expect(result.installed.map((skill) => skill.dirName)).toEqual([
+      'api-docs',
+      'api-pagination',
+      'api-sorting',
+    ]);
+
+    const contrib = await fs.readFile(
+      path.join(testDir, 'internal', 'api', 'apidocs', 'contrib.go'),
+      'utf-8'
+    );
+    expect(contrib).toContain('"github.com/acme/shop/internal/api/pagination"');
+    expect(contrib).toContain('"github.com/acme/shop/internal/api/sorting"');
+  });
+
+  it('should install api-pagination with api-validation', async () => {
+    await fs.writeFile(path.join(testDir, 'go.mod'), 'module github.com/acme/shop\n\ngo 1.22\n');
+
+    const installer = new SkillsInstaller(skillsDir);
+    const result = await installer.installSkills({
+      targetDirectory: path.join(testDir, '.claude', 'skills'),
+      skillsToInstall: ['api-validation'],
+      techStackContext: { techStack: { language: 'go', framework: 'gin' } },
+      projectRoot: testDir,
+    });
+
+    expect(result.errors).toHaveLength(0);
+    expect(result.installed.map((skill) => skill.dirName)).toEqual([
+      'api-validation',
+      'api-pagination',
+    ]);
+
+    const strict = await fs.readFile(
+      path.join(testDir, 'internal', 'api', 'validation', 'strict.go'),
+      'utf-8'
+    );
+    expect(strict).toContain('"github.com/acme/shop/internal/api/pagination"');
+  });
+
+  it('should install api-pagination with api-error-handling', async () => {
+    await fs.writeFile(path.join(testDir, 'go.mod'), 'module github.com/acme/shop\n\ngo 1.22\n');
+
+    const installer = new SkillsInstaller(skillsDir);
+    const result = await installer.installSkills({
+      targetDirectory: path.join(testDir, '.claude', 'skills'),
+      skillsToInstall: ['api-error-handling'],
+      techStackContext: { techStack: { language: 'go', framework: 'gin' } },
+      projectRoot: testDir,
+    });
+
+    expect(result.errors).toHaveLength(0);
+    expect(result.installed.map((skill) => skill.dirName)).toEqual([
+      'api-error-handling',
+      'api-pagination',
+    ]);
+
+    const mappings = await fs.readFile(
+      path.join(testDir, 'internal', 'api', 'problem', 'pagination.go'),
+      'utf-8'
+    );
+    expect(mappings).toContain('"github.com/acme/shop/internal/api/pagination"');
+  });
+
+  it('should commit no pack when a required pack fails', async () => {
+    await fs.writeFile(path.join(testDir, 'go.mod'), 'module github.com/acme/shop\n\ngo 1.22\n');
+    // A file where api-validation's package directory belongs fails its commit after
+    // api-pagination's files (earlier in path order) were already moved into place
+    await fs.outputFile(path.join(testDir, 'internal', 'api', 'validation'), 'not a directory');
+
+    const installer = new SkillsInstaller(skillsDir);
+    const result = await installer.installSkills({
+      targetDirectory: path.join(testDir, '.claude', 'skills'),
+      skillsToInstall: ['api-validation'],
+      techStackContext: { techStack: { language: 'go', framework: 'gin' } },
+      projectRoot: testDir,
+    });
+
+    expect(result.installed).toEqual([]);
+    expect(result.errors.map((error) => error.skill)).toEqual(['API Validation', 'API Pagination']);
+    expect(result.errors[0].error).toMatch(/no project files were changed/);
+    expect(await fs.readdir(path.join(testDir, 'internal', 'api'))).toEqual(['validation']);
+    expect(await fs.pathExists(path.join(testDir, '.claude', 'skills', 'api-pagination'))).toBe(
+      false
+    );
+  });
+});
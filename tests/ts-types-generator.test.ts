@@ -0,0 +1,157 @@
+import { describe, it, expect } from 'vitest';
+import fs from 'fs-extra';
+import path from 'path';
+import { fileURLToPath } from 'url';
+import {
+  PAGINATION_MODELS_SOURCE,
+  TypesGenerationError,
+  generatePaginationTypes,
+  goTypeToTs,
+  parseGoStructs,
+  parseResponseVariants,
+} from '../src/lib/ts-types-generator.js';
+
+const __filename = fileURLToPath(import.meta.url);
+const __dirname = path.dirname(__filename);
+
+const modelsSource = path.join(__dirname, '..', 'src', 'templates', PAGINATION_MODELS_SOURCE);
+
+/**
+ * Golden files lock the emitted TypeScript; when the Go response types change, regenerate
+ * them deliberately (agentweaver generate-types) and review the diff like an API change.
+ */
+async function readGolden(name: string): Promise<string> {
+  return fs.readFile(path.join(__dirname, 'fixtures', 'ts-types', name), 'utf-8');
+}
+
+describe('TypeScript type generation', () => {
+  describe('generatePaginationTypes', () => {
+    it('should match the golden envelope definitions', async () => {
+      const source = await fs.readFile(modelsSource, 'utf-8');
+
+      expect(generatePaginationTypes(source)).toBe(await readGolden('pagination.golden.d.ts'));
+    });
+
+    it('should match the golden definitions with model aliases', async () => {
+      const source = await fs.readFile(modelsSource, 'utf-8');
+
+      expect(generatePaginationTypes(source, { models: ['Product', 'Order'] })).toBe(
+        await readGolden('pagination-models.golden.d.ts')
+      );
+    });
+
+    it('should import models from --models-from', async () => {
+      const source = await fs.readFile(modelsSource, 'utf-8');
+      const output = generatePaginationTypes(source, {
+        models: ['Product'],
+        modelsFrom: '@/api/models',
+      });
+
+      expect(output).toContain("import type { Product } from '@/api/models';");
+      expect(output).toContain('export type ProductCursorPage = CursorPaginatedResponse<Product>;');
+    });
+
+    it('should fail when an envelope struct is missing', () => {
+      expect(() => generatePaginationTypes('package pagination\n')).toThrow(TypesGenerationError);
+    });
+  });
+
+  describe('parseGoStructs', () => {
+    it('should read json tags, omitempty, and type parameters', () => {
+      const structs = parseGoStructs(
+        [
+          '// Page is a page',
+          'type Page[T any] struct {',
+          '\tItems []T `json:"items"`',
+          '',
+          '\t// Next is the next cursor',
+          '\tNext  *string `json:"next,omitempty"`',
+          '\tIndex int     `json:"-"`',
+          '\tTotal int64',
+          '\tsecret string',
+          '}',
+        ].join('\n')
+      );
+      const page = structs.get('Page')!;
+
+      expect(page.typeParams).toEqual(['T']);
+      expect(page.doc).toEqual(['Page is a page']);
+      expect(page.fields.map((field) => field.jsonName)).toEqual(['items', 'next', 'Total']);
+      expect(page.fields[1]).toMatchObject({
+        type: '*string',
+        omitEmpty: true,
+        doc: ['Next is the next cursor'],
+      });
+    });
+  });
+
+  describe('parseResponseVariants', () => {
+    it('should split metadata by paginator and mark always-set pointers', () => {
+      const variants = parseResponseVariants(
+        [
+          'func (p *OffsetPagination[T]) ToResponse(baseURL string) PaginatedResponse[T] {',
+          '\tresponse := PaginatedResponse[T]{',
+          '\t\tPagination: PaginationMeta{',
+          '\t\t\tCurrentPage: &p.CurrentPage,',
+          '\t\t\tPageSize:    p.PageSize,',
+          '\t\t},',
+          '\t}',
+          '\tresponse.Pagination.NextCursor = p.NextCursor',
+          '\treturn response',
+          '}',
+          '',
+        ].join('\n')
+      );
+
+      expect(variants).toEqual([
+        {
+          prefix: 'Offset',
+          fields: ['CurrentPage', 'PageSize', 'NextCursor'],
+          alwaysSet: ['CurrentPage'],
+        },
+      ]);
+    });
+
+    it('should not mark pointers that are cleared after the literal as always-set', () => {
+      const variants = parseResponseVariants(
+        [
+          'func (p *OffsetPagination[T]) ToResponse(baseURL string) PaginatedResponse[T] {',
+          '\tresponse := PaginatedResponse[T]{',
+          '\t\tPagination: PaginationMeta{',
+          '\t\t\tCurrentPage: &p.CurrentPage,',
+          '\t\t\tTotalPages:  &p.TotalPages,',
+          '\t\t\tTotalItems:  &p.TotalItems,',
+          '\t\t},',
+          '\t}',
+          '\tif p.CountMode == CountSkipped {',
+          '\t\tresponse.Pagination.TotalPages, response.Pagination.TotalItems = nil, nil',
+          '\t}',
+          '\treturn response',
+          '}',
+          '',
+        ].join('\n')
+      );
+
+      expect(variants[0].alwaysSet).toEqual(['CurrentPage']);
+      expect(variants[0].fields).toEqual(['CurrentPage', 'TotalPages', 'TotalItems']);
+    });
+  });
+
+  describe('goTypeToTs', () => {
+    const known = new Set(['PaginationLinks', 'PaginatedResponse']);
+    const typeParams = new Set(['T']);
+
+    it('should map Go types to TypeScript', () => {
+      expect(goTypeToTs('int64', typeParams, known)).toBe('number');
+      expect(goTypeToTs('*string', typeParams, known)).toBe('string');
+      expect(goTypeToTs('[]T', typeParams, known)).toBe('T[]');
+      expect(goTypeToTs('map[string][]int', typeParams, known)).toBe('Record<string, number[]>');
+      expect(goTypeToTs('PaginatedResponse[T]', typeParams, known)).toBe('PaginatedResponse<T>');
+      expect(goTypeToTs('time.Time', typeParams, known)).toBe('string');
+    });
+
+    it('should reject types it cannot map', () => {
+      expect(() => goTypeToTs('chan int', typeParams, known)).toThrow(/Unsupported Go type/);
+    });
+  });
+});